@@ -129,6 +129,7 @@ export interface SystemHealth {
   api_response_time: string;
   database_status: string;
   uptime: string;
+  uptime_duration: string;
   error_rate: number;
 }
 
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"treesindia/config"
+	"treesindia/database"
+	"treesindia/utils"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// encryptedColumn is a column whose values are encrypted with utils.EncryptField
+type encryptedColumn struct {
+	Table  string
+	Column string
+}
+
+// encryptedColumns lists every column stored with the encrypted GORM serializer
+var encryptedColumns = []encryptedColumn{
+	{Table: "workers", Column: "banking_info"},
+	{Table: "workers", Column: "documents"},
+	{Table: "brokers", Column: "documents"},
+}
+
+type fieldRow struct {
+	ID    uint
+	Value string
+}
+
+// fieldEncryptor encrypts, re-encrypts or decrypts the values of encrypted columns
+type fieldEncryptor struct {
+	db        *gorm.DB
+	decrypt   bool
+	dryRun    bool
+	batchSize int
+	rewritten int
+	failed    int
+}
+
+func main() {
+	decrypt := flag.Bool("decrypt", false, "Decrypt values back to plaintext, e.g. before rolling back the encryption migration")
+	dryRun := flag.Bool("dry-run", false, "Count the values that would be rewritten without changing anything")
+	batchSize := flag.Int("batch-size", 500, "Number of rows read per query")
+	flag.Parse()
+
+	// Load application configuration
+	appConfig := config.LoadConfig()
+
+	// Initialize database
+	dsn := appConfig.GetDatabaseURL()
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	// Set the database instance
+	database.SetDB(db)
+
+	if err := utils.InitFieldEncryption(appConfig.FieldEncryptionKeys); err != nil {
+		log.Fatal("Failed to initialize field encryption:", err)
+	}
+	if !utils.FieldEncryptionEnabled() {
+		log.Fatal("FIELD_ENCRYPTION_KEYS is not set")
+	}
+
+	if *decrypt {
+		logrus.Infof("🔓 Decrypting encrypted fields (dry run: %v)...", *dryRun)
+	} else {
+		logrus.Infof("🔐 Encrypting fields with key %s (dry run: %v)...", utils.PrimaryFieldKeyID(), *dryRun)
+	}
+
+	e := &fieldEncryptor{
+		db:        db,
+		decrypt:   *decrypt,
+		dryRun:    *dryRun,
+		batchSize: *batchSize,
+	}
+
+	for _, column := range encryptedColumns {
+		if err := e.processColumn(column); err != nil {
+			logrus.Errorf("Failed to process %s.%s: %v", column.Table, column.Column, err)
+			e.failed++
+		}
+	}
+
+	logrus.Infof("✅ Field encryption finished: %d values rewritten, %d failures", e.rewritten, e.failed)
+	if e.failed > 0 {
+		logrus.Warn("Re-run the command to retry failed values; values already rewritten are skipped")
+	}
+}
+
+// processColumn rewrites the values of a column in batches, including soft-deleted rows
+func (e *fieldEncryptor) processColumn(column encryptedColumn) error {
+	var lastID uint
+	rewritten := 0
+
+	for {
+		var rows []fieldRow
+		err := e.db.Raw(fmt.Sprintf(
+			`SELECT id, %[2]s AS value FROM %[1]s WHERE id > ? AND %[2]s IS NOT NULL AND %[2]s <> '' ORDER BY id LIMIT ?`,
+			column.Table, column.Column,
+		), lastID, e.batchSize).Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+
+			changed, err := e.rewriteRow(column, row)
+			if err != nil {
+				logrus.Errorf("Failed to rewrite %s.%s for id %d: %v", column.Table, column.Column, row.ID, err)
+				e.failed++
+				continue
+			}
+			if changed {
+				rewritten++
+			}
+		}
+	}
+
+	logrus.Infof("%s.%s: %d values rewritten", column.Table, column.Column, rewritten)
+	e.rewritten += rewritten
+	return nil
+}
+
+// maxRewriteAttempts is how often a row is re-read when a concurrent write changes it first
+const maxRewriteAttempts = 3
+
+// rewriteRow rewrites one value. The update only applies while the row still holds the value that
+// was read, so a value written by the app in the meantime is never overwritten; the row is re-read
+// and the new value rewritten instead. Returns whether the value needed rewriting.
+func (e *fieldEncryptor) rewriteRow(column encryptedColumn, row fieldRow) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		value, changed, err := e.rewrite(row.Value)
+		if err != nil || !changed || e.dryRun {
+			return changed, err
+		}
+
+		result := e.db.Exec(fmt.Sprintf(`UPDATE %[1]s SET %[2]s = ? WHERE id = ? AND %[2]s = ?`, column.Table, column.Column),
+			value, row.ID, row.Value)
+		if result.Error != nil {
+			return false, result.Error
+		}
+		if result.RowsAffected > 0 {
+			return true, nil
+		}
+		if attempt == maxRewriteAttempts {
+			return false, fmt.Errorf("value kept changing, gave up after %d attempts", attempt)
+		}
+
+		// The value changed since it was read; rewrite the current one
+		var current []fieldRow
+		err = e.db.Raw(fmt.Sprintf(`SELECT id, COALESCE(%[2]s, '') AS value FROM %[1]s WHERE id = ?`, column.Table, column.Column), row.ID).
+			Scan(&current).Error
+		if err != nil {
+			return false, err
+		}
+		if len(current) == 0 || current[0].Value == "" {
+			return false, nil
+		}
+		row = current[0]
+	}
+}
+
+// rewrite returns the new value of a field: plaintext when decrypting, otherwise encrypted with
+// the primary key. Values that are already in the wanted form are left unchanged.
+func (e *fieldEncryptor) rewrite(value string) (string, bool, error) {
+	if e.decrypt {
+		if !utils.IsEncryptedField(value) {
+			return value, false, nil
+		}
+		plaintext, err := utils.DecryptField(value)
+		return plaintext, err == nil, err
+	}
+
+	if !utils.FieldNeedsEncryption(value) {
+		return value, false, nil
+	}
+	plaintext, err := utils.DecryptField(value)
+	if err != nil {
+		return "", false, err
+	}
+	encrypted, err := utils.EncryptField(plaintext)
+	return encrypted, err == nil, err
+}
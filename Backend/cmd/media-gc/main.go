@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"treesindia/config"
+	"treesindia/database"
+	"treesindia/services"
+	"treesindia/utils"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", true, "Only list orphaned media; pass -dry-run=false to delete it")
+	flag.Parse()
+
+	// Load application configuration
+	appConfig := config.LoadConfig()
+
+	// Initialize database
+	dsn := appConfig.GetDatabaseURL()
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	// Set the database instance
+	database.SetDB(db)
+
+	// Initialize field encryption for worker and broker banking details and documents
+	if err := utils.InitFieldEncryption(appConfig.FieldEncryptionKeys); err != nil {
+		log.Fatal("Failed to initialize field encryption:", err)
+	}
+
+	mediaStore, err := services.NewMediaStore()
+	if err != nil {
+		log.Fatal("Failed to initialize media store:", err)
+	}
+
+	logrus.Infof("🧹 Collecting orphaned media in the %s media store (dry run: %v)...", mediaStore.Backend(), *dryRun)
+
+	report, err := services.NewMediaGCService(mediaStore).Run(*dryRun)
+	if err != nil {
+		log.Fatal("Media garbage collection failed:", err)
+	}
+
+	for _, orphan := range report.Orphans {
+		logrus.Infof("Orphaned: %s (%d bytes, last modified %s)", orphan.URL, orphan.Size, orphan.LastModified.Format("2006-01-02"))
+	}
+
+	logrus.Infof("Stored files: %d (%d bytes)", report.StoredFiles, report.StoredBytes)
+	logrus.Infof("Referenced keys: %d", report.ReferencedKeys)
+	logrus.Infof("Unreferenced within grace period: %d", report.RecentFiles)
+	logrus.Infof("Orphaned files: %d (%d bytes)", report.OrphanedFiles, report.OrphanedBytes)
+	logrus.Infof("Chat attachments past retention (sent before %s): %d", report.ChatMediaCutoff.Format("2006-01-02"), report.ExpiredChatAttachments)
+
+	if report.DryRun {
+		logrus.Info("✅ Dry run finished; re-run with -dry-run=false to delete orphaned media")
+		return
+	}
+
+	logrus.Infof("✅ Deleted %d files, %d failed", report.DeletedFiles, report.FailedDeletes)
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"treesindia/config"
+	"treesindia/database"
+	"treesindia/repositories"
+	"treesindia/services"
+	"treesindia/utils"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var cloudinaryURLPattern = regexp.MustCompile(`https?://res\.cloudinary\.com/[^\s"',{}\[\]\\]+`)
+
+type mediaRow struct {
+	ID    uint
+	Value string
+	Type  string
+}
+
+// migrator copies Cloudinary assets into the configured media store
+type migrator struct {
+	db         *gorm.DB
+	store      services.MediaStore
+	httpClient *http.Client
+	dryRun     bool
+	copied     map[string]string
+	failed     int
+}
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "List the assets that would be copied without changing anything")
+	flag.Parse()
+
+	// Load application configuration
+	appConfig := config.LoadConfig()
+
+	// Initialize database
+	dsn := appConfig.GetDatabaseURL()
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	// Set the database instance
+	database.SetDB(db)
+
+	// Initialize field encryption for worker and broker banking details and documents
+	if err := utils.InitFieldEncryption(appConfig.FieldEncryptionKeys); err != nil {
+		log.Fatal("Failed to initialize field encryption:", err)
+	}
+
+	store, err := services.NewMediaStore()
+	if err != nil {
+		log.Fatal("Failed to initialize media store:", err)
+	}
+	if store.Backend() == services.MediaBackendCloudinary {
+		log.Fatal("MEDIA_STORAGE is cloudinary; set it to local or s3 to migrate media away from Cloudinary")
+	}
+
+	logrus.Infof("🚚 Copying Cloudinary media to the %s media store (dry run: %v)...", store.Backend(), *dryRun)
+
+	m := &migrator{
+		db:         db,
+		store:      store,
+		httpClient: &http.Client{Timeout: 2 * time.Minute},
+		dryRun:     *dryRun,
+		copied:     make(map[string]string),
+	}
+
+	for _, column := range repositories.MediaColumns {
+		if err := m.migrateColumn(column); err != nil {
+			logrus.Errorf("Failed to migrate %s.%s: %v", column.Table, column.Column, err)
+			m.failed++
+		}
+	}
+
+	if !m.dryRun {
+		m.refreshChatMediaKeys()
+	}
+
+	logrus.Infof("✅ Media migration finished: %d assets copied, %d failures", len(m.copied), m.failed)
+	if m.failed > 0 {
+		logrus.Warn("Re-run the command to retry failed assets; copied values are not touched again")
+	}
+}
+
+// migrateColumn copies every Cloudinary asset referenced by a column and rewrites the column
+func (m *migrator) migrateColumn(column repositories.MediaColumn) error {
+	if column.Encrypted {
+		return m.migrateEncryptedColumn(column)
+	}
+
+	var rows []mediaRow
+	err := m.db.Raw(fmt.Sprintf(
+		`SELECT id, %[2]s::text AS value, pg_typeof(%[2]s)::text AS type FROM %[1]s WHERE %[2]s::text LIKE '%%res.cloudinary.com/%%'`,
+		column.Table, column.Column,
+	)).Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("%s.%s: %d rows reference Cloudinary", column.Table, column.Column, len(rows))
+
+	for _, row := range rows {
+		value, changed := m.rewrite(row.Value)
+		if !changed || m.dryRun {
+			continue
+		}
+
+		err := m.db.Exec(
+			fmt.Sprintf(`UPDATE %s SET %s = CAST(? AS %s) WHERE id = ?`, column.Table, column.Column, row.Type),
+			value, row.ID,
+		).Error
+		if err != nil {
+			logrus.Errorf("Failed to update %s.%s for id %d: %v", column.Table, column.Column, row.ID, err)
+			m.failed++
+		}
+	}
+
+	return nil
+}
+
+// migrateEncryptedColumn is migrateColumn for encrypted columns, which can only be searched for
+// Cloudinary URLs once decrypted. Rewritten values are encrypted again with the primary key.
+func (m *migrator) migrateEncryptedColumn(column repositories.MediaColumn) error {
+	var rows []mediaRow
+	err := m.db.Raw(fmt.Sprintf(
+		`SELECT id, %[2]s AS value FROM %[1]s WHERE %[2]s IS NOT NULL AND %[2]s <> ''`,
+		column.Table, column.Column,
+	)).Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	referencing := 0
+	for _, row := range rows {
+		plaintext, err := utils.DecryptField(row.Value)
+		if err != nil {
+			logrus.Errorf("Failed to decrypt %s.%s for id %d: %v", column.Table, column.Column, row.ID, err)
+			m.failed++
+			continue
+		}
+		if !strings.Contains(plaintext, "res.cloudinary.com/") {
+			continue
+		}
+		referencing++
+
+		value, changed := m.rewrite(plaintext)
+		if !changed || m.dryRun {
+			continue
+		}
+
+		encrypted, err := utils.EncryptField(value)
+		if err != nil {
+			logrus.Errorf("Failed to encrypt %s.%s for id %d: %v", column.Table, column.Column, row.ID, err)
+			m.failed++
+			continue
+		}
+		err = m.db.Exec(fmt.Sprintf(`UPDATE %s SET %s = ? WHERE id = ?`, column.Table, column.Column), encrypted, row.ID).Error
+		if err != nil {
+			logrus.Errorf("Failed to update %s.%s for id %d: %v", column.Table, column.Column, row.ID, err)
+			m.failed++
+		}
+	}
+
+	logrus.Infof("%s.%s: %d rows reference Cloudinary", column.Table, column.Column, referencing)
+	return nil
+}
+
+// rewrite replaces the Cloudinary URLs in a value with copies in the media store
+func (m *migrator) rewrite(value string) (string, bool) {
+	changed := false
+	rewritten := cloudinaryURLPattern.ReplaceAllStringFunc(value, func(url string) string {
+		newURL, err := m.copy(url)
+		if err != nil {
+			logrus.Errorf("Failed to copy %s: %v", url, err)
+			m.failed++
+			return url
+		}
+		if newURL != url {
+			changed = true
+		}
+		return newURL
+	})
+	return rewritten, changed
+}
+
+// copy downloads a Cloudinary asset and uploads it to the media store, once per URL
+func (m *migrator) copy(url string) (string, error) {
+	if newURL, ok := m.copied[url]; ok {
+		return newURL, nil
+	}
+
+	folder, filename := cloudinaryAssetPath(url)
+	mediaType := "image"
+	if strings.Contains(url, "/video/upload/") {
+		mediaType = "video"
+	} else if strings.Contains(url, "/raw/upload/") {
+		mediaType = "raw"
+	}
+
+	if m.dryRun {
+		logrus.Infof("Would copy %s %s into %s", mediaType, url, folder)
+		m.copied[url] = url
+		return url, nil
+	}
+
+	resp, err := m.httpClient.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+
+	newURL, err := m.store.UploadReader(resp.Body, folder, filename, mediaType)
+	if err != nil {
+		return "", err
+	}
+
+	logrus.Infof("Copied %s -> %s", url, newURL)
+	m.copied[url] = newURL
+	return newURL, nil
+}
+
+// refreshChatMediaKeys points the chat auto-delete keys at the copied media
+func (m *migrator) refreshChatMediaKeys() {
+	for _, column := range []string{"image_url", "video_url"} {
+		var rows []mediaRow
+		err := m.db.Raw(fmt.Sprintf(
+			`SELECT id, %[1]s AS value FROM simple_conversation_messages WHERE %[1]s IS NOT NULL AND cloudinary_public_id IS NOT NULL`,
+			column,
+		)).Scan(&rows).Error
+		if err != nil {
+			logrus.Errorf("Failed to load chat media keys: %v", err)
+			m.failed++
+			return
+		}
+
+		for _, row := range rows {
+			key := m.store.KeyFromURL(row.Value)
+			if key == "" {
+				continue
+			}
+			if err := m.db.Exec(`UPDATE simple_conversation_messages SET cloudinary_public_id = ? WHERE id = ?`, key, row.ID).Error; err != nil {
+				logrus.Errorf("Failed to update chat media key for message %d: %v", row.ID, err)
+				m.failed++
+			}
+		}
+	}
+}
+
+// cloudinaryAssetPath returns the folder and file name of a Cloudinary URL, e.g.
+// https://res.cloudinary.com/demo/image/upload/v1234567890/properties/kitchen.jpg -> properties, kitchen.jpg
+func cloudinaryAssetPath(url string) (string, string) {
+	parts := strings.Split(url, "/")
+	for i, part := range parts {
+		if part != "upload" {
+			continue
+		}
+		assetParts := parts[i+1:]
+		if len(assetParts) > 1 && strings.HasPrefix(assetParts[0], "v") {
+			assetParts = assetParts[1:]
+		}
+		assetPath := strings.Join(assetParts, "/")
+		return path.Dir(assetPath), path.Base(assetPath)
+	}
+	return "migrated", path.Base(url)
+}
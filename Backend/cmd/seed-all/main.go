@@ -6,6 +6,7 @@ import (
 	"treesindia/database"
 	"treesindia/seed"
 	"treesindia/services"
+	"treesindia/utils"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
@@ -29,19 +30,24 @@ func main() {
 	// Set the database instance
 	database.SetDB(db)
 
+	// Initialize field encryption for worker and broker banking details and documents
+	if err := utils.InitFieldEncryption(appConfig.FieldEncryptionKeys); err != nil {
+		log.Fatal("Failed to initialize field encryption:", err)
+	}
+
 	logrus.Info("🚀 Starting master seed process...")
 	logrus.Info("🌱 Seeding all data (idempotent - will skip if already exists)...")
 
-	// Initialize Cloudinary service for image uploads
-	cloudinaryService, err := services.NewCloudinaryService()
+	// Initialize media store for image uploads
+	mediaStore, err := services.NewMediaStore()
 	if err != nil {
-		logrus.Warnf("Cloudinary service not available, some items will be created without images: %v", err)
+		logrus.Warnf("media store not available, some items will be created without images: %v", err)
 	}
 
 	// Create seed manager
 	seedManager := seed.NewSeedManager()
-	if cloudinaryService != nil {
-		seedManager.SetCloudinaryUploader(cloudinaryService)
+	if mediaStore != nil {
+		seedManager.SetCloudinaryUploader(mediaStore)
 		logrus.Info("Cloudinary service initialized for seeding")
 	}
 
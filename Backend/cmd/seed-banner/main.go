@@ -32,13 +32,13 @@ func main() {
 	// Create seed manager
 	seedManager := seed.NewSeedManager()
 
-	// Initialize Cloudinary service for image uploads
-	cloudinaryService, err := services.NewCloudinaryService()
+	// Initialize media store for image uploads
+	mediaStore, err := services.NewMediaStore()
 	if err != nil {
-		logrus.Warnf("Cloudinary service not available, banner images will be created without images: %v", err)
+		logrus.Warnf("media store not available, banner images will be created without images: %v", err)
 	} else {
 		// Set Cloudinary uploader in seed manager
-		seedManager.SetCloudinaryUploader(cloudinaryService)
+		seedManager.SetCloudinaryUploader(mediaStore)
 		logrus.Info("Cloudinary service initialized for banner seeding")
 	}
 
@@ -42,6 +42,17 @@ type AppConfig struct {
 	CloudinaryAPIKey    string
 	CloudinaryAPISecret string
 
+	// Media Storage Configuration
+	MediaStorage       string // "cloudinary", "local" or "s3" (empty = cloudinary when CLOUDINARY_URL is set, else local)
+	MediaPublicBaseURL string // Public base URL of locally stored media, e.g. https://api.example.com/media
+	S3Endpoint         string
+	S3Region           string
+	S3Bucket           string
+	S3AccessKey        string
+	S3SecretKey        string
+	S3UseSSL           bool
+	S3PublicBaseURL    string // Optional CDN or bucket URL objects are served from
+
 	// File Upload Configuration
 	MaxFileSize      int64
 	AllowedFileTypes []string
@@ -90,6 +101,15 @@ type AppConfig struct {
 	OpenAIModel       string
 	OpenAIMaxTokens   int
 	OpenAITemperature float64
+
+	// Realtime Configuration
+	RealtimePubSubBackend string // "memory" (single node) or "postgres" (LISTEN/NOTIFY fan-out)
+
+	// Metrics Configuration
+	MetricsToken string // Bearer token required to scrape /metrics (empty = no token)
+
+	// Field Encryption Configuration
+	FieldEncryptionKeys string // Comma separated id:base64key pairs, primary key first (empty = stored unencrypted)
 }
 
 // LoadConfig loads configuration from environment variables
@@ -133,6 +153,17 @@ func LoadConfig() *AppConfig {
 		CloudinaryAPIKey:    getEnv("CLOUDINARY_API_KEY", ""),
 		CloudinaryAPISecret: getEnv("CLOUDINARY_API_SECRET", ""),
 
+		// Media Storage Configuration
+		MediaStorage:       getEnv("MEDIA_STORAGE", ""),
+		MediaPublicBaseURL: getEnv("MEDIA_PUBLIC_BASE_URL", "http://localhost:8080/media"),
+		S3Endpoint:         getEnv("S3_ENDPOINT", "localhost:9000"),
+		S3Region:           getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:           getEnv("S3_BUCKET", "treesindia"),
+		S3AccessKey:        getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:        getEnv("S3_SECRET_KEY", ""),
+		S3UseSSL:           getEnvAsBool("S3_USE_SSL", false),
+		S3PublicBaseURL:    getEnv("S3_PUBLIC_BASE_URL", ""),
+
 		// File Upload Configuration
 		MaxFileSize:      getEnvAsInt64("MAX_FILE_SIZE", 10*1024*1024), // 10MB
 		AllowedFileTypes: getEnvAsSlice("ALLOWED_FILE_TYPES", []string{"image/jpeg", "image/png", "image/gif", "image/webp"}),
@@ -181,6 +212,15 @@ func LoadConfig() *AppConfig {
 		OpenAIModel:       getEnv("OPENAI_MODEL", "gpt-3.5-turbo"),
 		OpenAIMaxTokens:   getEnvAsInt("OPENAI_MAX_TOKENS", 500),
 		OpenAITemperature: getEnvAsFloat64("OPENAI_TEMPERATURE", 0.7),
+
+		// Realtime Configuration
+		RealtimePubSubBackend: getEnv("REALTIME_PUBSUB_BACKEND", "memory"),
+
+		// Metrics Configuration
+		MetricsToken: getEnv("METRICS_TOKEN", ""),
+
+		// Field Encryption Configuration
+		FieldEncryptionKeys: getEnv("FIELD_ENCRYPTION_KEYS", ""),
 	}
 
 	return config
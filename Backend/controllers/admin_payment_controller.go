@@ -354,6 +354,7 @@ func (apc *AdminPaymentController) GetTransactionFilters(c *gin.Context) {
 			string(models.PaymentTypeSegmentPay),
 			string(models.PaymentTypeQuote),
 			string(models.PaymentTypeRefund),
+			string(models.PaymentTypeReferralReward),
 			string(models.PaymentTypeManual),
 		},
 		"payment_statuses": []string{
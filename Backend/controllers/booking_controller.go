@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"treesindia/models"
 	"treesindia/repositories"
 	"treesindia/services"
@@ -294,13 +296,31 @@ func (bc *BookingController) GetAvailableSlots(c *gin.Context) {
 
 	availabilityService := services.NewAvailabilityService()
 
-	// Use custom duration if provided, otherwise use service default
+	// Use custom duration if provided, otherwise the duration of the chosen options
 	var customDuration *string
 	if duration != "" {
 		customDuration = &duration
 	}
 
-	availableSlots, err := availabilityService.GetAvailableSlotsWithDuration(uint(serviceID), date, "", customDuration)
+	selection, err := parseServiceSelectionQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Slots are priced for the chosen options and location; quotes have their own price
+	where := models.PricingLocation{
+		City:    c.Query("city"),
+		State:   c.Query("state"),
+		Pincode: c.Query("pincode"),
+	}
+
+	var availableSlots *services.AvailabilityResponse
+	if customDuration == nil {
+		availableSlots, err = availabilityService.GetAvailableSlotsForSelection(uint(serviceID), date, where, selection)
+	} else {
+		availableSlots, err = availabilityService.GetAvailableSlotsWithDuration(uint(serviceID), date, "", customDuration)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get available slots", "details": err.Error()})
 		return
@@ -313,6 +333,50 @@ func (bc *BookingController) GetAvailableSlots(c *gin.Context) {
 	})
 }
 
+// parseServiceSelectionQuery reads the variant_id, quantity and add_ons query parameters, where add_ons
+// is a comma separated list of add-on IDs with optional quantities, e.g. "3,5:2". Returns nil when none are set.
+func parseServiceSelectionQuery(c *gin.Context) (*models.ServiceSelection, error) {
+	variantIDStr, quantityStr, addOnsStr := c.Query("variant_id"), c.Query("quantity"), c.Query("add_ons")
+	if variantIDStr == "" && quantityStr == "" && addOnsStr == "" {
+		return nil, nil
+	}
+
+	selection := &models.ServiceSelection{}
+	if variantIDStr != "" {
+		variantID, err := strconv.ParseUint(variantIDStr, 10, 32)
+		if err != nil {
+			return nil, errors.New("invalid variant_id")
+		}
+		id := uint(variantID)
+		selection.VariantID = &id
+	}
+	if quantityStr != "" {
+		quantity, err := strconv.Atoi(quantityStr)
+		if err != nil || quantity < 1 {
+			return nil, errors.New("invalid quantity")
+		}
+		selection.Quantity = quantity
+	}
+	for _, item := range strings.Split(addOnsStr, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		idStr, quantityStr, _ := strings.Cut(item, ":")
+		addOnID, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			return nil, errors.New("invalid add_ons")
+		}
+		addOn := models.ServiceAddOnSelection{AddOnID: uint(addOnID)}
+		if quantityStr != "" {
+			if addOn.Quantity, err = strconv.Atoi(quantityStr); err != nil || addOn.Quantity < 1 {
+				return nil, errors.New("invalid add_ons")
+			}
+		}
+		selection.AddOns = append(selection.AddOns, addOn)
+	}
+	return selection, nil
+}
+
 // VerifyPayment verifies payment for a specific booking
 func (bc *BookingController) VerifyPayment(c *gin.Context) {
 	userID := bc.GetUserID(c)
@@ -501,6 +565,31 @@ func (bc *BookingController) CancelUserBooking(c *gin.Context) {
 	})
 }
 
+// GetServicePINs gets the start and completion PINs of a user's booking
+func (bc *BookingController) GetServicePINs(c *gin.Context) {
+	userID := bc.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	pins, err := bc.bookingService.GetServicePINs(userID, uint(bookingID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to get service PINs", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"service_pins": pins,
+	})
+}
+
 // AdminGetAllBookings gets all bookings (admin only)
 func (bc *BookingController) AdminGetAllBookings(c *gin.Context) {
 	userType := bc.GetUserType(c)
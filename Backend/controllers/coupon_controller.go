@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"strconv"
+	"treesindia/models"
+	"treesindia/services"
+	"treesindia/views"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type CouponController struct {
+	BaseController
+	couponService *services.CouponService
+}
+
+func NewCouponController() *CouponController {
+	return &CouponController{
+		BaseController: *NewBaseController(),
+		couponService:  services.NewCouponService(),
+	}
+}
+
+// ValidateCoupon previews the discount a coupon gives on a purchase
+// @Summary Validate coupon
+// @Description Customer checks a promo code and sees the discount before paying
+// @Tags coupons
+// @Accept json
+// @Produce json
+// @Param request body models.ValidateCouponRequest true "Coupon and purchase details"
+// @Success 200 {object} views.Response{data=services.CouponDiscount}
+// @Router /api/v1/coupons/validate [post]
+func (cc *CouponController) ValidateCoupon(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("CouponController.ValidateCoupon panic: %v", r)
+		}
+	}()
+
+	userID := cc.GetUserID(c)
+	if userID == 0 {
+		c.JSON(401, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	var req models.ValidateCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	discount, err := cc.couponService.PreviewCoupon(userID, &req)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Coupon cannot be applied", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Coupon applied successfully", discount))
+}
+
+// GetCoupons gets coupons (admin only)
+// @Summary Get coupons
+// @Description Admin lists coupons with filters
+// @Tags coupons
+// @Produce json
+// @Param search query string false "Search by code or description"
+// @Param scope query string false "Scope"
+// @Param is_active query bool false "Active flag"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} views.Response
+// @Router /api/v1/admin/coupons [get]
+func (cc *CouponController) GetCoupons(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("CouponController.GetCoupons panic: %v", r)
+		}
+	}()
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	filters := &models.CouponFilters{
+		Search: c.Query("search"),
+		Scope:  models.CouponScope(c.Query("scope")),
+		Page:   page,
+		Limit:  limit,
+	}
+	if isActive := c.Query("is_active"); isActive != "" {
+		isActiveBool := isActive == "true"
+		filters.IsActive = &isActiveBool
+	}
+
+	coupons, pagination, err := cc.couponService.GetCoupons(filters)
+	if err != nil {
+		logrus.Errorf("CouponController.GetCoupons service error: %v", err)
+		c.JSON(500, views.CreateErrorResponse("Failed to get coupons", err.Error()))
+		return
+	}
+
+	response := map[string]interface{}{
+		"coupons":    coupons,
+		"pagination": pagination,
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Coupons retrieved successfully", response))
+}
+
+// GetCoupon gets a coupon with its usage (admin only)
+// @Summary Get coupon
+// @Description Admin gets a coupon and how many times it has been used
+// @Tags coupons
+// @Produce json
+// @Param id path integer true "Coupon ID"
+// @Success 200 {object} views.Response
+// @Router /api/v1/admin/coupons/{id} [get]
+func (cc *CouponController) GetCoupon(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("CouponController.GetCoupon panic: %v", r)
+		}
+	}()
+
+	couponID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid coupon ID", err.Error()))
+		return
+	}
+
+	coupon, err := cc.couponService.GetCouponByID(uint(couponID))
+	if err != nil {
+		c.JSON(404, views.CreateErrorResponse("Coupon not found", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Coupon retrieved successfully", coupon))
+}
+
+// CreateCoupon creates a coupon (admin only)
+// @Summary Create coupon
+// @Description Admin creates a percentage or flat promo code
+// @Tags coupons
+// @Accept json
+// @Produce json
+// @Param request body models.CreateCouponRequest true "Coupon details"
+// @Success 201 {object} views.Response{data=models.Coupon}
+// @Router /api/v1/admin/coupons [post]
+func (cc *CouponController) CreateCoupon(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("CouponController.CreateCoupon panic: %v", r)
+		}
+	}()
+
+	adminID := cc.GetUserID(c)
+	if adminID == 0 {
+		c.JSON(401, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	var req models.CreateCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	coupon, err := cc.couponService.CreateCoupon(adminID, &req)
+	if err != nil {
+		logrus.Errorf("CouponController.CreateCoupon service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to create coupon", err.Error()))
+		return
+	}
+
+	c.JSON(201, views.CreateSuccessResponse("Coupon created successfully", coupon))
+}
+
+// UpdateCoupon updates a coupon (admin only)
+// @Summary Update coupon
+// @Description Admin updates a coupon's discount, limits, targeting or validity
+// @Tags coupons
+// @Accept json
+// @Produce json
+// @Param id path integer true "Coupon ID"
+// @Param request body models.UpdateCouponRequest true "Coupon changes"
+// @Success 200 {object} views.Response{data=models.Coupon}
+// @Router /api/v1/admin/coupons/{id} [put]
+func (cc *CouponController) UpdateCoupon(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("CouponController.UpdateCoupon panic: %v", r)
+		}
+	}()
+
+	couponID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid coupon ID", err.Error()))
+		return
+	}
+
+	var req models.UpdateCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	coupon, err := cc.couponService.UpdateCoupon(uint(couponID), &req)
+	if err != nil {
+		logrus.Errorf("CouponController.UpdateCoupon service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to update coupon", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Coupon updated successfully", coupon))
+}
+
+// GetCouponRedemptions gets the redemption records of a coupon (admin only)
+// @Summary Get coupon redemptions
+// @Description Admin lists who used a coupon and the discount given
+// @Tags coupons
+// @Produce json
+// @Param id path integer true "Coupon ID"
+// @Param status query string false "Redemption status"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} views.Response
+// @Router /api/v1/admin/coupons/{id}/redemptions [get]
+func (cc *CouponController) GetCouponRedemptions(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("CouponController.GetCouponRedemptions panic: %v", r)
+		}
+	}()
+
+	couponID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid coupon ID", err.Error()))
+		return
+	}
+	id := uint(couponID)
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	status := models.CouponRedemptionStatus(c.Query("status"))
+
+	redemptions, pagination, err := cc.couponService.GetRedemptions(&id, status, page, limit)
+	if err != nil {
+		logrus.Errorf("CouponController.GetCouponRedemptions service error: %v", err)
+		c.JSON(500, views.CreateErrorResponse("Failed to get coupon redemptions", err.Error()))
+		return
+	}
+
+	response := map[string]interface{}{
+		"redemptions": redemptions,
+		"pagination":  pagination,
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Coupon redemptions retrieved successfully", response))
+}
+
+// GetPromotionsReport summarises coupon and referral usage (admin only)
+// @Summary Get promotions report
+// @Description Admin report of coupon redemptions, discounts given and referral rewards
+// @Tags coupons
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} views.Response
+// @Router /api/v1/admin/coupons/report [get]
+func (cc *CouponController) GetPromotionsReport(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("CouponController.GetPromotionsReport panic: %v", r)
+		}
+	}()
+
+	report, err := cc.couponService.GetPromotionsReport(c.Query("start_date"), c.Query("end_date"))
+	if err != nil {
+		logrus.Errorf("CouponController.GetPromotionsReport service error: %v", err)
+		c.JSON(500, views.CreateErrorResponse("Failed to get promotions report", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Promotions report retrieved successfully", report))
+}
@@ -223,9 +223,38 @@ func (dc *DashboardController) GetMonthlyTrends(c *gin.Context) {
 	c.JSON(http.StatusOK, views.CreateSuccessResponse("Monthly trends retrieved successfully", trends))
 }
 
+// GetCommunicationAnalytics gets communication analytics data (admin only)
+// @Summary Get communication analytics
+// @Description Get chat activity, response times and push notification delivery analytics
+// @Tags Admin Dashboard
+// @Accept json
+// @Produce json
+// @Success 200 {object} views.Response{data=models.CommunicationAnalytics}
+// @Failure 401 {object} views.Response
+// @Failure 403 {object} views.Response
+// @Failure 500 {object} views.Response
+// @Router /admin/dashboard/communication-analytics [get]
+func (dc *DashboardController) GetCommunicationAnalytics(c *gin.Context) {
+	// Check if user is admin
+	userType := c.GetString("user_type")
+	if userType != "admin" {
+		c.JSON(http.StatusForbidden, views.CreateErrorResponse("Admin access required", ""))
+		return
+	}
+
+	// Get communication analytics
+	analytics, err := dc.dashboardService.GetCommunicationAnalytics()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to fetch communication analytics", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Communication analytics retrieved successfully", analytics))
+}
+
 // GetDashboardAlerts gets dashboard alerts and notifications (admin only)
 // @Summary Get dashboard alerts
-// @Description Get system alerts, urgent bookings, and notifications for dashboard
+// @Description Get urgent alerts, system alerts, pending admin actions and recent activity for dashboard
 // @Tags Admin Dashboard
 // @Accept json
 // @Produce json
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"treesindia/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultSearchRadiusKm is used when a radius search gives lat and lng without radius_km
+const defaultSearchRadiusKm = 10
+
+// parseGeoFilters adds a radius search (lat, lng, radius_km) and a bounding-box search
+// (min_lat, min_lng, max_lat, max_lng) from the query string to the listing filters
+func parseGeoFilters(c *gin.Context, filters map[string]interface{}) error {
+	if c.Query("lat") != "" || c.Query("lng") != "" {
+		values, err := parseFloatQueries(c, "lat", "lng")
+		if err != nil {
+			return err
+		}
+		radiusKm := float64(defaultSearchRadiusKm)
+		if radius := c.Query("radius_km"); radius != "" {
+			if radiusKm, err = strconv.ParseFloat(radius, 64); err != nil {
+				return fmt.Errorf("radius_km must be a number")
+			}
+		}
+		near := models.GeoRadius{Latitude: values[0], Longitude: values[1], RadiusMeters: radiusKm * 1000}
+		if err := near.Validate(); err != nil {
+			return err
+		}
+		filters["near"] = near
+	}
+
+	if c.Query("min_lat") != "" || c.Query("min_lng") != "" || c.Query("max_lat") != "" || c.Query("max_lng") != "" {
+		values, err := parseFloatQueries(c, "min_lat", "min_lng", "max_lat", "max_lng")
+		if err != nil {
+			return err
+		}
+		bounds := models.GeoBounds{MinLatitude: values[0], MinLongitude: values[1], MaxLatitude: values[2], MaxLongitude: values[3]}
+		if err := bounds.Validate(); err != nil {
+			return err
+		}
+		filters["bounds"] = bounds
+	}
+
+	return nil
+}
+
+// parseFloatQueries parses query parameters that must all be given as numbers
+func parseFloatQueries(c *gin.Context, keys ...string) ([]float64, error) {
+	values := make([]float64, len(keys))
+	for i, key := range keys {
+		value, err := strconv.ParseFloat(c.Query(key), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s is required and must be a number", key)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
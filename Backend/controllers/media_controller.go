@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"treesindia/services"
+	"treesindia/views"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MediaController serves media stored on the local filesystem
+type MediaController struct {
+	localStore *services.LocalMediaStore
+}
+
+// NewMediaController creates a new media controller
+func NewMediaController(localStore *services.LocalMediaStore) *MediaController {
+	return &MediaController{
+		localStore: localStore,
+	}
+}
+
+// ServeMedia serves a locally stored media file
+// @Summary Get media file
+// @Description Serve a media file stored by the local media store. Signed URLs are checked when a signature is present; private media such as data exports requires one.
+// @Tags Media
+// @Produce octet-stream
+// @Param key path string true "Media key"
+// @Param expires query string false "Signature expiry (unix seconds)"
+// @Param signature query string false "URL signature"
+// @Success 200 {file} file
+// @Failure 403 {object} views.Response
+// @Failure 404 {object} views.Response
+// @Router /media/{key} [get]
+func (mc *MediaController) ServeMedia(c *gin.Context) {
+	// Clean the key first, so "//data-exports/..." or "./" segments can't dodge the private prefix check
+	key := strings.TrimPrefix(path.Clean("/"+c.Param("key")), "/")
+
+	// Private media such as data exports is only served with a valid signature
+	if signature := c.Query("signature"); signature != "" || services.IsPrivateMediaKey(key) {
+		if !mc.localStore.VerifySignature(key, c.Query("expires"), signature) {
+			c.JSON(http.StatusForbidden, views.CreateErrorResponse("Invalid signature", "The media link is invalid or has expired"))
+			return
+		}
+	}
+
+	filePath, err := mc.localStore.FilePath(key)
+	if err != nil {
+		c.JSON(http.StatusNotFound, views.CreateErrorResponse("Media not found", err.Error()))
+		return
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil || info.IsDir() {
+		c.JSON(http.StatusNotFound, views.CreateErrorResponse("Media not found", "The requested media does not exist"))
+		return
+	}
+
+	c.File(filePath)
+}
@@ -11,12 +11,14 @@ import (
 )
 
 type PaymentSegmentController struct {
-	quoteService *services.QuoteService
+	quoteService     *services.QuoteService
+	milestoneService *services.PaymentSegmentMilestoneService
 }
 
 func NewPaymentSegmentController() *PaymentSegmentController {
 	return &PaymentSegmentController{
-		quoteService: services.NewQuoteService(),
+		quoteService:     services.NewQuoteService(),
+		milestoneService: services.NewPaymentSegmentMilestoneService(),
 	}
 }
 
@@ -263,3 +265,51 @@ func (psc *PaymentSegmentController) GetPaidSegments(c *gin.Context) {
 		"data":    segments,
 	})
 }
+
+// UpdateSegmentSchedule updates the milestone trigger and work gate of a segment
+// @Summary Update payment segment schedule (Admin)
+// @Description Change when a payment segment falls due and which work stage it blocks until paid
+// @Tags Payment Segments
+// @Accept json
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Param segment_id path int true "Payment segment ID"
+// @Param request body models.UpdatePaymentSegmentScheduleRequest true "Schedule details"
+// @Success 200 {object} models.PaymentSegment
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /admin/bookings/{id}/payment-segments/{segment_id}/schedule [put]
+func (psc *PaymentSegmentController) UpdateSegmentSchedule(c *gin.Context) {
+	// Get booking ID from URL
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	// Get segment ID from URL
+	segmentID, err := strconv.ParseUint(c.Param("segment_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid segment ID"})
+		return
+	}
+
+	// Parse request body
+	var req models.UpdatePaymentSegmentScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	segment, err := psc.milestoneService.UpdateSchedule(uint(bookingID), uint(segmentID), &req)
+	if err != nil {
+		logrus.Errorf("Failed to update schedule of segment %d for booking %d: %v", segmentID, bookingID, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    segment,
+	})
+}
@@ -0,0 +1,280 @@
+package controllers
+
+import (
+	"strconv"
+	"time"
+	"treesindia/models"
+	"treesindia/services"
+	"treesindia/views"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type PricingRuleController struct {
+	BaseController
+	pricingRuleService *services.PricingRuleService
+}
+
+func NewPricingRuleController() *PricingRuleController {
+	return &PricingRuleController{
+		BaseController:     *NewBaseController(),
+		pricingRuleService: services.NewPricingRuleService(),
+	}
+}
+
+// GetPricingRules gets surge pricing rules (admin only)
+// @Summary Get pricing rules
+// @Description Admin lists surge pricing rules, highest priority first
+// @Tags pricing
+// @Produce json
+// @Param is_active query bool false "Active flag"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} views.Response
+// @Router /api/v1/admin/pricing-rules [get]
+func (prc *PricingRuleController) GetPricingRules(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("PricingRuleController.GetPricingRules panic: %v", r)
+		}
+	}()
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	filters := &models.PricingRuleFilters{
+		Page:  page,
+		Limit: limit,
+	}
+	if isActive := c.Query("is_active"); isActive != "" {
+		isActiveBool := isActive == "true"
+		filters.IsActive = &isActiveBool
+	}
+
+	rules, pagination, err := prc.pricingRuleService.GetRules(filters)
+	if err != nil {
+		logrus.Errorf("PricingRuleController.GetPricingRules service error: %v", err)
+		c.JSON(500, views.CreateErrorResponse("Failed to get pricing rules", err.Error()))
+		return
+	}
+
+	response := map[string]interface{}{
+		"pricing_rules": rules,
+		"pagination":    pagination,
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Pricing rules retrieved successfully", response))
+}
+
+// GetPricingRule gets a surge pricing rule (admin only)
+// @Summary Get pricing rule
+// @Description Admin gets a surge pricing rule
+// @Tags pricing
+// @Produce json
+// @Param id path integer true "Pricing rule ID"
+// @Success 200 {object} views.Response{data=models.PricingRule}
+// @Router /api/v1/admin/pricing-rules/{id} [get]
+func (prc *PricingRuleController) GetPricingRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid pricing rule ID", err.Error()))
+		return
+	}
+
+	rule, err := prc.pricingRuleService.GetRule(uint(id))
+	if err != nil {
+		c.JSON(404, views.CreateErrorResponse("Pricing rule not found", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Pricing rule retrieved successfully", rule))
+}
+
+// CreatePricingRule creates a surge pricing rule (admin only)
+// @Summary Create pricing rule
+// @Description Admin adds a rule that raises or lowers prices by day, time, holiday, location, service or demand
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param request body models.CreatePricingRuleRequest true "Pricing rule details"
+// @Success 201 {object} views.Response{data=models.PricingRule}
+// @Router /api/v1/admin/pricing-rules [post]
+func (prc *PricingRuleController) CreatePricingRule(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("PricingRuleController.CreatePricingRule panic: %v", r)
+		}
+	}()
+
+	adminID := prc.GetUserID(c)
+	if adminID == 0 {
+		c.JSON(401, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	var req models.CreatePricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	rule, err := prc.pricingRuleService.CreateRule(adminID, &req)
+	if err != nil {
+		logrus.Errorf("PricingRuleController.CreatePricingRule service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to create pricing rule", err.Error()))
+		return
+	}
+
+	c.JSON(201, views.CreateSuccessResponse("Pricing rule created successfully", rule))
+}
+
+// UpdatePricingRule updates a surge pricing rule (admin only)
+// @Summary Update pricing rule
+// @Description Admin replaces the conditions and adjustment of a surge pricing rule
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path integer true "Pricing rule ID"
+// @Param request body models.UpdatePricingRuleRequest true "Pricing rule details"
+// @Success 200 {object} views.Response{data=models.PricingRule}
+// @Router /api/v1/admin/pricing-rules/{id} [put]
+func (prc *PricingRuleController) UpdatePricingRule(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("PricingRuleController.UpdatePricingRule panic: %v", r)
+		}
+	}()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid pricing rule ID", err.Error()))
+		return
+	}
+
+	var req models.UpdatePricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	rule, err := prc.pricingRuleService.UpdateRule(uint(id), &req)
+	if err != nil {
+		logrus.Errorf("PricingRuleController.UpdatePricingRule service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to update pricing rule", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Pricing rule updated successfully", rule))
+}
+
+// DeletePricingRule deletes a surge pricing rule (admin only)
+// @Summary Delete pricing rule
+// @Description Admin deletes a surge pricing rule; existing bookings keep their price
+// @Tags pricing
+// @Produce json
+// @Param id path integer true "Pricing rule ID"
+// @Success 200 {object} views.Response
+// @Router /api/v1/admin/pricing-rules/{id} [delete]
+func (prc *PricingRuleController) DeletePricingRule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid pricing rule ID", err.Error()))
+		return
+	}
+
+	if err := prc.pricingRuleService.DeleteRule(uint(id)); err != nil {
+		c.JSON(404, views.CreateErrorResponse("Failed to delete pricing rule", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Pricing rule deleted successfully", nil))
+}
+
+// GetHolidays gets the holiday calendar (admin only)
+// @Summary Get holidays
+// @Description Admin lists holidays between two dates, the current year by default
+// @Tags pricing
+// @Produce json
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Success 200 {object} views.Response{data=[]models.Holiday}
+// @Router /api/v1/admin/holidays [get]
+func (prc *PricingRuleController) GetHolidays(c *gin.Context) {
+	now := time.Now()
+	from := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(now.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			c.JSON(400, views.CreateErrorResponse("Invalid from date", "Use YYYY-MM-DD"))
+			return
+		}
+		from = parsed
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			c.JSON(400, views.CreateErrorResponse("Invalid to date", "Use YYYY-MM-DD"))
+			return
+		}
+		to = parsed
+	}
+
+	holidays, err := prc.pricingRuleService.GetHolidays(from, to)
+	if err != nil {
+		c.JSON(500, views.CreateErrorResponse("Failed to get holidays", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Holidays retrieved successfully", holidays))
+}
+
+// CreateHoliday adds a holiday to the calendar (admin only)
+// @Summary Create holiday
+// @Description Admin adds a holiday that holiday pricing rules apply on, everywhere or in some cities
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param request body models.CreateHolidayRequest true "Holiday details"
+// @Success 201 {object} views.Response{data=models.Holiday}
+// @Router /api/v1/admin/holidays [post]
+func (prc *PricingRuleController) CreateHoliday(c *gin.Context) {
+	var req models.CreateHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	holiday, err := prc.pricingRuleService.CreateHoliday(&req)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Failed to create holiday", err.Error()))
+		return
+	}
+
+	c.JSON(201, views.CreateSuccessResponse("Holiday created successfully", holiday))
+}
+
+// DeleteHoliday removes a holiday from the calendar (admin only)
+// @Summary Delete holiday
+// @Description Admin removes a holiday from the calendar
+// @Tags pricing
+// @Produce json
+// @Param id path integer true "Holiday ID"
+// @Success 200 {object} views.Response
+// @Router /api/v1/admin/holidays/{id} [delete]
+func (prc *PricingRuleController) DeleteHoliday(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid holiday ID", err.Error()))
+		return
+	}
+
+	if err := prc.pricingRuleService.DeleteHoliday(uint(id)); err != nil {
+		c.JSON(404, views.CreateErrorResponse("Failed to delete holiday", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Holiday deleted successfully", nil))
+}
@@ -24,17 +24,17 @@ type ProjectController struct {
 
 // NewProjectController creates a new project controller
 func NewProjectController() *ProjectController {
-	// Initialize Cloudinary service
-	cloudinaryService, err := services.NewCloudinaryService()
+	// Initialize media store
+	mediaStore, err := services.NewMediaStore()
 	if err != nil {
-		logrus.Errorf("Failed to initialize CloudinaryService: %v", err)
-		// Don't panic, create a nil cloudinary service
-		cloudinaryService = nil
+		logrus.Errorf("Failed to initialize media store: %v", err)
+		// Don't panic, leave the media store nil
+		mediaStore = nil
 	} else {
-		logrus.Info("CloudinaryService initialized successfully")
+		logrus.Info("Media store initialized successfully")
 	}
 
-	projectService := services.NewProjectService(cloudinaryService)
+	projectService := services.NewProjectService(mediaStore)
 
 	return &ProjectController{
 		BaseController:  NewBaseController(),
@@ -194,9 +194,17 @@ func (pc *ProjectController) GetProjectBySlug(c *gin.Context) {
 // @Param status query string false "Filter by status (starting_soon, on_going, completed, cancelled, on_hold)"
 // @Param state query string false "Filter by state"
 // @Param city query string false "Filter by city"
+// @Param lat query number false "Latitude of the radius search centre"
+// @Param lng query number false "Longitude of the radius search centre"
+// @Param radius_km query number false "Radius search distance in km (default: 10, max: 100)"
+// @Param min_lat query number false "Bounding box minimum latitude"
+// @Param min_lng query number false "Bounding box minimum longitude"
+// @Param max_lat query number false "Bounding box maximum latitude"
+// @Param max_lng query number false "Bounding box maximum longitude"
 // @Param limit query int false "Limit number of results (default: 20)"
 // @Param offset query int false "Offset for pagination (default: 0)"
 // @Success 200 {object} models.Response{data=[]models.Project} "Projects retrieved successfully"
+// @Failure 400 {object} models.Response "Invalid location filter"
 // @Failure 401 {object} models.Response "Unauthorized"
 // @Failure 403 {object} models.Response "Subscription required"
 // @Failure 500 {object} models.Response "Internal server error"
@@ -250,6 +258,10 @@ func (pc *ProjectController) GetProjects(c *gin.Context) {
 	if city := c.Query("city"); city != "" {
 		filters["city"] = city
 	}
+	if err := parseGeoFilters(c, filters); err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid location filter", err.Error()))
+		return
+	}
 
 	projects, err := pc.projectService.GetProjects(userID, filters, limit, offset)
 	if err != nil {
@@ -500,6 +512,18 @@ func (pc *ProjectController) parseFormDataProject(c *gin.Context, req *services.
 	req.Address = c.PostForm("address")
 	req.Pincode = c.PostForm("pincode")
 	
+	// Parse coordinates
+	if latitudeStr := c.PostForm("latitude"); latitudeStr != "" {
+		if latitude, err := strconv.ParseFloat(latitudeStr, 64); err == nil {
+			req.Latitude = &latitude
+		}
+	}
+	if longitudeStr := c.PostForm("longitude"); longitudeStr != "" {
+		if longitude, err := strconv.ParseFloat(longitudeStr, 64); err == nil {
+			req.Longitude = &longitude
+		}
+	}
+	
 	// Parse numeric fields
 	if estimatedDurationStr := c.PostForm("estimated_duration_days"); estimatedDurationStr != "" {
 		if estimatedDuration, err := strconv.Atoi(estimatedDurationStr); err == nil {
@@ -519,7 +543,7 @@ func (pc *ProjectController) parseFormDataProject(c *gin.Context, req *services.
 	if form.File != nil && len(form.File["images"]) > 0 {
 		logrus.Infof("ProjectController.parseFormDataProject uploading %d images", len(form.File["images"]))
 		
-		// Upload images to Cloudinary
+		// Upload images to media storage
 		imageURLs, err := pc.projectService.UploadProjectImages(form.File["images"])
 		if err != nil {
 			logrus.Errorf("ProjectController.parseFormDataProject image upload error: %v", err)
@@ -23,7 +23,7 @@ func NewPromotionBannerController() *PromotionBannerController {
 	bannerService, err := services.NewPromotionBannerService()
 	if err != nil {
 		logrus.Errorf("Failed to initialize PromotionBannerService: %v", err)
-		logrus.Error("This is likely due to missing Cloudinary configuration")
+		logrus.Error("This is likely due to missing media storage configuration")
 		logrus.Error("Please ensure CLOUDINARY_URL is set in your environment variables")
 		return &PromotionBannerController{
 			BaseController:   NewBaseController(),
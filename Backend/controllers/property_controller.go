@@ -17,30 +17,30 @@ import (
 
 type PropertyController struct {
 	propertyService             *services.PropertyService
-	cloudinaryService           *services.CloudinaryService
+	mediaStore           services.MediaStore
 	enhancedNotificationService *services.EnhancedNotificationService
 }
 
 func NewPropertyController(enhancedNotificationService *services.EnhancedNotificationService) *PropertyController {
 	logrus.Info("Initializing PropertyController...")
 
-	// Initialize Cloudinary service
-	cloudinaryService, err := services.NewCloudinaryService()
+	// Initialize media store
+	mediaStore, err := services.NewMediaStore()
 	if err != nil {
-		logrus.Errorf("Failed to initialize CloudinaryService: %v", err)
-		// Don't panic, create a nil cloudinary service
-		cloudinaryService = nil
+		logrus.Errorf("Failed to initialize media store: %v", err)
+		// Don't panic, leave the media store nil
+		mediaStore = nil
 	} else {
-		logrus.Info("CloudinaryService initialized successfully")
+		logrus.Info("Media store initialized successfully")
 	}
 
-	propertyService := services.NewPropertyService(cloudinaryService)
+	propertyService := services.NewPropertyService(mediaStore, enhancedNotificationService)
 	logrus.Info("PropertyService initialized")
 
 	logrus.Info("PropertyController initialization completed")
 	return &PropertyController{
 		propertyService:             propertyService,
-		cloudinaryService:           cloudinaryService,
+		mediaStore:           mediaStore,
 		enhancedNotificationService: enhancedNotificationService,
 	}
 }
@@ -199,7 +199,16 @@ func (pc *PropertyController) GetPropertyBySlug(c *gin.Context) {
 // @Param min_area query number false "Minimum area"
 // @Param max_area query number false "Maximum area"
 // @Param furnishing_status query string false "Furnishing status"
+// @Param lat query number false "Latitude of the radius search centre"
+// @Param lng query number false "Longitude of the radius search centre"
+// @Param radius_km query number false "Radius search distance in km (default: 10, max: 100)"
+// @Param min_lat query number false "Bounding box minimum latitude"
+// @Param min_lng query number false "Bounding box minimum longitude"
+// @Param max_lat query number false "Bounding box maximum latitude"
+// @Param max_lng query number false "Bounding box maximum longitude"
+// @Param featured query bool false "Only featured listings, or only listings that are not featured"
 // @Success 200 {object} views.SuccessResponse
+// @Failure 400 {object} views.ErrorResponse
 // @Router /api/v1/properties [get]
 func (pc *PropertyController) GetAllProperties(c *gin.Context) {
 	logrus.Infof("PropertyController.GetAllProperties called")
@@ -280,12 +289,21 @@ func (pc *PropertyController) GetAllProperties(c *gin.Context) {
 			filters["uploaded_by_admin"] = adminUpload
 		}
 	}
+	if featured := c.Query("featured"); featured != "" {
+		if isFeatured, err := strconv.ParseBool(featured); err == nil {
+			filters["featured"] = isFeatured
+		}
+	}
 	if sortBy := c.Query("sortBy"); sortBy != "" {
 		filters["sort_by"] = sortBy
 	}
 	if sortOrder := c.Query("sortOrder"); sortOrder != "" {
 		filters["sort_order"] = sortOrder
 	}
+	if err := parseGeoFilters(c, filters); err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid location filter", err.Error()))
+		return
+	}
 	
 	properties, pagination, err := pc.propertyService.GetAllProperties(params, filters)
 	if err != nil {
@@ -467,6 +485,53 @@ func (pc *PropertyController) GetPropertyStats(c *gin.Context) {
 	c.JSON(http.StatusOK, views.CreateSuccessResponse("Property stats retrieved successfully", stats))
 }
 
+// GetDuplicateImages lists near-identical photos shared by different properties
+// @Summary Get duplicate property images
+// @Description List photos that are near-identical (by perceptual hash) across different properties, optionally for one property (admin only)
+// @Tags properties
+// @Accept json
+// @Produce json
+// @Param id path int false "Property ID (omit to list duplicates across all properties)"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10, max: 100)"
+// @Success 200 {object} views.SuccessResponse
+// @Failure 400 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/admin/properties/duplicate-images [get]
+// @Router /api/v1/admin/properties/{id}/duplicate-images [get]
+// @Security ApiKeyAuth
+func (pc *PropertyController) GetDuplicateImages(c *gin.Context) {
+	logrus.Infof("PropertyController.GetDuplicateImages called")
+	
+	var propertyID uint
+	if idStr := c.Param("id"); idStr != "" {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid property ID", "ID must be a valid number"))
+			return
+		}
+		propertyID = uint(id)
+	}
+	
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	
+	duplicates, pagination, err := pc.propertyService.GetDuplicateImages(propertyID, page, limit)
+	if err != nil {
+		logrus.Errorf("PropertyController.GetDuplicateImages service error: %v", err)
+		if err.Error() == "property not found" {
+			c.JSON(http.StatusNotFound, views.CreateErrorResponse("Property not found", err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to retrieve duplicate images", "Internal server error"))
+		return
+	}
+	
+	paginationView := views.CreatePagination(pagination.Page, pagination.Limit, int64(pagination.Total))
+	
+	c.JSON(http.StatusOK, views.CreateSuccessResponseWithPagination("Duplicate images retrieved successfully", duplicates, paginationView))
+}
+
 // GetUserProperties retrieves properties by user ID
 // @Summary Get user properties
 // @Description Get properties listed by the authenticated user (works for both users and brokers)
@@ -1100,6 +1165,17 @@ func (pc *PropertyController) parseFormDataProperty(c *gin.Context, property *mo
 		}
 	}
 	
+	if latitudeStr := c.PostForm("latitude"); latitudeStr != "" {
+		if latitude, err := strconv.ParseFloat(latitudeStr, 64); err == nil {
+			property.Latitude = &latitude
+		}
+	}
+	
+	if longitudeStr := c.PostForm("longitude"); longitudeStr != "" {
+		if longitude, err := strconv.ParseFloat(longitudeStr, 64); err == nil {
+			property.Longitude = &longitude
+		}
+	}
 	
 	if floorNumberStr := c.PostForm("floor_number"); floorNumberStr != "" {
 		if floorNumber, err := strconv.Atoi(floorNumberStr); err == nil {
@@ -1131,7 +1207,7 @@ func (pc *PropertyController) parseFormDataProperty(c *gin.Context, property *mo
 	if form.File != nil && len(form.File["images"]) > 0 {
 		logrus.Infof("PropertyController.parseFormDataProperty uploading %d images", len(form.File["images"]))
 		
-		// Upload images to Cloudinary
+		// Upload images to media storage
 		imageURLs, err := pc.propertyService.UploadPropertyImages(form.File["images"])
 		if err != nil {
 			logrus.Errorf("PropertyController.parseFormDataProperty image upload error: %v", err)
@@ -1223,6 +1299,18 @@ func (pc *PropertyController) parseFormDataPropertyUpdate(c *gin.Context, update
 		}
 	}
 	
+	if latitudeStr := c.PostForm("latitude"); latitudeStr != "" {
+		if latitude, err := strconv.ParseFloat(latitudeStr, 64); err == nil {
+			(*updates)["latitude"] = latitude
+		}
+	}
+	
+	if longitudeStr := c.PostForm("longitude"); longitudeStr != "" {
+		if longitude, err := strconv.ParseFloat(longitudeStr, 64); err == nil {
+			(*updates)["longitude"] = longitude
+		}
+	}
+	
 	if parkingSpacesStr := c.PostForm("parking_spaces"); parkingSpacesStr != "" {
 		if parkingSpaces, err := strconv.Atoi(parkingSpacesStr); err == nil {
 			(*updates)["parking_spaces"] = parkingSpaces
@@ -1270,7 +1358,7 @@ func (pc *PropertyController) parseFormDataPropertyUpdate(c *gin.Context, update
 	if form.File != nil && len(form.File["images"]) > 0 {
 		logrus.Infof("PropertyController.parseFormDataPropertyUpdate uploading %d images", len(form.File["images"]))
 		
-		// Upload images to Cloudinary
+		// Upload images to media storage
 		imageURLs, err := pc.propertyService.UploadPropertyImages(form.File["images"])
 		if err != nil {
 			logrus.Errorf("PropertyController.parseFormDataPropertyUpdate image upload error: %v", err)
@@ -0,0 +1,368 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"treesindia/models"
+	"treesindia/services"
+	"treesindia/utils"
+	"treesindia/views"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type PropertyEnquiryController struct {
+	enquiryService *services.PropertyEnquiryService
+}
+
+func NewPropertyEnquiryController(enhancedNotificationService *services.EnhancedNotificationService) *PropertyEnquiryController {
+	return &PropertyEnquiryController{
+		enquiryService: services.NewPropertyEnquiryService(enhancedNotificationService),
+	}
+}
+
+// CreateEnquiry creates an enquiry on a property
+// @Summary Enquire about a property
+// @Description Send an enquiry to the owner or broker of a property listing, optionally with a budget and preferred visit slots
+// @Tags property-enquiries
+// @Accept json
+// @Produce json
+// @Param id path int true "Property ID"
+// @Param enquiry body models.CreatePropertyEnquiryRequest true "Enquiry"
+// @Success 201 {object} views.SuccessResponse
+// @Failure 400 {object} views.ErrorResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/properties/{id}/enquiries [post]
+func (pec *PropertyEnquiryController) CreateEnquiry(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid property ID", "ID must be a valid number"))
+		return
+	}
+
+	var req models.CreatePropertyEnquiryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	enquiry, err := pec.enquiryService.CreateEnquiry(uint(propertyID), userID.(uint), &req)
+	if err != nil {
+		logrus.Errorf("PropertyEnquiryController.CreateEnquiry service error: %v", err)
+		c.JSON(enquiryErrorStatus(err), views.CreateErrorResponse("Failed to create enquiry", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, views.CreateSuccessResponse("Enquiry sent successfully", enquiry))
+}
+
+// GetMyEnquiries gets the enquiries made by the user
+// @Summary Get my enquiries
+// @Description Get the property enquiries made by the authenticated user
+// @Tags property-enquiries
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} views.SuccessResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Router /api/v1/user/enquiries [get]
+func (pec *PropertyEnquiryController) GetMyEnquiries(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	params := parseListPagination(c)
+	enquiries, pagination, err := pec.enquiryService.GetBuyerEnquiries(userID.(uint), params)
+	if err != nil {
+		logrus.Errorf("PropertyEnquiryController.GetMyEnquiries service error: %v", err)
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to retrieve enquiries", "Internal server error"))
+		return
+	}
+
+	paginationView := views.CreatePagination(int(pagination.Page), int(pagination.Limit), pagination.Total)
+	c.JSON(http.StatusOK, views.CreateSuccessResponseWithPagination("Enquiries retrieved successfully", enquiries, paginationView))
+}
+
+// GetEnquiry gets an enquiry with its site visits
+// @Summary Get enquiry
+// @Description Get an enquiry made by the authenticated user, or a lead on one of their listings, with its site visits
+// @Tags property-enquiries
+// @Produce json
+// @Param id path int true "Enquiry ID"
+// @Success 200 {object} views.SuccessResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/enquiries/{id} [get]
+func (pec *PropertyEnquiryController) GetEnquiry(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	enquiryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid enquiry ID", "ID must be a valid number"))
+		return
+	}
+
+	enquiry, err := pec.enquiryService.GetEnquiry(uint(enquiryID), userID.(uint))
+	if err != nil {
+		c.JSON(enquiryErrorStatus(err), views.CreateErrorResponse("Failed to retrieve enquiry", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Enquiry retrieved successfully", enquiry))
+}
+
+// GetLeads gets the leads on the user's listings
+// @Summary Get leads
+// @Description Get the enquiries on the authenticated owner's or broker's listings
+// @Tags property-enquiries
+// @Produce json
+// @Param status query string false "Lead status (new, contacted, visit_scheduled, negotiating, closed_won, closed_lost)"
+// @Param property_id query int false "Property ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} views.SuccessResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Router /api/v1/user/leads [get]
+func (pec *PropertyEnquiryController) GetLeads(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	var propertyID uint64
+	if propertyIDStr := c.Query("property_id"); propertyIDStr != "" {
+		var err error
+		propertyID, err = strconv.ParseUint(propertyIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid property ID", "property_id must be a valid number"))
+			return
+		}
+	}
+
+	params := parseListPagination(c)
+	leads, pagination, err := pec.enquiryService.GetOwnerLeads(userID.(uint), c.Query("status"), uint(propertyID), params)
+	if err != nil {
+		logrus.Errorf("PropertyEnquiryController.GetLeads service error: %v", err)
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to retrieve leads", "Internal server error"))
+		return
+	}
+
+	paginationView := views.CreatePagination(int(pagination.Page), int(pagination.Limit), pagination.Total)
+	c.JSON(http.StatusOK, views.CreateSuccessResponseWithPagination("Leads retrieved successfully", leads, paginationView))
+}
+
+// GetLeadsDashboard gets the user's lead pipeline and per-listing conversion stats
+// @Summary Get leads dashboard
+// @Description Get lead counts by pipeline stage and enquiry, visit and conversion stats for each listing
+// @Tags property-enquiries
+// @Produce json
+// @Success 200 {object} views.SuccessResponse{data=models.LeadsDashboard}
+// @Failure 401 {object} views.ErrorResponse
+// @Router /api/v1/user/leads/dashboard [get]
+func (pec *PropertyEnquiryController) GetLeadsDashboard(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	dashboard, err := pec.enquiryService.GetLeadsDashboard(userID.(uint))
+	if err != nil {
+		logrus.Errorf("PropertyEnquiryController.GetLeadsDashboard service error: %v", err)
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to retrieve leads dashboard", "Internal server error"))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Leads dashboard retrieved successfully", dashboard))
+}
+
+// UpdateLeadStatus moves a lead through the pipeline
+// @Summary Update lead status
+// @Description Move a lead on one of the authenticated user's listings to another pipeline stage; closed leads cannot be reopened
+// @Tags property-enquiries
+// @Accept json
+// @Produce json
+// @Param id path int true "Enquiry ID"
+// @Param status body models.UpdateLeadStatusRequest true "Lead status"
+// @Success 200 {object} views.SuccessResponse
+// @Failure 400 {object} views.ErrorResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/leads/{id}/status [patch]
+func (pec *PropertyEnquiryController) UpdateLeadStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	enquiryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid enquiry ID", "ID must be a valid number"))
+		return
+	}
+
+	var req models.UpdateLeadStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	enquiry, err := pec.enquiryService.UpdateLeadStatus(uint(enquiryID), userID.(uint), &req)
+	if err != nil {
+		logrus.Errorf("PropertyEnquiryController.UpdateLeadStatus service error: %v", err)
+		c.JSON(enquiryErrorStatus(err), views.CreateErrorResponse("Failed to update lead", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Lead updated successfully", enquiry))
+}
+
+// ScheduleVisit books a site visit on an enquiry
+// @Summary Schedule site visit
+// @Description Book a site visit on an open enquiry; either the buyer or the listing owner can book it
+// @Tags property-enquiries
+// @Accept json
+// @Produce json
+// @Param id path int true "Enquiry ID"
+// @Param visit body models.ScheduleSiteVisitRequest true "Site visit"
+// @Success 201 {object} views.SuccessResponse
+// @Failure 400 {object} views.ErrorResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/enquiries/{id}/visits [post]
+func (pec *PropertyEnquiryController) ScheduleVisit(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	enquiryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid enquiry ID", "ID must be a valid number"))
+		return
+	}
+
+	var req models.ScheduleSiteVisitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	visit, err := pec.enquiryService.ScheduleVisit(uint(enquiryID), userID.(uint), &req)
+	if err != nil {
+		logrus.Errorf("PropertyEnquiryController.ScheduleVisit service error: %v", err)
+		c.JSON(enquiryErrorStatus(err), views.CreateErrorResponse("Failed to schedule visit", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, views.CreateSuccessResponse("Site visit scheduled successfully", visit))
+}
+
+// GetUpcomingVisits gets the user's scheduled site visits
+// @Summary Get upcoming site visits
+// @Description Get the scheduled site visits of the authenticated user as a buyer or listing owner
+// @Tags property-enquiries
+// @Produce json
+// @Success 200 {object} views.SuccessResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Router /api/v1/user/site-visits [get]
+func (pec *PropertyEnquiryController) GetUpcomingVisits(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	visits, err := pec.enquiryService.GetUpcomingVisits(userID.(uint))
+	if err != nil {
+		logrus.Errorf("PropertyEnquiryController.GetUpcomingVisits service error: %v", err)
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to retrieve site visits", "Internal server error"))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Site visits retrieved successfully", visits))
+}
+
+// UpdateVisit records the outcome of a site visit
+// @Summary Update site visit
+// @Description Cancel a scheduled site visit, or as the listing owner mark it completed or a no-show
+// @Tags property-enquiries
+// @Accept json
+// @Produce json
+// @Param id path int true "Site visit ID"
+// @Param visit body models.UpdateSiteVisitRequest true "Site visit outcome"
+// @Success 200 {object} views.SuccessResponse
+// @Failure 400 {object} views.ErrorResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/site-visits/{id} [patch]
+func (pec *PropertyEnquiryController) UpdateVisit(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	visitID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid site visit ID", "ID must be a valid number"))
+		return
+	}
+
+	var req models.UpdateSiteVisitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	visit, err := pec.enquiryService.UpdateVisit(uint(visitID), userID.(uint), &req)
+	if err != nil {
+		logrus.Errorf("PropertyEnquiryController.UpdateVisit service error: %v", err)
+		c.JSON(enquiryErrorStatus(err), views.CreateErrorResponse("Failed to update site visit", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Site visit updated successfully", visit))
+}
+
+// parseListPagination parses pagination parameters, defaulting to 20 items per page
+func parseListPagination(c *gin.Context) utils.PaginationParams {
+	paginationHelper := utils.NewPaginationHelper()
+	params := paginationHelper.ParsePaginationParams(c)
+
+	// Set default limit to 20
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+	return params
+}
+
+// enquiryErrorStatus maps an enquiry service error to an HTTP status
+func enquiryErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(err.Error(), "failed to"):
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
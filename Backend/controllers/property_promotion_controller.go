@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"treesindia/models"
+	"treesindia/services"
+	"treesindia/views"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type PropertyPromotionController struct {
+	promotionService *services.PropertyPromotionService
+}
+
+func NewPropertyPromotionController() *PropertyPromotionController {
+	return &PropertyPromotionController{
+		promotionService: services.NewPropertyPromotionService(),
+	}
+}
+
+// RenewProperty renews an expired or expiring listing
+// @Summary Renew a property listing
+// @Description Renew an expired listing, or one within the renewal window of its expiry, for another listing period
+// @Tags property-promotions
+// @Produce json
+// @Param id path int true "Property ID"
+// @Success 200 {object} views.SuccessResponse{data=models.Property}
+// @Failure 400 {object} views.ErrorResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/properties/{id}/renew [post]
+func (ppc *PropertyPromotionController) RenewProperty(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid property ID", err.Error()))
+		return
+	}
+
+	property, err := ppc.promotionService.RenewListing(userID.(uint), uint(propertyID))
+	if err != nil {
+		logrus.Errorf("PropertyPromotionController.RenewProperty service error: %v", err)
+		c.JSON(promotionErrorStatus(err), views.CreateErrorResponse("Failed to renew listing", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Listing renewed successfully", property))
+}
+
+// GetPromotionOptions gets the boosts and featured slots the owner can buy for a listing
+// @Summary Get listing promotion options
+// @Description Get the price and duration of boosts and featured slots, the free boosts left from the subscription plan this month, the featured slots free in the listing's city, and whether the listing can be renewed
+// @Tags property-promotions
+// @Produce json
+// @Param id path int true "Property ID"
+// @Success 200 {object} views.SuccessResponse{data=models.PropertyPromotionOptions}
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/properties/{id}/promotion-options [get]
+func (ppc *PropertyPromotionController) GetPromotionOptions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid property ID", err.Error()))
+		return
+	}
+
+	options, err := ppc.promotionService.GetPromotionOptions(userID.(uint), uint(propertyID))
+	if err != nil {
+		logrus.Errorf("PropertyPromotionController.GetPromotionOptions service error: %v", err)
+		c.JSON(promotionErrorStatus(err), views.CreateErrorResponse("Failed to get promotion options", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Promotion options retrieved successfully", options))
+}
+
+// PurchasePromotion buys a boost or featured slot for a listing
+// @Summary Buy a listing promotion
+// @Description Buy a boost or featured slot for a listing from the wallet, with a free boost from the subscription plan, or through Razorpay. Razorpay purchases return the order to pay and are activated by the verify endpoint.
+// @Tags property-promotions
+// @Accept json
+// @Produce json
+// @Param id path int true "Property ID"
+// @Param promotion body models.PurchasePropertyPromotionRequest true "Promotion"
+// @Success 201 {object} views.SuccessResponse
+// @Failure 400 {object} views.ErrorResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/properties/{id}/promotions [post]
+func (ppc *PropertyPromotionController) PurchasePromotion(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid property ID", err.Error()))
+		return
+	}
+
+	var req models.PurchasePropertyPromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	promotion, order, err := ppc.promotionService.PurchasePromotion(userID.(uint), uint(propertyID), &req)
+	if err != nil {
+		logrus.Errorf("PropertyPromotionController.PurchasePromotion service error: %v", err)
+		c.JSON(promotionErrorStatus(err), views.CreateErrorResponse("Failed to buy promotion", err.Error()))
+		return
+	}
+
+	if order != nil {
+		c.JSON(http.StatusCreated, views.CreateSuccessResponse("Payment order created successfully", gin.H{
+			"promotion":      promotion,
+			"razorpay_order": order,
+		}))
+		return
+	}
+
+	c.JSON(http.StatusCreated, views.CreateSuccessResponse("Promotion activated successfully", gin.H{
+		"promotion": promotion,
+	}))
+}
+
+// VerifyPromotionPayment verifies the Razorpay payment of a promotion and activates it
+// @Summary Verify a listing promotion payment
+// @Description Verify the Razorpay payment of a pending boost or featured slot and activate it
+// @Tags property-promotions
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Param payment body models.VerifyPropertyPromotionPaymentRequest true "Razorpay payment"
+// @Success 200 {object} views.SuccessResponse{data=models.PropertyPromotion}
+// @Failure 400 {object} views.ErrorResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/property-promotions/{id}/verify [post]
+func (ppc *PropertyPromotionController) VerifyPromotionPayment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	promotionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid promotion ID", err.Error()))
+		return
+	}
+
+	var req models.VerifyPropertyPromotionPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	promotion, err := ppc.promotionService.VerifyPromotionPayment(userID.(uint), uint(promotionID), &req)
+	if err != nil {
+		logrus.Errorf("PropertyPromotionController.VerifyPromotionPayment service error: %v", err)
+		c.JSON(promotionErrorStatus(err), views.CreateErrorResponse("Failed to verify payment", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Promotion activated successfully", promotion))
+}
+
+// GetPropertyPromotions gets the promotions bought for a listing
+// @Summary Get listing promotions
+// @Description Get the boosts and featured slots bought for a listing, newest first
+// @Tags property-promotions
+// @Produce json
+// @Param id path int true "Property ID"
+// @Success 200 {object} views.SuccessResponse{data=[]models.PropertyPromotion}
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/properties/{id}/promotions [get]
+func (ppc *PropertyPromotionController) GetPropertyPromotions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid property ID", err.Error()))
+		return
+	}
+
+	promotions, err := ppc.promotionService.GetPropertyPromotions(userID.(uint), uint(propertyID))
+	if err != nil {
+		logrus.Errorf("PropertyPromotionController.GetPropertyPromotions service error: %v", err)
+		c.JSON(promotionErrorStatus(err), views.CreateErrorResponse("Failed to get promotions", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Promotions retrieved successfully", promotions))
+}
+
+// promotionErrorStatus maps a promotion service error to an HTTP status
+func promotionErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(err.Error(), "failed to"):
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
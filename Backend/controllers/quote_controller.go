@@ -405,6 +405,56 @@ func (qc *QuoteController) CleanupExpiredQuotes(c *gin.Context) {
 	c.JSON(200, views.CreateSuccessResponse("Expired quotes cleaned up successfully", nil))
 }
 
+// ExtendQuoteValidity extends the expiry of a quote (admin only)
+// @Summary Extend quote validity
+// @Description Admin extends the expiry of a quote awaiting the customer
+// @Tags quotes
+// @Accept json
+// @Produce json
+// @Param id path integer true "Booking ID"
+// @Param request body models.ExtendQuoteValidityRequest true "New validity"
+// @Success 200 {object} views.Response{data=models.Booking}
+// @Router /api/v1/admin/bookings/{id}/extend-quote [post]
+func (qc *QuoteController) ExtendQuoteValidity(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("QuoteController.ExtendQuoteValidity panic: %v", r)
+		}
+	}()
+
+	// Get admin ID from context
+	adminID := qc.GetUserID(c)
+	if adminID == 0 {
+		logrus.Error("QuoteController.ExtendQuoteValidity: user_id not found in context")
+		c.JSON(401, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	// Parse booking ID
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	// Parse request body
+	var req models.ExtendQuoteValidityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	// Extend quote validity
+	booking, err := qc.quoteService.ExtendQuoteValidity(uint(bookingID), adminID, &req)
+	if err != nil {
+		logrus.Errorf("QuoteController.ExtendQuoteValidity service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to extend quote validity", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Quote validity extended successfully", booking))
+}
+
 // CreateQuotePayment creates a payment order for quote acceptance
 // @Summary Create payment order for quote acceptance
 // @Description Customer creates a payment order to pay for accepted quote
@@ -556,6 +606,195 @@ func (qc *QuoteController) WalletPayment(c *gin.Context) {
 	c.JSON(200, views.CreateSuccessResponse("Wallet payment processed successfully", booking))
 }
 
+// CounterOffer records a customer counter-offer on a quote
+// @Summary Counter-offer on quote
+// @Description Customer proposes a different amount for the current quote
+// @Tags quotes
+// @Accept json
+// @Produce json
+// @Param id path integer true "Booking ID"
+// @Param request body models.CounterOfferRequest true "Counter-offer details"
+// @Success 200 {object} views.Response{data=models.QuoteRevision}
+// @Router /api/v1/bookings/{id}/counter-offer [post]
+func (qc *QuoteController) CounterOffer(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("QuoteController.CounterOffer panic: %v", r)
+		}
+	}()
+
+	// Get user ID from context
+	userID := qc.GetUserID(c)
+	if userID == 0 {
+		logrus.Error("QuoteController.CounterOffer: user_id not found in context")
+		c.JSON(401, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	// Parse booking ID
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	// Parse request body
+	var req models.CounterOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	// Record counter-offer
+	revision, err := qc.quoteService.CounterOffer(uint(bookingID), userID, &req)
+	if err != nil {
+		logrus.Errorf("QuoteController.CounterOffer service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to submit counter-offer", err.Error()))
+		return
+	}
+
+	qc.sendQuoteResponseNotification(uint(bookingID), revision)
+
+	c.JSON(200, views.CreateSuccessResponse("Counter-offer submitted successfully", revision))
+}
+
+// RequestRevision records a customer request for a revised quote
+// @Summary Request quote revision
+// @Description Customer asks the admin to revise the current quote
+// @Tags quotes
+// @Accept json
+// @Produce json
+// @Param id path integer true "Booking ID"
+// @Param request body models.RequestQuoteRevisionRequest true "What should change"
+// @Success 200 {object} views.Response{data=models.QuoteRevision}
+// @Router /api/v1/bookings/{id}/request-revision [post]
+func (qc *QuoteController) RequestRevision(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("QuoteController.RequestRevision panic: %v", r)
+		}
+	}()
+
+	// Get user ID from context
+	userID := qc.GetUserID(c)
+	if userID == 0 {
+		logrus.Error("QuoteController.RequestRevision: user_id not found in context")
+		c.JSON(401, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	// Parse booking ID
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	// Parse request body
+	var req models.RequestQuoteRevisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	// Record revision request
+	revision, err := qc.quoteService.RequestRevision(uint(bookingID), userID, &req)
+	if err != nil {
+		logrus.Errorf("QuoteController.RequestRevision service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to request quote revision", err.Error()))
+		return
+	}
+
+	qc.sendQuoteResponseNotification(uint(bookingID), revision)
+
+	c.JSON(200, views.CreateSuccessResponse("Quote revision requested successfully", revision))
+}
+
+// GetQuoteRevisions gets the quote revision history of a booking
+// @Summary Get quote revisions
+// @Description Get every quote version, counter-offer and revision request for a booking
+// @Tags quotes
+// @Produce json
+// @Param id path integer true "Booking ID"
+// @Success 200 {object} views.Response{data=[]models.QuoteRevision}
+// @Router /api/v1/bookings/{id}/quote-revisions [get]
+func (qc *QuoteController) GetQuoteRevisions(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("QuoteController.GetQuoteRevisions panic: %v", r)
+		}
+	}()
+
+	// Get user ID from context
+	userID := qc.GetUserID(c)
+	if userID == 0 {
+		logrus.Error("QuoteController.GetQuoteRevisions: user_id not found in context")
+		c.JSON(401, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	// Parse booking ID
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid booking ID", err.Error()))
+		return
+	}
+
+	userType, _ := c.Get("user_type")
+	isAdmin := userType == string(models.UserTypeAdmin)
+
+	revisions, err := qc.quoteService.GetQuoteRevisions(uint(bookingID), userID, isAdmin)
+	if err != nil {
+		logrus.Errorf("QuoteController.GetQuoteRevisions service error: %v", err)
+		c.JSON(500, views.CreateErrorResponse("Failed to get quote revisions", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Quote revisions retrieved successfully", revisions))
+}
+
+// sendQuoteResponseNotification tells the quoting admin that the customer countered or asked for a revision
+func (qc *QuoteController) sendQuoteResponseNotification(bookingID uint, revision *models.QuoteRevision) {
+	go func() {
+		if qc.enhancedNotificationService == nil {
+			logrus.Warn("Notification service not available, skipping quote response notification")
+			return
+		}
+
+		booking, err := repositories.NewBookingRepository().GetByID(bookingID)
+		if err != nil || booking.QuoteProvidedBy == nil {
+			return
+		}
+
+		var title, body string
+		if revision.Type == models.QuoteRevisionTypeCounterOffer {
+			title = "Quote Counter-Offer"
+			body = fmt.Sprintf("Customer has countered the quote for booking %s with ₹%.2f.", booking.BookingReference, revision.TotalAmount)
+		} else {
+			title = "Quote Revision Requested"
+			body = fmt.Sprintf("Customer has asked for a revised quote for booking %s.", booking.BookingReference)
+		}
+
+		notificationReq := &services.NotificationRequest{
+			UserID: *booking.QuoteProvidedBy,
+			Type:   models.NotificationTypeBooking,
+			Title:  title,
+			Body:   body,
+			Data: map[string]string{
+				"type":       string(revision.Type),
+				"bookingId":  fmt.Sprintf("%d", booking.ID),
+				"revisionId": fmt.Sprintf("%d", revision.ID),
+				"version":    fmt.Sprintf("%d", revision.Version),
+			},
+			Priority: "high",
+		}
+
+		if _, err := qc.enhancedNotificationService.SendNotification(notificationReq); err != nil {
+			logrus.Errorf("Failed to send quote response notification for booking %d: %v", booking.ID, err)
+		}
+	}()
+}
+
 // sendQuoteNotification sends notification when admin provides a quote
 func (qc *QuoteController) sendQuoteNotification(booking *models.Booking) {
 	// This runs in a goroutine, so it doesn't block the response
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"strconv"
+	"treesindia/models"
+	"treesindia/services"
+	"treesindia/views"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ReferralController struct {
+	BaseController
+	referralService *services.ReferralService
+}
+
+func NewReferralController() *ReferralController {
+	return &ReferralController{
+		BaseController:  *NewBaseController(),
+		referralService: services.NewReferralService(),
+	}
+}
+
+// GetMyReferral gets the user's referral code and earnings
+// @Summary Get my referral code
+// @Description Get the authenticated user's referral code and referral earnings
+// @Tags referrals
+// @Produce json
+// @Success 200 {object} views.Response{data=models.ReferralSummary}
+// @Router /api/v1/referrals/me [get]
+func (rc *ReferralController) GetMyReferral(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("ReferralController.GetMyReferral panic: %v", r)
+		}
+	}()
+
+	userID := rc.GetUserID(c)
+	if userID == 0 {
+		c.JSON(401, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	summary, err := rc.referralService.GetReferralSummary(userID)
+	if err != nil {
+		logrus.Errorf("ReferralController.GetMyReferral service error: %v", err)
+		c.JSON(500, views.CreateErrorResponse("Failed to get referral details", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Referral details retrieved successfully", summary))
+}
+
+// ApplyReferralCode applies a friend's referral code
+// @Summary Apply referral code
+// @Description New customer applies the referral code of the user who invited them
+// @Tags referrals
+// @Accept json
+// @Produce json
+// @Param request body models.ApplyReferralCodeRequest true "Referral code"
+// @Success 200 {object} views.Response{data=models.Referral}
+// @Router /api/v1/referrals/apply [post]
+func (rc *ReferralController) ApplyReferralCode(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("ReferralController.ApplyReferralCode panic: %v", r)
+		}
+	}()
+
+	userID := rc.GetUserID(c)
+	if userID == 0 {
+		c.JSON(401, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	var req models.ApplyReferralCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	referral, err := rc.referralService.ApplyReferralCode(userID, req.Code)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Failed to apply referral code", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Referral code applied successfully", referral))
+}
+
+// GetReferrals gets referrals (admin only)
+// @Summary Get referrals
+// @Description Admin lists referrals and the rewards paid
+// @Tags referrals
+// @Produce json
+// @Param status query string false "Referral status"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} views.Response
+// @Router /api/v1/admin/referrals [get]
+func (rc *ReferralController) GetReferrals(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("ReferralController.GetReferrals panic: %v", r)
+		}
+	}()
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	status := models.ReferralStatus(c.Query("status"))
+
+	referrals, pagination, err := rc.referralService.GetReferrals(status, page, limit)
+	if err != nil {
+		logrus.Errorf("ReferralController.GetReferrals service error: %v", err)
+		c.JSON(500, views.CreateErrorResponse("Failed to get referrals", err.Error()))
+		return
+	}
+
+	response := map[string]interface{}{
+		"referrals":  referrals,
+		"pagination": pagination,
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Referrals retrieved successfully", response))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"treesindia/models"
 	"treesindia/repositories"
 	"treesindia/services"
@@ -16,7 +17,7 @@ import (
 
 type RoleApplicationController struct {
 	applicationService              *services.RoleApplicationService
-	cloudinaryService               *services.CloudinaryService
+	mediaStore               services.MediaStore
 	enhancedNotificationService     *services.EnhancedNotificationService
 }
 
@@ -30,11 +31,11 @@ func NewRoleApplicationController(enhancedNotificationService *services.Enhanced
 
 	applicationService := services.NewRoleApplicationService(applicationRepo, userRepo, nil)
 
-	// Initialize Cloudinary service
-	cloudinaryService, err := services.NewCloudinaryService()
+	// Initialize media store
+	mediaStore, err := services.NewMediaStore()
 	if err != nil {
-		logrus.Errorf("Failed to initialize Cloudinary service: %v", err)
-		cloudinaryService = nil
+		logrus.Errorf("Failed to initialize media store: %v", err)
+		mediaStore = nil
 	}
 
 	logrus.Info("Services initialized")
@@ -53,7 +54,7 @@ func NewRoleApplicationController(enhancedNotificationService *services.Enhanced
 	logrus.Info("RoleApplicationController initialization completed")
 	return &RoleApplicationController{
 		applicationService:          applicationService,
-		cloudinaryService:           cloudinaryService,
+		mediaStore:           mediaStore,
 		enhancedNotificationService: enhancedNotificationService,
 	}
 }
@@ -123,8 +124,8 @@ func (c *RoleApplicationController) SubmitWorkerApplication(ctx *gin.Context) {
 		return
 	}
 
-	// Check if Cloudinary service is available
-	if c.cloudinaryService == nil {
+	// Check if media store is available
+	if c.mediaStore == nil {
 		ctx.JSON(http.StatusInternalServerError, views.CreateErrorResponse("File upload service unavailable", "File upload service is not configured"))
 		return
 	}
@@ -138,7 +139,7 @@ func (c *RoleApplicationController) SubmitWorkerApplication(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Missing aadhar card", "Aadhar card file is required"))
 		return
 	}
-	aadharURL, err := c.cloudinaryService.UploadImage(aadharFile, "role-applications/worker/documents")
+	aadharURL, err := c.mediaStore.UploadImage(aadharFile, "role-applications/worker/documents")
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to upload aadhar card", err.Error()))
 		return
@@ -151,7 +152,7 @@ func (c *RoleApplicationController) SubmitWorkerApplication(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Missing pan card", "PAN card file is required"))
 		return
 	}
-	panURL, err := c.cloudinaryService.UploadImage(panFile, "role-applications/worker/documents")
+	panURL, err := c.mediaStore.UploadImage(panFile, "role-applications/worker/documents")
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to upload pan card", err.Error()))
 		return
@@ -164,7 +165,7 @@ func (c *RoleApplicationController) SubmitWorkerApplication(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Missing profile picture", "Profile picture file is required"))
 		return
 	}
-	profileURL, err := c.cloudinaryService.UploadImage(profileFile, "users/avatars")
+	profileURL, err := c.mediaStore.UploadImage(profileFile, "users/avatars")
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to upload profile picture", err.Error()))
 		return
@@ -177,7 +178,7 @@ func (c *RoleApplicationController) SubmitWorkerApplication(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Missing police verification", "Police verification file is required"))
 		return
 	}
-	policeURL, err := c.cloudinaryService.UploadImage(policeFile, "role-applications/worker/documents")
+	policeURL, err := c.mediaStore.UploadImage(policeFile, "role-applications/worker/documents")
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to upload police verification", err.Error()))
 		return
@@ -278,8 +279,8 @@ func (c *RoleApplicationController) SubmitBrokerApplication(ctx *gin.Context) {
 		return
 	}
 
-	// Check if Cloudinary service is available
-	if c.cloudinaryService == nil {
+	// Check if media store is available
+	if c.mediaStore == nil {
 		ctx.JSON(http.StatusInternalServerError, views.CreateErrorResponse("File upload service unavailable", "File upload service is not configured"))
 		return
 	}
@@ -293,7 +294,7 @@ func (c *RoleApplicationController) SubmitBrokerApplication(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Missing aadhar card", "Aadhar card file is required"))
 		return
 	}
-	aadharURL, err := c.cloudinaryService.UploadImage(aadharFile, "role-applications/broker/documents")
+	aadharURL, err := c.mediaStore.UploadImage(aadharFile, "role-applications/broker/documents")
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to upload aadhar card", err.Error()))
 		return
@@ -306,7 +307,7 @@ func (c *RoleApplicationController) SubmitBrokerApplication(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Missing pan card", "PAN card file is required"))
 		return
 	}
-	panURL, err := c.cloudinaryService.UploadImage(panFile, "role-applications/broker/documents")
+	panURL, err := c.mediaStore.UploadImage(panFile, "role-applications/broker/documents")
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to upload pan card", err.Error()))
 		return
@@ -319,7 +320,7 @@ func (c *RoleApplicationController) SubmitBrokerApplication(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Missing profile picture", "Profile picture file is required"))
 		return
 	}
-	profileURL, err := c.cloudinaryService.UploadImage(profileFile, "users/avatars")
+	profileURL, err := c.mediaStore.UploadImage(profileFile, "users/avatars")
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to upload profile picture", err.Error()))
 		return
@@ -425,6 +426,10 @@ func (c *RoleApplicationController) GetApplication(ctx *gin.Context) {
 		return
 	}
 
+	if canViewBankingDetails(ctx) {
+		application.RevealAccountNumber()
+	}
+
 	ctx.JSON(http.StatusOK, views.CreateSuccessResponse("Application retrieved successfully", application))
 }
 
@@ -500,6 +505,10 @@ func (c *RoleApplicationController) UpdateApplication(ctx *gin.Context) {
 	application, err := c.applicationService.UpdateApplication(uint(id), adminID, applicationStatus, &workerType)
 	if err != nil {
 		logrus.Errorf("Failed to update application: %v", err)
+		if strings.Contains(err.Error(), "documents not verified") {
+			ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Documents not verified", err.Error()))
+			return
+		}
 		ctx.JSON(http.StatusNotFound, views.CreateErrorResponse("Application not found", "Application with the specified ID does not exist"))
 		return
 	}
@@ -528,6 +537,12 @@ func (c *RoleApplicationController) GetPendingApplications(ctx *gin.Context) {
 		return
 	}
 
+	if canViewBankingDetails(ctx) {
+		for i := range applications {
+			applications[i].RevealAccountNumber()
+		}
+	}
+
 	ctx.JSON(http.StatusOK, views.CreateSuccessResponse("Pending applications retrieved successfully", applications))
 }
 
@@ -645,6 +660,12 @@ func (c *RoleApplicationController) GetApplicationsWithFilters(ctx *gin.Context)
 		return
 	}
 	
+	if canViewBankingDetails(ctx) {
+		for i := range applications {
+			applications[i].RevealAccountNumber()
+		}
+	}
+	
 	// Calculate pagination info
 	totalPages := (total + int64(limit) - 1) / int64(limit)
 	
@@ -691,6 +712,196 @@ func (c *RoleApplicationController) DeleteApplication(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, views.CreateSuccessResponse("Application deleted successfully", nil))
 }
 
+// GetMyApplicationDocuments gets the review state of each document of the user's application
+// @Summary Get my application documents
+// @Description Get the documents of the authenticated user's role application with their verification status
+// @Tags role-applications
+// @Produce json
+// @Success 200 {object} views.Response{data=[]models.UserDocument}
+// @Failure 401 {object} views.Response
+// @Failure 404 {object} views.Response
+// @Router /role-applications/me/documents [get]
+func (c *RoleApplicationController) GetMyApplicationDocuments(ctx *gin.Context) {
+	userIDInterface, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	documents, err := c.applicationService.GetUserApplicationDocuments(userID)
+	if err != nil {
+		logrus.Errorf("Failed to get application documents: %v", err)
+		ctx.JSON(http.StatusNotFound, views.CreateErrorResponse("Application not found", "No application found for this user"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views.CreateSuccessResponse("Documents retrieved successfully", documents))
+}
+
+// ReuploadApplicationDocument replaces a rejected or expired document of the user's application
+// @Summary Re-upload application document
+// @Description Replace a rejected or expired document; it is reviewed again
+// @Tags role-applications
+// @Accept multipart/form-data
+// @Produce json
+// @Param document_type path string true "Document type (aadhaar_card, pan_card, profile_photo, police_verification)"
+// @Param file formData file true "Document file"
+// @Success 200 {object} views.Response{data=models.UserDocument}
+// @Failure 400 {object} views.Response
+// @Failure 401 {object} views.Response
+// @Router /role-applications/me/documents/{document_type} [put]
+func (c *RoleApplicationController) ReuploadApplicationDocument(ctx *gin.Context) {
+	userIDInterface, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+	userID := userIDInterface.(uint)
+
+	documentType := models.DocumentType(ctx.Param("document_type"))
+	if !isApplicationDocumentType(documentType) {
+		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid document type", "Document type must be aadhaar_card, pan_card, profile_photo or police_verification"))
+		return
+	}
+
+	if c.mediaStore == nil {
+		ctx.JSON(http.StatusInternalServerError, views.CreateErrorResponse("File upload service unavailable", "File upload service is not configured"))
+		return
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Missing file", "Document file is required"))
+		return
+	}
+	fileURL, err := c.mediaStore.UploadImage(file, "role-applications/documents")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to upload document", err.Error()))
+		return
+	}
+
+	document, err := c.applicationService.ReuploadApplicationDocument(userID, documentType, fileURL, file.Filename, file.Size)
+	if err != nil {
+		logrus.Errorf("Failed to re-upload document: %v", err)
+		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Failed to re-upload document", err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views.CreateSuccessResponse("Document uploaded successfully", document))
+}
+
+// GetApplicationDocuments gets the review state of each document of an application (admin only)
+// @Summary Get application documents
+// @Description Get the documents of a role application with their verification status (admin only)
+// @Tags role-applications
+// @Produce json
+// @Param id path int true "Application ID"
+// @Success 200 {object} views.Response{data=[]models.UserDocument}
+// @Failure 400 {object} views.Response
+// @Failure 401 {object} views.Response
+// @Failure 403 {object} views.Response
+// @Failure 404 {object} views.Response
+// @Router /admin/role-applications/{id}/documents [get]
+func (c *RoleApplicationController) GetApplicationDocuments(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid application ID", "Application ID must be a valid number"))
+		return
+	}
+
+	documents, err := c.applicationService.GetApplicationDocuments(uint(id))
+	if err != nil {
+		logrus.Errorf("Failed to get application documents: %v", err)
+		ctx.JSON(http.StatusNotFound, views.CreateErrorResponse("Application not found", "Application with the specified ID does not exist"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views.CreateSuccessResponse("Documents retrieved successfully", documents))
+}
+
+// ReviewApplicationDocument verifies or rejects one document of an application (admin only)
+// @Summary Review application document
+// @Description Verify a document, optionally with an expiry date, or reject it with a reason (admin only)
+// @Tags role-applications
+// @Accept json
+// @Produce json
+// @Param id path int true "Application ID"
+// @Param document_id path int true "Document ID"
+// @Param request body models.ReviewUserDocumentRequest true "Review"
+// @Success 200 {object} views.Response{data=models.UserDocument}
+// @Failure 400 {object} views.Response
+// @Failure 401 {object} views.Response
+// @Failure 403 {object} views.Response
+// @Router /admin/role-applications/{id}/documents/{document_id} [put]
+func (c *RoleApplicationController) ReviewApplicationDocument(ctx *gin.Context) {
+	adminIDInterface, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+	adminID := adminIDInterface.(uint)
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid application ID", "Application ID must be a valid number"))
+		return
+	}
+	documentID, err := strconv.ParseUint(ctx.Param("document_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid document ID", "Document ID must be a valid number"))
+		return
+	}
+
+	var req models.ReviewUserDocumentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	document, err := c.applicationService.ReviewApplicationDocument(uint(id), uint(documentID), adminID, &req)
+	if err != nil {
+		logrus.Errorf("Failed to review document: %v", err)
+		ctx.JSON(http.StatusBadRequest, views.CreateErrorResponse("Failed to review document", err.Error()))
+		return
+	}
+
+	if document.VerificationStatus == models.DocumentVerificationStatusRejected {
+		c.sendDocumentRejectedNotification(uint(id), document)
+	}
+
+	ctx.JSON(http.StatusOK, views.CreateSuccessResponse("Document reviewed successfully", document))
+}
+
+// isApplicationDocumentType checks if a document type is collected by role applications
+func isApplicationDocumentType(documentType models.DocumentType) bool {
+	for _, applicationType := range models.ApplicationDocumentTypes {
+		if applicationType == documentType {
+			return true
+		}
+	}
+	return false
+}
+
+// canViewBankingDetails reports whether the requesting admin may see full bank account numbers.
+// Everyone else gets them masked.
+func canViewBankingDetails(ctx *gin.Context) bool {
+	rawRoles, exists := ctx.Get("admin_roles")
+	if !exists {
+		return false
+	}
+	roles, ok := rawRoles.([]string)
+	if !ok {
+		return false
+	}
+	for _, role := range roles {
+		if role == string(models.AdminRoleFinanceManager) || role == string(models.AdminRoleSuperAdmin) {
+			return true
+		}
+	}
+	return false
+}
+
 // sendApplicationStatusNotification sends notification about application approval/rejection
 func (c *RoleApplicationController) sendApplicationStatusNotification(application *models.RoleApplication, status string) {
 	// This runs in a goroutine, so it doesn't block the response
@@ -743,3 +954,31 @@ func (c *RoleApplicationController) sendApplicationStatusNotification(applicatio
 		}
 	}()
 }
+
+// sendDocumentRejectedNotification asks an applicant to re-upload a rejected document
+func (c *RoleApplicationController) sendDocumentRejectedNotification(applicationID uint, document *models.UserDocument) {
+	go func() {
+		if c.enhancedNotificationService == nil {
+			logrus.Warn("Notification service not available, skipping document rejected notification")
+			return
+		}
+
+		documentName := strings.ReplaceAll(string(document.DocumentType), "_", " ")
+		_, err := c.enhancedNotificationService.SendNotification(&services.NotificationRequest{
+			UserID: document.UserID,
+			Type:   models.NotificationTypeSystem,
+			Title:  "Document Rejected",
+			Body:   fmt.Sprintf("Your %s was rejected: %s. Please upload it again.", documentName, document.RejectionReason),
+			Data: map[string]string{
+				"type":          "role_application_document",
+				"applicationId": fmt.Sprintf("%d", applicationID),
+				"documentType":  string(document.DocumentType),
+				"status":        string(document.VerificationStatus),
+			},
+			Priority: "high",
+		})
+		if err != nil {
+			logrus.Errorf("Failed to send document rejected notification for document %d: %v", document.ID, err)
+		}
+	}()
+}
@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"treesindia/models"
+	"treesindia/services"
+	"treesindia/views"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type SavedSearchController struct {
+	savedSearchService *services.SavedSearchService
+}
+
+func NewSavedSearchController() *SavedSearchController {
+	return &SavedSearchController{
+		savedSearchService: services.NewSavedSearchService(),
+	}
+}
+
+// CreateSavedSearch saves a property search
+// @Summary Save a property search
+// @Description Save a set of property filters to re-run later and be alerted about new matching listings, instantly or in a daily digest
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Param search body models.CreateSavedSearchRequest true "Saved search"
+// @Success 201 {object} views.SuccessResponse{data=models.SavedSearch}
+// @Failure 400 {object} views.ErrorResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Router /api/v1/user/saved-searches [post]
+func (ssc *SavedSearchController) CreateSavedSearch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	var req models.CreateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	search, err := ssc.savedSearchService.CreateSavedSearch(userID.(uint), &req)
+	if err != nil {
+		logrus.Errorf("SavedSearchController.CreateSavedSearch service error: %v", err)
+		status := http.StatusBadRequest
+		if strings.HasPrefix(err.Error(), "failed to") {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, views.CreateErrorResponse("Failed to save search", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, views.CreateSuccessResponse("Search saved successfully", search))
+}
+
+// GetSavedSearches gets the user's saved searches
+// @Summary Get saved searches
+// @Description Get the property searches saved by the authenticated user
+// @Tags saved-searches
+// @Produce json
+// @Success 200 {object} views.SuccessResponse{data=[]models.SavedSearch}
+// @Failure 401 {object} views.ErrorResponse
+// @Router /api/v1/user/saved-searches [get]
+func (ssc *SavedSearchController) GetSavedSearches(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	searches, err := ssc.savedSearchService.GetSavedSearches(userID.(uint))
+	if err != nil {
+		logrus.Errorf("SavedSearchController.GetSavedSearches service error: %v", err)
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to retrieve saved searches", "Internal server error"))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Saved searches retrieved successfully", searches))
+}
+
+// UpdateSavedSearch updates a saved search
+// @Summary Update saved search
+// @Description Update the name, filters or alert settings of a saved search
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Param id path int true "Saved search ID"
+// @Param search body models.UpdateSavedSearchRequest true "Saved search changes"
+// @Success 200 {object} views.SuccessResponse{data=models.SavedSearch}
+// @Failure 400 {object} views.ErrorResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/saved-searches/{id} [put]
+func (ssc *SavedSearchController) UpdateSavedSearch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid saved search ID", "ID must be a valid number"))
+		return
+	}
+
+	var req models.UpdateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	search, err := ssc.savedSearchService.UpdateSavedSearch(uint(id), userID.(uint), &req)
+	if err != nil {
+		logrus.Errorf("SavedSearchController.UpdateSavedSearch service error: %v", err)
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		} else if strings.HasPrefix(err.Error(), "failed to") {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, views.CreateErrorResponse("Failed to update saved search", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Saved search updated successfully", search))
+}
+
+// DeleteSavedSearch deletes a saved search
+// @Summary Delete saved search
+// @Description Delete a saved search and stop its alerts
+// @Tags saved-searches
+// @Produce json
+// @Param id path int true "Saved search ID"
+// @Success 200 {object} views.SuccessResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/saved-searches/{id} [delete]
+func (ssc *SavedSearchController) DeleteSavedSearch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid saved search ID", "ID must be a valid number"))
+		return
+	}
+
+	if err := ssc.savedSearchService.DeleteSavedSearch(uint(id), userID.(uint)); err != nil {
+		logrus.Errorf("SavedSearchController.DeleteSavedSearch service error: %v", err)
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, views.CreateErrorResponse("Failed to delete saved search", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Saved search deleted successfully", nil))
+}
+
+// GetSavedSearchResults runs a saved search
+// @Summary Run saved search
+// @Description Get the current listings matching a saved search
+// @Tags saved-searches
+// @Produce json
+// @Param id path int true "Saved search ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} views.SuccessResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/saved-searches/{id}/results [get]
+func (ssc *SavedSearchController) GetSavedSearchResults(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid saved search ID", "ID must be a valid number"))
+		return
+	}
+
+	params := parseListPagination(c)
+	properties, pagination, err := ssc.savedSearchService.GetSavedSearchResults(uint(id), userID.(uint), params)
+	if err != nil {
+		logrus.Errorf("SavedSearchController.GetSavedSearchResults service error: %v", err)
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, views.CreateErrorResponse("Saved search not found", err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to retrieve properties", "Internal server error"))
+		return
+	}
+
+	paginationView := views.CreatePagination(int(pagination.Page), int(pagination.Limit), pagination.Total)
+	c.JSON(http.StatusOK, views.CreateSuccessResponseWithPagination("Properties retrieved successfully", properties, paginationView))
+}
@@ -1,25 +1,44 @@
 package controllers
 
 import (
+	"errors"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 
+	"treesindia/models"
 	"treesindia/services"
 	"treesindia/views"
 )
 
 type SearchController struct {
-	searchService *services.SearchService
+	searchService          *services.SearchService
+	searchAnalyticsService *services.SearchAnalyticsService
 }
 
 func NewSearchController() *SearchController {
 	return &SearchController{
-		searchService: services.NewSearchService(),
+		searchService:          services.NewSearchService(),
+		searchAnalyticsService: services.NewSearchAnalyticsService(),
 	}
 }
 
+// optionalUserID returns the signed-in user's ID, or nil for guests
+func optionalUserID(c *gin.Context) *uint {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return nil
+	}
+	id, ok := userID.(uint)
+	if !ok || id == 0 {
+		return nil
+	}
+	return &id
+}
+
 // GetSearchSuggestions godoc
 // @Summary Get search suggestions
 // @Description Get 5 popular keywords and 5 popular services for search suggestions
@@ -102,7 +121,7 @@ func (sc *SearchController) SearchServices(c *gin.Context) {
 	logrus.Infof("SearchController.SearchServices called with query: '%s', page: %d, limit: %d", query, page, limit)
 
 	// Perform search
-	searchResponse, err := sc.searchService.SearchServices(query, page, limit)
+	searchResponse, err := sc.searchService.SearchServices(query, page, limit, optionalUserID(c))
 	if err != nil {
 		logrus.Errorf("SearchController.SearchServices error: %v", err)
 		c.JSON(500, views.CreateErrorResponse("Failed to perform search", err.Error()))
@@ -173,7 +192,7 @@ func (sc *SearchController) SearchServicesWithFilters(c *gin.Context) {
 	// In a more advanced implementation, we would parse all the filter parameters
 	// and pass them to a more sophisticated search method
 	
-	searchResponse, err := sc.searchService.SearchServices(query, page, limit)
+	searchResponse, err := sc.searchService.SearchServices(query, page, limit, optionalUserID(c))
 	if err != nil {
 		logrus.Errorf("SearchController.SearchServicesWithFilters error: %v", err)
 		c.JSON(500, views.CreateErrorResponse("Failed to perform advanced search", err.Error()))
@@ -185,3 +204,271 @@ func (sc *SearchController) SearchServicesWithFilters(c *gin.Context) {
 
 	c.JSON(200, views.CreateSuccessResponse("Advanced search completed successfully", searchResponse))
 }
+
+// FederatedSearch godoc
+// @Summary Search services, properties, projects and vendors
+// @Description Full-text search with typo tolerance and synonyms across every listing type, ranked by relevance, with per-type facet counts
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query (e.g., 'ac repair', 'plumbr', '2 bhk flat')"
+// @Param types query string false "Comma-separated types to return (service, property, project, vendor); default all"
+// @Param city query string false "Only return results in this city"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Results per page (default: 20, max: 100)"
+// @Success 200 {object} views.Response{data=models.FederatedSearchResponse} "Search completed successfully"
+// @Failure 400 {object} views.Response "Bad request"
+// @Failure 500 {object} views.Response "Internal server error"
+// @Router /api/v1/search [get]
+func (sc *SearchController) FederatedSearch(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("SearchController.FederatedSearch panic: %v", r)
+		}
+	}()
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(400, views.CreateErrorResponse("Query parameter 'q' is required", "Please provide a search query"))
+		return
+	}
+
+	var types []models.SearchEntityType
+	if typesParam := c.Query("types"); typesParam != "" {
+		for _, t := range strings.Split(typesParam, ",") {
+			entityType := models.SearchEntityType(strings.TrimSpace(t))
+			if !slices.Contains(models.SearchEntityTypes, entityType) {
+				c.JSON(400, views.CreateErrorResponse("Invalid search type", "Types must be service, property, project or vendor"))
+				return
+			}
+			types = append(types, entityType)
+		}
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	filters := &models.FederatedSearchFilters{
+		Query: query,
+		Types: types,
+		City:  c.Query("city"),
+		Page:  page,
+		Limit: limit,
+	}
+
+	searchResponse, err := sc.searchService.FederatedSearch(filters, optionalUserID(c))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSearch) {
+			c.JSON(400, views.CreateErrorResponse("Invalid search", err.Error()))
+			return
+		}
+		logrus.Errorf("SearchController.FederatedSearch error: %v", err)
+		c.JSON(500, views.CreateErrorResponse("Failed to perform search", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Search completed successfully", searchResponse))
+}
+
+// GetSynonyms gets search synonyms (admin only)
+// @Summary Get search synonyms
+// @Description Admin lists the synonyms used to expand search queries
+// @Tags Search
+// @Produce json
+// @Param search query string false "Search by term"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} views.Response
+// @Router /api/v1/admin/search/synonyms [get]
+func (sc *SearchController) GetSynonyms(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("SearchController.GetSynonyms panic: %v", r)
+		}
+	}()
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	synonyms, pagination, err := sc.searchService.GetSynonyms(c.Query("search"), page, limit)
+	if err != nil {
+		logrus.Errorf("SearchController.GetSynonyms service error: %v", err)
+		c.JSON(500, views.CreateErrorResponse("Failed to get search synonyms", err.Error()))
+		return
+	}
+
+	response := map[string]interface{}{
+		"synonyms":   synonyms,
+		"pagination": pagination,
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Search synonyms retrieved successfully", response))
+}
+
+// CreateSynonym creates a search synonym (admin only)
+// @Summary Create search synonym
+// @Description Admin adds equivalent phrases for a search term (e.g. "ac" -> "air conditioner")
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Param request body models.CreateSearchSynonymRequest true "Synonym details"
+// @Success 201 {object} views.Response{data=models.SearchSynonym}
+// @Router /api/v1/admin/search/synonyms [post]
+func (sc *SearchController) CreateSynonym(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("SearchController.CreateSynonym panic: %v", r)
+		}
+	}()
+
+	var req models.CreateSearchSynonymRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	synonym, err := sc.searchService.CreateSynonym(&req)
+	if err != nil {
+		logrus.Errorf("SearchController.CreateSynonym service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to create search synonym", err.Error()))
+		return
+	}
+
+	c.JSON(201, views.CreateSuccessResponse("Search synonym created successfully", synonym))
+}
+
+// UpdateSynonym updates a search synonym (admin only)
+// @Summary Update search synonym
+// @Description Admin replaces a term's synonyms or toggles it
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Param id path integer true "Synonym ID"
+// @Param request body models.UpdateSearchSynonymRequest true "Synonym changes"
+// @Success 200 {object} views.Response{data=models.SearchSynonym}
+// @Router /api/v1/admin/search/synonyms/{id} [put]
+func (sc *SearchController) UpdateSynonym(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("SearchController.UpdateSynonym panic: %v", r)
+		}
+	}()
+
+	synonymID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid synonym ID", err.Error()))
+		return
+	}
+
+	var req models.UpdateSearchSynonymRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	synonym, err := sc.searchService.UpdateSynonym(uint(synonymID), &req)
+	if err != nil {
+		logrus.Errorf("SearchController.UpdateSynonym service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to update search synonym", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Search synonym updated successfully", synonym))
+}
+
+// DeleteSynonym deletes a search synonym (admin only)
+// @Summary Delete search synonym
+// @Description Admin removes a term's synonyms
+// @Tags Search
+// @Produce json
+// @Param id path integer true "Synonym ID"
+// @Success 200 {object} views.Response
+// @Router /api/v1/admin/search/synonyms/{id} [delete]
+func (sc *SearchController) DeleteSynonym(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("SearchController.DeleteSynonym panic: %v", r)
+		}
+	}()
+
+	synonymID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid synonym ID", err.Error()))
+		return
+	}
+
+	if err := sc.searchService.DeleteSynonym(uint(synonymID)); err != nil {
+		logrus.Errorf("SearchController.DeleteSynonym service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to delete search synonym", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Search synonym deleted successfully", nil))
+}
+
+// RecordSearchClick records the result a user opened from a search
+// @Summary Record search result click
+// @Description Records which result was opened from a search so click-through and bookings can be attributed to the query. Use the search_id and search_token from the search metadata.
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Param id path integer true "Search ID"
+// @Param request body models.RecordSearchClickRequest true "Opened result"
+// @Success 200 {object} views.Response
+// @Router /api/v1/search/{id}/click [post]
+func (sc *SearchController) RecordSearchClick(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("SearchController.RecordSearchClick panic: %v", r)
+		}
+	}()
+
+	searchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid search ID", err.Error()))
+		return
+	}
+
+	var req models.RecordSearchClickRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	if err := sc.searchAnalyticsService.RecordClick(uint(searchID), optionalUserID(c), &req); err != nil {
+		logrus.Errorf("SearchController.RecordSearchClick service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to record search click", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Search click recorded successfully", nil))
+}
+
+// GetSearchAnalytics gets the search analytics report (admin only)
+// @Summary Get search analytics
+// @Description Admin report of top queries, zero-result queries, click-through and conversion to booking
+// @Tags Search
+// @Produce json
+// @Param days query int false "Report period in days (default: 30)"
+// @Param limit query int false "Queries per list (default: 20, max: 100)"
+// @Success 200 {object} views.Response{data=models.SearchAnalyticsReport}
+// @Router /api/v1/admin/search/analytics [get]
+func (sc *SearchController) GetSearchAnalytics(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("SearchController.GetSearchAnalytics panic: %v", r)
+		}
+	}()
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	report, err := sc.searchAnalyticsService.GetReport(days, limit)
+	if err != nil {
+		logrus.Errorf("SearchController.GetSearchAnalytics service error: %v", err)
+		c.JSON(500, views.CreateErrorResponse("Failed to get search analytics", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Search analytics retrieved successfully", report))
+}
@@ -112,10 +112,10 @@ func (sac *ServiceAreaController) GetAllServiceAreas(c *gin.Context) {
 // @Description Get all service areas for a specific service
 // @Tags service-areas
 // @Produce json
-// @Param service_id path integer true "Service ID"
+// @Param id path integer true "Service ID"
 // @Success 200 {object} views.Response{data=[]models.ServiceArea}
 // @Failure 400 {object} views.Response
-// @Router /api/v1/admin/services/{service_id}/service-areas [get]
+// @Router /api/v1/admin/services/{id}/service-areas [get]
 func (sac *ServiceAreaController) GetServiceAreasByServiceID(c *gin.Context) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -123,7 +123,7 @@ func (sac *ServiceAreaController) GetServiceAreasByServiceID(c *gin.Context) {
 		}
 	}()
 
-	serviceIDStr := c.Param("service_id")
+	serviceIDStr := c.Param("id")
 	serviceID, err := strconv.ParseUint(serviceIDStr, 10, 32)
 	if err != nil {
 		c.JSON(400, views.CreateErrorResponse("Invalid service ID", err.Error()))
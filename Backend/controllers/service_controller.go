@@ -27,16 +27,16 @@ func NewServiceController() *ServiceController {
 	serviceRepo := repositories.NewServiceRepository()
 	logrus.Info("ServiceRepository initialized")
 	
-	cloudinaryService, err := services.NewCloudinaryService()
+	mediaStore, err := services.NewMediaStore()
 	if err != nil {
-		logrus.Errorf("Failed to initialize CloudinaryService: %v", err)
-		// Don't panic, create a nil cloudinary service
-		cloudinaryService = nil
+		logrus.Errorf("Failed to initialize media store: %v", err)
+		// Don't panic, leave the media store nil
+		mediaStore = nil
 	} else {
-		logrus.Info("CloudinaryService initialized successfully")
+		logrus.Info("Media store initialized successfully")
 	}
 	
-	serviceService := services.NewServiceService(serviceRepo, cloudinaryService)
+	serviceService := services.NewServiceService(serviceRepo, mediaStore)
 	logrus.Info("ServiceService initialized")
 	
 	// Ensure services table exists
@@ -67,6 +67,7 @@ func NewServiceController() *ServiceController {
 // @Param price_type formData string true "Price type (fixed or inquiry)"
 // @Param price formData number false "Fixed price (required if price_type is fixed)"
 // @Param duration formData string false "Service duration"
+// @Param max_quantity formData integer false "Units a customer can book at once (default: 1)"
 // @Param category_id formData integer true "Category ID"
 // @Param is_active formData boolean false "Is active (default: true)"
 // @Param images formData file false "Service images"
@@ -132,6 +133,12 @@ func (sc *ServiceController) CreateService(c *gin.Context) {
 				}
 			}
 			
+			if maxQuantityStr := c.PostForm("max_quantity"); maxQuantityStr != "" {
+				if maxQuantity, err := strconv.Atoi(maxQuantityStr); err == nil {
+					req.MaxQuantity = maxQuantity
+				}
+			}
+			
 			if isActiveStr := c.PostForm("is_active"); isActiveStr != "" {
 				if isActive, err := strconv.ParseBool(isActiveStr); err == nil {
 					req.IsActive = &isActive
@@ -539,6 +546,7 @@ func (sc *ServiceController) GetServicesByLocation(c *gin.Context) {
 // @Param price_type formData string false "Price type (fixed or inquiry)"
 // @Param price formData number false "Fixed price"
 // @Param duration formData string false "Service duration"
+// @Param max_quantity formData integer false "Units a customer can book at once (default: 1)"
 // @Param is_active formData boolean false "Is active"
 // @Param images formData file false "Additional service images"
 // @Success 200 {object} views.Response{data=models.Service}
@@ -572,6 +580,12 @@ func (sc *ServiceController) UpdateService(c *gin.Context) {
 			}
 		}
 		
+		if maxQuantityStr := c.PostForm("max_quantity"); maxQuantityStr != "" {
+			if maxQuantity, err := strconv.Atoi(maxQuantityStr); err == nil {
+				req.MaxQuantity = &maxQuantity
+			}
+		}
+		
 		if isActiveStr := c.PostForm("is_active"); isActiveStr != "" {
 			if isActive, err := strconv.ParseBool(isActiveStr); err == nil {
 				req.IsActive = &isActive
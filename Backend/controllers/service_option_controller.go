@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"strconv"
+	"treesindia/models"
+	"treesindia/services"
+	"treesindia/views"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ServiceOptionController struct {
+	BaseController
+	serviceOptionService *services.ServiceOptionService
+}
+
+func NewServiceOptionController() *ServiceOptionController {
+	return &ServiceOptionController{
+		BaseController:       *NewBaseController(),
+		serviceOptionService: services.NewServiceOptionService(),
+	}
+}
+
+// CalculatePrice prices a service with a variant, quantity and add-ons
+// @Summary Calculate service price
+// @Description Customer previews the price and duration of a service with the chosen variant, quantity and add-ons
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param id path integer true "Service ID"
+// @Param request body models.ServiceSelection true "Chosen variant, quantity and add-ons"
+// @Success 200 {object} views.Response{data=models.ServicePriceBreakdown}
+// @Failure 400 {object} views.Response
+// @Router /api/v1/services/{id}/price [post]
+func (soc *ServiceOptionController) CalculatePrice(c *gin.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("ServiceOptionController.CalculatePrice panic: %v", r)
+		}
+	}()
+
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid service ID", err.Error()))
+		return
+	}
+
+	var req models.ServiceSelection
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	breakdown, err := soc.serviceOptionService.CalculatePrice(uint(serviceID), &req)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Failed to calculate price", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Price calculated successfully", breakdown))
+}
+
+// GetVariants gets the variants of a service (admin only)
+// @Summary Get service variants
+// @Description Admin lists the variants of a fixed price service, including inactive ones
+// @Tags services
+// @Produce json
+// @Param id path integer true "Service ID"
+// @Success 200 {object} views.Response{data=[]models.ServiceVariant}
+// @Router /api/v1/admin/services/{id}/variants [get]
+func (soc *ServiceOptionController) GetVariants(c *gin.Context) {
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid service ID", err.Error()))
+		return
+	}
+
+	variants, err := soc.serviceOptionService.GetVariants(uint(serviceID), false)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Failed to get service variants", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Service variants retrieved successfully", variants))
+}
+
+// CreateVariant adds a variant to a service (admin only)
+// @Summary Create service variant
+// @Description Admin adds a priced variant, e.g. "2 BHK", to a fixed price service
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param id path integer true "Service ID"
+// @Param request body models.CreateServiceVariantRequest true "Variant details"
+// @Success 201 {object} views.Response{data=models.ServiceVariant}
+// @Router /api/v1/admin/services/{id}/variants [post]
+func (soc *ServiceOptionController) CreateVariant(c *gin.Context) {
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid service ID", err.Error()))
+		return
+	}
+
+	var req models.CreateServiceVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	variant, err := soc.serviceOptionService.CreateVariant(uint(serviceID), &req)
+	if err != nil {
+		logrus.Errorf("ServiceOptionController.CreateVariant service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to create service variant", err.Error()))
+		return
+	}
+
+	c.JSON(201, views.CreateSuccessResponse("Service variant created successfully", variant))
+}
+
+// UpdateVariant updates a variant of a service (admin only)
+// @Summary Update service variant
+// @Description Admin updates the price, duration or availability of a service variant
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param id path integer true "Service ID"
+// @Param variantId path integer true "Variant ID"
+// @Param request body models.UpdateServiceVariantRequest true "Variant changes"
+// @Success 200 {object} views.Response{data=models.ServiceVariant}
+// @Router /api/v1/admin/services/{id}/variants/{variantId} [put]
+func (soc *ServiceOptionController) UpdateVariant(c *gin.Context) {
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid service ID", err.Error()))
+		return
+	}
+	variantID, err := strconv.ParseUint(c.Param("variantId"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid variant ID", err.Error()))
+		return
+	}
+
+	var req models.UpdateServiceVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	variant, err := soc.serviceOptionService.UpdateVariant(uint(serviceID), uint(variantID), &req)
+	if err != nil {
+		logrus.Errorf("ServiceOptionController.UpdateVariant service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to update service variant", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Service variant updated successfully", variant))
+}
+
+// DeleteVariant deletes a variant of a service (admin only)
+// @Summary Delete service variant
+// @Description Admin deletes a service variant; existing bookings keep their price
+// @Tags services
+// @Produce json
+// @Param id path integer true "Service ID"
+// @Param variantId path integer true "Variant ID"
+// @Success 200 {object} views.Response
+// @Router /api/v1/admin/services/{id}/variants/{variantId} [delete]
+func (soc *ServiceOptionController) DeleteVariant(c *gin.Context) {
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid service ID", err.Error()))
+		return
+	}
+	variantID, err := strconv.ParseUint(c.Param("variantId"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid variant ID", err.Error()))
+		return
+	}
+
+	if err := soc.serviceOptionService.DeleteVariant(uint(serviceID), uint(variantID)); err != nil {
+		c.JSON(404, views.CreateErrorResponse("Failed to delete service variant", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Service variant deleted successfully", nil))
+}
+
+// GetAddOns gets the add-ons of a service (admin only)
+// @Summary Get service add-ons
+// @Description Admin lists the add-ons of a fixed price service, including inactive ones
+// @Tags services
+// @Produce json
+// @Param id path integer true "Service ID"
+// @Success 200 {object} views.Response{data=[]models.ServiceAddOn}
+// @Router /api/v1/admin/services/{id}/add-ons [get]
+func (soc *ServiceOptionController) GetAddOns(c *gin.Context) {
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid service ID", err.Error()))
+		return
+	}
+
+	addOns, err := soc.serviceOptionService.GetAddOns(uint(serviceID), false)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Failed to get service add-ons", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Service add-ons retrieved successfully", addOns))
+}
+
+// CreateAddOn adds an add-on to a service (admin only)
+// @Summary Create service add-on
+// @Description Admin adds an optional extra, e.g. "Gas refill", to a fixed price service
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param id path integer true "Service ID"
+// @Param request body models.CreateServiceAddOnRequest true "Add-on details"
+// @Success 201 {object} views.Response{data=models.ServiceAddOn}
+// @Router /api/v1/admin/services/{id}/add-ons [post]
+func (soc *ServiceOptionController) CreateAddOn(c *gin.Context) {
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid service ID", err.Error()))
+		return
+	}
+
+	var req models.CreateServiceAddOnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	addOn, err := soc.serviceOptionService.CreateAddOn(uint(serviceID), &req)
+	if err != nil {
+		logrus.Errorf("ServiceOptionController.CreateAddOn service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to create service add-on", err.Error()))
+		return
+	}
+
+	c.JSON(201, views.CreateSuccessResponse("Service add-on created successfully", addOn))
+}
+
+// UpdateAddOn updates an add-on of a service (admin only)
+// @Summary Update service add-on
+// @Description Admin updates the price, duration or availability of a service add-on
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param id path integer true "Service ID"
+// @Param addOnId path integer true "Add-on ID"
+// @Param request body models.UpdateServiceAddOnRequest true "Add-on changes"
+// @Success 200 {object} views.Response{data=models.ServiceAddOn}
+// @Router /api/v1/admin/services/{id}/add-ons/{addOnId} [put]
+func (soc *ServiceOptionController) UpdateAddOn(c *gin.Context) {
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid service ID", err.Error()))
+		return
+	}
+	addOnID, err := strconv.ParseUint(c.Param("addOnId"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid add-on ID", err.Error()))
+		return
+	}
+
+	var req models.UpdateServiceAddOnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	addOn, err := soc.serviceOptionService.UpdateAddOn(uint(serviceID), uint(addOnID), &req)
+	if err != nil {
+		logrus.Errorf("ServiceOptionController.UpdateAddOn service error: %v", err)
+		c.JSON(400, views.CreateErrorResponse("Failed to update service add-on", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Service add-on updated successfully", addOn))
+}
+
+// DeleteAddOn deletes an add-on of a service (admin only)
+// @Summary Delete service add-on
+// @Description Admin deletes a service add-on; existing bookings keep their price
+// @Tags services
+// @Produce json
+// @Param id path integer true "Service ID"
+// @Param addOnId path integer true "Add-on ID"
+// @Success 200 {object} views.Response
+// @Router /api/v1/admin/services/{id}/add-ons/{addOnId} [delete]
+func (soc *ServiceOptionController) DeleteAddOn(c *gin.Context) {
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid service ID", err.Error()))
+		return
+	}
+	addOnID, err := strconv.ParseUint(c.Param("addOnId"), 10, 32)
+	if err != nil {
+		c.JSON(400, views.CreateErrorResponse("Invalid add-on ID", err.Error()))
+		return
+	}
+
+	if err := soc.serviceOptionService.DeleteAddOn(uint(serviceID), uint(addOnID)); err != nil {
+		c.JSON(404, views.CreateErrorResponse("Failed to delete service add-on", err.Error()))
+		return
+	}
+
+	c.JSON(200, views.CreateSuccessResponse("Service add-on deleted successfully", nil))
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"treesindia/models"
+	"treesindia/services"
+	"treesindia/views"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ShortlistController struct {
+	shortlistService *services.ShortlistService
+}
+
+func NewShortlistController() *ShortlistController {
+	return &ShortlistController{
+		shortlistService: services.NewShortlistService(),
+	}
+}
+
+// AddToShortlist shortlists a property or project
+// @Summary Add to shortlist
+// @Description Shortlist a property or project; shortlisting an item twice has no effect
+// @Tags shortlist
+// @Accept json
+// @Produce json
+// @Param item body models.AddToShortlistRequest true "Item to shortlist"
+// @Success 201 {object} views.SuccessResponse{data=models.Shortlist}
+// @Failure 400 {object} views.ErrorResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/shortlist [post]
+func (sc *ShortlistController) AddToShortlist(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	var req models.AddToShortlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	item, err := sc.shortlistService.AddToShortlist(userID.(uint), &req)
+	if err != nil {
+		logrus.Errorf("ShortlistController.AddToShortlist service error: %v", err)
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		} else if strings.HasPrefix(err.Error(), "failed to") {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, views.CreateErrorResponse("Failed to add to shortlist", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, views.CreateSuccessResponse("Added to shortlist successfully", item))
+}
+
+// RemoveFromShortlist removes a property or project from the shortlist
+// @Summary Remove from shortlist
+// @Description Remove a shortlisted property or project
+// @Tags shortlist
+// @Produce json
+// @Param item_type path string true "Item type (property, project)"
+// @Param item_id path int true "Property or project ID"
+// @Success 200 {object} views.SuccessResponse
+// @Failure 400 {object} views.ErrorResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Failure 404 {object} views.ErrorResponse
+// @Router /api/v1/user/shortlist/{item_type}/{item_id} [delete]
+func (sc *ShortlistController) RemoveFromShortlist(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	itemType := models.ShortlistItemType(c.Param("item_type"))
+	if itemType != models.ShortlistItemTypeProperty && itemType != models.ShortlistItemTypeProject {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid item type", "Item type must be property or project"))
+		return
+	}
+
+	itemID, err := strconv.ParseUint(c.Param("item_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid item ID", "ID must be a valid number"))
+		return
+	}
+
+	if err := sc.shortlistService.RemoveFromShortlist(userID.(uint), itemType, uint(itemID)); err != nil {
+		logrus.Errorf("ShortlistController.RemoveFromShortlist service error: %v", err)
+		status := http.StatusInternalServerError
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, views.CreateErrorResponse("Failed to remove from shortlist", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Removed from shortlist successfully", nil))
+}
+
+// GetShortlist gets the user's shortlisted properties and projects
+// @Summary Get shortlist
+// @Description Get the authenticated user's shortlisted properties and projects, most recently added first
+// @Tags shortlist
+// @Produce json
+// @Param item_type query string false "Item type (property, project)"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} views.SuccessResponse
+// @Failure 401 {object} views.ErrorResponse
+// @Router /api/v1/user/shortlist [get]
+func (sc *ShortlistController) GetShortlist(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	params := parseListPagination(c)
+	items, pagination, err := sc.shortlistService.GetShortlist(userID.(uint), c.Query("item_type"), params)
+	if err != nil {
+		logrus.Errorf("ShortlistController.GetShortlist service error: %v", err)
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to retrieve shortlist", "Internal server error"))
+		return
+	}
+
+	paginationView := views.CreatePagination(int(pagination.Page), int(pagination.Limit), pagination.Total)
+	c.JSON(http.StatusOK, views.CreateSuccessResponseWithPagination("Shortlist retrieved successfully", items, paginationView))
+}
@@ -251,29 +251,29 @@ func (sc *SubcategoryController) CreateSubcategory(c *gin.Context) {
 			return
 		}
 		
-		// Check if Cloudinary service is available (use CategoryService)
+		// Check if media store is available (use CategoryService)
 		categoryService, err := services.NewCategoryService()
 		if err != nil {
-			logrus.Error("Cloudinary service is not available")
-			c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Cloudinary service unavailable", "Image upload service is not configured. Please contact administrator."))
+			logrus.Error("media store is not available")
+			c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Media store unavailable", "Image upload service is not configured. Please contact administrator."))
 			return
 		}
-		cloudinaryService := categoryService.GetCloudinaryService()
-		if cloudinaryService == nil {
-			logrus.Error("Cloudinary service is not available")
-			c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Cloudinary service unavailable", "Image upload service is not configured. Please contact administrator."))
+		mediaStore := categoryService.GetMediaStore()
+		if mediaStore == nil {
+			logrus.Error("media store is not available")
+			c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Media store unavailable", "Image upload service is not configured. Please contact administrator."))
 			return
 		}
 		
-		// Upload image to Cloudinary using the service's CloudinaryService
-		imageURL, err := cloudinaryService.UploadImage(file, "subcategories")
+		// Upload image to media storage using the service's CloudinaryService
+		imageURL, err := mediaStore.UploadImage(file, "subcategories")
 		if err != nil {
-			logrus.Error("Failed to upload image to Cloudinary:", err)
+			logrus.Error("Failed to upload image to media storage:", err)
 			c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to upload image", "Failed to upload image to cloud storage. Please try again."))
 			return
 		}
 		
-		logrus.Info("Image uploaded successfully to Cloudinary:", imageURL)
+		logrus.Info("Image uploaded successfully to media storage:", imageURL)
 		
 		// Set the request struct manually
 		req = CreateSubcategoryRequest{
@@ -446,29 +446,29 @@ func (sc *SubcategoryController) UpdateSubcategory(c *gin.Context) {
 				return
 			}
 			
-			// Check if Cloudinary service is available
+			// Check if media store is available
 			categoryService, err := services.NewCategoryService()
 		if err != nil {
-			logrus.Error("Cloudinary service is not available")
-			c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Cloudinary service unavailable", "Image upload service is not configured. Please contact administrator."))
+			logrus.Error("media store is not available")
+			c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Media store unavailable", "Image upload service is not configured. Please contact administrator."))
 			return
 		}
-		cloudinaryService := categoryService.GetCloudinaryService()
-			if cloudinaryService == nil {
-				logrus.Error("Cloudinary service is not available")
-				c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Cloudinary service unavailable", "Image upload service is not configured. Please contact administrator."))
+		mediaStore := categoryService.GetMediaStore()
+			if mediaStore == nil {
+				logrus.Error("media store is not available")
+				c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Media store unavailable", "Image upload service is not configured. Please contact administrator."))
 				return
 			}
 			
-			// Upload image to Cloudinary
-			uploadedURL, err := cloudinaryService.UploadImage(imageFile, "subcategories")
+			// Upload image to media storage
+			uploadedURL, err := mediaStore.UploadImage(imageFile, "subcategories")
 			if err != nil {
-				logrus.Error("Failed to upload image to Cloudinary:", err)
+				logrus.Error("Failed to upload image to media storage:", err)
 				c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to upload image", "Failed to upload image to cloud storage. Please try again."))
 				return
 			}
 			
-			logrus.Info("Image uploaded successfully to Cloudinary:", uploadedURL)
+			logrus.Info("Image uploaded successfully to media storage:", uploadedURL)
 			imageURL = uploadedURL
 		}
 		
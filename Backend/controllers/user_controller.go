@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 	"treesindia/database"
 	"treesindia/models"
@@ -17,23 +18,25 @@ import (
 type UserController struct {
 	db                *gorm.DB
 	validationHelper  *utils.ValidationHelper
-	cloudinaryService *services.CloudinaryService
+	mediaStore services.MediaStore
 	otpService       *services.OTPService
+	accountDataService *services.AccountDataService
 }
 
 // NewUserController creates a new user controller
 func NewUserController() *UserController {
-	cloudinaryService, err := services.NewCloudinaryService()
+	mediaStore, err := services.NewMediaStore()
 	if err != nil {
-		// Log error but continue - Cloudinary is optional for basic functionality
-		cloudinaryService = nil
+		// Log error but continue - the media store is optional for basic functionality
+		mediaStore = nil
 	}
 
 	return &UserController{
 		db:                database.GetDB(),
 		validationHelper:  utils.NewValidationHelper(),
-		cloudinaryService: cloudinaryService,
+		mediaStore: mediaStore,
 		otpService:       services.NewOTPService(),
+		accountDataService: services.NewAccountDataService(mediaStore),
 	}
 }
 
@@ -227,8 +230,8 @@ func (uc *UserController) UpdateUserProfile(c *gin.Context) {
 func (uc *UserController) UploadAvatar(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
-	// Check if Cloudinary service is available
-	if uc.cloudinaryService == nil {
+	// Check if media store is available
+	if uc.mediaStore == nil {
 		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("File upload service unavailable", "Avatar upload is currently disabled"))
 		return
 	}
@@ -264,8 +267,8 @@ func (uc *UserController) UploadAvatar(c *gin.Context) {
 		return
 	}
 
-	// Upload to Cloudinary
-	avatarURL, err := uc.cloudinaryService.UploadImage(file, "avatars")
+	// Upload to media storage
+	avatarURL, err := uc.mediaStore.UploadImage(file, "avatars")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Upload failed", "Failed to upload image"))
 		return
@@ -448,14 +451,16 @@ func (uc *UserController) RequestDeleteOTP(c *gin.Context) {
 
 // DeleteAccount godoc
 // @Summary Delete user account
-// @Description Permanently delete the authenticated user's account
+// @Description Schedule the deletion of the authenticated user's account. After the grace period their personal data is anonymised; booking and payment records are kept without it.
 // @Tags Users
+// @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} models.Response "Account deleted successfully"
+// @Param request body models.DeleteAccountRequest false "Deletion reason"
+// @Success 200 {object} models.Response "Account deletion scheduled"
+// @Failure 400 {object} models.Response "Account cannot be deleted yet"
 // @Failure 401 {object} models.Response "Unauthorized"
 // @Failure 404 {object} models.Response "User not found"
-// @Failure 500 {object} models.Response "Internal server error"
 // @Router /users/account [delete]
 func (uc *UserController) DeleteAccount(c *gin.Context) {
 	userID := c.GetUint("user_id")
@@ -471,233 +476,147 @@ func (uc *UserController) DeleteAccount(c *gin.Context) {
 		return
 	}
 
-	// Start a transaction to ensure all deletions are atomic
-	tx := uc.db.Begin()
-	if tx.Error != nil {
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to start transaction", tx.Error.Error()))
-		return
-	}
-
-	// Delete all related data in the correct order to avoid foreign key constraint violations
-
-	// 1. Delete chat messages where user is the sender
-	if err := tx.Unscoped().Where("sender_id = ?", userID).Delete(&models.ChatMessage{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete chat messages", err.Error()))
-		return
-	}
-
-	// 2. Delete chat rooms associated with user's bookings, properties, or worker inquiries
-	// First, get all bookings, properties, and worker inquiries for this user
-	var bookingIDs []uint
-	if err := tx.Model(&models.Booking{}).Where("user_id = ?", userID).Pluck("id", &bookingIDs).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to get user bookings", err.Error()))
-		return
-	}
-
-	var propertyIDs []uint
-	if err := tx.Model(&models.Property{}).Where("user_id = ?", userID).Pluck("id", &propertyIDs).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to get user properties", err.Error()))
-		return
-	}
-
-	var workerInquiryIDs []uint
-	if err := tx.Model(&models.WorkerInquiry{}).Where("user_id = ?", userID).Pluck("id", &workerInquiryIDs).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to get user worker inquiries", err.Error()))
-		return
-	}
-
-	// Delete chat rooms associated with these entities
-	if len(bookingIDs) > 0 {
-		if err := tx.Unscoped().Where("booking_id IN ?", bookingIDs).Delete(&models.ChatRoom{}).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete chat rooms for bookings", err.Error()))
-			return
-		}
-	}
-
-	if len(propertyIDs) > 0 {
-		if err := tx.Unscoped().Where("property_id IN ?", propertyIDs).Delete(&models.ChatRoom{}).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete chat rooms for properties", err.Error()))
-			return
-		}
-	}
-
-	if len(workerInquiryIDs) > 0 {
-		if err := tx.Unscoped().Where("worker_inquiry_id IN ?", workerInquiryIDs).Delete(&models.ChatRoom{}).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete chat rooms for worker inquiries", err.Error()))
-			return
-		}
-	}
-
-	// 3. Delete worker assignments for user's bookings
-	if len(bookingIDs) > 0 {
-		if err := tx.Unscoped().Where("booking_id IN ?", bookingIDs).Delete(&models.WorkerAssignment{}).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete worker assignments", err.Error()))
+	// The reason is optional, so an empty body is fine
+	var req models.DeleteAccountRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid request data", err.Error()))
 			return
 		}
 	}
 
-	// 4. Delete buffer requests for user's bookings
-	if len(bookingIDs) > 0 {
-		if err := tx.Unscoped().Where("booking_id IN ?", bookingIDs).Delete(&models.BufferRequest{}).Error; err != nil {
-			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete buffer requests", err.Error()))
-			return
-		}
-	}
-
-	// Delete chat room participants where user is a participant
-	if err := tx.Exec("DELETE FROM chat_room_participants WHERE user_id = ?", userID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete chat room participants", err.Error()))
-		return
-	}
-
-	// 5. Delete wallet transactions
-	if err := tx.Exec("DELETE FROM wallet_transactions WHERE user_id = ?", userID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete wallet transactions", err.Error()))
-		return
-	}
-
-	// 6. Delete payments
-	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.Payment{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete payments", err.Error()))
+	request, err := uc.accountDataService.ScheduleDeletion(user.ID, req.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Failed to delete account", err.Error()))
 		return
 	}
 
-	// 6. Delete bookings (payment_segments will be cascade deleted)
-	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.Booking{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete bookings", err.Error()))
+	if request.Status == models.AccountDeletionStatusCompleted {
+		c.JSON(http.StatusOK, views.CreateSuccessResponse("Account deleted successfully", gin.H{
+			"message": "Your personal data has been deleted",
+		}))
 		return
 	}
 
-	// 7. Delete worker inquiries
-	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.WorkerInquiry{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete worker inquiries", err.Error()))
-		return
-	}
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Account deletion scheduled", gin.H{
+		"message":       "Your account will be deleted on the scheduled date. You can cancel until then.",
+		"deletion":      request,
+		"scheduled_for": request.ScheduledFor,
+	}))
+}
 
-	// 8. Delete properties
-	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.Property{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete properties", err.Error()))
-		return
-	}
+// GetAccountDeletion godoc
+// @Summary Get scheduled account deletion
+// @Description Get the authenticated user's scheduled account deletion, if any
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.Response "Account deletion status"
+// @Router /users/account/deletion [get]
+func (uc *UserController) GetAccountDeletion(c *gin.Context) {
+	userID := c.GetUint("user_id")
 
-	// 9. Delete addresses
-	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.Address{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete addresses", err.Error()))
+	request, err := uc.accountDataService.GetScheduledDeletion(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to get account deletion", err.Error()))
 		return
 	}
 
-	// Note: user_documents and user_skills tables don't exist in migrations, skipping their deletion
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Account deletion status retrieved successfully", gin.H{
+		"scheduled": request != nil,
+		"deletion":  request,
+	}))
+}
 
-	// 10. Delete subscription warnings
-	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.SubscriptionWarning{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete subscription warnings", err.Error()))
-		return
-	}
+// CancelAccountDeletion godoc
+// @Summary Cancel account deletion
+// @Description Cancel the authenticated user's scheduled account deletion during the grace period
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.Response "Account deletion cancelled"
+// @Failure 400 {object} models.Response "No deletion scheduled"
+// @Router /users/account/deletion/cancel [post]
+func (uc *UserController) CancelAccountDeletion(c *gin.Context) {
+	userID := c.GetUint("user_id")
 
-	// 11. Delete user subscriptions
-	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.UserSubscription{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete user subscriptions", err.Error()))
+	request, err := uc.accountDataService.CancelDeletion(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Failed to cancel account deletion", err.Error()))
 		return
 	}
 
-	// 12. Delete user notification settings
-	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.UserNotificationSettings{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete notification settings", err.Error()))
-		return
-	}
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Account deletion cancelled", request))
+}
 
-	// 13. Delete role applications
-	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.RoleApplication{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete role applications", err.Error()))
-		return
-	}
+// RequestDataExport godoc
+// @Summary Request data export
+// @Description Start preparing an archive of the authenticated user's profile, addresses, bookings, payments, wallet history, chats and properties
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} models.Response "Data export requested"
+// @Failure 400 {object} models.Response "An export is already being prepared"
+// @Router /users/data-exports [post]
+func (uc *UserController) RequestDataExport(c *gin.Context) {
+	userID := c.GetUint("user_id")
 
-	// 14. Delete locations
-	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.Location{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete locations", err.Error()))
+	export, err := uc.accountDataService.RequestExport(userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Failed to request data export", err.Error()))
 		return
 	}
 
-	// 15. Delete worker record if exists
-	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.Worker{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete worker record", err.Error()))
-		return
-	}
+	c.JSON(http.StatusAccepted, views.CreateSuccessResponse("Data export requested", export))
+}
 
-	// 16. Delete simple conversation messages where user is the sender
-	if err := tx.Unscoped().Where("sender_id = ?", userID).Delete(&models.SimpleConversationMessage{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete conversation messages", err.Error()))
-		return
-	}
+// GetDataExports godoc
+// @Summary Get data exports
+// @Description Get the authenticated user's recent data exports
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.Response "Data exports"
+// @Router /users/data-exports [get]
+func (uc *UserController) GetDataExports(c *gin.Context) {
+	userID := c.GetUint("user_id")
 
-	// 17. Delete simple conversations where user is participant
-	if err := tx.Unscoped().Where("user_1 = ? OR user_2 = ? OR last_message_sender_id = ?", userID, userID, userID).Delete(&models.SimpleConversation{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete conversations", err.Error()))
+	exports, err := uc.accountDataService.GetExports(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to get data exports", err.Error()))
 		return
 	}
 
-	// 18. Delete broker record if exists
-	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.Broker{}).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete broker record", err.Error()))
-		return
-	}
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Data exports retrieved successfully", exports))
+}
 
-	// 19. Update ledger entries (set foreign key to NULL instead of deleting)
-	if err := tx.Exec("UPDATE ledger_entries SET created_by = NULL WHERE created_by = ?", userID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to update ledger entries (created_by)", err.Error()))
-		return
-	}
-	if err := tx.Exec("UPDATE ledger_entries SET updated_by = NULL WHERE updated_by = ?", userID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to update ledger entries (updated_by)", err.Error()))
-		return
-	}
-	if err := tx.Exec("UPDATE cash_bank_balances SET last_updated_by = NULL WHERE last_updated_by = ?", userID).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to update cash bank balances", err.Error()))
-		return
-	}
+// DownloadDataExport godoc
+// @Summary Download data export
+// @Description Get a short-lived download link for a completed data export archive
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path integer true "Data export ID"
+// @Success 200 {object} models.Response "Download link"
+// @Failure 404 {object} models.Response "Data export not found or not ready"
+// @Router /users/data-exports/{id}/download [get]
+func (uc *UserController) DownloadDataExport(c *gin.Context) {
+	userID := c.GetUint("user_id")
 
-	// Finally, delete the user account
-	if err := tx.Unscoped().Delete(&user).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to delete account", err.Error()))
+	exportID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid data export ID", err.Error()))
 		return
 	}
 
-	// Commit the transaction
-	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to commit transaction", err.Error()))
+	url, expiresAt, err := uc.accountDataService.GetExportDownloadURL(userID, uint(exportID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, views.CreateErrorResponse("Data export not available", err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, views.CreateSuccessResponse("Account deleted successfully", gin.H{
-		"message": "Your account and all associated data have been permanently deleted",
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Download link created successfully", gin.H{
+		"url":        url,
+		"expires_at": expiresAt,
 	}))
 }
@@ -28,12 +28,14 @@ type PurchaseSubscriptionRequest struct {
 	PlanID        uint   `json:"plan_id" binding:"required"`
 	PaymentMethod string `json:"payment_method" binding:"required"`
 	DurationType  string `json:"duration_type" binding:"required"`
+	CouponCode    string `json:"coupon_code"`
 }
 
 // CreateSubscriptionPaymentOrderRequest represents subscription payment order request
 type CreateSubscriptionPaymentOrderRequest struct {
 	PlanID       uint   `json:"plan_id" binding:"required"`
 	DurationType string `json:"duration_type" binding:"required"`
+	CouponCode   string `json:"coupon_code"`
 }
 
 // CompleteSubscriptionPurchaseRequest represents subscription purchase completion request
@@ -79,7 +81,7 @@ func (usc *UserSubscriptionController) PurchaseSubscription(c *gin.Context) {
 		return
 	}
 
-	subscription, err := usc.subscriptionService.PurchaseSubscription(userID.(uint), req.PlanID, req.PaymentMethod, req.DurationType)
+	subscription, err := usc.subscriptionService.PurchaseSubscription(userID.(uint), req.PlanID, req.PaymentMethod, req.DurationType, req.CouponCode)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to purchase subscription", err.Error()))
 		return
@@ -113,7 +115,7 @@ func (usc *UserSubscriptionController) CreateSubscriptionPaymentOrder(c *gin.Con
 		return
 	}
 
-	payment, razorpayOrder, err := usc.subscriptionService.CreateSubscriptionPaymentOrder(userID.(uint), req.PlanID, req.DurationType)
+	payment, razorpayOrder, err := usc.subscriptionService.CreateSubscriptionPaymentOrder(userID.(uint), req.PlanID, req.DurationType, req.CouponCode)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to create payment order", err.Error()))
 		return
@@ -603,10 +603,10 @@ func (vc *VendorController) parseFormDataVendor(c *gin.Context, req *models.Crea
 			return fmt.Errorf("profile picture must be an image file")
 		}
 		
-		// Upload to Cloudinary
-		cloudinaryService := vc.vendorService.GetCloudinaryService()
-		if cloudinaryService != nil {
-			profileURL, err := cloudinaryService.UploadImage(profileFile, "vendors/profiles")
+		// Upload to media storage
+		mediaStore := vc.vendorService.GetMediaStore()
+		if mediaStore != nil {
+			profileURL, err := mediaStore.UploadImage(profileFile, "vendors/profiles")
 			if err != nil {
 				return fmt.Errorf("failed to upload profile picture: %v", err)
 			}
@@ -630,10 +630,10 @@ func (vc *VendorController) parseFormDataVendor(c *gin.Context, req *models.Crea
 		}
 
 		var galleryURLs []string
-		cloudinaryService := vc.vendorService.GetCloudinaryService()
+		mediaStore := vc.vendorService.GetMediaStore()
 
-		if cloudinaryService == nil {
-			logrus.Error("Cloudinary service is nil, cannot upload gallery images")
+		if mediaStore == nil {
+			logrus.Error("media store is nil, cannot upload gallery images")
 			return fmt.Errorf("image upload service is not available")
 		}
 
@@ -654,10 +654,10 @@ func (vc *VendorController) parseFormDataVendor(c *gin.Context, req *models.Crea
 				return fmt.Errorf("gallery images must be image files")
 			}
 
-			// Upload to Cloudinary
-			galleryURL, err := cloudinaryService.UploadImage(file, "vendors/gallery")
+			// Upload to media storage
+			galleryURL, err := mediaStore.UploadImage(file, "vendors/gallery")
 			if err != nil {
-				logrus.Errorf("Failed to upload gallery image %d to Cloudinary: %v", i+1, err)
+				logrus.Errorf("Failed to upload gallery image %d to media storage: %v", i+1, err)
 				return fmt.Errorf("failed to upload gallery image: %v", err)
 			}
 			logrus.Infof("Successfully uploaded gallery image %d: %s", i+1, galleryURL)
@@ -753,10 +753,10 @@ func (vc *VendorController) parseFormDataUpdateVendor(c *gin.Context, req *model
 			return fmt.Errorf("profile picture must be an image file")
 		}
 
-		// Upload to Cloudinary
-		cloudinaryService := vc.vendorService.GetCloudinaryService()
-		if cloudinaryService != nil {
-			profileURL, err := cloudinaryService.UploadImage(profileFile, "vendors/profiles")
+		// Upload to media storage
+		mediaStore := vc.vendorService.GetMediaStore()
+		if mediaStore != nil {
+			profileURL, err := mediaStore.UploadImage(profileFile, "vendors/profiles")
 			if err != nil {
 				return fmt.Errorf("failed to upload profile picture: %v", err)
 			}
@@ -774,9 +774,9 @@ func (vc *VendorController) parseFormDataUpdateVendor(c *gin.Context, req *model
 		}
 
 		var galleryURLs []string
-		cloudinaryService := vc.vendorService.GetCloudinaryService()
+		mediaStore := vc.vendorService.GetMediaStore()
 
-		if cloudinaryService != nil {
+		if mediaStore != nil {
 			for _, file := range galleryFiles {
 				// Validate file size (5MB limit)
 				if file.Size > 5*1024*1024 {
@@ -789,8 +789,8 @@ func (vc *VendorController) parseFormDataUpdateVendor(c *gin.Context, req *model
 					return fmt.Errorf("gallery images must be image files")
 				}
 
-				// Upload to Cloudinary
-				galleryURL, err := cloudinaryService.UploadImage(file, "vendors/gallery")
+				// Upload to media storage
+				galleryURL, err := mediaStore.UploadImage(file, "vendors/gallery")
 				if err != nil {
 					return fmt.Errorf("failed to upload gallery image: %v", err)
 				}
@@ -230,7 +230,7 @@ func (wac *WorkerAssignmentController) StartAssignment(c *gin.Context) {
 		return
 	}
 
-	assignment, err := wac.workerAssignmentService.StartAssignment(uint(assignmentID), workerID, req.Notes)
+	assignment, err := wac.workerAssignmentService.StartAssignment(uint(assignmentID), workerID, req.PIN, req.Notes)
 	if err != nil {
 		logrus.Errorf("Failed to start assignment: %v", err)
 		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Failed to start assignment", err.Error()))
@@ -275,7 +275,7 @@ func (wac *WorkerAssignmentController) CompleteAssignment(c *gin.Context) {
 		return
 	}
 
-	assignment, err := wac.workerAssignmentService.CompleteAssignment(uint(assignmentID), workerID, req.Notes, req.MaterialsUsed, req.Photos)
+	assignment, err := wac.workerAssignmentService.CompleteAssignment(uint(assignmentID), workerID, req.PIN, req.Notes, req.MaterialsUsed, req.Photos)
 	if err != nil {
 		logrus.Errorf("Failed to complete assignment: %v", err)
 		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Failed to complete assignment", err.Error()))
@@ -288,6 +288,50 @@ func (wac *WorkerAssignmentController) CompleteAssignment(c *gin.Context) {
 	c.JSON(http.StatusOK, views.CreateSuccessResponse("Assignment completed successfully", assignment))
 }
 
+// AdminForceComplete completes a booking's service without the customer's completion PIN
+// @Summary Force complete service
+// @Description Complete the service of a booking without the completion PIN (admin only)
+// @Tags Worker Assignments
+// @Accept json
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Param request body models.ForceCompleteBookingRequest true "Force complete request"
+// @Success 200 {object} views.Response{data=models.WorkerAssignment}
+// @Failure 400 {object} views.Response
+// @Failure 401 {object} views.Response
+// @Router /admin/bookings/{id}/force-complete [post]
+func (wac *WorkerAssignmentController) AdminForceComplete(c *gin.Context) {
+	adminID := wac.GetUserID(c)
+	if adminID == 0 {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "Admin not authenticated"))
+		return
+	}
+
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid booking ID", "Booking ID must be a valid number"))
+		return
+	}
+
+	var req models.ForceCompleteBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid request data", err.Error()))
+		return
+	}
+
+	assignment, err := wac.workerAssignmentService.ForceCompleteBooking(uint(bookingID), adminID, req.Reason)
+	if err != nil {
+		logrus.Errorf("Failed to force complete booking %d: %v", bookingID, err)
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Failed to complete service", err.Error()))
+		return
+	}
+
+	// Send notification to customer
+	wac.sendAssignmentNotification(assignment, "completed")
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Service completed successfully", assignment))
+}
+
 // sendAssignmentNotification sends notification about assignment status changes
 func (wac *WorkerAssignmentController) sendAssignmentNotification(assignment *models.WorkerAssignment, notificationType string) {
 	// This runs in a goroutine, so it doesn't block the response
@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"treesindia/models"
+	"treesindia/services"
+	"treesindia/views"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type WorkerLocationController struct {
+	BaseController
+	locationTrackingService *services.LocationTrackingService
+}
+
+func NewWorkerLocationController(locationTrackingService *services.LocationTrackingService) *WorkerLocationController {
+	return &WorkerLocationController{
+		BaseController:          *NewBaseController(),
+		locationTrackingService: locationTrackingService,
+	}
+}
+
+// UpdateLocation records the worker's current location for an assignment
+// @Summary Update worker location
+// @Description Share the worker's location with the customer during an accepted or in-progress assignment
+// @Tags Worker Location
+// @Accept json
+// @Produce json
+// @Param id path int true "Assignment ID"
+// @Param request body models.LocationUpdate true "Location update"
+// @Success 200 {object} views.Response{data=models.WorkerLocationResponse}
+// @Failure 400 {object} views.Response
+// @Failure 401 {object} views.Response
+// @Router /worker/assignments/{id}/location [post]
+func (wlc *WorkerLocationController) UpdateLocation(c *gin.Context) {
+	workerID := wlc.GetUserID(c)
+	if workerID == 0 {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "Worker not authenticated"))
+		return
+	}
+
+	assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid assignment ID", "Assignment ID must be a valid number"))
+		return
+	}
+
+	var req models.LocationUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	location, err := wlc.locationTrackingService.UpdateLocation(workerID, uint(assignmentID), &req)
+	if err != nil {
+		logrus.Errorf("Failed to update worker location: %v", err)
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Failed to update location", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Location updated successfully", location))
+}
+
+// StopTracking stops sharing the worker's location for an assignment
+// @Summary Stop location tracking
+// @Description Stop sharing the worker's location for an assignment
+// @Tags Worker Location
+// @Produce json
+// @Param id path int true "Assignment ID"
+// @Success 200 {object} views.Response
+// @Failure 400 {object} views.Response
+// @Failure 401 {object} views.Response
+// @Router /worker/assignments/{id}/location/stop [post]
+func (wlc *WorkerLocationController) StopTracking(c *gin.Context) {
+	workerID := wlc.GetUserID(c)
+	if workerID == 0 {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "Worker not authenticated"))
+		return
+	}
+
+	assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid assignment ID", "Assignment ID must be a valid number"))
+		return
+	}
+
+	if err := wlc.locationTrackingService.StopTracking(workerID, uint(assignmentID)); err != nil {
+		logrus.Errorf("Failed to stop location tracking: %v", err)
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Failed to stop tracking", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Location tracking stopped", nil))
+}
+
+// GetWorkerTracking gets the tracking state of an assignment
+// @Summary Get assignment tracking
+// @Description Get the last shared location, distance and ETA of an assignment
+// @Tags Worker Location
+// @Produce json
+// @Param id path int true "Assignment ID"
+// @Success 200 {object} views.Response{data=models.WorkerLocationResponse}
+// @Failure 400 {object} views.Response
+// @Failure 401 {object} views.Response
+// @Router /worker/assignments/{id}/location [get]
+func (wlc *WorkerLocationController) GetWorkerTracking(c *gin.Context) {
+	workerID := wlc.GetUserID(c)
+	if workerID == 0 {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "Worker not authenticated"))
+		return
+	}
+
+	assignmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid assignment ID", "Assignment ID must be a valid number"))
+		return
+	}
+
+	location, err := wlc.locationTrackingService.GetWorkerTracking(workerID, uint(assignmentID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, views.CreateErrorResponse("Tracking not available", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Tracking retrieved successfully", location))
+}
+
+// GetBookingTracking gets the live location and ETA of the worker assigned to a booking
+// @Summary Track booking worker
+// @Description Get the live location, distance and ETA of the worker assigned to the customer's booking
+// @Tags Worker Location
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Success 200 {object} views.Response{data=models.WorkerLocationResponse}
+// @Failure 400 {object} views.Response
+// @Failure 401 {object} views.Response
+// @Failure 404 {object} views.Response
+// @Router /bookings/{id}/tracking [get]
+func (wlc *WorkerLocationController) GetBookingTracking(c *gin.Context) {
+	userID := wlc.GetUserID(c)
+	if userID == 0 {
+		c.JSON(http.StatusUnauthorized, views.CreateErrorResponse("Unauthorized", "User not authenticated"))
+		return
+	}
+
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid booking ID", "Booking ID must be a valid number"))
+		return
+	}
+
+	location, err := wlc.locationTrackingService.GetBookingTracking(userID, uint(bookingID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, views.CreateErrorResponse("Tracking not available", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Tracking retrieved successfully", location))
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"treesindia/services"
+	"treesindia/views"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type WorkerReassignmentController struct {
+	BaseController
+	workerReassignmentService *services.WorkerReassignmentService
+}
+
+func NewWorkerReassignmentController(workerReassignmentService *services.WorkerReassignmentService) *WorkerReassignmentController {
+	return &WorkerReassignmentController{
+		BaseController:            *NewBaseController(),
+		workerReassignmentService: workerReassignmentService,
+	}
+}
+
+// GetEscalatedBookings gets bookings waiting for an admin to assign a worker
+// @Summary Get escalated bookings
+// @Description Get bookings that no worker accepted after automatic reassignment (admin only)
+// @Tags Worker Assignments
+// @Produce json
+// @Success 200 {object} views.Response{data=[]models.Booking}
+// @Failure 500 {object} views.Response
+// @Router /admin/bookings/escalated [get]
+func (wrc *WorkerReassignmentController) GetEscalatedBookings(c *gin.Context) {
+	bookings, err := wrc.workerReassignmentService.GetEscalatedBookings()
+	if err != nil {
+		logrus.Errorf("Failed to get escalated bookings: %v", err)
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to get escalated bookings", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Escalated bookings retrieved successfully", bookings))
+}
+
+// GetBookingOffers gets the workers a booking was offered to
+// @Summary Get booking offers
+// @Description Get the workers a booking was offered to and how each responded (admin only)
+// @Tags Worker Assignments
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Success 200 {object} views.Response{data=[]models.AssignmentOffer}
+// @Failure 400 {object} views.Response
+// @Router /admin/bookings/{id}/offers [get]
+func (wrc *WorkerReassignmentController) GetBookingOffers(c *gin.Context) {
+	bookingID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid booking ID", "Booking ID must be a valid number"))
+		return
+	}
+
+	offers, err := wrc.workerReassignmentService.GetBookingOffers(uint(bookingID))
+	if err != nil {
+		logrus.Errorf("Failed to get offers for booking %d: %v", bookingID, err)
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to get offers", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Booking offers retrieved successfully", offers))
+}
+
+// GetWorkerAcceptanceStats gets a worker's acceptance rate
+// @Summary Get worker acceptance rate
+// @Description Get how a worker responded to the bookings offered to them in the last 90 days (admin only)
+// @Tags Worker Assignments
+// @Produce json
+// @Param worker_id path int true "Worker user ID"
+// @Success 200 {object} views.Response{data=models.WorkerAcceptanceStats}
+// @Failure 400 {object} views.Response
+// @Router /admin/workers/{worker_id}/acceptance [get]
+func (wrc *WorkerReassignmentController) GetWorkerAcceptanceStats(c *gin.Context) {
+	workerID, err := strconv.ParseUint(c.Param("worker_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, views.CreateErrorResponse("Invalid worker ID", "Worker ID must be a valid number"))
+		return
+	}
+
+	stats, err := wrc.workerReassignmentService.GetWorkerAcceptanceStats(uint(workerID))
+	if err != nil {
+		logrus.Errorf("Failed to get acceptance stats for worker %d: %v", workerID, err)
+		c.JSON(http.StatusInternalServerError, views.CreateErrorResponse("Failed to get acceptance rate", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, views.CreateSuccessResponse("Worker acceptance rate retrieved successfully", stats))
+}
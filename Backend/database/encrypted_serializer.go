@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"treesindia/utils"
+
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+
+// EncryptedSerializer stores string fields tagged `gorm:"serializer:encrypted"` encrypted with
+// utils.EncryptField and decrypts them when they are loaded. Rows written before encryption was
+// enabled are read as they are until cmd/encrypt-fields rewrites them.
+type EncryptedSerializer struct{}
+
+// Scan decrypts a column value into the field
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	default:
+		return fmt.Errorf("failed to decrypt %s: unsupported value type %T", field.Name, dbValue)
+	}
+
+	plaintext, err := utils.DecryptField(value)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s: %v", field.Name, err)
+	}
+
+	field.ReflectValueOf(ctx, dst).SetString(plaintext)
+	return nil
+}
+
+// Value encrypts the field for storage
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to encrypt %s: unsupported field type %T", field.Name, fieldValue)
+	}
+	return utils.EncryptField(value)
+}
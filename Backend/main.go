@@ -9,11 +9,13 @@ import (
 	"treesindia/config"
 	"treesindia/controllers"
 	"treesindia/database"
+	"treesindia/metrics"
 	"treesindia/middleware"
 	"treesindia/repositories"
 	"treesindia/routes"
 	"treesindia/seed"
 	"treesindia/services"
+	"treesindia/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -62,6 +64,11 @@ func initDatabase(appConfig *config.AppConfig) {
 		logrus.Fatal("Failed to connect to database:", err)
 	}
 
+	// Record query timings for the /metrics endpoint
+	if err := db.Use(metrics.NewGormPlugin()); err != nil {
+		logrus.Warnf("Failed to register database metrics: %v", err)
+	}
+
 	// Set the database instance in the database package
 	database.SetDB(db)
 
@@ -146,6 +153,17 @@ func main() {
 	// Initialize database with new config
 	initDatabase(appConfig)
 
+	// Initialize field encryption for worker and broker banking details and documents
+	if appConfig.FieldEncryptionKeys == "" {
+		if appConfig.IsProduction() {
+			log.Fatal("FIELD_ENCRYPTION_KEYS must be set in production")
+		}
+		logrus.Warn("FIELD_ENCRYPTION_KEYS is not set; banking details and documents will be stored unencrypted")
+	}
+	if err := utils.InitFieldEncryption(appConfig.FieldEncryptionKeys); err != nil {
+		log.Fatal("Failed to initialize field encryption:", err)
+	}
+
 	// Always run database migrations (both development and production)
 	// IMPORTANT: This must run BEFORE any GORM operations to ensure correct schema
 	if err := runMigrations(appConfig); err != nil {
@@ -219,12 +237,15 @@ func main() {
 		log.Fatal("Failed to initialize FCM service:", err)
 	}
 
+	// Initialize realtime pub/sub so websocket broadcasts reach clients on every instance
+	realtimePubSub := services.NewRealtimePubSub(appConfig)
+
 	// Initialize WebSocket service
-	wsService := services.NewWebSocketService()
+	wsService := services.NewWebSocketService(realtimePubSub)
 	wsController := controllers.NewWebSocketController(wsService)
 
 	// Initialize Simple Conversation WebSocket service
-	simpleConversationWsService := services.NewSimpleConversationWebSocketService()
+	simpleConversationWsService := services.NewSimpleConversationWebSocketService(realtimePubSub)
 
 	// Initialize notification services (moved before chat service)
 	deviceManagementService := services.NewDeviceManagementService(fcmService)
@@ -232,28 +253,26 @@ func main() {
 
 	// Initialize services with WebSocket service and notification service
 	chatService := services.NewChatService(wsService, enhancedNotificationService)
-	workerAssignmentService := services.NewWorkerAssignmentService(chatService)
+	locationTrackingService := services.NewLocationTrackingService(wsService, enhancedNotificationService)
+	workerAssignmentService := services.NewWorkerAssignmentService(chatService, locationTrackingService)
 
 	// Initialize Simple Conversation services
 	simpleConversationRepo := repositories.NewSimpleConversationRepository(db)
 	simpleConversationMessageRepo := repositories.NewSimpleConversationMessageRepository(db)
 	userRepo := repositories.NewUserRepository()
-	// Initialize Cloudinary service for file uploads
-	cloudinaryService, err := services.NewCloudinaryService()
+	// Initialize media store for file uploads
+	mediaStore, err := services.NewMediaStore()
 	if err != nil {
-		logrus.Errorf("Failed to initialize CloudinaryService: %v", err)
-		// Don't panic, create a nil cloudinary service
-		cloudinaryService = nil
-	} else {
-		logrus.Info("CloudinaryService initialized successfully")
+		logrus.Fatalf("Failed to initialize media store: %v", err)
 	}
+	logrus.Infof("Media store initialized (%s backend)", mediaStore.Backend())
 
 	simpleConversationService := services.NewSimpleConversationService(
 		simpleConversationRepo,
 		simpleConversationMessageRepo,
 		userRepo,
 		simpleConversationWsService,
-		cloudinaryService,
+		mediaStore,
 	)
 
 	// Start cleanup service
@@ -268,12 +287,54 @@ func main() {
 	paymentSegmentReminderService := services.NewPaymentSegmentReminderService(enhancedNotificationService)
 	paymentSegmentReminderService.Start()
 
+	// Start quote expiry service
+	quoteExpiryService := services.NewQuoteExpiryService(enhancedNotificationService)
+	quoteExpiryService.Start()
+
+	// Start orphaned media garbage collection
+	mediaGCService := services.NewMediaGCService(mediaStore)
+	mediaGCService.Start()
+
+	// Start data export and account deletion processing
+	accountDataService := services.NewAccountDataService(mediaStore)
+	accountDataService.Start()
+
+	// Start auto-completion of forgotten services
+	assignmentExpiryService := services.NewAssignmentExpiryService(workerAssignmentService, enhancedNotificationService)
+	assignmentExpiryService.Start()
+
+	// Start expiry and automatic reassignment of unaccepted assignments
+	workerReassignmentService := services.NewWorkerReassignmentService(enhancedNotificationService)
+	workerReassignmentService.Start()
+
+	// Start expiry of KYC documents such as police verification
+	documentExpiryService := services.NewDocumentExpiryService(enhancedNotificationService)
+	documentExpiryService.Start()
+
+	// Start property site visit reminders
+	siteVisitReminderService := services.NewSiteVisitReminderService(enhancedNotificationService)
+	siteVisitReminderService.Start()
+
+	// Start daily digests of saved property search alerts
+	savedSearchAlertService := services.NewSavedSearchAlertService(enhancedNotificationService)
+	savedSearchAlertService.Start()
+
+	// Start listing expiry and boost decay
+	propertyExpiryService := services.NewPropertyExpiryService(enhancedNotificationService)
+	propertyExpiryService.Start()
+
 	// Start Simple Conversation WebSocket service
 	go simpleConversationWsService.Start()
 
 	// Setup WebSocket routes (outside of /api/v1 prefix)
 	routes.SetupWebSocketRoutes(r, wsController)
 
+	// Setup Prometheus metrics endpoint (outside of /api/v1 prefix)
+	routes.SetupMetricsRoutes(r, appConfig)
+
+	// Serve locally stored media (outside of /api/v1 prefix)
+	routes.SetupMediaRoutes(r, mediaStore)
+
 	// Setup chat routes with WebSocket service
 	routes.SetupChatRoutes(r.Group("/api/v1"), chatService)
 
@@ -286,6 +347,8 @@ func main() {
 	bookingGroup := r.Group("/api/v1")
 	bookingGroup.Use(bookingMiddleware.BookingSystem())
 	routes.SetupWorkerAssignmentRoutes(bookingGroup, workerAssignmentService, enhancedNotificationService, db)
+	routes.SetupLocationTrackingRoutes(bookingGroup, locationTrackingService)
+	routes.SetupWorkerReassignmentRoutes(bookingGroup, workerReassignmentService)
 
 	// Setup role application routes with notification service
 	routes.SetupRoleApplicationRoutes(r.Group("/api/v1"), enhancedNotificationService)
@@ -295,6 +358,9 @@ func main() {
 
 	// Setup property routes with notification service
 	routes.SetupPropertyRoutes(r.Group("/api/v1"), enhancedNotificationService)
+	routes.SetupPropertyEnquiryRoutes(r.Group("/api/v1"), enhancedNotificationService)
+	routes.SetupSavedSearchRoutes(r.Group("/api/v1"))
+	routes.SetupPropertyPromotionRoutes(r.Group("/api/v1"))
 
 	// Setup notification routes (existing push notifications)
 	notificationController := controllers.NewNotificationController(enhancedNotificationService, deviceManagementService)
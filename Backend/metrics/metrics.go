@@ -0,0 +1,217 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+const namespace = "treesindia"
+
+// startTime is when this process started serving
+var startTime = time.Now()
+
+// Registry holds every metric exposed on /metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestDuration tracks request latency per route
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"method", "route"})
+
+	// HTTPRequestsTotal counts requests per route and status code
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	// DBQueryDuration tracks GORM query latency per operation and table
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency by GORM operation and table.",
+		Buckets:   []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	// DBQueryErrorsTotal counts failed GORM queries (record-not-found is not an error here)
+	DBQueryErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Database queries that returned an error, by GORM operation and table.",
+	}, []string{"operation", "table"})
+
+	// BookingsCreatedTotal counts bookings created by booking type
+	BookingsCreatedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bookings_created_total",
+		Help:      "Bookings created, by booking type.",
+	}, []string{"booking_type"})
+
+	// PaymentsTotal counts gateway payments reaching a final status
+	PaymentsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "payments_total",
+		Help:      "Gateway payments completed or failed, by payment type and status.",
+	}, []string{"type", "status"})
+
+	// WalletDebitsTotal counts wallet debits
+	WalletDebitsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "wallet_debits_total",
+		Help:      "Wallet debits, by what the wallet paid for.",
+	}, []string{"purpose"})
+
+	// WalletDebitAmountTotal sums wallet debit amounts in INR
+	WalletDebitAmountTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "wallet_debit_amount_inr_total",
+		Help:      "Total amount debited from wallets in INR, by what the wallet paid for.",
+	}, []string{"purpose"})
+
+	// FCMMessagesTotal counts push notification sends per device by result
+	FCMMessagesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fcm_messages_total",
+		Help:      "FCM push messages by result (success or failure).",
+	}, []string{"result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "uptime_seconds",
+			Help:      "Seconds since the API process started.",
+		}, func() float64 { return time.Since(startTime).Seconds() }),
+		HTTPRequestDuration,
+		HTTPRequestsTotal,
+		DBQueryDuration,
+		DBQueryErrorsTotal,
+		BookingsCreatedTotal,
+		PaymentsTotal,
+		WalletDebitsTotal,
+		WalletDebitAmountTotal,
+		FCMMessagesTotal,
+	)
+}
+
+// Handler serves the registry in Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records one served request
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	HTTPRequestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+	HTTPRequestsTotal.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+}
+
+// RecordBookingCreated counts a new booking
+func RecordBookingCreated(bookingType string) {
+	BookingsCreatedTotal.WithLabelValues(bookingType).Inc()
+}
+
+// RecordPayment counts a gateway payment reaching a final status
+func RecordPayment(paymentType, status string) {
+	PaymentsTotal.WithLabelValues(paymentType, status).Inc()
+}
+
+// RecordWalletDebit counts a wallet debit and its amount
+func RecordWalletDebit(purpose string, amount float64) {
+	WalletDebitsTotal.WithLabelValues(purpose).Inc()
+	WalletDebitAmountTotal.WithLabelValues(purpose).Add(amount)
+}
+
+// RecordFCMResult counts push messages that were accepted or rejected by FCM
+func RecordFCMResult(successCount, failureCount int) {
+	if successCount > 0 {
+		FCMMessagesTotal.WithLabelValues("success").Add(float64(successCount))
+	}
+	if failureCount > 0 {
+		FCMMessagesTotal.WithLabelValues("failure").Add(float64(failureCount))
+	}
+}
+
+// RegisterWebSocketHub exposes the live connection count of a websocket hub
+func RegisterWebSocketHub(hub string, connections func() int) {
+	gauge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "websocket_connections",
+		Help:        "Open websocket connections on this instance, by hub.",
+		ConstLabels: prometheus.Labels{"hub": hub},
+	}, func() float64 { return float64(connections()) })
+
+	if err := Registry.Register(gauge); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			logrus.Errorf("Failed to register websocket gauge for hub %s: %v", hub, err)
+		}
+	}
+}
+
+// Snapshot is a summary of the request metrics used by the system health endpoint
+type Snapshot struct {
+	Uptime              time.Duration
+	TotalRequests       uint64
+	ServerErrors        uint64
+	AverageResponseTime time.Duration
+}
+
+// ErrorRate returns the share of requests that ended in a 5xx, as a percentage
+func (s Snapshot) ErrorRate() float64 {
+	if s.TotalRequests == 0 {
+		return 0
+	}
+	return float64(s.ServerErrors) / float64(s.TotalRequests) * 100
+}
+
+// GetSnapshot reads request totals and latency from the registry
+func GetSnapshot() (*Snapshot, error) {
+	families, err := Registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{Uptime: time.Since(startTime)}
+	var latencySum float64
+	var latencyCount uint64
+
+	for _, family := range families {
+		switch family.GetName() {
+		case namespace + "_http_requests_total":
+			for _, metric := range family.GetMetric() {
+				count := uint64(metric.GetCounter().GetValue())
+				snapshot.TotalRequests += count
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "status" && len(label.GetValue()) == 3 && label.GetValue()[0] == '5' {
+						snapshot.ServerErrors += count
+					}
+				}
+			}
+		case namespace + "_http_request_duration_seconds":
+			for _, metric := range family.GetMetric() {
+				latencySum += metric.GetHistogram().GetSampleSum()
+				latencyCount += metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	if latencyCount > 0 {
+		snapshot.AverageResponseTime = time.Duration(latencySum / float64(latencyCount) * float64(time.Second))
+	}
+
+	return snapshot, nil
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"strings"
@@ -142,6 +143,57 @@ func AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// OptionalAuthMiddleware identifies the user when a valid access token is sent, and lets
+// the request through as a guest otherwise
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenParts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+			c.Next()
+			return
+		}
+
+		appConfig := config.LoadConfig()
+		parsedToken, err := jwt.Parse(tokenParts[1], func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return []byte(appConfig.JWTSecret), nil
+		})
+		if err != nil || !parsedToken.Valid {
+			c.Next()
+			return
+		}
+
+		claims, ok := parsedToken.Claims.(jwt.MapClaims)
+		if !ok {
+			c.Next()
+			return
+		}
+		if tokenType, ok := claims["type"].(string); !ok || tokenType != "access" {
+			c.Next()
+			return
+		}
+		userIDFloat, ok := claims["user_id"].(float64)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		var user models.User
+		if err := database.GetDB().First(&user, uint(userIDFloat)).Error; err != nil || !user.IsActive {
+			c.Next()
+			return
+		}
+
+		c.Set("user_id", user.ID)
+		c.Set("user_type", string(user.UserType))
+		c.Set("user", user)
+
+		c.Next()
+	}
+}
+
 // AdminMiddleware ensures only admin users can access
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -282,4 +334,23 @@ func UserOrWorkerMiddleware() gin.HandlerFunc {
 	}
 }
 
+// MetricsTokenMiddleware requires the configured bearer token to scrape metrics (no-op when unset)
+func MetricsTokenMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
 
+		expected := "Bearer " + token
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte(expected)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"message": "Invalid metrics token",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -2,12 +2,13 @@ package middleware
 
 import (
 	"time"
+	"treesindia/metrics"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
-// PerformanceMiddleware tracks response times and logs performance metrics
+// PerformanceMiddleware tracks response times, records request metrics and logs slow requests
 func PerformanceMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -23,6 +24,13 @@ func PerformanceMiddleware() gin.HandlerFunc {
 		method := c.Request.Method
 		path := c.Request.URL.Path
 		
+		// Record by route template so path IDs don't explode label cardinality
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHTTPRequest(method, route, status, duration)
+		
 		// Log based on response time thresholds
 		switch {
 		case duration > 10*time.Second:
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Create realtime_events table for websocket pub/sub payloads too large for NOTIFY
+
+CREATE TABLE IF NOT EXISTS realtime_events (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+
+    -- Pub/sub channel the payload was published on
+    channel VARCHAR(63) NOT NULL,
+
+    -- Serialized broadcast payload
+    payload TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_realtime_events_created_at ON realtime_events(created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS realtime_events;
@@ -0,0 +1,39 @@
+-- +goose Up
+-- Add milestone scheduling, work gates and reminder tracking to payment_segments
+
+ALTER TABLE payment_segments ADD COLUMN IF NOT EXISTS due_trigger VARCHAR(30) NOT NULL DEFAULT 'manual';
+ALTER TABLE payment_segments ADD COLUMN IF NOT EXISTS due_after_days INTEGER;
+ALTER TABLE payment_segments ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ;
+ALTER TABLE payment_segments ADD COLUMN IF NOT EXISTS required_before VARCHAR(20) NOT NULL DEFAULT 'none';
+ALTER TABLE payment_segments ADD COLUMN IF NOT EXISTS overdue_at TIMESTAMPTZ;
+ALTER TABLE payment_segments ADD COLUMN IF NOT EXISTS reminder_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE payment_segments ADD COLUMN IF NOT EXISTS last_reminder_at TIMESTAMPTZ;
+
+ALTER TABLE payment_segments ADD CONSTRAINT chk_payment_segments_due_trigger
+    CHECK (due_trigger IN ('on_booking', 'on_work_start', 'on_completion', 'days_after_previous', 'manual'));
+ALTER TABLE payment_segments ADD CONSTRAINT chk_payment_segments_required_before
+    CHECK (required_before IN ('none', 'work_start', 'completion'));
+
+-- Existing first segments were always due on quote acceptance
+UPDATE payment_segments SET due_trigger = 'on_booking' WHERE segment_number = 1;
+UPDATE payment_segments ps SET due_at = b.quote_accepted_at
+FROM bookings b
+WHERE ps.booking_id = b.id AND ps.segment_number = 1 AND ps.status IN ('pending', 'overdue');
+
+CREATE INDEX IF NOT EXISTS idx_payment_segments_due_at ON payment_segments(due_at);
+CREATE INDEX IF NOT EXISTS idx_payment_segments_required_before ON payment_segments(required_before);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_payment_segments_required_before;
+DROP INDEX IF EXISTS idx_payment_segments_due_at;
+
+ALTER TABLE payment_segments DROP CONSTRAINT IF EXISTS chk_payment_segments_required_before;
+ALTER TABLE payment_segments DROP CONSTRAINT IF EXISTS chk_payment_segments_due_trigger;
+
+ALTER TABLE payment_segments DROP COLUMN IF EXISTS last_reminder_at;
+ALTER TABLE payment_segments DROP COLUMN IF EXISTS reminder_count;
+ALTER TABLE payment_segments DROP COLUMN IF EXISTS overdue_at;
+ALTER TABLE payment_segments DROP COLUMN IF EXISTS required_before;
+ALTER TABLE payment_segments DROP COLUMN IF EXISTS due_at;
+ALTER TABLE payment_segments DROP COLUMN IF EXISTS due_after_days;
+ALTER TABLE payment_segments DROP COLUMN IF EXISTS due_trigger;
@@ -0,0 +1,81 @@
+-- +goose Up
+-- Create quote_revisions and quote_line_items tables for versioned quotes
+
+CREATE TABLE IF NOT EXISTS quote_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    booking_id BIGINT NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    type VARCHAR(30) NOT NULL CHECK (type IN ('quote', 'counter_offer', 'revision_request')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('active', 'open', 'superseded', 'accepted', 'rejected', 'expired')),
+
+    -- Author of the revision (admin for quotes, customer for counter-offers and requests)
+    author_id BIGINT NOT NULL REFERENCES users(id),
+    author_type VARCHAR(20) NOT NULL,
+
+    subtotal DECIMAL(10,2) NOT NULL DEFAULT 0,
+    tax_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    total_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+
+    notes TEXT,
+    duration VARCHAR(100),
+
+    -- Set when the customer accepts the revision
+    locked_at TIMESTAMPTZ,
+
+    UNIQUE (booking_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_revisions_booking_id ON quote_revisions(booking_id);
+CREATE INDEX IF NOT EXISTS idx_quote_revisions_status ON quote_revisions(status);
+CREATE INDEX IF NOT EXISTS idx_quote_revisions_deleted_at ON quote_revisions(deleted_at);
+
+CREATE TABLE IF NOT EXISTS quote_line_items (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    quote_revision_id BIGINT NOT NULL REFERENCES quote_revisions(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('labour', 'materials', 'visit_charge', 'tax', 'other')),
+    description TEXT,
+    quantity DECIMAL(10,2) NOT NULL DEFAULT 1,
+    unit_price DECIMAL(10,2) NOT NULL DEFAULT 0,
+    amount DECIMAL(10,2) NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_quote_line_items_quote_revision_id ON quote_line_items(quote_revision_id);
+CREATE INDEX IF NOT EXISTS idx_quote_line_items_deleted_at ON quote_line_items(deleted_at);
+
+ALTER TABLE payment_segments ADD COLUMN IF NOT EXISTS quote_revision_id BIGINT REFERENCES quote_revisions(id);
+CREATE INDEX IF NOT EXISTS idx_payment_segments_quote_revision_id ON payment_segments(quote_revision_id);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS accepted_quote_revision_id BIGINT REFERENCES quote_revisions(id);
+
+-- Backfill version 1 for bookings quoted before versioning
+INSERT INTO quote_revisions (booking_id, version, type, status, author_id, author_type, subtotal, total_amount, notes, duration, locked_at, created_at, updated_at)
+SELECT b.id, 1, 'quote',
+       CASE WHEN b.quote_accepted_at IS NOT NULL THEN 'accepted' ELSE 'active' END,
+       b.quote_provided_by, 'admin', b.quote_amount, b.quote_amount, b.quote_notes, b.quote_duration,
+       b.quote_accepted_at, COALESCE(b.quote_provided_at, NOW()), COALESCE(b.quote_provided_at, NOW())
+FROM bookings b
+WHERE b.quote_amount IS NOT NULL AND b.quote_provided_by IS NOT NULL AND b.deleted_at IS NULL;
+
+UPDATE bookings b SET accepted_quote_revision_id = qr.id
+FROM quote_revisions qr
+WHERE qr.booking_id = b.id AND qr.status = 'accepted';
+
+UPDATE payment_segments ps SET quote_revision_id = qr.id
+FROM quote_revisions qr
+WHERE qr.booking_id = ps.booking_id AND qr.version = 1;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_payment_segments_quote_revision_id;
+ALTER TABLE payment_segments DROP COLUMN IF EXISTS quote_revision_id;
+ALTER TABLE bookings DROP COLUMN IF EXISTS accepted_quote_revision_id;
+
+DROP TABLE IF EXISTS quote_line_items;
+DROP TABLE IF EXISTS quote_revisions;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS quote_expiry_warning_sent_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_quote_expires_at ON bookings(quote_expires_at) WHERE quote_expires_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_bookings_quote_expires_at;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS quote_expiry_warning_sent_at;
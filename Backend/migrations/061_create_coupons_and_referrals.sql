@@ -0,0 +1,100 @@
+-- +goose Up
+-- Create coupons, coupon_redemptions and referrals tables for promotions
+
+CREATE TABLE IF NOT EXISTS coupons (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    code VARCHAR(50) NOT NULL,
+    description TEXT,
+
+    discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('percentage', 'flat')),
+    discount_value DECIMAL(10,2) NOT NULL,
+    max_discount DECIMAL(10,2),
+    min_order_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+
+    scope VARCHAR(20) NOT NULL DEFAULT 'all' CHECK (scope IN ('all', 'booking', 'quote', 'subscription')),
+    usage_limit INTEGER,
+    per_user_limit INTEGER NOT NULL DEFAULT 1,
+
+    -- Eligibility (empty = any)
+    category_ids BIGINT[],
+    service_ids BIGINT[],
+    cities TEXT[],
+    first_booking_only BOOLEAN NOT NULL DEFAULT FALSE,
+
+    valid_from TIMESTAMPTZ,
+    valid_until TIMESTAMPTZ,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    created_by BIGINT REFERENCES users(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_coupons_code ON coupons(code);
+CREATE INDEX IF NOT EXISTS idx_coupons_deleted_at ON coupons(deleted_at);
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    coupon_id BIGINT NOT NULL REFERENCES coupons(id),
+    code VARCHAR(50) NOT NULL,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    scope VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('reserved', 'applied', 'released')),
+
+    -- What the coupon was applied to
+    related_entity_type VARCHAR(50),
+    related_entity_id BIGINT,
+    payment_id BIGINT REFERENCES payments(id),
+
+    original_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    final_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+
+    applied_at TIMESTAMPTZ,
+    released_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_coupon_id ON coupon_redemptions(coupon_id);
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_user_id ON coupon_redemptions(user_id);
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_payment_id ON coupon_redemptions(payment_id);
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_related_entity ON coupon_redemptions(related_entity_type, related_entity_id);
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_deleted_at ON coupon_redemptions(deleted_at);
+
+CREATE TABLE IF NOT EXISTS referrals (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    referrer_id BIGINT NOT NULL REFERENCES users(id),
+    referee_id BIGINT NOT NULL REFERENCES users(id),
+    code VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'rewarded')),
+
+    -- Reward (set when the referee completes their first booking)
+    reward_booking_id BIGINT REFERENCES bookings(id),
+    referrer_reward DECIMAL(10,2) NOT NULL DEFAULT 0,
+    referee_reward DECIMAL(10,2) NOT NULL DEFAULT 0,
+    rewarded_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referrals_referee_id ON referrals(referee_id);
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer_id ON referrals(referrer_id);
+CREATE INDEX IF NOT EXISTS idx_referrals_deleted_at ON referrals(deleted_at);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS referral_code VARCHAR(20);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_referral_code ON users(referral_code);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_referral_code;
+ALTER TABLE users DROP COLUMN IF EXISTS referral_code;
+
+DROP TABLE IF EXISTS referrals;
+DROP TABLE IF EXISTS coupon_redemptions;
+DROP TABLE IF EXISTS coupons;
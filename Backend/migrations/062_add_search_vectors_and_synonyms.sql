@@ -0,0 +1,95 @@
+-- +goose Up
+-- Full-text search vectors, trigram indexes for typo tolerance and admin-managed search synonyms
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Weighted search vectors (A = title, B = location/category text, C = long descriptions)
+ALTER TABLE services ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'C')
+    ) STORED;
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'C')
+    ) STORED;
+
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(city, '') || ' ' || coalesce(state, '') || ' ' || coalesce(address, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'C')
+    ) STORED;
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(city, '') || ' ' || coalesce(state, '') || ' ' || coalesce(project_type, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'C')
+    ) STORED;
+
+ALTER TABLE vendors ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(vendor_name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(business_address->>'city', '') || ' ' || coalesce(business_address->>'state', '') || ' ' || coalesce(business_type, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(business_description, '') || ' ' || coalesce(services_offered::text, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_services_search_vector ON services USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_categories_search_vector ON categories USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_properties_search_vector ON properties USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_projects_search_vector ON projects USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_vendors_search_vector ON vendors USING GIN (search_vector);
+
+-- Trigram indexes back the word-similarity (<%) typo matching on titles
+CREATE INDEX IF NOT EXISTS idx_services_name_trgm ON services USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_categories_name_trgm ON categories USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_properties_title_trgm ON properties USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_projects_title_trgm ON projects USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_vendors_vendor_name_trgm ON vendors USING GIN (vendor_name gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS search_synonyms (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    term VARCHAR(100) NOT NULL,
+    synonyms TEXT[] NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_search_synonyms_term ON search_synonyms(term) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_search_synonyms_deleted_at ON search_synonyms(deleted_at);
+
+INSERT INTO search_synonyms (term, synonyms) VALUES
+    ('ac', ARRAY['air conditioner', 'air conditioning']),
+    ('plumber', ARRAY['plumbing']),
+    ('electrician', ARRAY['electrical']),
+    ('carpenter', ARRAY['carpentry']),
+    ('flat', ARRAY['apartment']),
+    ('bhk', ARRAY['bedroom'])
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS search_synonyms;
+
+DROP INDEX IF EXISTS idx_vendors_vendor_name_trgm;
+DROP INDEX IF EXISTS idx_projects_title_trgm;
+DROP INDEX IF EXISTS idx_properties_title_trgm;
+DROP INDEX IF EXISTS idx_categories_name_trgm;
+DROP INDEX IF EXISTS idx_services_name_trgm;
+
+DROP INDEX IF EXISTS idx_vendors_search_vector;
+DROP INDEX IF EXISTS idx_projects_search_vector;
+DROP INDEX IF EXISTS idx_properties_search_vector;
+DROP INDEX IF EXISTS idx_categories_search_vector;
+DROP INDEX IF EXISTS idx_services_search_vector;
+
+ALTER TABLE vendors DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE projects DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE properties DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE categories DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE services DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Log every search with its result count, the result the user opened and any booking it led to
+
+CREATE TABLE IF NOT EXISTS search_query_logs (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    user_id BIGINT REFERENCES users(id), -- NULL for guests
+    query VARCHAR(255) NOT NULL,
+    normalized_query VARCHAR(255) NOT NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('services', 'federated')),
+    result_count INTEGER NOT NULL DEFAULT 0,
+
+    -- Click-through (the last result the user opened)
+    click_count INTEGER NOT NULL DEFAULT 0,
+    clicked_entity_type VARCHAR(20),
+    clicked_entity_id BIGINT,
+    clicked_at TIMESTAMPTZ,
+
+    -- Conversion
+    booking_id BIGINT REFERENCES bookings(id),
+    converted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_query_logs_normalized_query ON search_query_logs(normalized_query);
+CREATE INDEX IF NOT EXISTS idx_search_query_logs_created_at ON search_query_logs(created_at);
+CREATE INDEX IF NOT EXISTS idx_search_query_logs_user_clicked ON search_query_logs(user_id, clicked_entity_type, clicked_entity_id);
+CREATE INDEX IF NOT EXISTS idx_search_query_logs_deleted_at ON search_query_logs(deleted_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS search_query_logs;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Perceptual hashes of processed uploads, used to flag duplicate listing photos
+
+CREATE TABLE IF NOT EXISTS image_fingerprints (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    url TEXT NOT NULL,
+    folder VARCHAR(255) NOT NULL DEFAULT '',
+    perceptual_hash BIGINT NOT NULL,
+    width INTEGER NOT NULL DEFAULT 0,
+    height INTEGER NOT NULL DEFAULT 0,
+    size_bytes BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_image_fingerprints_url ON image_fingerprints(url);
+CREATE INDEX IF NOT EXISTS idx_image_fingerprints_folder ON image_fingerprints(folder);
+CREATE INDEX IF NOT EXISTS idx_image_fingerprints_deleted_at ON image_fingerprints(deleted_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS image_fingerprints;
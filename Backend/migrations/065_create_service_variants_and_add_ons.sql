@@ -0,0 +1,52 @@
+-- +goose Up
+-- Priced variants (e.g. 1 BHK / 2 BHK) and optional add-ons of a service, and the pricing
+-- snapshot of the options chosen on a booking
+
+CREATE TABLE IF NOT EXISTS service_variants (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    service_id BIGINT NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    price DECIMAL NOT NULL,
+    duration VARCHAR(100),
+    max_quantity INTEGER NOT NULL DEFAULT 1,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_variants_service_id ON service_variants(service_id);
+CREATE INDEX IF NOT EXISTS idx_service_variants_deleted_at ON service_variants(deleted_at);
+
+CREATE TABLE IF NOT EXISTS service_add_ons (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    service_id BIGINT NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    price DECIMAL NOT NULL,
+    duration VARCHAR(100),
+    max_quantity INTEGER NOT NULL DEFAULT 1,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_add_ons_service_id ON service_add_ons(service_id);
+CREATE INDEX IF NOT EXISTS idx_service_add_ons_deleted_at ON service_add_ons(deleted_at);
+
+-- Units of a fixed price service a customer can book in one go, e.g. 4 ACs
+ALTER TABLE services ADD COLUMN IF NOT EXISTS max_quantity INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS price_breakdown JSONB;
+
+-- +goose Down
+ALTER TABLE bookings DROP COLUMN IF EXISTS price_breakdown;
+ALTER TABLE services DROP COLUMN IF EXISTS max_quantity;
+DROP TABLE IF EXISTS service_add_ons;
+DROP TABLE IF EXISTS service_variants;
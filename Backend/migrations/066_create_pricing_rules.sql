@@ -0,0 +1,60 @@
+-- +goose Up
+-- Admin-managed surge pricing rules for fixed price services, and the holiday calendar they can target
+
+CREATE TABLE IF NOT EXISTS pricing_rules (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    priority INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+
+    -- Conditions (empty = any)
+    days_of_week INTEGER[],
+    start_time VARCHAR(5),
+    end_time VARCHAR(5),
+    start_date DATE,
+    end_date DATE,
+    holidays_only BOOLEAN NOT NULL DEFAULT FALSE,
+    cities TEXT[],
+    pincodes TEXT[],
+    service_ids BIGINT[],
+    category_ids BIGINT[],
+    min_utilisation DECIMAL,
+
+    -- Adjustment
+    adjustment_type VARCHAR(20) NOT NULL,
+    adjustment_value DECIMAL NOT NULL,
+    max_uplift DECIMAL,
+
+    created_by BIGINT REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pricing_rules_is_active ON pricing_rules(is_active);
+CREATE INDEX IF NOT EXISTS idx_pricing_rules_deleted_at ON pricing_rules(deleted_at);
+
+CREATE TABLE IF NOT EXISTS holidays (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    date DATE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    cities TEXT[]
+);
+
+CREATE INDEX IF NOT EXISTS idx_holidays_date ON holidays(date);
+CREATE INDEX IF NOT EXISTS idx_holidays_deleted_at ON holidays(deleted_at);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS pricing_rule_id BIGINT REFERENCES pricing_rules(id);
+CREATE INDEX IF NOT EXISTS idx_bookings_pricing_rule_id ON bookings(pricing_rule_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_bookings_pricing_rule_id;
+ALTER TABLE bookings DROP COLUMN IF EXISTS pricing_rule_id;
+DROP TABLE IF EXISTS holidays;
+DROP TABLE IF EXISTS pricing_rules;
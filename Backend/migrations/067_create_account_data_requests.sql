@@ -0,0 +1,51 @@
+-- +goose Up
+-- User data exports, and account deletions that anonymise personal data after a grace period
+-- instead of deleting booking and payment records
+
+CREATE TABLE IF NOT EXISTS data_exports (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    file_url TEXT,
+    file_size BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports(user_id);
+CREATE INDEX IF NOT EXISTS idx_data_exports_status ON data_exports(status);
+CREATE INDEX IF NOT EXISTS idx_data_exports_deleted_at ON data_exports(deleted_at);
+
+CREATE TABLE IF NOT EXISTS account_deletion_requests (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    reason TEXT NOT NULL DEFAULT '',
+    scheduled_for TIMESTAMPTZ NOT NULL,
+    cancelled_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    pseudonym VARCHAR(64)
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_deletion_requests_user_id ON account_deletion_requests(user_id);
+CREATE INDEX IF NOT EXISTS idx_account_deletion_requests_due ON account_deletion_requests(status, scheduled_for);
+CREATE INDEX IF NOT EXISTS idx_account_deletion_requests_deleted_at ON account_deletion_requests(deleted_at);
+-- One open deletion per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_account_deletion_requests_scheduled
+    ON account_deletion_requests(user_id) WHERE status = 'scheduled' AND deleted_at IS NULL;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS anonymised_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS anonymised_at;
+DROP TABLE IF EXISTS account_deletion_requests;
+DROP TABLE IF EXISTS data_exports;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Live worker tracking: the latest ETA to the customer and when the worker arrived
+
+ALTER TABLE worker_locations ADD COLUMN IF NOT EXISTS distance_meters INTEGER;
+ALTER TABLE worker_locations ADD COLUMN IF NOT EXISTS eta_seconds INTEGER;
+ALTER TABLE worker_locations ADD COLUMN IF NOT EXISTS eta_updated_at TIMESTAMPTZ;
+
+ALTER TABLE worker_assignments ADD COLUMN IF NOT EXISTS arrived_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE worker_assignments DROP COLUMN IF EXISTS arrived_at;
+ALTER TABLE worker_locations DROP COLUMN IF EXISTS eta_updated_at;
+ALTER TABLE worker_locations DROP COLUMN IF EXISTS eta_seconds;
+ALTER TABLE worker_locations DROP COLUMN IF EXISTS distance_meters;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Customer-held PINs the worker must enter to start and to complete a service
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS start_pin VARCHAR(6);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS completion_pin VARCHAR(6);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS pin_failed_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS pin_locked_until TIMESTAMPTZ;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS completion_forced_by BIGINT REFERENCES users(id);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS completion_reason TEXT;
+
+-- Bookings that can still be started or completed get their PINs now
+UPDATE bookings
+SET start_pin = LPAD(FLOOR(RANDOM() * 10000)::TEXT, 4, '0'),
+    completion_pin = LPAD(FLOOR(RANDOM() * 10000)::TEXT, 4, '0')
+WHERE start_pin IS NULL
+  AND status NOT IN ('completed', 'cancelled', 'rejected');
+
+-- +goose Down
+ALTER TABLE bookings DROP COLUMN IF EXISTS completion_reason;
+ALTER TABLE bookings DROP COLUMN IF EXISTS completion_forced_by;
+ALTER TABLE bookings DROP COLUMN IF EXISTS pin_locked_until;
+ALTER TABLE bookings DROP COLUMN IF EXISTS pin_failed_attempts;
+ALTER TABLE bookings DROP COLUMN IF EXISTS completion_pin;
+ALTER TABLE bookings DROP COLUMN IF EXISTS start_pin;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- Offers of a booking to workers, with how each worker responded, for auto-reassignment and
+-- worker acceptance rates
+
+CREATE TABLE IF NOT EXISTS assignment_offers (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    booking_id BIGINT NOT NULL REFERENCES bookings(id),
+    assignment_id BIGINT NOT NULL REFERENCES worker_assignments(id),
+    worker_id BIGINT NOT NULL REFERENCES users(id),
+    status TEXT NOT NULL DEFAULT 'offered',
+    offered_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ,
+    reason TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_offers_deleted_at ON assignment_offers(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_assignment_offers_booking_id ON assignment_offers(booking_id);
+CREATE INDEX IF NOT EXISTS idx_assignment_offers_worker_offered_at ON assignment_offers(worker_id, offered_at);
+
+-- Deadline for the assigned worker to accept
+ALTER TABLE worker_assignments ADD COLUMN IF NOT EXISTS accept_by TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_worker_assignments_accept_by ON worker_assignments(accept_by) WHERE status = 'assigned';
+
+-- Bookings handed to admins after automatic reassignment gave up
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS assignment_escalated_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE bookings DROP COLUMN IF EXISTS assignment_escalated_at;
+DROP INDEX IF EXISTS idx_worker_assignments_accept_by;
+ALTER TABLE worker_assignments DROP COLUMN IF EXISTS accept_by;
+DROP TABLE IF EXISTS assignment_offers;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Banking details and identity documents are encrypted at the field level, so these columns hold
+-- ciphertext instead of JSON. Run cmd/encrypt-fields after this migration to encrypt existing rows.
+ALTER TABLE workers ALTER COLUMN banking_info TYPE TEXT USING banking_info::text;
+ALTER TABLE workers ALTER COLUMN documents TYPE TEXT USING documents::text;
+ALTER TABLE brokers ALTER COLUMN documents TYPE TEXT USING documents::text;
+
+-- +goose Down
+-- Run cmd/encrypt-fields -decrypt before rolling back; encrypted values cannot be cast to JSONB
+ALTER TABLE brokers ALTER COLUMN documents TYPE JSONB USING NULLIF(documents, '')::jsonb;
+ALTER TABLE workers ALTER COLUMN documents TYPE JSONB USING NULLIF(documents, '')::jsonb;
+ALTER TABLE workers ALTER COLUMN banking_info TYPE JSONB USING NULLIF(banking_info, '')::jsonb;
@@ -0,0 +1,37 @@
+-- +goose Up
+-- Per-document KYC review for worker and broker applications. user_documents was previously only
+-- created by GORM auto-migration, so it is created here if it does not exist yet.
+
+CREATE TABLE IF NOT EXISTS user_documents (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    document_type TEXT NOT NULL,
+    file_url TEXT NOT NULL,
+    file_name TEXT,
+    file_size BIGINT DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_documents_deleted_at ON user_documents(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_user_documents_user_id ON user_documents(user_id);
+
+-- Review state of each document
+ALTER TABLE user_documents ADD COLUMN IF NOT EXISTS verification_status TEXT NOT NULL DEFAULT 'pending';
+ALTER TABLE user_documents ADD COLUMN IF NOT EXISTS rejection_reason TEXT;
+ALTER TABLE user_documents ADD COLUMN IF NOT EXISTS reviewed_by BIGINT REFERENCES users(id);
+ALTER TABLE user_documents ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+
+-- Documents such as police verification are only valid for a limited time
+ALTER TABLE user_documents ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_user_documents_expires_at ON user_documents(expires_at) WHERE expires_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_user_documents_expires_at;
+ALTER TABLE user_documents DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE user_documents DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE user_documents DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE user_documents DROP COLUMN IF EXISTS rejection_reason;
+ALTER TABLE user_documents DROP COLUMN IF EXISTS verification_status;
@@ -0,0 +1,52 @@
+-- +goose Up
+-- Buyer enquiries on property listings, tracked as leads by the listing owner or broker
+
+CREATE TABLE IF NOT EXISTS property_enquiries (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    property_id BIGINT NOT NULL REFERENCES properties(id),
+    buyer_id BIGINT NOT NULL REFERENCES users(id),
+    owner_id BIGINT NOT NULL REFERENCES users(id),
+    message TEXT NOT NULL,
+    budget DECIMAL(14,2),
+    preferred_visit_slots JSONB,
+    status TEXT NOT NULL DEFAULT 'new',
+    status_changed_at TIMESTAMPTZ,
+    owner_notes TEXT,
+    closed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_property_enquiries_deleted_at ON property_enquiries(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_property_enquiries_property_id ON property_enquiries(property_id);
+CREATE INDEX IF NOT EXISTS idx_property_enquiries_buyer_id ON property_enquiries(buyer_id);
+CREATE INDEX IF NOT EXISTS idx_property_enquiries_owner_status ON property_enquiries(owner_id, status);
+
+-- Site visits booked on an enquiry
+CREATE TABLE IF NOT EXISTS property_site_visits (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    enquiry_id BIGINT NOT NULL REFERENCES property_enquiries(id),
+    property_id BIGINT NOT NULL REFERENCES properties(id),
+    buyer_id BIGINT NOT NULL REFERENCES users(id),
+    owner_id BIGINT NOT NULL REFERENCES users(id),
+    scheduled_at TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL DEFAULT 'scheduled',
+    notes TEXT,
+    scheduled_by BIGINT REFERENCES users(id),
+    cancelled_by BIGINT REFERENCES users(id),
+    reminder_sent_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_property_site_visits_deleted_at ON property_site_visits(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_property_site_visits_enquiry_id ON property_site_visits(enquiry_id);
+CREATE INDEX IF NOT EXISTS idx_property_site_visits_scheduled_at ON property_site_visits(scheduled_at) WHERE status = 'scheduled';
+
+-- +goose Down
+DROP TABLE IF EXISTS property_site_visits;
+DROP TABLE IF EXISTS property_enquiries;
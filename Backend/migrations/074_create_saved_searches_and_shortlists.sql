@@ -0,0 +1,58 @@
+-- +goose Up
+-- Saved property searches with alerts for newly approved listings that match them
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    name TEXT NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}',
+    alert_frequency TEXT NOT NULL DEFAULT 'instant',
+    notify_push BOOLEAN NOT NULL DEFAULT TRUE,
+    notify_email BOOLEAN NOT NULL DEFAULT FALSE,
+    last_digest_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_deleted_at ON saved_searches(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_user_id ON saved_searches(user_id);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_alert_frequency ON saved_searches(alert_frequency) WHERE deleted_at IS NULL;
+
+-- Listings matched by a saved search. A listing is only alerted about once per search; matches of
+-- daily searches wait here until the digest is sent.
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    saved_search_id BIGINT NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    property_id BIGINT NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    notified_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_search_matches_deleted_at ON saved_search_matches(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_search_matches_search_property ON saved_search_matches(saved_search_id, property_id);
+CREATE INDEX IF NOT EXISTS idx_saved_search_matches_pending ON saved_search_matches(saved_search_id) WHERE notified_at IS NULL;
+
+-- Properties and projects shortlisted by users
+CREATE TABLE IF NOT EXISTS shortlists (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    item_type TEXT NOT NULL CHECK (item_type IN ('property', 'project')),
+    item_id BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_shortlists_deleted_at ON shortlists(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shortlists_user_item ON shortlists(user_id, item_type, item_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS shortlists;
+DROP TABLE IF EXISTS saved_search_matches;
+DROP TABLE IF EXISTS saved_searches;
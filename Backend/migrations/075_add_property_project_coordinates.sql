@@ -0,0 +1,27 @@
+-- +goose Up
+-- Coordinates of properties and projects for radius and bounding-box search. Radius queries use
+-- the earthdistance extension: earth_box() over a GiST index on ll_to_earth() narrows the rows,
+-- earth_distance() gives the exact distance.
+
+CREATE EXTENSION IF NOT EXISTS cube;
+CREATE EXTENSION IF NOT EXISTS earthdistance;
+
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+CREATE INDEX IF NOT EXISTS idx_properties_earth_location ON properties USING GIST (ll_to_earth(latitude, longitude)) WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_properties_lat_lng ON properties(latitude, longitude) WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_projects_earth_location ON projects USING GIST (ll_to_earth(latitude, longitude)) WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_projects_lat_lng ON projects(latitude, longitude) WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_projects_lat_lng;
+DROP INDEX IF EXISTS idx_projects_earth_location;
+DROP INDEX IF EXISTS idx_properties_lat_lng;
+DROP INDEX IF EXISTS idx_properties_earth_location;
+ALTER TABLE projects DROP COLUMN IF EXISTS longitude;
+ALTER TABLE projects DROP COLUMN IF EXISTS latitude;
+ALTER TABLE properties DROP COLUMN IF EXISTS longitude;
+ALTER TABLE properties DROP COLUMN IF EXISTS latitude;
@@ -0,0 +1,103 @@
+-- +goose Up
+-- Listings that pass their expiry date are now marked expired instead of sold or rented, and owners
+-- can renew them. Owners can also buy boosts and featured slots. priority_score stays the sort key:
+-- it is base_priority_score plus what is left of an active boost, which decays linearly until
+-- boosted_until. Listings the old expiry job already marked sold or rented are left as they are:
+-- nothing tells them apart from listings that really were sold or rented.
+
+ALTER TABLE properties DROP CONSTRAINT IF EXISTS chk_properties_status;
+ALTER TABLE properties ADD CONSTRAINT chk_properties_status
+    CHECK (status IN ('available', 'sold', 'rented', 'expired'));
+
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS base_priority_score INTEGER DEFAULT 0;
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS renewed_at TIMESTAMPTZ;
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS boost_points INTEGER DEFAULT 0;
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS boost_started_at TIMESTAMPTZ;
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS boosted_until TIMESTAMPTZ;
+ALTER TABLE properties ADD COLUMN IF NOT EXISTS featured_until TIMESTAMPTZ;
+
+UPDATE properties SET base_priority_score = priority_score;
+
+CREATE INDEX IF NOT EXISTS idx_properties_boosted_until ON properties(boosted_until) WHERE boosted_until IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_properties_featured_until ON properties(featured_until) WHERE featured_until IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_properties_status_expires_at ON properties(status, expires_at);
+
+-- Boosts and featured slots bought for a listing
+CREATE TABLE IF NOT EXISTS property_promotions (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+
+    property_id BIGINT NOT NULL REFERENCES properties(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    type TEXT NOT NULL CHECK (type IN ('boost', 'featured')),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'active', 'cancelled')),
+    payment_method TEXT NOT NULL CHECK (payment_method IN ('wallet', 'razorpay', 'subscription')),
+    payment_id BIGINT REFERENCES payments(id),
+    amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    duration_days INTEGER NOT NULL,
+    boost_points INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_property_promotions_deleted_at ON property_promotions(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_property_promotions_property_id ON property_promotions(property_id);
+CREATE INDEX IF NOT EXISTS idx_property_promotions_user_method ON property_promotions(user_id, payment_method, created_at);
+
+-- Promotions paid through Razorpay or the wallet; referral_reward was missing from the list
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS chk_payments_type;
+
+ALTER TABLE payments ADD CONSTRAINT chk_payments_type
+CHECK (type IN (
+    'booking',
+    'subscription',
+    'wallet_recharge',
+    'wallet_debit',
+    'refund',
+    'segment_pay',
+    'quote',
+    'manual',
+    'worker_earnings',
+    'worker_withdrawal',
+    'referral_reward',
+    'property_promotion'
+));
+
+-- +goose Down
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS chk_payments_type;
+
+ALTER TABLE payments ADD CONSTRAINT chk_payments_type
+CHECK (type IN (
+    'booking',
+    'subscription',
+    'wallet_recharge',
+    'wallet_debit',
+    'refund',
+    'segment_pay',
+    'quote',
+    'manual',
+    'worker_earnings',
+    'worker_withdrawal',
+    'referral_reward'
+));
+
+DROP TABLE IF EXISTS property_promotions;
+
+DROP INDEX IF EXISTS idx_properties_status_expires_at;
+DROP INDEX IF EXISTS idx_properties_featured_until;
+DROP INDEX IF EXISTS idx_properties_boosted_until;
+ALTER TABLE properties DROP COLUMN IF EXISTS featured_until;
+ALTER TABLE properties DROP COLUMN IF EXISTS boosted_until;
+ALTER TABLE properties DROP COLUMN IF EXISTS boost_started_at;
+ALTER TABLE properties DROP COLUMN IF EXISTS boost_points;
+ALTER TABLE properties DROP COLUMN IF EXISTS renewed_at;
+ALTER TABLE properties DROP COLUMN IF EXISTS base_priority_score;
+
+UPDATE properties SET status = CASE WHEN listing_type = 'sale' THEN 'sold' ELSE 'rented' END
+WHERE status = 'expired';
+
+ALTER TABLE properties DROP CONSTRAINT IF EXISTS chk_properties_status;
+ALTER TABLE properties ADD CONSTRAINT chk_properties_status
+    CHECK (status IN ('available', 'sold', 'rented'));
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Secret handed to the searcher with the search ID, so that only they can record clicks on it
+
+ALTER TABLE search_query_logs ADD COLUMN IF NOT EXISTS click_token VARCHAR(64);
+
+-- +goose Down
+ALTER TABLE search_query_logs DROP COLUMN IF EXISTS click_token;
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DataExportStatus represents the state of a user data export
+type DataExportStatus string
+
+const (
+	DataExportStatusPending    DataExportStatus = "pending"
+	DataExportStatusProcessing DataExportStatus = "processing"
+	DataExportStatusCompleted  DataExportStatus = "completed"
+	DataExportStatusFailed     DataExportStatus = "failed"
+	DataExportStatusExpired    DataExportStatus = "expired" // Archive deleted after the retention period
+)
+
+// DataExport is a user's request for an archive of their data
+type DataExport struct {
+	gorm.Model
+	UserID      uint             `json:"user_id" gorm:"not null;index"`
+	Status      DataExportStatus `json:"status" gorm:"not null;default:'pending'"`
+	FileURL     *string          `json:"-"` // Only handed out as a signed download URL
+	FileSize    int64            `json:"file_size" gorm:"default:0"`
+	Error       string           `json:"error,omitempty"`
+	CompletedAt *time.Time       `json:"completed_at"`
+	ExpiresAt   *time.Time       `json:"expires_at"`
+}
+
+// TableName returns the table name for DataExport
+func (DataExport) TableName() string {
+	return "data_exports"
+}
+
+// AccountDeletionStatus represents the state of an account deletion request
+type AccountDeletionStatus string
+
+const (
+	AccountDeletionStatusScheduled AccountDeletionStatus = "scheduled"
+	AccountDeletionStatusCancelled AccountDeletionStatus = "cancelled"
+	AccountDeletionStatusCompleted AccountDeletionStatus = "completed"
+)
+
+// AccountDeletionRequest schedules the anonymisation of a user's personal data. Booking and payment
+// records are kept and stay linked to the anonymised user, who is then only known by Pseudonym.
+type AccountDeletionRequest struct {
+	gorm.Model
+	UserID       uint                  `json:"user_id" gorm:"not null;index"`
+	Status       AccountDeletionStatus `json:"status" gorm:"not null;default:'scheduled'"`
+	Reason       string                `json:"reason"`
+	ScheduledFor time.Time             `json:"scheduled_for" gorm:"not null"`
+	CancelledAt  *time.Time            `json:"cancelled_at"`
+	CompletedAt  *time.Time            `json:"completed_at"`
+	Pseudonym    *string               `json:"-"`
+}
+
+// TableName returns the table name for AccountDeletionRequest
+func (AccountDeletionRequest) TableName() string {
+	return "account_deletion_requests"
+}
+
+// DeleteAccountRequest represents the request structure for deleting an account
+type DeleteAccountRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=1000"`
+}
+
+// UserDataArchive is the content of a data export, one file per field
+type UserDataArchive struct {
+	Profile              *User                       `json:"profile"`
+	Addresses            []Address                   `json:"addresses"`
+	Bookings             []Booking                   `json:"bookings"`
+	Payments             []Payment                   `json:"payments"`
+	WalletTransactions   []Payment                   `json:"wallet_transactions"`
+	ChatMessages         []ChatMessage               `json:"chat_messages"`
+	ConversationMessages []SimpleConversationMessage `json:"conversation_messages"`
+	Properties           []Property                  `json:"properties"`
+}
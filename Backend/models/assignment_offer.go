@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AssignmentOfferStatus represents how a worker responded to a booking offer
+type AssignmentOfferStatus string
+
+const (
+	AssignmentOfferStatusOffered   AssignmentOfferStatus = "offered"
+	AssignmentOfferStatusAccepted  AssignmentOfferStatus = "accepted"
+	AssignmentOfferStatusRejected  AssignmentOfferStatus = "rejected"
+	AssignmentOfferStatusExpired   AssignmentOfferStatus = "expired"   // Not accepted within the acceptance SLA
+	AssignmentOfferStatusWithdrawn AssignmentOfferStatus = "withdrawn" // Given to another worker, or the booking was cancelled, before the worker answered
+)
+
+// AssignmentOffer records a booking being offered to a worker. A booking's assignment is reused
+// when it moves to another worker, so offers keep the history of who was asked.
+type AssignmentOffer struct {
+	gorm.Model
+	BookingID    uint                  `json:"booking_id" gorm:"not null;index"`
+	AssignmentID uint                  `json:"assignment_id" gorm:"not null"`
+	WorkerID     uint                  `json:"worker_id" gorm:"not null"`
+	Status       AssignmentOfferStatus `json:"status" gorm:"not null;default:'offered'"`
+	OfferedAt    time.Time             `json:"offered_at" gorm:"not null"`
+	ExpiresAt    time.Time             `json:"expires_at" gorm:"not null"`
+	RespondedAt  *time.Time            `json:"responded_at"`
+	Reason       string                `json:"reason,omitempty"`
+}
+
+// TableName returns the table name for AssignmentOffer
+func (AssignmentOffer) TableName() string {
+	return "assignment_offers"
+}
+
+// WorkerAcceptanceStats summarises how a worker responded to the bookings offered to them
+type WorkerAcceptanceStats struct {
+	WorkerID       uint    `json:"worker_id"`
+	Offered        int64   `json:"offered"`
+	Accepted       int64   `json:"accepted"`
+	Rejected       int64   `json:"rejected"`
+	Expired        int64   `json:"expired"`
+	AcceptanceRate float64 `json:"acceptance_rate"`
+}
+
+// RankingScore is the acceptance rate used to rank workers for new offers. It is smoothed so that
+// workers with few offers start in the middle instead of at either extreme.
+func (s WorkerAcceptanceStats) RankingScore() float64 {
+	return (float64(s.Accepted) + 1) / (float64(s.Offered) + 2)
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
 	"time"
 
 	"gorm.io/gorm"
@@ -79,6 +82,14 @@ type Booking struct {
 	ActualEndTime    *time.Time    `json:"actual_end_time"`
 	ActualDurationMinutes *int     `json:"actual_duration_minutes"`
 	
+	// Service PINs (held by the customer, entered by the worker)
+	StartPIN          *string      `json:"-"`
+	CompletionPIN     *string      `json:"-"`
+	PINFailedAttempts int          `json:"-" gorm:"column:pin_failed_attempts;default:0"`
+	PINLockedUntil    *time.Time   `json:"-" gorm:"column:pin_locked_until"`
+	CompletionForcedBy *uint       `json:"completion_forced_by,omitempty"` // Admin who force-completed the service
+	CompletionReason  string       `json:"completion_reason,omitempty"`
+	
 	// Service Details
 	Address          *string       `json:"address" gorm:"type:jsonb"` // Store complete address object as JSON
 	Description      string        `json:"description"`
@@ -89,6 +100,9 @@ type Booking struct {
 	// Hold Management
 	HoldExpiresAt    *time.Time    `json:"hold_expires_at" gorm:"index"`
 	
+	// Assignment
+	AssignmentEscalatedAt *time.Time `json:"assignment_escalated_at,omitempty"` // Automatic reassignment gave up and an admin must assign a worker
+	
 	// Quote Management (for inquiry bookings)
 	QuoteAmount      *float64      `json:"quote_amount"`                    // Final quote amount
 	QuoteNotes       string        `json:"quote_notes"`                     // Admin notes with quote
@@ -96,7 +110,13 @@ type Booking struct {
 	QuoteProvidedAt  *time.Time    `json:"quote_provided_at"`               // When quote was provided
 	QuoteAcceptedAt  *time.Time    `json:"quote_accepted_at"`               // When customer accepted quote
 	QuoteExpiresAt   *time.Time    `json:"quote_expires_at"`               // Quote expiration date
+	QuoteExpiryWarningSentAt *time.Time `json:"quote_expiry_warning_sent_at"` // When the customer was warned the quote is about to expire
 	QuoteDuration    *string       `json:"quote_duration"`                  // Service duration specified in quote
+	AcceptedQuoteRevisionID *uint  `json:"accepted_quote_revision_id"`     // Locked quote revision the customer accepted
+
+	// Pricing (fixed price bookings)
+	PriceBreakdown   *ServicePriceBreakdown `json:"price_breakdown,omitempty" gorm:"type:jsonb"` // Variant, quantity and add-ons chosen at booking time
+	PricingRuleID    *uint         `json:"pricing_rule_id" gorm:"index"`          // Surge pricing rule applied at booking time
 	
 	// Relationships
 	User             User          `json:"user" gorm:"foreignKey:UserID"`
@@ -113,6 +133,49 @@ func (Booking) TableName() string {
 	return "bookings"
 }
 
+// BeforeCreate is a GORM hook that runs before creating a booking
+func (b *Booking) BeforeCreate(tx *gorm.DB) error {
+	// Generate the PINs the customer gives the worker to start and complete the service
+	if b.StartPIN == nil {
+		pin, err := GenerateServicePIN()
+		if err != nil {
+			return err
+		}
+		b.StartPIN = &pin
+	}
+	if b.CompletionPIN == nil {
+		pin, err := GenerateServicePIN()
+		if err != nil {
+			return err
+		}
+		b.CompletionPIN = &pin
+	}
+	return nil
+}
+
+// GenerateServicePIN generates a random 4 digit service PIN
+func GenerateServicePIN() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(10000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate service PIN: %w", err)
+	}
+	return fmt.Sprintf("%04d", n.Int64()), nil
+}
+
+// ServicePINsResponse shows the customer the PINs to give the worker. Each PIN is only shown while
+// it can still be used.
+type ServicePINsResponse struct {
+	BookingID     uint    `json:"booking_id"`
+	StartPIN      *string `json:"start_pin,omitempty"`
+	CompletionPIN *string `json:"completion_pin,omitempty"`
+}
+
+// ForceCompleteBookingRequest represents the request structure for an admin completing a service
+// without the completion PIN
+type ForceCompleteBookingRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
 // BookingAddress represents the address structure for bookings
 type BookingAddress struct {
 	Name        string  `json:"name"`
@@ -137,6 +200,8 @@ type CreateBookingRequest struct {
 	ContactPerson        string          `json:"contact_person"`
 	ContactPhone         string          `json:"contact_phone"`
 	SpecialInstructions  string          `json:"special_instructions"`
+	CouponCode           string          `json:"coupon_code"` // Optional promo code (fixed price services)
+	ServiceSelection                     // Optional variant, quantity and add-ons (fixed price services)
 }
 
 // CreateBookingWithPaymentRequest represents the request structure for creating a booking with payment
@@ -223,6 +288,7 @@ type OptimizedBookingResponse struct {
 	QuoteAcceptedAt       *time.Time              `json:"quote_accepted_at,omitempty"`
 	QuoteExpiresAt        *time.Time              `json:"quote_expires_at,omitempty"`
 	QuoteDuration         *string                 `json:"quote_duration,omitempty"`
+	PriceBreakdown        *ServicePriceBreakdown  `json:"price_breakdown,omitempty"`
 	
 	Service               *OptimizedServiceInfo   `json:"service"`
 	User                  *OptimizedUserInfo      `json:"user"`
@@ -301,6 +367,7 @@ type DetailedBookingResponse struct {
 	QuoteAcceptedAt       *time.Time              `json:"quote_accepted_at,omitempty"`
 	QuoteExpiresAt        *time.Time              `json:"quote_expires_at,omitempty"`
 	QuoteDuration         *string                 `json:"quote_duration,omitempty"`
+	PriceBreakdown        *ServicePriceBreakdown  `json:"price_breakdown,omitempty"`
 	
 	Service               *DetailedServiceInfo    `json:"service"`
 	User                  *DetailedUserInfo       `json:"user"`
@@ -470,12 +537,15 @@ type ProvideQuoteRequest struct {
 	Notes       string                    `json:"notes"`
 	Segments    []PaymentSegmentRequest   `json:"segments" binding:"required,min=1"`
 	Duration    *string                   `json:"duration"` // Optional service duration for single segment quotes
+	LineItems   []QuoteLineItemRequest    `json:"line_items,omitempty" binding:"omitempty,dive"` // Optional breakdown; must add up to the segments total
 }
 
 // UpdateQuoteRequest represents the request to update an existing quote
 type UpdateQuoteRequest struct {
 	Notes       string                    `json:"notes"`
 	Segments    []PaymentSegmentRequest   `json:"segments" binding:"required,min=1"`
+	Duration    *string                   `json:"duration"`
+	LineItems   []QuoteLineItemRequest    `json:"line_items,omitempty" binding:"omitempty,dive"`
 }
 
 // AcceptQuoteRequest represents the request to accept a quote
@@ -503,6 +573,7 @@ type CreateQuotePaymentRequest struct {
 	
 	// For segmented payments
 	SegmentNumber *int    `json:"segment_number,omitempty"` // Specific segment to pay (optional)
+	CouponCode    string  `json:"coupon_code,omitempty"`    // Optional promo code
 }
 
 // VerifyQuotePaymentRequest represents the request to verify payment for quote acceptance
@@ -519,6 +590,13 @@ type WalletPaymentRequest struct {
 	Amount        float64 `json:"amount" binding:"required,min=0"`   // Quote amount to pay
 }
 
+// ExtendQuoteValidityRequest represents the admin request to extend a quote's validity
+type ExtendQuoteValidityRequest struct {
+	Days      *int       `json:"days" binding:"omitempty,min=1,max=90"` // Extend from the current expiry (or now, if already past)
+	ExpiresAt *time.Time `json:"expires_at"`                             // Or set an explicit expiry
+	Notes     string     `json:"notes"`
+}
+
 // Quote represents a quote for notification purposes
 type Quote struct {
 	ID         uint      `json:"id"`
@@ -536,6 +614,10 @@ type QuoteInfo struct {
 	ExpiresAt      *time.Time `json:"expires_at"`
 	IsExpired      bool       `json:"is_expired"`
 	DaysUntilExpiry *int      `json:"days_until_expiry"`
+	Version        int        `json:"version,omitempty"`
+	RevisionID     *uint      `json:"revision_id,omitempty"`
+	LineItems      []QuoteLineItem `json:"line_items,omitempty"`
+	AwaitingRevision bool     `json:"awaiting_revision"` // Customer countered or asked for a revision
 }
 
 // GetPaymentProgress calculates payment progress for the booking
@@ -560,6 +642,10 @@ func (b *Booking) GetPaymentProgress() *PaymentProgress {
 			PaidAt:        segment.PaidAt,
 			Notes:         segment.Notes,
 			PaymentID:     segment.PaymentID,
+			DueTrigger:     segment.DueTrigger,
+			DueAfterDays:   segment.DueAfterDays,
+			DueAt:          segment.DueAt,
+			RequiredBefore: segment.RequiredBefore,
 		}
 
 		if segment.Status == PaymentSegmentStatusPaid {
@@ -13,7 +13,7 @@ type Broker struct {
 	// JSON Objects
 	ContactInfo        string  `json:"contact_info"`        // JSONB: {"alternative_number": "string"}
 	Address            string  `json:"address"`             // JSONB: {"street": "string", "city": "string", "state": "string", "pincode": "string", "landmark": "string"}
-	Documents          string  `json:"documents"`           // JSONB: {"aadhar_card": "cloudinary_url", "pan_card": "cloudinary_url", "profile_pic": "cloudinary_url"}
+	Documents          string  `json:"documents" gorm:"serializer:encrypted"` // Encrypted JSON: {"aadhar_card": "cloudinary_url", "pan_card": "cloudinary_url", "profile_pic": "cloudinary_url"}
 	
 	// Broker Specific
 	License            string  `json:"license" gorm:"uniqueIndex"`
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// CouponDiscountType represents how a coupon discount is calculated
+type CouponDiscountType string
+
+const (
+	CouponDiscountPercentage CouponDiscountType = "percentage" // Percentage of the order amount
+	CouponDiscountFlat       CouponDiscountType = "flat"       // Fixed amount off the order
+)
+
+// CouponScope represents what a coupon can be applied to
+type CouponScope string
+
+const (
+	CouponScopeAll          CouponScope = "all"
+	CouponScopeBooking      CouponScope = "booking"      // Fixed price service bookings
+	CouponScopeQuote        CouponScope = "quote"        // Quote payments for inquiry bookings
+	CouponScopeSubscription CouponScope = "subscription" // Subscription purchases
+)
+
+// CouponRedemptionStatus represents the status of a coupon redemption
+type CouponRedemptionStatus string
+
+const (
+	CouponRedemptionStatusReserved CouponRedemptionStatus = "reserved" // Applied to a payment that is not yet completed
+	CouponRedemptionStatusApplied  CouponRedemptionStatus = "applied"  // Payment completed with the discount
+	CouponRedemptionStatusReleased CouponRedemptionStatus = "released" // Payment abandoned; the use was given back
+)
+
+// Coupon represents a promo code that discounts bookings, quotes or subscriptions
+type Coupon struct {
+	gorm.Model
+	Code        string `json:"code" gorm:"uniqueIndex;not null"` // Stored upper case
+	Description string `json:"description"`
+
+	// Discount
+	DiscountType   CouponDiscountType `json:"discount_type" gorm:"not null"`
+	DiscountValue  float64            `json:"discount_value" gorm:"not null"`
+	MaxDiscount    *float64           `json:"max_discount"`     // Cap for percentage coupons
+	MinOrderAmount float64            `json:"min_order_amount"` // Minimum amount before discount
+
+	// Limits
+	Scope        CouponScope `json:"scope" gorm:"default:'all'"`
+	UsageLimit   *int        `json:"usage_limit"`                     // Total uses across all users (nil = unlimited)
+	PerUserLimit int         `json:"per_user_limit" gorm:"default:1"` // Uses per user (0 = unlimited)
+
+	// Eligibility (empty = any)
+	CategoryIDs      pq.Int64Array  `json:"category_ids" gorm:"type:bigint[]"`
+	ServiceIDs       pq.Int64Array  `json:"service_ids" gorm:"type:bigint[]"`
+	Cities           pq.StringArray `json:"cities" gorm:"type:text[]"`
+	FirstBookingOnly bool           `json:"first_booking_only" gorm:"default:false"`
+
+	// Validity
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until"`
+	IsActive   bool       `json:"is_active" gorm:"default:true"`
+
+	CreatedBy uint `json:"created_by"`
+}
+
+// TableName returns the table name for Coupon
+func (Coupon) TableName() string {
+	return "coupons"
+}
+
+// CouponRedemption records a coupon applied to a payment
+type CouponRedemption struct {
+	gorm.Model
+	CouponID uint                   `json:"coupon_id" gorm:"not null;index"`
+	Code     string                 `json:"code" gorm:"not null"`
+	UserID   uint                   `json:"user_id" gorm:"not null;index"`
+	Scope    CouponScope            `json:"scope" gorm:"not null"`
+	Status   CouponRedemptionStatus `json:"status" gorm:"not null"`
+
+	// What the coupon was applied to
+	RelatedEntityType string `json:"related_entity_type"` // "booking" or "subscription"
+	RelatedEntityID   uint   `json:"related_entity_id"`
+	PaymentID         *uint  `json:"payment_id"`
+
+	// Amounts
+	OriginalAmount float64 `json:"original_amount"`
+	DiscountAmount float64 `json:"discount_amount"`
+	FinalAmount    float64 `json:"final_amount"`
+
+	AppliedAt  *time.Time `json:"applied_at"`
+	ReleasedAt *time.Time `json:"released_at"`
+
+	// Relationships
+	Coupon *Coupon `json:"coupon,omitempty" gorm:"foreignKey:CouponID"`
+	User   *User   `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// TableName returns the table name for CouponRedemption
+func (CouponRedemption) TableName() string {
+	return "coupon_redemptions"
+}
+
+// CreateCouponRequest represents the request for creating a coupon
+type CreateCouponRequest struct {
+	Code             string             `json:"code" binding:"required,min=3,max=32"`
+	Description      string             `json:"description"`
+	DiscountType     CouponDiscountType `json:"discount_type" binding:"required,oneof=percentage flat"`
+	DiscountValue    float64            `json:"discount_value" binding:"required,gt=0"`
+	MaxDiscount      *float64           `json:"max_discount" binding:"omitempty,gt=0"`
+	MinOrderAmount   float64            `json:"min_order_amount" binding:"min=0"`
+	Scope            CouponScope        `json:"scope" binding:"omitempty,oneof=all booking quote subscription"`
+	UsageLimit       *int               `json:"usage_limit" binding:"omitempty,min=1"`
+	PerUserLimit     *int               `json:"per_user_limit" binding:"omitempty,min=0"`
+	CategoryIDs      []int64            `json:"category_ids"`
+	ServiceIDs       []int64            `json:"service_ids"`
+	Cities           []string           `json:"cities"`
+	FirstBookingOnly bool               `json:"first_booking_only"`
+	ValidFrom        *time.Time         `json:"valid_from"`
+	ValidUntil       *time.Time         `json:"valid_until"`
+	IsActive         *bool              `json:"is_active"`
+}
+
+// UpdateCouponRequest represents the request for updating a coupon
+type UpdateCouponRequest struct {
+	Description      *string    `json:"description"`
+	DiscountValue    *float64   `json:"discount_value" binding:"omitempty,gt=0"`
+	MaxDiscount      *float64   `json:"max_discount" binding:"omitempty,gt=0"`
+	MinOrderAmount   *float64   `json:"min_order_amount" binding:"omitempty,min=0"`
+	UsageLimit       *int       `json:"usage_limit" binding:"omitempty,min=1"`
+	PerUserLimit     *int       `json:"per_user_limit" binding:"omitempty,min=0"`
+	CategoryIDs      []int64    `json:"category_ids"`
+	ServiceIDs       []int64    `json:"service_ids"`
+	Cities           []string   `json:"cities"`
+	FirstBookingOnly *bool      `json:"first_booking_only"`
+	ValidFrom        *time.Time `json:"valid_from"`
+	ValidUntil       *time.Time `json:"valid_until"`
+	IsActive         *bool      `json:"is_active"`
+}
+
+// ValidateCouponRequest represents a customer's request to preview a coupon
+type ValidateCouponRequest struct {
+	Code             string      `json:"code" binding:"required"`
+	Scope            CouponScope `json:"scope" binding:"required,oneof=booking quote subscription"`
+	ServiceID        *uint       `json:"service_id"` // Booking coupons
+	City             string      `json:"city"`       // Booking coupons
+	BookingID        *uint       `json:"booking_id"` // Quote coupons
+	PlanID           *uint       `json:"plan_id"`    // Subscription coupons
+	Amount           float64     `json:"amount" binding:"omitempty,min=0"`
+	ServiceSelection             // Booking coupons: chosen variant, quantity and add-ons
+}
+
+// CouponFilters represents filters for listing coupons
+type CouponFilters struct {
+	Search   string      `json:"search"`
+	Scope    CouponScope `json:"scope"`
+	IsActive *bool       `json:"is_active"`
+	Page     int         `json:"page"`
+	Limit    int         `json:"limit"`
+}
+
+// CouponUsageReport summarises the redemptions of one coupon
+type CouponUsageReport struct {
+	CouponID        uint    `json:"coupon_id"`
+	Code            string  `json:"code"`
+	AppliedCount    int64   `json:"applied_count"`
+	ReservedCount   int64   `json:"reserved_count"`
+	TotalDiscount   float64 `json:"total_discount"`
+	TotalFinalValue float64 `json:"total_final_value"`
+}
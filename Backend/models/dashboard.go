@@ -151,7 +151,8 @@ type SystemHealth struct {
 	ActiveSessions    int     `json:"active_sessions"`
 	APIResponseTime   string  `json:"api_response_time"`
 	DatabaseStatus    string  `json:"database_status"`
-	Uptime            string  `json:"uptime"`
+	Uptime            string  `json:"uptime"`          // Percentage of requests served without a server error
+	UptimeDuration    string  `json:"uptime_duration"` // Time since the server started
 	ErrorRate         float64 `json:"error_rate"`
 }
 
@@ -229,6 +230,7 @@ type DashboardAlerts struct {
 	UrgentAlerts    []UrgentAlert `json:"urgent_alerts"`
 	SystemAlerts    []SystemAlert `json:"system_alerts"`
 	PendingActions  []PendingAction `json:"pending_actions"`
+	RecentActivity  []RecentActivity `json:"recent_activity"`
 }
 
 // RecentActivity represents recent system activity
@@ -262,3 +264,15 @@ type PendingAction struct {
 	CreatedAt   time.Time `json:"created_at"`
 	ActionURL   string    `json:"action_url"`
 }
+
+// DashboardAlertThresholds holds the admin-configured limits used by the dashboard feeds
+type DashboardAlertThresholds struct {
+	UnassignedBookingHours int `json:"unassigned_booking_hours"` // Flag bookings starting within this window with no accepted worker
+	WithdrawalPendingHours int `json:"withdrawal_pending_hours"` // Flag withdrawals pending longer than this
+	PropertyApprovalHours  int `json:"property_approval_hours"`  // Properties waiting longer than this are high priority
+	RoleApplicationHours   int `json:"role_application_hours"`   // Role applications waiting longer than this are high priority
+	InquiryQuoteHours      int `json:"inquiry_quote_hours"`      // Inquiries waiting longer than this for a quote are high priority
+	FailedPushWindowHours  int `json:"failed_push_window_hours"` // Window for counting failed push notifications
+	FailedPushThreshold    int `json:"failed_push_threshold"`    // Failed pushes in the window that raise an alert
+	UnansweredChatHours    int `json:"unanswered_chat_hours"`    // Unread messages older than this mark a room as unanswered
+}
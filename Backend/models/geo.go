@@ -0,0 +1,57 @@
+package models
+
+import "errors"
+
+// MaxSearchRadiusMeters is the largest radius accepted by radius searches
+const MaxSearchRadiusMeters = 100000
+
+// GeoRadius limits a listing search to a circle around a point; results are ordered by distance
+type GeoRadius struct {
+	Latitude     float64
+	Longitude    float64
+	RadiusMeters float64
+}
+
+// Validate checks the point and radius of the search
+func (r GeoRadius) Validate() error {
+	if err := ValidateCoordinates(r.Latitude, r.Longitude); err != nil {
+		return err
+	}
+	if r.RadiusMeters <= 0 || r.RadiusMeters > MaxSearchRadiusMeters {
+		return errors.New("radius must be greater than 0 and at most 100 km")
+	}
+	return nil
+}
+
+// GeoBounds limits a listing search to a bounding box, e.g. the visible area of a map
+type GeoBounds struct {
+	MinLatitude  float64
+	MinLongitude float64
+	MaxLatitude  float64
+	MaxLongitude float64
+}
+
+// Validate checks the corners of the box
+func (b GeoBounds) Validate() error {
+	if err := ValidateCoordinates(b.MinLatitude, b.MinLongitude); err != nil {
+		return err
+	}
+	if err := ValidateCoordinates(b.MaxLatitude, b.MaxLongitude); err != nil {
+		return err
+	}
+	if b.MinLatitude > b.MaxLatitude || b.MinLongitude > b.MaxLongitude {
+		return errors.New("bounding box minimums must not exceed its maximums")
+	}
+	return nil
+}
+
+// ValidateCoordinates checks that a latitude and longitude are within range
+func ValidateCoordinates(latitude, longitude float64) error {
+	if latitude < -90 || latitude > 90 {
+		return errors.New("latitude must be between -90 and 90")
+	}
+	if longitude < -180 || longitude > 180 {
+		return errors.New("longitude must be between -180 and 180")
+	}
+	return nil
+}
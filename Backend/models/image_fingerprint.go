@@ -0,0 +1,32 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// ImageFingerprint records the perceptual hash of a processed upload
+type ImageFingerprint struct {
+	gorm.Model
+	URL            string `json:"url" gorm:"not null;uniqueIndex"`
+	Folder         string `json:"folder"`
+	PerceptualHash int64  `json:"perceptual_hash" gorm:"not null"` // 64-bit difference hash stored as a signed integer
+	Width          int    `json:"width"`
+	Height         int    `json:"height"`
+	SizeBytes      int64  `json:"size_bytes"`
+}
+
+// TableName returns the table name for ImageFingerprint
+func (ImageFingerprint) TableName() string {
+	return "image_fingerprints"
+}
+
+// DuplicatePropertyImage is a pair of near-identical photos on two different properties
+type DuplicatePropertyImage struct {
+	PropertyID             uint   `json:"property_id"`
+	PropertyTitle          string `json:"property_title"`
+	ImageURL               string `json:"image_url"`
+	DuplicatePropertyID    uint   `json:"duplicate_property_id"`
+	DuplicatePropertyTitle string `json:"duplicate_property_title"`
+	DuplicateImageURL      string `json:"duplicate_image_url"`
+	Distance               int    `json:"distance"` // Bits that differ between the perceptual hashes (0 = identical)
+}
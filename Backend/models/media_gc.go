@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// MediaGCOrphan is a stored file no record references
+type MediaGCOrphan struct {
+	URL          string    `json:"url"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// MediaGCReport summarises a media garbage collection run
+type MediaGCReport struct {
+	DryRun                 bool            `json:"dry_run"`
+	StartedAt              time.Time       `json:"started_at"`
+	FinishedAt             time.Time       `json:"finished_at"`
+	ChatMediaCutoff        time.Time       `json:"chat_media_cutoff"`
+	StoredFiles            int             `json:"stored_files"`
+	StoredBytes            int64           `json:"stored_bytes"`
+	ReferencedKeys         int             `json:"referenced_keys"`
+	RecentFiles            int             `json:"recent_files"` // Unreferenced but still within the grace period
+	OrphanedFiles          int             `json:"orphaned_files"`
+	OrphanedBytes          int64           `json:"orphaned_bytes"`
+	DeletedFiles           int             `json:"deleted_files"`
+	FailedDeletes          int             `json:"failed_deletes"`
+	ExpiredChatAttachments int64           `json:"expired_chat_attachments"`
+	Orphans                []MediaGCOrphan `json:"orphans"`
+}
@@ -22,6 +22,8 @@ const (
 	PaymentTypeManual            PaymentType = "manual"
 	PaymentTypeWorkerEarnings    PaymentType = "worker_earnings"
 	PaymentTypeWorkerWithdrawal  PaymentType = "worker_withdrawal"
+	PaymentTypeReferralReward    PaymentType = "referral_reward"
+	PaymentTypePropertyPromotion PaymentType = "property_promotion"
 )
 
 
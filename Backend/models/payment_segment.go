@@ -16,12 +16,33 @@ const (
 	PaymentSegmentStatusCancelled PaymentSegmentStatus = "cancelled"
 )
 
+// PaymentSegmentDueTrigger represents the milestone that makes a payment segment due
+type PaymentSegmentDueTrigger string
+
+const (
+	PaymentSegmentDueOnBooking         PaymentSegmentDueTrigger = "on_booking"          // Due once the quote is accepted
+	PaymentSegmentDueOnWorkStart       PaymentSegmentDueTrigger = "on_work_start"       // Due when the worker starts the job
+	PaymentSegmentDueOnCompletion      PaymentSegmentDueTrigger = "on_completion"       // Due when the worker completes the job
+	PaymentSegmentDueDaysAfterPrevious PaymentSegmentDueTrigger = "days_after_previous" // Due N days after the previous segment fell due
+	PaymentSegmentDueManual            PaymentSegmentDueTrigger = "manual"              // Never falls due automatically
+)
+
+// PaymentSegmentGate represents a work stage that can be held until a segment is paid
+type PaymentSegmentGate string
+
+const (
+	PaymentSegmentGateNone       PaymentSegmentGate = "none"
+	PaymentSegmentGateWorkStart  PaymentSegmentGate = "work_start"
+	PaymentSegmentGateCompletion PaymentSegmentGate = "completion"
+)
+
 // PaymentSegment represents the payment segment model
 type PaymentSegment struct {
 	gorm.Model
 	// Basic Information
 	BookingID      uint                  `json:"booking_id" gorm:"not null"`
 	SegmentNumber  int                   `json:"segment_number" gorm:"not null"`
+	QuoteRevisionID *uint                `json:"quote_revision_id"` // Quote version this segment was created from
 
 	// Payment Details
 	Amount         float64               `json:"amount" gorm:"not null"`
@@ -34,6 +55,17 @@ type PaymentSegment struct {
 	// Additional Information
 	Notes          string                `json:"notes"`
 
+	// Milestone Scheduling
+	DueTrigger     PaymentSegmentDueTrigger `json:"due_trigger" gorm:"default:'manual'"`
+	DueAfterDays   *int                     `json:"due_after_days"`                    // Only for days_after_previous
+	DueAt          *time.Time               `json:"due_at"`                            // Set when the trigger fires
+	RequiredBefore PaymentSegmentGate       `json:"required_before" gorm:"default:'none'"` // Work stage held until this segment is paid
+
+	// Reminder Tracking
+	OverdueAt      *time.Time            `json:"overdue_at"`
+	ReminderCount  int                   `json:"reminder_count" gorm:"default:0"`
+	LastReminderAt *time.Time            `json:"last_reminder_at"`
+
 	// Relationships
 	Booking        Booking               `json:"booking" gorm:"foreignKey:BookingID"`
 	Payment        *Payment              `json:"payment,omitempty" gorm:"foreignKey:PaymentID"`
@@ -44,10 +76,25 @@ func (PaymentSegment) TableName() string {
 	return "payment_segments"
 }
 
+// IsUnpaid reports whether the segment still has to be paid
+func (ps *PaymentSegment) IsUnpaid() bool {
+	return ps.Status == PaymentSegmentStatusPending || ps.Status == PaymentSegmentStatusOverdue
+}
+
 // PaymentSegmentRequest represents the request structure for creating a payment segment
 type PaymentSegmentRequest struct {
-	Amount   float64    `json:"amount" binding:"required,min=0"`
-	Notes    string     `json:"notes,omitempty"`
+	Amount         float64                  `json:"amount" binding:"required,min=0"`
+	Notes          string                   `json:"notes,omitempty"`
+	DueTrigger     PaymentSegmentDueTrigger `json:"due_trigger,omitempty" binding:"omitempty,oneof=on_booking on_work_start on_completion days_after_previous manual"`
+	DueAfterDays   *int                     `json:"due_after_days,omitempty" binding:"omitempty,min=1"`
+	RequiredBefore PaymentSegmentGate       `json:"required_before,omitempty" binding:"omitempty,oneof=none work_start completion"`
+}
+
+// UpdatePaymentSegmentScheduleRequest represents the admin request to change a segment's milestone and gate
+type UpdatePaymentSegmentScheduleRequest struct {
+	DueTrigger     *PaymentSegmentDueTrigger `json:"due_trigger" binding:"omitempty,oneof=on_booking on_work_start on_completion days_after_previous manual"`
+	DueAfterDays   *int                      `json:"due_after_days" binding:"omitempty,min=1"`
+	RequiredBefore *PaymentSegmentGate       `json:"required_before" binding:"omitempty,oneof=none work_start completion"`
 }
 
 // CreateSegmentPaymentRequest represents the request to create payment for a specific segment
@@ -73,6 +120,10 @@ type PaymentSegmentInfo struct {
 	PaidAt        *time.Time            `json:"paid_at"`
 	Notes         string                `json:"notes"`
 	PaymentID     *uint                 `json:"payment_id"`
+	DueTrigger     PaymentSegmentDueTrigger `json:"due_trigger"`
+	DueAfterDays   *int                     `json:"due_after_days,omitempty"`
+	DueAt          *time.Time               `json:"due_at"`
+	RequiredBefore PaymentSegmentGate       `json:"required_before"`
 }
 
 // PaymentProgress represents the overall payment progress for a booking
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// PricingAdjustmentType represents how a pricing rule changes a price
+type PricingAdjustmentType string
+
+const (
+	PricingAdjustmentMultiplier PricingAdjustmentType = "multiplier" // Price x value, e.g. 1.25
+	PricingAdjustmentFlat       PricingAdjustmentType = "flat"       // Price + value in rupees
+)
+
+// PricingRule raises the price of fixed price services when all of its conditions match the slot
+// being booked. Empty conditions match anything. When several rules match, the one with the highest
+// priority applies.
+type PricingRule struct {
+	gorm.Model
+	Name        string `json:"name" gorm:"not null"`
+	Description string `json:"description"`
+	Priority    int    `json:"priority" gorm:"default:0"`
+	IsActive    bool   `json:"is_active" gorm:"default:true"`
+
+	// Conditions
+	DaysOfWeek     pq.Int64Array  `json:"days_of_week" gorm:"type:integer[]"` // 0 = Sunday
+	StartTime      *string        `json:"start_time"`                         // "HH:MM" IST; windows may wrap past midnight
+	EndTime        *string        `json:"end_time"`                           // "HH:MM" IST, exclusive
+	StartDate      *time.Time     `json:"start_date" gorm:"type:date"`
+	EndDate        *time.Time     `json:"end_date" gorm:"type:date"` // Inclusive
+	HolidaysOnly   bool           `json:"holidays_only" gorm:"default:false"`
+	Cities         pq.StringArray `json:"cities" gorm:"type:text[]"`
+	Pincodes       pq.StringArray `json:"pincodes" gorm:"type:text[]"`
+	ServiceIDs     pq.Int64Array  `json:"service_ids" gorm:"type:bigint[]"`
+	CategoryIDs    pq.Int64Array  `json:"category_ids" gorm:"type:bigint[]"`
+	MinUtilisation *float64       `json:"min_utilisation"` // Percentage of workers already busy in the slot
+
+	// Adjustment
+	AdjustmentType  PricingAdjustmentType `json:"adjustment_type" gorm:"not null"`
+	AdjustmentValue float64               `json:"adjustment_value" gorm:"not null"`
+	MaxUplift       *float64              `json:"max_uplift"` // Cap on the amount added, in rupees
+
+	CreatedBy *uint `json:"created_by"`
+}
+
+// TableName returns the table name for PricingRule
+func (PricingRule) TableName() string {
+	return "pricing_rules"
+}
+
+// Holiday is a date pricing rules can target with HolidaysOnly
+type Holiday struct {
+	gorm.Model
+	Date   time.Time      `json:"date" gorm:"type:date;not null;index"`
+	Name   string         `json:"name" gorm:"not null"`
+	Cities pq.StringArray `json:"cities" gorm:"type:text[]"` // Empty = everywhere
+}
+
+// TableName returns the table name for Holiday
+func (Holiday) TableName() string {
+	return "holidays"
+}
+
+// AppliedPricingRule records the pricing rule that changed a price
+type AppliedPricingRule struct {
+	RuleID          uint                  `json:"rule_id"`
+	Name            string                `json:"name"`
+	AdjustmentType  PricingAdjustmentType `json:"adjustment_type"`
+	AdjustmentValue float64               `json:"adjustment_value"`
+	Amount          float64               `json:"amount"` // Amount added to the price
+}
+
+// PricingLocation is where a service is booked, for location-based pricing rules
+type PricingLocation struct {
+	City    string `json:"city"`
+	State   string `json:"state"`
+	Pincode string `json:"pincode"`
+}
+
+// CreatePricingRuleRequest represents the request structure for creating a pricing rule
+type CreatePricingRuleRequest struct {
+	Name            string                `json:"name" binding:"required"`
+	Description     string                `json:"description"`
+	Priority        int                   `json:"priority"`
+	IsActive        *bool                 `json:"is_active"`
+	DaysOfWeek      []int64               `json:"days_of_week" binding:"omitempty,dive,min=0,max=6"`
+	StartTime       *string               `json:"start_time"`
+	EndTime         *string               `json:"end_time"`
+	StartDate       *string               `json:"start_date"` // YYYY-MM-DD
+	EndDate         *string               `json:"end_date"`   // YYYY-MM-DD
+	HolidaysOnly    bool                  `json:"holidays_only"`
+	Cities          []string              `json:"cities"`
+	Pincodes        []string              `json:"pincodes"`
+	ServiceIDs      []int64               `json:"service_ids"`
+	CategoryIDs     []int64               `json:"category_ids"`
+	MinUtilisation  *float64              `json:"min_utilisation" binding:"omitempty,min=0,max=100"`
+	AdjustmentType  PricingAdjustmentType `json:"adjustment_type" binding:"required,oneof=multiplier flat"`
+	AdjustmentValue float64               `json:"adjustment_value" binding:"required,gt=0"`
+	MaxUplift       *float64              `json:"max_uplift" binding:"omitempty,gt=0"`
+}
+
+// UpdatePricingRuleRequest represents the request structure for updating a pricing rule. Conditions
+// are replaced as a whole.
+type UpdatePricingRuleRequest struct {
+	CreatePricingRuleRequest
+}
+
+// PricingRuleFilters represents filters for listing pricing rules
+type PricingRuleFilters struct {
+	IsActive *bool `json:"is_active"`
+	Page     int   `json:"page"`
+	Limit    int   `json:"limit"`
+}
+
+// CreateHolidayRequest represents the request structure for adding a holiday
+type CreateHolidayRequest struct {
+	Date   string   `json:"date" binding:"required"` // YYYY-MM-DD
+	Name   string   `json:"name" binding:"required"`
+	Cities []string `json:"cities"`
+}
@@ -43,6 +43,11 @@ type Project struct {
 	City    string `json:"city" gorm:"not null"`
 	Address string `json:"address" gorm:"not null"`
 	Pincode string `json:"pincode" gorm:"not null"`
+	Latitude  *float64 `json:"latitude"`  // Geocoded from the address unless given by the creator
+	Longitude *float64 `json:"longitude"`
+	
+	// Distance in meters from the point of a radius search (not stored)
+	DistanceMeters *float64 `json:"distance_meters,omitempty" gorm:"->;-:migration"`
 	
 	// Project Timeline
 	EstimatedDuration int `json:"estimated_duration_days" gorm:"column:estimated_duration_days"`
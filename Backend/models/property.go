@@ -31,8 +31,12 @@ const (
 	PropertyStatusAvailable PropertyStatus = "available"
 	PropertyStatusSold      PropertyStatus = "sold"    // For sale listings
 	PropertyStatusRented    PropertyStatus = "rented"  // For rent listings
+	PropertyStatusExpired   PropertyStatus = "expired" // Listing passed its expiry date and can be renewed
 )
 
+// PublicPropertyStatuses are the statuses an approved listing can be shown to other users in
+var PublicPropertyStatuses = []PropertyStatus{PropertyStatusAvailable, PropertyStatusSold, PropertyStatusRented}
+
 // FurnishingStatus represents the furnishing status
 type FurnishingStatus string
 
@@ -108,6 +112,11 @@ type Property struct {
 	City    string `json:"city" gorm:"not null"`
 	Address string `json:"address"`
 	Pincode string `json:"pincode"`
+	Latitude  *float64 `json:"latitude"`  // Geocoded from the address unless given by the lister
+	Longitude *float64 `json:"longitude"`
+	
+	// Distance in meters from the point of a radius search (not stored)
+	DistanceMeters *float64 `json:"distance_meters,omitempty" gorm:"->;-:migration"`
 	
 	// Status and Approval
 	Status           PropertyStatus `json:"status" gorm:"default:'available'"`
@@ -117,9 +126,16 @@ type Property struct {
 	UploadedByAdmin  bool           `json:"uploaded_by_admin" gorm:"default:false"` // Track if admin uploaded
 	
 	// Priority and Subscription
-	PriorityScore        int  `json:"priority_score" gorm:"default:0"`           // Priority for listing order
+	PriorityScore        int  `json:"priority_score" gorm:"default:0"`           // Priority for listing order, including any boost
+	BasePriorityScore    int  `json:"base_priority_score" gorm:"default:0"`      // Priority without boosts
 	SubscriptionRequired bool `json:"subscription_required" gorm:"default:false"` // If broker needed subscription to post
 	
+	// Boosts and featured slots
+	BoostPoints    int        `json:"boost_points" gorm:"default:0"` // Priority added when the boost started
+	BoostStartedAt *time.Time `json:"boost_started_at"`
+	BoostedUntil   *time.Time `json:"boosted_until"`
+	FeaturedUntil  *time.Time `json:"featured_until"`
+	
 	// TreesIndia Assured Tag
 	TreesIndiaAssured    bool `json:"treesindia_assured" gorm:"column:treesindia_assured;default:false"`   // TreesIndia Assured tag for admin-created properties
 	
@@ -128,6 +144,7 @@ type Property struct {
 	
 	// Expiry
 	ExpiresAt        *time.Time `json:"expires_at"`
+	RenewedAt        *time.Time `json:"renewed_at"`
 	
 	// Relationships
 	UserID           uint      `json:"user_id" gorm:"not null"`
@@ -146,9 +163,11 @@ func (Property) TableName() string {
 
 // BeforeCreate is a GORM hook that runs before creating a property
 func (p *Property) BeforeCreate(tx *gorm.DB) error {
-	// Set expiry date to 30 days from now
-	expiryDate := time.Now().AddDate(0, 0, 30)
-	p.ExpiresAt = &expiryDate
+	// Set expiry date to 30 days from now unless the service set it from admin config
+	if p.ExpiresAt == nil {
+		expiryDate := time.Now().AddDate(0, 0, 30)
+		p.ExpiresAt = &expiryDate
+	}
 	
 	// Auto-approve if listed by broker, admin, or user with active subscription
 	if p.BrokerID != nil || p.UploadedByAdmin || p.SubscriptionRequired {
@@ -164,16 +183,17 @@ func (p *Property) BeforeCreate(tx *gorm.DB) error {
 	
 	// Set priority score and subscription required flag based on property type
 	if p.BrokerID != nil {
-		p.PriorityScore = 100 // Broker properties get high priority
+		p.BasePriorityScore = 100 // Broker properties get high priority
 		p.SubscriptionRequired = true // Broker properties require subscription
 	} else if p.UploadedByAdmin {
-		p.PriorityScore = 50 // Admin properties get medium priority
+		p.BasePriorityScore = 50 // Admin properties get medium priority
 		p.SubscriptionRequired = false // Admin properties don't require subscription
 	} else if p.SubscriptionRequired {
-		p.PriorityScore = 75 // Normal user properties with subscription get high-medium priority
+		p.BasePriorityScore = 75 // Normal user properties with subscription get high-medium priority
 	} else {
-		p.PriorityScore = 0 // Normal user properties without subscription get low priority
+		p.BasePriorityScore = 0 // Normal user properties without subscription get low priority
 	}
+	p.PriorityScore = p.BasePriorityScore
 	
 	return nil
 }
@@ -186,7 +206,59 @@ func (p *Property) IsExpired() bool {
 	return time.Now().After(*p.ExpiresAt)
 }
 
+// IsPublic checks if the property is approved and in a status that can be shown to other users
+func (p *Property) IsPublic() bool {
+	if !p.IsApproved {
+		return false
+	}
+	for _, status := range PublicPropertyStatuses {
+		if p.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
 // ShouldExpire checks if the property should be marked as expired
 func (p *Property) ShouldExpire() bool {
 	return p.Status == PropertyStatusAvailable && p.IsExpired()
 }
+
+// IsBoosted checks if the property has an active boost
+func (p *Property) IsBoosted(now time.Time) bool {
+	return p.BoostedUntil != nil && p.BoostedUntil.After(now)
+}
+
+// IsFeatured checks if the property holds a featured slot
+func (p *Property) IsFeatured(now time.Time) bool {
+	return p.FeaturedUntil != nil && p.FeaturedUntil.After(now)
+}
+
+// RemainingBoostPoints returns the part of the boost that has not decayed yet. Boosts decay
+// linearly from BoostPoints when bought to 0 when they end.
+func (p *Property) RemainingBoostPoints(now time.Time) int {
+	if !p.IsBoosted(now) || p.BoostStartedAt == nil || p.BoostPoints <= 0 {
+		return 0
+	}
+	total := p.BoostedUntil.Sub(*p.BoostStartedAt)
+	if total <= 0 {
+		return 0
+	}
+	remaining := p.BoostedUntil.Sub(now)
+	if remaining > total {
+		remaining = total
+	}
+	return int(float64(p.BoostPoints) * remaining.Seconds() / total.Seconds())
+}
+
+// CanRenew checks if the owner can renew the listing: it has expired, or it expires within the
+// renewal window
+func (p *Property) CanRenew(now time.Time, renewalWindow time.Duration) bool {
+	switch p.Status {
+	case PropertyStatusExpired:
+		return true
+	case PropertyStatusAvailable:
+		return p.ExpiresAt != nil && p.ExpiresAt.Before(now.Add(renewalWindow))
+	}
+	return false
+}
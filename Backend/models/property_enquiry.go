@@ -0,0 +1,162 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LeadStatus represents where a property enquiry is in the listing owner's lead pipeline
+type LeadStatus string
+
+const (
+	LeadStatusNew            LeadStatus = "new"
+	LeadStatusContacted      LeadStatus = "contacted"
+	LeadStatusVisitScheduled LeadStatus = "visit_scheduled"
+	LeadStatusNegotiating    LeadStatus = "negotiating"
+	LeadStatusClosedWon      LeadStatus = "closed_won"
+	LeadStatusClosedLost     LeadStatus = "closed_lost"
+)
+
+// IsClosed reports whether the lead has left the pipeline
+func (s LeadStatus) IsClosed() bool {
+	return s == LeadStatusClosedWon || s == LeadStatusClosedLost
+}
+
+// SiteVisitStatus represents the status of a property site visit
+type SiteVisitStatus string
+
+const (
+	SiteVisitStatusScheduled SiteVisitStatus = "scheduled"
+	SiteVisitStatusCompleted SiteVisitStatus = "completed"
+	SiteVisitStatusCancelled SiteVisitStatus = "cancelled"
+	SiteVisitStatusNoShow    SiteVisitStatus = "no_show"
+)
+
+// VisitSlot is a time window in which a buyer would like to visit a property
+type VisitSlot struct {
+	Start time.Time `json:"start" binding:"required"`
+	End   time.Time `json:"end" binding:"required"`
+}
+
+// VisitSlots represents a JSON array of visit slots
+type VisitSlots []VisitSlot
+
+// Value implements the driver.Valuer interface
+func (v VisitSlots) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// Scan implements the sql.Scanner interface
+func (v *VisitSlots) Scan(value interface{}) error {
+	if value == nil {
+		*v = nil
+		return nil
+	}
+
+	switch data := value.(type) {
+	case []byte:
+		return json.Unmarshal(data, v)
+	case string:
+		return json.Unmarshal([]byte(data), v)
+	default:
+		return nil
+	}
+}
+
+// PropertyEnquiry is a buyer's enquiry on a property listing, tracked as a lead by the listing
+// owner, or by the broker for broker listings
+type PropertyEnquiry struct {
+	gorm.Model
+	PropertyID          uint       `json:"property_id" gorm:"not null;index"`
+	BuyerID             uint       `json:"buyer_id" gorm:"not null;index"`
+	OwnerID             uint       `json:"owner_id" gorm:"not null"`
+	Message             string     `json:"message" gorm:"not null"`
+	Budget              *float64   `json:"budget"`
+	PreferredVisitSlots VisitSlots `json:"preferred_visit_slots" gorm:"type:jsonb"`
+	Status              LeadStatus `json:"status" gorm:"not null;default:'new'"`
+	StatusChangedAt     *time.Time `json:"status_changed_at"`
+	OwnerNotes          string     `json:"owner_notes,omitempty"`
+	ClosedAt            *time.Time `json:"closed_at"`
+
+	// Relationships
+	Property *Property           `json:"property,omitempty" gorm:"foreignKey:PropertyID"`
+	Buyer    *User               `json:"buyer,omitempty" gorm:"foreignKey:BuyerID"`
+	Visits   []PropertySiteVisit `json:"visits,omitempty" gorm:"foreignKey:EnquiryID"`
+}
+
+// TableName returns the table name for PropertyEnquiry
+func (PropertyEnquiry) TableName() string {
+	return "property_enquiries"
+}
+
+// PropertySiteVisit is a visit to a property booked on an enquiry
+type PropertySiteVisit struct {
+	gorm.Model
+	EnquiryID      uint            `json:"enquiry_id" gorm:"not null;index"`
+	PropertyID     uint            `json:"property_id" gorm:"not null"`
+	BuyerID        uint            `json:"buyer_id" gorm:"not null"`
+	OwnerID        uint            `json:"owner_id" gorm:"not null"`
+	ScheduledAt    time.Time       `json:"scheduled_at" gorm:"not null"`
+	Status         SiteVisitStatus `json:"status" gorm:"not null;default:'scheduled'"`
+	Notes          string          `json:"notes,omitempty"`
+	ScheduledBy    *uint           `json:"scheduled_by"`
+	CancelledBy    *uint           `json:"cancelled_by"`
+	ReminderSentAt *time.Time      `json:"reminder_sent_at"`
+
+	// Relationships
+	Property *Property `json:"property,omitempty" gorm:"foreignKey:PropertyID"`
+}
+
+// TableName returns the table name for PropertySiteVisit
+func (PropertySiteVisit) TableName() string {
+	return "property_site_visits"
+}
+
+// ListingLeadStats summarises the leads of one property listing
+type ListingLeadStats struct {
+	PropertyID     uint    `json:"property_id"`
+	Title          string  `json:"title"`
+	Enquiries      int64   `json:"enquiries"`
+	Open           int64   `json:"open"`
+	VisitsDone     int64   `json:"visits_done"`
+	Won            int64   `json:"won"`
+	Lost           int64   `json:"lost"`
+	ConversionRate float64 `json:"conversion_rate"` // Won leads as a percentage of all enquiries
+}
+
+// LeadsDashboard summarises a listing owner's leads
+type LeadsDashboard struct {
+	ByStatus map[LeadStatus]int64 `json:"by_status"`
+	Listings []ListingLeadStats   `json:"listings"`
+}
+
+// CreatePropertyEnquiryRequest represents the request structure for enquiring about a property
+type CreatePropertyEnquiryRequest struct {
+	Message             string      `json:"message" binding:"required,max=2000"`
+	Budget              *float64    `json:"budget" binding:"omitempty,gt=0"`
+	PreferredVisitSlots []VisitSlot `json:"preferred_visit_slots" binding:"omitempty,max=5,dive"`
+}
+
+// UpdateLeadStatusRequest represents the request structure for moving a lead through the pipeline
+type UpdateLeadStatusRequest struct {
+	Status LeadStatus `json:"status" binding:"required,oneof=new contacted visit_scheduled negotiating closed_won closed_lost"`
+	Notes  string     `json:"notes"`
+}
+
+// ScheduleSiteVisitRequest represents the request structure for booking a site visit
+type ScheduleSiteVisitRequest struct {
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+	Notes       string    `json:"notes"`
+}
+
+// UpdateSiteVisitRequest represents the request structure for updating the outcome of a site visit
+type UpdateSiteVisitRequest struct {
+	Status SiteVisitStatus `json:"status" binding:"required,oneof=completed cancelled no_show"`
+	Notes  string          `json:"notes"`
+}
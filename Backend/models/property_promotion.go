@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PropertyPromotionType represents a paid product that raises a listing's visibility
+type PropertyPromotionType string
+
+const (
+	PropertyPromotionTypeBoost    PropertyPromotionType = "boost"    // Raises priority, decaying over the boost
+	PropertyPromotionTypeFeatured PropertyPromotionType = "featured" // Lists the property ahead of others in its city
+)
+
+// PropertyPromotionStatus represents the status of a property promotion
+type PropertyPromotionStatus string
+
+const (
+	PropertyPromotionStatusPending   PropertyPromotionStatus = "pending" // Waiting for the Razorpay payment
+	PropertyPromotionStatusActive    PropertyPromotionStatus = "active"
+	PropertyPromotionStatusCancelled PropertyPromotionStatus = "cancelled"
+)
+
+// PaymentMethodSubscription pays for a boost with the free boosts of the user's subscription plan
+const PaymentMethodSubscription = "subscription"
+
+// PropertyPromotion is a boost or featured slot bought for a property listing
+type PropertyPromotion struct {
+	gorm.Model
+	PropertyID    uint                    `json:"property_id" gorm:"not null"`
+	UserID        uint                    `json:"user_id" gorm:"not null"`
+	Type          PropertyPromotionType   `json:"type" gorm:"not null"`
+	Status        PropertyPromotionStatus `json:"status" gorm:"default:'pending'"`
+	PaymentMethod string                  `json:"payment_method" gorm:"not null"` // "wallet", "razorpay", "subscription"
+	PaymentID     *uint                   `json:"payment_id"`
+	Amount        float64                 `json:"amount"`
+	DurationDays  int                     `json:"duration_days" gorm:"not null"`
+	BoostPoints   int                     `json:"boost_points"`
+	StartsAt      *time.Time              `json:"starts_at"`
+	EndsAt        *time.Time              `json:"ends_at"`
+
+	// Relationships
+	Property *Property `json:"property,omitempty" gorm:"foreignKey:PropertyID"`
+}
+
+// TableName returns the table name for PropertyPromotion
+func (PropertyPromotion) TableName() string {
+	return "property_promotions"
+}
+
+// PropertyPromotionProduct is the price and effect of a promotion type
+type PropertyPromotionProduct struct {
+	Type         PropertyPromotionType `json:"type"`
+	Price        float64               `json:"price"`
+	DurationDays int                   `json:"duration_days"`
+	BoostPoints  int                   `json:"boost_points,omitempty"`
+}
+
+// PropertyPromotionOptions lists what the owner of a property can buy for it
+type PropertyPromotionOptions struct {
+	Boost                  PropertyPromotionProduct `json:"boost"`
+	Featured               PropertyPromotionProduct `json:"featured"`
+	FreeBoostsRemaining    int                      `json:"free_boosts_remaining"`
+	FeaturedSlotsAvailable int                      `json:"featured_slots_available"`
+	CanRenew               bool                     `json:"can_renew"`
+}
+
+// PurchasePropertyPromotionRequest represents the request to buy a boost or featured slot
+type PurchasePropertyPromotionRequest struct {
+	Type          PropertyPromotionType `json:"type" binding:"required,oneof=boost featured"`
+	PaymentMethod string                `json:"payment_method" binding:"required,oneof=wallet razorpay subscription"`
+}
+
+// VerifyPropertyPromotionPaymentRequest represents the Razorpay payment of a pending promotion
+type VerifyPropertyPromotionPaymentRequest struct {
+	RazorpayPaymentID string `json:"razorpay_payment_id" binding:"required"`
+	RazorpaySignature string `json:"razorpay_signature" binding:"required"`
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// QuoteRevisionType represents who proposed a quote revision and how
+type QuoteRevisionType string
+
+const (
+	QuoteRevisionTypeQuote           QuoteRevisionType = "quote"            // Admin quote (initial or revised)
+	QuoteRevisionTypeCounterOffer    QuoteRevisionType = "counter_offer"    // Customer proposes a different amount
+	QuoteRevisionTypeRevisionRequest QuoteRevisionType = "revision_request" // Customer asks for a revised quote
+)
+
+// QuoteRevisionStatus represents the status of a quote revision
+type QuoteRevisionStatus string
+
+const (
+	QuoteRevisionStatusActive     QuoteRevisionStatus = "active"     // Latest admin quote awaiting the customer
+	QuoteRevisionStatusOpen       QuoteRevisionStatus = "open"       // Customer counter-offer or request awaiting the admin
+	QuoteRevisionStatusSuperseded QuoteRevisionStatus = "superseded" // Replaced by a later revision
+	QuoteRevisionStatusAccepted   QuoteRevisionStatus = "accepted"   // Accepted by the customer and locked
+	QuoteRevisionStatusRejected   QuoteRevisionStatus = "rejected"   // Rejected by the customer
+	QuoteRevisionStatusExpired    QuoteRevisionStatus = "expired"    // Passed its validity without a response
+)
+
+// QuoteLineItemType represents the kind of charge on a quote
+type QuoteLineItemType string
+
+const (
+	QuoteLineItemTypeLabour      QuoteLineItemType = "labour"
+	QuoteLineItemTypeMaterials   QuoteLineItemType = "materials"
+	QuoteLineItemTypeVisitCharge QuoteLineItemType = "visit_charge"
+	QuoteLineItemTypeTax         QuoteLineItemType = "tax"
+	QuoteLineItemTypeOther       QuoteLineItemType = "other"
+)
+
+// QuoteRevision is one version of the quote for an inquiry booking.
+// Every admin quote, customer counter-offer and revision request is kept.
+type QuoteRevision struct {
+	gorm.Model
+	BookingID uint                `json:"booking_id" gorm:"not null;index"`
+	Version   int                 `json:"version" gorm:"not null"`
+	Type      QuoteRevisionType   `json:"type" gorm:"not null"`
+	Status    QuoteRevisionStatus `json:"status" gorm:"not null"`
+
+	// Author
+	AuthorID   uint     `json:"author_id" gorm:"not null"`
+	AuthorType UserType `json:"author_type" gorm:"not null"`
+
+	// Amounts
+	Subtotal    float64 `json:"subtotal"`
+	TaxAmount   float64 `json:"tax_amount"`
+	TotalAmount float64 `json:"total_amount"`
+
+	Notes    string  `json:"notes"`
+	Duration *string `json:"duration"`
+
+	// Set once the customer accepts this revision; a locked revision never changes
+	LockedAt *time.Time `json:"locked_at"`
+
+	// Relationships
+	LineItems []QuoteLineItem `json:"line_items,omitempty" gorm:"foreignKey:QuoteRevisionID"`
+	Author    *User           `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
+}
+
+// TableName returns the table name for QuoteRevision
+func (QuoteRevision) TableName() string {
+	return "quote_revisions"
+}
+
+// IsLocked reports whether the revision has been accepted and can no longer change
+func (qr *QuoteRevision) IsLocked() bool {
+	return qr.LockedAt != nil
+}
+
+// QuoteLineItem is a single charge on a quote revision
+type QuoteLineItem struct {
+	gorm.Model
+	QuoteRevisionID uint              `json:"quote_revision_id" gorm:"not null;index"`
+	Type            QuoteLineItemType `json:"type" gorm:"not null"`
+	Description     string            `json:"description"`
+	Quantity        float64           `json:"quantity" gorm:"default:1"`
+	UnitPrice       float64           `json:"unit_price"`
+	Amount          float64           `json:"amount"`
+}
+
+// TableName returns the table name for QuoteLineItem
+func (QuoteLineItem) TableName() string {
+	return "quote_line_items"
+}
+
+// QuoteLineItemRequest represents a line item in a quote request
+type QuoteLineItemRequest struct {
+	Type        QuoteLineItemType `json:"type" binding:"required,oneof=labour materials visit_charge tax other"`
+	Description string            `json:"description"`
+	Quantity    float64           `json:"quantity" binding:"omitempty,gt=0"`
+	UnitPrice   float64           `json:"unit_price" binding:"min=0"`
+}
+
+// CounterOfferRequest represents a customer's counter-offer on a quote
+type CounterOfferRequest struct {
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+	Notes  string  `json:"notes"`
+}
+
+// RequestQuoteRevisionRequest represents a customer's request for a revised quote
+type RequestQuoteRevisionRequest struct {
+	Notes string `json:"notes" binding:"required"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReferralStatus represents the status of a referral
+type ReferralStatus string
+
+const (
+	ReferralStatusPending  ReferralStatus = "pending"  // Waiting for the referee's first completed booking
+	ReferralStatusRewarded ReferralStatus = "rewarded" // Both wallets credited
+)
+
+// Referral links a referred user to the user whose code they used
+type Referral struct {
+	gorm.Model
+	ReferrerID uint           `json:"referrer_id" gorm:"not null;index"`
+	RefereeID  uint           `json:"referee_id" gorm:"not null;uniqueIndex"` // A user can only be referred once
+	Code       string         `json:"code" gorm:"not null"`
+	Status     ReferralStatus `json:"status" gorm:"default:'pending'"`
+
+	// Reward (set when the referee completes their first booking)
+	RewardBookingID *uint      `json:"reward_booking_id"`
+	ReferrerReward  float64    `json:"referrer_reward"`
+	RefereeReward   float64    `json:"referee_reward"`
+	RewardedAt      *time.Time `json:"rewarded_at"`
+
+	// Relationships
+	Referrer *User `json:"referrer,omitempty" gorm:"foreignKey:ReferrerID"`
+	Referee  *User `json:"referee,omitempty" gorm:"foreignKey:RefereeID"`
+}
+
+// TableName returns the table name for Referral
+func (Referral) TableName() string {
+	return "referrals"
+}
+
+// ApplyReferralCodeRequest represents a new user entering a referral code
+type ApplyReferralCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// ReferralSummary represents a user's referral code and earnings
+type ReferralSummary struct {
+	Code             string  `json:"code"`
+	TotalReferrals   int64   `json:"total_referrals"`
+	PendingReferrals int64   `json:"pending_referrals"`
+	TotalEarned      float64 `json:"total_earned"`
+}
+
+// ReferralReport summarises referral rewards for admins
+type ReferralReport struct {
+	TotalReferrals    int64   `json:"total_referrals"`
+	PendingReferrals  int64   `json:"pending_referrals"`
+	RewardedReferrals int64   `json:"rewarded_referrals"`
+	TotalReferrerPaid float64 `json:"total_referrer_paid"`
+	TotalRefereePaid  float64 `json:"total_referee_paid"`
+}
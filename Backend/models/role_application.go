@@ -73,6 +73,18 @@ type EnhancedWorker struct {
 	Earnings           float64    `json:"earnings"`
 	TotalJobs          int        `json:"total_jobs"`
 	IsActive           bool       `json:"is_active"`
+	
+	// RevealAccountNumber returns the full bank account number in JSON instead of the masked one
+	RevealAccountNumber bool      `json:"-"`
+}
+
+// MarshalJSON masks the bank account number unless RevealAccountNumber is set
+func (w EnhancedWorker) MarshalJSON() ([]byte, error) {
+	type enhancedWorker EnhancedWorker
+	if !w.RevealAccountNumber {
+		w.BankingInfo.AccountNumber = MaskAccountNumber(w.BankingInfo.AccountNumber)
+	}
+	return json.Marshal(enhancedWorker(w))
 }
 
 // Enhanced Broker with parsed JSON
@@ -131,6 +143,13 @@ func (RoleApplication) TableName() string {
 	return "role_applications"
 }
 
+// RevealAccountNumber shows the applicant's full bank account number in JSON instead of the masked one
+func (era *EnhancedRoleApplication) RevealAccountNumber() {
+	if era.Worker != nil {
+		era.Worker.RevealAccountNumber = true
+	}
+}
+
 // ConvertToEnhanced converts a RoleApplication to EnhancedRoleApplication with parsed JSON
 func (ra *RoleApplication) ConvertToEnhanced() *EnhancedRoleApplication {
 	enhanced := &EnhancedRoleApplication{
@@ -159,6 +178,7 @@ func (ra *RoleApplication) ConvertToEnhanced() *EnhancedRoleApplication {
 			Earnings:         ra.Worker.Earnings,
 			TotalJobs:        ra.Worker.TotalJobs,
 			IsActive:         ra.Worker.IsActive,
+			RevealAccountNumber: ra.Worker.RevealAccountNumber,
 		}
 
 		// Parse JSON fields
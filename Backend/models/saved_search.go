@@ -0,0 +1,224 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"time"
+	"treesindia/utils"
+
+	"gorm.io/gorm"
+)
+
+// AlertFrequency represents how often a saved search alerts its owner about new matching listings
+type AlertFrequency string
+
+const (
+	AlertFrequencyInstant AlertFrequency = "instant" // As soon as a matching listing is approved
+	AlertFrequencyDaily   AlertFrequency = "daily"   // One digest of the day's matching listings
+	AlertFrequencyOff     AlertFrequency = "off"
+)
+
+// SavedSearchFilters is a saved set of property filters. Its fields use the same names and
+// meaning as the filters of the property listing API.
+type SavedSearchFilters struct {
+	Search           string   `json:"search,omitempty" binding:"omitempty,max=100"`
+	PropertyType     string   `json:"property_type,omitempty" binding:"omitempty,oneof=residential commercial"`
+	ListingType      string   `json:"listing_type,omitempty" binding:"omitempty,oneof=sale rent"`
+	Location         string   `json:"location,omitempty" binding:"omitempty,max=100"`
+	State            string   `json:"state,omitempty"`
+	City             string   `json:"city,omitempty"`
+	Bedrooms         *int     `json:"bedrooms,omitempty" binding:"omitempty,gt=0"`
+	Bathrooms        *int     `json:"bathrooms,omitempty" binding:"omitempty,gt=0"`
+	MinPrice         *float64 `json:"min_price,omitempty" binding:"omitempty,gt=0"`
+	MaxPrice         *float64 `json:"max_price,omitempty" binding:"omitempty,gt=0"`
+	MinArea          *float64 `json:"min_area,omitempty" binding:"omitempty,gt=0"`
+	MaxArea          *float64 `json:"max_area,omitempty" binding:"omitempty,gt=0"`
+	FurnishingStatus string   `json:"furnishing_status,omitempty" binding:"omitempty,oneof=furnished semi_furnished unfurnished"`
+	Latitude         *float64 `json:"latitude,omitempty"`
+	Longitude        *float64 `json:"longitude,omitempty"`
+	RadiusKm         *float64 `json:"radius_km,omitempty" binding:"omitempty,gt=0,lte=100"`
+}
+
+// ToFilterMap converts the filters to the filter map used by PropertyRepository
+func (f SavedSearchFilters) ToFilterMap() map[string]interface{} {
+	filters := make(map[string]interface{})
+	for key, value := range map[string]string{
+		"search":            f.Search,
+		"property_type":     f.PropertyType,
+		"listing_type":      f.ListingType,
+		"location":          f.Location,
+		"state":             f.State,
+		"city":              f.City,
+		"furnishing_status": f.FurnishingStatus,
+	} {
+		if value != "" {
+			filters[key] = value
+		}
+	}
+	if f.Bedrooms != nil {
+		filters["bedrooms"] = *f.Bedrooms
+	}
+	if f.Bathrooms != nil {
+		filters["bathrooms"] = *f.Bathrooms
+	}
+	if f.MinPrice != nil {
+		filters["min_price"] = *f.MinPrice
+	}
+	if f.MaxPrice != nil {
+		filters["max_price"] = *f.MaxPrice
+	}
+	if f.MinArea != nil {
+		filters["min_area"] = *f.MinArea
+	}
+	if f.MaxArea != nil {
+		filters["max_area"] = *f.MaxArea
+	}
+	if f.Latitude != nil && f.Longitude != nil && f.RadiusKm != nil {
+		filters["near"] = GeoRadius{Latitude: *f.Latitude, Longitude: *f.Longitude, RadiusMeters: *f.RadiusKm * 1000}
+	}
+	return filters
+}
+
+// Matches checks if a property is listed publicly and matches the filters, with the same meaning as
+// the property listing filters. It lets one new listing be checked against every saved search
+// without a query per search.
+func (f SavedSearchFilters) Matches(p *Property) bool {
+	if !p.IsApproved || p.Status != PropertyStatusAvailable {
+		return false
+	}
+
+	if f.Search != "" {
+		search := strings.ToLower(f.Search)
+		if !strings.Contains(strings.ToLower(p.Title), search) && !strings.Contains(strings.ToLower(p.Description), search) {
+			return false
+		}
+	}
+	if f.PropertyType != "" && string(p.PropertyType) != f.PropertyType {
+		return false
+	}
+	if f.ListingType != "" && string(p.ListingType) != f.ListingType {
+		return false
+	}
+	if f.Location != "" {
+		location := strings.ToLower(f.Location)
+		if !strings.Contains(strings.ToLower(p.State), location) && !strings.Contains(strings.ToLower(p.City), location) {
+			return false
+		}
+	}
+	if f.State != "" && p.State != f.State {
+		return false
+	}
+	if f.City != "" && p.City != f.City {
+		return false
+	}
+	if f.FurnishingStatus != "" && (p.FurnishingStatus == nil || string(*p.FurnishingStatus) != f.FurnishingStatus) {
+		return false
+	}
+	if f.Bedrooms != nil && *f.Bedrooms > 0 && (p.Bedrooms == nil || *p.Bedrooms != *f.Bedrooms) {
+		return false
+	}
+	if f.Bathrooms != nil && *f.Bathrooms > 0 && (p.Bathrooms == nil || *p.Bathrooms < *f.Bathrooms) {
+		return false
+	}
+
+	// A price filter matches on either the sale price or the monthly rent
+	priceMatches := func(ok func(price float64) bool) bool {
+		return (p.SalePrice != nil && ok(*p.SalePrice)) || (p.MonthlyRent != nil && ok(*p.MonthlyRent))
+	}
+	if f.MinPrice != nil && *f.MinPrice > 0 && !priceMatches(func(price float64) bool { return price >= *f.MinPrice }) {
+		return false
+	}
+	if f.MaxPrice != nil && *f.MaxPrice > 0 && !priceMatches(func(price float64) bool { return price <= *f.MaxPrice }) {
+		return false
+	}
+	if f.MinArea != nil && *f.MinArea > 0 && (p.Area == nil || *p.Area < *f.MinArea) {
+		return false
+	}
+	if f.MaxArea != nil && *f.MaxArea > 0 && (p.Area == nil || *p.Area > *f.MaxArea) {
+		return false
+	}
+
+	if f.Latitude != nil && f.Longitude != nil && f.RadiusKm != nil {
+		if p.Latitude == nil || p.Longitude == nil {
+			return false
+		}
+		if utils.DistanceMeters(*f.Latitude, *f.Longitude, *p.Latitude, *p.Longitude) > *f.RadiusKm*1000 {
+			return false
+		}
+	}
+	return true
+}
+
+// Value implements the driver.Valuer interface
+func (f SavedSearchFilters) Value() (driver.Value, error) {
+	return json.Marshal(f)
+}
+
+// Scan implements the sql.Scanner interface
+func (f *SavedSearchFilters) Scan(value interface{}) error {
+	if value == nil {
+		*f = SavedSearchFilters{}
+		return nil
+	}
+
+	switch data := value.(type) {
+	case []byte:
+		return json.Unmarshal(data, f)
+	case string:
+		return json.Unmarshal([]byte(data), f)
+	default:
+		return nil
+	}
+}
+
+// SavedSearch is a set of property filters saved by a user to re-run and be alerted about
+type SavedSearch struct {
+	gorm.Model
+	UserID         uint               `json:"user_id" gorm:"not null;index"`
+	Name           string             `json:"name" gorm:"not null"`
+	Filters        SavedSearchFilters `json:"filters" gorm:"type:jsonb;not null"`
+	AlertFrequency AlertFrequency     `json:"alert_frequency" gorm:"not null;default:'instant'"`
+	NotifyPush     bool               `json:"notify_push" gorm:"not null;default:true"`
+	NotifyEmail    bool               `json:"notify_email" gorm:"not null;default:false"`
+	LastDigestAt   *time.Time         `json:"last_digest_at"`
+}
+
+// TableName returns the table name for SavedSearch
+func (SavedSearch) TableName() string {
+	return "saved_searches"
+}
+
+// SavedSearchMatch records a listing matched by a saved search and whether its owner was alerted
+type SavedSearchMatch struct {
+	gorm.Model
+	SavedSearchID uint       `json:"saved_search_id" gorm:"not null"`
+	PropertyID    uint       `json:"property_id" gorm:"not null"`
+	NotifiedAt    *time.Time `json:"notified_at"`
+
+	// Relationships
+	Property *Property `json:"property,omitempty" gorm:"foreignKey:PropertyID"`
+}
+
+// TableName returns the table name for SavedSearchMatch
+func (SavedSearchMatch) TableName() string {
+	return "saved_search_matches"
+}
+
+// CreateSavedSearchRequest represents the request structure for saving a property search
+type CreateSavedSearchRequest struct {
+	Name           string             `json:"name" binding:"required,max=100"`
+	Filters        SavedSearchFilters `json:"filters"`
+	AlertFrequency AlertFrequency     `json:"alert_frequency" binding:"omitempty,oneof=instant daily off"`
+	NotifyPush     *bool              `json:"notify_push"`
+	NotifyEmail    *bool              `json:"notify_email"`
+}
+
+// UpdateSavedSearchRequest represents the request structure for updating a saved search
+type UpdateSavedSearchRequest struct {
+	Name           *string             `json:"name" binding:"omitempty,max=100"`
+	Filters        *SavedSearchFilters `json:"filters"`
+	AlertFrequency *AlertFrequency     `json:"alert_frequency" binding:"omitempty,oneof=instant daily off"`
+	NotifyPush     *bool               `json:"notify_push"`
+	NotifyEmail    *bool               `json:"notify_email"`
+}
@@ -63,6 +63,8 @@ type SearchResult struct {
 
 // SearchMetadata represents search metadata
 type SearchMetadata struct {
+	SearchID      *uint   `json:"search_id,omitempty"` // Logged search to attribute clicks to (first page only)
+	SearchToken   string  `json:"search_token,omitempty"` // Sent back with clicks on the logged search
 	Query         string  `json:"query"`
 	SearchTimeMs  int64   `json:"search_time_ms"`
 	TotalResults  int64   `json:"total_results"`
@@ -126,3 +128,59 @@ type PopularService struct {
 	PopularityScore int    `json:"popularity_score"`
 }
 
+
+// SearchEntityType is the kind of record returned by the federated search
+type SearchEntityType string
+
+const (
+	SearchEntityService  SearchEntityType = "service"
+	SearchEntityProperty SearchEntityType = "property"
+	SearchEntityProject  SearchEntityType = "project"
+	SearchEntityVendor   SearchEntityType = "vendor"
+)
+
+// SearchEntityTypes lists every type the federated search covers
+var SearchEntityTypes = []SearchEntityType{
+	SearchEntityService,
+	SearchEntityProperty,
+	SearchEntityProject,
+	SearchEntityVendor,
+}
+
+// FederatedSearchFilters represents the parameters of a federated search
+type FederatedSearchFilters struct {
+	Query string             `json:"query"`
+	Types []SearchEntityType `json:"types"` // Empty searches every type
+	City  string             `json:"city"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+}
+
+// FederatedSearchHit is one ranked result of the federated search
+type FederatedSearchHit struct {
+	Type      SearchEntityType `json:"type"`
+	ID        uint             `json:"id"`
+	Title     string           `json:"title"`
+	Subtitle  string           `json:"subtitle"` // Category for services, city for everything else
+	Slug      string           `json:"slug,omitempty"`
+	Image     string           `json:"image,omitempty"`
+	Price     *float64         `json:"price,omitempty"`
+	Score     float64          `json:"score"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// SearchFacet is the number of matches for one result type
+type SearchFacet struct {
+	Type  SearchEntityType `json:"type"`
+	Count int64            `json:"count"`
+}
+
+// FederatedSearchResponse represents the federated search response
+type FederatedSearchResponse struct {
+	Query          string               `json:"query"`
+	ExpandedTerms  []string             `json:"expanded_terms,omitempty"` // Synonyms added to the query
+	Results        []FederatedSearchHit `json:"results"`
+	Facets         []SearchFacet        `json:"facets"` // Counts across every type, regardless of the type filter
+	Pagination     PaginationInfo       `json:"pagination"`
+	SearchMetadata SearchMetadata       `json:"search_metadata"`
+}
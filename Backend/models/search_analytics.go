@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SearchSource is the endpoint a logged search came from
+type SearchSource string
+
+const (
+	SearchSourceServices  SearchSource = "services"  // Service search
+	SearchSourceFederated SearchSource = "federated" // Search across services, properties, projects and vendors
+)
+
+// SearchQueryLog records one search, the result the user opened and the booking it led to
+type SearchQueryLog struct {
+	gorm.Model
+	UserID          *uint        `json:"user_id"` // Nil for guests
+	Query           string       `json:"query" gorm:"not null"`
+	NormalizedQuery string       `json:"normalized_query" gorm:"not null"` // Lower case, single spaced
+	Source          SearchSource `json:"source" gorm:"not null"`
+	ResultCount     int64        `json:"result_count"`
+	ClickToken      string       `json:"-"` // Given only to the searcher; required to record clicks
+
+	// Click-through
+	ClickCount        int        `json:"click_count"`
+	ClickedEntityType string     `json:"clicked_entity_type"`
+	ClickedEntityID   *uint      `json:"clicked_entity_id"`
+	ClickedAt         *time.Time `json:"clicked_at"`
+
+	// Conversion
+	BookingID   *uint      `json:"booking_id"`
+	ConvertedAt *time.Time `json:"converted_at"`
+}
+
+// TableName returns the table name for SearchQueryLog
+func (SearchQueryLog) TableName() string {
+	return "search_query_logs"
+}
+
+// RecordSearchClickRequest represents a user opening a search result
+type RecordSearchClickRequest struct {
+	SearchToken string           `json:"search_token" binding:"required"`
+	EntityType  SearchEntityType `json:"entity_type" binding:"required,oneof=service property project vendor"`
+	EntityID    uint             `json:"entity_id" binding:"required"`
+}
+
+// SearchQueryStat aggregates the searches for one normalized query
+type SearchQueryStat struct {
+	Query          string    `json:"query"`
+	Searches       int64     `json:"searches"`
+	AverageResults float64   `json:"average_results"`
+	Clicks         int64     `json:"clicks"`      // Searches where a result was opened
+	Conversions    int64     `json:"conversions"` // Searches that led to a booking
+	LastSearchedAt time.Time `json:"last_searched_at"`
+}
+
+// SearchAnalyticsSummary holds the headline search numbers for a period
+type SearchAnalyticsSummary struct {
+	TotalSearches      int64   `json:"total_searches"`
+	UniqueQueries      int64   `json:"unique_queries"`
+	ZeroResultSearches int64   `json:"zero_result_searches"`
+	ZeroResultRate     float64 `json:"zero_result_rate"`   // Percentage of searches
+	ClickThroughRate   float64 `json:"click_through_rate"` // Percentage of searches with a click
+	Conversions        int64   `json:"conversions"`
+	ConversionRate     float64 `json:"conversion_rate"` // Percentage of searches that led to a booking
+}
+
+// SearchAnalyticsReport represents the admin search analytics report
+type SearchAnalyticsReport struct {
+	From              time.Time              `json:"from"`
+	To                time.Time              `json:"to"`
+	Summary           SearchAnalyticsSummary `json:"summary"`
+	TopQueries        []SearchQueryStat      `json:"top_queries"`
+	ZeroResultQueries []SearchQueryStat      `json:"zero_result_queries"`
+}
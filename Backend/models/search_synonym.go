@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// SearchSynonym expands a search term with equivalent phrases (e.g. "ac" -> "air conditioner")
+type SearchSynonym struct {
+	gorm.Model
+	Term     string         `json:"term" gorm:"not null"` // Stored lower case
+	Synonyms pq.StringArray `json:"synonyms" gorm:"type:text[];not null"`
+	IsActive bool           `json:"is_active" gorm:"default:true"`
+}
+
+// TableName returns the table name for SearchSynonym
+func (SearchSynonym) TableName() string {
+	return "search_synonyms"
+}
+
+// CreateSearchSynonymRequest represents the request for creating a search synonym
+type CreateSearchSynonymRequest struct {
+	Term     string   `json:"term" binding:"required,max=100"`
+	Synonyms []string `json:"synonyms" binding:"required,min=1"`
+	IsActive *bool    `json:"is_active"`
+}
+
+// UpdateSearchSynonymRequest represents the request for updating a search synonym
+type UpdateSearchSynonymRequest struct {
+	Synonyms []string `json:"synonyms" binding:"omitempty,min=1"`
+	IsActive *bool    `json:"is_active"`
+}
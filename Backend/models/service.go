@@ -16,6 +16,7 @@ type Service struct {
 	PriceType     string         `json:"price_type" gorm:"not null;default:'inquiry'"` // "fixed" or "inquiry"
 	Price         *float64       `json:"price"` // Fixed price (nil if inquiry-based)
 	Duration      *string        `json:"duration"` // Optional duration
+	MaxQuantity   int            `json:"max_quantity" gorm:"default:1"` // Units a customer can book at once (fixed price)
 	CategoryID    uint           `json:"category_id" gorm:"not null"` // References categories.id (typically Level 3)
 	Category      Category       `json:"category" gorm:"foreignKey:CategoryID"` // Include category with hierarchy
 	IsActive      bool           `json:"is_active" gorm:"default:true"`
@@ -25,6 +26,8 @@ type Service struct {
 	
 	// Relationships
 	ServiceAreas []ServiceArea `json:"service_areas,omitempty" gorm:"many2many:service_service_areas;"`
+	Variants     []ServiceVariant `json:"variants,omitempty" gorm:"foreignKey:ServiceID"`
+	AddOns       []ServiceAddOn   `json:"add_ons,omitempty" gorm:"foreignKey:ServiceID"`
 }
 
 // CreateServiceRequest represents the request structure for creating a service
@@ -34,6 +37,7 @@ type CreateServiceRequest struct {
 	PriceType     string   `json:"price_type" binding:"required,oneof=fixed inquiry"`
 	Price         *float64 `json:"price"` // Required if price_type is "fixed" (validated in service layer)
 	Duration      *string  `json:"duration" binding:"omitempty,duration"` // Custom validation
+	MaxQuantity   int      `json:"max_quantity" binding:"omitempty,min=1"`
 	CategoryID    uint     `json:"category_id" binding:"required"` // Typically Level 3 category
 	IsActive      *bool    `json:"is_active"`
 	ServiceAreaIDs []uint  `json:"service_area_ids" binding:"required,min=1"` // At least one service area ID required
@@ -46,6 +50,7 @@ type UpdateServiceRequest struct {
 	PriceType     string   `json:"price_type" binding:"omitempty,oneof=fixed inquiry"`
 	Price         *float64 `json:"price"`
 	Duration      *string  `json:"duration"`
+	MaxQuantity   *int     `json:"max_quantity" binding:"omitempty,min=1"`
 	CategoryID    *uint    `json:"category_id"` // Typically Level 3 category
 	IsActive      *bool    `json:"is_active"`
 	ServiceAreaIDs []uint  `json:"service_area_ids" binding:"omitempty,min=1"` // Optional but if provided, at least one required
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ServiceVariant is a priced option of a fixed price service, e.g. "2 BHK". A booking picks one
+// variant when the service has any.
+type ServiceVariant struct {
+	gorm.Model
+	ServiceID   uint    `json:"service_id" gorm:"not null;index"`
+	Name        string  `json:"name" gorm:"not null"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price" gorm:"not null"`         // Price per unit
+	Duration    *string `json:"duration"`                      // Time per unit; the service duration if empty
+	MaxQuantity int     `json:"max_quantity" gorm:"default:1"` // Units a customer can book at once
+	SortOrder   int     `json:"sort_order" gorm:"default:0"`
+	IsActive    bool    `json:"is_active" gorm:"default:true"`
+}
+
+// TableName returns the table name for ServiceVariant
+func (ServiceVariant) TableName() string {
+	return "service_variants"
+}
+
+// ServiceAddOn is an optional extra booked with a service, e.g. "Gas refill"
+type ServiceAddOn struct {
+	gorm.Model
+	ServiceID   uint    `json:"service_id" gorm:"not null;index"`
+	Name        string  `json:"name" gorm:"not null"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price" gorm:"not null"`         // Price per unit
+	Duration    *string `json:"duration"`                      // Extra time per unit
+	MaxQuantity int     `json:"max_quantity" gorm:"default:1"` // Units a customer can add
+	SortOrder   int     `json:"sort_order" gorm:"default:0"`
+	IsActive    bool    `json:"is_active" gorm:"default:true"`
+}
+
+// TableName returns the table name for ServiceAddOn
+func (ServiceAddOn) TableName() string {
+	return "service_add_ons"
+}
+
+// ServiceSelection is the variant, quantity and add-ons a customer chose for a service
+type ServiceSelection struct {
+	VariantID *uint                   `json:"variant_id"`
+	Quantity  int                     `json:"quantity" binding:"omitempty,min=1"` // Defaults to 1
+	AddOns    []ServiceAddOnSelection `json:"add_ons" binding:"omitempty,dive"`
+}
+
+// ServiceAddOnSelection is an add-on chosen with a service
+type ServiceAddOnSelection struct {
+	AddOnID  uint `json:"add_on_id" binding:"required"`
+	Quantity int  `json:"quantity" binding:"omitempty,min=1"` // Defaults to 1
+}
+
+// ServicePriceBreakdown is the price and duration of a service selection. It is snapshotted onto
+// the booking so later catalogue changes don't alter what the customer booked.
+type ServicePriceBreakdown struct {
+	ServiceID       uint                    `json:"service_id"`
+	ServiceName     string                  `json:"service_name"`
+	PriceType       string                  `json:"price_type"`
+	VariantID       *uint                   `json:"variant_id,omitempty"`
+	VariantName     string                  `json:"variant_name,omitempty"`
+	UnitPrice       float64                 `json:"unit_price"`
+	UnitMinutes     int                     `json:"unit_minutes"`
+	Quantity        int                     `json:"quantity"`
+	BaseAmount      float64                 `json:"base_amount"` // Unit price x quantity
+	AddOns          []ServicePriceAddOnLine `json:"add_ons"`
+	AddOnsAmount    float64                 `json:"add_ons_amount"`
+	PricingRule     *AppliedPricingRule     `json:"pricing_rule,omitempty"` // Surge pricing rule applied for the booked slot
+	Total           float64                 `json:"total"`                  // Before coupon discounts
+	DurationMinutes int                     `json:"duration_minutes"`
+}
+
+// ServicePriceAddOnLine is an add-on in a price breakdown
+type ServicePriceAddOnLine struct {
+	AddOnID         uint    `json:"add_on_id"`
+	Name            string  `json:"name"`
+	UnitPrice       float64 `json:"unit_price"`
+	Quantity        int     `json:"quantity"`
+	Amount          float64 `json:"amount"`
+	DurationMinutes int     `json:"duration_minutes"`
+}
+
+// Value implements the driver.Valuer interface
+func (b ServicePriceBreakdown) Value() (driver.Value, error) {
+	return json.Marshal(b)
+}
+
+// Scan implements the sql.Scanner interface
+func (b *ServicePriceBreakdown) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("cannot scan non-byte value into ServicePriceBreakdown")
+	}
+
+	return json.Unmarshal(bytes, b)
+}
+
+// AppliedPricingRuleID returns the ID of the pricing rule applied to the price, if any
+func (b *ServicePriceBreakdown) AppliedPricingRuleID() *uint {
+	if b == nil || b.PricingRule == nil {
+		return nil
+	}
+	id := b.PricingRule.RuleID
+	return &id
+}
+
+// CreateServiceVariantRequest represents the request structure for creating a service variant
+type CreateServiceVariantRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price" binding:"required,gt=0"`
+	Duration    *string `json:"duration" binding:"omitempty,duration"`
+	MaxQuantity int     `json:"max_quantity" binding:"omitempty,min=1"`
+	SortOrder   int     `json:"sort_order"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// UpdateServiceVariantRequest represents the request structure for updating a service variant
+type UpdateServiceVariantRequest struct {
+	Name        string   `json:"name"`
+	Description *string  `json:"description"`
+	Price       *float64 `json:"price" binding:"omitempty,gt=0"`
+	Duration    *string  `json:"duration" binding:"omitempty,duration"`
+	MaxQuantity *int     `json:"max_quantity" binding:"omitempty,min=1"`
+	SortOrder   *int     `json:"sort_order"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+// CreateServiceAddOnRequest represents the request structure for creating a service add-on
+type CreateServiceAddOnRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price" binding:"min=0"`
+	Duration    *string `json:"duration" binding:"omitempty,duration"`
+	MaxQuantity int     `json:"max_quantity" binding:"omitempty,min=1"`
+	SortOrder   int     `json:"sort_order"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// UpdateServiceAddOnRequest represents the request structure for updating a service add-on
+type UpdateServiceAddOnRequest struct {
+	Name        string   `json:"name"`
+	Description *string  `json:"description"`
+	Price       *float64 `json:"price" binding:"omitempty,min=0"`
+	Duration    *string  `json:"duration" binding:"omitempty,duration"`
+	MaxQuantity *int     `json:"max_quantity" binding:"omitempty,min=1"`
+	SortOrder   *int     `json:"sort_order"`
+	IsActive    *bool    `json:"is_active"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// ShortlistItemType represents the kind of listing that was shortlisted
+type ShortlistItemType string
+
+const (
+	ShortlistItemTypeProperty ShortlistItemType = "property"
+	ShortlistItemTypeProject  ShortlistItemType = "project"
+)
+
+// Shortlist is a property or project a user has shortlisted
+type Shortlist struct {
+	gorm.Model
+	UserID   uint              `json:"user_id" gorm:"not null"`
+	ItemType ShortlistItemType `json:"item_type" gorm:"not null"`
+	ItemID   uint              `json:"item_id" gorm:"not null"`
+
+	// Populated when the shortlist is listed
+	Property *Property `json:"property,omitempty" gorm:"-"`
+	Project  *Project  `json:"project,omitempty" gorm:"-"`
+}
+
+// TableName returns the table name for Shortlist
+func (Shortlist) TableName() string {
+	return "shortlists"
+}
+
+// AddToShortlistRequest represents the request structure for shortlisting a property or project
+type AddToShortlistRequest struct {
+	ItemType ShortlistItemType `json:"item_type" binding:"required,oneof=property project"`
+	ItemID   uint              `json:"item_id" binding:"required"`
+}
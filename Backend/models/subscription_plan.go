@@ -85,6 +85,24 @@ func (SubscriptionPlan) TableName() string {
 	return "subscription_plans"
 }
 
+// PlanFeatureFreeBoostsPerMonth is the Features key for the number of free listing boosts a plan
+// grants each calendar month
+const PlanFeatureFreeBoostsPerMonth = "free_boosts_per_month"
+
+// FreeBoostsPerMonth returns the number of free listing boosts the plan grants each month
+func (sp *SubscriptionPlan) FreeBoostsPerMonth() int {
+	if sp.Features == nil {
+		return 0
+	}
+	switch value := sp.Features[PlanFeatureFreeBoostsPerMonth].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	}
+	return 0
+}
+
 // Duration constants
 const (
 	DurationMonthly = "monthly"
@@ -32,6 +32,7 @@ type User struct {
 	// Status
 	IsActive    bool      `json:"is_active" gorm:"default:true"`
 	LastLoginAt *time.Time `json:"last_login_at"`
+	AnonymisedAt *time.Time `json:"anonymised_at,omitempty"` // Set when a deleted account's personal data was anonymised
 	
 	// Role application fields
 	RoleApplicationStatus string    `json:"role_application_status" gorm:"default:'none'"` // "none", "pending", "approved", "rejected"
@@ -41,6 +42,9 @@ type User struct {
 	// Wallet System
 	WalletBalance    float64 `json:"wallet_balance" gorm:"default:0"`    // Wallet balance
 	
+	// Referrals
+	ReferralCode     *string `json:"referral_code" gorm:"uniqueIndex"` // Generated on first use
+	
 	// Subscription fields
 	SubscriptionID      *uint             `json:"subscription_id"`
 	Subscription        *UserSubscription `json:"subscription" gorm:"foreignKey:SubscriptionID"`
@@ -19,8 +19,38 @@ const (
 	DocumentTypeAddressProof   DocumentType = "address_proof"
 	DocumentTypeSkillCertificate DocumentType = "skill_certificate"
 	DocumentTypeProfilePhoto   DocumentType = "profile_photo"
+	DocumentTypePoliceVerification DocumentType = "police_verification"
 )
 
+// DocumentVerificationStatus represents the review state of a document
+type DocumentVerificationStatus string
+
+const (
+	DocumentVerificationStatusPending  DocumentVerificationStatus = "pending"
+	DocumentVerificationStatusVerified DocumentVerificationStatus = "verified"
+	DocumentVerificationStatusRejected DocumentVerificationStatus = "rejected"
+	DocumentVerificationStatusExpired  DocumentVerificationStatus = "expired"
+)
+
+// ApplicationDocumentTypes maps the keys of the worker and broker documents JSON to document types
+var ApplicationDocumentTypes = map[string]DocumentType{
+	"aadhar_card":         DocumentTypeAadhaarCard,
+	"pan_card":            DocumentTypePANCard,
+	"profile_pic":         DocumentTypeProfilePhoto,
+	"police_verification": DocumentTypePoliceVerification,
+}
+
+// MandatoryDocumentTypes returns the documents that must be verified for a role
+func MandatoryDocumentTypes(role string) []DocumentType {
+	switch role {
+	case string(RoleTypeWorker):
+		return []DocumentType{DocumentTypeAadhaarCard, DocumentTypePANCard, DocumentTypeProfilePhoto, DocumentTypePoliceVerification}
+	case string(RoleTypeBroker):
+		return []DocumentType{DocumentTypeAadhaarCard, DocumentTypePANCard, DocumentTypeProfilePhoto}
+	}
+	return nil
+}
+
 // UserDocument represents a user's uploaded document
 type UserDocument struct {
 	ID           uint           `json:"id" gorm:"primaryKey"`
@@ -30,6 +60,14 @@ type UserDocument struct {
 	FileURL      string         `json:"file_url" gorm:"not null"`
 	FileName     string         `json:"file_name"`
 	FileSize     int64          `json:"file_size"`
+	
+	// Verification
+	VerificationStatus DocumentVerificationStatus `json:"verification_status" gorm:"default:'pending'"`
+	RejectionReason    string                     `json:"rejection_reason,omitempty"`
+	ReviewedBy         *uint                      `json:"reviewed_by"`
+	ReviewedAt         *time.Time                 `json:"reviewed_at"`
+	ExpiresAt          *time.Time                 `json:"expires_at"`
+	
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
@@ -42,7 +80,7 @@ func (UserDocument) TableName() string {
 
 // CreateUserDocumentRequest represents the request structure for uploading a document
 type CreateUserDocumentRequest struct {
-	DocumentType DocumentType `json:"document_type" binding:"required,oneof=pan_card aadhaar_card aadhaar_card_front aadhaar_card_back pan_card_front pan_card_back address_proof skill_certificate profile_photo"`
+	DocumentType DocumentType `json:"document_type" binding:"required,oneof=pan_card aadhaar_card aadhaar_card_front aadhaar_card_back pan_card_front pan_card_back address_proof skill_certificate profile_photo police_verification"`
 	FileURL      string       `json:"file_url" binding:"required"`
 	FileName     string       `json:"file_name"`
 	FileSize     int64        `json:"file_size"`
@@ -54,3 +92,15 @@ type UpdateUserDocumentRequest struct {
 	FileName string `json:"file_name"`
 	FileSize int64  `json:"file_size"`
 }
+
+// IsExpired reports whether the document's validity has run out
+func (d *UserDocument) IsExpired() bool {
+	return d.ExpiresAt != nil && d.ExpiresAt.Before(time.Now())
+}
+
+// ReviewUserDocumentRequest represents the request structure for an admin reviewing a document
+type ReviewUserDocumentRequest struct {
+	Status          DocumentVerificationStatus `json:"status" binding:"required,oneof=verified rejected"`
+	RejectionReason string                     `json:"rejection_reason"`
+	ExpiresAt       *time.Time                 `json:"expires_at"` // Defaults to the configured validity for police verification
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"strings"
+
 	"gorm.io/gorm"
 )
 
@@ -25,8 +27,8 @@ type Worker struct {
 	// JSON Objects
 	ContactInfo        string     `json:"contact_info"`        // JSONB: {"alternative_number": "string"}
 	Address            string     `json:"address"`             // JSONB: {"street": "string", "city": "string", "state": "string", "pincode": "string", "landmark": "string"}
-	BankingInfo        string     `json:"banking_info"`        // JSONB: {"account_number": "string", "ifsc_code": "string", "bank_name": "string", "account_holder_name": "string"}
-	Documents          string     `json:"documents"`           // JSONB: {"aadhar_card": "cloudinary_url", "pan_card": "cloudinary_url", "profile_pic": "cloudinary_url", "police_verification": "cloudinary_url"}
+	BankingInfo        string     `json:"banking_info" gorm:"serializer:encrypted"` // Encrypted JSON: {"account_number": "string", "ifsc_code": "string", "bank_name": "string", "account_holder_name": "string"}
+	Documents          string     `json:"documents" gorm:"serializer:encrypted"`    // Encrypted JSON: {"aadhar_card": "cloudinary_url", "pan_card": "cloudinary_url", "profile_pic": "cloudinary_url", "police_verification": "cloudinary_url"}
 	
 	// Skills & Experience
 	Skills             string     `json:"skills"`              // JSONB array of skill names
@@ -40,6 +42,10 @@ type Worker struct {
 	TotalJobs          int        `json:"total_jobs" gorm:"default:0"`
 	IsActive           bool       `json:"is_active" gorm:"default:false"`
 	
+	// RevealAccountNumber returns the full bank account number in JSON instead of the masked one.
+	// Only set it for admins allowed to see banking details.
+	RevealAccountNumber bool      `json:"-" gorm:"-"`
+	
 	// Relationships
 	User               User            `json:"-" gorm:"foreignKey:UserID"` // Exclude to avoid circular reference
 	RoleApplication    *RoleApplication `json:"role_application" gorm:"foreignKey:RoleApplicationID"`
@@ -83,6 +89,15 @@ type BankingInfoData struct {
 	BankName          string `json:"bank_name"`
 }
 
+// MaskAccountNumber hides all but the last four digits of a bank account number
+// Example: 123456789012 -> XXXXXXXX9012
+func MaskAccountNumber(accountNumber string) string {
+	if len(accountNumber) <= 4 {
+		return accountNumber
+	}
+	return strings.Repeat("X", len(accountNumber)-4) + accountNumber[len(accountNumber)-4:]
+}
+
 // MarshalJSON customizes the JSON serialization of Worker
 func (w Worker) MarshalJSON() ([]byte, error) {
 	// Parse contact_info JSON
@@ -131,6 +146,9 @@ func (w Worker) MarshalJSON() ([]byte, error) {
 	if w.BankingInfo != "" {
 		var bi BankingInfoData
 		if err := json.Unmarshal([]byte(w.BankingInfo), &bi); err == nil {
+			if !w.RevealAccountNumber {
+				bi.AccountNumber = MaskAccountNumber(bi.AccountNumber)
+			}
 			bankingInfo = &bi
 		}
 	}
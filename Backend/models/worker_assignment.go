@@ -16,6 +16,7 @@ const (
 	AssignmentStatusRejected AssignmentStatus = "rejected"
 	AssignmentStatusInProgress AssignmentStatus = "in_progress"
 	AssignmentStatusCompleted AssignmentStatus = "completed"
+	AssignmentStatusCancelled AssignmentStatus = "cancelled" // The booking was cancelled before the service started
 )
 
 // WorkerAssignment represents the worker assignment model
@@ -31,8 +32,10 @@ type WorkerAssignment struct {
 	
 	// Assignment Details
 	AssignedAt   time.Time        `json:"assigned_at" gorm:"not null"`
+	AcceptBy     *time.Time       `json:"accept_by"` // Acceptance deadline, after which the booking goes to another worker
 	AcceptedAt   *time.Time       `json:"accepted_at"`
 	RejectedAt   *time.Time       `json:"rejected_at"`
+	ArrivedAt    *time.Time       `json:"arrived_at"` // Worker reached the booking address
 	StartedAt    *time.Time       `json:"started_at"`
 	CompletedAt  *time.Time       `json:"completed_at"`
 	
@@ -66,11 +69,13 @@ type RejectAssignmentRequest struct {
 
 // StartServiceRequest represents the request structure for starting a service
 type StartServiceRequest struct {
+	PIN   string `json:"pin" binding:"required,len=4,numeric"` // Start PIN given by the customer
 	Notes string `json:"notes"`
 }
 
 // CompleteServiceRequest represents the request structure for completing a service
 type CompleteServiceRequest struct {
+	PIN          string   `json:"pin" binding:"required,len=4,numeric"` // Completion PIN given by the customer
 	Notes        string   `json:"notes"`
 	MaterialsUsed []string `json:"materials_used"`
 	Photos       []string `json:"photos"`
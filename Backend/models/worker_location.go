@@ -18,6 +18,11 @@ type WorkerLocation struct {
 	Longitude      float64   `json:"longitude" gorm:"not null"`
 	Accuracy       float64   `json:"accuracy,omitempty"` // GPS accuracy in meters
 	
+	// Route to the customer
+	DistanceMeters *int       `json:"distance_meters,omitempty"` // Straight-line distance to the booking address
+	ETASeconds     *int       `json:"eta_seconds,omitempty"`     // Driving time from Google Directions
+	ETAUpdatedAt   *time.Time `json:"eta_updated_at,omitempty"`
+	
 	// Status
 	Status         string    `json:"status" gorm:"default:'tracking'"` // tracking, completed, stopped
 	
@@ -56,6 +61,10 @@ type WorkerLocationResponse struct {
 	WorkerName     string    `json:"worker_name,omitempty"`
 	CustomerName   string    `json:"customer_name,omitempty"`
 	HasArrived        bool    `json:"has_arrived,omitempty"`        // Whether worker has arrived at customer location
+	ArrivedAt         *time.Time `json:"arrived_at,omitempty"`
+	DistanceMeters    *int    `json:"distance_meters,omitempty"`
+	ETASeconds        *int    `json:"eta_seconds,omitempty"`
+	ETAUpdatedAt      *time.Time `json:"eta_updated_at,omitempty"`
 }
 
 // CustomerLocationResponse represents the customer location response for workers
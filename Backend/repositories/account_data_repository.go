@@ -0,0 +1,274 @@
+package repositories
+
+import (
+	"time"
+	"treesindia/database"
+	"treesindia/models"
+
+	"gorm.io/gorm"
+)
+
+// anonymisedText replaces personal free text, such as chat messages, of deleted accounts
+const anonymisedText = "[deleted]"
+
+type AccountDataRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountDataRepository() *AccountDataRepository {
+	return &AccountDataRepository{
+		db: database.GetDB(),
+	}
+}
+
+// CreateExport creates a data export request
+func (ar *AccountDataRepository) CreateExport(export *models.DataExport) error {
+	return ar.db.Create(export).Error
+}
+
+// GetExportByID gets a data export by ID
+func (ar *AccountDataRepository) GetExportByID(id uint) (*models.DataExport, error) {
+	var export models.DataExport
+	if err := ar.db.First(&export, id).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// UpdateExport updates a data export
+func (ar *AccountDataRepository) UpdateExport(export *models.DataExport) error {
+	return ar.db.Save(export).Error
+}
+
+// GetUserExports gets a user's data exports, newest first
+func (ar *AccountDataRepository) GetUserExports(userID uint) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := ar.db.Where("user_id = ?", userID).Order("created_at DESC").Limit(20).Find(&exports).Error
+	return exports, err
+}
+
+// HasOpenExport reports whether a user has an export that is still being prepared
+func (ar *AccountDataRepository) HasOpenExport(userID uint) (bool, error) {
+	var count int64
+	err := ar.db.Model(&models.DataExport{}).
+		Where("user_id = ? AND status IN ?", userID, []models.DataExportStatus{models.DataExportStatusPending, models.DataExportStatusProcessing}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetPendingExports gets exports waiting to be prepared, including ones interrupted by a restart
+func (ar *AccountDataRepository) GetPendingExports(staleBefore time.Time) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := ar.db.Where("status = ? OR (status = ? AND updated_at < ?)",
+		models.DataExportStatusPending, models.DataExportStatusProcessing, staleBefore).
+		Order("created_at ASC").
+		Find(&exports).Error
+	return exports, err
+}
+
+// ClaimExport marks a pending or stale export as processing, returning false if another worker
+// claimed it first
+func (ar *AccountDataRepository) ClaimExport(id uint, staleBefore time.Time) (bool, error) {
+	result := ar.db.Model(&models.DataExport{}).
+		Where("id = ? AND (status = ? OR (status = ? AND updated_at < ?))",
+			id, models.DataExportStatusPending, models.DataExportStatusProcessing, staleBefore).
+		Updates(map[string]interface{}{"status": models.DataExportStatusProcessing, "updated_at": time.Now()})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// GetExpiredExports gets completed exports whose archive is past its retention period
+func (ar *AccountDataRepository) GetExpiredExports(now time.Time) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := ar.db.Where("status = ? AND expires_at < ?", models.DataExportStatusCompleted, now).Find(&exports).Error
+	return exports, err
+}
+
+// GetUserDataArchive collects everything a data export contains for a user
+func (ar *AccountDataRepository) GetUserDataArchive(userID uint) (*models.UserDataArchive, error) {
+	archive := &models.UserDataArchive{}
+
+	var user models.User
+	if err := ar.db.Preload("UserNotificationSettings").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	archive.Profile = &user
+
+	if err := ar.db.Where("user_id = ?", userID).Find(&archive.Addresses).Error; err != nil {
+		return nil, err
+	}
+	if err := ar.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&archive.Bookings).Error; err != nil {
+		return nil, err
+	}
+	if err := ar.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&archive.Payments).Error; err != nil {
+		return nil, err
+	}
+	for _, payment := range archive.Payments {
+		switch payment.Type {
+		case models.PaymentTypeWalletRecharge, models.PaymentTypeWalletDebit,
+			models.PaymentTypeWorkerEarnings, models.PaymentTypeReferralReward, models.PaymentTypeRefund:
+			archive.WalletTransactions = append(archive.WalletTransactions, payment)
+		}
+	}
+	if err := ar.db.Where("sender_id = ?", userID).Order("created_at ASC").Find(&archive.ChatMessages).Error; err != nil {
+		return nil, err
+	}
+	if err := ar.db.Where("sender_id = ?", userID).Order("created_at ASC").Find(&archive.ConversationMessages).Error; err != nil {
+		return nil, err
+	}
+	if err := ar.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&archive.Properties).Error; err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// CreateDeletionRequest creates an account deletion request
+func (ar *AccountDataRepository) CreateDeletionRequest(request *models.AccountDeletionRequest) error {
+	return ar.db.Create(request).Error
+}
+
+// UpdateDeletionRequest updates an account deletion request
+func (ar *AccountDataRepository) UpdateDeletionRequest(request *models.AccountDeletionRequest) error {
+	return ar.db.Save(request).Error
+}
+
+// GetScheduledDeletion gets a user's open account deletion request
+func (ar *AccountDataRepository) GetScheduledDeletion(userID uint) (*models.AccountDeletionRequest, error) {
+	var request models.AccountDeletionRequest
+	err := ar.db.Where("user_id = ? AND status = ?", userID, models.AccountDeletionStatusScheduled).First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetDueDeletions gets account deletion requests whose grace period has ended
+func (ar *AccountDataRepository) GetDueDeletions(now time.Time) ([]models.AccountDeletionRequest, error) {
+	var requests []models.AccountDeletionRequest
+	err := ar.db.Where("status = ? AND scheduled_for <= ?", models.AccountDeletionStatusScheduled, now).
+		Order("scheduled_for ASC").
+		Find(&requests).Error
+	return requests, err
+}
+
+// CountActiveBookings counts a user's bookings that are not finished yet
+func (ar *AccountDataRepository) CountActiveBookings(userID uint) (int64, error) {
+	var count int64
+	err := ar.db.Model(&models.Booking{}).
+		Where("user_id = ? AND status NOT IN ?", userID, []models.BookingStatus{
+			models.BookingStatusCompleted,
+			models.BookingStatusCancelled,
+			models.BookingStatusRejected,
+		}).
+		Count(&count).Error
+	return count, err
+}
+
+// AnonymiseUser removes a user's personal data and completes their deletion request in one
+// transaction. Bookings, payments and ledger entries are kept and stay linked to the user ID, whose
+// row only holds the pseudonym afterwards. Media no longer referenced is left to the media garbage
+// collector.
+func (ar *AccountDataRepository) AnonymiseUser(request *models.AccountDeletionRequest, pseudonym string) error {
+	userID := request.UserID
+	now := time.Now()
+
+	return ar.db.Transaction(func(tx *gorm.DB) error {
+		// User: the phone number must stay unique, so it becomes the pseudonym
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"name":          "Deleted User",
+			"email":         nil,
+			"phone":         "deleted-" + pseudonym,
+			"avatar":        "",
+			"gender":        "",
+			"referral_code": nil,
+			"is_active":     false,
+			"anonymised_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		// Addresses
+		if err := tx.Model(&models.Address{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+			"name":         "",
+			"address":      anonymisedText,
+			"postal_code":  "",
+			"latitude":     0,
+			"longitude":    0,
+			"landmark":     "",
+			"house_number": "",
+			"is_default":   false,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.Address{}).Error; err != nil {
+			return err
+		}
+
+		// Bookings are kept for accounting; only the contact details and street address go
+		if err := tx.Exec(`UPDATE bookings SET contact_person = '', contact_phone = '',
+			address = CASE WHEN address IS NULL THEN NULL
+				ELSE jsonb_build_object('city', address->>'city', 'state', address->>'state') END
+			WHERE user_id = ?`, userID).Error; err != nil {
+			return err
+		}
+
+		// Chat messages
+		if err := tx.Exec(`UPDATE chat_messages SET content = ?, attachments = '[]', metadata = '{}'
+			WHERE sender_id = ?`, anonymisedText, userID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SimpleConversationMessage{}).Where("sender_id = ?", userID).Updates(map[string]interface{}{
+			"message":              anonymisedText,
+			"attachment_type":      nil,
+			"image_url":            nil,
+			"video_url":            nil,
+			"cloudinary_public_id": nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		// Worker and broker profiles hold contact, bank and identity details
+		for _, table := range []string{"workers", "brokers"} {
+			if err := tx.Table(table).Where("user_id = ?", userID).Updates(map[string]interface{}{
+				"contact_info": gorm.Expr("'{}'::jsonb"),
+				"address":      gorm.Expr("'{}'::jsonb"),
+				"documents":    "",
+				"is_active":    false,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Table("workers").Where("user_id = ?", userID).
+			Update("banking_info", "").Error; err != nil {
+			return err
+		}
+
+		// Data that is only personal is removed
+		for _, model := range []interface{}{
+			&models.Property{},
+			&models.UserDocument{},
+		} {
+			if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		for _, model := range []interface{}{
+			&models.DeviceToken{},
+			&models.Location{},
+			&models.UserSearchHistory{},
+			&models.UserNotificationSettings{},
+		} {
+			if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		request.Status = models.AccountDeletionStatusCompleted
+		request.CompletedAt = &now
+		request.Pseudonym = &pseudonym
+		return tx.Save(request).Error
+	})
+}
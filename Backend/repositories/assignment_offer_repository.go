@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"time"
+	"treesindia/database"
+	"treesindia/models"
+
+	"gorm.io/gorm"
+)
+
+type AssignmentOfferRepository struct {
+	db *gorm.DB
+}
+
+func NewAssignmentOfferRepository() *AssignmentOfferRepository {
+	return &AssignmentOfferRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create creates an assignment offer
+func (aor *AssignmentOfferRepository) Create(offer *models.AssignmentOffer) error {
+	return aor.db.Create(offer).Error
+}
+
+// RespondToOffer records a worker's response to their open offer for an assignment
+func (aor *AssignmentOfferRepository) RespondToOffer(assignmentID uint, workerID uint, status models.AssignmentOfferStatus, reason string) error {
+	return aor.db.Model(&models.AssignmentOffer{}).
+		Where("assignment_id = ? AND worker_id = ? AND status = ?", assignmentID, workerID, models.AssignmentOfferStatusOffered).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"responded_at": time.Now(),
+			"reason":       reason,
+		}).Error
+}
+
+// GetBookingOffers gets the offers made for a booking, oldest first
+func (aor *AssignmentOfferRepository) GetBookingOffers(bookingID uint) ([]models.AssignmentOffer, error) {
+	var offers []models.AssignmentOffer
+	err := aor.db.Where("booking_id = ?", bookingID).Order("offered_at ASC").Find(&offers).Error
+	return offers, err
+}
+
+// GetAcceptanceStats gets how workers responded to offers made since the given time. Offers still
+// waiting for a response or withdrawn are not counted.
+func (aor *AssignmentOfferRepository) GetAcceptanceStats(workerIDs []uint, since time.Time) (map[uint]models.WorkerAcceptanceStats, error) {
+	stats := make(map[uint]models.WorkerAcceptanceStats)
+	if len(workerIDs) == 0 {
+		return stats, nil
+	}
+
+	var rows []models.WorkerAcceptanceStats
+	err := aor.db.Model(&models.AssignmentOffer{}).
+		Select(`worker_id,
+			COUNT(*) AS offered,
+			COUNT(*) FILTER (WHERE status = ?) AS accepted,
+			COUNT(*) FILTER (WHERE status = ?) AS rejected,
+			COUNT(*) FILTER (WHERE status = ?) AS expired`,
+			models.AssignmentOfferStatusAccepted, models.AssignmentOfferStatusRejected, models.AssignmentOfferStatusExpired).
+		Where("worker_id IN ? AND offered_at >= ? AND status IN ?", workerIDs, since, []models.AssignmentOfferStatus{
+			models.AssignmentOfferStatusAccepted, models.AssignmentOfferStatusRejected, models.AssignmentOfferStatusExpired,
+		}).
+		Group("worker_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if row.Offered > 0 {
+			row.AcceptanceRate = float64(row.Accepted) / float64(row.Offered)
+		}
+		stats[row.WorkerID] = row
+	}
+	for _, workerID := range workerIDs {
+		if _, ok := stats[workerID]; !ok {
+			stats[workerID] = models.WorkerAcceptanceStats{WorkerID: workerID}
+		}
+	}
+	return stats, nil
+}
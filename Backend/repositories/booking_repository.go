@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 	"treesindia/database"
+	"treesindia/metrics"
 	"treesindia/models"
 
 	"github.com/sirupsen/logrus"
@@ -32,6 +33,7 @@ func (br *BookingRepository) Create(booking *models.Booking) (*models.Booking, e
 	if err != nil {
 		return nil, err
 	}
+	metrics.RecordBookingCreated(string(booking.BookingType))
 	return booking, nil
 }
 
@@ -63,8 +65,11 @@ func (br *BookingRepository) GetByID(id uint) (*models.Booking, error) {
 func (br *BookingRepository) Update(booking *models.Booking) error {
 	// Use Omit to exclude preloaded relationships from being saved
 	// This prevents issues when booking has preloaded User, Service, WorkerAssignment, etc.
+	// The PIN attempt columns are only changed by their own atomic updates, so a stale booking
+	// cannot write them back.
 	return br.db.Model(booking).
-		Omit("User", "Service", "WorkerAssignment", "BufferRequests", "PaymentSegments", "Payment", "CreatedAt").
+		Omit("User", "Service", "WorkerAssignment", "BufferRequests", "PaymentSegments", "Payment", "CreatedAt",
+			"PINFailedAttempts", "PINLockedUntil").
 		Save(booking).Error
 }
 
@@ -769,6 +774,7 @@ func (br *BookingRepository) convertToOptimizedResponse(booking *models.Booking)
 		HoldExpiresAt:         booking.HoldExpiresAt,
 		CreatedAt:             booking.CreatedAt,
 		UpdatedAt:             booking.UpdatedAt,
+		PriceBreakdown:        booking.PriceBreakdown,
 		Service: &models.OptimizedServiceInfo{
 			ID:        booking.Service.ID,
 			Name:      booking.Service.Name,
@@ -1029,6 +1035,77 @@ func (br *BookingRepository) GetInquiryBookings(filters *InquiryBookingFilters)
 	return bookings, pagination, nil
 }
 
+// GetQuotesExpiringBefore gets live quotes that expire before the given time and have not been warned about yet
+func (br *BookingRepository) GetQuotesExpiringBefore(before time.Time) ([]models.Booking, error) {
+	var bookings []models.Booking
+	err := br.db.Where("booking_type = ? AND status = ? AND quote_expires_at IS NOT NULL AND quote_expires_at > NOW() AND quote_expires_at <= ? AND quote_expiry_warning_sent_at IS NULL",
+		models.BookingTypeInquiry, models.BookingStatusQuoteProvided, before).
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// MarkQuoteExpiryWarningSent records that the expiry warning was sent for a booking
+func (br *BookingRepository) MarkQuoteExpiryWarningSent(bookingID uint, sentAt time.Time) error {
+	return br.db.Model(&models.Booking{}).Where("id = ?", bookingID).Update("quote_expiry_warning_sent_at", sentAt).Error
+}
+
+// CountServicePINAttempt counts a service PIN attempt before the PIN is checked and returns the
+// attempts made since the last reset. It returns 0 when PIN entry is locked. The count is taken in
+// a single statement, so concurrent guesses can't get past the limit.
+func (br *BookingRepository) CountServicePINAttempt(bookingID uint) (int, error) {
+	var attempts []int
+	err := br.db.Raw(`UPDATE bookings SET pin_failed_attempts = pin_failed_attempts + 1
+		WHERE id = ? AND (pin_locked_until IS NULL OR pin_locked_until <= NOW())
+		RETURNING pin_failed_attempts`, bookingID).Scan(&attempts).Error
+	if err != nil || len(attempts) == 0 {
+		return 0, err
+	}
+	return attempts[0], nil
+}
+
+// LockServicePINEntry locks PIN entry for a booking until the given time and starts the count again
+func (br *BookingRepository) LockServicePINEntry(bookingID uint, lockUntil time.Time) error {
+	return br.db.Model(&models.Booking{}).Where("id = ?", bookingID).Updates(map[string]interface{}{
+		"pin_failed_attempts": 0,
+		"pin_locked_until":    lockUntil,
+	}).Error
+}
+
+// ResetServicePINAttempts clears the incorrect service PIN count and lock of a booking
+func (br *BookingRepository) ResetServicePINAttempts(bookingID uint) error {
+	return br.db.Model(&models.Booking{}).Where("id = ?", bookingID).Updates(map[string]interface{}{
+		"pin_failed_attempts": 0,
+		"pin_locked_until":    nil,
+	}).Error
+}
+
+// MarkAssignmentEscalated records that a booking's worker assignment was handed to admins
+func (br *BookingRepository) MarkAssignmentEscalated(bookingID uint, escalatedAt time.Time) error {
+	return br.db.Model(&models.Booking{}).Where("id = ?", bookingID).Update("assignment_escalated_at", escalatedAt).Error
+}
+
+// GetEscalatedAssignmentBookings gets bookings waiting for an admin to assign a worker after
+// automatic reassignment gave up
+func (br *BookingRepository) GetEscalatedAssignmentBookings() ([]models.Booking, error) {
+	var bookings []models.Booking
+	err := br.db.Where("assignment_escalated_at IS NOT NULL AND status = ?", models.BookingStatusConfirmed).
+		Preload("User").
+		Preload("Service").
+		Order("assignment_escalated_at ASC").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// CountPaidBookingsByUser counts a user's bookings that have been paid for, excluding one booking
+func (br *BookingRepository) CountPaidBookingsByUser(userID uint, excludeBookingID uint) (int64, error) {
+	var count int64
+	err := br.db.Model(&models.Booking{}).
+		Where("user_id = ? AND id <> ? AND payment_status IN ?", userID, excludeBookingID,
+			[]models.PaymentStatus{models.PaymentStatusCompleted, "partial"}).
+		Count(&count).Error
+	return count, err
+}
+
 // GetExpiredQuotes gets all quotes that have expired
 func (br *BookingRepository) GetExpiredQuotes() ([]models.Booking, error) {
 	var bookings []models.Booking
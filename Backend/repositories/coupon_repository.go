@@ -0,0 +1,272 @@
+package repositories
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"treesindia/database"
+	"treesindia/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// activeRedemptionStatuses are redemptions that count towards coupon limits
+var activeRedemptionStatuses = []models.CouponRedemptionStatus{
+	models.CouponRedemptionStatusReserved,
+	models.CouponRedemptionStatusApplied,
+}
+
+type CouponRepository struct {
+	db *gorm.DB
+}
+
+func NewCouponRepository() *CouponRepository {
+	return &CouponRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create creates a coupon
+func (cr *CouponRepository) Create(coupon *models.Coupon) error {
+	return cr.db.Create(coupon).Error
+}
+
+// GetByID gets a coupon by ID
+func (cr *CouponRepository) GetByID(id uint) (*models.Coupon, error) {
+	var coupon models.Coupon
+	err := cr.db.First(&coupon, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// GetByCode gets a coupon by its code (case-insensitive)
+func (cr *CouponRepository) GetByCode(code string) (*models.Coupon, error) {
+	var coupon models.Coupon
+	err := cr.db.Where("code = ?", strings.ToUpper(strings.TrimSpace(code))).First(&coupon).Error
+	if err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// Update updates a coupon
+func (cr *CouponRepository) Update(coupon *models.Coupon) error {
+	return cr.db.Save(coupon).Error
+}
+
+// GetCoupons gets coupons with filters and pagination
+func (cr *CouponRepository) GetCoupons(filters *models.CouponFilters) ([]models.Coupon, *Pagination, error) {
+	var coupons []models.Coupon
+	var total int64
+
+	query := cr.db.Model(&models.Coupon{})
+
+	// Apply filters
+	if filters.Search != "" {
+		search := "%" + strings.ToUpper(filters.Search) + "%"
+		query = query.Where("code LIKE ? OR UPPER(description) LIKE ?", search, search)
+	}
+	if filters.Scope != "" {
+		query = query.Where("scope = ?", filters.Scope)
+	}
+	if filters.IsActive != nil {
+		query = query.Where("is_active = ?", *filters.IsActive)
+	}
+
+	// Count total
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Apply pagination
+	page := filters.Page
+	if page <= 0 {
+		page = 1
+	}
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset := (page - 1) * limit
+	err = query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&coupons).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Calculate pagination
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	pagination := &Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	return coupons, pagination, nil
+}
+
+// CountRedemptions counts reserved and applied redemptions of a coupon, optionally for one user
+func (cr *CouponRepository) CountRedemptions(couponID uint, userID *uint) (int64, error) {
+	return countRedemptions(cr.db, couponID, userID)
+}
+
+// CreateRedemption records a redemption if the coupon's limits still allow it.
+// The coupon row is locked so concurrent checkouts cannot exceed the limits.
+func (cr *CouponRepository) CreateRedemption(redemption *models.CouponRedemption) error {
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		var coupon models.Coupon
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&coupon, redemption.CouponID).Error; err != nil {
+			return err
+		}
+
+		if coupon.UsageLimit != nil {
+			used, err := countRedemptions(tx, coupon.ID, nil)
+			if err != nil {
+				return err
+			}
+			if used >= int64(*coupon.UsageLimit) {
+				return errors.New("coupon usage limit reached")
+			}
+		}
+
+		if coupon.PerUserLimit > 0 {
+			used, err := countRedemptions(tx, coupon.ID, &redemption.UserID)
+			if err != nil {
+				return err
+			}
+			if used >= int64(coupon.PerUserLimit) {
+				return errors.New("you have already used this coupon")
+			}
+		}
+
+		return tx.Create(redemption).Error
+	})
+}
+
+// AttachPayment links a redemption to the payment it discounted
+func (cr *CouponRepository) AttachPayment(redemptionID uint, paymentID uint) error {
+	return cr.db.Model(&models.CouponRedemption{}).
+		Where("id = ?", redemptionID).
+		Update("payment_id", paymentID).Error
+}
+
+// ApplyByPaymentID marks the reserved redemption of a completed payment as applied
+func (cr *CouponRepository) ApplyByPaymentID(paymentID uint, appliedAt time.Time) (int64, error) {
+	result := cr.db.Model(&models.CouponRedemption{}).
+		Where("payment_id = ? AND status = ?", paymentID, models.CouponRedemptionStatusReserved).
+		Updates(map[string]interface{}{
+			"status":     models.CouponRedemptionStatusApplied,
+			"applied_at": appliedAt,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ApplyByID marks a reserved redemption as applied
+func (cr *CouponRepository) ApplyByID(redemptionID uint, appliedAt time.Time) error {
+	return cr.db.Model(&models.CouponRedemption{}).
+		Where("id = ? AND status = ?", redemptionID, models.CouponRedemptionStatusReserved).
+		Updates(map[string]interface{}{
+			"status":     models.CouponRedemptionStatusApplied,
+			"applied_at": appliedAt,
+		}).Error
+}
+
+// ReleaseReserved gives back reserved redemptions of an entity whose payment never completed
+func (cr *CouponRepository) ReleaseReserved(relatedEntityType string, relatedEntityID uint, userID uint, releasedAt time.Time) (int64, error) {
+	result := cr.db.Model(&models.CouponRedemption{}).
+		Where("related_entity_type = ? AND related_entity_id = ? AND user_id = ? AND status = ?",
+			relatedEntityType, relatedEntityID, userID, models.CouponRedemptionStatusReserved).
+		Updates(map[string]interface{}{
+			"status":      models.CouponRedemptionStatusReleased,
+			"released_at": releasedAt,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// GetRedemptions gets redemptions with pagination, optionally for one coupon
+func (cr *CouponRepository) GetRedemptions(couponID *uint, status models.CouponRedemptionStatus, page, limit int) ([]models.CouponRedemption, *Pagination, error) {
+	var redemptions []models.CouponRedemption
+	var total int64
+
+	query := cr.db.Model(&models.CouponRedemption{})
+	if couponID != nil {
+		query = query.Where("coupon_id = ?", *couponID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset := (page - 1) * limit
+	err = query.Preload("User").Order("created_at DESC").Offset(offset).Limit(limit).Find(&redemptions).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	pagination := &Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	return redemptions, pagination, nil
+}
+
+// GetUsageReport summarises redemptions per coupon, optionally within a date range
+func (cr *CouponRepository) GetUsageReport(startDate, endDate string) ([]models.CouponUsageReport, error) {
+	var report []models.CouponUsageReport
+
+	query := cr.db.Model(&models.CouponRedemption{}).
+		Select(`coupon_id, code,
+			COUNT(*) FILTER (WHERE status = ?) AS applied_count,
+			COUNT(*) FILTER (WHERE status = ?) AS reserved_count,
+			COALESCE(SUM(discount_amount) FILTER (WHERE status = ?), 0) AS total_discount,
+			COALESCE(SUM(final_amount) FILTER (WHERE status = ?), 0) AS total_final_value`,
+			models.CouponRedemptionStatusApplied, models.CouponRedemptionStatusReserved,
+			models.CouponRedemptionStatusApplied, models.CouponRedemptionStatusApplied)
+	if startDate != "" {
+		query = query.Where("created_at >= ?", startDate)
+	}
+	if endDate != "" {
+		query = query.Where("created_at <= ?", endDate)
+	}
+
+	err := query.Group("coupon_id, code").Order("total_discount DESC").Scan(&report).Error
+	return report, err
+}
+
+// countRedemptions counts redemptions that use up a coupon
+func countRedemptions(db *gorm.DB, couponID uint, userID *uint) (int64, error) {
+	var count int64
+	query := db.Model(&models.CouponRedemption{}).
+		Where("coupon_id = ? AND status IN ?", couponID, activeRedemptionStatuses)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
@@ -2,7 +2,11 @@ package repositories
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 	"treesindia/database"
+	"treesindia/metrics"
 	"treesindia/models"
 
 	"gorm.io/gorm"
@@ -172,7 +176,7 @@ func (dr *DashboardRepository) GetUserAnalytics() (*models.UserAnalytics, error)
 }
 
 // GetBookingAnalytics gets booking-related analytics
-func (dr *DashboardRepository) GetBookingAnalytics() (*models.BookingAnalytics, error) {
+func (dr *DashboardRepository) GetBookingAnalytics(thresholds models.DashboardAlertThresholds) (*models.BookingAnalytics, error) {
 	analytics := &models.BookingAnalytics{}
 
 	// Get booking trends (last 12 months)
@@ -197,7 +201,7 @@ func (dr *DashboardRepository) GetBookingAnalytics() (*models.BookingAnalytics,
 	analytics.RecentBookings = recentBookings
 
 	// Get urgent alerts
-	urgentAlerts, err := dr.GetUrgentAlerts()
+	urgentAlerts, err := dr.GetUrgentAlerts(thresholds)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get urgent alerts: %w", err)
 	}
@@ -396,14 +400,15 @@ func (dr *DashboardRepository) GetSystemHealth() (*models.SystemHealth, error) {
 	}
 	health.ActiveSessions = int(activeSessions)
 
-	// Set API response time (this would be measured in middleware)
-	health.APIResponseTime = "0ms" // Will be measured when implemented
-
-	// Set uptime (this would be calculated from application start time)
-	health.Uptime = "0%" // Will be calculated when implemented
-
-	// Calculate error rate (simplified)
-	health.ErrorRate = 0.0 // Will be calculated when implemented
+	// Read response time, uptime and error rate from the request metrics
+	snapshot, err := metrics.GetSnapshot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request metrics: %w", err)
+	}
+	health.APIResponseTime = snapshot.AverageResponseTime.Round(time.Millisecond).String()
+	health.ErrorRate = snapshot.ErrorRate()
+	health.Uptime = fmt.Sprintf("%.2f%%", 100-health.ErrorRate)
+	health.UptimeDuration = snapshot.Uptime.Round(time.Second).String()
 
 	return health, nil
 }
@@ -693,8 +698,106 @@ func (dr *DashboardRepository) getAverageBookingValue() (float64, error) {
 	return avgValue, nil
 }
 
-func (dr *DashboardRepository) GetUrgentAlerts() ([]models.UrgentAlert, error) {
-	return []models.UrgentAlert{}, nil
+// GetUrgentAlerts gets bookings and payouts that need attention now
+func (dr *DashboardRepository) GetUrgentAlerts(thresholds models.DashboardAlertThresholds) ([]models.UrgentAlert, error) {
+	alerts := []models.UrgentAlert{}
+	now := time.Now()
+
+	var bookings []struct {
+		ID               uint
+		BookingReference string
+		ServiceName      string
+		ScheduledTime    time.Time
+		CreatedAt        time.Time
+	}
+
+	// Bookings starting soon that no worker has accepted yet
+	err := dr.db.Table("bookings b").
+		Select("b.id, b.booking_reference, s.name AS service_name, b.scheduled_time, b.created_at").
+		Joins("LEFT JOIN services s ON s.id = b.service_id").
+		Where("b.deleted_at IS NULL AND b.status IN ? AND b.scheduled_time BETWEEN ? AND ?",
+			[]models.BookingStatus{
+				models.BookingStatusConfirmed,
+				models.BookingStatusScheduled,
+				models.BookingStatusPartiallyPaid,
+				models.BookingStatusAssigned,
+			},
+			now, now.Add(time.Duration(thresholds.UnassignedBookingHours)*time.Hour)).
+		Where("NOT EXISTS (SELECT 1 FROM worker_assignments wa WHERE wa.booking_id = b.id AND wa.deleted_at IS NULL AND wa.status IN ?)",
+			[]models.AssignmentStatus{models.AssignmentStatusAccepted, models.AssignmentStatusInProgress}).
+		Order("b.scheduled_time ASC").
+		Scan(&bookings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unassigned bookings: %w", err)
+	}
+	for _, booking := range bookings {
+		alerts = append(alerts, models.UrgentAlert{
+			ID:        booking.ID,
+			Type:      "booking",
+			Title:     "Booking starting without a worker",
+			Message:   fmt.Sprintf("%s booking %s starts in %s and no worker has accepted it", booking.ServiceName, booking.BookingReference, booking.ScheduledTime.Sub(now).Round(time.Minute)),
+			Priority:  "high",
+			CreatedAt: booking.CreatedAt,
+			ActionURL: fmt.Sprintf("/dashboard/bookings/%d", booking.ID),
+		})
+	}
+
+	// Bookings whose start time has passed but work has not begun
+	bookings = nil
+	err = dr.db.Table("bookings b").
+		Select("b.id, b.booking_reference, s.name AS service_name, b.scheduled_time, b.created_at").
+		Joins("LEFT JOIN services s ON s.id = b.service_id").
+		Where("b.deleted_at IS NULL AND b.status IN ? AND b.scheduled_time BETWEEN ? AND ?",
+			[]models.BookingStatus{models.BookingStatusConfirmed, models.BookingStatusScheduled, models.BookingStatusAssigned},
+			now.Add(-24*time.Hour), now).
+		Order("b.scheduled_time ASC").
+		Scan(&bookings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get overdue bookings: %w", err)
+	}
+	for _, booking := range bookings {
+		alerts = append(alerts, models.UrgentAlert{
+			ID:        booking.ID,
+			Type:      "booking",
+			Title:     "Booking not started",
+			Message:   fmt.Sprintf("%s booking %s was due %s ago and has not started", booking.ServiceName, booking.BookingReference, now.Sub(booking.ScheduledTime).Round(time.Minute)),
+			Priority:  "high",
+			CreatedAt: booking.CreatedAt,
+			ActionURL: fmt.Sprintf("/dashboard/bookings/%d", booking.ID),
+		})
+	}
+
+	// Worker withdrawals waiting too long for processing
+	var withdrawals []struct {
+		ID        uint
+		Amount    float64
+		UserName  string
+		CreatedAt time.Time
+	}
+	err = dr.db.Table("payments p").
+		Select("p.id, p.amount, u.name AS user_name, p.created_at").
+		Joins("LEFT JOIN users u ON u.id = p.user_id").
+		Where("p.deleted_at IS NULL AND p.type = ? AND p.status = ? AND p.created_at < ?",
+			models.PaymentTypeWorkerWithdrawal, models.PaymentStatusPending,
+			now.Add(-time.Duration(thresholds.WithdrawalPendingHours)*time.Hour)).
+		Order("p.created_at ASC").
+		Scan(&withdrawals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending withdrawals: %w", err)
+	}
+	for _, withdrawal := range withdrawals {
+		alerts = append(alerts, models.UrgentAlert{
+			ID:        withdrawal.ID,
+			Type:      "payment",
+			Title:     "Withdrawal pending",
+			Message:   fmt.Sprintf("Withdrawal of ₹%.2f by %s has been pending for %s", withdrawal.Amount, withdrawal.UserName, now.Sub(withdrawal.CreatedAt).Round(time.Hour)),
+			Priority:  "medium",
+			CreatedAt: withdrawal.CreatedAt,
+			ActionURL: "/dashboard/worker-withdrawals",
+		})
+	}
+
+	return alerts, nil
 }
 
 func (dr *DashboardRepository) getPopularServices(limit int) ([]models.ServicePerformance, error) {
@@ -768,8 +871,58 @@ func (dr *DashboardRepository) getCategoryPerformance() ([]models.CategoryPerfor
 	return categories, nil
 }
 
+// getServiceAreas gets booking volume, active workers and assignment coverage per city
 func (dr *DashboardRepository) getServiceAreas() ([]models.ServiceAreaData, error) {
-	return []models.ServiceAreaData{}, nil
+	var bookingAreas []struct {
+		AreaName         string
+		TotalBookings    int
+		AssignedBookings int
+	}
+	err := dr.db.Table("bookings b").
+		Select(`INITCAP(TRIM(b.address->>'city')) AS area_name, COUNT(b.id) AS total_bookings,
+			COUNT(b.id) FILTER (WHERE EXISTS (SELECT 1 FROM worker_assignments wa WHERE wa.booking_id = b.id AND wa.deleted_at IS NULL)) AS assigned_bookings`).
+		Where("b.deleted_at IS NULL AND COALESCE(TRIM(b.address->>'city'), '') <> ''").
+		Group("INITCAP(TRIM(b.address->>'city'))").
+		Order("total_bookings DESC").
+		Limit(20).
+		Scan(&bookingAreas).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var workerAreas []struct {
+		AreaName      string
+		ActiveWorkers int
+	}
+	err = dr.db.Table("workers").
+		Select("INITCAP(TRIM(address->>'city')) AS area_name, COUNT(*) AS active_workers").
+		Where("deleted_at IS NULL AND is_active = ? AND COALESCE(TRIM(address->>'city'), '') <> ''", true).
+		Group("INITCAP(TRIM(address->>'city'))").
+		Scan(&workerAreas).Error
+	if err != nil {
+		return nil, err
+	}
+
+	activeWorkers := make(map[string]int)
+	for _, area := range workerAreas {
+		activeWorkers[area.AreaName] = area.ActiveWorkers
+	}
+
+	areas := []models.ServiceAreaData{}
+	for _, area := range bookingAreas {
+		coverage := 0.0
+		if area.TotalBookings > 0 {
+			coverage = float64(area.AssignedBookings) / float64(area.TotalBookings) * 100
+		}
+		areas = append(areas, models.ServiceAreaData{
+			AreaName:      area.AreaName,
+			TotalBookings: area.TotalBookings,
+			ActiveWorkers: activeWorkers[area.AreaName],
+			Coverage:      coverage,
+		})
+	}
+
+	return areas, nil
 }
 
 func (dr *DashboardRepository) getServiceTrends() ([]models.MonthlyData, error) {
@@ -1047,11 +1200,90 @@ func (dr *DashboardRepository) getSubscriptionAnalytics() (*models.SubscriptionA
 }
 
 func (dr *DashboardRepository) getChatAnalytics() (*models.ChatAnalytics, error) {
-	return &models.ChatAnalytics{}, nil
+	analytics := &models.ChatAnalytics{}
+
+	// Get total chats
+	var totalChats int64
+	err := dr.db.Model(&models.ChatRoom{}).Count(&totalChats).Error
+	if err != nil {
+		return analytics, err
+	}
+	analytics.TotalChats = int(totalChats)
+
+	// Get chats with messages in the last 7 days
+	var activeChats int64
+	err = dr.db.Model(&models.ChatRoom{}).
+		Where("is_active = ? AND last_message_at >= NOW() - INTERVAL '7 days'", true).
+		Count(&activeChats).Error
+	if err != nil {
+		activeChats = 0
+	}
+	analytics.ActiveChats = int(activeChats)
+
+	// Average minutes until the other party replies (last 30 days)
+	var averageResponseTime float64
+	err = dr.db.Raw(`
+		SELECT COALESCE(AVG(EXTRACT(EPOCH FROM (next_at - created_at)) / 60), 0)
+		FROM (
+			SELECT created_at, sender_id,
+				LEAD(created_at) OVER (PARTITION BY room_id ORDER BY created_at) AS next_at,
+				LEAD(sender_id) OVER (PARTITION BY room_id ORDER BY created_at) AS next_sender_id
+			FROM chat_messages
+			WHERE deleted_at IS NULL AND created_at >= NOW() - INTERVAL '30 days'
+		) replies
+		WHERE next_sender_id IS NOT NULL AND next_sender_id <> sender_id`).
+		Scan(&averageResponseTime).Error
+	if err != nil {
+		averageResponseTime = 0
+	}
+	analytics.AverageResponseTime = averageResponseTime
+
+	// Get chat trends
+	chatTrends, err := dr.getChatTrends()
+	if err != nil {
+		chatTrends = []models.MonthlyData{}
+	}
+	analytics.ChatTrends = chatTrends
+
+	return analytics, nil
 }
 
 func (dr *DashboardRepository) getNotificationAnalytics() (*models.NotificationAnalytics, error) {
-	return &models.NotificationAnalytics{}, nil
+	analytics := &models.NotificationAnalytics{}
+
+	// Get total notifications
+	var totalNotifications int64
+	err := dr.db.Model(&models.PushNotification{}).Count(&totalNotifications).Error
+	if err != nil {
+		return analytics, err
+	}
+	analytics.TotalNotifications = int(totalNotifications)
+
+	// Get notifications accepted by FCM or confirmed delivered
+	var deliveredNotifications int64
+	err = dr.db.Model(&models.PushNotification{}).
+		Where("status IN ?", []models.NotificationStatus{models.NotificationStatusSent, models.NotificationStatusDelivered}).
+		Count(&deliveredNotifications).Error
+	if err != nil {
+		deliveredNotifications = 0
+	}
+	analytics.DeliveredNotifications = int(deliveredNotifications)
+
+	// Calculate delivery rate
+	if totalNotifications > 0 {
+		analytics.DeliveryRate = float64(deliveredNotifications) / float64(totalNotifications) * 100
+	} else {
+		analytics.DeliveryRate = 0.0
+	}
+
+	// Get notification trends
+	notificationTrends, err := dr.getNotificationTrends()
+	if err != nil {
+		notificationTrends = []models.MonthlyData{}
+	}
+	analytics.NotificationTrends = notificationTrends
+
+	return analytics, nil
 }
 
 // calculateRevenueGrowth calculates the revenue growth percentage compared to last month
@@ -1141,16 +1373,321 @@ func (dr *DashboardRepository) getSubscriptionTrends() ([]models.MonthlyData, er
 	return trends, nil
 }
 
-func (dr *DashboardRepository) GetRecentActivity() ([]models.RecentActivity, error) {
-	return []models.RecentActivity{}, nil
+// GetRecentActivity gets the latest sign-ups, bookings, payments, applications and listings
+func (dr *DashboardRepository) GetRecentActivity(limit int) ([]models.RecentActivity, error) {
+	activities := []models.RecentActivity{}
+
+	var users []struct {
+		ID        uint
+		Name      string
+		UserType  string
+		CreatedAt time.Time
+	}
+	err := dr.db.Model(&models.User{}).
+		Select("id, name, user_type, created_at").
+		Order("created_at DESC").
+		Limit(limit).
+		Scan(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent users: %w", err)
+	}
+	for _, user := range users {
+		activities = append(activities, models.RecentActivity{
+			ID:        user.ID,
+			Type:      "user",
+			Title:     "New user registered",
+			Message:   fmt.Sprintf("%s joined as %s", user.Name, user.UserType),
+			CreatedAt: user.CreatedAt,
+			UserID:    user.ID,
+			UserName:  user.Name,
+		})
+	}
+
+	var bookings []struct {
+		ID               uint
+		BookingReference string
+		ServiceName      string
+		UserID           uint
+		UserName         string
+		CreatedAt        time.Time
+	}
+	err = dr.db.Table("bookings b").
+		Select("b.id, b.booking_reference, s.name AS service_name, b.user_id, u.name AS user_name, b.created_at").
+		Joins("LEFT JOIN services s ON s.id = b.service_id").
+		Joins("LEFT JOIN users u ON u.id = b.user_id").
+		Where("b.deleted_at IS NULL").
+		Order("b.created_at DESC").
+		Limit(limit).
+		Scan(&bookings).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent bookings: %w", err)
+	}
+	for _, booking := range bookings {
+		activities = append(activities, models.RecentActivity{
+			ID:        booking.ID,
+			Type:      "booking",
+			Title:     "New booking",
+			Message:   fmt.Sprintf("%s booked %s (%s)", booking.UserName, booking.ServiceName, booking.BookingReference),
+			CreatedAt: booking.CreatedAt,
+			UserID:    booking.UserID,
+			UserName:  booking.UserName,
+		})
+	}
+
+	var payments []struct {
+		ID          uint
+		Amount      float64
+		Type        string
+		UserID      uint
+		UserName    string
+		CompletedAt time.Time
+	}
+	err = dr.db.Table("payments p").
+		Select("p.id, p.amount, p.type, p.user_id, u.name AS user_name, COALESCE(p.completed_at, p.updated_at) AS completed_at").
+		Joins("LEFT JOIN users u ON u.id = p.user_id").
+		Where("p.deleted_at IS NULL AND p.status = ?", models.PaymentStatusCompleted).
+		Order("completed_at DESC").
+		Limit(limit).
+		Scan(&payments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent payments: %w", err)
+	}
+	for _, payment := range payments {
+		activities = append(activities, models.RecentActivity{
+			ID:        payment.ID,
+			Type:      "payment",
+			Title:     "Payment completed",
+			Message:   fmt.Sprintf("%s payment of ₹%.2f by %s", strings.ReplaceAll(payment.Type, "_", " "), payment.Amount, payment.UserName),
+			CreatedAt: payment.CompletedAt,
+			UserID:    payment.UserID,
+			UserName:  payment.UserName,
+		})
+	}
+
+	var applications []struct {
+		ID            uint
+		RequestedRole string
+		UserID        uint
+		UserName      string
+		SubmittedAt   time.Time
+	}
+	err = dr.db.Table("role_applications ra").
+		Select("ra.id, ra.requested_role, ra.user_id, u.name AS user_name, ra.submitted_at").
+		Joins("LEFT JOIN users u ON u.id = ra.user_id").
+		Where("ra.deleted_at IS NULL").
+		Order("ra.submitted_at DESC").
+		Limit(limit).
+		Scan(&applications).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent role applications: %w", err)
+	}
+	for _, application := range applications {
+		activities = append(activities, models.RecentActivity{
+			ID:        application.ID,
+			Type:      "role_application",
+			Title:     "Role application submitted",
+			Message:   fmt.Sprintf("%s applied to become a %s", application.UserName, application.RequestedRole),
+			CreatedAt: application.SubmittedAt,
+			UserID:    application.UserID,
+			UserName:  application.UserName,
+		})
+	}
+
+	var properties []struct {
+		ID        uint
+		Title     string
+		City      string
+		UserID    uint
+		UserName  string
+		CreatedAt time.Time
+	}
+	err = dr.db.Table("properties p").
+		Select("p.id, p.title, p.city, p.user_id, u.name AS user_name, p.created_at").
+		Joins("LEFT JOIN users u ON u.id = p.user_id").
+		Where("p.deleted_at IS NULL").
+		Order("p.created_at DESC").
+		Limit(limit).
+		Scan(&properties).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent properties: %w", err)
+	}
+	for _, property := range properties {
+		activities = append(activities, models.RecentActivity{
+			ID:        property.ID,
+			Type:      "property",
+			Title:     "Property listed",
+			Message:   fmt.Sprintf("%s listed %s in %s", property.UserName, property.Title, property.City),
+			CreatedAt: property.CreatedAt,
+			UserID:    property.UserID,
+			UserName:  property.UserName,
+		})
+	}
+
+	sort.Slice(activities, func(i, j int) bool {
+		return activities[i].CreatedAt.After(activities[j].CreatedAt)
+	})
+	if len(activities) > limit {
+		activities = activities[:limit]
+	}
+
+	return activities, nil
 }
 
-func (dr *DashboardRepository) GetSystemAlerts() ([]models.SystemAlert, error) {
-	return []models.SystemAlert{}, nil
+// GetSystemAlerts gets alerts about failing push delivery and unanswered chats
+func (dr *DashboardRepository) GetSystemAlerts(thresholds models.DashboardAlertThresholds) ([]models.SystemAlert, error) {
+	alerts := []models.SystemAlert{}
+	now := time.Now()
+
+	// Failed push notifications within the window
+	var failedPushes struct {
+		Total    int
+		LatestID uint
+		Latest   *time.Time
+	}
+	err := dr.db.Model(&models.PushNotification{}).
+		Select("COUNT(*) AS total, COALESCE(MAX(id), 0) AS latest_id, MAX(created_at) AS latest").
+		Where("status = ? AND created_at >= ?", models.NotificationStatusFailed,
+			now.Add(-time.Duration(thresholds.FailedPushWindowHours)*time.Hour)).
+		Scan(&failedPushes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count failed push notifications: %w", err)
+	}
+	if failedPushes.Total >= thresholds.FailedPushThreshold && failedPushes.Latest != nil {
+		severity := "warning"
+		if failedPushes.Total >= thresholds.FailedPushThreshold*2 {
+			severity = "critical"
+		}
+		alerts = append(alerts, models.SystemAlert{
+			ID:        failedPushes.LatestID,
+			Type:      "push_notification",
+			Severity:  severity,
+			Message:   fmt.Sprintf("%d push notifications failed in the last %d hours", failedPushes.Total, thresholds.FailedPushWindowHours),
+			CreatedAt: *failedPushes.Latest,
+		})
+	}
+
+	// Active chat rooms whose latest message has gone unread
+	var unansweredChats struct {
+		Total    int
+		OldestID uint
+		Oldest   *time.Time
+	}
+	err = dr.db.Raw(`
+		SELECT COUNT(*) AS total, COALESCE((ARRAY_AGG(r.id ORDER BY m.created_at))[1], 0) AS oldest_id, MIN(m.created_at) AS oldest
+		FROM chat_rooms r
+		JOIN LATERAL (
+			SELECT created_at, is_read FROM chat_messages
+			WHERE room_id = r.id AND deleted_at IS NULL
+			ORDER BY created_at DESC LIMIT 1
+		) m ON TRUE
+		WHERE r.deleted_at IS NULL AND r.is_active = ? AND m.is_read = ? AND m.created_at < ?`,
+		true, false, now.Add(-time.Duration(thresholds.UnansweredChatHours)*time.Hour)).
+		Scan(&unansweredChats).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unanswered chats: %w", err)
+	}
+	if unansweredChats.Total > 0 && unansweredChats.Oldest != nil {
+		alerts = append(alerts, models.SystemAlert{
+			ID:        unansweredChats.OldestID,
+			Type:      "chat",
+			Severity:  "warning",
+			Message:   fmt.Sprintf("%d chat rooms have messages unanswered for over %d hours", unansweredChats.Total, thresholds.UnansweredChatHours),
+			CreatedAt: *unansweredChats.Oldest,
+		})
+	}
+
+	return alerts, nil
 }
 
-func (dr *DashboardRepository) GetPendingActions() ([]models.PendingAction, error) {
-	return []models.PendingAction{}, nil
+// GetPendingActions gets queues waiting on an admin, flagging those past their thresholds
+func (dr *DashboardRepository) GetPendingActions(thresholds models.DashboardAlertThresholds) ([]models.PendingAction, error) {
+	actions := []models.PendingAction{}
+	now := time.Now()
+
+	queues := []struct {
+		actionType     string
+		title          string
+		query          *gorm.DB
+		dateColumn     string
+		thresholdHours int
+		actionURL      string
+	}{
+		{
+			actionType:     "property_approval",
+			title:          "Properties awaiting approval",
+			query:          dr.db.Model(&models.Property{}).Where("is_approved = ? AND uploaded_by_admin = ?", false, false),
+			dateColumn:     "created_at",
+			thresholdHours: thresholds.PropertyApprovalHours,
+			actionURL:      "/dashboard/marketplace/rental-property/all",
+		},
+		{
+			actionType:     "role_application",
+			title:          "Role applications awaiting review",
+			query:          dr.db.Model(&models.RoleApplication{}).Where("status = ?", models.ApplicationStatusPending),
+			dateColumn:     "submitted_at",
+			thresholdHours: thresholds.RoleApplicationHours,
+			actionURL:      "/dashboard/role-applications",
+		},
+		{
+			actionType:     "worker_withdrawal",
+			title:          "Worker withdrawals to process",
+			query:          dr.db.Model(&models.Payment{}).Where("type = ? AND status = ?", models.PaymentTypeWorkerWithdrawal, models.PaymentStatusPending),
+			dateColumn:     "created_at",
+			thresholdHours: thresholds.WithdrawalPendingHours,
+			actionURL:      "/dashboard/worker-withdrawals",
+		},
+		{
+			actionType:     "inquiry_quote",
+			title:          "Inquiry bookings awaiting a quote",
+			query:          dr.db.Model(&models.Booking{}).Where("booking_type = ? AND status = ?", models.BookingTypeInquiry, models.BookingStatusPending),
+			dateColumn:     "created_at",
+			thresholdHours: thresholds.InquiryQuoteHours,
+			actionURL:      "/dashboard/bookings/inquiry-price",
+		},
+	}
+
+	for _, queue := range queues {
+		var summary struct {
+			Total    int
+			Overdue  int
+			OldestID uint
+			Oldest   *time.Time
+		}
+		err := queue.query.
+			Select(fmt.Sprintf("COUNT(*) AS total, COUNT(*) FILTER (WHERE %[1]s < ?) AS overdue, COALESCE((ARRAY_AGG(id ORDER BY %[1]s))[1], 0) AS oldest_id, MIN(%[1]s) AS oldest", queue.dateColumn),
+				now.Add(-time.Duration(queue.thresholdHours)*time.Hour)).
+			Scan(&summary).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pending %s: %w", queue.actionType, err)
+		}
+		if summary.Total == 0 || summary.Oldest == nil {
+			continue
+		}
+
+		priority := "medium"
+		description := fmt.Sprintf("%d waiting", summary.Total)
+		if summary.Overdue > 0 {
+			priority = "high"
+			description = fmt.Sprintf("%d waiting, %d for over %d hours", summary.Total, summary.Overdue, queue.thresholdHours)
+		}
+
+		actions = append(actions, models.PendingAction{
+			ID:          summary.OldestID,
+			Type:        queue.actionType,
+			Title:       queue.title,
+			Description: description,
+			Priority:    priority,
+			CreatedAt:   *summary.Oldest,
+			ActionURL:   queue.actionURL,
+		})
+	}
+
+	// Most urgent queues first
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].Priority == "high" && actions[j].Priority != "high"
+	})
+
+	return actions, nil
 }
 
 // Additional trend methods
@@ -1265,9 +1802,55 @@ func (dr *DashboardRepository) getPaymentTrends() ([]models.MonthlyData, error)
 
 
 func (dr *DashboardRepository) getChatTrends() ([]models.MonthlyData, error) {
-	return []models.MonthlyData{}, nil
+	var results []struct {
+		Month string `json:"month"`
+		Count int    `json:"count"`
+	}
+
+	err := dr.db.Model(&models.ChatRoom{}).
+		Select("TO_CHAR(created_at, 'YYYY-MM') as month, COUNT(*) as count").
+		Where("created_at >= NOW() - INTERVAL '12 months'").
+		Group("TO_CHAR(created_at, 'YYYY-MM')").
+		Order("month").
+		Scan(&results).Error
+	if err != nil {
+		return []models.MonthlyData{}, nil
+	}
+
+	var trends []models.MonthlyData
+	for _, result := range results {
+		trends = append(trends, models.MonthlyData{
+			Month: result.Month,
+			Value: result.Count,
+		})
+	}
+
+	return trends, nil
 }
 
 func (dr *DashboardRepository) getNotificationTrends() ([]models.MonthlyData, error) {
-	return []models.MonthlyData{}, nil
+	var results []struct {
+		Month string `json:"month"`
+		Count int    `json:"count"`
+	}
+
+	err := dr.db.Model(&models.PushNotification{}).
+		Select("TO_CHAR(created_at, 'YYYY-MM') as month, COUNT(*) as count").
+		Where("created_at >= NOW() - INTERVAL '12 months'").
+		Group("TO_CHAR(created_at, 'YYYY-MM')").
+		Order("month").
+		Scan(&results).Error
+	if err != nil {
+		return []models.MonthlyData{}, nil
+	}
+
+	var trends []models.MonthlyData
+	for _, result := range results {
+		trends = append(trends, models.MonthlyData{
+			Month: result.Month,
+			Value: result.Count,
+		})
+	}
+
+	return trends, nil
 }
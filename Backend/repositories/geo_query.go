@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"treesindia/models"
+
+	"gorm.io/gorm"
+)
+
+// applyGeoRadius limits a query on a table with latitude and longitude columns to the rows within
+// the radius, and selects each row's distance from the centre as distance_meters. earth_box uses
+// the GiST index on ll_to_earth(latitude, longitude); earth_distance then drops the corners of
+// the box.
+func applyGeoRadius(query *gorm.DB, table string, radius models.GeoRadius) *gorm.DB {
+	location := "ll_to_earth(" + table + ".latitude, " + table + ".longitude)"
+	return query.
+		Select(table+".*, earth_distance(ll_to_earth(?, ?), "+location+") AS distance_meters", radius.Latitude, radius.Longitude).
+		Where(table+".latitude IS NOT NULL AND "+table+".longitude IS NOT NULL").
+		Where("earth_box(ll_to_earth(?, ?), ?) @> "+location, radius.Latitude, radius.Longitude, radius.RadiusMeters).
+		Where("earth_distance(ll_to_earth(?, ?), "+location+") <= ?", radius.Latitude, radius.Longitude, radius.RadiusMeters)
+}
+
+// applyGeoBounds limits a query on a table with latitude and longitude columns to the rows inside
+// the bounding box
+func applyGeoBounds(query *gorm.DB, table string, bounds models.GeoBounds) *gorm.DB {
+	return query.
+		Where(table+".latitude BETWEEN ? AND ?", bounds.MinLatitude, bounds.MaxLatitude).
+		Where(table+".longitude BETWEEN ? AND ?", bounds.MinLongitude, bounds.MaxLongitude)
+}
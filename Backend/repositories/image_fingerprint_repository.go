@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"treesindia/database"
+	"treesindia/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ImageFingerprintRepository struct {
+	db *gorm.DB
+}
+
+func NewImageFingerprintRepository() *ImageFingerprintRepository {
+	return &ImageFingerprintRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create records an image fingerprint, keeping the existing one for a known URL
+func (ir *ImageFingerprintRepository) Create(fingerprint *models.ImageFingerprint) error {
+	return ir.db.Clauses(clause.OnConflict{DoNothing: true}).Create(fingerprint).Error
+}
+
+// DeleteByURL removes the fingerprint of a deleted image
+func (ir *ImageFingerprintRepository) DeleteByURL(url string) error {
+	return ir.db.Unscoped().Where("url = ?", url).Delete(&models.ImageFingerprint{}).Error
+}
+
+// duplicatePropertyImagesSQL pairs fingerprinted photos of different properties whose hashes are
+// at most @max_distance bits apart. @property_id = 0 lists every pair once.
+const duplicatePropertyImagesSQL = `
+WITH property_images AS (
+	SELECT p.id AS property_id, p.title AS property_title, img.url, f.perceptual_hash
+	FROM properties p
+	CROSS JOIN LATERAL jsonb_array_elements_text(
+		CASE WHEN jsonb_typeof(p.images::jsonb) = 'array' THEN p.images::jsonb ELSE '[]'::jsonb END
+	) AS img(url)
+	JOIN image_fingerprints f ON f.url = img.url AND f.deleted_at IS NULL
+	WHERE p.deleted_at IS NULL
+),
+pairs AS (
+	SELECT a.property_id, a.property_title, a.url AS image_url,
+		b.property_id AS duplicate_property_id, b.property_title AS duplicate_property_title, b.url AS duplicate_image_url,
+		length(replace((a.perceptual_hash # b.perceptual_hash)::bit(64)::text, '0', '')) AS distance
+	FROM property_images a
+	JOIN property_images b ON
+		CASE WHEN @property_id = 0 THEN a.property_id < b.property_id
+		ELSE a.property_id = @property_id AND b.property_id <> @property_id END
+)
+SELECT * FROM pairs WHERE distance <= @max_distance`
+
+// GetDuplicatePropertyImages finds near-identical photos shared by different properties with pagination.
+// A propertyID of 0 searches across all properties.
+func (ir *ImageFingerprintRepository) GetDuplicatePropertyImages(propertyID uint, maxDistance, page, limit int) ([]models.DuplicatePropertyImage, *Pagination, error) {
+	var duplicates []models.DuplicatePropertyImage
+	var total int64
+
+	args := map[string]interface{}{
+		"property_id":  propertyID,
+		"max_distance": maxDistance,
+	}
+
+	err := ir.db.Raw("SELECT COUNT(*) FROM ("+duplicatePropertyImagesSQL+") AS duplicates", args).Scan(&total).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Apply pagination
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	args["limit"] = limit
+	args["offset"] = (page - 1) * limit
+	err = ir.db.Raw(duplicatePropertyImagesSQL+`
+ORDER BY distance ASC, property_id DESC, duplicate_property_id DESC
+LIMIT @limit OFFSET @offset`, args).Scan(&duplicates).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	pagination := &Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	return duplicates, pagination, nil
+}
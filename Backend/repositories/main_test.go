@@ -0,0 +1,11 @@
+package repositories_test
+
+import (
+	"os"
+	"testing"
+	"treesindia/testutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.Main(m))
+}
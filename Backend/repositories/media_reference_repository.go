@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+	"treesindia/database"
+	"treesindia/models"
+	"treesindia/utils"
+
+	"gorm.io/gorm"
+)
+
+// MediaColumn is a database column that holds media URLs, as text, text[] or JSON
+type MediaColumn struct {
+	Table  string
+	Column string
+	// ChatMedia columns are only kept for the chat media retention window
+	ChatMedia bool
+	// Encrypted columns are stored encrypted with utils.EncryptField
+	Encrypted bool
+}
+
+// MediaColumns lists every column that references stored media
+var MediaColumns = []MediaColumn{
+	{Table: "users", Column: "avatar"},
+	{Table: "categories", Column: "icon"},
+	{Table: "services", Column: "images"},
+	{Table: "properties", Column: "images"},
+	{Table: "projects", Column: "images"},
+	{Table: "vendors", Column: "profile_picture"},
+	{Table: "vendors", Column: "business_gallery"},
+	{Table: "workers", Column: "documents", Encrypted: true},
+	{Table: "brokers", Column: "documents", Encrypted: true},
+	{Table: "user_documents", Column: "file_url"},
+	{Table: "banner_images", Column: "image"},
+	{Table: "hero_images", Column: "media_url"},
+	{Table: "hero_images", Column: "image_url"},
+	{Table: "promotion_banners", Column: "image"},
+	{Table: "homepage_category_icons", Column: "icon_url"},
+	{Table: "data_exports", Column: "file_url"},
+	{Table: "chat_messages", Column: "attachments"},
+	{Table: "simple_conversation_messages", Column: "image_url", ChatMedia: true},
+	{Table: "simple_conversation_messages", Column: "video_url", ChatMedia: true},
+}
+
+type MediaReferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaReferenceRepository() *MediaReferenceRepository {
+	return &MediaReferenceRepository{
+		db: database.GetDB(),
+	}
+}
+
+// ForEachReference calls fn with the text value of every non-empty media column. Chat media sent
+// before chatMediaCutoff is skipped, since it is past its retention window.
+func (mr *MediaReferenceRepository) ForEachReference(chatMediaCutoff time.Time, fn func(value string)) error {
+	for _, column := range MediaColumns {
+		query := mr.db.Table(column.Table).
+			Select(fmt.Sprintf("%s::text", column.Column)).
+			Where(fmt.Sprintf("%s IS NOT NULL", column.Column))
+		if column.ChatMedia {
+			query = query.Where("created_at >= ?", chatMediaCutoff)
+		}
+
+		rows, err := query.Rows()
+		if err != nil {
+			return fmt.Errorf("failed to read %s.%s: %v", column.Table, column.Column, err)
+		}
+
+		for rows.Next() {
+			var value string
+			if err := rows.Scan(&value); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to read %s.%s: %v", column.Table, column.Column, err)
+			}
+			if column.Encrypted {
+				// Stop rather than skip, so media that is still referenced is never collected
+				if value, err = utils.DecryptField(value); err != nil {
+					rows.Close()
+					return fmt.Errorf("failed to decrypt %s.%s: %v", column.Table, column.Column, err)
+				}
+			}
+			fn(value)
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s.%s: %v", column.Table, column.Column, err)
+		}
+	}
+	return nil
+}
+
+// CountExpiredChatAttachments counts messages sent before the cutoff that still have an attachment
+func (mr *MediaReferenceRepository) CountExpiredChatAttachments(cutoff time.Time) (int64, error) {
+	var count int64
+	err := mr.expiredChatAttachments(cutoff).Count(&count).Error
+	return count, err
+}
+
+// ClearExpiredChatAttachments removes the attachments of messages sent before the cutoff, once
+// their media has been deleted. Returns the number of messages updated.
+func (mr *MediaReferenceRepository) ClearExpiredChatAttachments(cutoff time.Time) (int64, error) {
+	result := mr.expiredChatAttachments(cutoff).
+		Updates(map[string]interface{}{
+			"attachment_type":      nil,
+			"image_url":            nil,
+			"video_url":            nil,
+			"cloudinary_public_id": nil,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// expiredChatAttachments scopes messages, including deleted ones, with an attachment sent before the cutoff
+func (mr *MediaReferenceRepository) expiredChatAttachments(cutoff time.Time) *gorm.DB {
+	return mr.db.Unscoped().Model(&models.SimpleConversationMessage{}).
+		Where("created_at < ? AND (image_url IS NOT NULL OR video_url IS NOT NULL)", cutoff)
+}
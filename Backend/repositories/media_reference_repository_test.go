@@ -0,0 +1,26 @@
+package repositories_test
+
+import (
+	"testing"
+	"treesindia/repositories"
+	"treesindia/testutil"
+)
+
+// Every media column the garbage collector reads must exist once the migrations have run, or the
+// collector fails before it deletes anything
+func TestMediaColumnsExistInSchema(t *testing.T) {
+	db := testutil.RequireDB(t)
+
+	for _, column := range repositories.MediaColumns {
+		var count int64
+		err := db.Raw(`SELECT COUNT(*) FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?`,
+			column.Table, column.Column).Scan(&count).Error
+		if err != nil {
+			t.Fatalf("failed to look up %s.%s: %v", column.Table, column.Column, err)
+		}
+		if count == 0 {
+			t.Errorf("media column %s.%s does not exist in the migrated schema", column.Table, column.Column)
+		}
+	}
+}
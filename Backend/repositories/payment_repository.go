@@ -173,6 +173,14 @@ func (pr *PaymentRepository) UpdateStatus(paymentID uint, status models.PaymentS
 		}).Error
 }
 
+// GetHeldBookingPayments gets payments on hold for a booking (e.g. inquiry fees)
+func (pr *PaymentRepository) GetHeldBookingPayments(bookingID uint) ([]models.Payment, error) {
+	var payments []models.Payment
+	err := pr.db.Where("related_entity_type = ? AND related_entity_id = ? AND status = ?", "booking", bookingID, models.PaymentStatusHold).
+		Find(&payments).Error
+	return payments, err
+}
+
 // GetPaymentStats gets payment statistics
 func (pr *PaymentRepository) GetPaymentStats(userID *uint) (map[string]interface{}, error) {
 	query := pr.db.Model(&models.Payment{})
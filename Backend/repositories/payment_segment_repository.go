@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"time"
 	"treesindia/database"
 	"treesindia/models"
 
@@ -62,10 +63,10 @@ func (psr *PaymentSegmentRepository) GetByBookingIDAndSegmentNumber(bookingID ui
 	return &segment, nil
 }
 
-// GetPendingSegments gets all pending segments for a booking
+// GetPendingSegments gets all unpaid (pending or overdue) segments for a booking
 func (psr *PaymentSegmentRepository) GetPendingSegments(bookingID uint) ([]models.PaymentSegment, error) {
 	var segments []models.PaymentSegment
-	err := psr.db.Where("booking_id = ? AND status = ?", bookingID, models.PaymentSegmentStatusPending).
+	err := psr.db.Where("booking_id = ? AND status IN ?", bookingID, unpaidSegmentStatuses).
 		Order("segment_number ASC").
 		Find(&segments).Error
 	return segments, err
@@ -140,6 +141,10 @@ func (psr *PaymentSegmentRepository) GetPaymentProgress(bookingID uint) (*models
 			PaidAt:        segment.PaidAt,
 			Notes:         segment.Notes,
 			PaymentID:     segment.PaymentID,
+			DueTrigger:     segment.DueTrigger,
+			DueAfterDays:   segment.DueAfterDays,
+			DueAt:          segment.DueAt,
+			RequiredBefore: segment.RequiredBefore,
 		}
 
 		if segment.Status == models.PaymentSegmentStatusPaid {
@@ -180,9 +185,159 @@ func (psr *PaymentSegmentRepository) CountSegmentsByStatus(bookingID uint, statu
 
 // IsAllSegmentsPaid checks if all segments for a booking are paid
 func (psr *PaymentSegmentRepository) IsAllSegmentsPaid(bookingID uint) (bool, error) {
-	pendingCount, err := psr.CountSegmentsByStatus(bookingID, models.PaymentSegmentStatusPending)
+	var unpaidCount int64
+	err := psr.db.Model(&models.PaymentSegment{}).
+		Where("booking_id = ? AND status IN ?", bookingID, unpaidSegmentStatuses).
+		Count(&unpaidCount).Error
 	if err != nil {
 		return false, err
 	}
-	return pendingCount == 0, nil
+	return unpaidCount == 0, nil
+}
+
+// unpaidSegmentStatuses are the statuses of segments that still have to be paid
+var unpaidSegmentStatuses = []models.PaymentSegmentStatus{
+	models.PaymentSegmentStatusPending,
+	models.PaymentSegmentStatusOverdue,
+}
+
+// ActivateDueTrigger sets the due date of unpaid segments waiting on the given milestone
+func (psr *PaymentSegmentRepository) ActivateDueTrigger(bookingID uint, trigger models.PaymentSegmentDueTrigger, dueAt time.Time) (int64, error) {
+	result := psr.db.Model(&models.PaymentSegment{}).
+		Where("booking_id = ? AND due_trigger = ? AND due_at IS NULL AND status IN ?", bookingID, trigger, unpaidSegmentStatuses).
+		Update("due_at", dueAt)
+	return result.RowsAffected, result.Error
+}
+
+// ResolveRelativeDueDates sets due dates of days_after_previous segments whose previous segment has fallen due
+func (psr *PaymentSegmentRepository) ResolveRelativeDueDates(bookingID uint) error {
+	segments, err := psr.GetByBookingID(bookingID)
+	if err != nil {
+		return err
+	}
+
+	// Segments are ordered by segment number, so a chain of relative
+	// segments resolves in a single pass
+	for i := 1; i < len(segments); i++ {
+		segment := &segments[i]
+		previous := segments[i-1]
+
+		if segment.DueTrigger != models.PaymentSegmentDueDaysAfterPrevious || segment.DueAt != nil || previous.DueAt == nil {
+			continue
+		}
+		if segment.DueAfterDays == nil || !segment.IsUnpaid() {
+			continue
+		}
+
+		dueAt := previous.DueAt.AddDate(0, 0, *segment.DueAfterDays)
+		if err := psr.db.Model(&models.PaymentSegment{}).Where("id = ?", segment.ID).Update("due_at", dueAt).Error; err != nil {
+			return err
+		}
+		segment.DueAt = &dueAt
+	}
+
+	return nil
+}
+
+// GetBookingIDsWithUnresolvedRelativeSegments gets bookings that have relative segments without a due date
+func (psr *PaymentSegmentRepository) GetBookingIDsWithUnresolvedRelativeSegments() ([]uint, error) {
+	var bookingIDs []uint
+	err := psr.db.Model(&models.PaymentSegment{}).
+		Where("due_trigger = ? AND due_at IS NULL AND status IN ?", models.PaymentSegmentDueDaysAfterPrevious, unpaidSegmentStatuses).
+		Distinct("booking_id").
+		Pluck("booking_id", &bookingIDs).Error
+	return bookingIDs, err
+}
+
+// GetDueUnpaidSegments gets unpaid segments that have fallen due on or before the given time
+func (psr *PaymentSegmentRepository) GetDueUnpaidSegments(before time.Time) ([]models.PaymentSegment, error) {
+	var segments []models.PaymentSegment
+	err := psr.db.Where("status IN ? AND due_at IS NOT NULL AND due_at <= ?", unpaidSegmentStatuses, before).
+		Order("due_at ASC").
+		Preload("Booking").
+		Find(&segments).Error
+	return segments, err
+}
+
+// MarkAsOverdue marks a pending payment segment as overdue
+func (psr *PaymentSegmentRepository) MarkAsOverdue(segmentID uint, overdueAt time.Time) error {
+	return psr.db.Model(&models.PaymentSegment{}).
+		Where("id = ? AND status = ?", segmentID, models.PaymentSegmentStatusPending).
+		Updates(map[string]interface{}{
+			"status":     models.PaymentSegmentStatusOverdue,
+			"overdue_at": overdueAt,
+		}).Error
+}
+
+// RecordReminder records that a payment reminder was sent for a segment
+func (psr *PaymentSegmentRepository) RecordReminder(segmentID uint, sentAt time.Time) error {
+	return psr.db.Model(&models.PaymentSegment{}).
+		Where("id = ?", segmentID).
+		Updates(map[string]interface{}{
+			"reminder_count":   gorm.Expr("reminder_count + 1"),
+			"last_reminder_at": sentAt,
+		}).Error
+}
+
+// GetUnpaidSegmentsRequiredBefore gets unpaid segments that hold the given work stage
+func (psr *PaymentSegmentRepository) GetUnpaidSegmentsRequiredBefore(bookingID uint, gate models.PaymentSegmentGate) ([]models.PaymentSegment, error) {
+	var segments []models.PaymentSegment
+	err := psr.db.Where("booking_id = ? AND required_before = ? AND status IN ?", bookingID, gate, unpaidSegmentStatuses).
+		Order("segment_number ASC").
+		Find(&segments).Error
+	return segments, err
+}
+
+// UpdateSchedule saves the milestone and gate fields of a payment segment and recomputes the
+// due dates of the days_after_previous segments that follow it
+func (psr *PaymentSegmentRepository) UpdateSchedule(segment *models.PaymentSegment) error {
+	return psr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.PaymentSegment{}).
+			Where("id = ?", segment.ID).
+			Updates(map[string]interface{}{
+				"due_trigger":     segment.DueTrigger,
+				"due_after_days":  segment.DueAfterDays,
+				"due_at":          segment.DueAt,
+				"required_before": segment.RequiredBefore,
+				"overdue_at":      segment.OverdueAt,
+				"status":          segment.Status,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		var following []models.PaymentSegment
+		err = tx.Where("booking_id = ? AND segment_number > ?", segment.BookingID, segment.SegmentNumber).
+			Order("segment_number ASC").
+			Find(&following).Error
+		if err != nil {
+			return err
+		}
+
+		previousDueAt := segment.DueAt
+		for _, next := range following {
+			if next.DueTrigger != models.PaymentSegmentDueDaysAfterPrevious || next.DueAfterDays == nil || !next.IsUnpaid() {
+				break
+			}
+
+			var dueAt *time.Time
+			if previousDueAt != nil {
+				resolved := previousDueAt.AddDate(0, 0, *next.DueAfterDays)
+				dueAt = &resolved
+			}
+			err := tx.Model(&models.PaymentSegment{}).
+				Where("id = ?", next.ID).
+				Updates(map[string]interface{}{
+					"due_at":     dueAt,
+					"overdue_at": nil,
+					"status":     models.PaymentSegmentStatusPending,
+				}).Error
+			if err != nil {
+				return err
+			}
+			previousDueAt = dueAt
+		}
+
+		return nil
+	})
 }
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"time"
+	"treesindia/database"
+	"treesindia/models"
+
+	"gorm.io/gorm"
+)
+
+type PricingRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewPricingRuleRepository() *PricingRuleRepository {
+	return &PricingRuleRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create creates a pricing rule
+func (pr *PricingRuleRepository) Create(rule *models.PricingRule) error {
+	return pr.db.Create(rule).Error
+}
+
+// GetByID gets a pricing rule by ID
+func (pr *PricingRuleRepository) GetByID(id uint) (*models.PricingRule, error) {
+	var rule models.PricingRule
+	err := pr.db.First(&rule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Update updates a pricing rule
+func (pr *PricingRuleRepository) Update(rule *models.PricingRule) error {
+	return pr.db.Save(rule).Error
+}
+
+// Delete deletes a pricing rule
+func (pr *PricingRuleRepository) Delete(id uint) error {
+	result := pr.db.Delete(&models.PricingRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetRules gets pricing rules with filters and pagination
+func (pr *PricingRuleRepository) GetRules(filters *models.PricingRuleFilters) ([]models.PricingRule, *Pagination, error) {
+	var rules []models.PricingRule
+	var total int64
+
+	query := pr.db.Model(&models.PricingRule{})
+	if filters.IsActive != nil {
+		query = query.Where("is_active = ?", *filters.IsActive)
+	}
+
+	// Count total
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Apply pagination
+	page := filters.Page
+	if page <= 0 {
+		page = 1
+	}
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset := (page - 1) * limit
+	err = query.Order("priority DESC, created_at DESC").Offset(offset).Limit(limit).Find(&rules).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Calculate pagination
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	pagination := &Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	return rules, pagination, nil
+}
+
+// GetActiveRulesOn gets the active rules whose date range includes a date, highest priority first
+func (pr *PricingRuleRepository) GetActiveRulesOn(date time.Time) ([]models.PricingRule, error) {
+	var rules []models.PricingRule
+	day := date.Format("2006-01-02")
+	err := pr.db.
+		Where("is_active = ?", true).
+		Where("start_date IS NULL OR start_date <= ?", day).
+		Where("end_date IS NULL OR end_date >= ?", day).
+		Order("priority DESC, id ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// CreateHoliday adds a holiday
+func (pr *PricingRuleRepository) CreateHoliday(holiday *models.Holiday) error {
+	return pr.db.Create(holiday).Error
+}
+
+// DeleteHoliday deletes a holiday
+func (pr *PricingRuleRepository) DeleteHoliday(id uint) error {
+	result := pr.db.Delete(&models.Holiday{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetHolidays gets the holidays between two dates, inclusive
+func (pr *PricingRuleRepository) GetHolidays(from, to time.Time) ([]models.Holiday, error) {
+	var holidays []models.Holiday
+	err := pr.db.
+		Where("date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("date ASC").
+		Find(&holidays).Error
+	return holidays, err
+}
@@ -34,6 +34,16 @@ func (pr *ProjectRepository) GetByID(id uint) (*models.Project, error) {
 	return &project, nil
 }
 
+// GetByIDs retrieves the projects with the given IDs
+func (pr *ProjectRepository) GetByIDs(ids []uint) ([]models.Project, error) {
+	var projects []models.Project
+	if len(ids) == 0 {
+		return projects, nil
+	}
+	err := pr.db.Where("id IN ?", ids).Find(&projects).Error
+	return projects, err
+}
+
 // GetBySlug retrieves a project by slug
 func (pr *ProjectRepository) GetBySlug(slug string) (*models.Project, error) {
 	var project models.Project
@@ -191,10 +201,20 @@ func (pr *ProjectRepository) GetProjectsByFilters(filters map[string]interface{}
 	if userID, ok := filters["user_id"].(uint); ok {
 		query = query.Where("user_id = ?", userID)
 	}
+	if bounds, ok := filters["bounds"].(models.GeoBounds); ok {
+		query = applyGeoBounds(query, "projects", bounds)
+	}
+	
+	// Radius searches are ordered by distance
+	order := "created_at DESC"
+	if radius, ok := filters["near"].(models.GeoRadius); ok {
+		query = applyGeoRadius(query, "projects", radius)
+		order = "distance_meters ASC"
+	}
 	
 	err := query.Limit(limit).
 		Offset(offset).
-		Order("created_at DESC").
+		Order(order).
 		Find(&projects).Error
 	return projects, err
 }
@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"time"
+	"treesindia/database"
+	"treesindia/models"
+	"treesindia/utils"
+
+	"gorm.io/gorm"
+)
+
+type PropertyEnquiryRepository struct {
+	db *gorm.DB
+}
+
+func NewPropertyEnquiryRepository() *PropertyEnquiryRepository {
+	return &PropertyEnquiryRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create creates a property enquiry
+func (per *PropertyEnquiryRepository) Create(enquiry *models.PropertyEnquiry) error {
+	return per.db.Create(enquiry).Error
+}
+
+// Update updates a property enquiry
+func (per *PropertyEnquiryRepository) Update(enquiry *models.PropertyEnquiry) error {
+	return per.db.Omit("Property", "Buyer", "Visits").Save(enquiry).Error
+}
+
+// GetByID gets an enquiry with its property, buyer and site visits
+func (per *PropertyEnquiryRepository) GetByID(id uint) (*models.PropertyEnquiry, error) {
+	var enquiry models.PropertyEnquiry
+	err := per.db.Preload("Property").
+		Preload("Buyer").
+		Preload("Visits", func(db *gorm.DB) *gorm.DB {
+			return db.Order("scheduled_at ASC")
+		}).
+		First(&enquiry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &enquiry, nil
+}
+
+// GetOpenByBuyerAndProperty gets a buyer's enquiry on a property that is still in the pipeline
+func (per *PropertyEnquiryRepository) GetOpenByBuyerAndProperty(buyerID, propertyID uint) (*models.PropertyEnquiry, error) {
+	var enquiry models.PropertyEnquiry
+	err := per.db.Where("buyer_id = ? AND property_id = ? AND status NOT IN ?", buyerID, propertyID,
+		[]models.LeadStatus{models.LeadStatusClosedWon, models.LeadStatusClosedLost}).
+		First(&enquiry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &enquiry, nil
+}
+
+// GetByBuyer gets the enquiries a buyer has made, newest first
+func (per *PropertyEnquiryRepository) GetByBuyer(buyerID uint, params utils.PaginationParams) ([]models.PropertyEnquiry, utils.PaginationResponse, error) {
+	query := per.db.Model(&models.PropertyEnquiry{}).Where("buyer_id = ?", buyerID)
+	return per.paginate(query, params)
+}
+
+// GetByOwner gets the leads of a listing owner, newest first, optionally filtered by status and listing
+func (per *PropertyEnquiryRepository) GetByOwner(ownerID uint, status string, propertyID uint, params utils.PaginationParams) ([]models.PropertyEnquiry, utils.PaginationResponse, error) {
+	query := per.db.Model(&models.PropertyEnquiry{}).Where("owner_id = ?", ownerID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if propertyID != 0 {
+		query = query.Where("property_id = ?", propertyID)
+	}
+	return per.paginate(query, params)
+}
+
+// CountByStatus counts a listing owner's leads in each pipeline stage
+func (per *PropertyEnquiryRepository) CountByStatus(ownerID uint) (map[models.LeadStatus]int64, error) {
+	var rows []struct {
+		Status models.LeadStatus
+		Count  int64
+	}
+	err := per.db.Model(&models.PropertyEnquiry{}).
+		Select("status, COUNT(*) AS count").
+		Where("owner_id = ?", ownerID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.LeadStatus]int64)
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// GetListingStats gets lead and conversion counts for each of an owner's listings that has enquiries
+func (per *PropertyEnquiryRepository) GetListingStats(ownerID uint) ([]models.ListingLeadStats, error) {
+	var stats []models.ListingLeadStats
+	err := per.db.Table("property_enquiries AS e").
+		Select(`e.property_id, p.title,
+			COUNT(*) AS enquiries,
+			COUNT(*) FILTER (WHERE e.status NOT IN ?) AS open,
+			COUNT(*) FILTER (WHERE EXISTS (
+				SELECT 1 FROM property_site_visits v
+				WHERE v.enquiry_id = e.id AND v.status = ? AND v.deleted_at IS NULL
+			)) AS visits_done,
+			COUNT(*) FILTER (WHERE e.status = ?) AS won,
+			COUNT(*) FILTER (WHERE e.status = ?) AS lost`,
+			[]models.LeadStatus{models.LeadStatusClosedWon, models.LeadStatusClosedLost},
+			models.SiteVisitStatusCompleted, models.LeadStatusClosedWon, models.LeadStatusClosedLost).
+		Joins("JOIN properties p ON p.id = e.property_id").
+		Where("e.owner_id = ? AND e.deleted_at IS NULL", ownerID).
+		Group("e.property_id, p.title").
+		Order("enquiries DESC").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range stats {
+		if stats[i].Enquiries > 0 {
+			stats[i].ConversionRate = float64(stats[i].Won) / float64(stats[i].Enquiries) * 100
+		}
+	}
+	return stats, nil
+}
+
+// CreateVisit creates a site visit
+func (per *PropertyEnquiryRepository) CreateVisit(visit *models.PropertySiteVisit) error {
+	return per.db.Create(visit).Error
+}
+
+// UpdateVisit updates a site visit
+func (per *PropertyEnquiryRepository) UpdateVisit(visit *models.PropertySiteVisit) error {
+	return per.db.Omit("Property").Save(visit).Error
+}
+
+// GetVisitByID gets a site visit by ID
+func (per *PropertyEnquiryRepository) GetVisitByID(id uint) (*models.PropertySiteVisit, error) {
+	var visit models.PropertySiteVisit
+	if err := per.db.Preload("Property").First(&visit, id).Error; err != nil {
+		return nil, err
+	}
+	return &visit, nil
+}
+
+// GetUpcomingVisits gets the scheduled site visits of a buyer or listing owner, soonest first
+func (per *PropertyEnquiryRepository) GetUpcomingVisits(userID uint, from time.Time) ([]models.PropertySiteVisit, error) {
+	var visits []models.PropertySiteVisit
+	err := per.db.Preload("Property").
+		Where("(buyer_id = ? OR owner_id = ?) AND status = ? AND scheduled_at >= ?", userID, userID, models.SiteVisitStatusScheduled, from).
+		Order("scheduled_at ASC").
+		Find(&visits).Error
+	return visits, err
+}
+
+// HasVisitConflict checks if a property already has a scheduled visit between start and end
+func (per *PropertyEnquiryRepository) HasVisitConflict(propertyID uint, start, end time.Time) (bool, error) {
+	var count int64
+	err := per.db.Model(&models.PropertySiteVisit{}).
+		Where("property_id = ? AND status = ? AND scheduled_at > ? AND scheduled_at < ?", propertyID, models.SiteVisitStatusScheduled, start, end).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetVisitsDueForReminder gets scheduled visits starting before the given time that have not been reminded about
+func (per *PropertyEnquiryRepository) GetVisitsDueForReminder(now, before time.Time) ([]models.PropertySiteVisit, error) {
+	var visits []models.PropertySiteVisit
+	err := per.db.Preload("Property").
+		Where("status = ? AND reminder_sent_at IS NULL AND scheduled_at > ? AND scheduled_at <= ?", models.SiteVisitStatusScheduled, now, before).
+		Find(&visits).Error
+	return visits, err
+}
+
+// MarkReminderSent records that the reminder for a site visit was sent
+func (per *PropertyEnquiryRepository) MarkReminderSent(visitID uint, sentAt time.Time) error {
+	return per.db.Model(&models.PropertySiteVisit{}).
+		Where("id = ?", visitID).
+		Update("reminder_sent_at", sentAt).Error
+}
+
+// paginate runs an enquiry query a page at a time, newest first
+func (per *PropertyEnquiryRepository) paginate(query *gorm.DB, params utils.PaginationParams) ([]models.PropertyEnquiry, utils.PaginationResponse, error) {
+	paginationHelper := utils.NewPaginationHelper()
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, utils.PaginationResponse{}, err
+	}
+
+	var enquiries []models.PropertyEnquiry
+	err := paginationHelper.ApplyPagination(query, params).
+		Preload("Property").
+		Preload("Buyer").
+		Order("created_at DESC").
+		Find(&enquiries).Error
+	if err != nil {
+		return nil, utils.PaginationResponse{}, err
+	}
+
+	return enquiries, paginationHelper.CalculatePagination(total, params), nil
+}
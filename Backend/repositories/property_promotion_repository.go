@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+	"treesindia/database"
+	"treesindia/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PropertyPromotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPropertyPromotionRepository() *PropertyPromotionRepository {
+	return &PropertyPromotionRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create creates a property promotion. It runs within tx when it is set.
+func (ppr *PropertyPromotionRepository) Create(tx *gorm.DB, promotion *models.PropertyPromotion) error {
+	return ppr.conn(tx).Create(promotion).Error
+}
+
+// Update updates a property promotion. It runs within tx when it is set.
+func (ppr *PropertyPromotionRepository) Update(tx *gorm.DB, promotion *models.PropertyPromotion) error {
+	return ppr.conn(tx).Omit("Property").Save(promotion).Error
+}
+
+// GetByID gets a property promotion by ID
+func (ppr *PropertyPromotionRepository) GetByID(id uint) (*models.PropertyPromotion, error) {
+	var promotion models.PropertyPromotion
+	if err := ppr.db.First(&promotion, id).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// GetByIDForUpdate gets a property promotion within tx and locks its row until tx ends
+func (ppr *PropertyPromotionRepository) GetByIDForUpdate(tx *gorm.DB, id uint) (*models.PropertyPromotion, error) {
+	var promotion models.PropertyPromotion
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&promotion, id).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// GetByProperty gets the promotions of a property, newest first
+func (ppr *PropertyPromotionRepository) GetByProperty(propertyID uint) ([]models.PropertyPromotion, error) {
+	var promotions []models.PropertyPromotion
+	err := ppr.db.Where("property_id = ?", propertyID).Order("created_at DESC").Find(&promotions).Error
+	return promotions, err
+}
+
+// CountSubscriptionBoostsSince counts the free subscription boosts a user has used since the given
+// time. It runs within tx when it is set.
+func (ppr *PropertyPromotionRepository) CountSubscriptionBoostsSince(tx *gorm.DB, userID uint, since time.Time) (int64, error) {
+	var count int64
+	err := ppr.conn(tx).Model(&models.PropertyPromotion{}).
+		Where("user_id = ? AND payment_method = ? AND type = ? AND status = ? AND created_at >= ?",
+			userID, models.PaymentMethodSubscription, models.PropertyPromotionTypeBoost, models.PropertyPromotionStatusActive, since).
+		Count(&count).Error
+	return count, err
+}
+
+// CountFeaturedInCity counts the listings in a city holding a featured slot, except the given
+// property. A featured slot bought through Razorpay holds its slot from heldSince while it awaits
+// payment. It runs within tx when it is set.
+func (ppr *PropertyPromotionRepository) CountFeaturedInCity(tx *gorm.DB, city, state string, excludePropertyID uint, now, heldSince time.Time) (int64, error) {
+	var count int64
+	pending := ppr.conn(tx).Model(&models.PropertyPromotion{}).Select("1").
+		Where("property_promotions.property_id = properties.id").
+		Where("property_promotions.type = ? AND property_promotions.status = ? AND property_promotions.created_at > ?",
+			models.PropertyPromotionTypeFeatured, models.PropertyPromotionStatusPending, heldSince)
+	err := ppr.conn(tx).Model(&models.Property{}).
+		Where("LOWER(city) = LOWER(?) AND LOWER(state) = LOWER(?)", city, state).
+		Where("id <> ?", excludePropertyID).
+		Where("featured_until > ? OR EXISTS (?)", now, pending).
+		Count(&count).Error
+	return count, err
+}
+
+// LockFeaturedSlots takes a lock on the featured slots of a city until tx ends, so slots are
+// counted and taken by one purchase at a time
+func (ppr *PropertyPromotionRepository) LockFeaturedSlots(tx *gorm.DB, city, state string) error {
+	key := "property_featured_slots:" + strings.ToLower(city) + ":" + strings.ToLower(state)
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error
+}
+
+// LockSubscriptionBoosts locks the user's row until tx ends, so the user's free boosts are counted
+// and used by one purchase at a time
+func (ppr *PropertyPromotionRepository) LockSubscriptionBoosts(tx *gorm.DB, userID uint) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.User{}, userID).Error
+}
+
+// conn returns tx when it is set and the repository's connection otherwise
+func (ppr *PropertyPromotionRepository) conn(tx *gorm.DB) *gorm.DB {
+	if tx != nil {
+		return tx
+	}
+	return ppr.db
+}
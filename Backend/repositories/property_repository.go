@@ -9,6 +9,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PropertyRepository struct {
@@ -305,8 +306,8 @@ func (pr *PropertyRepository) RejectProperty(id uint, adminID uint, reason strin
 	return nil
 }
 
-// UpdateExpiredProperties updates expired properties to sold/rented status based on listing type
-func (pr *PropertyRepository) UpdateExpiredProperties() error {
+// UpdateExpiredProperties marks available listings past their expiry date as expired and returns them
+func (pr *PropertyRepository) UpdateExpiredProperties() ([]models.Property, error) {
 	defer func() {
 		if r := recover(); r != nil {
 			logrus.Errorf("PropertyRepository.UpdateExpiredProperties panic: %v", r)
@@ -315,28 +316,88 @@ func (pr *PropertyRepository) UpdateExpiredProperties() error {
 	
 	logrus.Infof("PropertyRepository.UpdateExpiredProperties called")
 	
-	// Update sale properties to sold
-	err := pr.GetDB().Model(&models.Property{}).
-		Where("status = ? AND expires_at < ? AND listing_type = ?", models.PropertyStatusAvailable, time.Now(), models.ListingTypeSale).
-		Update("status", models.PropertyStatusSold).Error
-	
+	now := time.Now()
+	var properties []models.Property
+	err := pr.GetDB().Where("status = ? AND expires_at < ?", models.PropertyStatusAvailable, now).
+		Find(&properties).Error
 	if err != nil {
-		logrus.Errorf("PropertyRepository.UpdateExpiredProperties sale update error: %v", err)
-		return err
+		logrus.Errorf("PropertyRepository.UpdateExpiredProperties query error: %v", err)
+		return nil, err
+	}
+	if len(properties) == 0 {
+		return properties, nil
 	}
 	
-	// Update rent properties to rented
-	err = pr.GetDB().Model(&models.Property{}).
-		Where("status = ? AND expires_at < ? AND listing_type = ?", models.PropertyStatusAvailable, time.Now(), models.ListingTypeRent).
-		Update("status", models.PropertyStatusRented).Error
+	ids := make([]uint, len(properties))
+	for i := range properties {
+		ids[i] = properties[i].ID
+		properties[i].Status = models.PropertyStatusExpired
+	}
 	
+	// Re-check the status and expiry so a listing renewed meanwhile is left alone
+	err = pr.GetDB().Model(&models.Property{}).
+		Where("id IN ? AND status = ? AND expires_at < ?", ids, models.PropertyStatusAvailable, now).
+		Update("status", models.PropertyStatusExpired).Error
 	if err != nil {
-		logrus.Errorf("PropertyRepository.UpdateExpiredProperties rent update error: %v", err)
-		return err
+		logrus.Errorf("PropertyRepository.UpdateExpiredProperties update error: %v", err)
+		return nil, err
 	}
 	
-	logrus.Infof("PropertyRepository.UpdateExpiredProperties successfully updated expired properties")
-	return nil
+	logrus.Infof("PropertyRepository.UpdateExpiredProperties marked %d properties as expired", len(properties))
+	return properties, nil
+}
+
+// UpdateListingPromotion updates the expiry, boost or featured columns of a property. It runs
+// within tx when it is set.
+func (pr *PropertyRepository) UpdateListingPromotion(tx *gorm.DB, id uint, updates map[string]interface{}) error {
+	if tx == nil {
+		tx = pr.GetDB()
+	}
+	return tx.Model(&models.Property{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// GetByIDForUpdate gets a property within tx and locks its row until tx ends
+func (pr *PropertyRepository) GetByIDForUpdate(tx *gorm.DB, id uint) (*models.Property, error) {
+	var property models.Property
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&property, id).Error; err != nil {
+		return nil, err
+	}
+	return &property, nil
+}
+
+// RefreshBoostPriorities recalculates the priority of boosted properties as their boosts decay, and
+// clears boosts that have ended
+func (pr *PropertyRepository) RefreshBoostPriorities(now time.Time) (int64, error) {
+	result := pr.GetDB().Model(&models.Property{}).
+		Where("boosted_until IS NOT NULL").
+		Update("priority_score", gorm.Expr(`base_priority_score + CASE
+			WHEN boosted_until > ? AND boosted_until > boost_started_at
+			THEN FLOOR(boost_points * LEAST(EXTRACT(EPOCH FROM boosted_until - ?) / EXTRACT(EPOCH FROM boosted_until - boost_started_at), 1))::INTEGER
+			ELSE 0 END`, now, now))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	
+	err := pr.GetDB().Model(&models.Property{}).
+		Where("boosted_until <= ?", now).
+		Updates(map[string]interface{}{
+			"boost_points":     0,
+			"boost_started_at": nil,
+			"boosted_until":    nil,
+		}).Error
+	return result.RowsAffected, err
+}
+
+// GetPublicByIDs retrieves the properties with the given IDs that can be shown to other users
+func (pr *PropertyRepository) GetPublicByIDs(ids []uint) ([]models.Property, error) {
+	var properties []models.Property
+	if len(ids) == 0 {
+		return properties, nil
+	}
+	err := pr.GetDB().
+		Where("id IN ? AND is_approved = ? AND status IN ?", ids, true, models.PublicPropertyStatuses).
+		Find(&properties).Error
+	return properties, err
 }
 
 // applyFilters applies filters to the query
@@ -413,6 +474,22 @@ func (pr *PropertyRepository) applyFilters(query *gorm.DB, filters map[string]in
 			if treesIndiaAssured, ok := value.(bool); ok {
 				query = query.Where("treesindia_assured = ?", treesIndiaAssured)
 			}
+		case "featured":
+			if featured, ok := value.(bool); ok {
+				if featured {
+					query = query.Where("featured_until > ?", time.Now())
+				} else {
+					query = query.Where("featured_until IS NULL OR featured_until <= ?", time.Now())
+				}
+			}
+		case "near":
+			if radius, ok := value.(models.GeoRadius); ok {
+				query = applyGeoRadius(query, "properties", radius)
+			}
+		case "bounds":
+			if bounds, ok := value.(models.GeoBounds); ok {
+				query = applyGeoBounds(query, "properties", bounds)
+			}
 		case "sort_by":
 			// Handle sorting - this will be processed after the switch statement
 		case "sort_order":
@@ -434,6 +511,15 @@ func (pr *PropertyRepository) applyFilters(query *gorm.DB, filters map[string]in
 			}
 			query = query.Order(sortByStr + " " + sortOrder)
 		}
+	} else if _, exists := filters["near"]; exists {
+		// Radius searches are ordered by distance
+		query = query.Order("distance_meters ASC")
+	} else if !isAdmin {
+		// Featured listings first, then by priority including boosts
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "CASE WHEN featured_until > ? THEN 0 ELSE 1 END, priority_score DESC, created_at DESC",
+			Vars: []interface{}{time.Now()},
+		}})
 	} else {
 		// Default sorting by created_at desc
 		query = query.Order("created_at DESC")
@@ -564,7 +650,7 @@ func (pr *PropertyRepository) GetPropertyStats() (map[string]interface{}, error)
 	
 	logrus.Infof("PropertyRepository.GetPropertyStats called")
 	
-	var totalProperties, approvedProperties, pendingProperties, soldProperties, rentedProperties, treesindiaAssuredProperties int64
+	var totalProperties, approvedProperties, pendingProperties, soldProperties, rentedProperties, expiredProperties, treesindiaAssuredProperties int64
 	var residentialProperties, commercialProperties, saleProperties, rentProperties int64
 	
 	// Get total properties count
@@ -602,6 +688,13 @@ func (pr *PropertyRepository) GetPropertyStats() (map[string]interface{}, error)
 		return nil, err
 	}
 	
+	// Get expired properties count
+	err = pr.GetDB().Model(&models.Property{}).Where("status = ?", models.PropertyStatusExpired).Count(&expiredProperties).Error
+	if err != nil {
+		logrus.Errorf("PropertyRepository.GetPropertyStats expired count error: %v", err)
+		return nil, err
+	}
+	
 	// Get Trees India Assured properties count
 	err = pr.GetDB().Model(&models.Property{}).Where("treesindia_assured = ?", true).Count(&treesindiaAssuredProperties).Error
 	if err != nil {
@@ -648,8 +741,7 @@ func (pr *PropertyRepository) GetPropertyStats() (map[string]interface{}, error)
 		"commercial_properties":       commercialProperties,
 		"sale_properties":             saleProperties,
 		"rent_properties":             rentProperties,
-		// Keep backward compatibility
-		"expired_properties":          soldProperties + rentedProperties,
+		"expired_properties":          expiredProperties,
 	}
 	
 	logrus.Infof("PropertyRepository.GetPropertyStats retrieved stats: %+v", stats)
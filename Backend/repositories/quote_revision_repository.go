@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"time"
+	"treesindia/database"
+	"treesindia/models"
+
+	"gorm.io/gorm"
+)
+
+type QuoteRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewQuoteRevisionRepository() *QuoteRevisionRepository {
+	return &QuoteRevisionRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create creates a quote revision together with its line items
+func (qrr *QuoteRevisionRepository) Create(revision *models.QuoteRevision) error {
+	return qrr.db.Create(revision).Error
+}
+
+// GetByID gets a quote revision by ID
+func (qrr *QuoteRevisionRepository) GetByID(id uint) (*models.QuoteRevision, error) {
+	var revision models.QuoteRevision
+	err := qrr.db.Preload("LineItems").First(&revision, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetByBookingID gets the full revision history of a booking, oldest first
+func (qrr *QuoteRevisionRepository) GetByBookingID(bookingID uint) ([]models.QuoteRevision, error) {
+	var revisions []models.QuoteRevision
+	err := qrr.db.Where("booking_id = ?", bookingID).
+		Order("version ASC").
+		Preload("LineItems").
+		Preload("Author").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+// GetActiveQuote gets the admin quote currently awaiting the customer
+func (qrr *QuoteRevisionRepository) GetActiveQuote(bookingID uint) (*models.QuoteRevision, error) {
+	var revision models.QuoteRevision
+	err := qrr.db.Where("booking_id = ? AND type = ? AND status = ?", bookingID, models.QuoteRevisionTypeQuote, models.QuoteRevisionStatusActive).
+		Order("version DESC").
+		Preload("LineItems").
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetLatestVersion gets the highest revision number of a booking (0 if none)
+func (qrr *QuoteRevisionRepository) GetLatestVersion(bookingID uint) (int, error) {
+	var version int
+	err := qrr.db.Model(&models.QuoteRevision{}).
+		Where("booking_id = ?", bookingID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&version).Error
+	return version, err
+}
+
+// CountByBookingID counts the revisions of a booking
+func (qrr *QuoteRevisionRepository) CountByBookingID(bookingID uint) (int64, error) {
+	var count int64
+	err := qrr.db.Model(&models.QuoteRevision{}).Where("booking_id = ?", bookingID).Count(&count).Error
+	return count, err
+}
+
+// UpdateStatusByBooking moves unlocked revisions of a booking from one set of statuses to another
+func (qrr *QuoteRevisionRepository) UpdateStatusByBooking(bookingID uint, from []models.QuoteRevisionStatus, to models.QuoteRevisionStatus) error {
+	return qrr.db.Model(&models.QuoteRevision{}).
+		Where("booking_id = ? AND status IN ? AND locked_at IS NULL", bookingID, from).
+		Update("status", to).Error
+}
+
+// Lock marks a revision as accepted and locks it
+func (qrr *QuoteRevisionRepository) Lock(revisionID uint, lockedAt time.Time) error {
+	return qrr.db.Model(&models.QuoteRevision{}).
+		Where("id = ? AND locked_at IS NULL", revisionID).
+		Updates(map[string]interface{}{
+			"status":    models.QuoteRevisionStatusAccepted,
+			"locked_at": lockedAt,
+		}).Error
+}
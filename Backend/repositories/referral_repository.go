@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"treesindia/database"
+	"treesindia/models"
+
+	"gorm.io/gorm"
+)
+
+type ReferralRepository struct {
+	db *gorm.DB
+}
+
+func NewReferralRepository() *ReferralRepository {
+	return &ReferralRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create creates a referral
+func (rr *ReferralRepository) Create(referral *models.Referral) error {
+	return rr.db.Create(referral).Error
+}
+
+// GetByRefereeID gets the referral of a referred user
+func (rr *ReferralRepository) GetByRefereeID(refereeID uint) (*models.Referral, error) {
+	var referral models.Referral
+	err := rr.db.Where("referee_id = ?", refereeID).First(&referral).Error
+	if err != nil {
+		return nil, err
+	}
+	return &referral, nil
+}
+
+// MarkRewarded records the reward of a pending referral within tx; returns false if it was already
+// rewarded
+func (rr *ReferralRepository) MarkRewarded(tx *gorm.DB, referral *models.Referral) (bool, error) {
+	result := tx.Model(&models.Referral{}).
+		Where("id = ? AND status = ?", referral.ID, models.ReferralStatusPending).
+		Updates(map[string]interface{}{
+			"status":            models.ReferralStatusRewarded,
+			"reward_booking_id": referral.RewardBookingID,
+			"referrer_reward":   referral.ReferrerReward,
+			"referee_reward":    referral.RefereeReward,
+			"rewarded_at":       referral.RewardedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// SetUserReferralCode stores a user's referral code if they don't have one yet
+func (rr *ReferralRepository) SetUserReferralCode(userID uint, code string) error {
+	return rr.db.Model(&models.User{}).
+		Where("id = ? AND referral_code IS NULL", userID).
+		Update("referral_code", code).Error
+}
+
+// GetUserByReferralCode gets the user who owns a referral code
+func (rr *ReferralRepository) GetUserByReferralCode(code string) (*models.User, error) {
+	var user models.User
+	err := rr.db.Where("referral_code = ?", code).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetReferrerSummary counts a referrer's referrals and the rewards they earned
+func (rr *ReferralRepository) GetReferrerSummary(referrerID uint) (*models.ReferralSummary, error) {
+	var summary models.ReferralSummary
+	err := rr.db.Model(&models.Referral{}).
+		Select(`COUNT(*) AS total_referrals,
+			COUNT(*) FILTER (WHERE status = ?) AS pending_referrals,
+			COALESCE(SUM(referrer_reward), 0) AS total_earned`, models.ReferralStatusPending).
+		Where("referrer_id = ?", referrerID).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// GetReferrals gets referrals with pagination, optionally by status
+func (rr *ReferralRepository) GetReferrals(status models.ReferralStatus, page, limit int) ([]models.Referral, *Pagination, error) {
+	var referrals []models.Referral
+	var total int64
+
+	query := rr.db.Model(&models.Referral{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset := (page - 1) * limit
+	err = query.Preload("Referrer").Preload("Referee").
+		Order("created_at DESC").Offset(offset).Limit(limit).Find(&referrals).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	pagination := &Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	return referrals, pagination, nil
+}
+
+// GetReport summarises referrals and rewards paid
+func (rr *ReferralRepository) GetReport() (*models.ReferralReport, error) {
+	var report models.ReferralReport
+	err := rr.db.Model(&models.Referral{}).
+		Select(`COUNT(*) AS total_referrals,
+			COUNT(*) FILTER (WHERE status = ?) AS pending_referrals,
+			COUNT(*) FILTER (WHERE status = ?) AS rewarded_referrals,
+			COALESCE(SUM(referrer_reward), 0) AS total_referrer_paid,
+			COALESCE(SUM(referee_reward), 0) AS total_referee_paid`,
+			models.ReferralStatusPending, models.ReferralStatusRewarded).
+		Scan(&report).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"time"
+	"treesindia/database"
+	"treesindia/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SavedSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSavedSearchRepository() *SavedSearchRepository {
+	return &SavedSearchRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create creates a saved search
+func (ssr *SavedSearchRepository) Create(search *models.SavedSearch) error {
+	return ssr.db.Create(search).Error
+}
+
+// Update updates a saved search
+func (ssr *SavedSearchRepository) Update(search *models.SavedSearch) error {
+	return ssr.db.Save(search).Error
+}
+
+// Delete deletes a saved search
+func (ssr *SavedSearchRepository) Delete(id uint) error {
+	return ssr.db.Delete(&models.SavedSearch{}, id).Error
+}
+
+// GetByID gets a saved search by ID
+func (ssr *SavedSearchRepository) GetByID(id uint) (*models.SavedSearch, error) {
+	var search models.SavedSearch
+	if err := ssr.db.First(&search, id).Error; err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+// GetByUser gets a user's saved searches, newest first
+func (ssr *SavedSearchRepository) GetByUser(userID uint) ([]models.SavedSearch, error) {
+	var searches []models.SavedSearch
+	err := ssr.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&searches).Error
+	return searches, err
+}
+
+// CountByUser counts a user's saved searches
+func (ssr *SavedSearchRepository) CountByUser(userID uint) (int64, error) {
+	var count int64
+	err := ssr.db.Model(&models.SavedSearch{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// GetAlertingSearches gets the saved searches with alerts turned on, except those of the given user
+func (ssr *SavedSearchRepository) GetAlertingSearches(excludeUserID uint) ([]models.SavedSearch, error) {
+	var searches []models.SavedSearch
+	err := ssr.db.Where("alert_frequency <> ? AND user_id <> ?", models.AlertFrequencyOff, excludeUserID).
+		Find(&searches).Error
+	return searches, err
+}
+
+// CreateMatch records that a saved search matched a property. It reports false if the match was
+// already recorded.
+func (ssr *SavedSearchRepository) CreateMatch(match *models.SavedSearchMatch) (bool, error) {
+	result := ssr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(match)
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkMatchesNotified records that the owner of a saved search was alerted about the given matches
+func (ssr *SavedSearchRepository) MarkMatchesNotified(matchIDs []uint, notifiedAt time.Time) error {
+	if len(matchIDs) == 0 {
+		return nil
+	}
+	return ssr.db.Model(&models.SavedSearchMatch{}).
+		Where("id IN ?", matchIDs).
+		Update("notified_at", notifiedAt).Error
+}
+
+// GetSearchesDueDigest gets the daily saved searches with matches not yet alerted about whose
+// last digest was sent before the given time
+func (ssr *SavedSearchRepository) GetSearchesDueDigest(digestBefore time.Time) ([]models.SavedSearch, error) {
+	var searches []models.SavedSearch
+	err := ssr.db.Where("alert_frequency = ?", models.AlertFrequencyDaily).
+		Where("last_digest_at IS NULL OR last_digest_at < ?", digestBefore).
+		Where("EXISTS (SELECT 1 FROM saved_search_matches m WHERE m.saved_search_id = saved_searches.id AND m.notified_at IS NULL AND m.deleted_at IS NULL)").
+		Find(&searches).Error
+	return searches, err
+}
+
+// GetPendingMatches gets the matches of a saved search not yet alerted about, with their properties
+func (ssr *SavedSearchRepository) GetPendingMatches(searchID uint) ([]models.SavedSearchMatch, error) {
+	var matches []models.SavedSearchMatch
+	err := ssr.db.Preload("Property").
+		Where("saved_search_id = ? AND notified_at IS NULL", searchID).
+		Order("created_at ASC").
+		Find(&matches).Error
+	return matches, err
+}
+
+// UpdateLastDigestAt records when the daily digest of a saved search was sent
+func (ssr *SavedSearchRepository) UpdateLastDigestAt(searchID uint, sentAt time.Time) error {
+	return ssr.db.Model(&models.SavedSearch{}).
+		Where("id = ?", searchID).
+		Update("last_digest_at", sentAt).Error
+}
@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"time"
+	"treesindia/database"
+	"treesindia/models"
+
+	"gorm.io/gorm"
+)
+
+type SearchAnalyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchAnalyticsRepository() *SearchAnalyticsRepository {
+	return &SearchAnalyticsRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create logs a search
+func (sr *SearchAnalyticsRepository) Create(log *models.SearchQueryLog) error {
+	return sr.db.Create(log).Error
+}
+
+// GetByID gets a logged search by ID
+func (sr *SearchAnalyticsRepository) GetByID(id uint) (*models.SearchQueryLog, error) {
+	var log models.SearchQueryLog
+	err := sr.db.First(&log, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+// RecordClick stores the result a user opened from a search
+func (sr *SearchAnalyticsRepository) RecordClick(id uint, entityType models.SearchEntityType, entityID uint) error {
+	return sr.db.Model(&models.SearchQueryLog{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"click_count":         gorm.Expr("click_count + 1"),
+			"clicked_entity_type": string(entityType),
+			"clicked_entity_id":   entityID,
+			"clicked_at":          time.Now(),
+		}).Error
+}
+
+// ClaimGuestSearch attaches a guest search to the user who clicked a result from it
+func (sr *SearchAnalyticsRepository) ClaimGuestSearch(id, userID uint) error {
+	return sr.db.Model(&models.SearchQueryLog{}).
+		Where("id = ? AND user_id IS NULL", id).
+		Update("user_id", userID).Error
+}
+
+// AttributeBooking marks the user's latest unconverted search that opened the service between since and
+// until as converted by the booking, unless the booking is already credited to a search. Returns the
+// number of searches updated (0 or 1).
+func (sr *SearchAnalyticsRepository) AttributeBooking(userID, serviceID, bookingID uint, since, until time.Time) (int64, error) {
+	latest := sr.db.Model(&models.SearchQueryLog{}).
+		Select("id").
+		Where("user_id = ? AND clicked_entity_type = ? AND clicked_entity_id = ?", userID, models.SearchEntityService, serviceID).
+		Where("booking_id IS NULL AND clicked_at BETWEEN ? AND ?", since, until).
+		Order("clicked_at DESC").
+		Limit(1)
+
+	result := sr.db.Model(&models.SearchQueryLog{}).
+		Where("id IN (?)", latest).
+		Where("NOT EXISTS (SELECT 1 FROM search_query_logs converted WHERE converted.booking_id = ?)", bookingID).
+		Updates(map[string]interface{}{
+			"booking_id":   bookingID,
+			"converted_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// GetSummary gets headline search numbers since the given time
+func (sr *SearchAnalyticsRepository) GetSummary(since time.Time) (*models.SearchAnalyticsSummary, error) {
+	var summary models.SearchAnalyticsSummary
+	err := sr.db.Model(&models.SearchQueryLog{}).
+		Select(`COUNT(*) AS total_searches,
+			COUNT(DISTINCT normalized_query) AS unique_queries,
+			COUNT(*) FILTER (WHERE result_count = 0) AS zero_result_searches,
+			COUNT(*) FILTER (WHERE booking_id IS NOT NULL) AS conversions`).
+		Where("created_at >= ?", since).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var clicked int64
+	err = sr.db.Model(&models.SearchQueryLog{}).
+		Where("created_at >= ? AND click_count > 0", since).
+		Count(&clicked).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if summary.TotalSearches > 0 {
+		total := float64(summary.TotalSearches)
+		summary.ZeroResultRate = float64(summary.ZeroResultSearches) / total * 100
+		summary.ClickThroughRate = float64(clicked) / total * 100
+		summary.ConversionRate = float64(summary.Conversions) / total * 100
+	}
+
+	return &summary, nil
+}
+
+// GetTopQueries gets the most searched queries since the given time
+func (sr *SearchAnalyticsRepository) GetTopQueries(since time.Time, limit int) ([]models.SearchQueryStat, error) {
+	return sr.getQueryStats(since, limit, false)
+}
+
+// GetZeroResultQueries gets the most searched queries that returned nothing since the given time
+func (sr *SearchAnalyticsRepository) GetZeroResultQueries(since time.Time, limit int) ([]models.SearchQueryStat, error) {
+	return sr.getQueryStats(since, limit, true)
+}
+
+// GetSuggestableQueries gets the most searched queries since the given time that returned results
+// and were searched by at least minUsers distinct signed-in users
+func (sr *SearchAnalyticsRepository) GetSuggestableQueries(since time.Time, minUsers, limit int) ([]models.SearchQueryStat, error) {
+	var stats []models.SearchQueryStat
+	err := sr.db.Model(&models.SearchQueryLog{}).
+		Select(`normalized_query AS query,
+			COUNT(*) AS searches,
+			AVG(result_count) AS average_results,
+			MAX(created_at) AS last_searched_at`).
+		Where("created_at >= ?", since).
+		Group("normalized_query").
+		Having("COUNT(DISTINCT user_id) >= ? AND AVG(result_count) > 0", minUsers).
+		Order("searches DESC, last_searched_at DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// getQueryStats aggregates searches per normalized query, most searched first
+func (sr *SearchAnalyticsRepository) getQueryStats(since time.Time, limit int, zeroResultsOnly bool) ([]models.SearchQueryStat, error) {
+	var stats []models.SearchQueryStat
+
+	query := sr.db.Model(&models.SearchQueryLog{}).
+		Select(`normalized_query AS query,
+			COUNT(*) AS searches,
+			AVG(result_count) AS average_results,
+			COUNT(*) FILTER (WHERE click_count > 0) AS clicks,
+			COUNT(*) FILTER (WHERE booking_id IS NOT NULL) AS conversions,
+			MAX(created_at) AS last_searched_at`).
+		Where("created_at >= ?", since)
+	if zeroResultsOnly {
+		query = query.Where("result_count = 0")
+	}
+
+	err := query.Group("normalized_query").
+		Order("searches DESC, last_searched_at DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, err
+}
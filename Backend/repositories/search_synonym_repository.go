@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"strings"
+	"treesindia/database"
+	"treesindia/models"
+
+	"gorm.io/gorm"
+)
+
+type SearchSynonymRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchSynonymRepository() *SearchSynonymRepository {
+	return &SearchSynonymRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create creates a search synonym
+func (sr *SearchSynonymRepository) Create(synonym *models.SearchSynonym) error {
+	return sr.db.Create(synonym).Error
+}
+
+// GetByID gets a search synonym by ID
+func (sr *SearchSynonymRepository) GetByID(id uint) (*models.SearchSynonym, error) {
+	var synonym models.SearchSynonym
+	err := sr.db.First(&synonym, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &synonym, nil
+}
+
+// GetByTerm gets a search synonym by its term
+func (sr *SearchSynonymRepository) GetByTerm(term string) (*models.SearchSynonym, error) {
+	var synonym models.SearchSynonym
+	err := sr.db.Where("term = ?", term).First(&synonym).Error
+	if err != nil {
+		return nil, err
+	}
+	return &synonym, nil
+}
+
+// Update updates a search synonym
+func (sr *SearchSynonymRepository) Update(synonym *models.SearchSynonym) error {
+	return sr.db.Save(synonym).Error
+}
+
+// Delete soft deletes a search synonym
+func (sr *SearchSynonymRepository) Delete(id uint) error {
+	return sr.db.Delete(&models.SearchSynonym{}, id).Error
+}
+
+// GetActive gets every active search synonym
+func (sr *SearchSynonymRepository) GetActive() ([]models.SearchSynonym, error) {
+	var synonyms []models.SearchSynonym
+	err := sr.db.Where("is_active = ?", true).Find(&synonyms).Error
+	return synonyms, err
+}
+
+// GetSynonyms gets search synonyms matching a term with pagination
+func (sr *SearchSynonymRepository) GetSynonyms(search string, page, limit int) ([]models.SearchSynonym, *Pagination, error) {
+	var synonyms []models.SearchSynonym
+	var total int64
+
+	query := sr.db.Model(&models.SearchSynonym{})
+	if search != "" {
+		query = query.Where("term LIKE ?", "%"+strings.ToLower(search)+"%")
+	}
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Apply pagination
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset := (page - 1) * limit
+	err = query.Order("term ASC").Offset(offset).Limit(limit).Find(&synonyms).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	pagination := &Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+
+	return synonyms, pagination, nil
+}
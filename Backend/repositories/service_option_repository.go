@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"treesindia/database"
+	"treesindia/models"
+
+	"gorm.io/gorm"
+)
+
+type ServiceOptionRepository struct {
+	db *gorm.DB
+}
+
+func NewServiceOptionRepository() *ServiceOptionRepository {
+	return &ServiceOptionRepository{
+		db: database.GetDB(),
+	}
+}
+
+// CreateVariant creates a service variant
+func (sr *ServiceOptionRepository) CreateVariant(variant *models.ServiceVariant) error {
+	return sr.db.Create(variant).Error
+}
+
+// GetVariantByID gets a variant of a service
+func (sr *ServiceOptionRepository) GetVariantByID(serviceID, variantID uint) (*models.ServiceVariant, error) {
+	var variant models.ServiceVariant
+	err := sr.db.Where("service_id = ?", serviceID).First(&variant, variantID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// UpdateVariant updates a service variant
+func (sr *ServiceOptionRepository) UpdateVariant(variant *models.ServiceVariant) error {
+	return sr.db.Save(variant).Error
+}
+
+// DeleteVariant deletes a variant of a service
+func (sr *ServiceOptionRepository) DeleteVariant(serviceID, variantID uint) error {
+	result := sr.db.Where("service_id = ?", serviceID).Delete(&models.ServiceVariant{}, variantID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetVariants gets the variants of a service in display order
+func (sr *ServiceOptionRepository) GetVariants(serviceID uint, activeOnly bool) ([]models.ServiceVariant, error) {
+	var variants []models.ServiceVariant
+	query := sr.db.Where("service_id = ?", serviceID)
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("sort_order ASC, id ASC").Find(&variants).Error
+	return variants, err
+}
+
+// CreateAddOn creates a service add-on
+func (sr *ServiceOptionRepository) CreateAddOn(addOn *models.ServiceAddOn) error {
+	return sr.db.Create(addOn).Error
+}
+
+// GetAddOnByID gets an add-on of a service
+func (sr *ServiceOptionRepository) GetAddOnByID(serviceID, addOnID uint) (*models.ServiceAddOn, error) {
+	var addOn models.ServiceAddOn
+	err := sr.db.Where("service_id = ?", serviceID).First(&addOn, addOnID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &addOn, nil
+}
+
+// UpdateAddOn updates a service add-on
+func (sr *ServiceOptionRepository) UpdateAddOn(addOn *models.ServiceAddOn) error {
+	return sr.db.Save(addOn).Error
+}
+
+// DeleteAddOn deletes an add-on of a service
+func (sr *ServiceOptionRepository) DeleteAddOn(serviceID, addOnID uint) error {
+	result := sr.db.Where("service_id = ?", serviceID).Delete(&models.ServiceAddOn{}, addOnID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetAddOns gets the add-ons of a service in display order
+func (sr *ServiceOptionRepository) GetAddOns(serviceID uint, activeOnly bool) ([]models.ServiceAddOn, error) {
+	var addOns []models.ServiceAddOn
+	query := sr.db.Where("service_id = ?", serviceID)
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("sort_order ASC, id ASC").Find(&addOns).Error
+	return addOns, err
+}
@@ -374,7 +374,7 @@ func (sr *ServiceRepository) Update(service *models.Service) error {
 	// Use Omit to exclude preloaded relationships from being saved
 	// This prevents issues when service has preloaded Category, ServiceAreas, etc.
 	return sr.GetDB().Model(service).
-		Omit("Category", "ServiceAreas", "CreatedAt").
+		Omit("Category", "ServiceAreas", "Variants", "AddOns", "CreatedAt").
 		Save(service).Error
 }
 
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"treesindia/database"
+	"treesindia/models"
+	"treesindia/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShortlistRepository struct {
+	db *gorm.DB
+}
+
+func NewShortlistRepository() *ShortlistRepository {
+	return &ShortlistRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Add shortlists an item for a user; shortlisting an item twice has no effect
+func (sr *ShortlistRepository) Add(item *models.Shortlist) error {
+	return sr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(item).Error
+}
+
+// Remove removes an item from a user's shortlist
+func (sr *ShortlistRepository) Remove(userID uint, itemType models.ShortlistItemType, itemID uint) (bool, error) {
+	result := sr.db.Unscoped().
+		Where("user_id = ? AND item_type = ? AND item_id = ?", userID, itemType, itemID).
+		Delete(&models.Shortlist{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetByUser gets a user's shortlist, most recently added first, optionally of one item type
+func (sr *ShortlistRepository) GetByUser(userID uint, itemType string, params utils.PaginationParams) ([]models.Shortlist, utils.PaginationResponse, error) {
+	paginationHelper := utils.NewPaginationHelper()
+
+	query := sr.db.Model(&models.Shortlist{}).Where("user_id = ?", userID)
+	if itemType != "" {
+		query = query.Where("item_type = ?", itemType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, utils.PaginationResponse{}, err
+	}
+
+	var items []models.Shortlist
+	err := paginationHelper.ApplyPagination(query, params).Order("created_at DESC").Find(&items).Error
+	if err != nil {
+		return nil, utils.PaginationResponse{}, err
+	}
+
+	return items, paginationHelper.CalculatePagination(total, params), nil
+}
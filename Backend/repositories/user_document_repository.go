@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"time"
 	"treesindia/models"
 )
 
@@ -76,3 +77,47 @@ func (r *UserDocumentRepository) CheckRequiredDocuments(userID uint) (bool, erro
 	
 	return count == int64(len(requiredTypes)), err
 }
+
+// GetDocumentsByUserAndTypes gets a user's documents of the given types
+func (r *UserDocumentRepository) GetDocumentsByUserAndTypes(userID uint, documentTypes []models.DocumentType) ([]models.UserDocument, error) {
+	var documents []models.UserDocument
+	err := r.db.Where("user_id = ? AND document_type IN ?", userID, documentTypes).Find(&documents).Error
+	return documents, err
+}
+
+// HasExpiredDocuments checks if any of a user's documents of the given types are past their expiry date
+func (r *UserDocumentRepository) HasExpiredDocuments(userID uint, documentTypes []models.DocumentType, now time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.UserDocument{}).
+		Where("user_id = ? AND document_type IN ? AND expires_at IS NOT NULL AND expires_at < ?", userID, documentTypes, now).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetUserIDsWithExpiredDocuments gets which of the given users have a document of the given types past its expiry date
+func (r *UserDocumentRepository) GetUserIDsWithExpiredDocuments(userIDs []uint, documentTypes []models.DocumentType, now time.Time) ([]uint, error) {
+	var ids []uint
+	if len(userIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.UserDocument{}).
+		Where("user_id IN ? AND document_type IN ? AND expires_at IS NOT NULL AND expires_at < ?", userIDs, documentTypes, now).
+		Distinct().
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// GetVerifiedDocumentsExpiredBefore gets verified documents whose expiry date has passed
+func (r *UserDocumentRepository) GetVerifiedDocumentsExpiredBefore(now time.Time) ([]models.UserDocument, error) {
+	var documents []models.UserDocument
+	err := r.db.Where("verification_status = ? AND expires_at IS NOT NULL AND expires_at < ?", models.DocumentVerificationStatusVerified, now).
+		Find(&documents).Error
+	return documents, err
+}
+
+// MarkExpired marks a verified document as expired
+func (r *UserDocumentRepository) MarkExpired(id uint) error {
+	return r.db.Model(&models.UserDocument{}).
+		Where("id = ? AND verification_status = ?", id, models.DocumentVerificationStatusVerified).
+		Update("verification_status", models.DocumentVerificationStatusExpired).Error
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+	"treesindia/database"
+	"treesindia/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InsufficientWalletBalanceError is returned when a debit is larger than the wallet balance
+type InsufficientWalletBalanceError struct {
+	Required  float64
+	Available float64
+}
+
+func (e *InsufficientWalletBalanceError) Error() string {
+	return fmt.Sprintf("insufficient wallet balance. Required: ₹%.2f, Available: ₹%.2f", e.Required, e.Available)
+}
+
+// WalletLedgerRepository moves wallet balances together with the payments that record them
+type WalletLedgerRepository struct {
+	db *gorm.DB
+}
+
+func NewWalletLedgerRepository() *WalletLedgerRepository {
+	return &WalletLedgerRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Post adds delta to the user's wallet balance and creates payment as a completed transaction
+// carrying the new balance. The user's row is locked for the read-modify-write, so concurrent
+// posts cannot lose an update. Post joins tx when it is set, so the caller's own writes commit or
+// roll back with the balance change; otherwise it runs its own transaction.
+func (wlr *WalletLedgerRepository) Post(tx *gorm.DB, payment *models.Payment, delta float64) error {
+	if tx == nil {
+		return wlr.db.Transaction(func(tx *gorm.DB) error {
+			return wlr.post(tx, payment, delta)
+		})
+	}
+	return wlr.post(tx, payment, delta)
+}
+
+func (wlr *WalletLedgerRepository) post(tx *gorm.DB, payment *models.Payment, delta float64) error {
+	var user models.User
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "wallet_balance").
+		First(&user, payment.UserID).Error
+	if err != nil {
+		return err
+	}
+
+	newBalance := user.WalletBalance + delta
+	if delta < 0 && newBalance < 0 {
+		return &InsufficientWalletBalanceError{Required: -delta, Available: user.WalletBalance}
+	}
+
+	if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Update("wallet_balance", newBalance).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	payment.Status = models.PaymentStatusCompleted
+	payment.CompletedAt = &now
+	payment.BalanceAfter = &newBalance
+	return tx.Omit(clause.Associations).Create(payment).Error
+}
@@ -1,7 +1,9 @@
 package repositories
 
 import (
+	"errors"
 	"strings"
+	"time"
 	"treesindia/database"
 	"treesindia/models"
 
@@ -81,6 +83,122 @@ func (war *WorkerAssignmentRepository) Update(assignment *models.WorkerAssignmen
 		Save(assignment).Error
 }
 
+// Complete marks an accepted or in-progress assignment as completed. Returns whether this call
+// completed it, so a completion raced by another caller is not rewarded twice.
+func (war *WorkerAssignmentRepository) Complete(assignment *models.WorkerAssignment, now time.Time) (bool, error) {
+	result := war.db.Model(&models.WorkerAssignment{}).
+		Where("id = ? AND status IN ?", assignment.ID, []models.AssignmentStatus{models.AssignmentStatusAccepted, models.AssignmentStatusInProgress}).
+		Updates(map[string]interface{}{
+			"status":       models.AssignmentStatusCompleted,
+			"started_at":   gorm.Expr("COALESCE(started_at, ?)", now),
+			"completed_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// GetInProgressStartedBefore gets in-progress assignments that were started before the given time
+func (war *WorkerAssignmentRepository) GetInProgressStartedBefore(startedBefore time.Time) ([]models.WorkerAssignment, error) {
+	var assignments []models.WorkerAssignment
+	err := war.db.Where("status = ? AND started_at < ?", models.AssignmentStatusInProgress, startedBefore).
+		Preload("Booking.Service").
+		Preload("Booking.User").
+		Preload("Worker").
+		Order("started_at ASC").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// GetUnacceptedPastDeadline gets assigned assignments whose acceptance deadline has passed and whose
+// booking is still waiting for the worker to accept
+func (war *WorkerAssignmentRepository) GetUnacceptedPastDeadline(now time.Time) ([]models.WorkerAssignment, error) {
+	var assignments []models.WorkerAssignment
+	err := war.db.Joins("JOIN bookings ON bookings.id = worker_assignments.booking_id").
+		Where("worker_assignments.status = ? AND worker_assignments.accept_by < ? AND bookings.status = ? AND bookings.deleted_at IS NULL",
+			models.AssignmentStatusAssigned, now, models.BookingStatusAssigned).
+		Preload("Booking").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// ExpireUnaccepted marks an assignment the worker did not accept in time as rejected and hands its
+// booking back for reassignment. Both updates only apply while the assignment and booking are still
+// assigned, so a booking accepted or cancelled in the meantime is left alone. Returns whether the
+// assignment was expired.
+func (war *WorkerAssignmentRepository) ExpireUnaccepted(assignment *models.WorkerAssignment, reason string, now time.Time) (bool, error) {
+	expired := false
+	err := war.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.WorkerAssignment{}).
+			Where("id = ? AND status = ?", assignment.ID, models.AssignmentStatusAssigned).
+			Updates(map[string]interface{}{
+				"status":           models.AssignmentStatusRejected,
+				"rejected_at":      now,
+				"rejection_reason": reason,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		result = tx.Model(&models.Booking{}).
+			Where("id = ? AND status = ?", assignment.BookingID, models.BookingStatusAssigned).
+			Update("status", models.BookingStatusConfirmed)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Roll back: the booking moved on, so the assignment is not the worker's to lose
+			return errBookingNotAssigned
+		}
+
+		expired = true
+		return nil
+	})
+	if errors.Is(err, errBookingNotAssigned) {
+		return false, nil
+	}
+	return expired, err
+}
+
+var errBookingNotAssigned = errors.New("booking is no longer assigned")
+
+// CancelOpenAssignment closes the assignment of a cancelled booking if the service has not started.
+// Returns the assignment as it was before closing, or nil if there was no open assignment.
+func (war *WorkerAssignmentRepository) CancelOpenAssignment(bookingID uint) (*models.WorkerAssignment, error) {
+	var assignment models.WorkerAssignment
+	openStatuses := []models.AssignmentStatus{models.AssignmentStatusReserved, models.AssignmentStatusAssigned, models.AssignmentStatusAccepted}
+	err := war.db.Where("booking_id = ? AND status IN ?", bookingID, openStatuses).First(&assignment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	result := war.db.Model(&models.WorkerAssignment{}).
+		Where("id = ? AND status IN ?", assignment.ID, openStatuses).
+		Update("status", models.AssignmentStatusCancelled)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	return &assignment, nil
+}
+
+// GetRejectedAwaitingReassignment gets rejected assignments whose booking still needs a worker and
+// has not been handed to an admin
+func (war *WorkerAssignmentRepository) GetRejectedAwaitingReassignment() ([]models.WorkerAssignment, error) {
+	var assignments []models.WorkerAssignment
+	err := war.db.Joins("JOIN bookings ON bookings.id = worker_assignments.booking_id").
+		Where("worker_assignments.status = ? AND bookings.status = ? AND bookings.assignment_escalated_at IS NULL AND bookings.deleted_at IS NULL",
+			models.AssignmentStatusRejected, models.BookingStatusConfirmed).
+		Preload("Booking.Service").
+		Preload("Booking.User").
+		Order("worker_assignments.rejected_at ASC").
+		Find(&assignments).Error
+	return assignments, err
+}
+
 // Delete deletes a worker assignment by ID
 func (war *WorkerAssignmentRepository) Delete(id uint) error {
 	return war.db.Delete(&models.WorkerAssignment{}, id).Error
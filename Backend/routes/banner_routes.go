@@ -13,15 +13,15 @@ func SetupBannerRoutes(router *gin.RouterGroup) {
 	// Initialize repositories
 	bannerRepo := repositories.NewBannerRepository(database.GetDB())
 	
-	// Initialize Cloudinary service
-	cloudinaryService, err := services.NewCloudinaryService()
+	// Initialize media store
+	mediaStore, err := services.NewMediaStore()
 	if err != nil {
-		// Log error but continue - Cloudinary is optional for banner images
-		cloudinaryService = nil
+		// Log error but continue - the media store is optional for banner images
+		mediaStore = nil
 	}
 	
 	// Initialize services
-	bannerService := services.NewBannerService(bannerRepo, cloudinaryService)
+	bannerService := services.NewBannerService(bannerRepo, mediaStore)
 	
 	// Initialize controllers
 	bannerController := controllers.NewBannerController(bannerService)
@@ -40,6 +40,9 @@ func SetupBookingRoutes(router *gin.RouterGroup, enhancedNotificationService *se
 
 		// PUT /api/v1/bookings/:id/cancel - Cancel booking
 		userBookings.PUT("/:id/cancel", bookingController.CancelUserBooking)
+
+		// GET /api/v1/bookings/:id/service-pins - Get the start and completion PINs to give the worker
+		userBookings.GET("/:id/service-pins", bookingController.GetServicePINs)
 	}
 
 	// Inquiry-based booking routes
@@ -73,6 +76,15 @@ func SetupBookingRoutes(router *gin.RouterGroup, enhancedNotificationService *se
 		
 		// GET /api/v1/bookings/:id/quote-info - Get quote information
 		bookings.GET("/:id/quote-info", quoteController.GetQuoteInfo)
+		
+		// POST /api/v1/bookings/:id/counter-offer - Counter-offer on quote
+		bookings.POST("/:id/counter-offer", middleware.AuthMiddleware(), quoteController.CounterOffer)
+		
+		// POST /api/v1/bookings/:id/request-revision - Ask for a revised quote
+		bookings.POST("/:id/request-revision", middleware.AuthMiddleware(), quoteController.RequestRevision)
+		
+		// GET /api/v1/bookings/:id/quote-revisions - Get quote revision history
+		bookings.GET("/:id/quote-revisions", middleware.AuthMiddleware(), quoteController.GetQuoteRevisions)
 	}
 
 	// Admin booking routes (admin authentication required)
@@ -104,6 +116,12 @@ func SetupBookingRoutes(router *gin.RouterGroup, enhancedNotificationService *se
 		// PUT /api/v1/admin/bookings/:id/update-quote - Update quote
 		adminBookings.PUT("/:id/update-quote", quoteController.UpdateQuote)
 		
+		// POST /api/v1/admin/bookings/:id/extend-quote - Extend quote validity
+		adminBookings.POST("/:id/extend-quote", quoteController.ExtendQuoteValidity)
+		
+		// GET /api/v1/admin/bookings/:id/quote-revisions - Get quote revision history
+		adminBookings.GET("/:id/quote-revisions", quoteController.GetQuoteRevisions)
+		
 		// GET /api/v1/admin/bookings/inquiries - Get inquiry bookings
 		adminBookings.GET("/inquiries", quoteController.GetInquiryBookings)
 		
@@ -0,0 +1,66 @@
+package routes
+
+import (
+	"treesindia/controllers"
+	"treesindia/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCouponRoutes sets up coupon and referral routes
+func SetupCouponRoutes(router *gin.RouterGroup) {
+	couponController := controllers.NewCouponController()
+	referralController := controllers.NewReferralController()
+
+	// Customer coupon routes
+	coupons := router.Group("/coupons")
+	coupons.Use(middleware.AuthMiddleware())
+	{
+		// POST /api/v1/coupons/validate - Preview a coupon's discount
+		coupons.POST("/validate", couponController.ValidateCoupon)
+	}
+
+	// Customer referral routes
+	referrals := router.Group("/referrals")
+	referrals.Use(middleware.AuthMiddleware())
+	{
+		// GET /api/v1/referrals/me - Get my referral code and earnings
+		referrals.GET("/me", referralController.GetMyReferral)
+
+		// POST /api/v1/referrals/apply - Apply a friend's referral code
+		referrals.POST("/apply", referralController.ApplyReferralCode)
+	}
+
+	// Admin coupon routes
+	adminCoupons := router.Group("/admin/coupons")
+	adminCoupons.Use(middleware.AuthMiddleware())
+	adminCoupons.Use(middleware.AdminMiddleware())
+	{
+		// GET /api/v1/admin/coupons - Get coupons
+		adminCoupons.GET("", couponController.GetCoupons)
+
+		// POST /api/v1/admin/coupons - Create coupon
+		adminCoupons.POST("", couponController.CreateCoupon)
+
+		// GET /api/v1/admin/coupons/report - Coupon and referral report
+		adminCoupons.GET("/report", couponController.GetPromotionsReport)
+
+		// GET /api/v1/admin/coupons/:id - Get coupon
+		adminCoupons.GET("/:id", couponController.GetCoupon)
+
+		// PUT /api/v1/admin/coupons/:id - Update coupon
+		adminCoupons.PUT("/:id", couponController.UpdateCoupon)
+
+		// GET /api/v1/admin/coupons/:id/redemptions - Get coupon redemptions
+		adminCoupons.GET("/:id/redemptions", couponController.GetCouponRedemptions)
+	}
+
+	// Admin referral routes
+	adminReferrals := router.Group("/admin/referrals")
+	adminReferrals.Use(middleware.AuthMiddleware())
+	adminReferrals.Use(middleware.AdminMiddleware())
+	{
+		// GET /api/v1/admin/referrals - Get referrals
+		adminReferrals.GET("", referralController.GetReferrals)
+	}
+}
@@ -35,6 +35,9 @@ func SetupDashboardRoutes(r *gin.RouterGroup) {
 		// GET /api/v1/admin/dashboard/marketplace-analytics - Get marketplace analytics
 		adminDashboard.GET("/marketplace-analytics", dashboardController.GetMarketplaceAnalytics)
 		
+		// GET /api/v1/admin/dashboard/communication-analytics - Get chat and notification analytics
+		adminDashboard.GET("/communication-analytics", dashboardController.GetCommunicationAnalytics)
+		
 		// GET /api/v1/admin/dashboard/monthly-trends - Get monthly trends data
 		adminDashboard.GET("/monthly-trends", dashboardController.GetMonthlyTrends)
 		
@@ -13,15 +13,15 @@ func SetupHeroRoutes(router *gin.RouterGroup) {
 	// Initialize repositories
 	heroRepo := repositories.NewHeroRepository(database.GetDB())
 	
-	// Initialize Cloudinary service
-	cloudinaryService, err := services.NewCloudinaryService()
+	// Initialize media store
+	mediaStore, err := services.NewMediaStore()
 	if err != nil {
-		// Log error but continue - Cloudinary is optional for hero images
-		cloudinaryService = nil
+		// Log error but continue - the media store is optional for hero images
+		mediaStore = nil
 	}
 	
 	// Initialize services
-	heroService := services.NewHeroService(heroRepo, cloudinaryService)
+	heroService := services.NewHeroService(heroRepo, mediaStore)
 	
 	// Initialize controllers
 	heroController := controllers.NewHeroController(heroService)
@@ -13,15 +13,15 @@ func SetupHomepageCategoryIconRoutes(router *gin.RouterGroup) {
 	// Initialize repositories
 	iconRepo := repositories.NewHomepageCategoryIconRepository(database.GetDB())
 	
-	// Initialize Cloudinary service
-	cloudinaryService, err := services.NewCloudinaryService()
+	// Initialize media store
+	mediaStore, err := services.NewMediaStore()
 	if err != nil {
-		// Log error but continue - Cloudinary is optional for category icons
-		cloudinaryService = nil
+		// Log error but continue - the media store is optional for category icons
+		mediaStore = nil
 	}
 	
 	// Initialize services
-	iconService := services.NewHomepageCategoryIconService(iconRepo, cloudinaryService)
+	iconService := services.NewHomepageCategoryIconService(iconRepo, mediaStore)
 	
 	// Initialize controllers
 	iconController := controllers.NewHomepageCategoryIconController(iconService)
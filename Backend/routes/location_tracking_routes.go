@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"treesindia/controllers"
+	"treesindia/middleware"
+	"treesindia/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupLocationTrackingRoutes sets up live worker tracking routes
+func SetupLocationTrackingRoutes(router *gin.RouterGroup, locationTrackingService *services.LocationTrackingService) {
+	workerLocationController := controllers.NewWorkerLocationController(locationTrackingService)
+
+	// Worker location routes (authenticated workers only)
+	workerAssignments := router.Group("/worker/assignments")
+	workerAssignments.Use(middleware.AuthMiddleware(), middleware.WorkerMiddleware())
+	{
+		// POST /api/v1/worker/assignments/:id/location - Share current location
+		workerAssignments.POST("/:id/location", workerLocationController.UpdateLocation)
+
+		// GET /api/v1/worker/assignments/:id/location - Get tracking state
+		workerAssignments.GET("/:id/location", workerLocationController.GetWorkerTracking)
+
+		// POST /api/v1/worker/assignments/:id/location/stop - Stop sharing location
+		workerAssignments.POST("/:id/location/stop", workerLocationController.StopTracking)
+	}
+
+	// Customer tracking routes
+	bookings := router.Group("/bookings")
+	bookings.Use(middleware.AuthMiddleware())
+	{
+		// GET /api/v1/bookings/:id/tracking - Get the assigned worker's live location and ETA
+		bookings.GET("/:id/tracking", workerLocationController.GetBookingTracking)
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"treesindia/controllers"
+	"treesindia/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupMediaRoutes serves locally stored media. Other media stores serve their own URLs.
+func SetupMediaRoutes(router *gin.Engine, mediaStore services.MediaStore) {
+	localStore, ok := services.AsLocalMediaStore(mediaStore)
+	if !ok {
+		return
+	}
+
+	mediaController := controllers.NewMediaController(localStore)
+
+	// GET /media/*key - Serve a media file (checks expires/signature when signed)
+	router.GET("/media/*key", mediaController.ServeMedia)
+}
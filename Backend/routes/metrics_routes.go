@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"treesindia/config"
+	"treesindia/metrics"
+	"treesindia/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupMetricsRoutes exposes Prometheus metrics
+func SetupMetricsRoutes(router *gin.Engine, appConfig *config.AppConfig) {
+	// GET /metrics - Prometheus text format (bearer token required when METRICS_TOKEN is set)
+	router.GET("/metrics", middleware.MetricsTokenMiddleware(appConfig.MetricsToken), gin.WrapH(metrics.Handler()))
+}
@@ -29,4 +29,12 @@ func SetupPaymentSegmentRoutes(router *gin.RouterGroup) {
 		// Verify segment payment
 		paymentSegmentRoutes.POST("/verify", paymentSegmentController.VerifySegmentPayment)
 	}
+
+	// Admin payment segment routes
+	adminPaymentSegmentRoutes := router.Group("/admin/bookings/:id/payment-segments")
+	adminPaymentSegmentRoutes.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		// Change when a segment falls due and which work stage it blocks
+		adminPaymentSegmentRoutes.PUT("/:segment_id/schedule", paymentSegmentController.UpdateSegmentSchedule)
+	}
 }
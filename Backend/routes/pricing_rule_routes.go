@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"treesindia/controllers"
+	"treesindia/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupPricingRuleRoutes sets up surge pricing rule and holiday routes
+func SetupPricingRuleRoutes(router *gin.RouterGroup) {
+	pricingRuleController := controllers.NewPricingRuleController()
+
+	// Admin pricing rule routes
+	adminPricingRules := router.Group("/admin/pricing-rules")
+	adminPricingRules.Use(middleware.AuthMiddleware())
+	adminPricingRules.Use(middleware.AdminMiddleware())
+	{
+		// GET /api/v1/admin/pricing-rules - Get pricing rules
+		adminPricingRules.GET("", pricingRuleController.GetPricingRules)
+
+		// POST /api/v1/admin/pricing-rules - Create pricing rule
+		adminPricingRules.POST("", pricingRuleController.CreatePricingRule)
+
+		// GET /api/v1/admin/pricing-rules/:id - Get pricing rule
+		adminPricingRules.GET("/:id", pricingRuleController.GetPricingRule)
+
+		// PUT /api/v1/admin/pricing-rules/:id - Update pricing rule
+		adminPricingRules.PUT("/:id", pricingRuleController.UpdatePricingRule)
+
+		// DELETE /api/v1/admin/pricing-rules/:id - Delete pricing rule
+		adminPricingRules.DELETE("/:id", pricingRuleController.DeletePricingRule)
+	}
+
+	// Admin holiday calendar routes
+	adminHolidays := router.Group("/admin/holidays")
+	adminHolidays.Use(middleware.AuthMiddleware())
+	adminHolidays.Use(middleware.AdminMiddleware())
+	{
+		// GET /api/v1/admin/holidays - Get holidays
+		adminHolidays.GET("", pricingRuleController.GetHolidays)
+
+		// POST /api/v1/admin/holidays - Create holiday
+		adminHolidays.POST("", pricingRuleController.CreateHoliday)
+
+		// DELETE /api/v1/admin/holidays/:id - Delete holiday
+		adminHolidays.DELETE("/:id", pricingRuleController.DeleteHoliday)
+	}
+}
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"treesindia/controllers"
+	"treesindia/middleware"
+	"treesindia/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupPropertyEnquiryRoutes sets up property enquiry, lead and site visit routes
+func SetupPropertyEnquiryRoutes(router *gin.RouterGroup, enhancedNotificationService *services.EnhancedNotificationService) {
+	enquiryController := controllers.NewPropertyEnquiryController(enhancedNotificationService)
+
+	// Buyer routes
+	router.POST("/properties/:id/enquiries", middleware.AuthMiddleware(), enquiryController.CreateEnquiry) // Enquire about a property
+
+	enquiries := router.Group("/user/enquiries")
+	enquiries.Use(middleware.AuthMiddleware())
+	{
+		enquiries.GET("", enquiryController.GetMyEnquiries)            // Get enquiries made by the user
+		enquiries.GET("/:id", enquiryController.GetEnquiry)            // Get an enquiry or lead with its site visits
+		enquiries.POST("/:id/visits", enquiryController.ScheduleVisit) // Book a site visit (buyer or listing owner)
+	}
+
+	// Listing owner and broker routes
+	leads := router.Group("/user/leads")
+	leads.Use(middleware.AuthMiddleware())
+	{
+		leads.GET("", enquiryController.GetLeads)                      // Get leads on the user's listings
+		leads.GET("/dashboard", enquiryController.GetLeadsDashboard)   // Get lead pipeline and conversion stats
+		leads.PATCH("/:id/status", enquiryController.UpdateLeadStatus) // Move a lead through the pipeline
+	}
+
+	siteVisits := router.Group("/user/site-visits")
+	siteVisits.Use(middleware.AuthMiddleware())
+	{
+		siteVisits.GET("", enquiryController.GetUpcomingVisits) // Get upcoming site visits
+		siteVisits.PATCH("/:id", enquiryController.UpdateVisit) // Cancel or record the outcome of a site visit
+	}
+}
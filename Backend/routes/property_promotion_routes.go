@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"treesindia/controllers"
+	"treesindia/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupPropertyPromotionRoutes sets up listing renewal, boost and featured slot routes
+func SetupPropertyPromotionRoutes(router *gin.RouterGroup) {
+	promotionController := controllers.NewPropertyPromotionController()
+
+	userProperties := router.Group("/user/properties")
+	userProperties.Use(middleware.AuthMiddleware())
+	{
+		userProperties.POST("/:id/renew", promotionController.RenewProperty)                  // Renew an expired or expiring listing
+		userProperties.GET("/:id/promotion-options", promotionController.GetPromotionOptions) // Get boost and featured slot prices
+		userProperties.GET("/:id/promotions", promotionController.GetPropertyPromotions)      // Get promotions bought for a listing
+		userProperties.POST("/:id/promotions", promotionController.PurchasePromotion)         // Buy a boost or featured slot
+	}
+
+	promotions := router.Group("/user/property-promotions")
+	promotions.Use(middleware.AuthMiddleware())
+	{
+		promotions.POST("/:id/verify", promotionController.VerifyPromotionPayment) // Verify the Razorpay payment of a promotion
+	}
+}
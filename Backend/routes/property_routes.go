@@ -37,6 +37,8 @@ func SetupPropertyRoutes(router *gin.RouterGroup, enhancedNotificationService *s
 		adminProperties.GET("", propertyController.GetAllPropertiesForAdmin)      // Get all properties (admin only - shows all statuses)
 		adminProperties.GET("/:id", propertyController.GetPropertyByID)           // Get property by ID (admin only)
 		adminProperties.GET("/stats", propertyController.GetPropertyStats)        // Get property statistics (admin only)
+		adminProperties.GET("/duplicate-images", propertyController.GetDuplicateImages) // Get near-identical photos across properties (admin only)
+		adminProperties.GET("/:id/duplicate-images", propertyController.GetDuplicateImages) // Get photos of a property duplicated on other properties (admin only)
 		adminProperties.POST("", propertyController.CreateAdminProperty)          // Create property (admin only)
 		adminProperties.GET("/pending", propertyController.GetPendingProperties)  // Get pending properties only (admin only)
 		adminProperties.GET("/pending-approval", propertyController.GetPendingApproval) // Get pending approval properties (legacy)
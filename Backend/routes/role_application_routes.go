@@ -14,21 +14,25 @@ func SetupRoleApplicationRoutes(group *gin.RouterGroup, enhancedNotificationServ
 	
 	// Role application routes (authenticated users)
 	applications := group.Group("/role-applications")
-	applications.Use(middleware.AuthMiddleware(), middleware.PerformanceMiddleware())
+	applications.Use(middleware.AuthMiddleware())
 	{
 		applications.POST("/worker", applicationController.SubmitWorkerApplication)
 		applications.POST("/broker", applicationController.SubmitBrokerApplication)
 		applications.GET("/me", applicationController.GetUserApplication)
+		applications.GET("/me/documents", applicationController.GetMyApplicationDocuments)
+		applications.PUT("/me/documents/:document_type", applicationController.ReuploadApplicationDocument)
 	}
 	
 	// Admin role application routes
 	adminApplications := group.Group("/admin/role-applications")
-	adminApplications.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware(), middleware.PerformanceMiddleware())
+	adminApplications.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
 	{
 		adminApplications.GET("", applicationController.GetApplicationsWithFilters)
 		adminApplications.GET("/pending", applicationController.GetPendingApplications)
 		adminApplications.GET("/:id", applicationController.GetApplication)
 		adminApplications.PUT("/:id", applicationController.UpdateApplication)
 		adminApplications.DELETE("/:id", applicationController.DeleteApplication)
+		adminApplications.GET("/:id/documents", applicationController.GetApplicationDocuments)
+		adminApplications.PUT("/:id/documents/:document_id", applicationController.ReviewApplicationDocument)
 	}
 }
@@ -13,6 +13,7 @@ import (
 func SetupRoutes(r *gin.Engine) {
 	// Add global middleware
 	r.Use(middleware.RequestIDMiddleware())
+	r.Use(middleware.PerformanceMiddleware())
 	r.Use(middleware.ValidationMiddleware())
 	
 	// Swagger documentation route
@@ -47,6 +48,9 @@ func SetupRoutes(r *gin.Engine) {
 		SetupProjectRoutes(v1)
 		SetupSubscriptionRoutes(v1)
 		SetupWalletRoutes(v1)
+		SetupCouponRoutes(v1)
+		SetupPricingRuleRoutes(v1)
+		SetupSearchRoutes(v1)
 		SetupRazorpayRoutes(v1)
 		// Chat routes will be set up in main.go with WebSocket service
 		// Simple conversation routes will be set up in main.go with WebSocket service
@@ -0,0 +1,77 @@
+package routes_test
+
+import (
+	"testing"
+	"treesindia/config"
+	"treesindia/controllers"
+	"treesindia/database"
+	"treesindia/middleware"
+	"treesindia/routes"
+	"treesindia/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// TestRouteTreeBuilds registers every route group the way main does. Gin panics on conflicting
+// routes, such as two wildcards with different names at the same position, so a clash fails here
+// instead of at server start.
+func TestRouteTreeBuilds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// Controllers open the configured media store, which creates its upload directory
+	t.Setenv("UPLOAD_PATH", t.TempDir())
+	t.Setenv("GOOGLE_MAPS_API_KEY", "test")
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("failed to build the route tree: %v", r)
+		}
+	}()
+
+	// Services keep a handle on the database when they are built; the routes are never served, so
+	// a handle that never connects is enough
+	db, err := gorm.Open(postgres.Open("host=localhost dbname=unused"), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("failed to create database handle: %v", err)
+	}
+	database.SetDB(db)
+
+	mediaStore, err := services.NewLocalMediaStore(t.TempDir(), "http://localhost/media", "test-signing-key")
+	if err != nil {
+		t.Fatalf("failed to create media store: %v", err)
+	}
+
+	r := gin.New()
+	r.GET("/", controllers.AppInfo)
+	routes.SetupRoutes(r)
+
+	routes.SetupWebSocketRoutes(r, controllers.NewWebSocketController(nil))
+	routes.SetupMetricsRoutes(r, &config.AppConfig{})
+	routes.SetupMediaRoutes(r, mediaStore)
+	routes.SetupChatRoutes(r.Group("/api/v1"), nil)
+	routes.SetupSimpleConversationRoutes(r.Group("/api/v1"), nil)
+	routes.SetupSimpleConversationWebSocketRoutes(r.Group("/api/v1"), nil)
+
+	bookingGroup := r.Group("/api/v1")
+	bookingGroup.Use(middleware.NewDynamicConfigMiddleware().BookingSystem())
+	routes.SetupWorkerAssignmentRoutes(bookingGroup, nil, nil, nil)
+	routes.SetupLocationTrackingRoutes(bookingGroup, nil)
+	routes.SetupWorkerReassignmentRoutes(bookingGroup, nil)
+	routes.SetupRoleApplicationRoutes(r.Group("/api/v1"), nil)
+	routes.SetupBookingRoutes(bookingGroup, nil)
+
+	routes.SetupPropertyRoutes(r.Group("/api/v1"), nil)
+	routes.SetupPropertyEnquiryRoutes(r.Group("/api/v1"), nil)
+	routes.SetupSavedSearchRoutes(r.Group("/api/v1"))
+	routes.SetupPropertyPromotionRoutes(r.Group("/api/v1"))
+
+	notificationController := controllers.NewNotificationController(nil, nil)
+	routes.SetupNotificationRoutes(r.Group("/api/v1"), notificationController)
+	routes.SetupAdminNotificationRoutesWithController(r.Group("/api/v1/admin"), notificationController)
+	routes.SetupCallMaskingRoutes(r.Group("/api/v1"))
+	routes.SetupTestRoutes(r.Group("/api/v1"))
+
+	if len(r.Routes()) == 0 {
+		t.Fatal("no routes registered")
+	}
+}
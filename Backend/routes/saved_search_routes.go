@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"treesindia/controllers"
+	"treesindia/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupSavedSearchRoutes sets up saved search and shortlist routes
+func SetupSavedSearchRoutes(router *gin.RouterGroup) {
+	savedSearchController := controllers.NewSavedSearchController()
+	shortlistController := controllers.NewShortlistController()
+
+	savedSearches := router.Group("/user/saved-searches")
+	savedSearches.Use(middleware.AuthMiddleware())
+	{
+		savedSearches.POST("", savedSearchController.CreateSavedSearch)                // Save a property search
+		savedSearches.GET("", savedSearchController.GetSavedSearches)                  // Get saved searches
+		savedSearches.PUT("/:id", savedSearchController.UpdateSavedSearch)             // Update filters or alert settings
+		savedSearches.DELETE("/:id", savedSearchController.DeleteSavedSearch)          // Delete a saved search
+		savedSearches.GET("/:id/results", savedSearchController.GetSavedSearchResults) // Run a saved search
+	}
+
+	shortlist := router.Group("/user/shortlist")
+	shortlist.Use(middleware.AuthMiddleware())
+	{
+		shortlist.POST("", shortlistController.AddToShortlist)                            // Shortlist a property or project
+		shortlist.GET("", shortlistController.GetShortlist)                               // Get shortlisted properties and projects
+		shortlist.DELETE("/:item_type/:item_id", shortlistController.RemoveFromShortlist) // Remove from shortlist
+	}
+}
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"treesindia/controllers"
+	"treesindia/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupSearchRoutes sets up federated search, search analytics and search synonym routes
+func SetupSearchRoutes(router *gin.RouterGroup) {
+	searchController := controllers.NewSearchController()
+
+	// GET /api/v1/search - Search services, properties, projects and vendors
+	router.GET("/search", middleware.OptionalAuthMiddleware(), searchController.FederatedSearch)
+
+	// POST /api/v1/search/:id/click - Record the result opened from a search
+	router.POST("/search/:id/click", middleware.OptionalAuthMiddleware(), searchController.RecordSearchClick)
+
+	// Admin synonym routes
+	adminSearch := router.Group("/admin/search")
+	adminSearch.Use(middleware.AuthMiddleware())
+	adminSearch.Use(middleware.AdminMiddleware())
+	{
+		// GET /api/v1/admin/search/synonyms - Get search synonyms
+		adminSearch.GET("/synonyms", searchController.GetSynonyms)
+
+		// POST /api/v1/admin/search/synonyms - Create search synonym
+		adminSearch.POST("/synonyms", searchController.CreateSynonym)
+
+		// PUT /api/v1/admin/search/synonyms/:id - Update search synonym
+		adminSearch.PUT("/synonyms/:id", searchController.UpdateSynonym)
+
+		// DELETE /api/v1/admin/search/synonyms/:id - Delete search synonym
+		adminSearch.DELETE("/synonyms/:id", searchController.DeleteSynonym)
+
+		// GET /api/v1/admin/search/analytics - Search analytics report
+		adminSearch.GET("/analytics", searchController.GetSearchAnalytics)
+	}
+}
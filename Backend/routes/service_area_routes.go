@@ -32,7 +32,7 @@ func SetupServiceAreaRoutes(router *gin.RouterGroup) {
 	}
 
 	// Admin routes for service-specific service areas
-	adminServiceServiceAreas := router.Group("/admin/services/:service_id/service-areas")
+	adminServiceServiceAreas := router.Group("/admin/services/:id/service-areas")
 	adminServiceServiceAreas.Use(middleware.AuthMiddleware())
 	adminServiceServiceAreas.Use(middleware.AdminMiddleware())
 	{
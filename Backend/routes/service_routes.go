@@ -11,6 +11,7 @@ import (
 func SetupServiceRoutes(router *gin.RouterGroup) {
 	serviceController := controllers.NewServiceController()
 	searchController := controllers.NewSearchController()
+	serviceOptionController := controllers.NewServiceOptionController()
 
 	// Public routes (no authentication required)
 	services := router.Group("/services")
@@ -22,11 +23,12 @@ func SetupServiceRoutes(router *gin.RouterGroup) {
 		services.GET("/categories", serviceController.GetServiceCategories)
 		services.GET("/categories/:id/subcategories", serviceController.GetServiceSubcategories)
 		services.GET("/:id", serviceController.GetServiceByID)
+		services.POST("/:id/price", serviceOptionController.CalculatePrice)
 		
 		// Search routes
 		services.GET("/search/suggestions", searchController.GetSearchSuggestions)
-		services.GET("/search", searchController.SearchServices)
-		services.GET("/search/advanced", searchController.SearchServicesWithFilters)
+		services.GET("/search", middleware.OptionalAuthMiddleware(), searchController.SearchServices)
+		services.GET("/search/advanced", middleware.OptionalAuthMiddleware(), searchController.SearchServicesWithFilters)
 	}
 
 	// Admin routes (authentication and specific admin roles required)
@@ -43,5 +45,15 @@ func SetupServiceRoutes(router *gin.RouterGroup) {
 		adminServices.DELETE("/:id", serviceController.DeleteService)
 		adminServices.DELETE("/:id/images", serviceController.DeleteServiceImage)
 		adminServices.PATCH("/:id/status", serviceController.ToggleStatus)
+
+		// Variants and add-ons
+		adminServices.GET("/:id/variants", serviceOptionController.GetVariants)
+		adminServices.POST("/:id/variants", serviceOptionController.CreateVariant)
+		adminServices.PUT("/:id/variants/:variantId", serviceOptionController.UpdateVariant)
+		adminServices.DELETE("/:id/variants/:variantId", serviceOptionController.DeleteVariant)
+		adminServices.GET("/:id/add-ons", serviceOptionController.GetAddOns)
+		adminServices.POST("/:id/add-ons", serviceOptionController.CreateAddOn)
+		adminServices.PUT("/:id/add-ons/:addOnId", serviceOptionController.UpdateAddOn)
+		adminServices.DELETE("/:id/add-ons/:addOnId", serviceOptionController.DeleteAddOn)
 	}
 }
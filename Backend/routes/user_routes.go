@@ -35,7 +35,22 @@ func SetupUserRoutes(router *gin.RouterGroup) {
 		// POST /api/v1/users/request-delete-otp - Request OTP for account deletion
 		users.POST("/request-delete-otp", userController.RequestDeleteOTP)
 		
-		// DELETE /api/v1/users/account - Delete user account
+		// DELETE /api/v1/users/account - Schedule account deletion
 		users.DELETE("/account", userController.DeleteAccount)
+		
+		// GET /api/v1/users/account/deletion - Get scheduled account deletion
+		users.GET("/account/deletion", userController.GetAccountDeletion)
+		
+		// POST /api/v1/users/account/deletion/cancel - Cancel scheduled account deletion
+		users.POST("/account/deletion/cancel", userController.CancelAccountDeletion)
+		
+		// POST /api/v1/users/data-exports - Request a data export
+		users.POST("/data-exports", userController.RequestDataExport)
+		
+		// GET /api/v1/users/data-exports - Get data exports
+		users.GET("/data-exports", userController.GetDataExports)
+		
+		// GET /api/v1/users/data-exports/:id/download - Get a data export download link
+		users.GET("/data-exports/:id/download", userController.DownloadDataExport)
 	}
 }
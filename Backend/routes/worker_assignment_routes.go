@@ -44,4 +44,12 @@ func SetupWorkerAssignmentRoutes(
 		// POST /api/v1/worker/assignments/:id/complete - Complete assignment
 		workerAssignments.POST("/:id/complete", workerAssignmentController.CompleteAssignment)
 	}
+
+	// Admin assignment routes
+	adminBookings := router.Group("/admin/bookings")
+	adminBookings.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		// POST /api/v1/admin/bookings/:id/force-complete - Complete a service without the completion PIN
+		adminBookings.POST("/:id/force-complete", workerAssignmentController.AdminForceComplete)
+	}
 }
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"treesindia/controllers"
+	"treesindia/middleware"
+	"treesindia/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupWorkerReassignmentRoutes sets up admin routes for worker auto-reassignment
+func SetupWorkerReassignmentRoutes(router *gin.RouterGroup, workerReassignmentService *services.WorkerReassignmentService) {
+	workerReassignmentController := controllers.NewWorkerReassignmentController(workerReassignmentService)
+
+	admin := router.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		// GET /api/v1/admin/bookings/escalated - Get bookings no worker accepted
+		admin.GET("/bookings/escalated", workerReassignmentController.GetEscalatedBookings)
+
+		// GET /api/v1/admin/bookings/:id/offers - Get the workers a booking was offered to
+		admin.GET("/bookings/:id/offers", workerReassignmentController.GetBookingOffers)
+
+		// GET /api/v1/admin/workers/:worker_id/acceptance - Get a worker's acceptance rate
+		admin.GET("/workers/:worker_id/acceptance", workerReassignmentController.GetWorkerAcceptanceStats)
+	}
+}
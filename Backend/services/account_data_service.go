@@ -0,0 +1,387 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// dataExportDownloadExpiry is how long a data export download link works
+const dataExportDownloadExpiry = 15 * time.Minute
+
+// dataExportStaleAfter is how long an export may stay processing before it is retried, e.g. after
+// a restart
+const dataExportStaleAfter = 30 * time.Minute
+
+// AccountDataService exports a user's data and deletes accounts by anonymising their personal data
+// once the grace period ends
+type AccountDataService struct {
+	accountDataRepo    *repositories.AccountDataRepository
+	mediaStore         MediaStore
+	adminConfigService *AdminConfigService
+	stopChan           chan bool
+}
+
+// NewAccountDataService creates a new account data service that keeps export archives in a media store
+func NewAccountDataService(mediaStore MediaStore) *AccountDataService {
+	return &AccountDataService{
+		accountDataRepo:    repositories.NewAccountDataRepository(),
+		mediaStore:         mediaStore,
+		adminConfigService: NewAdminConfigService(),
+		stopChan:           make(chan bool),
+	}
+}
+
+// Start begins the hourly processing of exports and due account deletions
+func (ads *AccountDataService) Start() {
+	logrus.Info("AccountDataService starting...")
+
+	// Run immediately on start to pick up exports interrupted by a restart
+	go ads.runScheduled()
+
+	ticker := time.NewTicker(1 * time.Hour)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				ads.runScheduled()
+			case <-ads.stopChan:
+				ticker.Stop()
+				logrus.Info("AccountDataService stopped")
+				return
+			}
+		}
+	}()
+
+	logrus.Info("AccountDataService started successfully")
+}
+
+// Stop stops the account data service
+func (ads *AccountDataService) Stop() {
+	logrus.Info("Stopping AccountDataService...")
+	ads.stopChan <- true
+}
+
+// runScheduled prepares waiting exports, deletes expired archives and anonymises due accounts
+func (ads *AccountDataService) runScheduled() {
+	exports, err := ads.accountDataRepo.GetPendingExports(time.Now().Add(-dataExportStaleAfter))
+	if err != nil {
+		logrus.Errorf("AccountDataService: failed to get pending exports: %v", err)
+	}
+	for _, export := range exports {
+		ads.processExport(export.ID)
+	}
+
+	ads.expireExports()
+
+	requests, err := ads.accountDataRepo.GetDueDeletions(time.Now())
+	if err != nil {
+		logrus.Errorf("AccountDataService: failed to get due account deletions: %v", err)
+		return
+	}
+	for i := range requests {
+		if err := ads.anonymise(&requests[i]); err != nil {
+			logrus.Errorf("AccountDataService: failed to anonymise user %d: %v", requests[i].UserID, err)
+		}
+	}
+}
+
+// RequestExport queues an export of a user's data. The archive is prepared in the background.
+func (ads *AccountDataService) RequestExport(userID uint) (*models.DataExport, error) {
+	if ads.mediaStore == nil {
+		return nil, errors.New("data exports are not available right now")
+	}
+
+	open, err := ads.accountDataRepo.HasOpenExport(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check data exports: %v", err)
+	}
+	if open {
+		return nil, errors.New("a data export is already being prepared")
+	}
+
+	export := &models.DataExport{
+		UserID: userID,
+		Status: models.DataExportStatusPending,
+	}
+	if err := ads.accountDataRepo.CreateExport(export); err != nil {
+		return nil, fmt.Errorf("failed to create data export: %v", err)
+	}
+
+	go ads.processExport(export.ID)
+	return export, nil
+}
+
+// GetExports gets a user's recent data exports
+func (ads *AccountDataService) GetExports(userID uint) ([]models.DataExport, error) {
+	return ads.accountDataRepo.GetUserExports(userID)
+}
+
+// GetExportDownloadURL returns a short-lived link to a user's completed export archive
+func (ads *AccountDataService) GetExportDownloadURL(userID, exportID uint) (string, time.Time, error) {
+	export, err := ads.accountDataRepo.GetExportByID(exportID)
+	if err != nil || export.UserID != userID {
+		return "", time.Time{}, errors.New("data export not found")
+	}
+	if export.Status != models.DataExportStatusCompleted || export.FileURL == nil {
+		return "", time.Time{}, fmt.Errorf("data export is %s", export.Status)
+	}
+	if ads.mediaStore == nil {
+		return "", time.Time{}, errors.New("data exports are not available right now")
+	}
+
+	url, err := ads.mediaStore.SignedURL(*export.FileURL, dataExportDownloadExpiry)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create download link: %v", err)
+	}
+	return url, time.Now().Add(dataExportDownloadExpiry), nil
+}
+
+// processExport builds and stores the archive of an export, unless another worker already is
+func (ads *AccountDataService) processExport(exportID uint) {
+	claimed, err := ads.accountDataRepo.ClaimExport(exportID, time.Now().Add(-dataExportStaleAfter))
+	if err != nil {
+		logrus.Errorf("AccountDataService: failed to claim export %d: %v", exportID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	export, err := ads.accountDataRepo.GetExportByID(exportID)
+	if err != nil {
+		logrus.Errorf("AccountDataService: failed to get export %d: %v", exportID, err)
+		return
+	}
+
+	url, size, err := ads.buildArchive(export)
+	if err != nil {
+		logrus.Errorf("AccountDataService: export %d for user %d failed: %v", export.ID, export.UserID, err)
+		export.Status = models.DataExportStatusFailed
+		export.Error = "We could not prepare your data. Please try again later."
+		if updateErr := ads.accountDataRepo.UpdateExport(export); updateErr != nil {
+			logrus.Errorf("AccountDataService: failed to update export %d: %v", export.ID, updateErr)
+		}
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(ads.adminConfigService.GetDataExportRetentionHours()) * time.Hour)
+	export.Status = models.DataExportStatusCompleted
+	export.FileURL = &url
+	export.FileSize = size
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	if err := ads.accountDataRepo.UpdateExport(export); err != nil {
+		logrus.Errorf("AccountDataService: failed to update export %d: %v", export.ID, err)
+		return
+	}
+
+	logrus.Infof("AccountDataService: export %d for user %d ready (%d bytes)", export.ID, export.UserID, size)
+}
+
+// buildArchive writes a user's data as one JSON file per section of a zip archive and stores it
+func (ads *AccountDataService) buildArchive(export *models.DataExport) (string, int64, error) {
+	data, err := ads.accountDataRepo.GetUserDataArchive(export.UserID)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to collect user data: %v", err)
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"profile.json", data.Profile},
+		{"addresses.json", data.Addresses},
+		{"bookings.json", data.Bookings},
+		{"payments.json", data.Payments},
+		{"wallet_transactions.json", data.WalletTransactions},
+		{"chat_messages.json", data.ChatMessages},
+		{"conversation_messages.json", data.ConversationMessages},
+		{"properties.json", data.Properties},
+	}
+	for _, file := range files {
+		content, err := json.MarshalIndent(file.content, "", "  ")
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to encode %s: %v", file.name, err)
+		}
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to add %s: %v", file.name, err)
+		}
+		if _, err := w.Write(content); err != nil {
+			return "", 0, fmt.Errorf("failed to write %s: %v", file.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return "", 0, fmt.Errorf("failed to finish archive: %v", err)
+	}
+
+	token, err := randomHex(16)
+	if err != nil {
+		return "", 0, err
+	}
+	key := fmt.Sprintf("data-exports/%d/%s.zip", export.UserID, token)
+	size := int64(buf.Len())
+
+	url, err := ads.mediaStore.Put(&buf, key, "application/zip")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to store archive: %v", err)
+	}
+	return url, size, nil
+}
+
+// expireExports deletes archives past their retention period
+func (ads *AccountDataService) expireExports() {
+	exports, err := ads.accountDataRepo.GetExpiredExports(time.Now())
+	if err != nil {
+		logrus.Errorf("AccountDataService: failed to get expired exports: %v", err)
+		return
+	}
+
+	for i := range exports {
+		if err := ads.expireExport(&exports[i]); err != nil {
+			logrus.Errorf("AccountDataService: failed to expire export %d: %v", exports[i].ID, err)
+		}
+	}
+}
+
+// expireExport deletes an export's archive and marks it expired
+func (ads *AccountDataService) expireExport(export *models.DataExport) error {
+	if export.FileURL != nil && ads.mediaStore != nil {
+		if err := ads.mediaStore.Delete(*export.FileURL); err != nil {
+			return err
+		}
+	}
+	export.Status = models.DataExportStatusExpired
+	export.FileURL = nil
+	return ads.accountDataRepo.UpdateExport(export)
+}
+
+// ScheduleDeletion schedules the anonymisation of a user's account after the grace period
+func (ads *AccountDataService) ScheduleDeletion(userID uint, reason string) (*models.AccountDeletionRequest, error) {
+	if existing, err := ads.accountDataRepo.GetScheduledDeletion(userID); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check account deletion: %v", err)
+	}
+
+	activeBookings, err := ads.accountDataRepo.CountActiveBookings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bookings: %v", err)
+	}
+	if activeBookings > 0 {
+		return nil, errors.New("please complete or cancel your active bookings before deleting your account")
+	}
+
+	graceDays := ads.adminConfigService.GetAccountDeletionGraceDays()
+	request := &models.AccountDeletionRequest{
+		UserID:       userID,
+		Status:       models.AccountDeletionStatusScheduled,
+		Reason:       reason,
+		ScheduledFor: time.Now().AddDate(0, 0, graceDays),
+	}
+	if err := ads.accountDataRepo.CreateDeletionRequest(request); err != nil {
+		return nil, fmt.Errorf("failed to schedule account deletion: %v", err)
+	}
+
+	logrus.Infof("AccountDataService: user %d scheduled account deletion for %s", userID, request.ScheduledFor.Format(time.RFC3339))
+
+	if graceDays == 0 {
+		if err := ads.anonymise(request); err != nil {
+			return nil, err
+		}
+	}
+	return request, nil
+}
+
+// GetScheduledDeletion gets a user's scheduled account deletion, or nil if there is none
+func (ads *AccountDataService) GetScheduledDeletion(userID uint) (*models.AccountDeletionRequest, error) {
+	request, err := ads.accountDataRepo.GetScheduledDeletion(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+// CancelDeletion cancels a user's scheduled account deletion during the grace period
+func (ads *AccountDataService) CancelDeletion(userID uint) (*models.AccountDeletionRequest, error) {
+	request, err := ads.accountDataRepo.GetScheduledDeletion(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("no account deletion is scheduled")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	request.Status = models.AccountDeletionStatusCancelled
+	request.CancelledAt = &now
+	if err := ads.accountDataRepo.UpdateDeletionRequest(request); err != nil {
+		return nil, fmt.Errorf("failed to cancel account deletion: %v", err)
+	}
+
+	logrus.Infof("AccountDataService: user %d cancelled account deletion", userID)
+	return request, nil
+}
+
+// anonymise removes the personal data of a user whose deletion is due. Users who booked again
+// during the grace period are retried on a later run.
+func (ads *AccountDataService) anonymise(request *models.AccountDeletionRequest) error {
+	activeBookings, err := ads.accountDataRepo.CountActiveBookings(request.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to check bookings: %v", err)
+	}
+	if activeBookings > 0 {
+		logrus.Warnf("AccountDataService: user %d has %d active bookings, postponing anonymisation", request.UserID, activeBookings)
+		return nil
+	}
+
+	// Archives hold the personal data being removed
+	exports, err := ads.accountDataRepo.GetUserExports(request.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get data exports: %v", err)
+	}
+	for i := range exports {
+		if exports[i].Status == models.DataExportStatusCompleted {
+			if err := ads.expireExport(&exports[i]); err != nil {
+				return fmt.Errorf("failed to delete data export %d: %v", exports[i].ID, err)
+			}
+		}
+	}
+
+	pseudonym, err := randomHex(12)
+	if err != nil {
+		return err
+	}
+	if err := ads.accountDataRepo.AnonymiseUser(request, pseudonym); err != nil {
+		return fmt.Errorf("failed to anonymise user: %v", err)
+	}
+
+	logrus.Infof("AccountDataService: anonymised user %d", request.UserID)
+	return nil
+}
+
+// randomHex returns n random bytes as a hex string
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -240,6 +240,275 @@ func (s *AdminConfigService) GetRequirePropertyApproval() bool {
 	return require
 }
 
+// GetQuoteValidityDays retrieves the number of days a quote stays valid
+func (s *AdminConfigService) GetQuoteValidityDays() int {
+	days, err := s.GetIntValue("quote_validity_days")
+	if err != nil {
+		logrus.Warnf("Failed to get quote validity days, using 7: %v", err)
+		return 7
+	}
+	return days
+}
+
+// GetQuoteExpiryWarningHours retrieves how long before expiry customers are warned
+func (s *AdminConfigService) GetQuoteExpiryWarningHours() int {
+	hours, err := s.GetIntValue("quote_expiry_warning_hours")
+	if err != nil {
+		logrus.Warnf("Failed to get quote expiry warning hours, using 24: %v", err)
+		return 24
+	}
+	return hours
+}
+
+// GetInquiryFeeExpiryPolicy retrieves what happens to a held inquiry fee when its quote expires
+func (s *AdminConfigService) GetInquiryFeeExpiryPolicy() string {
+	policy, err := s.repo.GetValueByKey("inquiry_fee_expiry_policy")
+	if err != nil || policy == "" {
+		logrus.Warnf("Failed to get inquiry fee expiry policy, using wallet_credit: %v", err)
+		return "wallet_credit"
+	}
+	return policy
+}
+
+// GetPaymentSegmentOverdueGraceHours retrieves the hours a due segment waits before it is marked overdue
+func (s *AdminConfigService) GetPaymentSegmentOverdueGraceHours() int {
+	hours, err := s.GetIntValue("payment_segment_overdue_grace_hours")
+	if err != nil {
+		logrus.Warnf("Failed to get payment segment overdue grace hours, using 48: %v", err)
+		return 48
+	}
+	return hours
+}
+
+// GetPaymentSegmentReminderIntervalHours retrieves the minimum hours between segment reminders
+func (s *AdminConfigService) GetPaymentSegmentReminderIntervalHours() int {
+	hours, err := s.GetIntValue("payment_segment_reminder_interval_hours")
+	if err != nil {
+		logrus.Warnf("Failed to get payment segment reminder interval hours, using 24: %v", err)
+		return 24
+	}
+	return hours
+}
+
+// GetPaymentSegmentMaxReminders retrieves the maximum reminders sent per segment
+func (s *AdminConfigService) GetPaymentSegmentMaxReminders() int {
+	count, err := s.GetIntValue("payment_segment_max_reminders")
+	if err != nil {
+		logrus.Warnf("Failed to get payment segment max reminders, using 5: %v", err)
+		return 5
+	}
+	return count
+}
+
+// GetReferralReferrerReward retrieves the wallet credit for a referrer
+func (s *AdminConfigService) GetReferralReferrerReward() float64 {
+	amount, err := s.GetFloatValue("referral_referrer_reward")
+	if err != nil {
+		logrus.Warnf("Failed to get referral referrer reward, using 0: %v", err)
+		return 0
+	}
+	return amount
+}
+
+// GetReferralRefereeReward retrieves the wallet credit for a referred user
+func (s *AdminConfigService) GetReferralRefereeReward() float64 {
+	amount, err := s.GetFloatValue("referral_referee_reward")
+	if err != nil {
+		logrus.Warnf("Failed to get referral referee reward, using 0: %v", err)
+		return 0
+	}
+	return amount
+}
+
+// GetDashboardAlertThresholds retrieves the thresholds used by the dashboard alert feeds
+func (s *AdminConfigService) GetDashboardAlertThresholds() models.DashboardAlertThresholds {
+	return models.DashboardAlertThresholds{
+		UnassignedBookingHours: s.getIntValueOrDefault("dashboard_unassigned_booking_hours", 2),
+		WithdrawalPendingHours: s.getIntValueOrDefault("dashboard_withdrawal_pending_hours", 24),
+		PropertyApprovalHours:  s.getIntValueOrDefault("dashboard_property_approval_hours", 24),
+		RoleApplicationHours:   s.getIntValueOrDefault("dashboard_role_application_hours", 48),
+		InquiryQuoteHours:      s.getIntValueOrDefault("dashboard_inquiry_quote_hours", 24),
+		FailedPushWindowHours:  s.getIntValueOrDefault("dashboard_failed_push_window_hours", 24),
+		FailedPushThreshold:    s.getIntValueOrDefault("dashboard_failed_push_threshold", 10),
+		UnansweredChatHours:    s.getIntValueOrDefault("dashboard_unanswered_chat_hours", 4),
+	}
+}
+
+// GetSearchFuzzyThreshold retrieves the trigram similarity needed for a fuzzy search match
+func (s *AdminConfigService) GetSearchFuzzyThreshold() float64 {
+	threshold, err := s.GetFloatValue("search_fuzzy_threshold")
+	if err != nil || threshold <= 0 || threshold > 1 {
+		logrus.Warnf("Failed to get search fuzzy threshold, using 0.4: %v", err)
+		return 0.4
+	}
+	return threshold
+}
+
+// GetSearchAttributionWindowHours retrieves how long after a click a booking is credited to the search
+func (s *AdminConfigService) GetSearchAttributionWindowHours() int {
+	return s.getIntValueOrDefault("search_attribution_window_hours", 24)
+}
+
+// GetSearchSuggestionMinUsers retrieves how many different users must search a query before it is suggested publicly
+func (s *AdminConfigService) GetSearchSuggestionMinUsers() int {
+	return s.getIntValueOrDefault("search_suggestion_min_users", 5)
+}
+
+// GetDuplicateImageMaxDistance retrieves the perceptual hash distance under which photos count as duplicates
+func (s *AdminConfigService) GetDuplicateImageMaxDistance() int {
+	return s.getIntValueOrDefault("duplicate_image_max_distance", 6)
+}
+
+// GetMediaGCDryRun retrieves whether the scheduled media garbage collector only reports orphans
+func (s *AdminConfigService) GetMediaGCDryRun() bool {
+	dryRun, err := s.GetBoolValue("media_gc_dry_run")
+	if err != nil {
+		logrus.Warnf("Failed to get media GC dry run setting, using true: %v", err)
+		return true
+	}
+	return dryRun
+}
+
+// GetMediaGCGraceHours retrieves how long new uploads are protected from the media garbage collector
+func (s *AdminConfigService) GetMediaGCGraceHours() int {
+	return s.getIntValueOrDefault("media_gc_grace_hours", 24)
+}
+
+// GetChatMediaRetentionDays retrieves how long chat attachments are kept
+func (s *AdminConfigService) GetChatMediaRetentionDays() int {
+	return s.getIntValueOrDefault("chat_media_retention_days", 30)
+}
+
+// GetSurgePricingEnabled retrieves whether pricing rules are applied to fixed price bookings
+func (s *AdminConfigService) GetSurgePricingEnabled() bool {
+	enabled, err := s.GetBoolValue("surge_pricing_enabled")
+	if err != nil {
+		logrus.Warnf("Failed to get surge pricing setting, using true: %v", err)
+		return true
+	}
+	return enabled
+}
+
+// GetSurgeMaxMultiplier retrieves the most a pricing rule may raise a price, as a multiple of the normal price
+func (s *AdminConfigService) GetSurgeMaxMultiplier() float64 {
+	multiplier, err := s.GetFloatValue("surge_max_multiplier")
+	if err != nil || multiplier < 1 {
+		logrus.Warnf("Failed to get surge max multiplier, using 2.0: %v", err)
+		return 2.0
+	}
+	return multiplier
+}
+
+// GetAccountDeletionGraceDays retrieves how long a user can cancel an account deletion
+func (s *AdminConfigService) GetAccountDeletionGraceDays() int {
+	return s.getIntValueOrDefault("account_deletion_grace_days", 30)
+}
+
+// GetDataExportRetentionHours retrieves how long a data export archive can be downloaded
+func (s *AdminConfigService) GetDataExportRetentionHours() int {
+	return s.getIntValueOrDefault("data_export_retention_hours", 72)
+}
+
+// GetWorkerArrivalRadiusMeters retrieves how close a tracked worker must be to count as arrived
+func (s *AdminConfigService) GetWorkerArrivalRadiusMeters() int {
+	return s.getIntValueOrDefault("worker_arrival_radius_meters", 150)
+}
+
+// GetWorkerETARefreshSeconds retrieves how often a tracked worker's ETA is recalculated
+func (s *AdminConfigService) GetWorkerETARefreshSeconds() int {
+	return s.getIntValueOrDefault("worker_eta_refresh_seconds", 60)
+}
+
+// GetServicePINMaxAttempts retrieves how many incorrect service PINs lock PIN entry
+func (s *AdminConfigService) GetServicePINMaxAttempts() int {
+	return s.getIntValueOrDefault("service_pin_max_attempts", 5)
+}
+
+// GetServicePINLockMinutes retrieves how long PIN entry stays locked
+func (s *AdminConfigService) GetServicePINLockMinutes() int {
+	return s.getIntValueOrDefault("service_pin_lock_minutes", 30)
+}
+
+// GetServiceAutoCompleteHours retrieves how long a started service runs before it is completed automatically
+func (s *AdminConfigService) GetServiceAutoCompleteHours() int {
+	return s.getIntValueOrDefault("service_auto_complete_hours", 24)
+}
+
+// GetAssignmentAcceptanceSLAMinutes retrieves how long a worker has to accept an assigned booking
+func (s *AdminConfigService) GetAssignmentAcceptanceSLAMinutes() int {
+	return s.getIntValueOrDefault("assignment_acceptance_sla_minutes", 30)
+}
+
+// GetAssignmentMaxAutoOffers retrieves how many failed offers escalate a booking to admins
+func (s *AdminConfigService) GetAssignmentMaxAutoOffers() int {
+	return s.getIntValueOrDefault("assignment_max_auto_offers", 3)
+}
+
+// GetPoliceVerificationValidityMonths retrieves how long a verified police verification stays valid
+func (s *AdminConfigService) GetPoliceVerificationValidityMonths() int {
+	return s.getIntValueOrDefault("police_verification_validity_months", 12)
+}
+
+// GetSiteVisitReminderHours retrieves how long before a site visit its reminder is sent
+func (s *AdminConfigService) GetSiteVisitReminderHours() int {
+	return s.getIntValueOrDefault("site_visit_reminder_hours", 24)
+}
+
+// GetPropertyRenewalWindowDays retrieves how many days before expiry an owner can renew a listing
+func (s *AdminConfigService) GetPropertyRenewalWindowDays() int {
+	return s.getIntValueOrDefault("property_renewal_window_days", 7)
+}
+
+// GetPropertyBoostPrice retrieves the price of a listing boost
+func (s *AdminConfigService) GetPropertyBoostPrice() float64 {
+	price, err := s.GetFloatValue("property_boost_price")
+	if err != nil {
+		logrus.Warnf("Failed to get property boost price, using 199: %v", err)
+		return 199
+	}
+	return price
+}
+
+// GetPropertyBoostDays retrieves how many days a listing boost lasts
+func (s *AdminConfigService) GetPropertyBoostDays() int {
+	return s.getIntValueOrDefault("property_boost_days", 7)
+}
+
+// GetPropertyBoostPoints retrieves the priority a boost adds to a listing when bought
+func (s *AdminConfigService) GetPropertyBoostPoints() int {
+	return s.getIntValueOrDefault("property_boost_points", 50)
+}
+
+// GetPropertyFeaturedPrice retrieves the price of a featured slot
+func (s *AdminConfigService) GetPropertyFeaturedPrice() float64 {
+	price, err := s.GetFloatValue("property_featured_price")
+	if err != nil {
+		logrus.Warnf("Failed to get property featured price, using 499: %v", err)
+		return 499
+	}
+	return price
+}
+
+// GetPropertyFeaturedDays retrieves how many days a featured slot lasts
+func (s *AdminConfigService) GetPropertyFeaturedDays() int {
+	return s.getIntValueOrDefault("property_featured_days", 7)
+}
+
+// GetPropertyFeaturedSlotsPerCity retrieves how many listings can be featured at once in a city
+func (s *AdminConfigService) GetPropertyFeaturedSlotsPerCity() int {
+	return s.getIntValueOrDefault("property_featured_slots_per_city", 5)
+}
+
+// getIntValueOrDefault retrieves an integer config, falling back to the default when missing
+func (s *AdminConfigService) getIntValueOrDefault(key string, defaultValue int) int {
+	value, err := s.GetIntValue(key)
+	if err != nil {
+		logrus.Warnf("Failed to get %s, using %d: %v", key, defaultValue, err)
+		return defaultValue
+	}
+	return value
+}
+
 // DynamicConfigChecker provides dynamic configuration checking capabilities
 type DynamicConfigChecker struct {
 	service *AdminConfigService
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"treesindia/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AssignmentExpiryService completes services that workers started but forgot to complete
+type AssignmentExpiryService struct {
+	workerAssignmentService     *WorkerAssignmentService
+	enhancedNotificationService *EnhancedNotificationService
+	stopChan                    chan bool
+}
+
+func NewAssignmentExpiryService(workerAssignmentService *WorkerAssignmentService, enhancedNotificationService *EnhancedNotificationService) *AssignmentExpiryService {
+	return &AssignmentExpiryService{
+		workerAssignmentService:     workerAssignmentService,
+		enhancedNotificationService: enhancedNotificationService,
+		stopChan:                    make(chan bool),
+	}
+}
+
+// Start begins the periodic check for expired assignments
+func (aes *AssignmentExpiryService) Start() {
+	logrus.Info("AssignmentExpiryService starting...")
+
+	// Run immediately on start
+	go aes.completeExpired()
+
+	// Then run every hour
+	ticker := time.NewTicker(1 * time.Hour)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				aes.completeExpired()
+			case <-aes.stopChan:
+				ticker.Stop()
+				logrus.Info("AssignmentExpiryService stopped")
+				return
+			}
+		}
+	}()
+
+	logrus.Info("AssignmentExpiryService started successfully")
+}
+
+// Stop stops the assignment expiry service
+func (aes *AssignmentExpiryService) Stop() {
+	logrus.Info("Stopping AssignmentExpiryService...")
+	aes.stopChan <- true
+}
+
+// completeExpired completes expired assignments and tells the customer and worker
+func (aes *AssignmentExpiryService) completeExpired() {
+	completed, err := aes.workerAssignmentService.CompleteExpiredAssignments()
+	if err != nil {
+		logrus.Errorf("AssignmentExpiryService: %v", err)
+		return
+	}
+
+	for i := range completed {
+		aes.sendAutoCompleted(&completed[i])
+	}
+
+	if len(completed) > 0 {
+		logrus.Infof("AssignmentExpiryService: %d assignments completed automatically", len(completed))
+	}
+}
+
+// sendAutoCompleted notifies the customer and the worker that a service was completed automatically
+func (aes *AssignmentExpiryService) sendAutoCompleted(assignment *models.WorkerAssignment) {
+	go func() {
+		if aes.enhancedNotificationService == nil {
+			logrus.Warn("Notification service not available, skipping auto-completion notification")
+			return
+		}
+
+		data := map[string]string{
+			"type":           "booking",
+			"bookingId":      fmt.Sprintf("%d", assignment.BookingID),
+			"assignmentId":   fmt.Sprintf("%d", assignment.ID),
+			"completionType": string(models.CompletionTypeTimeExpired),
+		}
+
+		requests := []*NotificationRequest{
+			{
+				UserID:   assignment.Booking.UserID,
+				Type:     models.NotificationTypeBooking,
+				Title:    "Service Completed",
+				Body:     fmt.Sprintf("Your booking %s was marked as completed. Contact support if the work is not finished.", assignment.Booking.BookingReference),
+				Data:     data,
+				Priority: "high",
+			},
+			{
+				UserID:   assignment.WorkerID,
+				Type:     models.NotificationTypeBooking,
+				Title:    "Service Completed Automatically",
+				Body:     fmt.Sprintf("Booking %s was completed automatically because it was never marked as complete.", assignment.Booking.BookingReference),
+				Data:     data,
+				Priority: "normal",
+			},
+		}
+
+		for _, req := range requests {
+			if _, err := aes.enhancedNotificationService.SendNotification(req); err != nil {
+				logrus.Errorf("Failed to send auto-completion notification for assignment %d to user %d: %v", assignment.ID, req.UserID, err)
+			}
+		}
+	}()
+}
@@ -17,6 +17,8 @@ type AvailabilityService struct {
 	bookingRepo          *repositories.BookingRepository
 	workerAssignmentRepo *repositories.WorkerAssignmentRepository
 	userRepo             *repositories.UserRepository
+	pricingService       *PricingService
+	pricingRuleService   *PricingRuleService
 }
 
 func NewAvailabilityService() *AvailabilityService {
@@ -26,6 +28,8 @@ func NewAvailabilityService() *AvailabilityService {
 		bookingRepo:          repositories.NewBookingRepository(),
 		workerAssignmentRepo: repositories.NewWorkerAssignmentRepository(),
 		userRepo:             repositories.NewUserRepository(),
+		pricingService:       NewPricingService(),
+		pricingRuleService:   NewPricingRuleService(),
 	}
 }
 
@@ -34,6 +38,13 @@ type AvailableSlot struct {
 	Time             string `json:"time"`
 	AvailableWorkers int    `json:"available_workers"`
 	IsAvailable      bool   `json:"is_available"`
+
+	// Price of the chosen service options in this slot, including surge pricing (fixed price services)
+	Price       *float64                   `json:"price,omitempty"`
+	PricingRule *models.AppliedPricingRule `json:"pricing_rule,omitempty"`
+
+	utilisation    float64                       // Percentage of workers busy in the slot
+	priceBreakdown *models.ServicePriceBreakdown // Breakdown behind Price
 }
 
 // AvailabilityResponse represents the response for available slots
@@ -57,7 +68,103 @@ func (as *AvailabilityService) GetAvailableSlotsWithDuration(serviceID uint, dat
 		return nil, fmt.Errorf("service not found: %v", err)
 	}
 
-	// 2. Get working hours from admin config
+	// 2. Calculate service duration from service or custom duration
+	var serviceDurationMinutes int
+	if customDuration != nil && *customDuration != "" {
+		// Use custom duration (e.g., from quote)
+		duration, err := utils.ParseDuration(*customDuration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom duration: %v", err)
+		}
+		serviceDurationMinutes = duration.ToMinutes()
+	} else if service.Duration != nil && *service.Duration != "" {
+		// Use service duration
+		duration, err := utils.ParseDuration(*service.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service duration: %v", err)
+		}
+		serviceDurationMinutes = duration.ToMinutes()
+	} else {
+		// If no duration specified, use a reasonable default
+		serviceDurationMinutes = defaultServiceDurationMinutes
+	}
+
+	return as.GetAvailableSlotsForDuration(serviceID, date, location, serviceDurationMinutes)
+}
+
+// GetAvailableSlotsForSelection calculates available time slots for a service booked with a variant,
+// quantity and add-ons, which together determine how long the job takes, and prices each slot
+func (as *AvailabilityService) GetAvailableSlotsForSelection(serviceID uint, date string, where models.PricingLocation, selection *models.ServiceSelection) (*AvailabilityResponse, error) {
+	service, err := as.serviceRepo.GetByID(serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("service not found: %v", err)
+	}
+
+	breakdown, err := as.pricingService.Calculate(service, selection)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.GetAvailableSlotsForBreakdown(service, date, where, breakdown)
+}
+
+// GetAvailableSlotsForBreakdown calculates available time slots for a priced service selection.
+// Fixed price slots carry their price after surge pricing rules.
+func (as *AvailabilityService) GetAvailableSlotsForBreakdown(service *models.Service, date string, where models.PricingLocation, breakdown *models.ServicePriceBreakdown) (*AvailabilityResponse, error) {
+	location := ""
+	if where.City != "" && where.State != "" {
+		location = where.City + ", " + where.State
+	}
+
+	response, err := as.GetAvailableSlotsForDuration(service.ID, date, location, breakdown.DurationMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	if breakdown.PriceType == "fixed" {
+		if err := as.priceSlots(service, date, where, breakdown, response.AvailableSlots); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// priceSlots sets the price of each slot, applying the pricing rule that matches it
+func (as *AvailabilityService) priceSlots(service *models.Service, date string, where models.PricingLocation, breakdown *models.ServicePriceBreakdown, slots []AvailableSlot) error {
+	istLocation, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		istLocation = time.FixedZone("IST", 5*60*60+30*60)
+	}
+
+	parsedDate, err := time.ParseInLocation("2006-01-02", date, istLocation)
+	if err != nil {
+		return fmt.Errorf("invalid date format: %v", err)
+	}
+
+	pricer, err := as.pricingRuleService.NewSurgePricer(service, parsedDate, where)
+	if err != nil {
+		return err
+	}
+
+	for i := range slots {
+		slotTime, err := time.ParseInLocation("2006-01-02 15:04", date+" "+slots[i].Time, istLocation)
+		if err != nil {
+			continue
+		}
+
+		priced := pricer.Apply(breakdown, slotTime, slots[i].utilisation)
+		price := priced.Total
+		slots[i].Price = &price
+		slots[i].PricingRule = priced.PricingRule
+		slots[i].priceBreakdown = priced
+	}
+	return nil
+}
+
+// GetAvailableSlotsForDuration calculates available time slots for a job of the given length
+func (as *AvailabilityService) GetAvailableSlotsForDuration(serviceID uint, date string, location string, serviceDurationMinutes int) (*AvailabilityResponse, error) {
+	// 1. Get working hours from admin config
 	startTimeConfig, err := as.adminConfigRepo.GetByKey("working_hours_start")
 	if err != nil {
 		startTimeConfig = &models.AdminConfig{Value: "09:00"} // Default 9 AM
@@ -68,7 +175,7 @@ func (as *AvailabilityService) GetAvailableSlotsWithDuration(serviceID uint, dat
 		endTimeConfig = &models.AdminConfig{Value: "22:00"} // Default 10 PM
 	}
 
-	// 3. Get buffer time
+	// 2. Get buffer time
 	bufferTimeConfig, err := as.adminConfigRepo.GetByKey("booking_buffer_time_minutes")
 	if err != nil {
 		bufferTimeConfig = &models.AdminConfig{Value: "30"} // Default 30 minutes
@@ -79,40 +186,19 @@ func (as *AvailabilityService) GetAvailableSlotsWithDuration(serviceID uint, dat
 		bufferTimeMinutes = 30
 	}
 
-	// 4. Calculate service duration from service or custom duration
-	var serviceDurationMinutes int
-	if customDuration != nil && *customDuration != "" {
-		// Use custom duration (e.g., from quote)
-		duration, err := utils.ParseDuration(*customDuration)
-		if err != nil {
-			return nil, fmt.Errorf("invalid custom duration: %v", err)
-		}
-		serviceDurationMinutes = duration.ToMinutes()
-	} else if service.Duration != nil && *service.Duration != "" {
-		// Use service duration
-		duration, err := utils.ParseDuration(*service.Duration)
-		if err != nil {
-			return nil, fmt.Errorf("invalid service duration: %v", err)
-		}
-		serviceDurationMinutes = duration.ToMinutes()
-	} else {
-		// If no duration specified, use a reasonable default
-		serviceDurationMinutes = 120 // Default 2 hours
-	}
-
-	// 5. Get all worker assignments for the date (single optimized query)
+	// 3. Get all worker assignments for the date (single optimized query)
 	workerAssignments, err := as.getWorkerAssignmentsForDate(date)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get worker assignments: %v", err)
 	}
 
-	// 6. Get total active Trees India workers
+	// 4. Get total active Trees India workers
 	totalWorkers, err := as.getTotalActiveWorkers()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total workers: %v", err)
 	}
 
-	logrus.Infof("GetAvailableSlotsWithDuration: totalWorkers=%d for serviceID=%d, date=%s", totalWorkers, serviceID, date)
+	logrus.Infof("GetAvailableSlotsForDuration: totalWorkers=%d for serviceID=%d, date=%s", totalWorkers, serviceID, date)
 
 	// If no Trees India workers exist, return empty slots (all unavailable)
 	if totalWorkers == 0 {
@@ -128,7 +214,7 @@ func (as *AvailabilityService) GetAvailableSlotsWithDuration(serviceID uint, dat
 		}, nil
 	}
 
-	// 7. Calculate available slots
+	// 5. Calculate available slots
 	availableSlots := as.calculateAvailableSlots(
 		date,
 		startTimeConfig.Value,
@@ -141,7 +227,7 @@ func (as *AvailabilityService) GetAvailableSlotsWithDuration(serviceID uint, dat
 		location,
 	)
 
-	// 8. Build response
+	// 6. Build response
 	response := &AvailabilityResponse{
 		WorkingHours: map[string]string{
 			"start": startTimeConfig.Value,
@@ -264,6 +350,9 @@ func (as *AvailabilityService) calculateAvailableSlots(
 			AvailableWorkers: availableWorkers,
 			IsAvailable:      isAvailable,
 		}
+		if totalWorkers > 0 {
+			slot.utilisation = float64(totalWorkers-availableWorkers) * 100 / float64(totalWorkers)
+		}
 
 		slots = append(slots, slot)
 		currentTime = currentTime.Add(slotInterval)
@@ -10,13 +10,13 @@ import (
 
 type BannerService struct {
 	bannerRepo *repositories.BannerRepository
-	cloudinaryService *CloudinaryService
+	mediaStore MediaStore
 }
 
-func NewBannerService(bannerRepo *repositories.BannerRepository, cloudinaryService *CloudinaryService) *BannerService {
+func NewBannerService(bannerRepo *repositories.BannerRepository, mediaStore MediaStore) *BannerService {
 	return &BannerService{
 		bannerRepo: bannerRepo,
-		cloudinaryService: cloudinaryService,
+		mediaStore: mediaStore,
 	}
 }
 
@@ -51,21 +51,21 @@ func (s *BannerService) UpdateBannerImage(image *models.BannerImage) error {
 
 // UpdateBannerImageWithFile updates an existing banner image with file upload
 func (s *BannerService) UpdateBannerImageWithFile(id uint, image *models.BannerImage, file *multipart.FileHeader) error {
-	// Get the existing image to get the old Cloudinary URL
+	// Get the existing image to get the old media URL
 	existingImage, err := s.bannerRepo.GetBannerImageByID(id)
 	if err != nil {
 		return err
 	}
 	
-	// Check if Cloudinary service is available
-	if s.cloudinaryService == nil {
-		return errors.New("cloudinary service is not available")
+	// Check if media store is available
+	if s.mediaStore == nil {
+		return errors.New("media store is not available")
 	}
 	
-	// Upload new image to Cloudinary
-	imageURL, err := s.cloudinaryService.UploadImage(file, "banner-images")
+	// Upload new image to media storage
+	imageURL, err := s.mediaStore.UploadImage(file, "banner-images")
 	if err != nil {
-		return fmt.Errorf("failed to upload image to Cloudinary: %w", err)
+		return fmt.Errorf("failed to upload image to media storage: %w", err)
 	}
 	
 	// Set the new image URL
@@ -77,12 +77,12 @@ func (s *BannerService) UpdateBannerImageWithFile(id uint, image *models.BannerI
 		return err
 	}
 	
-	// Delete old image from Cloudinary if it exists
+	// Delete old image from media storage if it exists
 	if existingImage.Image != "" {
-		publicID := s.cloudinaryService.GetPublicIDFromURL(existingImage.Image)
+		publicID := s.mediaStore.KeyFromURL(existingImage.Image)
 		if publicID != "" {
-			// Try to delete from Cloudinary, but don't fail if it doesn't work
-			if deleteErr := s.cloudinaryService.DeleteImage(publicID); deleteErr != nil {
+			// Try to delete from media storage, but don't fail if it doesn't work
+			if deleteErr := s.mediaStore.Delete(existingImage.Image); deleteErr != nil {
 				// Log error silently
 			}
 		}
@@ -93,7 +93,7 @@ func (s *BannerService) UpdateBannerImageWithFile(id uint, image *models.BannerI
 
 // DeleteBannerImage deletes a banner image
 func (s *BannerService) DeleteBannerImage(id uint) error {
-	// Get the image first to get the Cloudinary URL
+	// Get the image first to get the media URL
 	image, err := s.bannerRepo.GetBannerImageByID(id)
 	if err != nil {
 		return err
@@ -105,13 +105,13 @@ func (s *BannerService) DeleteBannerImage(id uint) error {
 		return err
 	}
 	
-	// Delete from Cloudinary if service is available and URL is from Cloudinary
-	if s.cloudinaryService != nil && image.Image != "" {
-		// Extract public ID from Cloudinary URL
-		publicID := s.cloudinaryService.GetPublicIDFromURL(image.Image)
+	// Delete from media storage if it is available
+	if s.mediaStore != nil && image.Image != "" {
+		// Extract public ID from media storage URL
+		publicID := s.mediaStore.KeyFromURL(image.Image)
 		if publicID != "" {
-			// Try to delete from Cloudinary, but don't fail if it doesn't work
-			if deleteErr := s.cloudinaryService.DeleteImage(publicID); deleteErr != nil {
+			// Try to delete from media storage, but don't fail if it doesn't work
+			if deleteErr := s.mediaStore.Delete(image.Image); deleteErr != nil {
 				// Log error silently
 			}
 		}
@@ -134,15 +134,15 @@ func (s *BannerService) CreateBannerImageWithFile(image *models.BannerImage, fil
 		return errors.New("title is required")
 	}
 	
-	// Check if Cloudinary service is available
-	if s.cloudinaryService == nil {
-		return errors.New("cloudinary service is not available")
+	// Check if media store is available
+	if s.mediaStore == nil {
+		return errors.New("media store is not available")
 	}
 	
-	// Upload image to Cloudinary
-	imageURL, err := s.cloudinaryService.UploadImage(file, "banner-images")
+	// Upload image to media storage
+	imageURL, err := s.mediaStore.UploadImage(file, "banner-images")
 	if err != nil {
-		return fmt.Errorf("failed to upload image to Cloudinary: %w", err)
+		return fmt.Errorf("failed to upload image to media storage: %w", err)
 	}
 	
 	// Set the image URL
@@ -8,11 +8,14 @@ import (
 	"time"
 	"unicode"
 
+	"treesindia/database"
+	"treesindia/metrics"
 	"treesindia/models"
 	"treesindia/repositories"
 	"treesindia/utils"
 
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 // isDigit checks if a byte is a digit
@@ -20,31 +23,132 @@ func isDigit(b byte) bool {
 	return unicode.IsDigit(rune(b))
 }
 
+// bookingDurationMinutes returns how long a booking's service takes, defaulting to two hours
+func bookingDurationMinutes(booking *models.Booking) int {
+	serviceDurationMinutes := 120 // Default
+	if booking.PriceBreakdown != nil && booking.PriceBreakdown.DurationMinutes > 0 {
+		serviceDurationMinutes = booking.PriceBreakdown.DurationMinutes
+	} else if booking.Service.Duration != nil && *booking.Service.Duration != "" {
+		duration, err := utils.ParseDuration(*booking.Service.Duration)
+		if err == nil {
+			serviceDurationMinutes = duration.ToMinutes()
+		}
+	}
+	return serviceDurationMinutes
+}
+
+// bookingPincode returns the pincode of a booking address, from postal_code or the address string
+func bookingPincode(address *models.BookingAddress) string {
+	pincode := address.PostalCode
+	if pincode == "" {
+		// Try to extract 6-digit pincode from address string
+		// Look for 6-digit numbers in the address
+		addressStr := address.Address
+		if len(addressStr) > 0 {
+			// Simple regex-like extraction: find 6-digit number
+			for i := 0; i <= len(addressStr)-6; i++ {
+				if isDigit(addressStr[i]) && isDigit(addressStr[i+1]) && isDigit(addressStr[i+2]) &&
+					isDigit(addressStr[i+3]) && isDigit(addressStr[i+4]) && isDigit(addressStr[i+5]) {
+					// Check if it's a word boundary (not part of a longer number)
+					if (i == 0 || !isDigit(addressStr[i-1])) && 
+					   (i+6 >= len(addressStr) || !isDigit(addressStr[i+6])) {
+						pincode = addressStr[i : i+6]
+						break
+					}
+				}
+			}
+		}
+	}
+	return pincode
+}
+
 type BookingService struct {
-	bookingRepo      *repositories.BookingRepository
+	db               *gorm.DB
+	bookingRepo      BookingStore
 	serviceRepo      *repositories.ServiceRepository
 	userRepo         *repositories.UserRepository
 	workerAssignmentRepo *repositories.WorkerAssignmentRepository
 	serviceAreaRepo  *repositories.ServiceAreaRepository
 	locationRepo     *repositories.LocationRepository
 	paymentService   *PaymentService
-	razorpayService  *RazorpayService
+	walletService    *UnifiedWalletService
+	razorpayService  PaymentGateway
 	notificationService *NotificationService
 	enhancedNotificationService *EnhancedNotificationService
+	couponService    *CouponService
+	searchAnalyticsService *SearchAnalyticsService
+	pricingService   *PricingService
+	availabilityService *AvailabilityService
+	assignmentOfferRepo *repositories.AssignmentOfferRepository
+	adminConfigService *AdminConfigService
+	userDocumentService *UserDocumentService
 }
 
 func NewBookingService(enhancedNotificationService *EnhancedNotificationService) *BookingService {
+	return NewBookingServiceWith(
+		database.GetDB(),
+		repositories.NewBookingRepository(),
+		repositories.NewServiceRepository(),
+		repositories.NewUserRepository(),
+		repositories.NewWorkerAssignmentRepository(),
+		repositories.NewServiceAreaRepository(),
+		repositories.NewLocationRepository(),
+		repositories.NewAssignmentOfferRepository(),
+		NewPaymentService(),
+		NewUnifiedWalletService(),
+		NewNotificationService(),
+		enhancedNotificationService,
+		NewCouponService(),
+		NewSearchAnalyticsService(),
+		NewPricingService(),
+		NewAvailabilityService(),
+		NewAdminConfigService(),
+		NewUserDocumentService(repositories.NewUserDocumentRepository(), nil),
+	)
+}
+
+// NewBookingServiceWith creates a booking service on db and the given stores and services. Online
+// booking payments go through paymentService and its gateway, wallet payments through walletService.
+func NewBookingServiceWith(
+	db *gorm.DB,
+	bookingRepo BookingStore,
+	serviceRepo *repositories.ServiceRepository,
+	userRepo *repositories.UserRepository,
+	workerAssignmentRepo *repositories.WorkerAssignmentRepository,
+	serviceAreaRepo *repositories.ServiceAreaRepository,
+	locationRepo *repositories.LocationRepository,
+	assignmentOfferRepo *repositories.AssignmentOfferRepository,
+	paymentService *PaymentService,
+	walletService *UnifiedWalletService,
+	notificationService *NotificationService,
+	enhancedNotificationService *EnhancedNotificationService,
+	couponService *CouponService,
+	searchAnalyticsService *SearchAnalyticsService,
+	pricingService *PricingService,
+	availabilityService *AvailabilityService,
+	adminConfigService *AdminConfigService,
+	userDocumentService *UserDocumentService,
+) *BookingService {
 	return &BookingService{
-		bookingRepo:      repositories.NewBookingRepository(),
-		serviceRepo:      repositories.NewServiceRepository(),
-		userRepo:         repositories.NewUserRepository(),
-		workerAssignmentRepo: repositories.NewWorkerAssignmentRepository(),
-		serviceAreaRepo:  repositories.NewServiceAreaRepository(),
-		locationRepo:     repositories.NewLocationRepository(),
-		paymentService:   NewPaymentService(),
-		razorpayService:  NewRazorpayService(),
-		notificationService: NewNotificationService(),
+		db:               db,
+		bookingRepo:      bookingRepo,
+		serviceRepo:      serviceRepo,
+		userRepo:         userRepo,
+		workerAssignmentRepo: workerAssignmentRepo,
+		serviceAreaRepo:  serviceAreaRepo,
+		locationRepo:     locationRepo,
+		paymentService:   paymentService,
+		walletService:    walletService,
+		razorpayService:  paymentService.razorpayService,
+		notificationService: notificationService,
 		enhancedNotificationService: enhancedNotificationService,
+		couponService:    couponService,
+		searchAnalyticsService: searchAnalyticsService,
+		pricingService:   pricingService,
+		availabilityService: availabilityService,
+		assignmentOfferRepo: assignmentOfferRepo,
+		adminConfigService: adminConfigService,
+		userDocumentService: userDocumentService,
 	}
 }
 
@@ -66,27 +170,7 @@ func (bs *BookingService) CreateBooking(userID uint, req *models.CreateBookingRe
 	}
 
 	// 1.5. Check service availability using flexible matching (city/state OR pincode)
-	// Extract pincode from postal_code or address string
-	pincode := req.Address.PostalCode
-	if pincode == "" {
-		// Try to extract 6-digit pincode from address string
-		// Look for 6-digit numbers in the address
-		addressStr := req.Address.Address
-		if len(addressStr) > 0 {
-			// Simple regex-like extraction: find 6-digit number
-			for i := 0; i <= len(addressStr)-6; i++ {
-				if isDigit(addressStr[i]) && isDigit(addressStr[i+1]) && isDigit(addressStr[i+2]) &&
-					isDigit(addressStr[i+3]) && isDigit(addressStr[i+4]) && isDigit(addressStr[i+5]) {
-					// Check if it's a word boundary (not part of a longer number)
-					if (i == 0 || !isDigit(addressStr[i-1])) && 
-					   (i+6 >= len(addressStr) || !isDigit(addressStr[i+6])) {
-						pincode = addressStr[i : i+6]
-						break
-					}
-				}
-			}
-		}
-	}
+	pincode := bookingPincode(&req.Address)
 	
 	available, err := bs.serviceAreaRepo.CheckServiceAvailabilityFlexible(req.ServiceID, req.Address.City, req.Address.State, pincode)
 	if err != nil {
@@ -146,17 +230,12 @@ func (bs *BookingService) CreateBooking(userID uint, req *models.CreateBookingRe
 		holdTimeMinutes = 7 // Default fallback
 	}
 
-	// 4. Calculate service duration from service
-	var serviceDurationMinutes int
-	if service.Duration != nil && *service.Duration != "" {
-		duration, err := utils.ParseDuration(*service.Duration)
-		if err != nil {
-			return nil, nil, fmt.Errorf("invalid service duration: %v", err)
-		}
-		serviceDurationMinutes = duration.ToMinutes()
-	} else {
-		serviceDurationMinutes = 120 // Default 2 hours
+	// 4. Price the chosen variant, quantity and add-ons; the duration is their summed duration
+	priceBreakdown, err := bs.pricingService.Calculate(service, &req.ServiceSelection)
+	if err != nil {
+		return nil, nil, err
 	}
+	serviceDurationMinutes := priceBreakdown.DurationMinutes
 
 	// 5. Determine booking type and payment requirements
 	var bookingType models.BookingType
@@ -166,27 +245,38 @@ func (bs *BookingService) CreateBooking(userID uint, req *models.CreateBookingRe
 		// Fixed price service - implement two-phase booking
 		bookingType = models.BookingTypeRegular
 		
-		// Check if service price is valid
-		if service.Price == nil {
-			return nil, nil, errors.New("service price is not set")
-		}
-		totalAmount = service.Price
-		
 		// Check if time slot is available using worker pool
-		// Extract location from address
-		location := ""
-		if req.Address.City != "" && req.Address.State != "" {
-			location = req.Address.City + ", " + req.Address.State
-		}
-		
-		isSlotAvailable, err := bs.isTimeSlotAvailable(scheduledTime, serviceDurationMinutes, req.ServiceID, location)
+		where := models.PricingLocation{City: req.Address.City, State: req.Address.State, Pincode: pincode}
+		slot, err := bs.getAvailableSlot(scheduledTime, service, priceBreakdown, where)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to check slot availability: %v", err)
 		}
 		
-		if !isSlotAvailable {
+		if slot == nil {
 			return nil, nil, errors.New("selected time slot is not available - no worker slots available")
 		}
+
+		// Use the slot's price, which includes surge pricing
+		priceBreakdown = slot.priceBreakdown
+		totalAmount = &priceBreakdown.Total
+
+		// Apply promo code
+		var discount *CouponDiscount
+		couponCheckout := &CouponCheckout{
+			UserID:     userID,
+			Scope:      models.CouponScopeBooking,
+			Amount:     priceBreakdown.Total,
+			ServiceID:  service.ID,
+			CategoryID: service.CategoryID,
+			City:       req.Address.City,
+		}
+		if req.CouponCode != "" {
+			discount, err = bs.couponService.CalculateDiscount(req.CouponCode, couponCheckout)
+			if err != nil {
+				return nil, nil, err
+			}
+			totalAmount = &discount.FinalAmount
+		}
 		
 		scheduledEndTime := scheduledTime.Add(time.Duration(serviceDurationMinutes+bufferTimeMinutes) * time.Minute)
 
@@ -220,6 +310,8 @@ func (bs *BookingService) CreateBooking(userID uint, req *models.CreateBookingRe
 			ContactPhone:        req.ContactPhone,
 			SpecialInstructions: req.SpecialInstructions,
 			HoldExpiresAt:       &holdExpiresAt,
+			PriceBreakdown:      priceBreakdown,
+			PricingRuleID:       priceBreakdown.AppliedPricingRuleID(),
 		}
 
 		// 9. Save booking
@@ -228,6 +320,19 @@ func (bs *BookingService) CreateBooking(userID uint, req *models.CreateBookingRe
 			return nil, nil, fmt.Errorf("failed to save booking: %v", err)
 		}
 
+		// 9.5. Reserve the coupon until the payment completes
+		var redemption *models.CouponRedemption
+		var paymentMetadata *models.JSONMap
+		if discount != nil {
+			redemption, err = bs.couponService.Reserve(discount, couponCheckout, "booking", booking.ID)
+			if err != nil {
+				booking.Status = models.BookingStatusCancelled
+				bs.bookingRepo.Update(booking)
+				return nil, nil, err
+			}
+			paymentMetadata = discount.PaymentMetadata(nil)
+		}
+
 		// 10. Create payment record
 		paymentReq := &models.CreatePaymentRequest{
 			UserID:            userID,
@@ -238,12 +343,19 @@ func (bs *BookingService) CreateBooking(userID uint, req *models.CreateBookingRe
 			RelatedEntityType: "booking",
 			RelatedEntityID:   booking.ID,
 			Description:       "Service booking payment",
+			Metadata:          paymentMetadata,
 		}
 
-		_, razorpayOrder, err := bs.paymentService.CreateRazorpayOrder(paymentReq)
+		payment, razorpayOrder, err := bs.paymentService.CreateRazorpayOrder(paymentReq)
 		if err != nil {
+			if redemption != nil {
+				bs.couponService.Release("booking", booking.ID, userID)
+			}
 			return nil, nil, fmt.Errorf("failed to create payment: %v", err)
 		}
+		if redemption != nil {
+			bs.couponService.AttachPayment(redemption, payment.ID)
+		}
 
 		// Calculate payment progress before returning
 		booking.GetPaymentProgress()
@@ -255,6 +367,11 @@ func (bs *BookingService) CreateBooking(userID uint, req *models.CreateBookingRe
 	} else {
 		// Inquiry-based service
 		bookingType = models.BookingTypeInquiry
+
+		// Inquiry fees are not discounted; coupons apply to the quote payment
+		if req.CouponCode != "" {
+			return nil, nil, errors.New("coupons can be applied when paying for the quote")
+		}
 		
 		// Check inquiry booking fee
 		feeConfig, err := adminConfigRepo.GetByKey("inquiry_booking_fee")
@@ -556,7 +673,7 @@ func (bs *BookingService) checkWorkerBookingConflict(workerID uint, startTime ti
 	// Get all bookings for this worker that overlap with the requested time
 	// Exclude cancelled bookings
 	var conflictingBookings []models.Booking
-	err := bs.bookingRepo.GetDB().Joins("JOIN worker_assignments ON bookings.id = worker_assignments.booking_id").
+	err := bs.db.Joins("JOIN worker_assignments ON bookings.id = worker_assignments.booking_id").
 		Where("worker_assignments.worker_id = ? AND worker_assignments.status IN (?) AND bookings.status != ?", 
 			workerID, []string{"reserved", "assigned", "accepted", "in_progress"}, models.BookingStatusCancelled).
 		Where("(bookings.scheduled_time < ? AND bookings.scheduled_end_time > ?) OR "+
@@ -577,34 +694,26 @@ func (bs *BookingService) checkWorkerBookingConflict(workerID uint, startTime ti
 	return len(conflictingBookings) > 0, nil
 }
 
-// isTimeSlotAvailable checks if a time slot is available for booking using Go-based calculation
-func (bs *BookingService) isTimeSlotAvailable(scheduledTime time.Time, serviceDurationMinutes int, serviceID uint, location string) (bool, error) {
-	// Ensure service duration is valid
-	if serviceDurationMinutes <= 0 {
-		serviceDurationMinutes = 120 // Default to 2 hours if invalid
-	}
-
+// getAvailableSlot returns the slot starting at scheduledTime, priced for the service selection, or
+// nil if no worker is free for it
+func (bs *BookingService) getAvailableSlot(scheduledTime time.Time, service *models.Service, priceBreakdown *models.ServicePriceBreakdown, where models.PricingLocation) (*AvailableSlot, error) {
 	// Get total active Trees India workers only - filter at database level
 	var totalWorkers int64
-	db := bs.userRepo.GetDB()
+	db := bs.db
 	err := db.Model(&models.User{}).
 		Joins("JOIN workers ON users.id = workers.user_id").
 		Where("users.user_type = ? AND users.is_active = ? AND workers.worker_type = ?", 
 			models.UserTypeWorker, true, models.WorkerTypeTreesIndia).
 		Count(&totalWorkers).Error
 	if err != nil {
-		return false, fmt.Errorf("failed to get workers: %v", err)
+		return nil, fmt.Errorf("failed to get workers: %v", err)
 	}
 
 	// If no Trees India workers available, return false
 	if totalWorkers == 0 {
-		return false, nil
+		return nil, nil
 	}
 
-	// Use AvailabilityService to check slot availability for consistency
-	// This ensures the same logic is used for both showing available slots and validating bookings
-	availabilityService := NewAvailabilityService()
-	
 	// Use IST timezone (same as AvailabilityService) to format the date and time
 	istLocation, err := time.LoadLocation("Asia/Kolkata")
 	if err != nil {
@@ -616,38 +725,47 @@ func (bs *BookingService) isTimeSlotAvailable(scheduledTime time.Time, serviceDu
 	dateStr := scheduledTimeIST.Format("2006-01-02")
 	slotKey := scheduledTimeIST.Format("15:04")
 	
-	logrus.Infof("isTimeSlotAvailable: Checking slot - date=%s, time=%s (IST), serviceID=%d, duration=%d", 
-		dateStr, slotKey, serviceID, serviceDurationMinutes)
+	logrus.Infof("getAvailableSlot: Checking slot - date=%s, time=%s (IST), serviceID=%d, duration=%d", 
+		dateStr, slotKey, service.ID, priceBreakdown.DurationMinutes)
 	
 	// Get available slots for this date using the same service
-	availabilityResponse, err := availabilityService.GetAvailableSlotsWithDuration(serviceID, dateStr, location, nil)
+	// Use AvailabilityService to check slot availability for consistency
+	// This ensures the same logic is used for both showing available slots and validating bookings
+	availabilityResponse, err := bs.availabilityService.GetAvailableSlotsForBreakdown(service, dateStr, where, priceBreakdown)
 	if err != nil {
-		logrus.Errorf("isTimeSlotAvailable: Failed to get available slots - %v", err)
-		return false, fmt.Errorf("failed to get available slots: %v", err)
+		logrus.Errorf("getAvailableSlot: Failed to get available slots - %v", err)
+		return nil, fmt.Errorf("failed to get available slots: %v", err)
 	}
 	
-	logrus.Infof("isTimeSlotAvailable: Found %d slots for date=%s", len(availabilityResponse.AvailableSlots), dateStr)
+	logrus.Infof("getAvailableSlot: Found %d slots for date=%s", len(availabilityResponse.AvailableSlots), dateStr)
 	
 	// Find the specific time slot in the available slots (format in IST to match slot keys)
-	for _, slot := range availabilityResponse.AvailableSlots {
+	for i := range availabilityResponse.AvailableSlots {
+		slot := &availabilityResponse.AvailableSlots[i]
 		if slot.Time == slotKey {
-			logrus.Infof("isTimeSlotAvailable: Found slot %s - available=%v, workers=%d", 
+			logrus.Infof("getAvailableSlot: Found slot %s - available=%v, workers=%d", 
 				slotKey, slot.IsAvailable, slot.AvailableWorkers)
 			// Return the availability status from the same calculation used to show slots
-			return slot.IsAvailable, nil
+			if !slot.IsAvailable {
+				return nil, nil
+			}
+			if slot.priceBreakdown == nil {
+				slot.priceBreakdown = priceBreakdown
+			}
+			return slot, nil
 		}
 	}
 	
 	// If slot not found in available slots list, it's not available
-	logrus.Warnf("isTimeSlotAvailable: Slot %s not found in available slots list for date=%s", slotKey, dateStr)
-	return false, nil
+	logrus.Warnf("getAvailableSlot: Slot %s not found in available slots list for date=%s", slotKey, dateStr)
+	return nil, nil
 }
 
 // assignAvailableWorker finds and assigns an available Trees India worker for the given time period
 func (bs *BookingService) assignAvailableWorker(startTime time.Time, serviceDurationMinutes int) (uint, error) {
 	// Get all active Trees India workers only - filter at database level
 	var workers []models.User
-	db := bs.userRepo.GetDB()
+	db := bs.db
 	err := db.Joins("JOIN workers ON users.id = workers.user_id").
 		Where("users.user_type = ? AND users.is_active = ? AND workers.worker_type = ?", 
 			models.UserTypeWorker, true, models.WorkerTypeTreesIndia).
@@ -683,19 +801,16 @@ func (bs *BookingService) assignAvailableWorker(startTime time.Time, serviceDura
 
 // VerifyPaymentAndCreateBooking verifies payment and creates the booking
 func (bs *BookingService) VerifyPaymentAndCreateBooking(userID uint, req *models.VerifyPaymentAndCreateBookingRequest) (*models.Booking, error) {
-	// 1. Create payment service
-	paymentService := NewPaymentService()
-
-	// 2. Find the payment record by Razorpay order ID
-	payment, err := paymentService.GetPaymentByRazorpayOrderID(req.RazorpayOrderID)
+	// 1. Find the payment record by Razorpay order ID
+	payment, err := bs.paymentService.GetPaymentByRazorpayOrderID(req.RazorpayOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("payment not found: %v", err)
 	}
 
-	// 3. Verify and complete the payment
+	// 2. Verify and complete the payment
 	if req.RazorpaySignature != "" {
 		// If signature is provided, verify it
-		_, err = paymentService.VerifyAndCompletePayment(payment.ID, req.RazorpayPaymentID, req.RazorpaySignature)
+		_, err = bs.paymentService.VerifyAndCompletePayment(payment.ID, req.RazorpayPaymentID, req.RazorpaySignature)
 		if err != nil {
 			return nil, fmt.Errorf("payment verification failed: %v", err)
 		}
@@ -707,19 +822,20 @@ func (bs *BookingService) VerifyPaymentAndCreateBooking(userID uint, req *models
 		payment.CompletedAt = &now
 		payment.Notes = "Payment completed via frontend callback"
 		
-		err = paymentService.paymentRepo.Update(payment)
+		err = bs.paymentService.paymentRepo.Update(payment)
 		if err != nil {
 			return nil, fmt.Errorf("failed to update payment status: %v", err)
 		}
+		metrics.RecordPayment(string(payment.Type), string(payment.Status))
 	}
 
-	// 4. Create the booking with the verified payment
+	// 3. Create the booking with the verified payment
 	booking, _, err := bs.CreateBooking(userID, &req.CreateBookingRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	// 5. Update booking status - only confirm for regular bookings, keep inquiry bookings pending
+	// 4. Update booking status - only confirm for regular bookings, keep inquiry bookings pending
 	if booking.BookingType == models.BookingTypeRegular {
 		booking.Status = models.BookingStatusConfirmed
 	} else if booking.BookingType == models.BookingTypeInquiry {
@@ -727,13 +843,15 @@ func (bs *BookingService) VerifyPaymentAndCreateBooking(userID uint, req *models
 		booking.Status = models.BookingStatusPending
 	}
 
-	// 6. Save booking
+	// 5. Save booking
 	err = bs.bookingRepo.Update(booking)
 	if err != nil {
 		return nil, err
 	}
 
-	// 7. Send confirmation notifications only for regular bookings
+	bs.searchAnalyticsService.AttributeBooking(booking)
+
+	// 6. Send confirmation notifications only for regular bookings
 	if booking.BookingType == models.BookingTypeRegular {
 		// Get user and service details for notification
 		var user models.User
@@ -803,6 +921,8 @@ func (bs *BookingService) VerifyPayment(req *models.VerifyPaymentRequest) (*mode
 		return nil, err
 	}
 
+	bs.searchAnalyticsService.AttributeBooking(booking)
+
 	// 7. Send confirmation notifications
 
 	// Calculate payment progress before returning
@@ -829,6 +949,9 @@ func (bs *BookingService) CleanupExpiredTemporaryHolds() error {
 			continue
 		}
 
+		// Give back any coupon reserved for the unpaid booking
+		bs.couponService.Release("booking", booking.ID, booking.UserID)
+
 		// Disable call masking for expired bookings
 		callMaskingService := NewCallMaskingService()
 		go callMaskingService.DisableCallMasking(booking.ID)
@@ -847,6 +970,30 @@ func (bs *BookingService) GetBookingByID(bookingID uint) (*models.Booking, error
 	return bs.bookingRepo.GetByID(bookingID)
 }
 
+// GetServicePINs gets the PINs a customer gives the worker to start and complete the service. The
+// start PIN is shown until the service starts and the completion PIN only once it is in progress, so
+// the worker can't collect both on arrival.
+func (bs *BookingService) GetServicePINs(userID uint, bookingID uint) (*models.ServicePINsResponse, error) {
+	booking, err := bs.bookingRepo.GetByID(bookingID)
+	if err != nil {
+		return nil, errors.New("booking not found")
+	}
+
+	if booking.UserID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	response := &models.ServicePINsResponse{BookingID: booking.ID}
+	switch booking.Status {
+	case models.BookingStatusCompleted, models.BookingStatusCancelled, models.BookingStatusRejected:
+	case models.BookingStatusInProgress:
+		response.CompletionPIN = booking.CompletionPIN
+	default:
+		response.StartPIN = booking.StartPIN
+	}
+	return response, nil
+}
+
 // CancelUserBooking cancels a user's booking
 func (bs *BookingService) CancelUserBooking(userID uint, bookingID uint, req *models.CancelBookingRequest) (map[string]interface{}, error) {
 	// 1. Get booking
@@ -870,6 +1017,16 @@ func (bs *BookingService) CancelUserBooking(userID uint, bookingID uint, req *mo
 		return nil, err
 	}
 
+	// Close the worker's assignment so the booking isn't expired and offered to another worker
+	assignment, err := bs.workerAssignmentRepo.CancelOpenAssignment(booking.ID)
+	if err != nil {
+		logrus.Errorf("Failed to close assignment of cancelled booking %d: %v", booking.ID, err)
+	} else if assignment != nil && assignment.Status == models.AssignmentStatusAssigned {
+		if err := bs.assignmentOfferRepo.RespondToOffer(assignment.ID, assignment.WorkerID, models.AssignmentOfferStatusWithdrawn, "Booking cancelled"); err != nil {
+			logrus.Errorf("Failed to close offer for assignment %d: %v", assignment.ID, err)
+		}
+	}
+
 	// 3. Disable call masking if it exists
 	callMaskingService := NewCallMaskingService()
 	go callMaskingService.DisableCallMasking(bookingID)
@@ -919,7 +1076,7 @@ func (bs *BookingService) AssignWorker(bookingID uint, workerID uint, notes stri
 
 	// 2. Check if worker exists and is available
 	worker := &models.User{}
-	db := bs.userRepo.GetDB()
+	db := bs.db
 	err = db.Where("id = ?", workerID).Preload("Worker").First(worker).Error
 	if err != nil {
 		return nil, errors.New("worker not found")
@@ -934,6 +1091,10 @@ func (bs *BookingService) AssignWorker(bookingID uint, workerID uint, notes stri
 		return nil, errors.New("only Trees India workers can be assigned to bookings")
 	}
 
+	if err := bs.checkWorkerDocuments(workerID); err != nil {
+		return nil, err
+	}
+
 	// 3. Create worker assignment
 	assignment := &models.WorkerAssignment{
 		BookingID:        booking.ID,
@@ -1035,10 +1196,28 @@ func (bs *BookingService) AssignWorkerToBooking(bookingID uint, workerID uint, a
 		return nil, errors.New("booking is not in a valid state for worker assignment")
 	}
 
-	// 2. Check if worker exists and is available
+	return bs.assignWorker(booking, workerID, adminID, "Assigned by admin")
+}
+
+// checkWorkerDocuments stops workers whose mandatory documents have expired from taking new bookings
+func (bs *BookingService) checkWorkerDocuments(workerID uint) error {
+	hasExpiredDocuments, err := bs.userDocumentService.HasExpiredMandatoryDocuments(workerID)
+	if err != nil {
+		return fmt.Errorf("failed to check worker documents: %v", err)
+	}
+	if hasExpiredDocuments {
+		return errors.New("worker's mandatory documents have expired")
+	}
+	return nil
+}
+
+// assignWorker offers a booking to a worker, reusing the booking's assignment if the previous worker
+// has not accepted it. The worker must accept within the acceptance SLA.
+func (bs *BookingService) assignWorker(booking *models.Booking, workerID uint, assignedBy uint, notes string) (*models.WorkerAssignment, error) {
+	// 1. Check if worker exists and is available
 	worker := &models.User{}
-	db := bs.userRepo.GetDB()
-	err = db.Where("id = ?", workerID).Preload("Worker").First(worker).Error
+	db := bs.db
+	err := db.Where("id = ?", workerID).Preload("Worker").First(worker).Error
 	if err != nil {
 		return nil, errors.New("worker not found")
 	}
@@ -1052,15 +1231,13 @@ func (bs *BookingService) AssignWorkerToBooking(bookingID uint, workerID uint, a
 		return nil, errors.New("only Trees India workers can be assigned to bookings")
 	}
 
-	// 3. Check if the worker is available for this time slot
+	if err := bs.checkWorkerDocuments(workerID); err != nil {
+		return nil, err
+	}
+
+	// 2. Check if the worker is available for this time slot
 	if booking.ScheduledTime != nil {
-		serviceDurationMinutes := 120 // Default
-		if booking.Service.Duration != nil && *booking.Service.Duration != "" {
-			duration, err := utils.ParseDuration(*booking.Service.Duration)
-			if err == nil {
-				serviceDurationMinutes = duration.ToMinutes()
-			}
-		}
+		serviceDurationMinutes := bookingDurationMinutes(booking)
 
 		// Check if worker has any conflicting bookings during this time period
 		hasConflict, err := bs.checkWorkerBookingConflict(workerID, *booking.ScheduledTime, booking.ScheduledTime.Add(time.Duration(serviceDurationMinutes)*time.Minute))
@@ -1073,10 +1250,13 @@ func (bs *BookingService) AssignWorkerToBooking(bookingID uint, workerID uint, a
 		}
 	}
 
-	// 4. Check existing assignment status
-	workerAssignmentRepo := repositories.NewWorkerAssignmentRepository()
-	existingAssignment, err := workerAssignmentRepo.GetByBookingID(bookingID)
+	now := time.Now()
+	acceptBy := now.Add(time.Duration(bs.adminConfigService.GetAssignmentAcceptanceSLAMinutes()) * time.Minute)
+
+	// 3. Check existing assignment status
+	existingAssignment, err := bs.workerAssignmentRepo.GetByBookingID(booking.ID)
 	
+	var assignment *models.WorkerAssignment
 	if err == nil && existingAssignment != nil {
 		// Check if admin can reassign based on current assignment status
 		if existingAssignment.Status == models.AssignmentStatusAccepted {
@@ -1093,12 +1273,20 @@ func (bs *BookingService) AssignWorkerToBooking(bookingID uint, workerID uint, a
 		
 		// Allow reassignment if status is: assigned (not accepted yet) or rejected
 		if existingAssignment.Status == models.AssignmentStatusAssigned || existingAssignment.Status == models.AssignmentStatusRejected {
+			// Withdraw the offer from a worker who has not answered yet
+			if existingAssignment.Status == models.AssignmentStatusAssigned && existingAssignment.WorkerID != workerID {
+				if err := bs.assignmentOfferRepo.RespondToOffer(existingAssignment.ID, existingAssignment.WorkerID, models.AssignmentOfferStatusWithdrawn, "Reassigned before accepting"); err != nil {
+					logrus.Errorf("Failed to close offer for assignment %d: %v", existingAssignment.ID, err)
+				}
+			}
+
 			// Update existing assignment
 			existingAssignment.WorkerID = workerID
-			existingAssignment.AssignedBy = adminID
+			existingAssignment.AssignedBy = assignedBy
 			existingAssignment.Status = models.AssignmentStatusAssigned
-			existingAssignment.AssignedAt = time.Now()
-			existingAssignment.AssignmentNotes = "Worker reassigned by admin"
+			existingAssignment.AssignedAt = now
+			existingAssignment.AcceptBy = &acceptBy
+			existingAssignment.AssignmentNotes = notes
 			
 			// Clear previous acceptance/rejection data
 			existingAssignment.AcceptedAt = nil
@@ -1107,42 +1295,48 @@ func (bs *BookingService) AssignWorkerToBooking(bookingID uint, workerID uint, a
 			existingAssignment.RejectionNotes = ""
 			existingAssignment.RejectionReason = ""
 			
-			err = workerAssignmentRepo.Update(existingAssignment)
-			if err != nil {
-				return nil, err
-			}
-			
-			// Update booking status
-			booking.Status = models.BookingStatusAssigned
-			err = bs.bookingRepo.Update(booking)
+			err = bs.workerAssignmentRepo.Update(existingAssignment)
 			if err != nil {
 				return nil, err
 			}
-			
-			// Send notification to new worker
-			bs.sendWorkerAssignmentNotification(existingAssignment, booking)
-
-			return existingAssignment, nil
+			assignment = existingAssignment
 		}
 	}
 
-	// 5. Create new worker assignment
-	assignment := &models.WorkerAssignment{
-		BookingID:        booking.ID,
-		WorkerID:         workerID,
-		AssignedBy:       adminID,
-		Status:           models.AssignmentStatusAssigned,
-		AssignedAt:       time.Now(),
-		AssignmentNotes:  "Assigned by admin",
+	// 4. Create new worker assignment
+	if assignment == nil {
+		assignment = &models.WorkerAssignment{
+			BookingID:        booking.ID,
+			WorkerID:         workerID,
+			AssignedBy:       assignedBy,
+			Status:           models.AssignmentStatusAssigned,
+			AssignedAt:       now,
+			AcceptBy:         &acceptBy,
+			AssignmentNotes:  notes,
+		}
+
+		err = bs.workerAssignmentRepo.Create(assignment)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	err = bs.workerAssignmentRepo.Create(assignment)
+	// 5. Record the offer
+	err = bs.assignmentOfferRepo.Create(&models.AssignmentOffer{
+		BookingID:    booking.ID,
+		AssignmentID: assignment.ID,
+		WorkerID:     workerID,
+		Status:       models.AssignmentOfferStatusOffered,
+		OfferedAt:    now,
+		ExpiresAt:    acceptBy,
+	})
 	if err != nil {
-		return nil, err
+		logrus.Errorf("Failed to record offer of booking %d to worker %d: %v", booking.ID, workerID, err)
 	}
 
 	// 6. Update booking status
 	booking.Status = models.BookingStatusAssigned
+	booking.AssignmentEscalatedAt = nil
 	err = bs.bookingRepo.Update(booking)
 	if err != nil {
 		return nil, err
@@ -1260,8 +1454,6 @@ func (bs *BookingService) VerifyInquiryPaymentAndCreateBooking(userID uint, req
 	// We'll need to modify the frontend to send service_id in the verification request
 	
 	// 3. Create payment record only after successful verification
-	paymentService := NewPaymentService()
-	
 	// Get service details from request
 	serviceID := req.ServiceID
 	
@@ -1282,20 +1474,20 @@ func (bs *BookingService) VerifyInquiryPaymentAndCreateBooking(userID uint, req
 		Notes:              "Inquiry booking fee - completed",
 	}
 	
-	payment, err := paymentService.CreatePayment(paymentReq)
+	payment, err := bs.paymentService.CreatePayment(paymentReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create payment record: %v", err)
 	}
 	
-	// Update payment with Razorpay details and mark as completed
+	// Update payment with Razorpay details and hold the fee until the quote is settled
 	now := time.Now()
-	payment.Status = models.PaymentStatusCompleted
+	payment.Status = models.PaymentStatusHold
 	payment.RazorpayOrderID = &req.RazorpayOrderID
 	payment.RazorpayPaymentID = &req.RazorpayPaymentID
 	payment.RazorpaySignature = &req.RazorpaySignature
 	payment.CompletedAt = &now
 	
-	err = paymentService.UpdatePayment(payment)
+	err = bs.paymentService.UpdatePayment(payment)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update payment with Razorpay details: %v", err)
 	}
@@ -1327,10 +1519,12 @@ func (bs *BookingService) VerifyInquiryPaymentAndCreateBooking(userID uint, req
 		return nil, fmt.Errorf("failed to create booking: %v", err)
 	}
 
+	bs.searchAnalyticsService.AttributeBooking(booking)
+
 	// 6. Update payment to link to the actual booking
 	payment.RelatedEntityType = "booking"
 	payment.RelatedEntityID = booking.ID
-	err = paymentService.UpdatePayment(payment)
+	err = bs.paymentService.UpdatePayment(payment)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update payment with booking link: %v", err)
 	}
@@ -1411,6 +1605,8 @@ func (bs *BookingService) VerifyInquiryPayment(userID uint, req *models.VerifyIn
 		return nil, err
 	}
 
+	bs.searchAnalyticsService.AttributeBooking(booking)
+
 	logrus.Infof("Booking created with ID: %d, status: %s, booking_type: %s", 
 		booking.ID, booking.Status, booking.BookingType)
 
@@ -1468,10 +1664,10 @@ func (bs *BookingService) VerifyInquiryPayment(userID uint, req *models.VerifyIn
 		return nil, fmt.Errorf("failed to update booking: %v", err)
 	}
 	
-	// 11. Update payment record with booking link and mark as completed
+	// 11. Update payment record with booking link and hold the fee until the quote is settled
 	existingPayment.RelatedEntityType = "booking"
 	existingPayment.RelatedEntityID = booking.ID
-	existingPayment.Status = models.PaymentStatusCompleted
+	existingPayment.Status = models.PaymentStatusHold
 	existingPayment.RazorpayPaymentID = &req.RazorpayPaymentID
 	existingPayment.RazorpaySignature = &req.RazorpaySignature
 	now := time.Now()
@@ -1602,6 +1798,7 @@ func (bs *BookingService) ConvertToOptimizedBookingResponse(booking *models.Book
 		QuoteAcceptedAt:       booking.QuoteAcceptedAt,
 		QuoteExpiresAt:        booking.QuoteExpiresAt,
 		QuoteDuration:         booking.QuoteDuration,
+		PriceBreakdown:        booking.PriceBreakdown,
 		
 		Service: &models.OptimizedServiceInfo{
 			ID:        booking.Service.ID,
@@ -1735,6 +1932,7 @@ func (bs *BookingService) ConvertToUserOptimizedBookingResponse(booking *models.
 		QuoteAcceptedAt:       booking.QuoteAcceptedAt,
 		QuoteExpiresAt:        booking.QuoteExpiresAt,
 		QuoteDuration:         booking.QuoteDuration,
+		PriceBreakdown:        booking.PriceBreakdown,
 		
 		Service: &models.OptimizedServiceInfo{
 			ID:        booking.Service.ID,
@@ -1880,6 +2078,7 @@ func (bs *BookingService) ConvertToDetailedBookingResponse(booking *models.Booki
 		QuoteAcceptedAt:       booking.QuoteAcceptedAt,
 		QuoteExpiresAt:        booking.QuoteExpiresAt,
 		QuoteDuration:         booking.QuoteDuration,
+		PriceBreakdown:        booking.PriceBreakdown,
 		
 		Service: &models.DetailedServiceInfo{
 			ID:          booking.Service.ID,
@@ -2008,9 +2207,10 @@ func (bs *BookingService) CreateBookingWithWallet(userID uint, req *models.Creat
 		return nil, errors.New("service is not fixed price")
 	}
 
-	// 3. Check if service price is valid
-	if service.Price == nil {
-		return nil, errors.New("service price is not set")
+	// 3. Price the chosen variant, quantity and add-ons
+	priceBreakdown, err := bs.pricingService.Calculate(service, &req.ServiceSelection)
+	if err != nil {
+		return nil, err
 	}
 
 	// 4. Parse scheduled date and time
@@ -2040,42 +2240,52 @@ func (bs *BookingService) CreateBookingWithWallet(userID uint, req *models.Creat
 	}
 
 	// 7. Check if time slot is available
-	location := ""
-	if req.Address.City != "" && req.Address.State != "" {
-		location = req.Address.City + ", " + req.Address.State
-	}
+	where := models.PricingLocation{City: req.Address.City, State: req.Address.State, Pincode: bookingPincode(&req.Address)}
 	
-	// Calculate service duration using the same parsing logic as availability service
-	serviceDurationMinutes := 120 // Default 2 hours (same as availability service)
-	if service.Duration != nil && *service.Duration != "" {
-		duration, err := utils.ParseDuration(*service.Duration)
-		if err != nil {
-			logrus.Warnf("CreateBookingWithWallet: Failed to parse service duration '%s', using default - %v", *service.Duration, err)
-			serviceDurationMinutes = 120 // Use default on parse error
-		} else {
-			serviceDurationMinutes = duration.ToMinutes()
-		}
-	}
+	// The summed duration of the variant, quantity and add-ons (same as availability service)
+	serviceDurationMinutes := priceBreakdown.DurationMinutes
 	
 	// Log for debugging
 	logrus.Infof("CreateBookingWithWallet: Checking slot availability - date=%s, time=%s (IST), serviceID=%d, duration=%d", 
 		req.ScheduledDate, scheduledTime.Format("15:04"), req.ServiceID, serviceDurationMinutes)
 	
-	isSlotAvailable, err := bs.isTimeSlotAvailable(scheduledTime, serviceDurationMinutes, req.ServiceID, location)
+	slot, err := bs.getAvailableSlot(scheduledTime, service, priceBreakdown, where)
 	if err != nil {
 		logrus.Errorf("CreateBookingWithWallet: Slot availability check failed - %v", err)
 		return nil, fmt.Errorf("failed to check slot availability: %v", err)
 	}
 	
-	if !isSlotAvailable {
+	if slot == nil {
 		logrus.Warnf("CreateBookingWithWallet: Slot not available - date=%s, time=%s (IST), serviceID=%d", 
 			req.ScheduledDate, scheduledTime.Format("15:04"), req.ServiceID)
 		return nil, errors.New("selected time slot is not available")
 	}
 	
+	// Use the slot's price, which includes surge pricing
+	priceBreakdown = slot.priceBreakdown
+	
 	logrus.Infof("CreateBookingWithWallet: Slot available - date=%s, time=%s (IST), serviceID=%d", 
 		req.ScheduledDate, scheduledTime.Format("15:04"), req.ServiceID)
 
+	// 7.5. Apply promo code
+	amount := priceBreakdown.Total
+	var discount *CouponDiscount
+	couponCheckout := &CouponCheckout{
+		UserID:     userID,
+		Scope:      models.CouponScopeBooking,
+		Amount:     amount,
+		ServiceID:  service.ID,
+		CategoryID: service.CategoryID,
+		City:       req.Address.City,
+	}
+	if req.CouponCode != "" {
+		discount, err = bs.couponService.CalculateDiscount(req.CouponCode, couponCheckout)
+		if err != nil {
+			return nil, err
+		}
+		amount = discount.FinalAmount
+	}
+
 	// 8. Generate booking reference
 	bookingReference := bs.generateBookingReference()
 
@@ -2115,6 +2325,8 @@ func (bs *BookingService) CreateBookingWithWallet(userID uint, req *models.Creat
 		ContactPerson:       req.ContactPerson,
 		ContactPhone:        req.ContactPhone,
 		SpecialInstructions: req.SpecialInstructions,
+		PriceBreakdown:      priceBreakdown,
+		PricingRuleID:       priceBreakdown.AppliedPricingRuleID(),
 	}
 
 	// 12. Save booking
@@ -2123,17 +2335,43 @@ func (bs *BookingService) CreateBookingWithWallet(userID uint, req *models.Creat
 		return nil, fmt.Errorf("failed to save booking: %v", err)
 	}
 
+	// 12.5. Reserve the coupon before charging the wallet
+	var redemption *models.CouponRedemption
+	if discount != nil {
+		redemption, err = bs.couponService.Reserve(discount, couponCheckout, "booking", booking.ID)
+		if err != nil {
+			booking.Status = models.BookingStatusCancelled
+			booking.PaymentStatus = "failed"
+			bs.bookingRepo.Update(booking)
+			return nil, err
+		}
+	}
+
 	// 13. Process wallet payment after booking is created
-	walletService := NewUnifiedWalletService()
-	_, err = walletService.DeductFromWalletForBooking(userID, *service.Price, booking.ID, "Service booking payment")
+	payment, err := bs.walletService.DeductFromWalletForBooking(userID, amount, booking.ID, "Service booking payment")
 	if err != nil {
 		// If payment fails, update booking status to cancelled
 		booking.Status = models.BookingStatusCancelled
 		booking.PaymentStatus = "failed"
 		bs.bookingRepo.Update(booking)
+		if redemption != nil {
+			bs.couponService.Release("booking", booking.ID, userID)
+		}
 		return nil, fmt.Errorf("failed to process wallet payment: %v", err)
 	}
 
+	bs.searchAnalyticsService.AttributeBooking(booking)
+
+	// 13.5. Record the discount on the payment and mark the coupon used
+	if redemption != nil {
+		payment.Metadata = discount.PaymentMetadata(payment.Metadata)
+		if err := bs.paymentService.UpdatePayment(payment); err != nil {
+			logrus.Errorf("Failed to record coupon on payment %d: %v", payment.ID, err)
+		}
+		bs.couponService.AttachPayment(redemption, payment.ID)
+		bs.couponService.ApplyForPayment(payment.ID)
+	}
+
 	// 14. Send confirmation notification
 	
 	// 15. Send in-app notification to admin
@@ -2144,7 +2382,7 @@ func (bs *BookingService) CreateBookingWithWallet(userID uint, req *models.Creat
 	if err := bs.userRepo.FindByID(&user, userID); err == nil {
 	}
 
-	logrus.Infof("Wallet payment booking created successfully: booking_id=%d, amount=%.2f", booking.ID, *service.Price)
+	logrus.Infof("Wallet payment booking created successfully: booking_id=%d, amount=%.2f", booking.ID, amount)
 	// Calculate payment progress before returning
 	booking.GetPaymentProgress()
 	
@@ -2249,8 +2487,7 @@ func (bs *BookingService) CreateInquiryBookingWithWallet(userID uint, req *model
 	// 9. Process wallet payment if fee is required (after booking is created)
 	if feeAmount > 0 {
 		feeFloat := float64(feeAmount)
-		walletService := NewUnifiedWalletService()
-		_, err = walletService.DeductFromWalletForBooking(userID, feeFloat, booking.ID, "Inquiry booking fee")
+		feePayment, err := bs.walletService.DeductFromWalletForBooking(userID, feeFloat, booking.ID, "Inquiry booking fee")
 		if err != nil {
 			// If payment fails, update booking status to cancelled
 			booking.Status = models.BookingStatusCancelled
@@ -2258,6 +2495,14 @@ func (bs *BookingService) CreateInquiryBookingWithWallet(userID uint, req *model
 			bs.bookingRepo.Update(booking)
 			return nil, fmt.Errorf("failed to process wallet payment: %v", err)
 		}
+
+		// Hold the fee until the quote is accepted or expires
+		feePayment.Status = models.PaymentStatusHold
+		if err := bs.paymentService.UpdatePayment(feePayment); err != nil {
+			logrus.Errorf("Failed to hold inquiry fee payment %d: %v", feePayment.ID, err)
+		}
+
+		bs.searchAnalyticsService.AttributeBooking(booking)
 	}
 
 	logrus.Infof("Inquiry booking with wallet payment created successfully: booking_id=%d, fee_amount=%.2f", booking.ID, float64(feeAmount))
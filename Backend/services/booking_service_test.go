@@ -0,0 +1,252 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+	"treesindia/models"
+	"treesindia/services"
+	"treesindia/testutil"
+
+	"github.com/lib/pq"
+)
+
+// bookInquiry books an inquiry service, paying the inquiry fee through the payment gateway
+func bookInquiry(t *testing.T, f *flowFixture, userID uint, service *models.Service) *models.Booking {
+	t.Helper()
+
+	booking, order, err := f.bookings.CreateInquiryBooking(userID, inquiryRequest(service))
+	if err != nil {
+		t.Fatalf("CreateInquiryBooking returned error: %v", err)
+	}
+	if booking != nil || order == nil {
+		t.Fatalf("CreateInquiryBooking returned booking %v and order %v, want only a payment order", booking, order)
+	}
+
+	orderID := order["id"].(string)
+	booking, err = f.bookings.VerifyInquiryPayment(userID, &models.VerifyInquiryPaymentRequest{
+		ServiceID:         service.ID,
+		RazorpayOrderID:   orderID,
+		RazorpayPaymentID: "pay_" + orderID,
+		RazorpaySignature: f.gateway.Sign(orderID, "pay_"+orderID),
+	})
+	if err != nil {
+		t.Fatalf("VerifyInquiryPayment returned error: %v", err)
+	}
+	return booking
+}
+
+func inquiryRequest(service *models.Service) *models.CreateInquiryBookingRequest {
+	return &models.CreateInquiryBookingRequest{
+		ServiceID: service.ID,
+		Address: models.BookingAddress{
+			Name:    "Home",
+			Address: "12 Hill Cart Road",
+			City:    "Siliguri",
+			State:   "West Bengal",
+			Country: "India",
+		},
+		Description:   "Repaint two bedrooms",
+		ContactPerson: "Test User",
+		ContactPhone:  "+919000000001",
+	}
+}
+
+func TestInquiryBookingWithFee(t *testing.T) {
+	f := newFlowFixture(t)
+	testutil.SetAdminConfig(t, "inquiry_booking_fee", "99")
+	service := testutil.CreateInquiryService(t, "Siliguri", "West Bengal")
+	user := testutil.CreateUser(t, 0)
+
+	booking := bookInquiry(t, f, user.ID, service)
+
+	if booking.Status != models.BookingStatusPending || booking.BookingType != models.BookingTypeInquiry {
+		t.Errorf("booking status/type = %s/%s, want pending/inquiry", booking.Status, booking.BookingType)
+	}
+	if booking.Description != "Repaint two bedrooms" || booking.Address == nil {
+		t.Errorf("booking does not carry the inquiry details: description %q, address %v", booking.Description, booking.Address)
+	}
+
+	orders := f.gateway.Orders()
+	if len(orders) != 1 || orders[0]["amount"] != int64(9900) {
+		t.Fatalf("gateway orders = %v, want one order of 9900 paise", orders)
+	}
+	fee, err := f.payments.GetPaymentByRazorpayOrderID(orders[0]["id"].(string))
+	if err != nil {
+		t.Fatalf("inquiry fee payment not found: %v", err)
+	}
+	if fee.Status != models.PaymentStatusHold {
+		t.Errorf("inquiry fee status = %s, want hold until the quote is settled", fee.Status)
+	}
+	if fee.RelatedEntityType != "booking" || fee.RelatedEntityID != booking.ID {
+		t.Errorf("inquiry fee is related to %s %d, want booking %d", fee.RelatedEntityType, fee.RelatedEntityID, booking.ID)
+	}
+}
+
+func TestInquiryBookingRejectsBadSignature(t *testing.T) {
+	f := newFlowFixture(t)
+	testutil.SetAdminConfig(t, "inquiry_booking_fee", "99")
+	service := testutil.CreateInquiryService(t, "Siliguri", "West Bengal")
+	user := testutil.CreateUser(t, 0)
+
+	_, order, err := f.bookings.CreateInquiryBooking(user.ID, inquiryRequest(service))
+	if err != nil {
+		t.Fatalf("CreateInquiryBooking returned error: %v", err)
+	}
+
+	booking, err := f.bookings.VerifyInquiryPayment(user.ID, &models.VerifyInquiryPaymentRequest{
+		ServiceID:         service.ID,
+		RazorpayOrderID:   order["id"].(string),
+		RazorpayPaymentID: "pay_forged",
+		RazorpaySignature: "forged",
+	})
+	if err == nil {
+		t.Fatalf("VerifyInquiryPayment accepted a bad signature and created booking %d", booking.ID)
+	}
+}
+
+func TestInquiryBookingWithoutFee(t *testing.T) {
+	f := newFlowFixture(t)
+	testutil.SetAdminConfig(t, "inquiry_booking_fee", "0")
+	service := testutil.CreateInquiryService(t, "Siliguri", "West Bengal")
+	user := testutil.CreateUser(t, 0)
+
+	booking, order, err := f.bookings.CreateInquiryBooking(user.ID, inquiryRequest(service))
+	if err != nil {
+		t.Fatalf("CreateInquiryBooking returned error: %v", err)
+	}
+	if order != nil {
+		t.Errorf("CreateInquiryBooking returned payment order %v without a fee", order)
+	}
+	if booking == nil || booking.Status != models.BookingStatusPending {
+		t.Fatalf("booking = %v, want a pending booking", booking)
+	}
+	if orders := f.gateway.Orders(); len(orders) != 0 {
+		t.Errorf("%d gateway orders were created, want none", len(orders))
+	}
+}
+
+func TestInquiryBookingOutsideServiceArea(t *testing.T) {
+	f := newFlowFixture(t)
+	service := testutil.CreateInquiryService(t, "Siliguri", "West Bengal")
+	user := testutil.CreateUser(t, 0)
+
+	req := inquiryRequest(service)
+	req.Address.City = "Pune"
+	req.Address.State = "Maharashtra"
+	if _, _, err := f.bookings.CreateInquiryBooking(user.ID, req); err == nil {
+		t.Fatal("CreateInquiryBooking accepted an address outside the service area")
+	}
+}
+
+func TestFixedPriceBookingWithSurgeAndCoupon(t *testing.T) {
+	f := newFlowFixture(t)
+	db := testutil.RequireDB(t)
+	testutil.SetAdminConfig(t, "surge_pricing_enabled", "true")
+	testutil.SetAdminConfig(t, "booking_hold_time_minutes", "7")
+	service := testutil.CreateFixedPriceService(t, 1000, "Siliguri", "West Bengal")
+	testutil.CreateTreesIndiaWorker(t)
+	admin := testutil.CreateAdmin(t)
+	user := testutil.CreateUser(t, 0)
+
+	// Morning slots cost 200 more
+	start, end := "10:00", "12:00"
+	rule := &models.PricingRule{
+		Name:            "Morning rush",
+		IsActive:        true,
+		StartTime:       &start,
+		EndTime:         &end,
+		ServiceIDs:      pq.Int64Array{int64(service.ID)},
+		AdjustmentType:  models.PricingAdjustmentFlat,
+		AdjustmentValue: 200,
+	}
+	if err := db.Create(rule).Error; err != nil {
+		t.Fatalf("failed to create pricing rule: %v", err)
+	}
+
+	coupons := services.NewCouponService()
+	coupon, err := coupons.CreateCoupon(admin.ID, &models.CreateCouponRequest{
+		Code:          fmt.Sprintf("FIXED%d", service.ID),
+		DiscountType:  models.CouponDiscountFlat,
+		DiscountValue: 100,
+		Scope:         models.CouponScopeBooking,
+		ServiceIDs:    []int64{int64(service.ID)},
+	})
+	if err != nil {
+		t.Fatalf("CreateCoupon returned error: %v", err)
+	}
+
+	ist := time.FixedZone("IST", 5*60*60+30*60)
+	req := fixedPriceRequest(service, time.Now().In(ist).AddDate(0, 0, 1).Format("2006-01-02"), "10:00")
+	req.CouponCode = coupon.Code
+	before := time.Now()
+	booking, order, err := f.bookings.CreateBooking(user.ID, req)
+	if err != nil {
+		t.Fatalf("CreateBooking returned error: %v", err)
+	}
+
+	// The slot is held at the surge price until the payment completes
+	if booking.Status != models.BookingStatusTemporaryHold || booking.BookingType != models.BookingTypeRegular {
+		t.Errorf("booking status/type = %s/%s, want temporary_hold/regular", booking.Status, booking.BookingType)
+	}
+	if booking.HoldExpiresAt == nil || booking.HoldExpiresAt.Before(before.Add(6*time.Minute)) || booking.HoldExpiresAt.After(time.Now().Add(7*time.Minute)) {
+		t.Errorf("hold expires at %v, want 7 minutes from now", booking.HoldExpiresAt)
+	}
+	if booking.PriceBreakdown == nil || booking.PriceBreakdown.Total != 1200 || booking.PriceBreakdown.PricingRule == nil {
+		t.Fatalf("price breakdown = %+v, want 1200 with the pricing rule applied", booking.PriceBreakdown)
+	}
+	if booking.PricingRuleID == nil || *booking.PricingRuleID != rule.ID {
+		t.Errorf("booking pricing rule = %v, want %d", booking.PricingRuleID, rule.ID)
+	}
+
+	// The coupon comes off the surge price
+	if order["amount"] != int64(110000) {
+		t.Errorf("order amount = %v paise, want 110000", order["amount"])
+	}
+	payment, err := f.payments.GetPaymentByRazorpayOrderID(order["id"].(string))
+	if err != nil {
+		t.Fatalf("booking payment not found: %v", err)
+	}
+	if payment.Status != models.PaymentStatusPending || payment.Amount != 1100 || payment.RelatedEntityID != booking.ID {
+		t.Errorf("payment status/amount/booking = %s/%.2f/%d, want pending/1100/%d", payment.Status, payment.Amount, payment.RelatedEntityID, booking.ID)
+	}
+
+	redemptions, _, err := coupons.GetRedemptions(&coupon.ID, models.CouponRedemptionStatusReserved, 1, 10)
+	if err != nil {
+		t.Fatalf("GetRedemptions returned error: %v", err)
+	}
+	if len(redemptions) != 1 {
+		t.Fatalf("%d reserved redemptions, want 1", len(redemptions))
+	}
+	redemption := redemptions[0]
+	if redemption.RelatedEntityID != booking.ID || redemption.PaymentID == nil || *redemption.PaymentID != payment.ID || redemption.DiscountAmount != 100 {
+		t.Errorf("redemption booking/payment/discount = %d/%v/%.2f, want %d/%d/100", redemption.RelatedEntityID, redemption.PaymentID, redemption.DiscountAmount, booking.ID, payment.ID)
+	}
+
+	// An afternoon slot is outside the pricing rule
+	other := testutil.CreateUser(t, 0)
+	afternoon, _, err := f.bookings.CreateBooking(other.ID, fixedPriceRequest(service, req.ScheduledDate, "15:00"))
+	if err != nil {
+		t.Fatalf("CreateBooking returned error for the afternoon slot: %v", err)
+	}
+	if afternoon.PriceBreakdown == nil || afternoon.PriceBreakdown.Total != 1000 || afternoon.PricingRuleID != nil {
+		t.Errorf("afternoon price breakdown = %+v, want 1000 without a pricing rule", afternoon.PriceBreakdown)
+	}
+}
+
+func fixedPriceRequest(service *models.Service, date, slot string) *models.CreateBookingRequest {
+	return &models.CreateBookingRequest{
+		ServiceID:     service.ID,
+		ScheduledDate: date,
+		ScheduledTime: slot,
+		Address: models.BookingAddress{
+			Name:    "Home",
+			Address: "12 Hill Cart Road",
+			City:    "Siliguri",
+			State:   "West Bengal",
+			Country: "India",
+		},
+		ContactPerson: "Test User",
+		ContactPhone:  "+919000000001",
+	}
+}
@@ -12,7 +12,7 @@ import (
 
 // CallMaskingService handles call masking business logic
 type CallMaskingService struct {
-	exotelService    *ExotelService
+	exotelService    CallProvider
 	callMaskingRepo  *repositories.CallMaskingEnabledRepository
 	callLogRepo      *repositories.CallLogRepository
 	bookingRepo      *repositories.BookingRepository
@@ -21,8 +21,13 @@ type CallMaskingService struct {
 
 // NewCallMaskingService creates a new call masking service
 func NewCallMaskingService() *CallMaskingService {
+	return NewCallMaskingServiceWith(NewExotelService())
+}
+
+// NewCallMaskingServiceWith creates a call masking service that places calls through provider
+func NewCallMaskingServiceWith(provider CallProvider) *CallMaskingService {
 	return &CallMaskingService{
-		exotelService:   NewExotelService(),
+		exotelService:   provider,
 		callMaskingRepo: repositories.NewCallMaskingEnabledRepository(),
 		callLogRepo:     repositories.NewCallLogRepository(),
 		bookingRepo:     repositories.NewBookingRepository(),
@@ -0,0 +1,67 @@
+package services_test
+
+import (
+	"testing"
+	"treesindia/models"
+	"treesindia/repositories"
+	"treesindia/services"
+	"treesindia/testutil"
+)
+
+func TestMaskedCallBetweenCustomerAndWorker(t *testing.T) {
+	f := newFlowFixture(t)
+	testutil.SetAdminConfig(t, "inquiry_booking_fee", "0")
+	service := testutil.CreateInquiryService(t, "Siliguri", "West Bengal")
+	customer := testutil.CreateUser(t, 0)
+	worker := testutil.CreateUser(t, 0)
+	stranger := testutil.CreateUser(t, 0)
+
+	booking, _, err := f.bookings.CreateInquiryBooking(customer.ID, inquiryRequest(service))
+	if err != nil {
+		t.Fatalf("CreateInquiryBooking returned error: %v", err)
+	}
+	callMaskingRepo := repositories.NewCallMaskingEnabledRepository()
+	if err := callMaskingRepo.Create(&models.CallMaskingEnabled{
+		BookingID:  booking.ID,
+		WorkerID:   worker.ID,
+		CustomerID: customer.ID,
+	}); err != nil {
+		t.Fatalf("failed to enable call masking: %v", err)
+	}
+
+	provider := testutil.NewFakeCallProvider()
+	calls := services.NewCallMaskingServiceWith(provider)
+
+	if err := calls.InitiateCall(booking.ID, stranger.ID); err == nil {
+		t.Error("InitiateCall let a user outside the booking place a call")
+	}
+	if err := calls.InitiateCall(booking.ID, customer.ID); err != nil {
+		t.Fatalf("InitiateCall returned error: %v", err)
+	}
+
+	placed := provider.Calls()
+	if len(placed) != 1 || placed[0].From != customer.Phone || placed[0].To != worker.Phone {
+		t.Fatalf("calls placed = %v, want one call from %s to %s", placed, customer.Phone, worker.Phone)
+	}
+
+	// The provider reports the call as completed after 95 seconds
+	if err := calls.HandleCallWebhook(placed[0].ID, "completed", "95"); err != nil {
+		t.Fatalf("HandleCallWebhook returned error: %v", err)
+	}
+
+	callLog, err := repositories.NewCallLogRepository().GetByExotelCallSID(placed[0].ID)
+	if err != nil {
+		t.Fatalf("call log not found: %v", err)
+	}
+	if callLog.CallStatus != models.CallStatusCompleted || callLog.CallDuration != 95 || callLog.EndedAt == nil {
+		t.Errorf("call log status/duration/ended = %s/%d/%v, want completed/95/set", callLog.CallStatus, callLog.CallDuration, callLog.EndedAt)
+	}
+
+	callMasking, err := callMaskingRepo.GetByBookingID(booking.ID)
+	if err != nil {
+		t.Fatalf("failed to reload call masking: %v", err)
+	}
+	if callMasking.CallCount != 1 || callMasking.TotalCallDuration != 95 {
+		t.Errorf("call count/total duration = %d/%d, want 1/95", callMasking.CallCount, callMasking.TotalCallDuration)
+	}
+}
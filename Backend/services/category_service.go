@@ -15,22 +15,22 @@ import (
 
 // CategoryService handles category business logic
 type CategoryService struct {
-	categoryRepo      *repositories.CategoryRepository
-	cloudinaryService *CloudinaryService
-	validationHelper  *utils.ValidationHelper
+	categoryRepo     *repositories.CategoryRepository
+	mediaStore       MediaStore
+	validationHelper *utils.ValidationHelper
 }
 
 // NewCategoryService creates a new category service
 func NewCategoryService() (*CategoryService, error) {
-	cloudinaryService, err := NewCloudinaryService()
+	mediaStore, err := NewMediaStore()
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize cloudinary service: %w", err)
+		return nil, fmt.Errorf("failed to initialize media store: %w", err)
 	}
 
 	return &CategoryService{
-		categoryRepo:      repositories.NewCategoryRepository(),
-		cloudinaryService: cloudinaryService,
-		validationHelper:  utils.NewValidationHelper(),
+		categoryRepo:     repositories.NewCategoryRepository(),
+		mediaStore:       mediaStore,
+		validationHelper: utils.NewValidationHelper(),
 	}, nil
 }
 
@@ -123,7 +123,7 @@ func (cs *CategoryService) CreateCategory(req *CreateCategoryRequest, imageFile
 	// Handle image upload if provided
 	var iconURL string
 	if imageFile != nil {
-		logrus.Info("Image file provided, uploading to Cloudinary")
+		logrus.Info("Image file provided, uploading to media storage")
 		// Validate file size (max 10MB)
 		if err := cs.validationHelper.ValidateFileSize(imageFile.Size, 10*1024*1024); err != nil {
 			return nil, fmt.Errorf("file too large: %w", err)
@@ -135,9 +135,9 @@ func (cs *CategoryService) CreateCategory(req *CreateCategoryRequest, imageFile
 			return nil, fmt.Errorf("invalid file type: %w", err)
 		}
 
-		// Upload image to Cloudinary
+		// Upload image to media storage
 		var err error
-		iconURL, err = cs.cloudinaryService.UploadImage(imageFile, "categories")
+		iconURL, err = cs.mediaStore.UploadImage(imageFile, "categories")
 		if err != nil {
 			return nil, fmt.Errorf("failed to upload image: %w", err)
 		}
@@ -259,7 +259,7 @@ func (cs *CategoryService) UpdateCategory(id uint, req *CreateCategoryRequest, i
 
 	// Handle image upload if provided
 	if imageFile != nil {
-		logrus.Info("Image file provided, uploading to Cloudinary")
+		logrus.Info("Image file provided, uploading to media storage")
 		// Validate file size (max 10MB)
 		if err := cs.validationHelper.ValidateFileSize(imageFile.Size, 10*1024*1024); err != nil {
 			return nil, fmt.Errorf("file too large: %w", err)
@@ -271,8 +271,8 @@ func (cs *CategoryService) UpdateCategory(id uint, req *CreateCategoryRequest, i
 			return nil, fmt.Errorf("invalid file type: %w", err)
 		}
 
-		// Upload image to Cloudinary
-		iconURL, err := cs.cloudinaryService.UploadImage(imageFile, "categories")
+		// Upload image to media storage
+		iconURL, err := cs.mediaStore.UploadImage(imageFile, "categories")
 		if err != nil {
 			return nil, fmt.Errorf("failed to upload image: %w", err)
 		}
@@ -452,9 +452,9 @@ func (cs *CategoryService) GetChildrenByParentID(parentID uint) ([]models.Catego
 	return categories, nil
 }
 
-// GetCloudinaryService returns the Cloudinary service instance
-func (cs *CategoryService) GetCloudinaryService() *CloudinaryService {
-	return cs.cloudinaryService
+// GetMediaStore returns the media store instance
+func (cs *CategoryService) GetMediaStore() MediaStore {
+	return cs.mediaStore
 }
 
 // loadChildrenRecursively recursively loads all children for all categories
@@ -24,10 +24,27 @@ type ChatbotService struct {
 	serviceRepo    *repositories.ServiceRepository
 	projectRepo    *repositories.ProjectRepository
 	userRepo       *repositories.UserRepository
-	config         *config.AppConfig
+	chatClient     ChatCompleter
 	prompts        *ChatbotPrompts
 }
 
+// ChatCompleter generates the assistant's reply to a chat. OpenAIClient is the production completer.
+type ChatCompleter interface {
+	Complete(messages []OpenAIMessage) (string, error)
+}
+
+// OpenAIClient completes chats with the OpenAI chat completions API
+type OpenAIClient struct {
+	config *config.AppConfig
+}
+
+// NewOpenAIClient creates an OpenAI client using the model and key in appConfig
+func NewOpenAIClient(appConfig *config.AppConfig) *OpenAIClient {
+	return &OpenAIClient{
+		config: appConfig,
+	}
+}
+
 type OpenAIMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
@@ -61,13 +78,18 @@ type OpenAIResponse struct {
 }
 
 func NewChatbotService() *ChatbotService {
+	return NewChatbotServiceWith(NewOpenAIClient(config.LoadConfig()))
+}
+
+// NewChatbotServiceWith creates a chatbot service that generates replies with chatClient
+func NewChatbotServiceWith(chatClient ChatCompleter) *ChatbotService {
 	return &ChatbotService{
 		ChatbotRepo:  repositories.NewChatbotRepository(),
 		propertyRepo: repositories.NewPropertyRepository(),
 		serviceRepo:  repositories.NewServiceRepository(),
 		projectRepo:  repositories.NewProjectRepository(),
 		userRepo:     repositories.NewUserRepository(),
-		config:       config.LoadConfig(),
+		chatClient:   chatClient,
 		prompts:      NewChatbotPrompts(),
 	}
 }
@@ -864,15 +886,20 @@ func (s *ChatbotService) callOpenAI(prompt string) (string, error) {
 
 // callOpenAIWithMessages makes a request to OpenAI API with message history
 func (s *ChatbotService) callOpenAIWithMessages(messages []OpenAIMessage) (string, error) {
-	if s.config.OpenAIAPIKey == "" {
+	return s.chatClient.Complete(messages)
+}
+
+// Complete sends the chat to OpenAI and returns the reply
+func (c *OpenAIClient) Complete(messages []OpenAIMessage) (string, error) {
+	if c.config.OpenAIAPIKey == "" {
 		return "I'm sorry, but the AI service is currently unavailable. Please try again later.", nil
 	}
 	
 	requestBody := OpenAIRequest{
-		Model:       s.config.OpenAIModel,
+		Model:       c.config.OpenAIModel,
 		Messages:    messages,
-		MaxTokens:   s.config.OpenAIMaxTokens,
-		Temperature: s.config.OpenAITemperature,
+		MaxTokens:   c.config.OpenAIMaxTokens,
+		Temperature: c.config.OpenAITemperature,
 	}
 	
 	jsonData, err := json.Marshal(requestBody)
@@ -886,7 +913,7 @@ func (s *ChatbotService) callOpenAIWithMessages(messages []OpenAIMessage) (strin
 	}
 	
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+s.config.OpenAIAPIKey)
+	req.Header.Set("Authorization", "Bearer "+c.config.OpenAIAPIKey)
 	
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
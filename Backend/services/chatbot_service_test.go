@@ -0,0 +1,69 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"treesindia/models"
+	"treesindia/services"
+	"treesindia/testutil"
+)
+
+func TestChatbotRepliesWithCompletion(t *testing.T) {
+	testutil.RequireDB(t)
+	completer := testutil.NewFakeChatCompleter("We have plumbers available in Siliguri.")
+	chatbot := services.NewChatbotServiceWith(completer)
+
+	session, err := chatbot.CreateSession(&models.CreateChatbotSessionRequest{Location: "Siliguri"})
+	if err != nil {
+		t.Fatalf("CreateSession returned error: %v", err)
+	}
+
+	response, err := chatbot.SendMessage(session.SessionID, &models.SendChatbotMessageRequest{
+		SessionID: session.SessionID,
+		Message:   "Do you have plumbers?",
+	})
+	if err != nil {
+		t.Fatalf("SendMessage returned error: %v", err)
+	}
+	if response.Role != "assistant" || response.Content != completer.Reply {
+		t.Errorf("response role/content = %s/%q, want assistant/%q", response.Role, response.Content, completer.Reply)
+	}
+
+	// The question is sent to the completer with the conversation so far
+	asked := false
+	for _, chat := range completer.Chats() {
+		for _, message := range chat {
+			if message.Role == "user" && message.Content == "Do you have plumbers?" {
+				asked = true
+			}
+		}
+	}
+	if !asked {
+		t.Error("the question was never sent to the chat completer")
+	}
+}
+
+func TestChatbotFallsBackWhenCompletionFails(t *testing.T) {
+	testutil.RequireDB(t)
+	completer := &testutil.FakeChatCompleter{Err: errors.New("service unavailable")}
+	chatbot := services.NewChatbotServiceWith(completer)
+
+	session, err := chatbot.CreateSession(&models.CreateChatbotSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession returned error: %v", err)
+	}
+
+	response, err := chatbot.SendMessage(session.SessionID, &models.SendChatbotMessageRequest{
+		SessionID: session.SessionID,
+		Message:   "Hello",
+	})
+	if err != nil {
+		t.Fatalf("SendMessage returned error: %v", err)
+	}
+	if response.Content == "" {
+		t.Error("SendMessage returned an empty reply when the completion failed")
+	}
+	if len(response.Suggestions) == 0 {
+		t.Error("SendMessage returned no suggestions when the completion failed")
+	}
+}
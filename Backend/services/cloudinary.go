@@ -3,9 +3,12 @@ package services
 import (
 	"context"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 	"treesindia/config"
@@ -17,7 +20,7 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// CloudinaryService handles image upload and management
+// CloudinaryService handles image upload and management. It implements MediaStore.
 type CloudinaryService struct {
 	cld *cloudinary.Cloudinary
 }
@@ -233,9 +236,15 @@ func (cs *CloudinaryService) UploadMedia(file *multipart.FileHeader, folder stri
 
 // DeleteMedia deletes a media file (image or video) from Cloudinary
 func (cs *CloudinaryService) DeleteMedia(publicID string, resourceType string) error {
+	return cs.destroy(publicID, resourceType, api.Upload)
+}
+
+// destroy deletes an asset of the given delivery type
+func (cs *CloudinaryService) destroy(publicID, resourceType string, deliveryType api.DeliveryType) error {
 	ctx := context.Background()
 	result, err := cs.cld.Upload.Destroy(ctx, uploader.DestroyParams{
 		PublicID:     publicID,
+		Type:         deliveryType.String(),
 		ResourceType: resourceType,
 	})
 
@@ -247,30 +256,117 @@ func (cs *CloudinaryService) DeleteMedia(publicID string, resourceType string) e
 	return nil
 }
 
-// GetPublicIDFromURL extracts the public ID from a Cloudinary URL
-func (cs *CloudinaryService) GetPublicIDFromURL(url string) string {
+// UploadReader uploads media read from r to Cloudinary under the given file name
+func (cs *CloudinaryService) UploadReader(r io.Reader, folder, filename, mediaType string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	resourceType := mediaType
+	if resourceType != "image" && resourceType != "video" {
+		resourceType = "auto"
+	}
+
+	ext := filepath.Ext(filename)
+	publicID := fmt.Sprintf("%s_%d", strings.TrimSuffix(filepath.Base(filename), ext), time.Now().UnixNano())
+
+	result, err := cs.cld.Upload.Upload(ctx, r, uploader.UploadParams{
+		PublicID:     publicID,
+		Folder:       folder,
+		ResourceType: resourceType,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to upload %s to Cloudinary: %v", filename, err)
+		return "", fmt.Errorf("failed to upload media to Cloudinary: %v", err)
+	}
+
+	logrus.Infof("Successfully uploaded %s to Cloudinary: %s", filename, result.SecureURL)
+	return result.SecureURL, nil
+}
+
+// Put uploads media to Cloudinary under an exact public ID. Image and video public IDs don't
+// include the file extension. Private media is uploaded with the authenticated delivery type, so
+// it can only be fetched through SignedURL.
+func (cs *CloudinaryService) Put(r io.Reader, key, contentType string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	resourceType := "raw"
+	publicID := key
+	if strings.HasPrefix(contentType, "image/") || strings.HasPrefix(contentType, "video/") {
+		resourceType = strings.SplitN(contentType, "/", 2)[0]
+		publicID = strings.TrimSuffix(key, path.Ext(key))
+	}
+
+	deliveryType := api.Upload
+	if IsPrivateMediaKey(key) {
+		deliveryType = api.Authenticated
+	}
+
+	overwrite := true
+	result, err := cs.cld.Upload.Upload(ctx, r, uploader.UploadParams{
+		PublicID:     publicID,
+		Type:         deliveryType,
+		ResourceType: resourceType,
+		Overwrite:    &overwrite,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to upload %s to Cloudinary: %v", key, err)
+		return "", fmt.Errorf("failed to upload media to Cloudinary: %v", err)
+	}
+
+	return result.SecureURL, nil
+}
+
+// Backend returns the name of the storage backend
+func (cs *CloudinaryService) Backend() string {
+	return MediaBackendCloudinary
+}
+
+// Delete deletes media from Cloudinary by its URL
+func (cs *CloudinaryService) Delete(url string) error {
+	publicID := cs.KeyFromURL(url)
+	if publicID == "" {
+		return fmt.Errorf("media URL is not from Cloudinary: %s", url)
+	}
+	return cs.destroy(publicID, cs.GetResourceTypeFromURL(url), cloudinaryDeliveryType(url))
+}
+
+// KeyFromURL extracts the public ID from a Cloudinary URL
+func (cs *CloudinaryService) KeyFromURL(url string) string {
 	// Example URL: https://res.cloudinary.com/dxw83r0h4/image/upload/v1234567890/categories/plumbing.jpg
-	parts := strings.Split(url, "/")
-	if len(parts) < 2 {
+	if !strings.Contains(url, "res.cloudinary.com/") {
 		return ""
 	}
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
 
-	// Find the index after "upload"
+	parts := strings.Split(url, "/")
+
+	// Find the index after the delivery type
 	uploadIndex := -1
 	for i, part := range parts {
-		if part == "upload" {
+		if part == "upload" || part == api.Authenticated {
 			uploadIndex = i
 			break
 		}
 	}
-
-	if uploadIndex == -1 || uploadIndex+2 >= len(parts) {
+	if uploadIndex == -1 || uploadIndex+1 >= len(parts) {
 		return ""
 	}
 
-	// Skip version number and get the rest
-	pathParts := parts[uploadIndex+2:]
-	return strings.Join(pathParts, "/")
+	// Skip the version number if present
+	pathParts := parts[uploadIndex+1:]
+	if len(pathParts) > 1 && cloudinaryVersionPattern.MatchString(pathParts[0]) {
+		pathParts = pathParts[1:]
+	}
+
+	// Public IDs of images and videos don't include the format extension
+	publicID := strings.Join(pathParts, "/")
+	if cs.GetResourceTypeFromURL(url) != "raw" {
+		publicID = strings.TrimSuffix(publicID, path.Ext(publicID))
+	}
+	return publicID
 }
 
 // GetResourceTypeFromURL extracts the resource type from a Cloudinary URL
@@ -279,9 +375,9 @@ func (cs *CloudinaryService) GetResourceTypeFromURL(url string) string {
 	// or: https://res.cloudinary.com/dxw83r0h4/video/upload/v1234567890/hero/video.mp4
 	parts := strings.Split(url, "/")
 	
-	// Find the resource type (image or video) before "upload"
+	// Find the resource type (image or video) before the delivery type
 	for i, part := range parts {
-		if part == "upload" && i > 0 {
+		if (part == "upload" || part == api.Authenticated) && i > 0 {
 			return parts[i-1]
 		}
 	}
@@ -289,6 +385,90 @@ func (cs *CloudinaryService) GetResourceTypeFromURL(url string) string {
 	return "image" // default to image if not found
 }
 
+var cloudinaryVersionPattern = regexp.MustCompile(`^v\d+$`)
+
+// cloudinaryDeliveryType returns the delivery type of a Cloudinary URL
+func cloudinaryDeliveryType(url string) api.DeliveryType {
+	if strings.Contains(url, "/"+api.Authenticated+"/") {
+		return api.Authenticated
+	}
+	return api.Upload
+}
+
+// PublicURL returns the delivery URL of an image public ID
+func (cs *CloudinaryService) PublicURL(key string) string {
+	return fmt.Sprintf("https://res.cloudinary.com/%s/image/upload/%s", cs.cld.Config.Cloud.CloudName, key)
+}
+
+// SignedURL returns a URL that grants temporary read access to media. Authenticated (private)
+// media gets a signed download URL that expires; public media is returned unchanged.
+func (cs *CloudinaryService) SignedURL(url string, expiry time.Duration) (string, error) {
+	publicID := cs.KeyFromURL(url)
+	if publicID == "" {
+		return "", fmt.Errorf("media URL is not from Cloudinary: %s", url)
+	}
+	if cloudinaryDeliveryType(url) != api.Authenticated {
+		return url, nil
+	}
+
+	// Raw public IDs keep their extension; images and videos are downloaded in their stored format
+	resourceType := cs.GetResourceTypeFromURL(url)
+	format := ""
+	if resourceType != "raw" {
+		format = strings.TrimPrefix(path.Ext(strings.SplitN(url, "?", 2)[0]), ".")
+	}
+
+	expiresAt := time.Now().Add(expiry)
+	signed, err := cs.cld.Upload.PrivateDownloadURL(uploader.PrivateDownloadURLParams{
+		PublicID:     publicID,
+		Format:       format,
+		DeliveryType: api.Authenticated,
+		ExpiresAt:    &expiresAt,
+		ResourceType: api.AssetType(resourceType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign media URL: %v", err)
+	}
+	return signed, nil
+}
+
+// ListMedia calls fn for every image, video and raw file in the Cloudinary account
+func (cs *CloudinaryService) ListMedia(fn func(MediaObject) error) error {
+	ctx := context.Background()
+
+	for _, assetType := range []api.AssetType{api.Image, api.Video, api.AssetType("raw")} {
+		nextCursor := ""
+		for {
+			result, err := cs.cld.Admin.Assets(ctx, admin.AssetsParams{
+				AssetType:  assetType,
+				MaxResults: 500,
+				NextCursor: nextCursor,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to list %s resources: %v", assetType, err)
+			}
+
+			for _, asset := range result.Assets {
+				err := fn(MediaObject{
+					Key:          asset.PublicID,
+					URL:          asset.SecureURL,
+					Size:         int64(asset.Bytes),
+					LastModified: asset.CreatedAt,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			if result.NextCursor == "" {
+				break
+			}
+			nextCursor = result.NextCursor
+		}
+	}
+	return nil
+}
+
 // DeleteAllResources deletes all resources from Cloudinary
 // This uses the Admin API to list and delete all resources
 func (cs *CloudinaryService) DeleteAllResources() error {
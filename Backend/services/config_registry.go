@@ -294,6 +294,478 @@ func (cr *ConfigRegistry) registerDefaultSchemas() {
 		MaxValue:    10000,
 		Unit:        "INR",
 	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "quote_validity_days",
+		Type:        "int",
+		Category:    "booking",
+		Description: "Number of days a quote stays valid before it expires automatically",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    90,
+		Unit:        "days",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "quote_expiry_warning_hours",
+		Type:        "int",
+		Category:    "booking",
+		Description: "Hours before expiry at which the customer is warned about an unanswered quote",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    168,
+		Unit:        "hours",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "inquiry_fee_expiry_policy",
+		Type:        "string",
+		Category:    "booking",
+		Description: "What happens to a held inquiry fee when the quote expires",
+		Required:    false,
+		Options:     []string{"refund", "wallet_credit", "retain"},
+	})
+
+	// Payment Segments
+	cr.registerSchema(ConfigSchema{
+		Key:         "payment_segment_overdue_grace_hours",
+		Type:        "int",
+		Category:    "payment",
+		Description: "Hours after a payment segment falls due before it is marked overdue",
+		Required:    false,
+		MinValue:    0,
+		MaxValue:    720,
+		Unit:        "hours",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "payment_segment_reminder_interval_hours",
+		Type:        "int",
+		Category:    "payment",
+		Description: "Minimum hours between payment reminders for the same segment",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    168,
+		Unit:        "hours",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "payment_segment_max_reminders",
+		Type:        "int",
+		Category:    "payment",
+		Description: "Maximum number of payment reminders sent for a segment",
+		Required:    false,
+		MinValue:    0,
+		MaxValue:    30,
+	})
+
+	// Referrals
+	cr.registerSchema(ConfigSchema{
+		Key:         "referral_referrer_reward",
+		Type:        "float",
+		Category:    "wallet",
+		Description: "Wallet credit for a referrer when their referee completes a first booking (0 disables)",
+		Required:    false,
+		MinValue:    0.0,
+		MaxValue:    10000.0,
+		Unit:        "INR",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "referral_referee_reward",
+		Type:        "float",
+		Category:    "wallet",
+		Description: "Wallet credit for a referred user on their first completed booking (0 disables)",
+		Required:    false,
+		MinValue:    0.0,
+		MaxValue:    10000.0,
+		Unit:        "INR",
+	})
+
+	// Dashboard
+	cr.registerSchema(ConfigSchema{
+		Key:         "dashboard_unassigned_booking_hours",
+		Type:        "int",
+		Category:    "system",
+		Description: "Hours before a booking's start at which it is flagged if no worker has accepted it",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    48,
+		Unit:        "hours",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "dashboard_withdrawal_pending_hours",
+		Type:        "int",
+		Category:    "system",
+		Description: "Hours a worker withdrawal can stay pending before it is flagged on the dashboard",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    720,
+		Unit:        "hours",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "dashboard_property_approval_hours",
+		Type:        "int",
+		Category:    "system",
+		Description: "Hours a property can wait for approval before it is flagged as high priority",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    720,
+		Unit:        "hours",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "dashboard_role_application_hours",
+		Type:        "int",
+		Category:    "system",
+		Description: "Hours a role application can wait for review before it is flagged as high priority",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    720,
+		Unit:        "hours",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "dashboard_inquiry_quote_hours",
+		Type:        "int",
+		Category:    "system",
+		Description: "Hours an inquiry booking can wait for a quote before it is flagged as high priority",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    720,
+		Unit:        "hours",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "dashboard_failed_push_window_hours",
+		Type:        "int",
+		Category:    "system",
+		Description: "Window in hours over which failed push notifications are counted for system alerts",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    168,
+		Unit:        "hours",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "dashboard_failed_push_threshold",
+		Type:        "int",
+		Category:    "system",
+		Description: "Failed push notifications within the window that raise a system alert",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    10000,
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "dashboard_unanswered_chat_hours",
+		Type:        "int",
+		Category:    "system",
+		Description: "Hours a chat message can stay unread before its room is flagged as unanswered",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    168,
+		Unit:        "hours",
+	})
+
+	// Search
+	cr.registerSchema(ConfigSchema{
+		Key:         "search_fuzzy_threshold",
+		Type:        "float",
+		Category:    "system",
+		Description: "Minimum trigram word similarity for a misspelt search term to still match (lower is more forgiving)",
+		Required:    false,
+		MinValue:    0.1,
+		MaxValue:    1.0,
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "search_attribution_window_hours",
+		Type:        "int",
+		Category:    "system",
+		Description: "Hours after opening a search result within which a booking of that service counts as a search conversion",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    720,
+		Unit:        "hours",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "search_suggestion_min_users",
+		Type:        "int",
+		Category:    "system",
+		Description: "Different signed-in users who must search a query before it is shown as a public search suggestion",
+		Required:    false,
+		MinValue:    2,
+		MaxValue:    1000,
+		Unit:        "users",
+	})
+
+	// Media
+	cr.registerSchema(ConfigSchema{
+		Key:         "duplicate_image_max_distance",
+		Type:        "int",
+		Category:    "system",
+		Description: "Maximum perceptual hash distance (0-64 bits) at which two listing photos are flagged as duplicates",
+		Required:    false,
+		MinValue:    0,
+		MaxValue:    32,
+		Unit:        "bits",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "media_gc_dry_run",
+		Type:        "bool",
+		Category:    "system",
+		Description: "When enabled the scheduled media garbage collector only reports orphaned media instead of deleting it",
+		Required:    false,
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "media_gc_grace_hours",
+		Type:        "int",
+		Category:    "system",
+		Description: "Hours an uploaded file may stay unreferenced before the media garbage collector treats it as orphaned",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    720,
+		Unit:        "hours",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "chat_media_retention_days",
+		Type:        "int",
+		Category:    "system",
+		Description: "Days chat image and video attachments are kept before the media garbage collector removes them",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    3650,
+		Unit:        "days",
+	})
+
+	// Surge pricing
+	cr.registerSchema(ConfigSchema{
+		Key:         "surge_pricing_enabled",
+		Type:        "bool",
+		Category:    "booking",
+		Description: "Apply active pricing rules to fixed price bookings and available slot prices",
+		Required:    false,
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "surge_max_multiplier",
+		Type:        "float",
+		Category:    "booking",
+		Description: "Highest a pricing rule may raise a booking price, as a multiple of the normal price",
+		Required:    false,
+		MinValue:    1.0,
+		MaxValue:    5.0,
+	})
+
+	// Account data
+	cr.registerSchema(ConfigSchema{
+		Key:         "account_deletion_grace_days",
+		Type:        "int",
+		Category:    "system",
+		Description: "Days after a user asks to delete their account before their personal data is anonymised; they can cancel until then",
+		Required:    false,
+		MinValue:    0,
+		MaxValue:    90,
+		Unit:        "days",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "data_export_retention_hours",
+		Type:        "int",
+		Category:    "system",
+		Description: "Hours a user's data export archive can be downloaded before it is deleted",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    720,
+		Unit:        "hours",
+	})
+
+	// Worker tracking
+	cr.registerSchema(ConfigSchema{
+		Key:         "worker_arrival_radius_meters",
+		Type:        "int",
+		Category:    "booking",
+		Description: "Distance from the booking address within which a tracked worker is marked as arrived",
+		Required:    false,
+		MinValue:    20,
+		MaxValue:    2000,
+		Unit:        "meters",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "worker_eta_refresh_seconds",
+		Type:        "int",
+		Category:    "booking",
+		Description: "Seconds between ETA recalculations for a tracked worker",
+		Required:    false,
+		MinValue:    15,
+		MaxValue:    900,
+		Unit:        "seconds",
+	})
+
+	// Service PINs
+	cr.registerSchema(ConfigSchema{
+		Key:         "service_pin_max_attempts",
+		Type:        "int",
+		Category:    "booking",
+		Description: "Incorrect start or completion PINs a worker can enter before PIN entry is locked for the booking",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    20,
+		Unit:        "attempts",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "service_pin_lock_minutes",
+		Type:        "int",
+		Category:    "booking",
+		Description: "Minutes PIN entry stays locked after too many incorrect PINs",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    1440,
+		Unit:        "minutes",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "service_auto_complete_hours",
+		Type:        "int",
+		Category:    "booking",
+		Description: "Hours after a service was started before it is completed automatically if the worker never completes it",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    720,
+		Unit:        "hours",
+	})
+
+	// Worker auto-reassignment
+	cr.registerSchema(ConfigSchema{
+		Key:         "assignment_acceptance_sla_minutes",
+		Type:        "int",
+		Category:    "booking",
+		Description: "Minutes a worker has to accept an assigned booking before it is offered to another worker",
+		Required:    false,
+		MinValue:    5,
+		MaxValue:    1440,
+		Unit:        "minutes",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "assignment_max_auto_offers",
+		Type:        "int",
+		Category:    "booking",
+		Description: "Rejected or expired offers of a booking after which it is escalated to admins instead of offered to another worker",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    20,
+		Unit:        "offers",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "police_verification_validity_months",
+		Type:        "int",
+		Category:    "system",
+		Description: "Months a verified police verification stays valid when the reviewer does not set an expiry date",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    60,
+		Unit:        "months",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "site_visit_reminder_hours",
+		Type:        "int",
+		Category:    "system",
+		Description: "Hours before a property site visit that the buyer and listing owner are reminded about it",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    72,
+		Unit:        "hours",
+	})
+
+	// Listing renewal, boosts and featured slots
+	cr.registerSchema(ConfigSchema{
+		Key:         "property_renewal_window_days",
+		Type:        "int",
+		Category:    "property",
+		Description: "Days before a listing expires that its owner can renew it",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    60,
+		Unit:        "days",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "property_boost_price",
+		Type:        "float",
+		Category:    "property",
+		Description: "Price of a listing boost",
+		Required:    false,
+		MinValue:    0.0,
+		MaxValue:    100000.0,
+		Unit:        "INR",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "property_boost_days",
+		Type:        "int",
+		Category:    "property",
+		Description: "Days a listing boost lasts",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    90,
+		Unit:        "days",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "property_boost_points",
+		Type:        "int",
+		Category:    "property",
+		Description: "Priority a boost adds to a listing when bought; it decays to 0 by the end of the boost",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    1000,
+		Unit:        "points",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "property_featured_price",
+		Type:        "float",
+		Category:    "property",
+		Description: "Price of a featured slot for a listing",
+		Required:    false,
+		MinValue:    0.0,
+		MaxValue:    100000.0,
+		Unit:        "INR",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "property_featured_days",
+		Type:        "int",
+		Category:    "property",
+		Description: "Days a featured slot lasts",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    90,
+		Unit:        "days",
+	})
+
+	cr.registerSchema(ConfigSchema{
+		Key:         "property_featured_slots_per_city",
+		Type:        "int",
+		Category:    "property",
+		Description: "Listings that can be featured at the same time in a city",
+		Required:    false,
+		MinValue:    1,
+		MaxValue:    100,
+		Unit:        "listings",
+	})
 }
 
 // registerSchema registers a configuration schema
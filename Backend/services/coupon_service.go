@@ -0,0 +1,516 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]+$`)
+
+// CouponService handles promo codes and their redemptions
+type CouponService struct {
+	couponRepo   *repositories.CouponRepository
+	referralRepo *repositories.ReferralRepository
+	bookingRepo  *repositories.BookingRepository
+	serviceRepo  *repositories.ServiceRepository
+	planRepo     *repositories.SubscriptionPlanRepository
+}
+
+// NewCouponService creates a new coupon service
+func NewCouponService() *CouponService {
+	return &CouponService{
+		couponRepo:   repositories.NewCouponRepository(),
+		referralRepo: repositories.NewReferralRepository(),
+		bookingRepo:  repositories.NewBookingRepository(),
+		serviceRepo:  repositories.NewServiceRepository(),
+		planRepo:     repositories.NewSubscriptionPlanRepository(),
+	}
+}
+
+// CouponCheckout describes the purchase a coupon is applied to
+type CouponCheckout struct {
+	UserID     uint
+	Scope      models.CouponScope
+	Amount     float64
+	ServiceID  uint   // Bookings and quotes
+	CategoryID uint   // Bookings and quotes
+	City       string // Bookings and quotes
+	BookingID  uint   // Existing booking, excluded from the first booking check
+}
+
+// CouponDiscount is the result of applying a coupon to a purchase
+type CouponDiscount struct {
+	CouponID       uint    `json:"coupon_id"`
+	Code           string  `json:"code"`
+	Description    string  `json:"description"`
+	OriginalAmount float64 `json:"original_amount"`
+	DiscountAmount float64 `json:"discount_amount"`
+	FinalAmount    float64 `json:"final_amount"`
+}
+
+// PaymentMetadata adds the discount to a payment's metadata
+func (cd *CouponDiscount) PaymentMetadata(metadata *models.JSONMap) *models.JSONMap {
+	if metadata == nil {
+		metadata = &models.JSONMap{}
+	}
+	(*metadata)["coupon_id"] = cd.CouponID
+	(*metadata)["coupon_code"] = cd.Code
+	(*metadata)["original_amount"] = cd.OriginalAmount
+	(*metadata)["discount_amount"] = cd.DiscountAmount
+	return metadata
+}
+
+// CalculateDiscount validates a coupon for a purchase and works out the discount
+func (cs *CouponService) CalculateDiscount(code string, checkout *CouponCheckout) (*CouponDiscount, error) {
+	coupon, err := cs.couponRepo.GetByCode(code)
+	if err != nil {
+		return nil, errors.New("invalid coupon code")
+	}
+
+	if err := cs.checkEligibility(coupon, checkout); err != nil {
+		return nil, err
+	}
+
+	// Overall and per-user limits
+	if coupon.UsageLimit != nil {
+		used, err := cs.couponRepo.CountRedemptions(coupon.ID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check coupon usage: %v", err)
+		}
+		if used >= int64(*coupon.UsageLimit) {
+			return nil, errors.New("coupon usage limit reached")
+		}
+	}
+	if coupon.PerUserLimit > 0 {
+		used, err := cs.couponRepo.CountRedemptions(coupon.ID, &checkout.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check coupon usage: %v", err)
+		}
+		if used >= int64(coupon.PerUserLimit) {
+			return nil, errors.New("you have already used this coupon")
+		}
+	}
+
+	// Work out the discount
+	var discount float64
+	if coupon.DiscountType == models.CouponDiscountPercentage {
+		discount = checkout.Amount * coupon.DiscountValue / 100
+		if coupon.MaxDiscount != nil && discount > *coupon.MaxDiscount {
+			discount = *coupon.MaxDiscount
+		}
+	} else {
+		discount = coupon.DiscountValue
+	}
+	discount = math.Min(math.Round(discount*100)/100, checkout.Amount)
+
+	finalAmount := math.Round((checkout.Amount-discount)*100) / 100
+	if finalAmount <= 0 {
+		return nil, errors.New("coupon cannot cover the full amount")
+	}
+
+	return &CouponDiscount{
+		CouponID:       coupon.ID,
+		Code:           coupon.Code,
+		Description:    coupon.Description,
+		OriginalAmount: checkout.Amount,
+		DiscountAmount: discount,
+		FinalAmount:    finalAmount,
+	}, nil
+}
+
+// checkEligibility checks a coupon's validity window, scope and targeting
+func (cs *CouponService) checkEligibility(coupon *models.Coupon, checkout *CouponCheckout) error {
+	now := time.Now()
+	if !coupon.IsActive {
+		return errors.New("coupon is not active")
+	}
+	if coupon.ValidFrom != nil && now.Before(*coupon.ValidFrom) {
+		return errors.New("coupon is not valid yet")
+	}
+	if coupon.ValidUntil != nil && now.After(*coupon.ValidUntil) {
+		return errors.New("coupon has expired")
+	}
+
+	if coupon.Scope != models.CouponScopeAll && coupon.Scope != checkout.Scope {
+		return fmt.Errorf("coupon can only be applied to %s purchases", coupon.Scope)
+	}
+
+	if checkout.Amount < coupon.MinOrderAmount {
+		return fmt.Errorf("minimum order amount for this coupon is ₹%.2f", coupon.MinOrderAmount)
+	}
+
+	if len(coupon.CategoryIDs) > 0 && !containsID(coupon.CategoryIDs, checkout.CategoryID) {
+		return errors.New("coupon is not valid for this service")
+	}
+	if len(coupon.ServiceIDs) > 0 && !containsID(coupon.ServiceIDs, checkout.ServiceID) {
+		return errors.New("coupon is not valid for this service")
+	}
+	if len(coupon.Cities) > 0 {
+		eligible := false
+		for _, city := range coupon.Cities {
+			if checkout.City != "" && strings.EqualFold(strings.TrimSpace(city), strings.TrimSpace(checkout.City)) {
+				eligible = true
+				break
+			}
+		}
+		if !eligible {
+			return errors.New("coupon is not valid in your city")
+		}
+	}
+
+	if coupon.FirstBookingOnly {
+		if checkout.Scope == models.CouponScopeSubscription {
+			return errors.New("coupon is only valid on your first booking")
+		}
+		count, err := cs.bookingRepo.CountPaidBookingsByUser(checkout.UserID, checkout.BookingID)
+		if err != nil {
+			return fmt.Errorf("failed to check previous bookings: %v", err)
+		}
+		if count > 0 {
+			return errors.New("coupon is only valid on your first booking")
+		}
+	}
+
+	return nil
+}
+
+// Reserve records a coupon against a purchase whose payment has not completed yet
+func (cs *CouponService) Reserve(discount *CouponDiscount, checkout *CouponCheckout, relatedEntityType string, relatedEntityID uint) (*models.CouponRedemption, error) {
+	redemption := &models.CouponRedemption{
+		CouponID:          discount.CouponID,
+		Code:              discount.Code,
+		UserID:            checkout.UserID,
+		Scope:             checkout.Scope,
+		Status:            models.CouponRedemptionStatusReserved,
+		RelatedEntityType: relatedEntityType,
+		RelatedEntityID:   relatedEntityID,
+		OriginalAmount:    discount.OriginalAmount,
+		DiscountAmount:    discount.DiscountAmount,
+		FinalAmount:       discount.FinalAmount,
+	}
+
+	if err := cs.couponRepo.CreateRedemption(redemption); err != nil {
+		return nil, err
+	}
+
+	return redemption, nil
+}
+
+// AttachPayment links a reserved redemption to the payment created for it
+func (cs *CouponService) AttachPayment(redemption *models.CouponRedemption, paymentID uint) {
+	redemption.PaymentID = &paymentID
+	if err := cs.couponRepo.AttachPayment(redemption.ID, paymentID); err != nil {
+		logrus.Errorf("Failed to link coupon redemption %d to payment %d: %v", redemption.ID, paymentID, err)
+	}
+}
+
+// ApplyForPayment marks the coupon used on a payment as applied once the payment completes
+func (cs *CouponService) ApplyForPayment(paymentID uint) {
+	applied, err := cs.couponRepo.ApplyByPaymentID(paymentID, time.Now())
+	if err != nil {
+		logrus.Errorf("Failed to apply coupon redemption for payment %d: %v", paymentID, err)
+		return
+	}
+	if applied > 0 {
+		logrus.Infof("Applied coupon redemption for payment %d", paymentID)
+	}
+}
+
+// ApplyRedemption marks a reserved redemption as applied when there is no payment record to key it on
+func (cs *CouponService) ApplyRedemption(redemption *models.CouponRedemption) {
+	if err := cs.couponRepo.ApplyByID(redemption.ID, time.Now()); err != nil {
+		logrus.Errorf("Failed to apply coupon redemption %d: %v", redemption.ID, err)
+	}
+}
+
+// Release gives back coupons reserved on a purchase that was abandoned or re-ordered
+func (cs *CouponService) Release(relatedEntityType string, relatedEntityID uint, userID uint) {
+	released, err := cs.couponRepo.ReleaseReserved(relatedEntityType, relatedEntityID, userID, time.Now())
+	if err != nil {
+		logrus.Errorf("Failed to release coupon redemptions for %s %d: %v", relatedEntityType, relatedEntityID, err)
+		return
+	}
+	if released > 0 {
+		logrus.Infof("Released %d coupon redemption(s) for %s %d", released, relatedEntityType, relatedEntityID)
+	}
+}
+
+// PreviewCoupon validates a coupon for the customer before checkout
+func (cs *CouponService) PreviewCoupon(userID uint, req *models.ValidateCouponRequest) (*CouponDiscount, error) {
+	checkout := &CouponCheckout{
+		UserID: userID,
+		Scope:  req.Scope,
+		Amount: req.Amount,
+		City:   req.City,
+	}
+
+	switch req.Scope {
+	case models.CouponScopeBooking:
+		if req.ServiceID == nil {
+			return nil, errors.New("service_id is required")
+		}
+		service, err := cs.serviceRepo.GetByID(*req.ServiceID)
+		if err != nil {
+			return nil, errors.New("service not found")
+		}
+		if service.PriceType != "fixed" {
+			return nil, errors.New("coupons can be applied when paying for the quote")
+		}
+		priceBreakdown, err := NewPricingService().Calculate(service, &req.ServiceSelection)
+		if err != nil {
+			return nil, err
+		}
+		checkout.Amount = priceBreakdown.Total
+		checkout.ServiceID = service.ID
+		checkout.CategoryID = service.CategoryID
+	case models.CouponScopeQuote:
+		if req.BookingID == nil {
+			return nil, errors.New("booking_id is required")
+		}
+		booking, err := cs.bookingRepo.GetByID(*req.BookingID)
+		if err != nil || booking.UserID != userID {
+			return nil, errors.New("booking not found")
+		}
+		if err := cs.QuoteCheckout(booking, checkout); err != nil {
+			return nil, err
+		}
+	case models.CouponScopeSubscription:
+		if req.PlanID == nil {
+			return nil, errors.New("plan_id is required")
+		}
+		if _, err := cs.planRepo.GetByID(*req.PlanID); err != nil {
+			return nil, errors.New("subscription plan not found")
+		}
+	}
+
+	if checkout.Amount <= 0 {
+		return nil, errors.New("amount is required")
+	}
+
+	return cs.CalculateDiscount(req.Code, checkout)
+}
+
+// QuoteCheckout fills in the service and city of an inquiry booking for coupon targeting
+func (cs *CouponService) QuoteCheckout(booking *models.Booking, checkout *CouponCheckout) error {
+	if booking.BookingType != models.BookingTypeInquiry {
+		return errors.New("booking is not inquiry type")
+	}
+
+	checkout.BookingID = booking.ID
+	checkout.ServiceID = booking.ServiceID
+	checkout.CategoryID = booking.Service.CategoryID
+	if checkout.CategoryID == 0 {
+		if service, err := cs.serviceRepo.GetByID(booking.ServiceID); err == nil {
+			checkout.CategoryID = service.CategoryID
+		}
+	}
+	if checkout.City == "" && booking.Address != nil {
+		var address models.BookingAddress
+		if err := json.Unmarshal([]byte(*booking.Address), &address); err == nil {
+			checkout.City = address.City
+		}
+	}
+	return nil
+}
+
+// CreateCoupon creates a coupon (admin only)
+func (cs *CouponService) CreateCoupon(adminID uint, req *models.CreateCouponRequest) (*models.Coupon, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	if !couponCodePattern.MatchString(code) {
+		return nil, errors.New("coupon code can only contain letters, numbers, dashes and underscores")
+	}
+
+	if _, err := cs.couponRepo.GetByCode(code); err == nil {
+		return nil, errors.New("coupon code already exists")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check coupon code: %v", err)
+	}
+
+	coupon := &models.Coupon{
+		Code:             code,
+		Description:      req.Description,
+		DiscountType:     req.DiscountType,
+		DiscountValue:    req.DiscountValue,
+		MaxDiscount:      req.MaxDiscount,
+		MinOrderAmount:   req.MinOrderAmount,
+		Scope:            req.Scope,
+		UsageLimit:       req.UsageLimit,
+		PerUserLimit:     1,
+		CategoryIDs:      req.CategoryIDs,
+		ServiceIDs:       req.ServiceIDs,
+		Cities:           req.Cities,
+		FirstBookingOnly: req.FirstBookingOnly,
+		ValidFrom:        req.ValidFrom,
+		ValidUntil:       req.ValidUntil,
+		IsActive:         true,
+		CreatedBy:        adminID,
+	}
+	if coupon.Scope == "" {
+		coupon.Scope = models.CouponScopeAll
+	}
+	if req.PerUserLimit != nil {
+		coupon.PerUserLimit = *req.PerUserLimit
+	}
+	if req.IsActive != nil {
+		coupon.IsActive = *req.IsActive
+	}
+
+	if err := validateCoupon(coupon); err != nil {
+		return nil, err
+	}
+
+	if err := cs.couponRepo.Create(coupon); err != nil {
+		return nil, fmt.Errorf("failed to create coupon: %v", err)
+	}
+
+	logrus.Infof("Coupon %s created by admin %d", coupon.Code, adminID)
+	return coupon, nil
+}
+
+// UpdateCoupon updates a coupon (admin only). The code and discount type cannot change
+// once created, so existing redemptions stay meaningful.
+func (cs *CouponService) UpdateCoupon(id uint, req *models.UpdateCouponRequest) (*models.Coupon, error) {
+	coupon, err := cs.couponRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("coupon not found")
+	}
+
+	if req.Description != nil {
+		coupon.Description = *req.Description
+	}
+	if req.DiscountValue != nil {
+		coupon.DiscountValue = *req.DiscountValue
+	}
+	if req.MaxDiscount != nil {
+		coupon.MaxDiscount = req.MaxDiscount
+	}
+	if req.MinOrderAmount != nil {
+		coupon.MinOrderAmount = *req.MinOrderAmount
+	}
+	if req.UsageLimit != nil {
+		coupon.UsageLimit = req.UsageLimit
+	}
+	if req.PerUserLimit != nil {
+		coupon.PerUserLimit = *req.PerUserLimit
+	}
+	if req.CategoryIDs != nil {
+		coupon.CategoryIDs = req.CategoryIDs
+	}
+	if req.ServiceIDs != nil {
+		coupon.ServiceIDs = req.ServiceIDs
+	}
+	if req.Cities != nil {
+		coupon.Cities = req.Cities
+	}
+	if req.FirstBookingOnly != nil {
+		coupon.FirstBookingOnly = *req.FirstBookingOnly
+	}
+	if req.ValidFrom != nil {
+		coupon.ValidFrom = req.ValidFrom
+	}
+	if req.ValidUntil != nil {
+		coupon.ValidUntil = req.ValidUntil
+	}
+	if req.IsActive != nil {
+		coupon.IsActive = *req.IsActive
+	}
+
+	if err := validateCoupon(coupon); err != nil {
+		return nil, err
+	}
+
+	if err := cs.couponRepo.Update(coupon); err != nil {
+		return nil, fmt.Errorf("failed to update coupon: %v", err)
+	}
+
+	return coupon, nil
+}
+
+// GetCoupons gets coupons for admins
+func (cs *CouponService) GetCoupons(filters *models.CouponFilters) ([]models.Coupon, *repositories.Pagination, error) {
+	return cs.couponRepo.GetCoupons(filters)
+}
+
+// GetCouponByID gets a coupon with its usage count
+func (cs *CouponService) GetCouponByID(id uint) (map[string]interface{}, error) {
+	coupon, err := cs.couponRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("coupon not found")
+	}
+
+	used, err := cs.couponRepo.CountRedemptions(id, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count coupon usage: %v", err)
+	}
+
+	return map[string]interface{}{
+		"coupon":     coupon,
+		"used_count": used,
+	}, nil
+}
+
+// GetRedemptions gets redemption records for admins
+func (cs *CouponService) GetRedemptions(couponID *uint, status models.CouponRedemptionStatus, page, limit int) ([]models.CouponRedemption, *repositories.Pagination, error) {
+	return cs.couponRepo.GetRedemptions(couponID, status, page, limit)
+}
+
+// GetPromotionsReport summarises coupon redemptions and referral rewards
+func (cs *CouponService) GetPromotionsReport(startDate, endDate string) (map[string]interface{}, error) {
+	coupons, err := cs.couponRepo.GetUsageReport(startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coupon report: %v", err)
+	}
+
+	referrals, err := cs.referralRepo.GetReport()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referral report: %v", err)
+	}
+
+	var totalDiscount float64
+	var totalRedemptions int64
+	for _, coupon := range coupons {
+		totalDiscount += coupon.TotalDiscount
+		totalRedemptions += coupon.AppliedCount
+	}
+
+	return map[string]interface{}{
+		"coupons":           coupons,
+		"total_redemptions": totalRedemptions,
+		"total_discount":    totalDiscount,
+		"referrals":         referrals,
+	}, nil
+}
+
+// validateCoupon validates a coupon's discount and validity window
+func validateCoupon(coupon *models.Coupon) error {
+	if coupon.DiscountType == models.CouponDiscountPercentage && coupon.DiscountValue > 100 {
+		return errors.New("percentage discount cannot exceed 100")
+	}
+	if coupon.ValidFrom != nil && coupon.ValidUntil != nil && !coupon.ValidUntil.After(*coupon.ValidFrom) {
+		return errors.New("valid_until must be after valid_from")
+	}
+	if coupon.PerUserLimit < 0 {
+		return errors.New("per_user_limit cannot be negative")
+	}
+	return nil
+}
+
+// containsID reports whether the ID is in the list
+func containsID(ids []int64, id uint) bool {
+	for _, v := range ids {
+		if v == int64(id) {
+			return true
+		}
+	}
+	return false
+}
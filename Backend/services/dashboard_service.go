@@ -16,6 +16,8 @@ type DashboardService struct {
 	projectRepo   *repositories.ProjectRepository
 	vendorRepo    *repositories.VendorRepository
 	paymentRepo   *repositories.PaymentRepository
+
+	adminConfigService *AdminConfigService
 }
 
 // NewDashboardService creates a new dashboard service
@@ -29,6 +31,8 @@ func NewDashboardService() *DashboardService {
 		projectRepo:   repositories.NewProjectRepository(),
 		vendorRepo:    repositories.NewVendorRepository(),
 		paymentRepo:   repositories.NewPaymentRepository(),
+
+		adminConfigService: NewAdminConfigService(),
 	}
 }
 
@@ -78,6 +82,11 @@ func (ds *DashboardService) GetMarketplaceAnalytics() (*models.MarketplaceAnalyt
 	return ds.getMarketplaceAnalytics()
 }
 
+// GetCommunicationAnalytics gets chat and notification analytics data
+func (ds *DashboardService) GetCommunicationAnalytics() (*models.CommunicationAnalytics, error) {
+	return ds.getCommunicationAnalytics()
+}
+
 // GetMonthlyTrends gets monthly trends data
 func (ds *DashboardService) GetMonthlyTrends() (*models.MonthlyTrends, error) {
 	return ds.getMonthlyTrends()
@@ -86,28 +95,36 @@ func (ds *DashboardService) GetMonthlyTrends() (*models.MonthlyTrends, error) {
 // GetDashboardAlerts gets dashboard alerts and notifications
 func (ds *DashboardService) GetDashboardAlerts() (*models.DashboardAlerts, error) {
 	alerts := &models.DashboardAlerts{}
+	thresholds := ds.adminConfigService.GetDashboardAlertThresholds()
 
 	// Get urgent alerts
-	urgentAlerts, err := ds.dashboardRepo.GetUrgentAlerts()
+	urgentAlerts, err := ds.dashboardRepo.GetUrgentAlerts(thresholds)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get urgent alerts: %w", err)
 	}
 	alerts.UrgentAlerts = urgentAlerts
 
 	// Get system alerts
-	systemAlerts, err := ds.dashboardRepo.GetSystemAlerts()
+	systemAlerts, err := ds.dashboardRepo.GetSystemAlerts(thresholds)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get system alerts: %w", err)
 	}
 	alerts.SystemAlerts = systemAlerts
 
 	// Get pending actions
-	pendingActions, err := ds.dashboardRepo.GetPendingActions()
+	pendingActions, err := ds.dashboardRepo.GetPendingActions(thresholds)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pending actions: %w", err)
 	}
 	alerts.PendingActions = pendingActions
 
+	// Get recent activity
+	recentActivity, err := ds.dashboardRepo.GetRecentActivity(20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent activity: %w", err)
+	}
+	alerts.RecentActivity = recentActivity
+
 	return alerts, nil
 }
 
@@ -123,7 +140,7 @@ func (ds *DashboardService) getUserAnalytics() (*models.UserAnalytics, error) {
 
 // getBookingAnalytics gets booking-related analytics
 func (ds *DashboardService) getBookingAnalytics() (*models.BookingAnalytics, error) {
-	return ds.dashboardRepo.GetBookingAnalytics()
+	return ds.dashboardRepo.GetBookingAnalytics(ds.adminConfigService.GetDashboardAlertThresholds())
 }
 
 // getServiceAnalytics gets service-related analytics
@@ -12,7 +12,7 @@ import (
 // DeviceManagementService handles device token operations
 type DeviceManagementService struct {
 	db          *gorm.DB
-	fcmService  *FCMService
+	fcmService  PushSender
 }
 
 // DeviceRegistrationRequest represents a device registration request
@@ -26,7 +26,7 @@ type DeviceRegistrationRequest struct {
 }
 
 // NewDeviceManagementService creates a new device management service
-func NewDeviceManagementService(fcmService *FCMService) *DeviceManagementService {
+func NewDeviceManagementService(fcmService PushSender) *DeviceManagementService {
 	return &DeviceManagementService{
 		db:         database.GetDB(),
 		fcmService: fcmService,
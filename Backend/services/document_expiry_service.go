@@ -0,0 +1,109 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DocumentExpiryService marks verified documents whose validity has run out as expired and asks
+// their owners for a replacement. Workers with expired mandatory documents are kept out of new
+// assignments by BookingService until the replacement is verified.
+type DocumentExpiryService struct {
+	documentRepo                *repositories.UserDocumentRepository
+	enhancedNotificationService *EnhancedNotificationService
+	stopChan                    chan bool
+}
+
+func NewDocumentExpiryService(enhancedNotificationService *EnhancedNotificationService) *DocumentExpiryService {
+	return &DocumentExpiryService{
+		documentRepo:                repositories.NewUserDocumentRepository(),
+		enhancedNotificationService: enhancedNotificationService,
+		stopChan:                    make(chan bool),
+	}
+}
+
+// Start begins the periodic check for expired documents
+func (des *DocumentExpiryService) Start() {
+	logrus.Info("DocumentExpiryService starting...")
+
+	// Run immediately on start
+	go des.expireDocuments()
+
+	// Then run every hour
+	ticker := time.NewTicker(1 * time.Hour)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				des.expireDocuments()
+			case <-des.stopChan:
+				ticker.Stop()
+				logrus.Info("DocumentExpiryService stopped")
+				return
+			}
+		}
+	}()
+
+	logrus.Info("DocumentExpiryService started successfully")
+}
+
+// Stop stops the document expiry service
+func (des *DocumentExpiryService) Stop() {
+	logrus.Info("Stopping DocumentExpiryService...")
+	des.stopChan <- true
+}
+
+// expireDocuments marks documents past their expiry date as expired and notifies their owners
+func (des *DocumentExpiryService) expireDocuments() {
+	documents, err := des.documentRepo.GetVerifiedDocumentsExpiredBefore(time.Now())
+	if err != nil {
+		logrus.Errorf("DocumentExpiryService: failed to get expired documents: %v", err)
+		return
+	}
+
+	expired := 0
+	for i := range documents {
+		document := &documents[i]
+		if err := des.documentRepo.MarkExpired(document.ID); err != nil {
+			logrus.Errorf("DocumentExpiryService: failed to expire document %d: %v", document.ID, err)
+			continue
+		}
+		expired++
+		des.sendExpired(document)
+	}
+
+	if expired > 0 {
+		logrus.Infof("DocumentExpiryService: %d documents expired", expired)
+	}
+}
+
+// sendExpired asks the owner of an expired document to upload a new one
+func (des *DocumentExpiryService) sendExpired(document *models.UserDocument) {
+	if des.enhancedNotificationService == nil {
+		return
+	}
+
+	go func() {
+		documentName := strings.ReplaceAll(string(document.DocumentType), "_", " ")
+		_, err := des.enhancedNotificationService.SendNotification(&NotificationRequest{
+			UserID: document.UserID,
+			Type:   models.NotificationTypeSystem,
+			Title:  "Document Expired",
+			Body:   fmt.Sprintf("Your %s has expired. Upload a new one to keep receiving bookings.", documentName),
+			Data: map[string]string{
+				"type":         "role_application_document",
+				"documentType": string(document.DocumentType),
+				"status":       string(models.DocumentVerificationStatusExpired),
+			},
+			Priority: "high",
+		})
+		if err != nil {
+			logrus.Errorf("DocumentExpiryService: failed to notify user %d: %v", document.UserID, err)
+		}
+	}()
+}
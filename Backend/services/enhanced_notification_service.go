@@ -13,7 +13,7 @@ import (
 // EnhancedNotificationService provides unified notification sending
 type EnhancedNotificationService struct {
 	db                  *gorm.DB
-	fcmService          *FCMService
+	fcmService          PushSender
 	deviceService       *DeviceManagementService
 	emailService        *EmailService
 }
@@ -45,7 +45,7 @@ type NotificationResult struct {
 }
 
 // NewEnhancedNotificationService creates a new enhanced notification service
-func NewEnhancedNotificationService(fcmService *FCMService, deviceService *DeviceManagementService, emailService *EmailService) *EnhancedNotificationService {
+func NewEnhancedNotificationService(fcmService PushSender, deviceService *DeviceManagementService, emailService *EmailService) *EnhancedNotificationService {
 	return &EnhancedNotificationService{
 		db:            database.GetDB(),
 		fcmService:    fcmService,
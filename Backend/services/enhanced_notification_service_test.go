@@ -0,0 +1,59 @@
+package services_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"treesindia/services"
+	"treesindia/testutil"
+)
+
+// deviceToken returns a token of a valid FCM length that is unique to user and name
+func deviceToken(userID uint, name string) string {
+	return fmt.Sprintf("%s-%d-%s", name, userID, strings.Repeat("x", 100))
+}
+
+func TestPushNotificationSkipsInvalidTokens(t *testing.T) {
+	testutil.RequireDB(t)
+	push := testutil.NewFakePushSender()
+	devices := services.NewDeviceManagementService(push)
+	notifications := services.NewEnhancedNotificationService(push, devices, nil)
+	user := testutil.CreateUser(t, 0)
+
+	phone := deviceToken(user.ID, "phone")
+	tablet := deviceToken(user.ID, "tablet")
+	for _, token := range []string{phone, tablet} {
+		if err := devices.RegisterDevice(&services.DeviceRegistrationRequest{UserID: user.ID, Token: token, Platform: "android"}); err != nil {
+			t.Fatalf("RegisterDevice returned error: %v", err)
+		}
+	}
+
+	// The tablet uninstalled the app, so its token is dropped
+	push.InvalidTokens[tablet] = true
+	if err := devices.ValidateAndCleanupTokens(); err != nil {
+		t.Fatalf("ValidateAndCleanupTokens returned error: %v", err)
+	}
+
+	result, err := notifications.SendNotification(&services.NotificationRequest{
+		UserID: user.ID,
+		Title:  "Booking confirmed",
+		Body:   "Your booking is confirmed",
+	})
+	if err != nil {
+		t.Fatalf("SendNotification returned error: %v", err)
+	}
+	if !result.PushSent || !result.PushSuccess {
+		t.Errorf("push sent/success = %v/%v (%s), want true/true", result.PushSent, result.PushSuccess, result.PushError)
+	}
+
+	sent := push.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("%d pushes were sent, want 1", len(sent))
+	}
+	if len(sent[0].Tokens) != 1 || sent[0].Tokens[0] != phone {
+		t.Errorf("push was sent to %v, want only the phone", sent[0].Tokens)
+	}
+	if sent[0].Notification.Title != "Booking confirmed" {
+		t.Errorf("push title = %q, want %q", sent[0].Notification.Title, "Booking confirmed")
+	}
+}
@@ -13,6 +13,18 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// CallProvider connects masked calls between two phone numbers. ExotelService is the production provider.
+type CallProvider interface {
+	// InitiateCall connects fromPhone to toPhone and returns the provider's call ID
+	InitiateCall(fromPhone, toPhone string) (string, error)
+	// ParseCallDuration converts a call duration reported by the provider to seconds
+	ParseCallDuration(duration string) int
+	// IsServiceAvailable reports whether the provider is configured
+	IsServiceAvailable() bool
+	// TestCall places a test call to a phone number and returns the call ID
+	TestCall(testPhoneNumber string) (string, error)
+}
+
 // ExotelService handles Exotel API interactions
 type ExotelService struct {
 	accountSid   string
@@ -199,8 +199,36 @@ func (s *FastChatbotService) processMessageWithAI(message string, session *model
 	return response, nil
 }
 
+// getSessionGeoRadius builds a radius search around the latitude and longitude shared in the
+// session context
+func (s *FastChatbotService) getSessionGeoRadius(session *models.ChatbotSession) (models.GeoRadius, bool) {
+	latitude, latOK := session.GetContext("latitude")
+	longitude, lngOK := session.GetContext("longitude")
+	if !latOK || !lngOK {
+		return models.GeoRadius{}, false
+	}
+	lat, latOK := latitude.(float64)
+	lng, lngOK := longitude.(float64)
+	if !latOK || !lngOK {
+		return models.GeoRadius{}, false
+	}
+	
+	near := models.GeoRadius{Latitude: lat, Longitude: lng, RadiusMeters: chatbotSearchRadiusMeters}
+	if near.Validate() != nil {
+		return models.GeoRadius{}, false
+	}
+	return near, true
+}
+
 // handlePropertyQuery handles property-related queries
 func (s *FastChatbotService) handlePropertyQuery(intent *SimpleIntent, session *models.ChatbotSession) (*FastChatbotResponse, error) {
+	// Search around the user's location when they did not name a place
+	if _, hasLocation := intent.Entities["location"]; !hasLocation {
+		if near, ok := s.getSessionGeoRadius(session); ok {
+			intent.Entities["near"] = near
+		}
+	}
+	
 	// Search properties
 	searchResult, err := s.propertyService.SearchProperties(intent)
 	if err != nil {
@@ -8,6 +8,9 @@ import (
 	"treesindia/models"
 )
 
+// chatbotSearchRadiusMeters is the radius searched around a location shared with the chatbot
+const chatbotSearchRadiusMeters = 10000
+
 // FastPropertyService handles fast property searches with direct SQL
 type FastPropertyService struct {
 	db *sql.DB
@@ -132,6 +135,21 @@ func (s *FastPropertyService) buildPropertyQuery(intent *SimpleIntent) (string,
 		argIndex++
 	}
 	
+	// Add radius filter around the user's location
+	orderBy := "created_at DESC"
+	if near, ok := intent.Entities["near"].(models.GeoRadius); ok {
+		location := "ll_to_earth(latitude, longitude)"
+		centre := fmt.Sprintf("ll_to_earth($%d, $%d)", argIndex, argIndex+1)
+		conditions = append(conditions,
+			"latitude IS NOT NULL AND longitude IS NOT NULL",
+			fmt.Sprintf("earth_box(%s, $%d) @> %s", centre, argIndex+2, location),
+			fmt.Sprintf("earth_distance(%s, %s) <= $%d", centre, location, argIndex+2),
+		)
+		orderBy = fmt.Sprintf("earth_distance(%s, %s)", centre, location)
+		args = append(args, near.Latitude, near.Longitude, near.RadiusMeters)
+		argIndex += 3
+	}
+	
 	// Add budget filter
 	if budget, ok := intent.Entities["budget"].(int); ok {
 		if intent.Action == "rent" {
@@ -154,7 +172,7 @@ func (s *FastPropertyService) buildPropertyQuery(intent *SimpleIntent) (string,
 	}
 	
 	// Add ordering and limit
-	baseQuery += " ORDER BY created_at DESC LIMIT 10"
+	baseQuery += " ORDER BY " + orderBy + " LIMIT 10"
 	
 	return baseQuery, args
 }
@@ -171,6 +189,12 @@ func (s *FastPropertyService) buildFilters(intent *SimpleIntent) map[string]inte
 		filters["city"] = location
 	}
 	
+	if near, ok := intent.Entities["near"].(models.GeoRadius); ok {
+		filters["lat"] = near.Latitude
+		filters["lng"] = near.Longitude
+		filters["radius_km"] = near.RadiusMeters / 1000
+	}
+	
 	if budget, ok := intent.Entities["budget"].(int); ok {
 		filters["max_price"] = budget
 	}
@@ -252,7 +276,9 @@ func (s *FastPropertyService) GetMissingInfo(intent *SimpleIntent) []string {
 	missing := make([]string, 0)
 	
 	if intent.Type == "property" {
-		if _, ok := intent.Entities["location"]; !ok {
+		_, hasLocation := intent.Entities["location"]
+		_, hasNear := intent.Entities["near"]
+		if !hasLocation && !hasNear {
 			missing = append(missing, "location")
 		}
 		if _, ok := intent.Entities["bedrooms"]; !ok {
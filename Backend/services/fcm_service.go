@@ -4,12 +4,21 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"treesindia/metrics"
 
 	firebase "firebase.google.com/go/v4"
 	"firebase.google.com/go/v4/messaging"
 	"google.golang.org/api/option"
 )
 
+// PushSender sends push notifications to devices. FCMService is the production sender.
+type PushSender interface {
+	SendToDevice(token string, notification *FCMNotification) (*FCMResponse, error)
+	SendToMultipleDevices(tokens []string, notification *FCMNotification) (*FCMResponse, error)
+	SendToTopic(topic string, notification *FCMNotification) (*FCMResponse, error)
+	ValidateToken(token string) (bool, error)
+}
+
 // FCMService handles Firebase Cloud Messaging operations
 type FCMService struct {
 	client *messaging.Client
@@ -90,12 +99,14 @@ func (f *FCMService) SendToDevice(token string, notification *FCMNotification) (
 
 	response, err := f.client.Send(context.Background(), message)
 	if err != nil {
+		metrics.RecordFCMResult(0, 1)
 		return &FCMResponse{
 			SuccessCount: 0,
 			FailureCount: 1,
 			Errors:       []string{err.Error()},
 		}, err
 	}
+	metrics.RecordFCMResult(1, 0)
 
 	return &FCMResponse{
 		SuccessCount: 1,
@@ -181,12 +192,14 @@ func (f *FCMService) SendToTopic(topic string, notification *FCMNotification) (*
 
 	response, err := f.client.Send(context.Background(), message)
 	if err != nil {
+		metrics.RecordFCMResult(0, 1)
 		return &FCMResponse{
 			SuccessCount: 0,
 			FailureCount: 1,
 			Errors:       []string{err.Error()},
 		}, err
 	}
+	metrics.RecordFCMResult(1, 0)
 
 	return &FCMResponse{
 		SuccessCount: 1,
@@ -277,12 +290,14 @@ func (f *FCMService) sendBatch(tokens []string, notification *FCMNotification) (
 
 	response, err := f.client.SendMulticast(context.Background(), message)
 	if err != nil {
+		metrics.RecordFCMResult(0, len(tokens))
 		return &FCMResponse{
 			SuccessCount: 0,
 			FailureCount: len(tokens),
 			Errors:       []string{err.Error()},
 		}, err
 	}
+	metrics.RecordFCMResult(response.SuccessCount, response.FailureCount)
 
 	var errors []string
 	if response.FailureCount > 0 {
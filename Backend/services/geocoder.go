@@ -0,0 +1,50 @@
+package services
+
+import (
+	"os"
+	"strings"
+)
+
+// Geocoder converts addresses to coordinates. GoogleMapsService and GeoapifyService both
+// implement it.
+type Geocoder interface {
+	GeocodeAddress(req *GeocodeRequest) (*GeocodingResponse, error)
+}
+
+// NewGeocoder returns the Google Maps geocoder when GOOGLE_MAPS_API_KEY is set, otherwise the
+// Geoapify geocoder
+func NewGeocoder() Geocoder {
+	if os.Getenv("GOOGLE_MAPS_API_KEY") != "" {
+		return NewGoogleMapsService()
+	}
+	return NewGeoapifyService()
+}
+
+// geocodeListingAddress geocodes the address of a property or project listing in India. It
+// returns nil when the address could not be found.
+func geocodeListingAddress(geocoder Geocoder, parts ...string) (*LatLng, error) {
+	var address []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			address = append(address, part)
+		}
+	}
+	if len(address) == 0 {
+		return nil, nil
+	}
+	address = append(address, "India")
+
+	response, err := geocoder.GeocodeAddress(&GeocodeRequest{
+		Address:    strings.Join(address, ", "),
+		Components: "country:in",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Results) == 0 {
+		return nil, nil
+	}
+
+	location := response.Results[0].Geometry.Location
+	return &location, nil
+}
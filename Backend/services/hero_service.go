@@ -10,13 +10,13 @@ import (
 
 type HeroService struct {
 	heroRepo *repositories.HeroRepository
-	cloudinaryService *CloudinaryService
+	mediaStore MediaStore
 }
 
-func NewHeroService(heroRepo *repositories.HeroRepository, cloudinaryService *CloudinaryService) *HeroService {
+func NewHeroService(heroRepo *repositories.HeroRepository, mediaStore MediaStore) *HeroService {
 	return &HeroService{
 		heroRepo: heroRepo,
-		cloudinaryService: cloudinaryService,
+		mediaStore: mediaStore,
 	}
 }
 
@@ -65,15 +65,15 @@ func (s *HeroService) UpdateHeroImage(image *models.HeroImage) error {
 
 // UpdateHeroImageWithFile updates an existing hero image/video with file upload
 func (s *HeroService) UpdateHeroImageWithFile(id uint, image *models.HeroImage, file *multipart.FileHeader) error {
-	// Get the existing image to get the old Cloudinary URL
+	// Get the existing image to get the old media URL
 	existingImage, err := s.heroRepo.GetHeroImageByID(id)
 	if err != nil {
 		return err
 	}
 	
-	// Check if Cloudinary service is available
-	if s.cloudinaryService == nil {
-		return errors.New("cloudinary service is not available")
+	// Check if media store is available
+	if s.mediaStore == nil {
+		return errors.New("media store is not available")
 	}
 	
 	// Determine media type from the image model (should be set before calling this)
@@ -82,10 +82,10 @@ func (s *HeroService) UpdateHeroImageWithFile(id uint, image *models.HeroImage,
 		mediaType = "image" // default to image
 	}
 	
-	// Upload new media to Cloudinary
-	mediaURL, err := s.cloudinaryService.UploadMedia(file, "hero-media", mediaType)
+	// Upload new media to media storage
+	mediaURL, err := s.mediaStore.UploadMedia(file, "hero-media", mediaType)
 	if err != nil {
-		return fmt.Errorf("failed to upload media to Cloudinary: %w", err)
+		return fmt.Errorf("failed to upload media to media storage: %w", err)
 	}
 	
 	// Set the new media URLs
@@ -98,23 +98,21 @@ func (s *HeroService) UpdateHeroImageWithFile(id uint, image *models.HeroImage,
 		return err
 	}
 	
-	// Delete old media from Cloudinary if it exists
+	// Delete old media from media storage if it exists
 	if existingImage.MediaURL != "" {
-		publicID := s.cloudinaryService.GetPublicIDFromURL(existingImage.MediaURL)
-		resourceType := s.cloudinaryService.GetResourceTypeFromURL(existingImage.MediaURL)
+		publicID := s.mediaStore.KeyFromURL(existingImage.MediaURL)
 		if publicID != "" {
-			// Try to delete from Cloudinary, but don't fail if it doesn't work
-			if deleteErr := s.cloudinaryService.DeleteMedia(publicID, resourceType); deleteErr != nil {
+			// Try to delete from media storage, but don't fail if it doesn't work
+			if deleteErr := s.mediaStore.Delete(existingImage.MediaURL); deleteErr != nil {
 				// Log error silently
 			}
 		}
 	} else if existingImage.ImageURL != "" {
 		// Fallback to ImageURL for backward compatibility
-		publicID := s.cloudinaryService.GetPublicIDFromURL(existingImage.ImageURL)
-		resourceType := s.cloudinaryService.GetResourceTypeFromURL(existingImage.ImageURL)
+		publicID := s.mediaStore.KeyFromURL(existingImage.ImageURL)
 		if publicID != "" {
-			// Try to delete from Cloudinary, but don't fail if it doesn't work
-			if deleteErr := s.cloudinaryService.DeleteMedia(publicID, resourceType); deleteErr != nil {
+			// Try to delete from media storage, but don't fail if it doesn't work
+			if deleteErr := s.mediaStore.Delete(existingImage.ImageURL); deleteErr != nil {
 				// Log error silently
 			}
 		}
@@ -125,7 +123,7 @@ func (s *HeroService) UpdateHeroImageWithFile(id uint, image *models.HeroImage,
 
 // DeleteHeroImage deletes a hero image/video
 func (s *HeroService) DeleteHeroImage(id uint) error {
-	// Get the image first to get the Cloudinary URL
+	// Get the image first to get the media URL
 	image, err := s.heroRepo.GetHeroImageByID(id)
 	if err != nil {
 		return err
@@ -137,8 +135,8 @@ func (s *HeroService) DeleteHeroImage(id uint) error {
 		return err
 	}
 	
-	// Delete from Cloudinary if service is available and URL is from Cloudinary
-	if s.cloudinaryService != nil {
+	// Delete from media storage if it is available
+	if s.mediaStore != nil {
 		var urlToDelete string
 		if image.MediaURL != "" {
 			urlToDelete = image.MediaURL
@@ -147,12 +145,11 @@ func (s *HeroService) DeleteHeroImage(id uint) error {
 		}
 		
 		if urlToDelete != "" {
-			// Extract public ID and resource type from Cloudinary URL
-			publicID := s.cloudinaryService.GetPublicIDFromURL(urlToDelete)
-			resourceType := s.cloudinaryService.GetResourceTypeFromURL(urlToDelete)
+			// Extract public ID and resource type from media storage URL
+			publicID := s.mediaStore.KeyFromURL(urlToDelete)
 			if publicID != "" {
-				// Try to delete from Cloudinary, but don't fail if it doesn't work
-				if deleteErr := s.cloudinaryService.DeleteMedia(publicID, resourceType); deleteErr != nil {
+				// Try to delete from media storage, but don't fail if it doesn't work
+				if deleteErr := s.mediaStore.Delete(urlToDelete); deleteErr != nil {
 					// Log error silently
 				}
 			}
@@ -175,9 +172,9 @@ func (s *HeroService) CreateHeroImageWithFile(image *models.HeroImage, file *mul
 		return errors.New("file is required")
 	}
 	
-	// Check if Cloudinary service is available
-	if s.cloudinaryService == nil {
-		return errors.New("cloudinary service is not available")
+	// Check if media store is available
+	if s.mediaStore == nil {
+		return errors.New("media store is not available")
 	}
 	
 	// Determine media type from the image model (should be set before calling this)
@@ -186,10 +183,10 @@ func (s *HeroService) CreateHeroImageWithFile(image *models.HeroImage, file *mul
 		mediaType = "image" // default to image
 	}
 	
-	// Upload media to Cloudinary
-	mediaURL, err := s.cloudinaryService.UploadMedia(file, "hero-media", mediaType)
+	// Upload media to media storage
+	mediaURL, err := s.mediaStore.UploadMedia(file, "hero-media", mediaType)
 	if err != nil {
-		return fmt.Errorf("failed to upload media to Cloudinary: %w", err)
+		return fmt.Errorf("failed to upload media to media storage: %w", err)
 	}
 	
 	// Set the media URLs (keep ImageURL for backward compatibility)
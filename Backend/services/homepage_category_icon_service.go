@@ -10,13 +10,13 @@ import (
 
 type HomepageCategoryIconService struct {
 	iconRepo           *repositories.HomepageCategoryIconRepository
-	cloudinaryService  *CloudinaryService
+	mediaStore  MediaStore
 }
 
-func NewHomepageCategoryIconService(iconRepo *repositories.HomepageCategoryIconRepository, cloudinaryService *CloudinaryService) *HomepageCategoryIconService {
+func NewHomepageCategoryIconService(iconRepo *repositories.HomepageCategoryIconRepository, mediaStore MediaStore) *HomepageCategoryIconService {
 	return &HomepageCategoryIconService{
 		iconRepo:          iconRepo,
-		cloudinaryService: cloudinaryService,
+		mediaStore: mediaStore,
 	}
 }
 
@@ -48,15 +48,15 @@ func (s *HomepageCategoryIconService) UpdateIconWithFile(name string, file *mult
 		return fmt.Errorf("category icon not found: %w", err)
 	}
 
-	// Check if Cloudinary service is available
-	if s.cloudinaryService == nil {
-		return errors.New("cloudinary service is not available")
+	// Check if media store is available
+	if s.mediaStore == nil {
+		return errors.New("media store is not available")
 	}
 
-	// Upload image to Cloudinary
-	iconURL, err := s.cloudinaryService.UploadImage(file, "category-icons")
+	// Upload image to media storage
+	iconURL, err := s.mediaStore.UploadImage(file, "category-icons")
 	if err != nil {
-		return fmt.Errorf("failed to upload image to Cloudinary: %w", err)
+		return fmt.Errorf("failed to upload image to media storage: %w", err)
 	}
 
 	// Update the icon URL
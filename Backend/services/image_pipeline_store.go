@@ -0,0 +1,158 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"treesindia/models"
+	"treesindia/repositories"
+	"treesindia/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxProcessedImageBytes caps how much of an upload is read into memory for processing
+const maxProcessedImageBytes = 25 * 1024 * 1024
+
+// ImagePipelineStore runs uploaded images through the image pipeline before handing them to the
+// underlying media store: metadata is stripped, the image is auto-oriented and stored at a
+// web-optimised size with fixed thumbnails next to it, and its perceptual hash is recorded.
+// Videos and other media pass through unchanged.
+type ImagePipelineStore struct {
+	MediaStore
+	fingerprintRepo *repositories.ImageFingerprintRepository
+}
+
+// NewImagePipelineStore wraps a media store with the image pipeline
+func NewImagePipelineStore(store MediaStore) *ImagePipelineStore {
+	return &ImagePipelineStore{
+		MediaStore:      store,
+		fingerprintRepo: repositories.NewImageFingerprintRepository(),
+	}
+}
+
+// Unwrap returns the underlying media store
+func (ps *ImagePipelineStore) Unwrap() MediaStore {
+	return ps.MediaStore
+}
+
+// UploadImage processes and uploads an image
+func (ps *ImagePipelineStore) UploadImage(file *multipart.FileHeader, folder string) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		logrus.Errorf("Failed to open file %s: %v", file.Filename, err)
+		return "", fmt.Errorf("failed to open file: %v", err)
+	}
+	defer src.Close()
+
+	return ps.UploadReader(src, folder, file.Filename, "image")
+}
+
+// UploadMedia uploads either an image or video based on the media type
+func (ps *ImagePipelineStore) UploadMedia(file *multipart.FileHeader, folder string, mediaType string) (string, error) {
+	if mediaType == "video" {
+		return ps.MediaStore.UploadVideo(file, folder)
+	}
+	return ps.UploadImage(file, folder)
+}
+
+// UploadImageFromPath processes and uploads an image from a local file path
+func (ps *ImagePipelineStore) UploadImageFromPath(filePath string, folder string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		logrus.Errorf("Failed to open file %s: %v", filePath, err)
+		return "", fmt.Errorf("failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	return ps.UploadReader(file, folder, filepath.Base(filePath), "image")
+}
+
+// UploadReader processes images read from r before uploading them; other media is uploaded as is
+func (ps *ImagePipelineStore) UploadReader(r io.Reader, folder, filename, mediaType string) (string, error) {
+	if mediaType != "image" {
+		return ps.MediaStore.UploadReader(r, folder, filename, mediaType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, maxProcessedImageBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %v", err)
+	}
+	if len(data) > maxProcessedImageBytes {
+		return "", fmt.Errorf("image is larger than %d MB", maxProcessedImageBytes/(1024*1024))
+	}
+
+	// Vector icons and animations can't be re-encoded without losing them
+	contentType := http.DetectContentType(data)
+	if contentType == "image/gif" || strings.EqualFold(filepath.Ext(filename), ".svg") {
+		return ps.MediaStore.UploadReader(bytes.NewReader(data), folder, filename, mediaType)
+	}
+
+	processed, err := utils.ProcessImage(data)
+	if err != nil {
+		logrus.Errorf("Failed to process image %s: %v", filename, err)
+		return "", fmt.Errorf("invalid image %s: %v", filename, err)
+	}
+
+	key := newMediaKey(folder, filename)
+	key = strings.TrimSuffix(key, path.Ext(key)) + processed.Extension
+
+	url, err := ps.Put(bytes.NewReader(processed.Web.Data), key, processed.ContentType)
+	if err != nil {
+		logrus.Errorf("Failed to upload image %s: %v", filename, err)
+		return "", fmt.Errorf("failed to upload image: %v", err)
+	}
+
+	for _, variant := range utils.ImageVariants {
+		encoded := processed.Variants[variant.Name]
+		if _, err := ps.Put(bytes.NewReader(encoded.Data), utils.ImageVariantURL(key, variant.Name), processed.ContentType); err != nil {
+			logrus.Errorf("Failed to upload %s variant of image %s: %v", variant.Name, filename, err)
+			ps.Delete(url)
+			return "", fmt.Errorf("failed to upload image: %v", err)
+		}
+	}
+
+	fingerprint := &models.ImageFingerprint{
+		URL:            url,
+		Folder:         folder,
+		PerceptualHash: int64(processed.PerceptualHash),
+		Width:          processed.Web.Width,
+		Height:         processed.Web.Height,
+		SizeBytes:      int64(len(processed.Web.Data)),
+	}
+	if err := ps.fingerprintRepo.Create(fingerprint); err != nil {
+		logrus.Warnf("Failed to record fingerprint of image %s: %v", url, err)
+	}
+
+	logrus.Infof("Processed image %s (%d bytes -> %d bytes, %dx%d): %s",
+		filename, len(data), len(processed.Web.Data), processed.Web.Width, processed.Web.Height, url)
+	return url, nil
+}
+
+// Delete deletes media by its URL, along with the thumbnails of processed images
+func (ps *ImagePipelineStore) Delete(url string) error {
+	if err := ps.MediaStore.Delete(url); err != nil {
+		return err
+	}
+	ps.deleteImage(url)
+	return nil
+}
+
+// deleteImage deletes the thumbnails and fingerprint of a processed image. Images stored before
+// the pipeline existed have no thumbnails, so failures are only logged.
+func (ps *ImagePipelineStore) deleteImage(url string) {
+	for _, variant := range utils.ImageVariants {
+		if err := ps.MediaStore.Delete(utils.ImageVariantURL(url, variant.Name)); err != nil {
+			logrus.Debugf("No %s variant deleted for %s: %v", variant.Name, url, err)
+		}
+	}
+	if err := ps.fingerprintRepo.DeleteByURL(url); err != nil {
+		logrus.Warnf("Failed to delete fingerprint of image %s: %v", url, err)
+	}
+}
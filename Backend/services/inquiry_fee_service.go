@@ -0,0 +1,188 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Inquiry fee expiry policies
+const (
+	InquiryFeePolicyRefund       = "refund"        // Refund to the original payment method
+	InquiryFeePolicyWalletCredit = "wallet_credit" // Credit the fee to the customer's wallet
+	InquiryFeePolicyRetain       = "retain"        // Keep the fee; the booking goes back for a new quote
+)
+
+// InquiryFeeService settles inquiry fees that are held while a quote is open
+type InquiryFeeService struct {
+	paymentRepo        PaymentStore
+	razorpayService    PaymentGateway
+	walletService      *UnifiedWalletService
+	adminConfigService *AdminConfigService
+}
+
+// NewInquiryFeeService creates a new inquiry fee service
+func NewInquiryFeeService() *InquiryFeeService {
+	return NewInquiryFeeServiceWith(repositories.NewPaymentRepository(), NewRazorpayService(), NewUnifiedWalletService())
+}
+
+// NewInquiryFeeServiceWith creates an inquiry fee service that refunds fees through gateway and
+// credits them through walletService
+func NewInquiryFeeServiceWith(paymentRepo PaymentStore, gateway PaymentGateway, walletService *UnifiedWalletService) *InquiryFeeService {
+	return &InquiryFeeService{
+		paymentRepo:        paymentRepo,
+		razorpayService:    gateway,
+		walletService:      walletService,
+		adminConfigService: NewAdminConfigService(),
+	}
+}
+
+// InquiryFeeRelease describes how held fees were settled for a booking
+type InquiryFeeRelease struct {
+	Policy string  `json:"policy"`
+	Amount float64 `json:"amount"`
+}
+
+// CaptureHeldFees completes held inquiry fees once the customer accepts a quote
+func (ifs *InquiryFeeService) CaptureHeldFees(bookingID uint) error {
+	payments, err := ifs.paymentRepo.GetHeldBookingPayments(bookingID)
+	if err != nil {
+		return fmt.Errorf("failed to get held payments: %v", err)
+	}
+
+	now := time.Now()
+	for i := range payments {
+		payment := &payments[i]
+		payment.Status = models.PaymentStatusCompleted
+		if payment.CompletedAt == nil {
+			payment.CompletedAt = &now
+		}
+		if err := ifs.paymentRepo.Update(payment); err != nil {
+			return fmt.Errorf("failed to capture held payment %d: %v", payment.ID, err)
+		}
+		logrus.Infof("Captured held inquiry fee payment %d for booking %d", payment.ID, bookingID)
+	}
+
+	return nil
+}
+
+// ReleaseHeldFees settles held inquiry fees of an expired quote according to the configured policy.
+// The fee payments are locked and updated within tx and wallet credits join it, so they roll back
+// together with the caller's own updates. Razorpay refunds happen outside the transaction and are
+// not undone if it rolls back, but a later run finds them already refunded and does not refund again.
+func (ifs *InquiryFeeService) ReleaseHeldFees(tx *gorm.DB, booking *models.Booking) (*InquiryFeeRelease, error) {
+	policy := ifs.adminConfigService.GetInquiryFeeExpiryPolicy()
+	release := &InquiryFeeRelease{Policy: policy}
+
+	var payments []models.Payment
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("related_entity_type = ? AND related_entity_id = ? AND status = ?", "booking", booking.ID, models.PaymentStatusHold).
+		Find(&payments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held payments: %v", err)
+	}
+
+	for _, payment := range payments {
+		release.Amount += payment.Amount
+	}
+
+	if policy == InquiryFeePolicyRetain {
+		now := time.Now()
+		for i := range payments {
+			payment := &payments[i]
+			payment.Status = models.PaymentStatusCompleted
+			if payment.CompletedAt == nil {
+				payment.CompletedAt = &now
+			}
+			if err := tx.Omit(clause.Associations).Save(payment).Error; err != nil {
+				return nil, fmt.Errorf("failed to capture held payment %d: %v", payment.ID, err)
+			}
+		}
+		return release, nil
+	}
+	if policy != InquiryFeePolicyRefund {
+		release.Policy = InquiryFeePolicyWalletCredit
+	}
+
+	for i := range payments {
+		if release.Policy == InquiryFeePolicyRefund {
+			err = ifs.refundToSource(tx, booking, &payments[i])
+		} else {
+			err = ifs.refundToWallet(tx, booking, &payments[i])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// refundToSource refunds a held fee to the method it was paid with
+func (ifs *InquiryFeeService) refundToSource(tx *gorm.DB, booking *models.Booking, payment *models.Payment) error {
+	// Wallet-paid fees go back to the wallet
+	if payment.Method != "razorpay" || payment.RazorpayPaymentID == nil {
+		return ifs.refundToWallet(tx, booking, payment)
+	}
+
+	// The refund cannot be rolled back with tx, so a payment Razorpay already refunded in a run that
+	// rolled back is only marked refunded
+	refunded, err := ifs.razorpayRefunded(payment)
+	if err != nil {
+		return err
+	}
+	if !refunded {
+		_, err := ifs.razorpayService.RefundPayment(*payment.RazorpayPaymentID, payment.Amount,
+			fmt.Sprintf("Quote expired for booking %s", booking.BookingReference))
+		if err != nil {
+			return fmt.Errorf("failed to refund payment %d via Razorpay: %v", payment.ID, err)
+		}
+	}
+
+	return ifs.markRefunded(tx, payment, "razorpay")
+}
+
+// razorpayRefunded checks with Razorpay if a payment has already been refunded in full
+func (ifs *InquiryFeeService) razorpayRefunded(payment *models.Payment) (bool, error) {
+	details, err := ifs.razorpayService.GetPaymentDetails(*payment.RazorpayPaymentID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get payment %d from Razorpay: %v", payment.ID, err)
+	}
+	amountRefunded, _ := details["amount_refunded"].(float64) // In paise
+	return amountRefunded >= math.Round(payment.Amount*100), nil
+}
+
+// refundToWallet credits a held fee to the customer's wallet
+func (ifs *InquiryFeeService) refundToWallet(tx *gorm.DB, booking *models.Booking, payment *models.Payment) error {
+	_, err := ifs.walletService.CreditRefundToWalletWithTx(tx, booking.UserID, payment.Amount, booking.ID,
+		fmt.Sprintf("Inquiry fee refund for booking %s", booking.BookingReference))
+	if err != nil {
+		return fmt.Errorf("failed to credit inquiry fee to wallet: %v", err)
+	}
+
+	return ifs.markRefunded(tx, payment, "wallet")
+}
+
+// markRefunded records the refund on the original fee payment
+func (ifs *InquiryFeeService) markRefunded(tx *gorm.DB, payment *models.Payment, method string) error {
+	now := time.Now()
+	reason := "Quote expired"
+	payment.Status = models.PaymentStatusRefunded
+	payment.RefundAmount = &payment.Amount
+	payment.RefundReason = &reason
+	payment.RefundMethod = &method
+	payment.RefundedAt = &now
+
+	if err := tx.Omit(clause.Associations).Save(payment).Error; err != nil {
+		return fmt.Errorf("failed to update refunded payment %d: %v", payment.ID, err)
+	}
+
+	logrus.Infof("Refunded held inquiry fee payment %d (₹%.2f) via %s", payment.ID, payment.Amount, method)
+	return nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LocalMediaStore stores media on the local filesystem and serves it from the /media route.
+// It is meant for local development and tests.
+type LocalMediaStore struct {
+	keyedUploads
+	dir        string
+	baseURL    string
+	signingKey []byte
+}
+
+// NewLocalMediaStore creates a media store rooted at dir whose files are served under baseURL
+func NewLocalMediaStore(dir, baseURL, signingKey string) (*LocalMediaStore, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid media directory %s: %v", dir, err)
+	}
+	if err := os.MkdirAll(absDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory %s: %v", absDir, err)
+	}
+
+	store := &LocalMediaStore{
+		dir:        absDir,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		signingKey: []byte(signingKey),
+	}
+	store.keyedUploads = keyedUploads{put: store.put, publicURL: store.PublicURL}
+
+	logrus.Infof("Local media store initialized at %s", absDir)
+	return store, nil
+}
+
+// AsLocalMediaStore returns the local store behind a media store, if it is one
+func AsLocalMediaStore(store MediaStore) (*LocalMediaStore, bool) {
+	if pipeline, ok := store.(*ImagePipelineStore); ok {
+		store = pipeline.Unwrap()
+	}
+	localStore, ok := store.(*LocalMediaStore)
+	return localStore, ok
+}
+
+// Backend returns the name of the storage backend
+func (ls *LocalMediaStore) Backend() string {
+	return MediaBackendLocal
+}
+
+// put writes an object to disk
+func (ls *LocalMediaStore) put(ctx context.Context, r io.Reader, size int64, key, contentType string) error {
+	filePath, err := ls.FilePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	dst, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, r); err != nil {
+		dst.Close()
+		os.Remove(filePath)
+		return err
+	}
+	return dst.Close()
+}
+
+// Delete deletes media by its URL
+func (ls *LocalMediaStore) Delete(url string) error {
+	key := ls.KeyFromURL(url)
+	if key == "" {
+		return fmt.Errorf("media URL is not from the local store: %s", url)
+	}
+
+	filePath, err := ls.FilePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete media: %v", err)
+	}
+
+	logrus.Infof("Media deleted successfully: %s", key)
+	return nil
+}
+
+// KeyFromURL returns the storage key of a media URL
+func (ls *LocalMediaStore) KeyFromURL(url string) string {
+	return keyFromBaseURL(ls.baseURL, url)
+}
+
+// PublicURL returns the public URL of a storage key
+func (ls *LocalMediaStore) PublicURL(key string) string {
+	return ls.baseURL + "/" + key
+}
+
+// SignedURL returns the media URL with an expiring HMAC signature
+func (ls *LocalMediaStore) SignedURL(url string, expiry time.Duration) (string, error) {
+	key := ls.KeyFromURL(url)
+	if key == "" {
+		return "", fmt.Errorf("media URL is not from the local store: %s", url)
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	return fmt.Sprintf("%s?expires=%s&signature=%s", ls.PublicURL(key), expires, ls.sign(key, expires)), nil
+}
+
+// ListMedia calls fn for every file in the media directory
+func (ls *LocalMediaStore) ListMedia(fn func(MediaObject) error) error {
+	return filepath.WalkDir(ls.dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(ls.dir, filePath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+
+		return fn(MediaObject{
+			Key:          key,
+			URL:          ls.PublicURL(key),
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+	})
+}
+
+// VerifySignature checks a signature created by SignedURL
+func (ls *LocalMediaStore) VerifySignature(key, expires, signature string) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(ls.sign(key, expires)))
+}
+
+// FilePath returns the path on disk of a storage key, rejecting keys that escape the media directory
+func (ls *LocalMediaStore) FilePath(key string) (string, error) {
+	filePath := filepath.Join(ls.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(filePath, ls.dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid media key: %s", key)
+	}
+	return filePath, nil
+}
+
+// sign computes the signature of a key and expiry
+func (ls *LocalMediaStore) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, ls.signingKey)
+	mac.Write([]byte(key + ":" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+	"treesindia/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Location tracking message types sent to the booking's chat room
+const (
+	MessageTypeWorkerLocation  = "worker_location"
+	MessageTypeWorkerArrived   = "worker_arrived"
+	MessageTypeTrackingStopped = "tracking_stopped"
+)
+
+// Worker location statuses
+const (
+	WorkerLocationStatusTracking  = "tracking"
+	WorkerLocationStatusCompleted = "completed"
+	WorkerLocationStatusStopped   = "stopped"
+)
+
+// LocationTrackingService streams a worker's location to the customer while they travel to and work
+// on a booking, with the ETA to the booking address and arrival detection
+type LocationTrackingService struct {
+	workerLocationRepo          *repositories.WorkerLocationRepository
+	workerAssignmentRepo        *repositories.WorkerAssignmentRepository
+	chatRoomRepo                *repositories.ChatRoomRepository
+	googleMapsService           *GoogleMapsService
+	wsService                   *WebSocketService
+	enhancedNotificationService *EnhancedNotificationService
+	adminConfigService          *AdminConfigService
+
+	// Chat room of each tracked booking, where location updates are broadcast
+	bookingRooms sync.Map
+}
+
+// NewLocationTrackingService creates a new location tracking service
+func NewLocationTrackingService(wsService *WebSocketService, enhancedNotificationService *EnhancedNotificationService) *LocationTrackingService {
+	return &LocationTrackingService{
+		workerLocationRepo:          repositories.NewWorkerLocationRepository(),
+		workerAssignmentRepo:        repositories.NewWorkerAssignmentRepository(),
+		chatRoomRepo:                repositories.NewChatRoomRepository(),
+		googleMapsService:           NewGoogleMapsService(),
+		wsService:                   wsService,
+		enhancedNotificationService: enhancedNotificationService,
+		adminConfigService:          NewAdminConfigService(),
+	}
+}
+
+// UpdateLocation records a worker's position for an accepted or in-progress assignment and
+// broadcasts it, with the ETA, to the customer
+func (lts *LocationTrackingService) UpdateLocation(workerID, assignmentID uint, update *models.LocationUpdate) (*models.WorkerLocationResponse, error) {
+	if update.Latitude < -90 || update.Latitude > 90 || update.Longitude < -180 || update.Longitude > 180 {
+		return nil, errors.New("invalid coordinates")
+	}
+
+	assignment, err := lts.workerAssignmentRepo.GetByID(assignmentID)
+	if err != nil {
+		return nil, errors.New("assignment not found")
+	}
+	if assignment.WorkerID != workerID {
+		return nil, errors.New("unauthorized access to assignment")
+	}
+	if assignment.Status != models.AssignmentStatusAccepted && assignment.Status != models.AssignmentStatusInProgress {
+		return nil, errors.New("location can only be shared for accepted or in-progress assignments")
+	}
+
+	location, err := lts.saveLocation(assignment, update)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, hasDestination := bookingCoordinates(&assignment.Booking)
+	justArrived := false
+	if hasDestination {
+		distance := int(utils.DistanceMeters(update.Latitude, update.Longitude, destination.Latitude, destination.Longitude))
+		location.DistanceMeters = &distance
+
+		if assignment.ArrivedAt == nil && distance <= lts.adminConfigService.GetWorkerArrivalRadiusMeters() {
+			now := time.Now()
+			assignment.ArrivedAt = &now
+			if err := lts.workerAssignmentRepo.Update(assignment); err != nil {
+				logrus.Errorf("LocationTrackingService: failed to record arrival for assignment %d: %v", assignment.ID, err)
+			} else {
+				justArrived = true
+			}
+		}
+
+		if assignment.ArrivedAt != nil {
+			zero := 0
+			location.ETASeconds = &zero
+		} else if lts.etaIsStale(location) {
+			lts.refreshETA(location, destination)
+		}
+	}
+
+	if err := lts.workerLocationRepo.Update(location); err != nil {
+		return nil, fmt.Errorf("failed to save location: %v", err)
+	}
+
+	response := lts.buildResponse(assignment, location)
+	lts.broadcast(assignment.BookingID, MessageTypeWorkerLocation, response)
+	if justArrived {
+		lts.broadcast(assignment.BookingID, MessageTypeWorkerArrived, response)
+		go lts.notifyArrival(assignment)
+	}
+	return response, nil
+}
+
+// StopTracking stops sharing a worker's location for an assignment
+func (lts *LocationTrackingService) StopTracking(workerID, assignmentID uint) error {
+	assignment, err := lts.workerAssignmentRepo.GetByID(assignmentID)
+	if err != nil {
+		return errors.New("assignment not found")
+	}
+	if assignment.WorkerID != workerID {
+		return errors.New("unauthorized access to assignment")
+	}
+	return lts.stop(assignment, WorkerLocationStatusStopped)
+}
+
+// CompleteTracking stops tracking when an assignment is completed
+func (lts *LocationTrackingService) CompleteTracking(assignment *models.WorkerAssignment) {
+	if err := lts.stop(assignment, WorkerLocationStatusCompleted); err != nil {
+		logrus.Errorf("LocationTrackingService: failed to stop tracking for assignment %d: %v", assignment.ID, err)
+	}
+}
+
+// GetWorkerTracking gets the tracking state of one of a worker's assignments
+func (lts *LocationTrackingService) GetWorkerTracking(workerID, assignmentID uint) (*models.WorkerLocationResponse, error) {
+	assignment, err := lts.workerAssignmentRepo.GetByID(assignmentID)
+	if err != nil {
+		return nil, errors.New("assignment not found")
+	}
+	if assignment.WorkerID != workerID {
+		return nil, errors.New("unauthorized access to assignment")
+	}
+	return lts.latestResponse(assignment)
+}
+
+// GetBookingTracking gets the live location and ETA of the worker assigned to a customer's booking
+func (lts *LocationTrackingService) GetBookingTracking(userID, bookingID uint) (*models.WorkerLocationResponse, error) {
+	assignment, err := lts.workerAssignmentRepo.GetByBookingID(bookingID)
+	if err != nil {
+		return nil, errors.New("no worker is assigned to this booking")
+	}
+	if assignment.Booking.UserID != userID {
+		return nil, errors.New("unauthorized access to booking")
+	}
+	return lts.latestResponse(assignment)
+}
+
+// saveLocation updates the assignment's location record. The table allows one active and one
+// inactive record per worker and assignment, so a stopped record is reactivated rather than a new
+// one created.
+func (lts *LocationTrackingService) saveLocation(assignment *models.WorkerAssignment, update *models.LocationUpdate) (*models.WorkerLocation, error) {
+	locations, err := lts.workerLocationRepo.GetLocationHistory(assignment.WorkerID, assignment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location: %v", err)
+	}
+
+	now := time.Now()
+	if len(locations) == 0 {
+		location := &models.WorkerLocation{
+			WorkerID:     assignment.WorkerID,
+			AssignmentID: assignment.ID,
+			BookingID:    assignment.BookingID,
+			Latitude:     update.Latitude,
+			Longitude:    update.Longitude,
+			Accuracy:     update.Accuracy,
+			Status:       WorkerLocationStatusTracking,
+			LastUpdated:  now,
+			IsActive:     true,
+		}
+		if err := lts.workerLocationRepo.Create(location); err != nil {
+			return nil, fmt.Errorf("failed to start tracking: %v", err)
+		}
+		logrus.Infof("LocationTrackingService: worker %d started tracking for assignment %d", assignment.WorkerID, assignment.ID)
+		return location, nil
+	}
+
+	location := &locations[0]
+	location.Latitude = update.Latitude
+	location.Longitude = update.Longitude
+	location.Accuracy = update.Accuracy
+	location.Status = WorkerLocationStatusTracking
+	location.LastUpdated = now
+	location.IsActive = true
+	return location, nil
+}
+
+// stop marks an assignment's tracking as finished and tells the customer
+func (lts *LocationTrackingService) stop(assignment *models.WorkerAssignment, status string) error {
+	location, err := lts.workerLocationRepo.GetActiveLocationByAssignmentID(assignment.ID)
+	if err != nil {
+		// Tracking was never started or is already stopped
+		return nil
+	}
+
+	location.Status = status
+	location.IsActive = false
+	location.LastUpdated = time.Now()
+	if err := lts.workerLocationRepo.Update(location); err != nil {
+		return fmt.Errorf("failed to stop tracking: %v", err)
+	}
+
+	lts.broadcast(assignment.BookingID, MessageTypeTrackingStopped, lts.buildResponse(assignment, location))
+	lts.bookingRooms.Delete(assignment.BookingID)
+	return nil
+}
+
+// etaIsStale reports whether the ETA of a location should be recalculated
+func (lts *LocationTrackingService) etaIsStale(location *models.WorkerLocation) bool {
+	if location.ETAUpdatedAt == nil || location.ETASeconds == nil {
+		return true
+	}
+	refresh := time.Duration(lts.adminConfigService.GetWorkerETARefreshSeconds()) * time.Second
+	return time.Since(*location.ETAUpdatedAt) >= refresh
+}
+
+// refreshETA asks Google Directions for the driving time from the worker to the booking address.
+// On failure the previous ETA is kept.
+func (lts *LocationTrackingService) refreshETA(location *models.WorkerLocation, destination *models.LocationCoordinates) {
+	origin := fmt.Sprintf("%f,%f", location.Latitude, location.Longitude)
+	target := fmt.Sprintf("%f,%f", destination.Latitude, destination.Longitude)
+
+	directions, err := lts.googleMapsService.GetDirections(origin, target)
+	if err != nil {
+		logrus.Warnf("LocationTrackingService: failed to get ETA for assignment %d: %v", location.AssignmentID, err)
+		return
+	}
+
+	now := time.Now()
+	location.ETASeconds = &directions.Duration
+	location.ETAUpdatedAt = &now
+}
+
+// latestResponse builds the tracking response from the assignment's last known location
+func (lts *LocationTrackingService) latestResponse(assignment *models.WorkerAssignment) (*models.WorkerLocationResponse, error) {
+	locations, err := lts.workerLocationRepo.GetLocationHistory(assignment.WorkerID, assignment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location: %v", err)
+	}
+	if len(locations) == 0 {
+		return nil, errors.New("the worker has not shared their location yet")
+	}
+	return lts.buildResponse(assignment, &locations[0]), nil
+}
+
+// buildResponse builds the tracking response sent to workers and customers
+func (lts *LocationTrackingService) buildResponse(assignment *models.WorkerAssignment, location *models.WorkerLocation) *models.WorkerLocationResponse {
+	return &models.WorkerLocationResponse{
+		WorkerID:       location.WorkerID,
+		AssignmentID:   location.AssignmentID,
+		BookingID:      location.BookingID,
+		Latitude:       location.Latitude,
+		Longitude:      location.Longitude,
+		Accuracy:       location.Accuracy,
+		Status:         location.Status,
+		LastUpdated:    location.LastUpdated,
+		WorkerName:     assignment.Worker.Name,
+		CustomerName:   assignment.Booking.User.Name,
+		HasArrived:     assignment.ArrivedAt != nil,
+		ArrivedAt:      assignment.ArrivedAt,
+		DistanceMeters: location.DistanceMeters,
+		ETASeconds:     location.ETASeconds,
+		ETAUpdatedAt:   location.ETAUpdatedAt,
+	}
+}
+
+// broadcast sends a tracking message to the booking's chat room, which the customer's app joins
+func (lts *LocationTrackingService) broadcast(bookingID uint, messageType string, response *models.WorkerLocationResponse) {
+	if lts.wsService == nil {
+		return
+	}
+
+	roomID, ok := lts.bookingRooms.Load(bookingID)
+	if !ok {
+		room, err := lts.chatRoomRepo.GetByBookingID(bookingID)
+		if err != nil {
+			logrus.Debugf("LocationTrackingService: booking %d has no chat room to broadcast to", bookingID)
+			return
+		}
+		roomID = room.ID
+		lts.bookingRooms.Store(bookingID, room.ID)
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		logrus.Errorf("LocationTrackingService: failed to encode location: %v", err)
+		return
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		logrus.Errorf("LocationTrackingService: failed to encode location: %v", err)
+		return
+	}
+
+	lts.wsService.BroadcastRoomMessage(roomID.(uint), messageType, payload)
+}
+
+// notifyArrival tells the customer that the worker has arrived
+func (lts *LocationTrackingService) notifyArrival(assignment *models.WorkerAssignment) {
+	if lts.enhancedNotificationService == nil {
+		return
+	}
+
+	_, err := lts.enhancedNotificationService.SendNotification(&NotificationRequest{
+		UserID: assignment.Booking.UserID,
+		Type:   models.NotificationTypeBooking,
+		Title:  "Worker Arrived!",
+		Body:   fmt.Sprintf("%s has arrived at your address", assignment.Worker.Name),
+		Data: map[string]string{
+			"type":         "booking",
+			"bookingId":    fmt.Sprintf("%d", assignment.BookingID),
+			"assignmentId": fmt.Sprintf("%d", assignment.ID),
+			"workerId":     fmt.Sprintf("%d", assignment.WorkerID),
+		},
+		Priority: "high",
+	})
+	if err != nil {
+		logrus.Errorf("LocationTrackingService: failed to send arrival notification for assignment %d: %v", assignment.ID, err)
+	}
+}
+
+// bookingCoordinates returns the coordinates of a booking's address, if it has them
+func bookingCoordinates(booking *models.Booking) (*models.LocationCoordinates, bool) {
+	if booking.Address == nil || *booking.Address == "" {
+		return nil, false
+	}
+
+	var address models.BookingAddress
+	if err := json.Unmarshal([]byte(*booking.Address), &address); err != nil {
+		return nil, false
+	}
+	if address.Latitude == 0 && address.Longitude == 0 {
+		return nil, false
+	}
+	return &models.LocationCoordinates{Latitude: address.Latitude, Longitude: address.Longitude}, true
+}
@@ -0,0 +1,70 @@
+package services_test
+
+import (
+	"os"
+	"testing"
+	"treesindia/repositories"
+	"treesindia/services"
+	"treesindia/testutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.Main(m))
+}
+
+// flowFixture wires the booking, quote and wallet services to the test database, with online
+// payments going through the fake payment gateway
+type flowFixture struct {
+	gateway  *testutil.FakePaymentGateway
+	payments *services.PaymentService
+	wallet   *services.UnifiedWalletService
+	bookings *services.BookingService
+	quotes   *services.QuoteService
+}
+
+func newFlowFixture(t *testing.T) *flowFixture {
+	t.Helper()
+	db := testutil.RequireDB(t)
+
+	f := &flowFixture{gateway: testutil.NewFakePaymentGateway()}
+	f.payments = services.NewPaymentServiceWith(repositories.NewPaymentRepository(), f.gateway)
+	f.wallet = services.NewUnifiedWalletServiceWith(f.payments, repositories.NewUserRepository(), repositories.NewWalletLedgerRepository(), testutil.StaticWalletLimits{MinRecharge: 100})
+	adminConfig := services.NewAdminConfigService()
+	coupons := services.NewCouponService()
+	searchAnalytics := services.NewSearchAnalyticsService()
+	f.bookings = services.NewBookingServiceWith(
+		db,
+		repositories.NewBookingRepository(),
+		repositories.NewServiceRepository(),
+		repositories.NewUserRepository(),
+		repositories.NewWorkerAssignmentRepository(),
+		repositories.NewServiceAreaRepository(),
+		repositories.NewLocationRepository(),
+		repositories.NewAssignmentOfferRepository(),
+		f.payments,
+		f.wallet,
+		services.NewNotificationService(),
+		nil,
+		coupons,
+		searchAnalytics,
+		services.NewPricingService(),
+		services.NewAvailabilityService(),
+		adminConfig,
+		services.NewUserDocumentService(repositories.NewUserDocumentRepository(), nil),
+	)
+	f.quotes = services.NewQuoteServiceWith(
+		db,
+		repositories.NewBookingRepository(),
+		repositories.NewUserRepository(),
+		repositories.NewPaymentSegmentRepository(),
+		repositories.NewQuoteRevisionRepository(),
+		f.payments,
+		f.wallet,
+		services.NewPaymentSegmentMilestoneService(),
+		services.NewInquiryFeeServiceWith(repositories.NewPaymentRepository(), f.gateway, f.wallet),
+		adminConfig,
+		coupons,
+		searchAnalytics,
+	)
+	return f
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+	"treesindia/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+var mediaURLPattern = regexp.MustCompile(`https?://[^\s"',{}\[\]\\]+`)
+
+// MediaGCService deletes stored media that no record references any more, including chat
+// attachments past their retention window
+type MediaGCService struct {
+	mediaStore         MediaStore
+	mediaReferenceRepo *repositories.MediaReferenceRepository
+	adminConfigService *AdminConfigService
+	stopChan           chan bool
+}
+
+// NewMediaGCService creates a new media garbage collector for a media store
+func NewMediaGCService(mediaStore MediaStore) *MediaGCService {
+	return &MediaGCService{
+		mediaStore:         mediaStore,
+		mediaReferenceRepo: repositories.NewMediaReferenceRepository(),
+		adminConfigService: NewAdminConfigService(),
+		stopChan:           make(chan bool),
+	}
+}
+
+// Start begins the daily media garbage collection. Whether it deletes anything is controlled by
+// the media_gc_dry_run setting.
+func (gs *MediaGCService) Start() {
+	logrus.Info("MediaGCService starting...")
+
+	ticker := time.NewTicker(24 * time.Hour)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				gs.runScheduled()
+			case <-gs.stopChan:
+				ticker.Stop()
+				logrus.Info("MediaGCService stopped")
+				return
+			}
+		}
+	}()
+
+	logrus.Info("MediaGCService started successfully")
+}
+
+// Stop stops the media garbage collector
+func (gs *MediaGCService) Stop() {
+	logrus.Info("Stopping MediaGCService...")
+	gs.stopChan <- true
+}
+
+// runScheduled runs a collection with the configured dry run setting
+func (gs *MediaGCService) runScheduled() {
+	report, err := gs.Run(gs.adminConfigService.GetMediaGCDryRun())
+	if err != nil {
+		logrus.Errorf("MediaGCService: collection failed: %v", err)
+		return
+	}
+
+	logrus.Infof("MediaGCService: %d files stored, %d orphaned (%d bytes), %d deleted, %d failed, %d expired chat attachments (dry run: %v)",
+		report.StoredFiles, report.OrphanedFiles, report.OrphanedBytes, report.DeletedFiles, report.FailedDeletes,
+		report.ExpiredChatAttachments, report.DryRun)
+}
+
+// Run finds stored files that no record references and, unless dryRun is set, deletes them and
+// clears chat attachments past the retention window. Files uploaded within the grace period are
+// kept, since the record referencing them may not be saved yet.
+func (gs *MediaGCService) Run(dryRun bool) (*models.MediaGCReport, error) {
+	if gs.mediaStore == nil {
+		return nil, errors.New("media store is not available")
+	}
+
+	now := time.Now()
+	graceCutoff := now.Add(-time.Duration(gs.adminConfigService.GetMediaGCGraceHours()) * time.Hour)
+	report := &models.MediaGCReport{
+		DryRun:          dryRun,
+		StartedAt:       now,
+		ChatMediaCutoff: now.AddDate(0, 0, -gs.adminConfigService.GetChatMediaRetentionDays()),
+		Orphans:         []models.MediaGCOrphan{},
+	}
+
+	referenced, err := gs.referencedKeys(report.ChatMediaCutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect media references: %v", err)
+	}
+	report.ReferencedKeys = len(referenced)
+
+	var orphans []MediaObject
+	err = gs.mediaStore.ListMedia(func(object MediaObject) error {
+		report.StoredFiles++
+		report.StoredBytes += object.Size
+
+		if referenced[object.Key] {
+			return nil
+		}
+		if object.LastModified.After(graceCutoff) {
+			report.RecentFiles++
+			return nil
+		}
+
+		report.OrphanedFiles++
+		report.OrphanedBytes += object.Size
+		orphans = append(orphans, object)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stored media: %v", err)
+	}
+
+	for _, orphan := range orphans {
+		report.Orphans = append(report.Orphans, models.MediaGCOrphan{
+			URL:          orphan.URL,
+			Size:         orphan.Size,
+			LastModified: orphan.LastModified,
+		})
+	}
+
+	if dryRun {
+		report.ExpiredChatAttachments, err = gs.mediaReferenceRepo.CountExpiredChatAttachments(report.ChatMediaCutoff)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count expired chat attachments: %v", err)
+		}
+		report.FinishedAt = time.Now()
+		return report, nil
+	}
+
+	// Never delete everything because references could not be read
+	if len(referenced) == 0 && report.StoredFiles > 0 {
+		return nil, errors.New("no media references found; refusing to delete every stored file")
+	}
+
+	for _, orphan := range orphans {
+		if err := gs.mediaStore.Delete(orphan.URL); err != nil {
+			logrus.Warnf("MediaGCService: failed to delete %s: %v", orphan.URL, err)
+			report.FailedDeletes++
+			continue
+		}
+		report.DeletedFiles++
+	}
+
+	// Files that failed to delete stay unreferenced and are retried on the next run
+	report.ExpiredChatAttachments, err = gs.mediaReferenceRepo.ClearExpiredChatAttachments(report.ChatMediaCutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear expired chat attachments: %v", err)
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// referencedKeys collects the storage keys of every media URL saved in the database, together
+// with the thumbnails generated for processed images
+func (gs *MediaGCService) referencedKeys(chatMediaCutoff time.Time) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+
+	err := gs.mediaReferenceRepo.ForEachReference(chatMediaCutoff, func(value string) {
+		for _, url := range mediaURLPattern.FindAllString(value, -1) {
+			key := gs.mediaStore.KeyFromURL(url)
+			if key == "" {
+				continue
+			}
+
+			referenced[key] = true
+			for _, variant := range utils.ImageVariants {
+				referenced[utils.ImageVariantURL(key, variant.Name)] = true
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return referenced, nil
+}
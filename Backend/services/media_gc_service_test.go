@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+	"treesindia/services"
+	"treesindia/testutil"
+)
+
+func TestMediaGCDeletesUnreferencedMedia(t *testing.T) {
+	db := testutil.RequireDB(t)
+	testutil.SetAdminConfig(t, "media_gc_grace_hours", "0")
+	store := testutil.NewFakeMediaStore()
+	gc := services.NewMediaGCService(store)
+	user := testutil.CreateUser(t, 0)
+
+	avatarURL, err := store.Put(strings.NewReader("avatar"), "avatars/user.jpg", "image/jpeg")
+	if err != nil {
+		t.Fatalf("failed to store avatar: %v", err)
+	}
+	if _, err := store.Put(strings.NewReader("orphan"), "avatars/orphan.jpg", "image/jpeg"); err != nil {
+		t.Fatalf("failed to store orphan: %v", err)
+	}
+	if err := db.Model(user).Update("avatar", avatarURL).Error; err != nil {
+		t.Fatalf("failed to set avatar: %v", err)
+	}
+
+	// A dry run only reports the orphan
+	report, err := gc.Run(true)
+	if err != nil {
+		t.Fatalf("dry run returned error: %v", err)
+	}
+	if report.StoredFiles != 2 || report.OrphanedFiles != 1 || report.DeletedFiles != 0 {
+		t.Errorf("dry run stored/orphaned/deleted = %d/%d/%d, want 2/1/0", report.StoredFiles, report.OrphanedFiles, report.DeletedFiles)
+	}
+	if len(report.Orphans) != 1 || report.Orphans[0].URL != store.PublicURL("avatars/orphan.jpg") {
+		t.Errorf("dry run orphans = %v, want avatars/orphan.jpg", report.Orphans)
+	}
+	if _, ok := store.Get("avatars/orphan.jpg"); !ok {
+		t.Fatal("dry run deleted the orphan")
+	}
+
+	report, err = gc.Run(false)
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if report.DeletedFiles != 1 || report.FailedDeletes != 0 {
+		t.Errorf("deleted/failed = %d/%d, want 1/0", report.DeletedFiles, report.FailedDeletes)
+	}
+	if _, ok := store.Get("avatars/orphan.jpg"); ok {
+		t.Error("the orphan was not deleted")
+	}
+	if _, ok := store.Get("avatars/user.jpg"); !ok {
+		t.Error("the referenced avatar was deleted")
+	}
+}
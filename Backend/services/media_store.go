@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"treesindia/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Media storage backends
+const (
+	MediaBackendCloudinary = "cloudinary"
+	MediaBackendLocal      = "local"
+	MediaBackendS3         = "s3"
+)
+
+// MediaStore stores uploaded images and videos. Stored media is always referred to by the URL
+// returned from the upload methods, which is what the database keeps.
+type MediaStore interface {
+	// Backend returns the name of the storage backend
+	Backend() string
+
+	// UploadImage uploads an image and returns its public URL
+	UploadImage(file *multipart.FileHeader, folder string) (string, error)
+	// UploadVideo uploads a video and returns its public URL
+	UploadVideo(file *multipart.FileHeader, folder string) (string, error)
+	// UploadMedia uploads an image or a video depending on mediaType ("image" or "video")
+	UploadMedia(file *multipart.FileHeader, folder string, mediaType string) (string, error)
+	// UploadImageFromPath uploads an image from a local file path
+	UploadImageFromPath(filePath string, folder string) (string, error)
+	// UploadReader uploads media read from r under the given file name
+	UploadReader(r io.Reader, folder, filename, mediaType string) (string, error)
+	// Put stores media under an exact key, replacing any existing media
+	Put(r io.Reader, key, contentType string) (string, error)
+
+	// Delete deletes media by its URL
+	Delete(url string) error
+	// KeyFromURL returns the storage key of media URL, or "" if the URL is not from this store
+	KeyFromURL(url string) string
+	// PublicURL returns the public URL of a storage key
+	PublicURL(key string) string
+	// SignedURL returns a URL that grants temporary read access to media
+	SignedURL(url string, expiry time.Duration) (string, error)
+
+	// ListMedia calls fn for every stored media file, stopping at the first error fn returns
+	ListMedia(fn func(MediaObject) error) error
+}
+
+// privateMediaPrefixes are the key prefixes of media holding personal data, such as data exports.
+// Stores keep it out of public delivery, so it can only be read through SignedURL.
+var privateMediaPrefixes = []string{"data-exports/"}
+
+// IsPrivateMediaKey reports whether media stored under key can only be read through a signed URL
+func IsPrivateMediaKey(key string) bool {
+	for _, prefix := range privateMediaPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MediaObject is a file held by a media store
+type MediaObject struct {
+	Key          string
+	URL          string
+	Size         int64
+	LastModified time.Time
+}
+
+// NewMediaStore creates the media store selected by the MEDIA_STORAGE setting. Without a setting
+// Cloudinary is used when it is configured and local disk otherwise. Uploaded images go through
+// the image pipeline whichever backend is used.
+func NewMediaStore() (MediaStore, error) {
+	store, err := newBackendMediaStore()
+	if err != nil {
+		return nil, err
+	}
+	return NewImagePipelineStore(store), nil
+}
+
+// newBackendMediaStore creates the configured storage backend
+func newBackendMediaStore() (MediaStore, error) {
+	appConfig := config.LoadConfig()
+
+	backend := strings.ToLower(appConfig.MediaStorage)
+	if backend == "" {
+		if appConfig.CloudinaryURL != "" {
+			backend = MediaBackendCloudinary
+		} else {
+			backend = MediaBackendLocal
+		}
+	}
+
+	switch backend {
+	case MediaBackendCloudinary:
+		cloudinaryService, err := NewCloudinaryService()
+		if err != nil {
+			return nil, err
+		}
+		return cloudinaryService, nil
+	case MediaBackendLocal:
+		localStore, err := NewLocalMediaStore(appConfig.UploadPath, appConfig.MediaPublicBaseURL, appConfig.JWTSecret)
+		if err != nil {
+			return nil, err
+		}
+		return localStore, nil
+	case MediaBackendS3:
+		s3Store, err := NewS3MediaStore(appConfig)
+		if err != nil {
+			return nil, err
+		}
+		return s3Store, nil
+	default:
+		return nil, fmt.Errorf("unknown media storage backend: %s", appConfig.MediaStorage)
+	}
+}
+
+// objectPutter writes an object under a storage key
+type objectPutter func(ctx context.Context, r io.Reader, size int64, key, contentType string) error
+
+// keyedUploads implements the MediaStore upload methods for stores that address media by key
+type keyedUploads struct {
+	put       objectPutter
+	publicURL func(key string) string
+}
+
+// UploadImage uploads an image and returns its public URL
+func (ku *keyedUploads) UploadImage(file *multipart.FileHeader, folder string) (string, error) {
+	return ku.uploadFile(file, folder, "image", 15*time.Second)
+}
+
+// UploadVideo uploads a video and returns its public URL
+func (ku *keyedUploads) UploadVideo(file *multipart.FileHeader, folder string) (string, error) {
+	return ku.uploadFile(file, folder, "video", 60*time.Second)
+}
+
+// UploadMedia uploads either an image or video based on the media type
+func (ku *keyedUploads) UploadMedia(file *multipart.FileHeader, folder string, mediaType string) (string, error) {
+	if mediaType == "video" {
+		return ku.UploadVideo(file, folder)
+	}
+	return ku.UploadImage(file, folder)
+}
+
+// UploadImageFromPath uploads an image from a local file path
+func (ku *keyedUploads) UploadImageFromPath(filePath string, folder string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		logrus.Errorf("Failed to open file %s: %v", filePath, err)
+		return "", fmt.Errorf("failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to get file info: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	return ku.upload(ctx, file, fileInfo.Size(), folder, fileInfo.Name(), "image")
+}
+
+// UploadReader uploads media read from r under the given file name
+func (ku *keyedUploads) UploadReader(r io.Reader, folder, filename, mediaType string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	return ku.upload(ctx, r, -1, folder, filename, mediaType)
+}
+
+// Put stores media under an exact key and returns its public URL
+func (ku *keyedUploads) Put(r io.Reader, key, contentType string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	if err := ku.put(ctx, r, -1, key, contentType); err != nil {
+		return "", fmt.Errorf("failed to store %s: %v", key, err)
+	}
+	return ku.publicURL(key), nil
+}
+
+// uploadFile uploads a multipart file with the given timeout
+func (ku *keyedUploads) uploadFile(file *multipart.FileHeader, folder, mediaType string, timeout time.Duration) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		logrus.Errorf("Failed to open file %s: %v", file.Filename, err)
+		return "", fmt.Errorf("failed to open file: %v", err)
+	}
+	defer src.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return ku.upload(ctx, src, file.Size, folder, file.Filename, mediaType)
+}
+
+// upload stores media under a unique key and returns its public URL
+func (ku *keyedUploads) upload(ctx context.Context, r io.Reader, size int64, folder, filename, mediaType string) (string, error) {
+	key := newMediaKey(folder, filename)
+
+	if err := ku.put(ctx, r, size, key, mediaContentType(filename, mediaType)); err != nil {
+		logrus.Errorf("Failed to store %s %s: %v", mediaType, filename, err)
+		return "", fmt.Errorf("failed to upload %s: %v", mediaType, err)
+	}
+
+	url := ku.publicURL(key)
+	logrus.Infof("Successfully uploaded %s %s: %s", mediaType, filename, url)
+	return url, nil
+}
+
+var unsafeMediaNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// newMediaKey builds a unique storage key such as "properties/kitchen_1700000000000000000.jpg"
+func newMediaKey(folder, filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	baseName := unsafeMediaNameChars.ReplaceAllString(strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)), "_")
+	if baseName == "" || baseName == "_" {
+		baseName = "media"
+	}
+
+	name := fmt.Sprintf("%s_%d%s", baseName, time.Now().UnixNano(), ext)
+
+	folder = strings.Trim(path.Clean("/"+strings.ReplaceAll(folder, "\\", "/")), "/")
+	if folder == "" {
+		return name
+	}
+	return folder + "/" + name
+}
+
+// mediaContentType guesses the content type of a file from its extension
+func mediaContentType(filename, mediaType string) string {
+	if contentType := mime.TypeByExtension(strings.ToLower(filepath.Ext(filename))); contentType != "" {
+		return contentType
+	}
+	if mediaType == "video" {
+		return "video/mp4"
+	}
+	if mediaType == "image" {
+		return "image/jpeg"
+	}
+	return "application/octet-stream"
+}
+
+// keyFromBaseURL returns the key of a URL under baseURL, without any query string
+func keyFromBaseURL(baseURL, url string) string {
+	prefix := strings.TrimSuffix(baseURL, "/") + "/"
+	if !strings.HasPrefix(url, prefix) {
+		return ""
+	}
+
+	key := strings.TrimPrefix(url, prefix)
+	if i := strings.IndexAny(key, "?#"); i >= 0 {
+		key = key[:i]
+	}
+	return key
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PaymentSegmentMilestoneService ties payment segment due dates and work gates to booking milestones
+type PaymentSegmentMilestoneService struct {
+	paymentSegmentRepo *repositories.PaymentSegmentRepository
+}
+
+// NewPaymentSegmentMilestoneService creates a new payment segment milestone service
+func NewPaymentSegmentMilestoneService() *PaymentSegmentMilestoneService {
+	return &PaymentSegmentMilestoneService{
+		paymentSegmentRepo: repositories.NewPaymentSegmentRepository(),
+	}
+}
+
+// ActivateTrigger marks segments waiting on the milestone as due and resolves any
+// "N days after previous" segments that follow them
+func (pms *PaymentSegmentMilestoneService) ActivateTrigger(bookingID uint, trigger models.PaymentSegmentDueTrigger) {
+	now := time.Now()
+
+	activated, err := pms.paymentSegmentRepo.ActivateDueTrigger(bookingID, trigger, now)
+	if err != nil {
+		logrus.Errorf("Failed to activate %s payment segments for booking %d: %v", trigger, bookingID, err)
+		return
+	}
+
+	if err := pms.paymentSegmentRepo.ResolveRelativeDueDates(bookingID); err != nil {
+		logrus.Errorf("Failed to resolve relative payment segment due dates for booking %d: %v", bookingID, err)
+		return
+	}
+
+	if activated > 0 {
+		logrus.Infof("Activated %d %s payment segment(s) for booking %d", activated, trigger, bookingID)
+	}
+}
+
+// CheckGate returns an error if an unpaid segment holds the given work stage
+func (pms *PaymentSegmentMilestoneService) CheckGate(bookingID uint, gate models.PaymentSegmentGate) error {
+	segments, err := pms.paymentSegmentRepo.GetUnpaidSegmentsRequiredBefore(bookingID, gate)
+	if err != nil {
+		logrus.Errorf("Failed to check payment segment gate %s for booking %d: %v", gate, bookingID, err)
+		return errors.New("failed to verify payment status")
+	}
+
+	if len(segments) == 0 {
+		return nil
+	}
+
+	stage := "work can start"
+	if gate == models.PaymentSegmentGateCompletion {
+		stage = "work can be completed"
+	}
+	return fmt.Errorf("payment segment #%d must be paid before %s", segments[0].SegmentNumber, stage)
+}
+
+// UpdateSchedule changes the milestone trigger and work gate of a segment (admin only)
+func (pms *PaymentSegmentMilestoneService) UpdateSchedule(bookingID uint, segmentID uint, req *models.UpdatePaymentSegmentScheduleRequest) (*models.PaymentSegment, error) {
+	segment, err := pms.paymentSegmentRepo.GetByID(segmentID)
+	if err != nil || segment.BookingID != bookingID {
+		return nil, errors.New("payment segment not found")
+	}
+
+	if !segment.IsUnpaid() {
+		return nil, errors.New("only unpaid payment segments can be rescheduled")
+	}
+
+	previousDueAt := segment.DueAt
+	if req.DueTrigger != nil && *req.DueTrigger != segment.DueTrigger {
+		segment.DueTrigger = *req.DueTrigger
+		segment.DueAt = nil
+	}
+	if req.DueAfterDays != nil {
+		segment.DueAfterDays = req.DueAfterDays
+	}
+	if req.RequiredBefore != nil {
+		segment.RequiredBefore = *req.RequiredBefore
+	}
+
+	if err := ValidateSegmentSchedule(segment.SegmentNumber, segment.DueTrigger, segment.DueAfterDays); err != nil {
+		return nil, err
+	}
+
+	// The new schedule decides the due date from scratch, so the next reminder
+	// run works from the recomputed date rather than the old one
+	dueAt, err := pms.scheduledDueAt(segment)
+	if err != nil {
+		return nil, err
+	}
+	segment.DueAt = dueAt
+	if !sameDueAt(previousDueAt, dueAt) {
+		segment.OverdueAt = nil
+		segment.Status = models.PaymentSegmentStatusPending
+	}
+
+	if err := pms.paymentSegmentRepo.UpdateSchedule(segment); err != nil {
+		return nil, fmt.Errorf("failed to update payment segment: %v", err)
+	}
+
+	return pms.paymentSegmentRepo.GetByID(segmentID)
+}
+
+// scheduledDueAt works out when a segment falls due under its current schedule, or nil if it is not due yet
+func (pms *PaymentSegmentMilestoneService) scheduledDueAt(segment *models.PaymentSegment) (*time.Time, error) {
+	switch segment.DueTrigger {
+	case models.PaymentSegmentDueManual:
+		return nil, nil
+	case models.PaymentSegmentDueDaysAfterPrevious:
+		previous, err := pms.paymentSegmentRepo.GetByBookingIDAndSegmentNumber(segment.BookingID, segment.SegmentNumber-1)
+		if err != nil {
+			return nil, errors.New("previous payment segment not found")
+		}
+		if previous.DueAt == nil {
+			return nil, nil
+		}
+		dueAt := previous.DueAt.AddDate(0, 0, *segment.DueAfterDays)
+		return &dueAt, nil
+	default:
+		if !pms.isMilestoneReached(&segment.Booking, segment.DueTrigger) {
+			return nil, nil
+		}
+		// Keep the existing due date when the milestone has not changed
+		if segment.DueAt != nil {
+			return segment.DueAt, nil
+		}
+		now := time.Now()
+		return &now, nil
+	}
+}
+
+// sameDueAt reports whether two optional due dates are the same
+func sameDueAt(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// isMilestoneReached reports whether the booking has already passed the milestone
+func (pms *PaymentSegmentMilestoneService) isMilestoneReached(booking *models.Booking, trigger models.PaymentSegmentDueTrigger) bool {
+	switch trigger {
+	case models.PaymentSegmentDueOnBooking:
+		return booking.QuoteAcceptedAt != nil
+	case models.PaymentSegmentDueOnWorkStart:
+		return booking.ActualStartTime != nil
+	case models.PaymentSegmentDueOnCompletion:
+		return booking.ActualEndTime != nil
+	default:
+		return false
+	}
+}
+
+// ValidateSegmentSchedule validates a segment's milestone configuration
+func ValidateSegmentSchedule(segmentNumber int, trigger models.PaymentSegmentDueTrigger, dueAfterDays *int) error {
+	if trigger != models.PaymentSegmentDueDaysAfterPrevious {
+		return nil
+	}
+	if segmentNumber == 1 {
+		return errors.New("the first payment segment cannot be due after a previous segment")
+	}
+	if dueAfterDays == nil || *dueAfterDays < 1 {
+		return fmt.Errorf("payment segment #%d requires due_after_days of at least 1", segmentNumber)
+	}
+	return nil
+}
@@ -4,18 +4,23 @@ import (
 	"fmt"
 	"time"
 	"treesindia/models"
+	"treesindia/repositories"
 
 	"github.com/sirupsen/logrus"
 )
 
 type PaymentSegmentReminderService struct {
 	enhancedNotificationService *EnhancedNotificationService
+	paymentSegmentRepo          *repositories.PaymentSegmentRepository
+	adminConfigService          *AdminConfigService
 	stopChan                    chan bool
 }
 
 func NewPaymentSegmentReminderService(enhancedNotificationService *EnhancedNotificationService) *PaymentSegmentReminderService {
 	return &PaymentSegmentReminderService{
 		enhancedNotificationService: enhancedNotificationService,
+		paymentSegmentRepo:          repositories.NewPaymentSegmentRepository(),
+		adminConfigService:          NewAdminConfigService(),
 		stopChan:                    make(chan bool),
 	}
 }
@@ -27,8 +32,8 @@ func (ps *PaymentSegmentReminderService) Start() {
 	// Run immediately on start
 	go ps.checkAndSendReminders()
 
-	// Then run every 12 hours
-	ticker := time.NewTicker(12 * time.Hour)
+	// Then run every hour
+	ticker := time.NewTicker(1 * time.Hour)
 	go func() {
 		for {
 			select {
@@ -51,16 +56,71 @@ func (ps *PaymentSegmentReminderService) Stop() {
 	ps.stopChan <- true
 }
 
-// checkAndSendReminders checks for upcoming due dates and sends reminders
-// NOTE: Due dates have been removed from payment segments. This functionality is now disabled.
+// checkAndSendReminders marks segments overdue once their grace period ends and
+// reminds customers about segments that have fallen due
 func (ps *PaymentSegmentReminderService) checkAndSendReminders() {
-	logrus.Info("PaymentSegmentReminderService: Due date functionality has been removed - skipping reminder checks")
-	// Due dates are no longer used in the system, so this service does nothing
-	return
+	now := time.Now()
+
+	// Resolve "N days after previous" segments whose previous segment fell due
+	bookingIDs, err := ps.paymentSegmentRepo.GetBookingIDsWithUnresolvedRelativeSegments()
+	if err != nil {
+		logrus.Errorf("PaymentSegmentReminderService: failed to get bookings with relative segments: %v", err)
+	}
+	for _, bookingID := range bookingIDs {
+		if err := ps.paymentSegmentRepo.ResolveRelativeDueDates(bookingID); err != nil {
+			logrus.Errorf("PaymentSegmentReminderService: failed to resolve due dates for booking %d: %v", bookingID, err)
+		}
+	}
+
+	segments, err := ps.paymentSegmentRepo.GetDueUnpaidSegments(now)
+	if err != nil {
+		logrus.Errorf("PaymentSegmentReminderService: failed to get due segments: %v", err)
+		return
+	}
+	if len(segments) == 0 {
+		return
+	}
+
+	graceHours := ps.adminConfigService.GetPaymentSegmentOverdueGraceHours()
+	reminderInterval := time.Duration(ps.adminConfigService.GetPaymentSegmentReminderIntervalHours()) * time.Hour
+	maxReminders := ps.adminConfigService.GetPaymentSegmentMaxReminders()
+
+	overdueCount, reminderCount := 0, 0
+	for i := range segments {
+		segment := &segments[i]
+
+		// Mark as overdue once the grace period has passed
+		if segment.Status == models.PaymentSegmentStatusPending && segment.DueAt.Add(time.Duration(graceHours)*time.Hour).Before(now) {
+			if err := ps.paymentSegmentRepo.MarkAsOverdue(segment.ID, now); err != nil {
+				logrus.Errorf("PaymentSegmentReminderService: failed to mark segment %d overdue: %v", segment.ID, err)
+			} else {
+				segment.Status = models.PaymentSegmentStatusOverdue
+				segment.OverdueAt = &now
+				overdueCount++
+			}
+		}
+
+		if segment.ReminderCount >= maxReminders {
+			continue
+		}
+		if segment.LastReminderAt != nil && now.Sub(*segment.LastReminderAt) < reminderInterval {
+			continue
+		}
+
+		if err := ps.paymentSegmentRepo.RecordReminder(segment.ID, now); err != nil {
+			logrus.Errorf("PaymentSegmentReminderService: failed to record reminder for segment %d: %v", segment.ID, err)
+			continue
+		}
+		ps.sendSegmentDueReminder(segment)
+		reminderCount++
+	}
+
+	logrus.Infof("PaymentSegmentReminderService: %d due segments checked, %d marked overdue, %d reminders sent",
+		len(segments), overdueCount, reminderCount)
 }
 
-// sendSegmentDueReminder sends a notification reminder for an upcoming payment segment
-func (ps *PaymentSegmentReminderService) sendSegmentDueReminder(segment *models.PaymentSegment, daysUntilDue int) {
+// sendSegmentDueReminder sends a notification reminder for a due or overdue payment segment
+func (ps *PaymentSegmentReminderService) sendSegmentDueReminder(segment *models.PaymentSegment) {
 	// This runs in a goroutine, so it doesn't block the checking process
 	go func() {
 		// Skip if notification service is not available
@@ -69,34 +129,38 @@ func (ps *PaymentSegmentReminderService) sendSegmentDueReminder(segment *models.
 			return
 		}
 
-		var title, body string
+		var title, body, notificationType string
 
-		if daysUntilDue == 0 {
-			title = "Payment Due Today!"
-			body = fmt.Sprintf("Your payment segment #%d of ₹%.2f for booking #%d is due today. Please make the payment to avoid any issues.",
-				segment.SegmentNumber, segment.Amount, segment.BookingID)
-		} else if daysUntilDue == 1 {
-			title = "Payment Due Tomorrow!"
-			body = fmt.Sprintf("Your payment segment #%d of ₹%.2f for booking #%d is due tomorrow. Please make the payment soon.",
+		if segment.Status == models.PaymentSegmentStatusOverdue {
+			notificationType = "payment_segment_overdue"
+			title = "Payment Overdue"
+			body = fmt.Sprintf("Your payment segment #%d of ₹%.2f for booking #%d is overdue. Please make the payment as soon as possible.",
 				segment.SegmentNumber, segment.Amount, segment.BookingID)
 		} else {
-			title = "Payment Due Soon!"
-			body = fmt.Sprintf("Your payment segment #%d of ₹%.2f for booking #%d is due in %d days. Please plan your payment accordingly.",
-				segment.SegmentNumber, segment.Amount, segment.BookingID, daysUntilDue)
+			notificationType = "payment_segment_due"
+			title = "Payment Due"
+			body = fmt.Sprintf("Your payment segment #%d of ₹%.2f for booking #%d is now due. Please make the payment to avoid any issues.",
+				segment.SegmentNumber, segment.Amount, segment.BookingID)
+		}
+
+		if segment.RequiredBefore == models.PaymentSegmentGateWorkStart {
+			body += " Work will start once this payment is received."
+		} else if segment.RequiredBefore == models.PaymentSegmentGateCompletion {
+			body += " Work can be completed once this payment is received."
 		}
 
 		notificationReq := &NotificationRequest{
-			UserID:   segment.Booking.UserID,
-			Type:     models.NotificationTypePayment,
-			Title:    title,
-			Body:     body,
+			UserID: segment.Booking.UserID,
+			Type:   models.NotificationTypePayment,
+			Title:  title,
+			Body:   body,
 			Data: map[string]string{
-				"type":          "payment_segment_due",
+				"type":          notificationType,
 				"bookingId":     fmt.Sprintf("%d", segment.BookingID),
 				"segmentId":     fmt.Sprintf("%d", segment.ID),
 				"segmentNumber": fmt.Sprintf("%d", segment.SegmentNumber),
 				"amount":        fmt.Sprintf("%.2f", segment.Amount),
-				"daysUntilDue":  fmt.Sprintf("%d", daysUntilDue),
+				"dueAt":         segment.DueAt.Format(time.RFC3339),
 			},
 			Priority: "high",
 		}
@@ -105,8 +169,8 @@ func (ps *PaymentSegmentReminderService) sendSegmentDueReminder(segment *models.
 		if err != nil {
 			logrus.Errorf("Failed to send due reminder notification for segment %d: %v", segment.ID, err)
 		} else {
-			logrus.Infof("Sent due reminder notification for segment %d (booking %d) to user %d - %d days until due",
-				segment.ID, segment.BookingID, segment.Booking.UserID, daysUntilDue)
+			logrus.Infof("Sent %s reminder for segment %d (booking %d) to user %d",
+				segment.Status, segment.ID, segment.BookingID, segment.Booking.UserID)
 		}
 	}()
 }
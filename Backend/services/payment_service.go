@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"treesindia/metrics"
 	"treesindia/models"
 	"treesindia/repositories"
 
@@ -13,19 +14,39 @@ import (
 )
 
 type PaymentService struct {
-	paymentRepo     *repositories.PaymentRepository
-	razorpayService *RazorpayService
+	paymentRepo     PaymentStore
+	razorpayService PaymentGateway
+	couponService   *CouponService
 }
 
 func NewPaymentService() *PaymentService {
+	return NewPaymentServiceWith(repositories.NewPaymentRepository(), NewRazorpayService())
+}
+
+// NewPaymentServiceWith creates a payment service that stores payments in paymentRepo and takes
+// online payments through gateway
+func NewPaymentServiceWith(paymentRepo PaymentStore, gateway PaymentGateway) *PaymentService {
 	return &PaymentService{
-		paymentRepo:     repositories.NewPaymentRepository(),
-		razorpayService: NewRazorpayService(),
+		paymentRepo:     paymentRepo,
+		razorpayService: gateway,
+		couponService:   NewCouponService(),
 	}
 }
 
 // CreatePayment creates a new payment record
 func (ps *PaymentService) CreatePayment(req *models.CreatePaymentRequest) (*models.Payment, error) {
+	payment := ps.newPayment(req)
+
+	err := ps.paymentRepo.Create(payment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment: %v", err)
+	}
+
+	return payment, nil
+}
+
+// newPayment builds a pending payment for req without storing it
+func (ps *PaymentService) newPayment(req *models.CreatePaymentRequest) *models.Payment {
 	// Generate payment reference
 	paymentReference := ps.generatePaymentReference()
 
@@ -39,7 +60,7 @@ func (ps *PaymentService) CreatePayment(req *models.CreatePaymentRequest) (*mode
 		metadata = &emptyMap
 	}
 
-	payment := &models.Payment{
+	return &models.Payment{
 		PaymentReference:  paymentReference,
 		UserID:           req.UserID,
 		Amount:           req.Amount,
@@ -54,13 +75,6 @@ func (ps *PaymentService) CreatePayment(req *models.CreatePaymentRequest) (*mode
 		Metadata:         metadata,
 		InitiatedAt:      time.Now(),
 	}
-
-	err := ps.paymentRepo.Create(payment)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create payment: %v", err)
-	}
-
-	return payment, nil
 }
 
 // CreateRazorpayOrder creates a Razorpay order and payment record
@@ -117,6 +131,7 @@ func (ps *PaymentService) VerifyAndCompletePayment(paymentID uint, razorpayPayme
 		if err != nil {
 			return nil, fmt.Errorf("failed to update payment status: %v", err)
 		}
+		metrics.RecordPayment(string(payment.Type), string(payment.Status))
 		return nil, fmt.Errorf("payment signature verification failed")
 	}
 
@@ -132,6 +147,10 @@ func (ps *PaymentService) VerifyAndCompletePayment(paymentID uint, razorpayPayme
 	if err != nil {
 		return nil, fmt.Errorf("failed to update payment status: %v", err)
 	}
+	metrics.RecordPayment(string(payment.Type), string(payment.Status))
+
+	// Any coupon reserved on this payment is now used
+	ps.couponService.ApplyForPayment(payment.ID)
 
 	// Handle payment completion based on type
 	if payment.RelatedEntityType == "booking" && payment.RelatedEntityID != 0 {
@@ -215,15 +234,23 @@ func (ps *PaymentService) handleSegmentPaymentCompletion(booking *models.Booking
 		return fmt.Errorf("failed to check if all segments are paid: %v", err)
 	}
 
-	// Update booking status
+	// Update booking status. Milestone segments can be paid after work has
+	// started, so only bookings that are still waiting on payment move status.
 	bookingRepo := repositories.NewBookingRepository()
+	awaitingPayment := booking.Status == models.BookingStatusQuoteAccepted ||
+		booking.Status == models.BookingStatusPartiallyPaid ||
+		booking.Status == models.BookingStatusConfirmed
 	if allPaid {
 		// All segments paid - booking is confirmed
-		booking.Status = models.BookingStatusConfirmed
+		if awaitingPayment {
+			booking.Status = models.BookingStatusConfirmed
+		}
 		booking.PaymentStatus = "completed"
 	} else {
 		// Some segments still pending - booking is partially paid
-		booking.Status = models.BookingStatusPartiallyPaid
+		if awaitingPayment {
+			booking.Status = models.BookingStatusPartiallyPaid
+		}
 		booking.PaymentStatus = "partial"
 	}
 
@@ -248,8 +275,8 @@ func (ps *PaymentService) handleRegularBookingPaymentCompletion(booking *models.
 	if len(segments) > 0 {
 		now := time.Now()
 		for i := range segments {
-			// Only update pending segments
-			if segments[i].Status == models.PaymentSegmentStatusPending {
+			// Only update unpaid segments
+			if segments[i].IsUnpaid() {
 				segments[i].Status = models.PaymentSegmentStatusPaid
 				segments[i].PaymentID = &payment.ID
 				segments[i].PaidAt = &now
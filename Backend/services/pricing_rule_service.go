@@ -0,0 +1,351 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// PricingRuleService manages surge pricing rules and the holiday calendar
+type PricingRuleService struct {
+	pricingRuleRepo    *repositories.PricingRuleRepository
+	adminConfigService *AdminConfigService
+}
+
+// NewPricingRuleService creates a new pricing rule service
+func NewPricingRuleService() *PricingRuleService {
+	return &PricingRuleService{
+		pricingRuleRepo:    repositories.NewPricingRuleRepository(),
+		adminConfigService: NewAdminConfigService(),
+	}
+}
+
+// GetRules gets pricing rules for admins
+func (prs *PricingRuleService) GetRules(filters *models.PricingRuleFilters) ([]models.PricingRule, *repositories.Pagination, error) {
+	return prs.pricingRuleRepo.GetRules(filters)
+}
+
+// GetRule gets a pricing rule
+func (prs *PricingRuleService) GetRule(id uint) (*models.PricingRule, error) {
+	rule, err := prs.pricingRuleRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("pricing rule not found")
+	}
+	return rule, nil
+}
+
+// CreateRule creates a pricing rule
+func (prs *PricingRuleService) CreateRule(adminID uint, req *models.CreatePricingRuleRequest) (*models.PricingRule, error) {
+	rule := &models.PricingRule{CreatedBy: &adminID}
+	if err := applyPricingRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := prs.pricingRuleRepo.Create(rule); err != nil {
+		return nil, fmt.Errorf("failed to create pricing rule: %v", err)
+	}
+	return rule, nil
+}
+
+// UpdateRule replaces the conditions and adjustment of a pricing rule
+func (prs *PricingRuleService) UpdateRule(id uint, req *models.UpdatePricingRuleRequest) (*models.PricingRule, error) {
+	rule, err := prs.pricingRuleRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("pricing rule not found")
+	}
+	if err := applyPricingRuleRequest(rule, &req.CreatePricingRuleRequest); err != nil {
+		return nil, err
+	}
+
+	if err := prs.pricingRuleRepo.Update(rule); err != nil {
+		return nil, fmt.Errorf("failed to update pricing rule: %v", err)
+	}
+	return rule, nil
+}
+
+// DeleteRule deletes a pricing rule. Bookings keep the rule they were priced with.
+func (prs *PricingRuleService) DeleteRule(id uint) error {
+	if err := prs.pricingRuleRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("pricing rule not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// GetHolidays gets the holidays between two dates
+func (prs *PricingRuleService) GetHolidays(from, to time.Time) ([]models.Holiday, error) {
+	return prs.pricingRuleRepo.GetHolidays(from, to)
+}
+
+// CreateHoliday adds a holiday to the calendar
+func (prs *PricingRuleService) CreateHoliday(req *models.CreateHolidayRequest) (*models.Holiday, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.New("invalid date format, use YYYY-MM-DD")
+	}
+
+	holiday := &models.Holiday{
+		Date:   date,
+		Name:   req.Name,
+		Cities: pq.StringArray(req.Cities),
+	}
+	if err := prs.pricingRuleRepo.CreateHoliday(holiday); err != nil {
+		return nil, fmt.Errorf("failed to create holiday: %v", err)
+	}
+	return holiday, nil
+}
+
+// DeleteHoliday removes a holiday from the calendar
+func (prs *PricingRuleService) DeleteHoliday(id uint) error {
+	if err := prs.pricingRuleRepo.DeleteHoliday(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("holiday not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// NewSurgePricer loads the rules that can apply to a service booked on a date at a location
+func (prs *PricingRuleService) NewSurgePricer(service *models.Service, date time.Time, location models.PricingLocation) (*SurgePricer, error) {
+	pricer := &SurgePricer{service: service, location: location}
+	if service.PriceType != "fixed" || !prs.adminConfigService.GetSurgePricingEnabled() {
+		return pricer, nil
+	}
+
+	rules, err := prs.pricingRuleRepo.GetActiveRulesOn(date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pricing rules: %v", err)
+	}
+	holidays, err := prs.pricingRuleRepo.GetHolidays(date, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %v", err)
+	}
+
+	pricer.rules = rules
+	pricer.maxMultiplier = prs.adminConfigService.GetSurgeMaxMultiplier()
+	for _, holiday := range holidays {
+		if len(holiday.Cities) == 0 || containsFold(holiday.Cities, location.City) {
+			pricer.isHoliday = true
+			break
+		}
+	}
+	return pricer, nil
+}
+
+// SurgePricer applies pricing rules to the slots of one day
+type SurgePricer struct {
+	service       *models.Service
+	location      models.PricingLocation
+	rules         []models.PricingRule // Highest priority first
+	isHoliday     bool
+	maxMultiplier float64
+}
+
+// Apply returns a copy of a price breakdown with the highest priority rule matching the slot applied.
+// utilisation is the percentage of workers already busy in the slot.
+func (sp *SurgePricer) Apply(breakdown *models.ServicePriceBreakdown, slotTime time.Time, utilisation float64) *models.ServicePriceBreakdown {
+	priced := *breakdown
+	priced.PricingRule = nil
+	priced.Total = roundAmount(priced.BaseAmount + priced.AddOnsAmount)
+
+	for i := range sp.rules {
+		rule := &sp.rules[i]
+		if !sp.matches(rule, slotTime, utilisation) {
+			continue
+		}
+
+		amount := ruleAdjustment(rule, priced.Total, sp.maxMultiplier)
+		if amount == 0 {
+			return &priced
+		}
+
+		priced.PricingRule = &models.AppliedPricingRule{
+			RuleID:          rule.ID,
+			Name:            rule.Name,
+			AdjustmentType:  rule.AdjustmentType,
+			AdjustmentValue: rule.AdjustmentValue,
+			Amount:          amount,
+		}
+		priced.Total = roundAmount(priced.Total + amount)
+		return &priced
+	}
+	return &priced
+}
+
+// matches reports whether every condition of a rule holds for a slot
+func (sp *SurgePricer) matches(rule *models.PricingRule, slotTime time.Time, utilisation float64) bool {
+	if len(rule.DaysOfWeek) > 0 && !containsInt64(rule.DaysOfWeek, int64(slotTime.Weekday())) {
+		return false
+	}
+	if !inTimeWindow(rule.StartTime, rule.EndTime, slotTime) {
+		return false
+	}
+	if rule.HolidaysOnly && !sp.isHoliday {
+		return false
+	}
+	if len(rule.Cities) > 0 && !containsFold(rule.Cities, sp.location.City) {
+		return false
+	}
+	if len(rule.Pincodes) > 0 && !containsFold(rule.Pincodes, sp.location.Pincode) {
+		return false
+	}
+	if len(rule.ServiceIDs) > 0 && !containsInt64(rule.ServiceIDs, int64(sp.service.ID)) {
+		return false
+	}
+	if len(rule.CategoryIDs) > 0 && !containsInt64(rule.CategoryIDs, int64(sp.service.CategoryID)) {
+		return false
+	}
+	if rule.MinUtilisation != nil && utilisation < *rule.MinUtilisation {
+		return false
+	}
+	return true
+}
+
+// ruleAdjustment calculates the amount a rule adds to a price, within the rule's cap and the
+// configured maximum multiplier
+func ruleAdjustment(rule *models.PricingRule, price, maxMultiplier float64) float64 {
+	var amount float64
+	switch rule.AdjustmentType {
+	case models.PricingAdjustmentMultiplier:
+		amount = price * (rule.AdjustmentValue - 1)
+	case models.PricingAdjustmentFlat:
+		amount = rule.AdjustmentValue
+	}
+
+	if rule.MaxUplift != nil && amount > *rule.MaxUplift {
+		amount = *rule.MaxUplift
+	}
+	if maxMultiplier >= 1 && amount > price*(maxMultiplier-1) {
+		amount = price * (maxMultiplier - 1)
+	}
+	// Off-peak multipliers below 1 can't make a service free
+	amount = math.Max(amount, -price)
+
+	return roundAmount(amount)
+}
+
+// inTimeWindow reports whether a slot starts within an "HH:MM" window, which may wrap past midnight
+func inTimeWindow(start, end *string, slotTime time.Time) bool {
+	if start == nil || end == nil || *start == "" || *end == "" {
+		return true
+	}
+
+	startTime, err := time.Parse("15:04", *start)
+	if err != nil {
+		return false
+	}
+	endTime, err := time.Parse("15:04", *end)
+	if err != nil {
+		return false
+	}
+
+	minute := slotTime.Hour()*60 + slotTime.Minute()
+	startMinute := startTime.Hour()*60 + startTime.Minute()
+	endMinute := endTime.Hour()*60 + endTime.Minute()
+
+	switch {
+	case startMinute < endMinute:
+		return minute >= startMinute && minute < endMinute
+	case startMinute > endMinute:
+		return minute >= startMinute || minute < endMinute
+	default:
+		return true
+	}
+}
+
+// applyPricingRuleRequest validates a pricing rule request and copies it onto a rule
+func applyPricingRuleRequest(rule *models.PricingRule, req *models.CreatePricingRuleRequest) error {
+	if (req.StartTime == nil) != (req.EndTime == nil) {
+		return errors.New("start_time and end_time must be set together")
+	}
+	for _, value := range []*string{req.StartTime, req.EndTime} {
+		if value != nil {
+			if _, err := time.Parse("15:04", *value); err != nil {
+				return errors.New("invalid time format, use HH:MM")
+			}
+		}
+	}
+
+	startDate, err := parseOptionalDate(req.StartDate)
+	if err != nil {
+		return err
+	}
+	endDate, err := parseOptionalDate(req.EndDate)
+	if err != nil {
+		return err
+	}
+	if startDate != nil && endDate != nil && endDate.Before(*startDate) {
+		return errors.New("end_date must not be before start_date")
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	rule.Name = req.Name
+	rule.Description = req.Description
+	rule.Priority = req.Priority
+	rule.IsActive = isActive
+	rule.DaysOfWeek = pq.Int64Array(req.DaysOfWeek)
+	rule.StartTime = req.StartTime
+	rule.EndTime = req.EndTime
+	rule.StartDate = startDate
+	rule.EndDate = endDate
+	rule.HolidaysOnly = req.HolidaysOnly
+	rule.Cities = pq.StringArray(req.Cities)
+	rule.Pincodes = pq.StringArray(req.Pincodes)
+	rule.ServiceIDs = pq.Int64Array(req.ServiceIDs)
+	rule.CategoryIDs = pq.Int64Array(req.CategoryIDs)
+	rule.MinUtilisation = req.MinUtilisation
+	rule.AdjustmentType = req.AdjustmentType
+	rule.AdjustmentValue = req.AdjustmentValue
+	rule.MaxUplift = req.MaxUplift
+	return nil
+}
+
+// parseOptionalDate parses an optional YYYY-MM-DD date
+func parseOptionalDate(value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", *value)
+	if err != nil {
+		return nil, errors.New("invalid date format, use YYYY-MM-DD")
+	}
+	return &date, nil
+}
+
+// containsFold reports whether values contains value, ignoring case and surrounding spaces
+func containsFold(values []string, value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsInt64 reports whether values contains value
+func containsInt64(values []int64, value int64) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
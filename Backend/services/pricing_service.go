@@ -0,0 +1,183 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"treesindia/models"
+	"treesindia/repositories"
+	"treesindia/utils"
+)
+
+// defaultServiceDurationMinutes is used for services without a duration
+const defaultServiceDurationMinutes = 120
+
+// PricingService prices a service with the variant, quantity and add-ons a customer chose
+type PricingService struct {
+	serviceOptionRepo *repositories.ServiceOptionRepository
+}
+
+// NewPricingService creates a new pricing service
+func NewPricingService() *PricingService {
+	return &PricingService{
+		serviceOptionRepo: repositories.NewServiceOptionRepository(),
+	}
+}
+
+// Calculate returns the price and duration of a service selection. Fixed price services with
+// variants require one to be chosen; the variant's price and duration replace the service's and are
+// multiplied by the quantity. Add-on prices and durations are added on top. Inquiry services can't
+// have options and only get their duration calculated.
+func (ps *PricingService) Calculate(service *models.Service, selection *models.ServiceSelection) (*models.ServicePriceBreakdown, error) {
+	if selection == nil {
+		selection = &models.ServiceSelection{}
+	}
+
+	quantity := selection.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+
+	breakdown := &models.ServicePriceBreakdown{
+		ServiceID:   service.ID,
+		ServiceName: service.Name,
+		PriceType:   service.PriceType,
+		Quantity:    quantity,
+		AddOns:      []models.ServicePriceAddOnLine{},
+	}
+
+	if service.PriceType != "fixed" {
+		if selection.VariantID != nil || len(selection.AddOns) > 0 || quantity > 1 {
+			return nil, errors.New("options can only be chosen for fixed price services")
+		}
+		minutes, err := durationMinutes(service.Duration, defaultServiceDurationMinutes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service duration: %v", err)
+		}
+		breakdown.UnitMinutes = minutes
+		breakdown.DurationMinutes = minutes
+		return breakdown, nil
+	}
+
+	variants, err := ps.serviceOptionRepo.GetVariants(service.ID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service options: %v", err)
+	}
+
+	unitPrice, unitDuration, maxQuantity := service.Price, service.Duration, service.MaxQuantity
+	if selection.VariantID != nil {
+		var variant *models.ServiceVariant
+		for i := range variants {
+			if variants[i].ID == *selection.VariantID {
+				variant = &variants[i]
+				break
+			}
+		}
+		if variant == nil {
+			return nil, errors.New("selected service option is not available")
+		}
+
+		unitPrice = &variant.Price
+		if variant.Duration != nil && *variant.Duration != "" {
+			unitDuration = variant.Duration
+		}
+		maxQuantity = variant.MaxQuantity
+		breakdown.VariantID = &variant.ID
+		breakdown.VariantName = variant.Name
+	} else if len(variants) > 0 {
+		return nil, errors.New("please choose a service option")
+	}
+
+	if unitPrice == nil {
+		return nil, errors.New("service price is not set")
+	}
+	if maxQuantity < 1 {
+		maxQuantity = 1
+	}
+	if quantity > maxQuantity {
+		return nil, fmt.Errorf("at most %d can be booked at once", maxQuantity)
+	}
+
+	unitMinutes, err := durationMinutes(unitDuration, defaultServiceDurationMinutes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service duration: %v", err)
+	}
+
+	breakdown.UnitPrice = *unitPrice
+	breakdown.UnitMinutes = unitMinutes
+	breakdown.BaseAmount = roundAmount(*unitPrice * float64(quantity))
+	breakdown.DurationMinutes = unitMinutes * quantity
+
+	if len(selection.AddOns) > 0 {
+		addOns, err := ps.serviceOptionRepo.GetAddOns(service.ID, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service add-ons: %v", err)
+		}
+		addOnsByID := make(map[uint]*models.ServiceAddOn, len(addOns))
+		for i := range addOns {
+			addOnsByID[addOns[i].ID] = &addOns[i]
+		}
+
+		chosen := make(map[uint]bool, len(selection.AddOns))
+		for _, selected := range selection.AddOns {
+			addOn, ok := addOnsByID[selected.AddOnID]
+			if !ok {
+				return nil, errors.New("selected add-on is not available")
+			}
+			if chosen[addOn.ID] {
+				return nil, fmt.Errorf("add-on %s is selected more than once", addOn.Name)
+			}
+			chosen[addOn.ID] = true
+
+			addOnQuantity := selected.Quantity
+			if addOnQuantity <= 0 {
+				addOnQuantity = 1
+			}
+			addOnMax := addOn.MaxQuantity
+			if addOnMax < 1 {
+				addOnMax = 1
+			}
+			if addOnQuantity > addOnMax {
+				return nil, fmt.Errorf("at most %d of add-on %s can be added", addOnMax, addOn.Name)
+			}
+
+			addOnMinutes, err := durationMinutes(addOn.Duration, 0)
+			if err != nil {
+				return nil, fmt.Errorf("invalid duration of add-on %s: %v", addOn.Name, err)
+			}
+
+			line := models.ServicePriceAddOnLine{
+				AddOnID:         addOn.ID,
+				Name:            addOn.Name,
+				UnitPrice:       addOn.Price,
+				Quantity:        addOnQuantity,
+				Amount:          roundAmount(addOn.Price * float64(addOnQuantity)),
+				DurationMinutes: addOnMinutes * addOnQuantity,
+			}
+			breakdown.AddOns = append(breakdown.AddOns, line)
+			breakdown.AddOnsAmount = roundAmount(breakdown.AddOnsAmount + line.Amount)
+			breakdown.DurationMinutes += line.DurationMinutes
+		}
+	}
+
+	breakdown.Total = roundAmount(breakdown.BaseAmount + breakdown.AddOnsAmount)
+	return breakdown, nil
+}
+
+// durationMinutes parses an optional duration such as "1 hour 30 minutes", returning fallback if empty
+func durationMinutes(duration *string, fallback int) (int, error) {
+	if duration == nil || *duration == "" {
+		return fallback, nil
+	}
+
+	parsed, err := utils.ParseDuration(*duration)
+	if err != nil {
+		return 0, err
+	}
+	return parsed.ToMinutes(), nil
+}
+
+// roundAmount rounds an amount to paise
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -16,15 +16,17 @@ import (
 type ProjectService struct {
 	projectRepo *repositories.ProjectRepository
 	userRepo    *repositories.UserRepository
-	cloudinary  *CloudinaryService
+	geocoder    Geocoder
+	mediaStore  MediaStore
 }
 
 // NewProjectService creates a new project service
-func NewProjectService(cloudinaryService *CloudinaryService) *ProjectService {
+func NewProjectService(mediaStore MediaStore) *ProjectService {
 	return &ProjectService{
 		projectRepo: repositories.NewProjectRepository(),
 		userRepo:    repositories.NewUserRepository(),
-		cloudinary:  cloudinaryService,
+		geocoder:    NewGeocoder(),
+		mediaStore:  mediaStore,
 	}
 }
 
@@ -38,6 +40,8 @@ type CreateProjectRequest struct {
 	City                string                    `json:"city" form:"city" binding:"required"`
 	Address             string                    `json:"address" form:"address"`
 	Pincode             string                    `json:"pincode" form:"pincode"`
+	Latitude            *float64                  `json:"latitude,omitempty" form:"latitude"`
+	Longitude           *float64                  `json:"longitude,omitempty" form:"longitude"`
 	EstimatedDuration   int                       `json:"estimated_duration_days" form:"estimated_duration_days"`
 	ContactInfo         models.JSONB              `json:"contact_info" form:"contact_info"`
 	Images              models.JSONStringArray    `json:"images" form:"images"`
@@ -53,6 +57,8 @@ type UpdateProjectRequest struct {
 	City                *string                   `json:"city,omitempty" form:"city"`
 	Address             *string                   `json:"address,omitempty" form:"address"`
 	Pincode             *string                   `json:"pincode,omitempty" form:"pincode"`
+	Latitude            *float64                  `json:"latitude,omitempty" form:"latitude"`
+	Longitude           *float64                  `json:"longitude,omitempty" form:"longitude"`
 	EstimatedDuration   *int                      `json:"estimated_duration_days,omitempty" form:"estimated_duration_days"`
 	ContactInfo         *models.JSONB             `json:"contact_info,omitempty" form:"contact_info"`
 	Images              *models.JSONStringArray   `json:"images,omitempty" form:"images"`
@@ -92,6 +98,8 @@ func (ps *ProjectService) CreateProject(userID uint, req *CreateProjectRequest)
 		City:              req.City,
 		Address:           req.Address,
 		Pincode:           req.Pincode,
+		Latitude:          req.Latitude,
+		Longitude:         req.Longitude,
 		EstimatedDuration: req.EstimatedDuration,
 		ContactInfo:       req.ContactInfo,
 		Images:            req.Images,
@@ -114,6 +122,11 @@ func (ps *ProjectService) CreateProject(userID uint, req *CreateProjectRequest)
 		return nil, fmt.Errorf("image validation failed: %v", err)
 	}
 
+	// Geocode the address unless the location was pinned
+	if project.Latitude == nil {
+		ps.geocodeProject(project)
+	}
+
 	// Create project in database
 	if err := ps.projectRepo.Create(project); err != nil {
 		return nil, fmt.Errorf("failed to create project: %v", err)
@@ -248,6 +261,17 @@ func (ps *ProjectService) UpdateProject(userID uint, projectID uint, req *Update
 	if req.Pincode != nil {
 		project.Pincode = *req.Pincode
 	}
+	if req.Latitude != nil || req.Longitude != nil {
+		project.Latitude = req.Latitude
+		project.Longitude = req.Longitude
+		if err := ps.validateCoordinates(project); err != nil {
+			return nil, fmt.Errorf("project validation failed: %v", err)
+		}
+	} else if req.State != nil || req.City != nil || req.Address != nil || req.Pincode != nil {
+		project.Latitude = nil
+		project.Longitude = nil
+		ps.geocodeProject(project)
+	}
 	if req.EstimatedDuration != nil {
 		project.EstimatedDuration = *req.EstimatedDuration
 	}
@@ -356,7 +380,7 @@ func (ps *ProjectService) GetProjectStats(userID uint) (map[string]interface{},
 	return stats, nil
 }
 
-// UploadProjectImages uploads project images to Cloudinary
+// UploadProjectImages uploads project images to media storage
 func (ps *ProjectService) UploadProjectImages(imageFiles []*multipart.FileHeader) ([]string, error) {
 	logrus.Infof("ProjectService.UploadProjectImages called with %d images", len(imageFiles))
 	
@@ -369,9 +393,9 @@ func (ps *ProjectService) UploadProjectImages(imageFiles []*multipart.FileHeader
 		return nil, fmt.Errorf("maximum 7 images allowed")
 	}
 	
-	// Upload images to Cloudinary
+	// Upload images to media storage
 	var imageURLs []string
-	if ps.cloudinary != nil {
+	if ps.mediaStore != nil {
 		for _, file := range imageFiles {
 			if file != nil {
 				// Validate file size (1MB limit)
@@ -380,7 +404,7 @@ func (ps *ProjectService) UploadProjectImages(imageFiles []*multipart.FileHeader
 				}
 				
 				logrus.Infof("ProjectService.UploadProjectImages uploading image: %s", file.Filename)
-				url, err := ps.cloudinary.UploadImage(file, "projects")
+				url, err := ps.mediaStore.UploadImage(file, "projects")
 				if err != nil {
 					logrus.Errorf("ProjectService.UploadProjectImages image upload error: %v", err)
 					return nil, err
@@ -390,8 +414,8 @@ func (ps *ProjectService) UploadProjectImages(imageFiles []*multipart.FileHeader
 			}
 		}
 	} else {
-		logrus.Warn("ProjectService.UploadProjectImages cloudinary service is nil, skipping image upload")
-		return nil, fmt.Errorf("cloudinary service not available")
+		logrus.Warn("ProjectService.UploadProjectImages media store is nil, skipping image upload")
+		return nil, fmt.Errorf("media store not available")
 	}
 	
 	logrus.Infof("ProjectService.UploadProjectImages successfully uploaded %d images", len(imageURLs))
@@ -459,9 +483,36 @@ func (ps *ProjectService) validateProject(project *models.Project) error {
 		return fmt.Errorf("contact info is required")
 	}
 	
+	return ps.validateCoordinates(project)
+}
+
+// validateCoordinates validates the project's coordinates if provided
+func (ps *ProjectService) validateCoordinates(project *models.Project) error {
+	if (project.Latitude == nil) != (project.Longitude == nil) {
+		return fmt.Errorf("latitude and longitude must be provided together")
+	}
+	if project.Latitude != nil {
+		return models.ValidateCoordinates(*project.Latitude, *project.Longitude)
+	}
 	return nil
 }
 
+// geocodeProject sets the project's coordinates from its address. Failures are logged and leave
+// the project without coordinates.
+func (ps *ProjectService) geocodeProject(project *models.Project) {
+	location, err := geocodeListingAddress(ps.geocoder, project.Address, project.City, project.State, project.Pincode)
+	if err != nil {
+		logrus.Warnf("ProjectService.geocodeProject failed to geocode project %q: %v", project.Title, err)
+		return
+	}
+	if location == nil {
+		logrus.Warnf("ProjectService.geocodeProject no results for project %q", project.Title)
+		return
+	}
+	project.Latitude = &location.Lat
+	project.Longitude = &location.Lng
+}
+
 // validateImages validates project images
 func (ps *ProjectService) validateImages(images models.JSONStringArray) error {
 	if len(images) < 2 {
@@ -13,22 +13,22 @@ import (
 
 // PromotionBannerService handles promotion banner business logic
 type PromotionBannerService struct {
-	bannerRepo        *repositories.PromotionBannerRepository
-	cloudinaryService *CloudinaryService
-	validationHelper  *utils.ValidationHelper
+	bannerRepo       *repositories.PromotionBannerRepository
+	mediaStore       MediaStore
+	validationHelper *utils.ValidationHelper
 }
 
 // NewPromotionBannerService creates a new promotion banner service
 func NewPromotionBannerService() (*PromotionBannerService, error) {
-	cloudinaryService, err := NewCloudinaryService()
+	mediaStore, err := NewMediaStore()
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize cloudinary service: %w", err)
+		return nil, fmt.Errorf("failed to initialize media store: %w", err)
 	}
 
 	return &PromotionBannerService{
-		bannerRepo:        repositories.NewPromotionBannerRepository(),
-		cloudinaryService: cloudinaryService,
-		validationHelper:  utils.NewValidationHelper(),
+		bannerRepo:       repositories.NewPromotionBannerRepository(),
+		mediaStore:       mediaStore,
+		validationHelper: utils.NewValidationHelper(),
 	}, nil
 }
 
@@ -97,7 +97,7 @@ func (pbs *PromotionBannerService) CreatePromotionBanner(req *CreatePromotionBan
 	// Handle image upload if provided
 	var imageURL string
 	if imageFile != nil {
-		uploadedURL, err := pbs.cloudinaryService.UploadImage(imageFile, "promotion-banners")
+		uploadedURL, err := pbs.mediaStore.UploadImage(imageFile, "promotion-banners")
 		if err != nil {
 			return nil, fmt.Errorf("failed to upload image: %w", err)
 		}
@@ -144,7 +144,7 @@ func (pbs *PromotionBannerService) UpdatePromotionBanner(id uint, req *UpdatePro
 	// Handle image upload if provided
 	var imageURL string
 	if imageFile != nil {
-		uploadedURL, err := pbs.cloudinaryService.UploadImage(imageFile, "promotion-banners")
+		uploadedURL, err := pbs.mediaStore.UploadImage(imageFile, "promotion-banners")
 		if err != nil {
 			return nil, fmt.Errorf("failed to upload image: %w", err)
 		}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+	"treesindia/utils"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// siteVisitWindow is the minimum time between two scheduled visits of the same property
+const siteVisitWindow = time.Hour
+
+// leadTransitions lists the pipeline stages a lead can move to from each open stage. Closed
+// leads cannot be reopened; the buyer makes a new enquiry instead.
+var leadTransitions = map[models.LeadStatus][]models.LeadStatus{
+	models.LeadStatusNew:            {models.LeadStatusContacted, models.LeadStatusVisitScheduled, models.LeadStatusNegotiating, models.LeadStatusClosedWon, models.LeadStatusClosedLost},
+	models.LeadStatusContacted:      {models.LeadStatusVisitScheduled, models.LeadStatusNegotiating, models.LeadStatusClosedWon, models.LeadStatusClosedLost},
+	models.LeadStatusVisitScheduled: {models.LeadStatusContacted, models.LeadStatusNegotiating, models.LeadStatusClosedWon, models.LeadStatusClosedLost},
+	models.LeadStatusNegotiating:    {models.LeadStatusVisitScheduled, models.LeadStatusClosedWon, models.LeadStatusClosedLost},
+}
+
+type PropertyEnquiryService struct {
+	enquiryRepo                 *repositories.PropertyEnquiryRepository
+	propertyRepo                *repositories.PropertyRepository
+	enhancedNotificationService *EnhancedNotificationService
+}
+
+func NewPropertyEnquiryService(enhancedNotificationService *EnhancedNotificationService) *PropertyEnquiryService {
+	return &PropertyEnquiryService{
+		enquiryRepo:                 repositories.NewPropertyEnquiryRepository(),
+		propertyRepo:                repositories.NewPropertyRepository(),
+		enhancedNotificationService: enhancedNotificationService,
+	}
+}
+
+// CreateEnquiry creates a buyer's enquiry on a property and notifies the listing owner
+func (pes *PropertyEnquiryService) CreateEnquiry(propertyID, buyerID uint, req *models.CreatePropertyEnquiryRequest) (*models.PropertyEnquiry, error) {
+	property, err := pes.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		return nil, errors.New("property not found")
+	}
+
+	ownerID := propertyOwnerID(property)
+	if ownerID == buyerID {
+		return nil, errors.New("cannot enquire about your own property")
+	}
+	if !property.IsApproved || property.Status != models.PropertyStatusAvailable || property.IsExpired() {
+		return nil, errors.New("property is not available")
+	}
+
+	if _, err := pes.enquiryRepo.GetOpenByBuyerAndProperty(buyerID, propertyID); err == nil {
+		return nil, errors.New("you already have an open enquiry on this property")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing enquiries: %v", err)
+	}
+
+	now := time.Now()
+	for _, slot := range req.PreferredVisitSlots {
+		if !slot.End.After(slot.Start) {
+			return nil, errors.New("visit slot end must be after its start")
+		}
+		if slot.Start.Before(now) {
+			return nil, errors.New("visit slots must be in the future")
+		}
+	}
+
+	enquiry := &models.PropertyEnquiry{
+		PropertyID:          propertyID,
+		BuyerID:             buyerID,
+		OwnerID:             ownerID,
+		Message:             req.Message,
+		Budget:              req.Budget,
+		PreferredVisitSlots: models.VisitSlots(req.PreferredVisitSlots),
+		Status:              models.LeadStatusNew,
+		StatusChangedAt:     &now,
+	}
+	if err := pes.enquiryRepo.Create(enquiry); err != nil {
+		return nil, fmt.Errorf("failed to create enquiry: %v", err)
+	}
+
+	pes.notify(ownerID, "New Property Enquiry",
+		fmt.Sprintf("You have a new enquiry on %s.", property.Title),
+		map[string]string{
+			"type":       "property_lead",
+			"enquiryId":  fmt.Sprintf("%d", enquiry.ID),
+			"propertyId": fmt.Sprintf("%d", propertyID),
+		})
+
+	enquiry.Property = property
+	return enquiry, nil
+}
+
+// GetBuyerEnquiries gets the enquiries a buyer has made
+func (pes *PropertyEnquiryService) GetBuyerEnquiries(buyerID uint, params utils.PaginationParams) ([]models.PropertyEnquiry, utils.PaginationResponse, error) {
+	return pes.enquiryRepo.GetByBuyer(buyerID, params)
+}
+
+// GetOwnerLeads gets the leads on a listing owner's properties
+func (pes *PropertyEnquiryService) GetOwnerLeads(ownerID uint, status string, propertyID uint, params utils.PaginationParams) ([]models.PropertyEnquiry, utils.PaginationResponse, error) {
+	return pes.enquiryRepo.GetByOwner(ownerID, status, propertyID, params)
+}
+
+// GetEnquiry gets an enquiry visible to the buyer who made it or the owner of the listing
+func (pes *PropertyEnquiryService) GetEnquiry(enquiryID, userID uint) (*models.PropertyEnquiry, error) {
+	enquiry, err := pes.enquiryRepo.GetByID(enquiryID)
+	if err != nil {
+		return nil, errors.New("enquiry not found")
+	}
+	if enquiry.BuyerID != userID && enquiry.OwnerID != userID {
+		return nil, errors.New("enquiry not found")
+	}
+
+	// Owner notes are private to the listing owner
+	if enquiry.OwnerID != userID {
+		enquiry.OwnerNotes = ""
+	}
+	return enquiry, nil
+}
+
+// UpdateLeadStatus moves a lead through the listing owner's pipeline
+func (pes *PropertyEnquiryService) UpdateLeadStatus(enquiryID, ownerID uint, req *models.UpdateLeadStatusRequest) (*models.PropertyEnquiry, error) {
+	enquiry, err := pes.enquiryRepo.GetByID(enquiryID)
+	if err != nil || enquiry.OwnerID != ownerID {
+		return nil, errors.New("enquiry not found")
+	}
+
+	if req.Status != enquiry.Status {
+		if !canMoveLead(enquiry.Status, req.Status) {
+			return nil, fmt.Errorf("cannot move lead from %s to %s", enquiry.Status, req.Status)
+		}
+
+		now := time.Now()
+		enquiry.Status = req.Status
+		enquiry.StatusChangedAt = &now
+		if req.Status.IsClosed() {
+			enquiry.ClosedAt = &now
+		}
+	}
+	if req.Notes != "" {
+		enquiry.OwnerNotes = req.Notes
+	}
+
+	if err := pes.enquiryRepo.Update(enquiry); err != nil {
+		return nil, fmt.Errorf("failed to update enquiry: %v", err)
+	}
+
+	return enquiry, nil
+}
+
+// ScheduleVisit books a site visit on an open enquiry. Either the buyer or the listing owner can
+// book it, and the other party is notified.
+func (pes *PropertyEnquiryService) ScheduleVisit(enquiryID, userID uint, req *models.ScheduleSiteVisitRequest) (*models.PropertySiteVisit, error) {
+	enquiry, err := pes.enquiryRepo.GetByID(enquiryID)
+	if err != nil || (enquiry.BuyerID != userID && enquiry.OwnerID != userID) {
+		return nil, errors.New("enquiry not found")
+	}
+	if enquiry.Status.IsClosed() {
+		return nil, errors.New("enquiry is closed")
+	}
+	if !req.ScheduledAt.After(time.Now()) {
+		return nil, errors.New("visit must be scheduled in the future")
+	}
+
+	conflict, err := pes.enquiryRepo.HasVisitConflict(enquiry.PropertyID, req.ScheduledAt.Add(-siteVisitWindow), req.ScheduledAt.Add(siteVisitWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check visit schedule: %v", err)
+	}
+	if conflict {
+		return nil, errors.New("another visit is already scheduled at this time")
+	}
+
+	visit := &models.PropertySiteVisit{
+		EnquiryID:   enquiry.ID,
+		PropertyID:  enquiry.PropertyID,
+		BuyerID:     enquiry.BuyerID,
+		OwnerID:     enquiry.OwnerID,
+		ScheduledAt: req.ScheduledAt,
+		Status:      models.SiteVisitStatusScheduled,
+		Notes:       req.Notes,
+		ScheduledBy: &userID,
+	}
+	if err := pes.enquiryRepo.CreateVisit(visit); err != nil {
+		return nil, fmt.Errorf("failed to schedule visit: %v", err)
+	}
+
+	if enquiry.Status == models.LeadStatusNew || enquiry.Status == models.LeadStatusContacted {
+		now := time.Now()
+		enquiry.Status = models.LeadStatusVisitScheduled
+		enquiry.StatusChangedAt = &now
+		if err := pes.enquiryRepo.Update(enquiry); err != nil {
+			logrus.Errorf("PropertyEnquiryService: failed to move enquiry %d to visit scheduled: %v", enquiry.ID, err)
+		}
+	}
+
+	recipientID := enquiry.OwnerID
+	if userID == enquiry.OwnerID {
+		recipientID = enquiry.BuyerID
+	}
+	pes.notify(recipientID, "Site Visit Scheduled",
+		fmt.Sprintf("A visit to %s has been scheduled for %s.", propertyTitle(enquiry.Property), req.ScheduledAt.Format("02 Jan 2006, 3:04 PM")),
+		siteVisitNotificationData(visit))
+
+	visit.Property = enquiry.Property
+	return visit, nil
+}
+
+// UpdateVisit records the outcome of a site visit. Either party can cancel a scheduled visit;
+// only the listing owner can mark it completed or as a no-show.
+func (pes *PropertyEnquiryService) UpdateVisit(visitID, userID uint, req *models.UpdateSiteVisitRequest) (*models.PropertySiteVisit, error) {
+	visit, err := pes.enquiryRepo.GetVisitByID(visitID)
+	if err != nil || (visit.BuyerID != userID && visit.OwnerID != userID) {
+		return nil, errors.New("site visit not found")
+	}
+	if visit.Status != models.SiteVisitStatusScheduled {
+		return nil, fmt.Errorf("site visit is already %s", visit.Status)
+	}
+	if req.Status != models.SiteVisitStatusCancelled && visit.OwnerID != userID {
+		return nil, errors.New("only the listing owner can record the outcome of a visit")
+	}
+
+	visit.Status = req.Status
+	if req.Notes != "" {
+		visit.Notes = req.Notes
+	}
+	if req.Status == models.SiteVisitStatusCancelled {
+		visit.CancelledBy = &userID
+	}
+	if err := pes.enquiryRepo.UpdateVisit(visit); err != nil {
+		return nil, fmt.Errorf("failed to update site visit: %v", err)
+	}
+
+	if req.Status == models.SiteVisitStatusCancelled {
+		recipientID := visit.OwnerID
+		if userID == visit.OwnerID {
+			recipientID = visit.BuyerID
+		}
+		pes.notify(recipientID, "Site Visit Cancelled",
+			fmt.Sprintf("The visit to %s on %s has been cancelled.", propertyTitle(visit.Property), visit.ScheduledAt.Format("02 Jan 2006, 3:04 PM")),
+			siteVisitNotificationData(visit))
+	}
+
+	return visit, nil
+}
+
+// GetUpcomingVisits gets the scheduled site visits of a buyer or listing owner
+func (pes *PropertyEnquiryService) GetUpcomingVisits(userID uint) ([]models.PropertySiteVisit, error) {
+	return pes.enquiryRepo.GetUpcomingVisits(userID, time.Now())
+}
+
+// GetLeadsDashboard gets a listing owner's lead pipeline counts and per-listing conversion stats
+func (pes *PropertyEnquiryService) GetLeadsDashboard(ownerID uint) (*models.LeadsDashboard, error) {
+	byStatus, err := pes.enquiryRepo.CountByStatus(ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count leads: %v", err)
+	}
+
+	listings, err := pes.enquiryRepo.GetListingStats(ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listing stats: %v", err)
+	}
+
+	return &models.LeadsDashboard{
+		ByStatus: byStatus,
+		Listings: listings,
+	}, nil
+}
+
+// notify sends a property lead notification in the background
+func (pes *PropertyEnquiryService) notify(userID uint, title, body string, data map[string]string) {
+	if pes.enhancedNotificationService == nil {
+		return
+	}
+
+	go func() {
+		_, err := pes.enhancedNotificationService.SendNotification(&NotificationRequest{
+			UserID:   userID,
+			Type:     models.NotificationTypeSystem,
+			Title:    title,
+			Body:     body,
+			Data:     data,
+			Priority: "high",
+		})
+		if err != nil {
+			logrus.Errorf("PropertyEnquiryService: failed to notify user %d: %v", userID, err)
+		}
+	}()
+}
+
+// canMoveLead reports whether a lead can move from one pipeline stage to another
+func canMoveLead(from, to models.LeadStatus) bool {
+	for _, status := range leadTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// propertyOwnerID returns the user who receives the leads of a property: the broker for broker
+// listings, otherwise the user who listed it
+func propertyOwnerID(property *models.Property) uint {
+	if property.BrokerID != nil {
+		return *property.BrokerID
+	}
+	return property.UserID
+}
+
+// propertyTitle returns the title of a property for notifications
+func propertyTitle(property *models.Property) string {
+	if property == nil {
+		return "the property"
+	}
+	return property.Title
+}
+
+// siteVisitNotificationData returns the notification data of a site visit
+func siteVisitNotificationData(visit *models.PropertySiteVisit) map[string]string {
+	return map[string]string{
+		"type":        "property_site_visit",
+		"visitId":     fmt.Sprintf("%d", visit.ID),
+		"enquiryId":   fmt.Sprintf("%d", visit.EnquiryID),
+		"propertyId":  fmt.Sprintf("%d", visit.PropertyID),
+		"scheduledAt": visit.ScheduledAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PropertyExpiryService expires listings past their expiry date, telling owners they can renew
+// them, and decays listing boosts
+type PropertyExpiryService struct {
+	propertyRepo                *repositories.PropertyRepository
+	enhancedNotificationService *EnhancedNotificationService
+	stopChan                    chan bool
+}
+
+func NewPropertyExpiryService(enhancedNotificationService *EnhancedNotificationService) *PropertyExpiryService {
+	return &PropertyExpiryService{
+		propertyRepo:                repositories.NewPropertyRepository(),
+		enhancedNotificationService: enhancedNotificationService,
+		stopChan:                    make(chan bool),
+	}
+}
+
+// Start begins the periodic listing expiry and boost decay
+func (pes *PropertyExpiryService) Start() {
+	logrus.Info("PropertyExpiryService starting...")
+
+	// Run immediately on start
+	go pes.run()
+
+	// Then run every hour
+	ticker := time.NewTicker(1 * time.Hour)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				pes.run()
+			case <-pes.stopChan:
+				ticker.Stop()
+				logrus.Info("PropertyExpiryService stopped")
+				return
+			}
+		}
+	}()
+
+	logrus.Info("PropertyExpiryService started successfully")
+}
+
+// Stop stops the property expiry service
+func (pes *PropertyExpiryService) Stop() {
+	logrus.Info("Stopping PropertyExpiryService...")
+	pes.stopChan <- true
+}
+
+// run expires listings and refreshes boosted priorities
+func (pes *PropertyExpiryService) run() {
+	expired, err := pes.propertyRepo.UpdateExpiredProperties()
+	if err != nil {
+		logrus.Errorf("PropertyExpiryService: failed to expire listings: %v", err)
+	} else {
+		for i := range expired {
+			pes.notifyExpired(&expired[i])
+		}
+	}
+
+	refreshed, err := pes.propertyRepo.RefreshBoostPriorities(time.Now())
+	if err != nil {
+		logrus.Errorf("PropertyExpiryService: failed to refresh boosted priorities: %v", err)
+		return
+	}
+	if refreshed > 0 {
+		logrus.Infof("PropertyExpiryService: refreshed priority of %d boosted listings", refreshed)
+	}
+}
+
+// notifyExpired tells the owner that their listing expired and can be renewed
+func (pes *PropertyExpiryService) notifyExpired(property *models.Property) {
+	if pes.enhancedNotificationService == nil {
+		return
+	}
+
+	go func() {
+		_, err := pes.enhancedNotificationService.SendNotification(&NotificationRequest{
+			UserID: property.UserID,
+			Type:   models.NotificationTypeSystem,
+			Title:  "Listing Expired",
+			Body:   fmt.Sprintf("Your listing %s has expired and is no longer shown to buyers. Renew it to list it again.", property.Title),
+			Data: map[string]string{
+				"type":       "property_expired",
+				"propertyId": fmt.Sprintf("%d", property.ID),
+			},
+			Priority: "normal",
+		})
+		if err != nil {
+			logrus.Errorf("PropertyExpiryService: failed to notify user %d: %v", property.UserID, err)
+		}
+	}()
+}
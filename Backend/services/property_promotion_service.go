@@ -0,0 +1,407 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"treesindia/database"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// featuredSlotHold is how long a featured slot bought through Razorpay is held for its payment
+const featuredSlotHold = 30 * time.Minute
+
+// PropertyPromotionService handles listing renewal and the boosts and featured slots owners buy
+// for their listings
+type PropertyPromotionService struct {
+	db                 *gorm.DB
+	promotionRepo      *repositories.PropertyPromotionRepository
+	propertyRepo       *repositories.PropertyRepository
+	subscriptionRepo   *repositories.UserSubscriptionRepository
+	adminConfigService *AdminConfigService
+	paymentService     *PaymentService
+	walletService      *UnifiedWalletService
+}
+
+func NewPropertyPromotionService() *PropertyPromotionService {
+	return &PropertyPromotionService{
+		db:                 database.GetDB(),
+		promotionRepo:      repositories.NewPropertyPromotionRepository(),
+		propertyRepo:       repositories.NewPropertyRepository(),
+		subscriptionRepo:   repositories.NewUserSubscriptionRepository(),
+		adminConfigService: NewAdminConfigService(),
+		paymentService:     NewPaymentService(),
+		walletService:      NewUnifiedWalletService(),
+	}
+}
+
+// RenewListing extends an expired listing, or one about to expire, by the listing duration
+func (pps *PropertyPromotionService) RenewListing(userID, propertyID uint) (*models.Property, error) {
+	property, err := pps.getOwnProperty(userID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	renewalWindow := time.Duration(pps.adminConfigService.GetPropertyRenewalWindowDays()) * 24 * time.Hour
+	if !property.CanRenew(now, renewalWindow) {
+		if property.Status != models.PropertyStatusAvailable && property.Status != models.PropertyStatusExpired {
+			return nil, fmt.Errorf("cannot renew a %s listing", property.Status)
+		}
+		return nil, fmt.Errorf("listing can only be renewed within %d days of expiry", pps.adminConfigService.GetPropertyRenewalWindowDays())
+	}
+
+	// Renewing early keeps the days left on the listing
+	from := now
+	if property.ExpiresAt != nil && property.ExpiresAt.After(now) {
+		from = *property.ExpiresAt
+	}
+	expiresAt := from.AddDate(0, 0, pps.adminConfigService.GetPropertyExpiryDays())
+
+	err = pps.propertyRepo.UpdateListingPromotion(nil, property.ID, map[string]interface{}{
+		"status":     models.PropertyStatusAvailable,
+		"expires_at": expiresAt,
+		"renewed_at": now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew listing: %v", err)
+	}
+
+	property.Status = models.PropertyStatusAvailable
+	property.ExpiresAt = &expiresAt
+	property.RenewedAt = &now
+	return property, nil
+}
+
+// GetPromotionOptions returns the promotions the owner can buy for a listing
+func (pps *PropertyPromotionService) GetPromotionOptions(userID, propertyID uint) (*models.PropertyPromotionOptions, error) {
+	property, err := pps.getOwnProperty(userID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	freeBoosts, err := pps.freeBoostsRemaining(nil, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	featured, err := pps.promotionRepo.CountFeaturedInCity(nil, property.City, property.State, property.ID, now, now.Add(-featuredSlotHold))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count featured listings: %v", err)
+	}
+	slotsAvailable := pps.adminConfigService.GetPropertyFeaturedSlotsPerCity() - int(featured)
+	if slotsAvailable < 0 {
+		slotsAvailable = 0
+	}
+
+	renewalWindow := time.Duration(pps.adminConfigService.GetPropertyRenewalWindowDays()) * 24 * time.Hour
+	return &models.PropertyPromotionOptions{
+		Boost:                  pps.product(models.PropertyPromotionTypeBoost),
+		Featured:               pps.product(models.PropertyPromotionTypeFeatured),
+		FreeBoostsRemaining:    freeBoosts,
+		FeaturedSlotsAvailable: slotsAvailable,
+		CanRenew:               property.CanRenew(now, renewalWindow),
+	}, nil
+}
+
+// PurchasePromotion buys a boost or featured slot for a listing. Wallet and subscription purchases
+// are active straight away; Razorpay purchases return the order to pay and stay pending until
+// VerifyPromotionPayment, holding their featured slot meanwhile.
+func (pps *PropertyPromotionService) PurchasePromotion(userID, propertyID uint, req *models.PurchasePropertyPromotionRequest) (*models.PropertyPromotion, map[string]interface{}, error) {
+	if _, err := pps.getOwnProperty(userID, propertyID); err != nil {
+		return nil, nil, err
+	}
+	if req.Type == models.PropertyPromotionTypeFeatured && req.PaymentMethod == models.PaymentMethodSubscription {
+		return nil, nil, errors.New("subscription credits can only be used for boosts")
+	}
+
+	product := pps.product(req.Type)
+	promotion := &models.PropertyPromotion{
+		PropertyID:    propertyID,
+		UserID:        userID,
+		Type:          req.Type,
+		Status:        models.PropertyPromotionStatusPending,
+		PaymentMethod: req.PaymentMethod,
+		Amount:        product.Price,
+		DurationDays:  product.DurationDays,
+		BoostPoints:   product.BoostPoints,
+	}
+
+	// The limits are checked, the payment taken and the promotion activated in one transaction, so
+	// a failed activation refunds the wallet and concurrent purchases cannot exceed the limits
+	var property *models.Property
+	err := pps.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		property, err = pps.propertyRepo.GetByIDForUpdate(tx, propertyID)
+		if err != nil {
+			return fmt.Errorf("failed to get property: %v", err)
+		}
+		if !property.IsApproved || property.Status != models.PropertyStatusAvailable || property.IsExpired() {
+			return errors.New("only approved, available listings can be promoted")
+		}
+
+		if req.Type == models.PropertyPromotionTypeFeatured {
+			if err := pps.promotionRepo.LockFeaturedSlots(tx, property.City, property.State); err != nil {
+				return fmt.Errorf("failed to lock featured slots: %v", err)
+			}
+			now := time.Now()
+			featured, err := pps.promotionRepo.CountFeaturedInCity(tx, property.City, property.State, property.ID, now, now.Add(-featuredSlotHold))
+			if err != nil {
+				return fmt.Errorf("failed to count featured listings: %v", err)
+			}
+			if int(featured) >= pps.adminConfigService.GetPropertyFeaturedSlotsPerCity() {
+				return fmt.Errorf("no featured slots available in %s", property.City)
+			}
+		}
+
+		if req.PaymentMethod == models.PaymentMethodSubscription {
+			if err := pps.promotionRepo.LockSubscriptionBoosts(tx, userID); err != nil {
+				return fmt.Errorf("failed to lock free boosts: %v", err)
+			}
+			freeBoosts, err := pps.freeBoostsRemaining(tx, userID)
+			if err != nil {
+				return err
+			}
+			if freeBoosts <= 0 {
+				return errors.New("no free boosts left this month")
+			}
+			promotion.Amount = 0
+		}
+
+		if err := pps.promotionRepo.Create(tx, promotion); err != nil {
+			return fmt.Errorf("failed to create promotion: %v", err)
+		}
+
+		switch req.PaymentMethod {
+		case models.PaymentMethodRazorpay:
+			return nil
+		case models.PaymentMethodWallet:
+			payment, err := pps.walletService.DeductFromWalletForPropertyPromotionWithTx(tx, userID, promotion.Amount, promotion.ID, pps.paymentDescription(promotion, property))
+			if err != nil {
+				return err
+			}
+			promotion.PaymentID = &payment.ID
+		}
+		return pps.activate(tx, promotion, property)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if req.PaymentMethod != models.PaymentMethodRazorpay {
+		return promotion, nil, nil
+	}
+
+	payment, order, err := pps.paymentService.CreateRazorpayOrder(&models.CreatePaymentRequest{
+		UserID:            userID,
+		Amount:            promotion.Amount,
+		Currency:          "INR",
+		Type:              models.PaymentTypePropertyPromotion,
+		Method:            models.PaymentMethodRazorpay,
+		RelatedEntityType: "property_promotion",
+		RelatedEntityID:   promotion.ID,
+		Description:       pps.paymentDescription(promotion, property),
+		Metadata: &models.JSONMap{
+			"property_id":    property.ID,
+			"promotion_type": string(promotion.Type),
+		},
+	})
+	if err != nil {
+		pps.cancel(promotion)
+		return nil, nil, fmt.Errorf("failed to create payment order: %v", err)
+	}
+	promotion.PaymentID = &payment.ID
+	if err := pps.promotionRepo.Update(nil, promotion); err != nil {
+		return nil, nil, fmt.Errorf("failed to update promotion: %v", err)
+	}
+	return promotion, order, nil
+}
+
+// VerifyPromotionPayment verifies the Razorpay payment of a pending promotion and activates it. A
+// promotion that is already active is returned as it is.
+func (pps *PropertyPromotionService) VerifyPromotionPayment(userID, promotionID uint, req *models.VerifyPropertyPromotionPaymentRequest) (*models.PropertyPromotion, error) {
+	promotion, err := pps.promotionRepo.GetByID(promotionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("promotion not found")
+		}
+		return nil, fmt.Errorf("failed to get promotion: %v", err)
+	}
+	if promotion.UserID != userID {
+		return nil, errors.New("promotion not found")
+	}
+	if promotion.Status == models.PropertyPromotionStatusActive {
+		return promotion, nil
+	}
+	if promotion.Status != models.PropertyPromotionStatusPending || promotion.PaymentID == nil {
+		return nil, errors.New("promotion is not awaiting payment")
+	}
+
+	if _, err := pps.paymentService.VerifyAndCompletePayment(*promotion.PaymentID, req.RazorpayPaymentID, req.RazorpaySignature); err != nil {
+		return nil, fmt.Errorf("payment verification failed: %v", err)
+	}
+
+	// The slot was held when the order was created; a paid promotion is always honoured. The
+	// promotion is re-read under lock so concurrent verifications activate it only once.
+	err = pps.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		promotion, err = pps.promotionRepo.GetByIDForUpdate(tx, promotionID)
+		if err != nil {
+			return fmt.Errorf("failed to get promotion: %v", err)
+		}
+		if promotion.Status == models.PropertyPromotionStatusActive {
+			return nil
+		}
+		if promotion.Status != models.PropertyPromotionStatusPending {
+			return errors.New("promotion is not awaiting payment")
+		}
+
+		property, err := pps.propertyRepo.GetByIDForUpdate(tx, promotion.PropertyID)
+		if err != nil {
+			return fmt.Errorf("failed to get property: %v", err)
+		}
+		return pps.activate(tx, promotion, property)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// GetPropertyPromotions returns the promotions bought for a listing
+func (pps *PropertyPromotionService) GetPropertyPromotions(userID, propertyID uint) ([]models.PropertyPromotion, error) {
+	if _, err := pps.getOwnProperty(userID, propertyID); err != nil {
+		return nil, err
+	}
+	promotions, err := pps.promotionRepo.GetByProperty(propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promotions: %v", err)
+	}
+	return promotions, nil
+}
+
+// activate applies a paid promotion to its listing within tx. property must be locked in tx. A
+// boost bought while another is running adds to what is left of it; featured days are added after
+// the current slot ends.
+func (pps *PropertyPromotionService) activate(tx *gorm.DB, promotion *models.PropertyPromotion, property *models.Property) error {
+	now := time.Now()
+	startsAt := now
+	var updates map[string]interface{}
+
+	switch promotion.Type {
+	case models.PropertyPromotionTypeBoost:
+		points := property.RemainingBoostPoints(now) + promotion.BoostPoints
+		endsAt := now.AddDate(0, 0, promotion.DurationDays)
+		promotion.EndsAt = &endsAt
+		updates = map[string]interface{}{
+			"boost_points":     points,
+			"boost_started_at": now,
+			"boosted_until":    endsAt,
+			"priority_score":   property.BasePriorityScore + points,
+		}
+
+	case models.PropertyPromotionTypeFeatured:
+		if property.IsFeatured(now) {
+			startsAt = *property.FeaturedUntil
+		}
+		endsAt := startsAt.AddDate(0, 0, promotion.DurationDays)
+		promotion.EndsAt = &endsAt
+		updates = map[string]interface{}{
+			"featured_until": endsAt,
+		}
+	}
+
+	if err := pps.propertyRepo.UpdateListingPromotion(tx, property.ID, updates); err != nil {
+		return fmt.Errorf("failed to apply promotion: %v", err)
+	}
+
+	promotion.Status = models.PropertyPromotionStatusActive
+	promotion.StartsAt = &startsAt
+	if err := pps.promotionRepo.Update(tx, promotion); err != nil {
+		return fmt.Errorf("failed to update promotion: %v", err)
+	}
+
+	logrus.Infof("PropertyPromotionService: activated %s promotion %d for property %d until %s", promotion.Type, promotion.ID, property.ID, promotion.EndsAt.Format(time.RFC3339))
+	return nil
+}
+
+// cancel marks a promotion whose payment failed as cancelled
+func (pps *PropertyPromotionService) cancel(promotion *models.PropertyPromotion) {
+	promotion.Status = models.PropertyPromotionStatusCancelled
+	if err := pps.promotionRepo.Update(nil, promotion); err != nil {
+		logrus.Errorf("PropertyPromotionService: failed to cancel promotion %d: %v", promotion.ID, err)
+	}
+}
+
+// freeBoostsRemaining returns the free boosts the user's subscription plan has left this calendar
+// month, counting within tx when it is set
+func (pps *PropertyPromotionService) freeBoostsRemaining(tx *gorm.DB, userID uint) (int, error) {
+	subscription, err := pps.subscriptionRepo.GetActiveByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get subscription: %v", err)
+	}
+
+	allowance := subscription.Plan.FreeBoostsPerMonth()
+	if allowance <= 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	used, err := pps.promotionRepo.CountSubscriptionBoostsSince(tx, userID, monthStart)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count free boosts: %v", err)
+	}
+
+	if remaining := allowance - int(used); remaining > 0 {
+		return remaining, nil
+	}
+	return 0, nil
+}
+
+// product returns the current price and effect of a promotion type
+func (pps *PropertyPromotionService) product(promotionType models.PropertyPromotionType) models.PropertyPromotionProduct {
+	if promotionType == models.PropertyPromotionTypeFeatured {
+		return models.PropertyPromotionProduct{
+			Type:         promotionType,
+			Price:        pps.adminConfigService.GetPropertyFeaturedPrice(),
+			DurationDays: pps.adminConfigService.GetPropertyFeaturedDays(),
+		}
+	}
+	return models.PropertyPromotionProduct{
+		Type:         models.PropertyPromotionTypeBoost,
+		Price:        pps.adminConfigService.GetPropertyBoostPrice(),
+		DurationDays: pps.adminConfigService.GetPropertyBoostDays(),
+		BoostPoints:  pps.adminConfigService.GetPropertyBoostPoints(),
+	}
+}
+
+// getOwnProperty gets a property listed by the user
+func (pps *PropertyPromotionService) getOwnProperty(userID, propertyID uint) (*models.Property, error) {
+	property, err := pps.propertyRepo.GetByID(propertyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("property not found")
+		}
+		return nil, fmt.Errorf("failed to get property: %v", err)
+	}
+	if property.UserID != userID {
+		return nil, errors.New("property not found")
+	}
+	return property, nil
+}
+
+// paymentDescription describes a promotion on its payment
+func (pps *PropertyPromotionService) paymentDescription(promotion *models.PropertyPromotion, property *models.Property) string {
+	if promotion.Type == models.PropertyPromotionTypeFeatured {
+		return fmt.Sprintf("Featured listing (%d days): %s", promotion.DurationDays, property.Title)
+	}
+	return fmt.Sprintf("Listing boost (%d days): %s", promotion.DurationDays, property.Title)
+}
@@ -14,16 +14,24 @@ import (
 )
 
 type PropertyService struct {
-	propertyRepo     *repositories.PropertyRepository
-	userRepo         *repositories.UserRepository
-	cloudinary       *CloudinaryService
+	propertyRepo         *repositories.PropertyRepository
+	userRepo             *repositories.UserRepository
+	imageFingerprintRepo *repositories.ImageFingerprintRepository
+	adminConfigService   *AdminConfigService
+	savedSearchAlerts    *SavedSearchAlertService
+	geocoder             Geocoder
+	mediaStore           MediaStore
 }
 
-func NewPropertyService(cloudinaryService *CloudinaryService) *PropertyService {
+func NewPropertyService(mediaStore MediaStore, enhancedNotificationService *EnhancedNotificationService) *PropertyService {
 	return &PropertyService{
-		propertyRepo: repositories.NewPropertyRepository(),
-		userRepo:     repositories.NewUserRepository(),
-		cloudinary:   cloudinaryService,
+		propertyRepo:         repositories.NewPropertyRepository(),
+		userRepo:             repositories.NewUserRepository(),
+		imageFingerprintRepo: repositories.NewImageFingerprintRepository(),
+		adminConfigService:   NewAdminConfigService(),
+		savedSearchAlerts:    NewSavedSearchAlertService(enhancedNotificationService),
+		geocoder:             NewGeocoder(),
+		mediaStore:           mediaStore,
 	}
 }
 
@@ -74,6 +82,10 @@ func (ps *PropertyService) CreateProperty(property *models.Property, userID uint
 	// Generate slug
 	property.Slug = ps.generateSlug(property.Title)
 	
+	// Set expiry from admin config
+	expiryDate := time.Now().AddDate(0, 0, ps.adminConfigService.GetPropertyExpiryDays())
+	property.ExpiresAt = &expiryDate
+	
 	// Validate property data
 	if err := ps.validateProperty(property); err != nil {
 		logrus.Errorf("PropertyService.CreateProperty validation error: %v", err)
@@ -86,6 +98,11 @@ func (ps *PropertyService) CreateProperty(property *models.Property, userID uint
 		return err
 	}
 	
+	// Geocode the address unless the lister pinned the location
+	if property.Latitude == nil {
+		ps.geocodeProperty(property)
+	}
+	
 	// Create property
 	err = ps.propertyRepo.Create(property)
 	if err != nil {
@@ -97,20 +114,25 @@ func (ps *PropertyService) CreateProperty(property *models.Property, userID uint
 	
 	// Send notification to admins about new property
 	
+	// Alert saved searches about listings auto-approved in BeforeCreate
+	if property.IsApproved {
+		go ps.savedSearchAlerts.NotifyNewListing(property)
+	}
+	
 	return nil
 }
 
-// UploadPropertyImages uploads property images to Cloudinary
+// UploadPropertyImages uploads property images to media storage
 func (ps *PropertyService) UploadPropertyImages(imageFiles []*multipart.FileHeader) ([]string, error) {
 	logrus.Infof("PropertyService.UploadPropertyImages called with %d images", len(imageFiles))
 	
-	// Upload images to Cloudinary
+	// Upload images to media storage
 	var imageURLs []string
-	if ps.cloudinary != nil {
+	if ps.mediaStore != nil {
 		for _, file := range imageFiles {
 			if file != nil {
 				logrus.Infof("PropertyService.UploadPropertyImages uploading image: %s", file.Filename)
-				url, err := ps.cloudinary.UploadImage(file, "properties")
+				url, err := ps.mediaStore.UploadImage(file, "properties")
 				if err != nil {
 					logrus.Errorf("PropertyService.UploadPropertyImages image upload error: %v", err)
 					return nil, err
@@ -120,8 +142,8 @@ func (ps *PropertyService) UploadPropertyImages(imageFiles []*multipart.FileHead
 			}
 		}
 	} else {
-		logrus.Warn("PropertyService.UploadPropertyImages cloudinary service is nil, skipping image upload")
-		return nil, fmt.Errorf("cloudinary service not available")
+		logrus.Warn("PropertyService.UploadPropertyImages media store is nil, skipping image upload")
+		return nil, fmt.Errorf("media store not available")
 	}
 	
 	logrus.Infof("PropertyService.UploadPropertyImages successfully uploaded %d images", len(imageURLs))
@@ -140,11 +162,7 @@ func (ps *PropertyService) GetPropertyByID(id uint) (*models.Property, error) {
 	
 	// Check if property is expired and update status if needed
 	if property.ShouldExpire() {
-		if property.ListingType == models.ListingTypeSale {
-			property.Status = models.PropertyStatusSold
-		} else {
-			property.Status = models.PropertyStatusRented
-		}
+		property.Status = models.PropertyStatusExpired
 		ps.propertyRepo.Update(property)
 	}
 	
@@ -163,11 +181,7 @@ func (ps *PropertyService) GetPropertyBySlug(slug string) (*models.Property, err
 	
 	// Check if property is expired and update status if needed
 	if property.ShouldExpire() {
-		if property.ListingType == models.ListingTypeSale {
-			property.Status = models.PropertyStatusSold
-		} else {
-			property.Status = models.PropertyStatusRented
-		}
+		property.Status = models.PropertyStatusExpired
 		ps.propertyRepo.Update(property)
 	}
 	
@@ -222,6 +236,25 @@ func (ps *PropertyService) GetAllPropertiesForAdmin(params utils.PaginationParam
 	return properties, pagination, nil
 }
 
+// GetDuplicateImages finds photos of a property that are near-identical to photos of other properties.
+// A propertyID of 0 lists duplicates across all properties.
+func (ps *PropertyService) GetDuplicateImages(propertyID uint, page, limit int) ([]models.DuplicatePropertyImage, *repositories.Pagination, error) {
+	if propertyID != 0 {
+		if _, err := ps.propertyRepo.GetByID(propertyID); err != nil {
+			return nil, nil, fmt.Errorf("property not found")
+		}
+	}
+
+	maxDistance := ps.adminConfigService.GetDuplicateImageMaxDistance()
+	duplicates, pagination, err := ps.imageFingerprintRepo.GetDuplicatePropertyImages(propertyID, maxDistance, page, limit)
+	if err != nil {
+		logrus.Errorf("PropertyService.GetDuplicateImages error: %v", err)
+		return nil, nil, fmt.Errorf("failed to find duplicate images: %v", err)
+	}
+
+	return duplicates, pagination, nil
+}
+
 // GetPropertyStats retrieves property statistics for admin dashboard
 func (ps *PropertyService) GetPropertyStats() (map[string]interface{}, error) {
 	logrus.Infof("PropertyService.GetPropertyStats called")
@@ -410,7 +443,14 @@ func (ps *PropertyService) UpdateProperty(id uint, updates map[string]interface{
 		property.UploadedByAdmin = uploadedByAdmin.(bool)
 	}
 	if priorityScore, exists := updates["priority_score"]; exists {
-		property.PriorityScore = int(priorityScore.(float64))
+		// Admins set the base priority; an active boost stays on top of it
+		switch score := priorityScore.(type) {
+		case float64:
+			property.BasePriorityScore = int(score)
+		case int:
+			property.BasePriorityScore = score
+		}
+		property.PriorityScore = property.BasePriorityScore + property.RemainingBoostPoints(time.Now())
 	}
 	if subscriptionRequired, exists := updates["subscription_required"]; exists {
 		property.SubscriptionRequired = subscriptionRequired.(bool)
@@ -429,6 +469,10 @@ func (ps *PropertyService) UpdateProperty(id uint, updates map[string]interface{
 		}
 	}
 	
+	if err := ps.applyLocationUpdates(property, updates); err != nil {
+		return err
+	}
+	
 	// Update property
 	err = ps.propertyRepo.Update(property)
 	if err != nil {
@@ -764,6 +808,10 @@ func (ps *PropertyService) UpdateUserProperty(id uint, updates map[string]interf
 		}
 	}
 	
+	if err := ps.applyLocationUpdates(property, updates); err != nil {
+		return err
+	}
+	
 	// Validate property data
 	if err := ps.validateProperty(property); err != nil {
 		logrus.Errorf("PropertyService.UpdateUserProperty validation error: %v", err)
@@ -810,6 +858,10 @@ func (ps *PropertyService) ApproveProperty(id uint, adminID uint) error {
 	}
 
 	logrus.Infof("PropertyService.ApproveProperty successfully approved property ID: %d", id)
+
+	// Alert saved searches the newly approved listing matches
+	go ps.savedSearchAlerts.NotifyNewListing(property)
+
 	return nil
 }
 
@@ -849,7 +901,7 @@ func (ps *PropertyService) RejectProperty(id uint, adminID uint, reason string)
 func (ps *PropertyService) UpdateExpiredProperties() error {
 	logrus.Infof("PropertyService.UpdateExpiredProperties called")
 	
-	err := ps.propertyRepo.UpdateExpiredProperties()
+	_, err := ps.propertyRepo.UpdateExpiredProperties()
 	if err != nil {
 		logrus.Errorf("PropertyService.UpdateExpiredProperties repository error: %v", err)
 		return err
@@ -896,6 +948,16 @@ func (ps *PropertyService) validateProperty(property *models.Property) error {
 		}
 	}
 	
+	// Validate coordinates if provided
+	if (property.Latitude == nil) != (property.Longitude == nil) {
+		return fmt.Errorf("latitude and longitude must be provided together")
+	}
+	if property.Latitude != nil {
+		if err := models.ValidateCoordinates(*property.Latitude, *property.Longitude); err != nil {
+			return err
+		}
+	}
+	
 	// Validate age if provided
 	if property.Age != nil {
 		validAges := []models.PropertyAge{
@@ -919,6 +981,52 @@ func (ps *PropertyService) validateProperty(property *models.Property) error {
 	return nil
 }
 
+// applyLocationUpdates sets coordinates given in the updates, or re-geocodes the property when
+// its address changed without them
+func (ps *PropertyService) applyLocationUpdates(property *models.Property, updates map[string]interface{}) error {
+	latitude, hasLatitude := updates["latitude"]
+	longitude, hasLongitude := updates["longitude"]
+	if hasLatitude || hasLongitude {
+		lat, latOK := latitude.(float64)
+		lng, lngOK := longitude.(float64)
+		if !latOK || !lngOK {
+			return fmt.Errorf("latitude and longitude must be provided together")
+		}
+		if err := models.ValidateCoordinates(lat, lng); err != nil {
+			return err
+		}
+		property.Latitude = &lat
+		property.Longitude = &lng
+		return nil
+	}
+
+	for _, key := range []string{"state", "city", "address", "pincode"} {
+		if _, exists := updates[key]; exists {
+			property.Latitude = nil
+			property.Longitude = nil
+			ps.geocodeProperty(property)
+			break
+		}
+	}
+	return nil
+}
+
+// geocodeProperty sets the property's coordinates from its address. Failures are logged and leave
+// the property without coordinates, so it is only missing from radius searches.
+func (ps *PropertyService) geocodeProperty(property *models.Property) {
+	location, err := geocodeListingAddress(ps.geocoder, property.Address, property.City, property.State, property.Pincode)
+	if err != nil {
+		logrus.Warnf("PropertyService.geocodeProperty failed to geocode property %q: %v", property.Title, err)
+		return
+	}
+	if location == nil {
+		logrus.Warnf("PropertyService.geocodeProperty no results for property %q", property.Title)
+		return
+	}
+	property.Latitude = &location.Lat
+	property.Longitude = &location.Lng
+}
+
 // validateImages validates property images
 func (ps *PropertyService) validateImages(images models.JSONStringArray) error {
 	if len(images) < 2 {
@@ -1016,11 +1124,7 @@ func (ps *PropertyService) processFilters(filters map[string]interface{}) map[st
 func (ps *PropertyService) updateExpiredProperties(properties []models.Property) {
 	for i := range properties {
 		if properties[i].ShouldExpire() {
-			if properties[i].ListingType == models.ListingTypeSale {
-				properties[i].Status = models.PropertyStatusSold
-			} else {
-				properties[i].Status = models.PropertyStatusRented
-			}
+			properties[i].Status = models.PropertyStatusExpired
 			ps.propertyRepo.Update(&properties[i])
 		}
 	}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"treesindia/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+type QuoteExpiryService struct {
+	enhancedNotificationService *EnhancedNotificationService
+	quoteService                *QuoteService
+	stopChan                    chan bool
+}
+
+func NewQuoteExpiryService(enhancedNotificationService *EnhancedNotificationService) *QuoteExpiryService {
+	return &QuoteExpiryService{
+		enhancedNotificationService: enhancedNotificationService,
+		quoteService:                NewQuoteService(),
+		stopChan:                    make(chan bool),
+	}
+}
+
+// Start begins the periodic quote expiry check
+func (qes *QuoteExpiryService) Start() {
+	logrus.Info("QuoteExpiryService starting...")
+
+	// Run immediately on start
+	go qes.checkQuotes()
+
+	// Then run every hour
+	ticker := time.NewTicker(1 * time.Hour)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				qes.checkQuotes()
+			case <-qes.stopChan:
+				ticker.Stop()
+				logrus.Info("QuoteExpiryService stopped")
+				return
+			}
+		}
+	}()
+
+	logrus.Info("QuoteExpiryService started successfully")
+}
+
+// Stop stops the quote expiry service
+func (qes *QuoteExpiryService) Stop() {
+	logrus.Info("Stopping QuoteExpiryService...")
+	qes.stopChan <- true
+}
+
+// checkQuotes warns customers about quotes that are about to expire and
+// expires quotes that have passed their validity
+func (qes *QuoteExpiryService) checkQuotes() {
+	bookings, err := qes.quoteService.GetQuotesNeedingExpiryWarning()
+	if err != nil {
+		logrus.Errorf("QuoteExpiryService: failed to get expiring quotes: %v", err)
+	}
+
+	warningCount := 0
+	for i := range bookings {
+		booking := bookings[i]
+		if err := qes.quoteService.MarkExpiryWarningSent(booking.ID); err != nil {
+			logrus.Errorf("QuoteExpiryService: failed to record expiry warning for booking %d: %v", booking.ID, err)
+			continue
+		}
+		qes.sendExpiryWarning(&booking)
+		warningCount++
+	}
+
+	expired, err := qes.quoteService.ExpireQuotes()
+	if err != nil {
+		logrus.Errorf("QuoteExpiryService: failed to expire quotes: %v", err)
+		return
+	}
+	for i := range expired {
+		qes.sendQuoteExpired(&expired[i])
+	}
+
+	logrus.Infof("QuoteExpiryService: %d expiry warnings sent, %d quotes expired", warningCount, len(expired))
+}
+
+// sendExpiryWarning notifies the customer that their quote expires soon
+func (qes *QuoteExpiryService) sendExpiryWarning(booking *models.Booking) {
+	// This runs in a goroutine, so it doesn't block the checking process
+	go func() {
+		// Skip if notification service is not available
+		if qes.enhancedNotificationService == nil {
+			logrus.Warn("Notification service not available, skipping quote expiry warning")
+			return
+		}
+
+		amount := 0.0
+		if booking.QuoteAmount != nil {
+			amount = *booking.QuoteAmount
+		}
+
+		notificationReq := &NotificationRequest{
+			UserID: booking.UserID,
+			Type:   models.NotificationTypeBooking,
+			Title:  "Quote Expiring Soon",
+			Body: fmt.Sprintf("Your quote of ₹%.2f for booking %s expires on %s. Accept it before then to keep this price.",
+				amount, booking.BookingReference, booking.QuoteExpiresAt.Format("02 Jan 2006, 03:04 PM")),
+			Data: map[string]string{
+				"type":      "quote_expiring",
+				"bookingId": fmt.Sprintf("%d", booking.ID),
+				"amount":    fmt.Sprintf("%.2f", amount),
+				"expiresAt": booking.QuoteExpiresAt.Format(time.RFC3339),
+			},
+			Priority: "high",
+		}
+
+		_, err := qes.enhancedNotificationService.SendNotification(notificationReq)
+		if err != nil {
+			logrus.Errorf("Failed to send quote expiry warning for booking %d: %v", booking.ID, err)
+		} else {
+			logrus.Infof("Sent quote expiry warning for booking %d to user %d", booking.ID, booking.UserID)
+		}
+	}()
+}
+
+// sendQuoteExpired notifies the customer that their quote expired and how the inquiry fee was handled
+func (qes *QuoteExpiryService) sendQuoteExpired(expired *ExpiredQuote) {
+	go func() {
+		if qes.enhancedNotificationService == nil {
+			logrus.Warn("Notification service not available, skipping quote expired notification")
+			return
+		}
+
+		booking := expired.Booking
+		body := fmt.Sprintf("The quote for booking %s has expired.", booking.BookingReference)
+		if expired.FeeRelease.Amount > 0 {
+			switch expired.FeeRelease.Policy {
+			case InquiryFeePolicyRefund:
+				body += fmt.Sprintf(" Your inquiry fee of ₹%.2f has been refunded.", expired.FeeRelease.Amount)
+			case InquiryFeePolicyWalletCredit:
+				body += fmt.Sprintf(" Your inquiry fee of ₹%.2f has been credited to your wallet.", expired.FeeRelease.Amount)
+			}
+		}
+		if booking.Status == models.BookingStatusPending {
+			body += " We will send you a new quote shortly."
+		}
+
+		notificationReq := &NotificationRequest{
+			UserID: booking.UserID,
+			Type:   models.NotificationTypeBooking,
+			Title:  "Quote Expired",
+			Body:   body,
+			Data: map[string]string{
+				"type":      "quote_expired",
+				"bookingId": fmt.Sprintf("%d", booking.ID),
+				"feePolicy": expired.FeeRelease.Policy,
+				"feeAmount": fmt.Sprintf("%.2f", expired.FeeRelease.Amount),
+			},
+			Priority: "high",
+		}
+
+		_, err := qes.enhancedNotificationService.SendNotification(notificationReq)
+		if err != nil {
+			logrus.Errorf("Failed to send quote expired notification for booking %d: %v", booking.ID, err)
+		}
+	}()
+}
@@ -3,24 +3,78 @@ package services
 import (
 	"errors"
 	"fmt"
+	"math"
 	"time"
+	"treesindia/database"
 	"treesindia/models"
 	"treesindia/repositories"
 
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type QuoteService struct {
-	bookingRepo           *repositories.BookingRepository
-	userRepo              *repositories.UserRepository
-	paymentSegmentRepo    *repositories.PaymentSegmentRepository
+	bookingRepo           BookingStore
+	userRepo              UserStore
+	paymentSegmentRepo    PaymentSegmentStore
+	quoteRevisionRepo     QuoteRevisionStore
+	paymentService        *PaymentService
+	walletService         *UnifiedWalletService
+	milestoneService      *PaymentSegmentMilestoneService
+	inquiryFeeService     *InquiryFeeService
+	adminConfigService    *AdminConfigService
+	couponService         *CouponService
+	searchAnalyticsService *SearchAnalyticsService
+	db                    *gorm.DB
 }
 
 func NewQuoteService() *QuoteService {
+	return NewQuoteServiceWith(
+		database.GetDB(),
+		repositories.NewBookingRepository(),
+		repositories.NewUserRepository(),
+		repositories.NewPaymentSegmentRepository(),
+		repositories.NewQuoteRevisionRepository(),
+		NewPaymentService(),
+		NewUnifiedWalletService(),
+		NewPaymentSegmentMilestoneService(),
+		NewInquiryFeeService(),
+		NewAdminConfigService(),
+		NewCouponService(),
+		NewSearchAnalyticsService(),
+	)
+}
+
+// NewQuoteServiceWith creates a quote service on db and the given stores. Quote and segment payments
+// go through paymentService or walletService, and held inquiry fees are settled by inquiryFeeService.
+func NewQuoteServiceWith(
+	db *gorm.DB,
+	bookingRepo BookingStore,
+	userRepo UserStore,
+	paymentSegmentRepo PaymentSegmentStore,
+	quoteRevisionRepo QuoteRevisionStore,
+	paymentService *PaymentService,
+	walletService *UnifiedWalletService,
+	milestoneService *PaymentSegmentMilestoneService,
+	inquiryFeeService *InquiryFeeService,
+	adminConfigService *AdminConfigService,
+	couponService *CouponService,
+	searchAnalyticsService *SearchAnalyticsService,
+) *QuoteService {
 	return &QuoteService{
-		bookingRepo:        repositories.NewBookingRepository(),
-		userRepo:           repositories.NewUserRepository(),
-		paymentSegmentRepo: repositories.NewPaymentSegmentRepository(),
+		bookingRepo:        bookingRepo,
+		userRepo:           userRepo,
+		paymentSegmentRepo: paymentSegmentRepo,
+		quoteRevisionRepo:  quoteRevisionRepo,
+		paymentService:     paymentService,
+		walletService:      walletService,
+		milestoneService:   milestoneService,
+		inquiryFeeService:  inquiryFeeService,
+		searchAnalyticsService: searchAnalyticsService,
+		db:                 db,
+		adminConfigService: adminConfigService,
+		couponService:      couponService,
 	}
 }
 
@@ -57,12 +111,20 @@ func (qs *QuoteService) ProvideQuote(bookingID uint, adminID uint, req *models.P
 		return nil, errors.New("total quote amount must be greater than 0")
 	}
 
-	// 4. Set quote details
+	// 4. Record the quote as a new revision
+	revision, err := qs.createQuoteRevision(booking, adminID, req.Notes, segmentsTotal, req.Duration, req.LineItems)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Set quote details
 	now := time.Now()
 	booking.QuoteAmount = &segmentsTotal
 	booking.QuoteNotes = req.Notes
 	booking.QuoteProvidedBy = &adminID
 	booking.QuoteProvidedAt = &now
+	booking.QuoteExpiresAt = qs.quoteExpiry(now)
+	booking.QuoteExpiryWarningSentAt = nil
 	booking.Status = models.BookingStatusQuoteProvided
 	
 	// Set quote duration if provided (for single segment quotes)
@@ -70,13 +132,16 @@ func (qs *QuoteService) ProvideQuote(bookingID uint, adminID uint, req *models.P
 		booking.QuoteDuration = req.Duration
 	}
 
-	// 5. Create payment segments
-	err = qs.createPaymentSegments(bookingID, req.Segments)
+	// 6. Create payment segments
+	err = qs.createPaymentSegments(bookingID, revision.ID, req.Segments)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create payment segments: %v", err)
 	}
+	if segments, err := qs.getPaymentSegments(bookingID); err == nil {
+		booking.PaymentSegments = segments
+	}
 
-	// 6. Update booking
+	// 7. Update booking
 	err = qs.bookingRepo.Update(booking)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update booking: %v", err)
@@ -133,20 +198,34 @@ func (qs *QuoteService) UpdateQuote(bookingID uint, adminID uint, req *models.Up
 		return nil, errors.New("total quote amount must be greater than 0")
 	}
 
-	// 4. Update quote details
+	// 4. Record the revised quote; earlier versions stay in the history
+	revision, err := qs.createQuoteRevision(booking, adminID, req.Notes, segmentsTotal, req.Duration, req.LineItems)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Update quote details
 	now := time.Now()
 	booking.QuoteAmount = &segmentsTotal
 	booking.QuoteNotes = req.Notes
 	booking.QuoteProvidedBy = &adminID
 	booking.QuoteProvidedAt = &now
+	booking.QuoteExpiresAt = qs.quoteExpiry(now)
+	booking.QuoteExpiryWarningSentAt = nil
+	if req.Duration != nil && *req.Duration != "" {
+		booking.QuoteDuration = req.Duration
+	}
 
-	// 5. Create payment segments
-	err = qs.createPaymentSegments(bookingID, req.Segments)
+	// 6. Replace payment segments with the ones from the new revision
+	err = qs.createPaymentSegments(bookingID, revision.ID, req.Segments)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create payment segments: %v", err)
 	}
+	if segments, err := qs.getPaymentSegments(bookingID); err == nil {
+		booking.PaymentSegments = segments
+	}
 
-	// 5. Update booking
+	// 7. Update booking
 	err = qs.bookingRepo.Update(booking)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update booking: %v", err)
@@ -186,17 +265,39 @@ func (qs *QuoteService) AcceptQuote(bookingID uint, userID uint, req *models.Acc
 		return nil, errors.New("quote has expired")
 	}
 
-	// 5. Update booking status
+	// 5. Find the quote version being accepted
+	revision, err := qs.getAcceptableRevision(bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 6. Update booking status
 	now := time.Now()
 	booking.Status = models.BookingStatusQuoteAccepted
 	booking.QuoteAcceptedAt = &now
 
-	// 6. Update booking
+	// 7. Lock the accepted version
+	if revision != nil {
+		if err := qs.quoteRevisionRepo.Lock(revision.ID, now); err != nil {
+			return nil, fmt.Errorf("failed to lock quote revision: %v", err)
+		}
+		booking.AcceptedQuoteRevisionID = &revision.ID
+	}
+
+	// 8. Update booking
 	err = qs.bookingRepo.Update(booking)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update booking: %v", err)
 	}
 
+	// 9. Segments due on booking fall due now
+	qs.milestoneService.ActivateTrigger(bookingID, models.PaymentSegmentDueOnBooking)
+
+	// 10. The inquiry fee is no longer refundable
+	if err := qs.inquiryFeeService.CaptureHeldFees(bookingID); err != nil {
+		logrus.Errorf("Failed to capture inquiry fee for booking %d: %v", bookingID, err)
+	}
+
 	// Calculate payment progress before returning
 	booking.GetPaymentProgress()
 	
@@ -242,7 +343,15 @@ func (qs *QuoteService) RejectQuote(bookingID uint, userID uint, req *models.Rej
 		return nil, errors.New("booking does not have a quote provided")
 	}
 
-	// 4. Update booking status back to pending (allows for new quote)
+	// 4. Close the quote version and drop its unpaid segments
+	if err := qs.quoteRevisionRepo.UpdateStatusByBooking(bookingID, openQuoteRevisionStatuses, models.QuoteRevisionStatusRejected); err != nil {
+		return nil, fmt.Errorf("failed to update quote revisions: %v", err)
+	}
+	if err := qs.paymentSegmentRepo.DeleteByBookingID(bookingID); err != nil {
+		return nil, fmt.Errorf("failed to remove payment segments: %v", err)
+	}
+
+	// 5. Update booking status back to pending (allows for new quote)
 	booking.Status = models.BookingStatusPending
 	// Clear quote details
 	booking.QuoteAmount = nil
@@ -251,7 +360,7 @@ func (qs *QuoteService) RejectQuote(bookingID uint, userID uint, req *models.Rej
 	booking.QuoteProvidedAt = nil
 	booking.QuoteExpiresAt = nil
 
-	// 5. Update booking
+	// 6. Update booking
 	err = qs.bookingRepo.Update(booking)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update booking: %v", err)
@@ -382,6 +491,20 @@ func (qs *QuoteService) GetQuoteInfo(bookingID uint) (*models.QuoteInfo, error)
 		DaysUntilExpiry: daysUntilExpiry,
 	}
 
+	// 5. Attach the current (or accepted) quote version
+	revision, err := qs.getCurrentQuoteRevision(booking)
+	if err != nil {
+		logrus.Warnf("Failed to get quote revision for booking %d: %v", bookingID, err)
+	}
+	if revision != nil {
+		quoteInfo.Version = revision.Version
+		quoteInfo.RevisionID = &revision.ID
+		quoteInfo.LineItems = revision.LineItems
+	} else if booking.Status == models.BookingStatusQuoteProvided {
+		count, _ := qs.quoteRevisionRepo.CountByBookingID(bookingID)
+		quoteInfo.AwaitingRevision = count > 0
+	}
+
 	return quoteInfo, nil
 }
 
@@ -395,35 +518,176 @@ func (qs *QuoteService) GetExpiredQuotes() ([]models.Booking, error) {
 	return qs.bookingRepo.GetExpiredQuotes()
 }
 
+// ExpiredQuote describes a quote that was expired and how its inquiry fee was settled
+type ExpiredQuote struct {
+	Booking    models.Booking
+	FeeRelease *InquiryFeeRelease
+}
+
 // CleanupExpiredQuotes automatically expires quotes that have passed their expiration date
 func (qs *QuoteService) CleanupExpiredQuotes() error {
+	_, err := qs.ExpireQuotes()
+	return err
+}
+
+// ExpireQuotes expires quotes past their validity and settles held inquiry fees.
+// If the fee is refunded or credited the booking is cancelled; otherwise it goes
+// back to pending so a new quote can be provided.
+func (qs *QuoteService) ExpireQuotes() ([]ExpiredQuote, error) {
 	expiredBookings, err := qs.GetExpiredQuotes()
 	if err != nil {
-		return fmt.Errorf("failed to get expired quotes: %v", err)
+		return nil, fmt.Errorf("failed to get expired quotes: %v", err)
 	}
 
+	var expired []ExpiredQuote
 	for _, booking := range expiredBookings {
-		booking.Status = models.BookingStatusPending
-		// Clear quote details
-		booking.QuoteAmount = nil
-		booking.QuoteNotes = ""
-		booking.QuoteProvidedBy = nil
-		booking.QuoteProvidedAt = nil
-		booking.QuoteExpiresAt = nil
-
-		err = qs.bookingRepo.Update(&booking)
+		// The fee release and the booking update commit together, so a refunded fee always
+		// leaves the quote expired; on failure the quote is left in place and retried next run
+		var release *InquiryFeeRelease
+		err := qs.db.Transaction(func(tx *gorm.DB) error {
+			// Re-read the booking under lock, as the quote may have been accepted or renewed, or
+			// expired by another replica, since it was listed
+			var current models.Booking
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, booking.ID).Error; err != nil {
+				return err
+			}
+			if current.Status != models.BookingStatusQuoteProvided || current.QuoteExpiresAt == nil || current.QuoteExpiresAt.After(time.Now()) {
+				return errQuoteNotExpired
+			}
+			current.User, current.Service, current.Payment = booking.User, booking.Service, booking.Payment
+			booking = current
+
+			var err error
+			release, err = qs.inquiryFeeService.ReleaseHeldFees(tx, &booking)
+			if err != nil {
+				return err
+			}
+			return qs.expireQuote(tx, &booking, release)
+		})
+		if errors.Is(err, errQuoteNotExpired) {
+			continue
+		}
 		if err != nil {
-			logrus.Errorf("Failed to cleanup expired quote for booking %d: %v", booking.ID, err)
+			logrus.Errorf("Failed to expire quote on booking %d: %v", booking.ID, err)
+			continue
 		}
+
+		expired = append(expired, ExpiredQuote{Booking: booking, FeeRelease: release})
 	}
 
-	if len(expiredBookings) > 0 {
-		logrus.Infof("Cleaned up %d expired quotes", len(expiredBookings))
+	if len(expired) > 0 {
+		logrus.Infof("Cleaned up %d expired quotes", len(expired))
 	}
 
+	return expired, nil
+}
+
+// errQuoteNotExpired rolls back the expiry of a quote that is no longer open and past its expiry
+var errQuoteNotExpired = errors.New("quote is no longer expired")
+
+// expireQuote expires the quote revisions of a booking, removes its payment segments and clears
+// the quote, within tx
+func (qs *QuoteService) expireQuote(tx *gorm.DB, booking *models.Booking, release *InquiryFeeRelease) error {
+	err := tx.Model(&models.QuoteRevision{}).
+		Where("booking_id = ? AND status IN ? AND locked_at IS NULL", booking.ID, openQuoteRevisionStatuses).
+		Update("status", models.QuoteRevisionStatusExpired).Error
+	if err != nil {
+		return fmt.Errorf("failed to expire quote revisions: %v", err)
+	}
+	if err := tx.Where("booking_id = ?", booking.ID).Delete(&models.PaymentSegment{}).Error; err != nil {
+		return fmt.Errorf("failed to remove payment segments: %v", err)
+	}
+
+	if release.Amount > 0 && release.Policy != InquiryFeePolicyRetain {
+		booking.Status = models.BookingStatusCancelled
+		booking.PaymentStatus = models.PaymentStatusRefunded
+	} else {
+		booking.Status = models.BookingStatusPending
+	}
+	// Clear quote details
+	booking.QuoteAmount = nil
+	booking.QuoteNotes = ""
+	booking.QuoteProvidedBy = nil
+	booking.QuoteProvidedAt = nil
+	booking.QuoteExpiresAt = nil
+	booking.QuoteExpiryWarningSentAt = nil
+
+	err = tx.Model(booking).
+		Omit("User", "Service", "WorkerAssignment", "BufferRequests", "PaymentSegments", "Payment", "CreatedAt",
+			"PINFailedAttempts", "PINLockedUntil").
+		Save(booking).Error
+	if err != nil {
+		return fmt.Errorf("failed to clear expired quote: %v", err)
+	}
 	return nil
 }
 
+// GetQuotesNeedingExpiryWarning gets live quotes about to expire whose customer has not been warned
+func (qs *QuoteService) GetQuotesNeedingExpiryWarning() ([]models.Booking, error) {
+	warningHours := qs.adminConfigService.GetQuoteExpiryWarningHours()
+	return qs.bookingRepo.GetQuotesExpiringBefore(time.Now().Add(time.Duration(warningHours) * time.Hour))
+}
+
+// MarkExpiryWarningSent records that the customer was warned about the quote's expiry
+func (qs *QuoteService) MarkExpiryWarningSent(bookingID uint) error {
+	return qs.bookingRepo.MarkQuoteExpiryWarningSent(bookingID, time.Now())
+}
+
+// ExtendQuoteValidity extends the expiry of a live quote (admin only)
+func (qs *QuoteService) ExtendQuoteValidity(bookingID uint, adminID uint, req *models.ExtendQuoteValidityRequest) (*models.Booking, error) {
+	// 1. Get booking
+	booking, err := qs.bookingRepo.GetByID(bookingID)
+	if err != nil {
+		return nil, errors.New("booking not found")
+	}
+
+	// 2. Only live quotes can be extended; expired ones stay quote_provided until the job runs
+	if booking.BookingType != models.BookingTypeInquiry {
+		return nil, errors.New("booking is not inquiry type")
+	}
+
+	if booking.Status != models.BookingStatusQuoteProvided {
+		return nil, errors.New("booking does not have a quote provided")
+	}
+
+	// 3. Work out the new expiry
+	now := time.Now()
+	var expiresAt time.Time
+	switch {
+	case req.ExpiresAt != nil:
+		expiresAt = *req.ExpiresAt
+	case req.Days != nil:
+		base := now
+		if booking.QuoteExpiresAt != nil && booking.QuoteExpiresAt.After(now) {
+			base = *booking.QuoteExpiresAt
+		}
+		expiresAt = base.AddDate(0, 0, *req.Days)
+	default:
+		return nil, errors.New("either days or expires_at is required")
+	}
+
+	if !expiresAt.After(now) {
+		return nil, errors.New("new expiry must be in the future")
+	}
+	if booking.QuoteExpiresAt != nil && !expiresAt.After(*booking.QuoteExpiresAt) {
+		return nil, errors.New("new expiry must be later than the current expiry")
+	}
+
+	// 4. Update booking; a fresh warning is sent before the new expiry
+	booking.QuoteExpiresAt = &expiresAt
+	booking.QuoteExpiryWarningSentAt = nil
+
+	err = qs.bookingRepo.Update(booking)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update booking: %v", err)
+	}
+
+	booking.GetPaymentProgress()
+
+	logrus.Infof("Quote validity for booking %d extended to %s by admin %d: %s", bookingID, expiresAt.Format(time.RFC3339), adminID, req.Notes)
+	return booking, nil
+}
+
 // CreateQuotePayment creates a payment order for quote acceptance
 func (qs *QuoteService) CreateQuotePayment(bookingID uint, userID uint, req *models.CreateQuotePaymentRequest) (map[string]interface{}, error) {
 	// 1. Get booking
@@ -528,8 +792,7 @@ func (qs *QuoteService) VerifyQuotePayment(bookingID uint, userID uint, req *mod
 		booking.ScheduledDate, booking.ScheduledTime)
 
 		// 4. Find the most recent payment record for this booking
-	paymentRepo := repositories.NewPaymentRepository()
-	payments, _, err := paymentRepo.GetPayments(&models.PaymentFilters{
+	payments, _, err := qs.paymentService.paymentRepo.GetPayments(&models.PaymentFilters{
 		RelatedEntityType: "booking",
 		RelatedEntityID:   &bookingID,
 		Limit:             1,
@@ -540,8 +803,7 @@ func (qs *QuoteService) VerifyQuotePayment(bookingID uint, userID uint, req *mod
 	payment := &payments[0]
 	
 	// 5. Verify payment with Razorpay using payment service
-	paymentService := NewPaymentService()
-	_, err = paymentService.VerifyAndCompletePayment(payment.ID, req.RazorpayPaymentID, req.RazorpaySignature)
+	_, err = qs.paymentService.VerifyAndCompletePayment(payment.ID, req.RazorpayPaymentID, req.RazorpaySignature)
 	if err != nil {
 		return nil, fmt.Errorf("payment verification failed: %v", err)
 	}
@@ -562,6 +824,8 @@ func (qs *QuoteService) VerifyQuotePayment(bookingID uint, userID uint, req *mod
 		return nil, fmt.Errorf("failed to update booking: %v", err)
 	}
 
+	qs.searchAnalyticsService.AttributeBooking(booking)
+
 	// 8. Mark payment segment as paid (if this is a segmented payment)
 	segments, err := qs.getPaymentSegments(bookingID)
 	if err == nil && len(segments) > 0 {
@@ -634,10 +898,8 @@ func (qs *QuoteService) WalletPayment(bookingID uint, userID uint, req *models.W
 	}
 
 	// 8. Process wallet payment using unified wallet service
-	walletService := NewUnifiedWalletService()
-	
 	// Deduct amount from wallet for booking payment
-	_, err = walletService.DeductFromWalletForBooking(userID, req.Amount, bookingID, "Quote payment for "+booking.BookingReference)
+	_, err = qs.walletService.DeductFromWalletForBooking(userID, req.Amount, bookingID, "Quote payment for "+booking.BookingReference)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process wallet payment: %v", err)
 	}
@@ -654,6 +916,8 @@ func (qs *QuoteService) WalletPayment(bookingID uint, userID uint, req *models.W
 		return nil, fmt.Errorf("failed to update booking: %v", err)
 	}
 
+	qs.searchAnalyticsService.AttributeBooking(booking)
+
 	// Calculate payment progress before returning
 	booking.GetPaymentProgress()
 	
@@ -661,21 +925,52 @@ func (qs *QuoteService) WalletPayment(bookingID uint, userID uint, req *models.W
 	return booking, nil
 }
 
-// createPaymentSegments creates payment segments for a booking
-func (qs *QuoteService) createPaymentSegments(bookingID uint, segments []models.PaymentSegmentRequest) error {
+// createPaymentSegments replaces the unpaid payment segments of a booking with
+// the ones from the given quote revision
+func (qs *QuoteService) createPaymentSegments(bookingID uint, quoteRevisionID uint, segments []models.PaymentSegmentRequest) error {
 	var paymentSegments []models.PaymentSegment
 
 	for i, segmentReq := range segments {
+		segmentNumber := i + 1
+
+		// The first segment is due on booking unless told otherwise; later
+		// segments wait for the admin unless a milestone is given
+		dueTrigger := segmentReq.DueTrigger
+		if dueTrigger == "" {
+			dueTrigger = models.PaymentSegmentDueManual
+			if segmentNumber == 1 {
+				dueTrigger = models.PaymentSegmentDueOnBooking
+			}
+		}
+		requiredBefore := segmentReq.RequiredBefore
+		if requiredBefore == "" {
+			requiredBefore = models.PaymentSegmentGateNone
+		}
+
+		if err := ValidateSegmentSchedule(segmentNumber, dueTrigger, segmentReq.DueAfterDays); err != nil {
+			return err
+		}
+
 		segment := models.PaymentSegment{
-			BookingID:     bookingID,
-			SegmentNumber: i + 1,
-			Amount:        segmentReq.Amount,
-			Status:        models.PaymentSegmentStatusPending,
-			Notes:         segmentReq.Notes,
+			BookingID:      bookingID,
+			QuoteRevisionID: &quoteRevisionID,
+			SegmentNumber:  segmentNumber,
+			Amount:         segmentReq.Amount,
+			Status:         models.PaymentSegmentStatusPending,
+			Notes:          segmentReq.Notes,
+			DueTrigger:     dueTrigger,
+			DueAfterDays:   segmentReq.DueAfterDays,
+			RequiredBefore: requiredBefore,
 		}
 		paymentSegments = append(paymentSegments, segment)
 	}
 
+	// Segments of a superseded revision are no longer payable. Quotes can only
+	// be revised before acceptance, so none of them has been paid.
+	if err := qs.paymentSegmentRepo.DeleteByBookingID(bookingID); err != nil {
+		return err
+	}
+
 	return qs.paymentSegmentRepo.CreateMultiple(paymentSegments)
 }
 
@@ -704,8 +999,6 @@ func (qs *QuoteService) processSinglePayment(bookingID uint, userID uint, req *m
 	}
 	
 	// Process payment using existing logic
-	paymentService := NewPaymentService()
-	
 	paymentReq := &models.CreatePaymentRequest{
 		UserID:            userID,
 		Amount:            req.Amount,
@@ -717,11 +1010,20 @@ func (qs *QuoteService) processSinglePayment(bookingID uint, userID uint, req *m
 		Description:       "Quote payment for booking",
 		Notes:             "Quote payment for booking",
 	}
+
+	redemption, err := qs.applyQuoteCoupon(bookingID, userID, req, paymentReq)
+	if err != nil {
+		return nil, err
+	}
 	
-	_, paymentOrder, err := paymentService.CreateRazorpayOrder(paymentReq)
+	payment, paymentOrder, err := qs.paymentService.CreateRazorpayOrder(paymentReq)
 	if err != nil {
+		qs.couponService.Release("booking", bookingID, userID)
 		return nil, fmt.Errorf("failed to create payment order: %v", err)
 	}
+	if redemption != nil {
+		qs.couponService.AttachPayment(redemption, payment.ID)
+	}
 	
 	// Update booking with scheduling details (but don't mark as completed yet)
 	if req.ScheduledDate == nil || req.ScheduledTime == nil {
@@ -795,8 +1097,8 @@ func (qs *QuoteService) processSegmentPayment(bookingID uint, userID uint, req *
 		return nil, errors.New("payment segment not found")
 	}
 	
-	// Validate segment is pending
-	if segment.Status != models.PaymentSegmentStatusPending {
+	// Validate segment is unpaid
+	if !segment.IsUnpaid() {
 		return nil, errors.New("payment segment is not pending")
 	}
 	
@@ -806,8 +1108,6 @@ func (qs *QuoteService) processSegmentPayment(bookingID uint, userID uint, req *
 	}
 	
 	// Process payment using existing logic
-	paymentService := NewPaymentService()
-	
 	paymentReq := &models.CreatePaymentRequest{
 		UserID:            userID,
 		Amount:            req.Amount,
@@ -819,11 +1119,20 @@ func (qs *QuoteService) processSegmentPayment(bookingID uint, userID uint, req *
 		Description:       fmt.Sprintf("Segment %d payment for booking", segment.SegmentNumber),
 		Notes:             fmt.Sprintf("Segment %d payment for booking", segment.SegmentNumber),
 	}
+
+	redemption, err := qs.applyQuoteCoupon(bookingID, userID, req, paymentReq)
+	if err != nil {
+		return nil, err
+	}
 	
-	_, paymentOrder, err := paymentService.CreateRazorpayOrder(paymentReq)
+	payment, paymentOrder, err := qs.paymentService.CreateRazorpayOrder(paymentReq)
 	if err != nil {
+		qs.couponService.Release("booking", bookingID, userID)
 		return nil, fmt.Errorf("failed to create payment order: %v", err)
 	}
+	if redemption != nil {
+		qs.couponService.AttachPayment(redemption, payment.ID)
+	}
 	
 	// If this is the first segment, update booking scheduling (only if scheduled date/time provided)
 	if segment.SegmentNumber == 1 && req.ScheduledDate != nil && req.ScheduledTime != nil {
@@ -905,8 +1214,8 @@ func (qs *QuoteService) CreateSegmentPayment(bookingID uint, userID uint, req *m
 		return nil, errors.New("payment segment not found")
 	}
 
-	// Validate segment is pending
-	if segment.Status != models.PaymentSegmentStatusPending {
+	// Validate segment is unpaid
+	if !segment.IsUnpaid() {
 		return nil, errors.New("payment segment is not pending")
 	}
 
@@ -918,10 +1227,8 @@ func (qs *QuoteService) CreateSegmentPayment(bookingID uint, userID uint, req *m
 	// Handle different payment methods
 	if req.PaymentMethod == "wallet" {
 		// Process wallet payment
-		walletService := NewUnifiedWalletService()
-		
 		description := fmt.Sprintf("Segment %d payment for booking", segment.SegmentNumber)
-		payment, err := walletService.DeductFromWalletForBooking(userID, req.Amount, bookingID, description)
+		payment, err := qs.walletService.DeductFromWalletForBooking(userID, req.Amount, bookingID, description)
 		if err != nil {
 			return nil, fmt.Errorf("wallet payment failed: %v", err)
 		}
@@ -951,6 +1258,7 @@ func (qs *QuoteService) CreateSegmentPayment(bookingID uint, userID uint, req *m
 				booking.PaymentStatus = "completed"
 				qs.bookingRepo.Update(booking)
 			}
+			qs.searchAnalyticsService.AttributeBooking(booking)
 		}
 
 		return map[string]interface{}{
@@ -960,8 +1268,6 @@ func (qs *QuoteService) CreateSegmentPayment(bookingID uint, userID uint, req *m
 		}, nil
 	} else {
 		// Process Razorpay payment (existing logic)
-		paymentService := NewPaymentService()
-		
 		paymentReq := &models.CreatePaymentRequest{
 			UserID:            userID,
 			Amount:            req.Amount,
@@ -974,7 +1280,7 @@ func (qs *QuoteService) CreateSegmentPayment(bookingID uint, userID uint, req *m
 			Notes:             fmt.Sprintf("Segment %d payment for booking", segment.SegmentNumber),
 		}
 		
-		_, paymentOrder, err := paymentService.CreateRazorpayOrder(paymentReq)
+		_, paymentOrder, err := qs.paymentService.CreateRazorpayOrder(paymentReq)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create payment order: %v", err)
 		}
@@ -1010,15 +1316,13 @@ func (qs *QuoteService) VerifySegmentPayment(bookingID uint, userID uint, req *m
 	}
 
 	// 4. Find payment by Razorpay order ID
-	paymentRepo := repositories.NewPaymentRepository()
-	payment, err := paymentRepo.GetByRazorpayOrderID(req.RazorpayOrderID)
+	payment, err := qs.paymentService.GetPaymentByRazorpayOrderID(req.RazorpayOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("payment not found: %v", err)
 	}
 
 	// 5. Verify payment with Razorpay using payment service
-	paymentService := NewPaymentService()
-	_, err = paymentService.VerifyAndCompletePayment(payment.ID, req.RazorpayPaymentID, req.RazorpaySignature)
+	_, err = qs.paymentService.VerifyAndCompletePayment(payment.ID, req.RazorpayPaymentID, req.RazorpaySignature)
 	if err != nil {
 		return nil, fmt.Errorf("payment verification failed: %v", err)
 	}
@@ -1029,6 +1333,8 @@ func (qs *QuoteService) VerifySegmentPayment(bookingID uint, userID uint, req *m
 		return nil, fmt.Errorf("failed to get updated booking: %v", err)
 	}
 
+	qs.searchAnalyticsService.AttributeBooking(updatedBooking)
+
 	// Calculate payment progress before returning
 	updatedBooking.GetPaymentProgress()
 	
@@ -1068,6 +1374,10 @@ func (qs *QuoteService) GetPendingSegments(bookingID uint, userID uint) ([]model
 			PaidAt:        segment.PaidAt,
 			Notes:         segment.Notes,
 			PaymentID:     segment.PaymentID,
+			DueTrigger:     segment.DueTrigger,
+			DueAfterDays:   segment.DueAfterDays,
+			DueAt:          segment.DueAt,
+			RequiredBefore: segment.RequiredBefore,
 		}
 		segmentInfos = append(segmentInfos, segmentInfo)
 	}
@@ -1104,9 +1414,289 @@ func (qs *QuoteService) GetPaidSegments(bookingID uint, userID uint) ([]models.P
 			PaidAt:        segment.PaidAt,
 			Notes:         segment.Notes,
 			PaymentID:     segment.PaymentID,
+			DueTrigger:     segment.DueTrigger,
+			DueAfterDays:   segment.DueAfterDays,
+			DueAt:          segment.DueAt,
+			RequiredBefore: segment.RequiredBefore,
 		}
 		segmentInfos = append(segmentInfos, segmentInfo)
 	}
 
 	return segmentInfos, nil
 }
+
+// openQuoteRevisionStatuses are the statuses of revisions still under negotiation
+var openQuoteRevisionStatuses = []models.QuoteRevisionStatus{
+	models.QuoteRevisionStatusActive,
+	models.QuoteRevisionStatusOpen,
+}
+
+// CounterOffer records a customer's counter-offer on the current quote (customer only)
+func (qs *QuoteService) CounterOffer(bookingID uint, userID uint, req *models.CounterOfferRequest) (*models.QuoteRevision, error) {
+	booking, active, err := qs.getNegotiableQuote(bookingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if math.Abs(req.Amount-active.TotalAmount) < 0.01 {
+		return nil, errors.New("counter-offer must differ from the quoted amount")
+	}
+
+	revision, err := qs.addCustomerRevision(booking, userID, models.QuoteRevisionTypeCounterOffer, req.Amount, req.Notes)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Counter-offer on booking %d by user %d: amount=%.2f (quoted %.2f)", bookingID, userID, req.Amount, active.TotalAmount)
+	return revision, nil
+}
+
+// RequestRevision records a customer's request for a revised quote (customer only)
+func (qs *QuoteService) RequestRevision(bookingID uint, userID uint, req *models.RequestQuoteRevisionRequest) (*models.QuoteRevision, error) {
+	booking, active, err := qs.getNegotiableQuote(bookingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := qs.addCustomerRevision(booking, userID, models.QuoteRevisionTypeRevisionRequest, active.TotalAmount, req.Notes)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Quote revision requested on booking %d by user %d", bookingID, userID)
+	return revision, nil
+}
+
+// GetQuoteRevisions gets the full quote history of a booking
+func (qs *QuoteService) GetQuoteRevisions(bookingID uint, userID uint, isAdmin bool) ([]models.QuoteRevision, error) {
+	booking, err := qs.bookingRepo.GetByID(bookingID)
+	if err != nil {
+		return nil, errors.New("booking not found")
+	}
+
+	if !isAdmin && booking.UserID != userID {
+		return nil, errors.New("unauthorized access to booking")
+	}
+
+	return qs.quoteRevisionRepo.GetByBookingID(bookingID)
+}
+
+// getNegotiableQuote validates that the customer can respond to the active quote
+func (qs *QuoteService) getNegotiableQuote(bookingID uint, userID uint) (*models.Booking, *models.QuoteRevision, error) {
+	booking, err := qs.bookingRepo.GetByID(bookingID)
+	if err != nil {
+		return nil, nil, errors.New("booking not found")
+	}
+
+	if booking.UserID != userID {
+		return nil, nil, errors.New("unauthorized access to booking")
+	}
+
+	if booking.BookingType != models.BookingTypeInquiry {
+		return nil, nil, errors.New("booking is not inquiry type")
+	}
+
+	if booking.Status != models.BookingStatusQuoteProvided {
+		return nil, nil, errors.New("booking does not have a quote provided")
+	}
+
+	if booking.QuoteExpiresAt != nil && time.Now().After(*booking.QuoteExpiresAt) {
+		return nil, nil, errors.New("quote has expired")
+	}
+
+	active, err := qs.quoteRevisionRepo.GetActiveQuote(bookingID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("quote is already awaiting revision")
+		}
+		return nil, nil, fmt.Errorf("failed to get current quote: %v", err)
+	}
+
+	return booking, active, nil
+}
+
+// addCustomerRevision records a customer response and takes the active quote off the table
+// until the admin sends a revised version
+func (qs *QuoteService) addCustomerRevision(booking *models.Booking, userID uint, revisionType models.QuoteRevisionType, amount float64, notes string) (*models.QuoteRevision, error) {
+	version, err := qs.quoteRevisionRepo.GetLatestVersion(booking.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote version: %v", err)
+	}
+
+	err = qs.quoteRevisionRepo.UpdateStatusByBooking(booking.ID, openQuoteRevisionStatuses, models.QuoteRevisionStatusSuperseded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update quote revisions: %v", err)
+	}
+
+	revision := &models.QuoteRevision{
+		BookingID:   booking.ID,
+		Version:     version + 1,
+		Type:        revisionType,
+		Status:      models.QuoteRevisionStatusOpen,
+		AuthorID:    userID,
+		AuthorType:  models.UserTypeNormal,
+		Subtotal:    amount,
+		TotalAmount: amount,
+		Notes:       notes,
+	}
+	if err := qs.quoteRevisionRepo.Create(revision); err != nil {
+		return nil, fmt.Errorf("failed to save quote revision: %v", err)
+	}
+
+	return revision, nil
+}
+
+// createQuoteRevision records a new admin quote version, superseding any earlier
+// quote or open customer response
+func (qs *QuoteService) createQuoteRevision(booking *models.Booking, adminID uint, notes string, total float64, duration *string, lineItemReqs []models.QuoteLineItemRequest) (*models.QuoteRevision, error) {
+	lineItems, subtotal, taxAmount := buildQuoteLineItems(lineItemReqs)
+	if len(lineItems) > 0 {
+		if math.Abs(subtotal+taxAmount-total) > 0.01 {
+			return nil, fmt.Errorf("line items total %.2f does not match quote total %.2f", subtotal+taxAmount, total)
+		}
+	} else {
+		subtotal = total
+	}
+
+	version, err := qs.quoteRevisionRepo.GetLatestVersion(booking.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote version: %v", err)
+	}
+
+	err = qs.quoteRevisionRepo.UpdateStatusByBooking(booking.ID, openQuoteRevisionStatuses, models.QuoteRevisionStatusSuperseded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update quote revisions: %v", err)
+	}
+
+	revision := &models.QuoteRevision{
+		BookingID:   booking.ID,
+		Version:     version + 1,
+		Type:        models.QuoteRevisionTypeQuote,
+		Status:      models.QuoteRevisionStatusActive,
+		AuthorID:    adminID,
+		AuthorType:  models.UserTypeAdmin,
+		Subtotal:    subtotal,
+		TaxAmount:   taxAmount,
+		TotalAmount: total,
+		Notes:       notes,
+		Duration:    duration,
+		LineItems:   lineItems,
+	}
+	if err := qs.quoteRevisionRepo.Create(revision); err != nil {
+		return nil, fmt.Errorf("failed to save quote revision: %v", err)
+	}
+
+	return revision, nil
+}
+
+// getAcceptableRevision gets the quote version the customer can accept. Bookings
+// quoted before versioning have no revisions and are accepted as before.
+func (qs *QuoteService) getAcceptableRevision(bookingID uint) (*models.QuoteRevision, error) {
+	revision, err := qs.quoteRevisionRepo.GetActiveQuote(bookingID)
+	if err == nil {
+		return revision, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get current quote: %v", err)
+	}
+
+	count, err := qs.quoteRevisionRepo.CountByBookingID(bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote revisions: %v", err)
+	}
+	if count > 0 {
+		return nil, errors.New("quote is awaiting revision")
+	}
+	return nil, nil
+}
+
+// getCurrentQuoteRevision gets the accepted revision, or the active one if none is accepted yet
+func (qs *QuoteService) getCurrentQuoteRevision(booking *models.Booking) (*models.QuoteRevision, error) {
+	if booking.AcceptedQuoteRevisionID != nil {
+		return qs.quoteRevisionRepo.GetByID(*booking.AcceptedQuoteRevisionID)
+	}
+
+	revision, err := qs.quoteRevisionRepo.GetActiveQuote(booking.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return revision, nil
+}
+
+// buildQuoteLineItems converts line item requests and totals them, keeping taxes separate
+func buildQuoteLineItems(reqs []models.QuoteLineItemRequest) ([]models.QuoteLineItem, float64, float64) {
+	var lineItems []models.QuoteLineItem
+	var subtotal, taxAmount float64
+
+	for _, req := range reqs {
+		quantity := req.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		amount := math.Round(quantity*req.UnitPrice*100) / 100
+
+		lineItems = append(lineItems, models.QuoteLineItem{
+			Type:        req.Type,
+			Description: req.Description,
+			Quantity:    quantity,
+			UnitPrice:   req.UnitPrice,
+			Amount:      amount,
+		})
+
+		if req.Type == models.QuoteLineItemTypeTax {
+			taxAmount += amount
+		} else {
+			subtotal += amount
+		}
+	}
+
+	return lineItems, subtotal, taxAmount
+}
+
+// quoteExpiry gets the expiry of a quote provided at the given time
+func (qs *QuoteService) quoteExpiry(providedAt time.Time) *time.Time {
+	expiresAt := providedAt.AddDate(0, 0, qs.adminConfigService.GetQuoteValidityDays())
+	return &expiresAt
+}
+
+// applyQuoteCoupon discounts a quote payment with the customer's promo code and
+// reserves the coupon. The segment is still settled in full once the payment completes.
+func (qs *QuoteService) applyQuoteCoupon(bookingID uint, userID uint, req *models.CreateQuotePaymentRequest, paymentReq *models.CreatePaymentRequest) (*models.CouponRedemption, error) {
+	// A new payment order replaces any earlier unpaid one for this booking
+	qs.couponService.Release("booking", bookingID, userID)
+
+	if req.CouponCode == "" {
+		return nil, nil
+	}
+
+	booking, err := qs.bookingRepo.GetByID(bookingID)
+	if err != nil {
+		return nil, errors.New("booking not found")
+	}
+
+	checkout := &CouponCheckout{
+		UserID: userID,
+		Scope:  models.CouponScopeQuote,
+		Amount: paymentReq.Amount,
+	}
+	if err := qs.couponService.QuoteCheckout(booking, checkout); err != nil {
+		return nil, err
+	}
+
+	discount, err := qs.couponService.CalculateDiscount(req.CouponCode, checkout)
+	if err != nil {
+		return nil, err
+	}
+
+	redemption, err := qs.couponService.Reserve(discount, checkout, "booking", bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentReq.Amount = discount.FinalAmount
+	paymentReq.Metadata = discount.PaymentMetadata(paymentReq.Metadata)
+	return redemption, nil
+}
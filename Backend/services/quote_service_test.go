@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"testing"
+	"treesindia/models"
+	"treesindia/repositories"
+	"treesindia/testutil"
+)
+
+func TestQuoteAndSegmentPayments(t *testing.T) {
+	f := newFlowFixture(t)
+	testutil.SetAdminConfig(t, "inquiry_booking_fee", "99")
+	service := testutil.CreateInquiryService(t, "Siliguri", "West Bengal")
+	admin := testutil.CreateAdmin(t)
+	user := testutil.CreateUser(t, 2000)
+	booking := bookInquiry(t, f, user.ID, service)
+
+	// Admin quotes the work in two segments
+	quoted, err := f.quotes.ProvideQuote(booking.ID, admin.ID, &models.ProvideQuoteRequest{
+		Notes: "Materials and labour",
+		Segments: []models.PaymentSegmentRequest{
+			{Amount: 1500},
+			{Amount: 2500},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ProvideQuote returned error: %v", err)
+	}
+	if quoted.Status != models.BookingStatusQuoteProvided || quoted.QuoteAmount == nil || *quoted.QuoteAmount != 4000 {
+		t.Fatalf("quoted booking status/amount = %s/%v, want quote_provided/4000", quoted.Status, quoted.QuoteAmount)
+	}
+
+	// Only the customer can accept the quote
+	other := testutil.CreateUser(t, 0)
+	if _, err := f.quotes.AcceptQuote(booking.ID, other.ID, &models.AcceptQuoteRequest{}); err == nil {
+		t.Fatal("AcceptQuote let another user accept the quote")
+	}
+	accepted, err := f.quotes.AcceptQuote(booking.ID, user.ID, &models.AcceptQuoteRequest{})
+	if err != nil {
+		t.Fatalf("AcceptQuote returned error: %v", err)
+	}
+	if accepted.Status != models.BookingStatusQuoteAccepted {
+		t.Fatalf("booking status = %s, want quote_accepted", accepted.Status)
+	}
+
+	// Accepting the quote captures the held inquiry fee
+	fee, err := f.payments.GetPaymentByRazorpayOrderID(f.gateway.Orders()[0]["id"].(string))
+	if err != nil {
+		t.Fatalf("inquiry fee payment not found: %v", err)
+	}
+	if fee.Status != models.PaymentStatusCompleted {
+		t.Errorf("inquiry fee status = %s, want completed", fee.Status)
+	}
+
+	// Segment 1 is paid from the wallet
+	if _, err := f.quotes.CreateSegmentPayment(booking.ID, user.ID, &models.CreateSegmentPaymentRequest{
+		SegmentNumber: 1,
+		Amount:        1000,
+		PaymentMethod: "wallet",
+	}); err == nil {
+		t.Fatal("CreateSegmentPayment accepted an amount that does not match the segment")
+	}
+	if _, err := f.quotes.CreateSegmentPayment(booking.ID, user.ID, &models.CreateSegmentPaymentRequest{
+		SegmentNumber: 1,
+		Amount:        1500,
+		PaymentMethod: "wallet",
+	}); err != nil {
+		t.Fatalf("wallet segment payment returned error: %v", err)
+	}
+
+	var reloaded models.User
+	if err := repositories.NewUserRepository().FindByID(&reloaded, user.ID); err != nil {
+		t.Fatalf("failed to reload user: %v", err)
+	}
+	if reloaded.WalletBalance != 500 {
+		t.Errorf("wallet balance = %.2f, want 500", reloaded.WalletBalance)
+	}
+
+	// Segment 2 is paid online
+	result, err := f.quotes.CreateSegmentPayment(booking.ID, user.ID, &models.CreateSegmentPaymentRequest{
+		SegmentNumber: 2,
+		Amount:        2500,
+		PaymentMethod: "razorpay",
+	})
+	if err != nil {
+		t.Fatalf("online segment payment returned error: %v", err)
+	}
+	order, ok := result["payment_order"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("online segment payment returned %v, want a payment order", result)
+	}
+	orderID := order["id"].(string)
+
+	if _, err := f.quotes.VerifySegmentPayment(booking.ID, user.ID, &models.VerifySegmentPaymentRequest{
+		RazorpayOrderID:   orderID,
+		RazorpayPaymentID: "pay_segment_2",
+		RazorpaySignature: f.gateway.Sign(orderID, "pay_segment_2"),
+	}); err != nil {
+		t.Fatalf("VerifySegmentPayment returned error: %v", err)
+	}
+
+	// With every segment paid the booking is confirmed
+	segments, err := repositories.NewPaymentSegmentRepository().GetByBookingID(booking.ID)
+	if err != nil {
+		t.Fatalf("failed to get payment segments: %v", err)
+	}
+	for _, segment := range segments {
+		if segment.Status != models.PaymentSegmentStatusPaid || segment.PaymentID == nil {
+			t.Errorf("segment %d status = %s, want paid", segment.SegmentNumber, segment.Status)
+		}
+	}
+
+	confirmed, err := repositories.NewBookingRepository().GetByID(booking.ID)
+	if err != nil {
+		t.Fatalf("failed to reload booking: %v", err)
+	}
+	if confirmed.Status != models.BookingStatusConfirmed || confirmed.PaymentStatus != models.PaymentStatusCompleted {
+		t.Errorf("booking status/payment status = %s/%s, want confirmed/completed", confirmed.Status, confirmed.PaymentStatus)
+	}
+}
+
+func TestProvideQuoteRequiresPendingInquiry(t *testing.T) {
+	f := newFlowFixture(t)
+	testutil.SetAdminConfig(t, "inquiry_booking_fee", "0")
+	service := testutil.CreateInquiryService(t, "Siliguri", "West Bengal")
+	admin := testutil.CreateAdmin(t)
+	user := testutil.CreateUser(t, 0)
+
+	booking, _, err := f.bookings.CreateInquiryBooking(user.ID, inquiryRequest(service))
+	if err != nil {
+		t.Fatalf("CreateInquiryBooking returned error: %v", err)
+	}
+
+	req := &models.ProvideQuoteRequest{Segments: []models.PaymentSegmentRequest{{Amount: 1000}}}
+	if _, err := f.quotes.ProvideQuote(booking.ID, admin.ID, &models.ProvideQuoteRequest{
+		Segments: []models.PaymentSegmentRequest{{Amount: 0}},
+	}); err == nil {
+		t.Error("ProvideQuote accepted a quote of 0")
+	}
+	if _, err := f.quotes.ProvideQuote(booking.ID, admin.ID, req); err != nil {
+		t.Fatalf("ProvideQuote returned error: %v", err)
+	}
+	if _, err := f.quotes.ProvideQuote(booking.ID, admin.ID, req); err == nil {
+		t.Error("ProvideQuote quoted a booking that already has a quote")
+	}
+}
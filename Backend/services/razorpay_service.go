@@ -15,6 +15,19 @@ import (
 
 
 
+// PaymentGateway takes online payments. RazorpayService is the production gateway; services take a
+// PaymentGateway so tests can run payment flows without calling Razorpay.
+type PaymentGateway interface {
+	// CreateOrder creates an order for amount (in rupees) and returns it; the order ID is under "id"
+	CreateOrder(amount float64, receipt string, notes string) (map[string]interface{}, error)
+	// VerifyPayment checks the signature the checkout returned for a payment of an order
+	VerifyPayment(paymentID, orderID, signature string) (bool, error)
+	// GetPaymentDetails fetches a payment
+	GetPaymentDetails(paymentID string) (map[string]interface{}, error)
+	// RefundPayment refunds a captured payment, fully or partially
+	RefundPayment(paymentID string, amount float64, notes string) (map[string]interface{}, error)
+}
+
 type RazorpayService struct {
 	keyID     string
 	keySecret string
@@ -213,6 +226,67 @@ func (rs *RazorpayService) GetPaymentDetails(paymentID string) (map[string]inter
 	return paymentDetails, nil
 }
 
+// RefundPayment refunds a captured payment, fully or partially
+func (rs *RazorpayService) RefundPayment(paymentID string, amount float64, notes string) (map[string]interface{}, error) {
+	// Check if Razorpay is configured
+	if rs.keyID == "" || rs.keySecret == "" {
+		return nil, fmt.Errorf("razorpay is not configured - missing API keys")
+	}
+	
+	// Prepare request payload (amount in paise)
+	payload := map[string]interface{}{
+		"amount": int64(amount * 100),
+		"notes": map[string]string{
+			"reason": notes,
+		},
+	}
+	
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	
+	// Create HTTP request
+	req, err := http.NewRequest("POST", fmt.Sprintf("https://api.razorpay.com/v1/payments/%s/refund", paymentID), bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+	auth := rs.getBasicAuth()
+	if auth == "" {
+		return nil, fmt.Errorf("razorpay is not configured - missing API keys")
+	}
+	req.Header.Set("Authorization", "Basic "+auth)
+	
+	// Make request
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+	
+	// Read response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("razorpay API error: %s", string(body))
+	}
+	
+	// Parse response
+	var refund map[string]interface{}
+	if err := json.Unmarshal(body, &refund); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	
+	return refund, nil
+}
+
 // IsPaymentSuccessful checks if payment was successful
 func (rs *RazorpayService) IsPaymentSuccessful(paymentDetails map[string]interface{}) bool {
 	// Check if Razorpay is configured
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"treesindia/config"
+	"treesindia/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Realtime pub/sub backends
+const (
+	RealtimePubSubBackendMemory   = "memory"
+	RealtimePubSubBackendPostgres = "postgres"
+)
+
+// Realtime pub/sub channels used by the websocket services
+const (
+	RealtimeChannelChatHub            = "treesindia_chat_hub"
+	RealtimeChannelSimpleConversation = "treesindia_simple_conversation"
+)
+
+// realtimeBroadcastBuffer is the queue length of the websocket broadcast channels. Events from other
+// instances are dropped rather than stalling the pub/sub listener when the queue is full.
+const realtimeBroadcastBuffer = 256
+
+// Postgres rejects NOTIFY payloads of 8000 bytes or more. Anything larger is
+// parked in realtime_events and only its reference is sent over NOTIFY.
+const (
+	postgresNotifyMaxPayload   = 7900
+	postgresNotifyRefPrefix    = "ref:"
+	realtimeEventRetention     = time.Hour
+	realtimeEventCleanupPeriod = 10 * time.Minute
+)
+
+// RealtimePubSub fans websocket broadcasts out to every backend instance.
+// Publishers send an opaque payload on a channel; every subscriber of that
+// channel on every instance (including the publisher's own) receives it.
+type RealtimePubSub interface {
+	// InstanceID identifies this backend process so handlers can skip their own events
+	InstanceID() string
+	// Publish sends a payload to every subscriber of the channel
+	Publish(channel string, payload []byte) error
+	// Subscribe registers a handler for payloads published on the channel
+	Subscribe(channel string, handler func(payload []byte)) error
+	// Close releases any resources held by the backend
+	Close() error
+}
+
+// NewRealtimePubSub creates the pub/sub backend selected in the app config.
+// It falls back to the in-memory backend if Postgres LISTEN cannot be set up,
+// so a single node keeps working even when fan-out is unavailable.
+func NewRealtimePubSub(appConfig *config.AppConfig) RealtimePubSub {
+	switch appConfig.RealtimePubSubBackend {
+	case RealtimePubSubBackendPostgres:
+		pubSub, err := NewPostgresPubSub(appConfig.GetDatabaseURL(), database.GetDB())
+		if err != nil {
+			logrus.Errorf("Failed to initialize Postgres realtime pub/sub, falling back to in-memory: %v", err)
+			return NewInMemoryPubSub()
+		}
+		logrus.Info("Realtime pub/sub initialized with Postgres LISTEN/NOTIFY backend")
+		return pubSub
+	case RealtimePubSubBackendMemory, "":
+		logrus.Info("Realtime pub/sub initialized with in-memory backend")
+		return NewInMemoryPubSub()
+	default:
+		logrus.Warnf("Unknown realtime pub/sub backend %q, using in-memory", appConfig.RealtimePubSubBackend)
+		return NewInMemoryPubSub()
+	}
+}
+
+// subscriberRegistry keeps the handlers registered per channel
+type subscriberRegistry struct {
+	handlers map[string][]func(payload []byte)
+	mu       sync.RWMutex
+}
+
+func newSubscriberRegistry() *subscriberRegistry {
+	return &subscriberRegistry{
+		handlers: make(map[string][]func(payload []byte)),
+	}
+}
+
+// add registers a handler and reports whether it is the first one for the channel
+func (r *subscriberRegistry) add(channel string, handler func(payload []byte)) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	first := len(r.handlers[channel]) == 0
+	r.handlers[channel] = append(r.handlers[channel], handler)
+	return first
+}
+
+// dispatch delivers a payload to every handler of the channel
+func (r *subscriberRegistry) dispatch(channel string, payload []byte) {
+	r.mu.RLock()
+	handlers := append([]func(payload []byte){}, r.handlers[channel]...)
+	r.mu.RUnlock()
+
+	for _, handler := range handlers {
+		func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					logrus.Errorf("Realtime pub/sub handler for channel %s panicked: %v", channel, rec)
+				}
+			}()
+			handler(payload)
+		}()
+	}
+}
+
+// InMemoryPubSub delivers payloads within the current process only.
+// It is the default for single-node deployments.
+type InMemoryPubSub struct {
+	instanceID  string
+	subscribers *subscriberRegistry
+}
+
+// NewInMemoryPubSub creates a new in-memory pub/sub backend
+func NewInMemoryPubSub() *InMemoryPubSub {
+	return &InMemoryPubSub{
+		instanceID:  uuid.New().String(),
+		subscribers: newSubscriberRegistry(),
+	}
+}
+
+// InstanceID returns the identifier of this process
+func (ps *InMemoryPubSub) InstanceID() string {
+	return ps.instanceID
+}
+
+// Publish delivers the payload to local subscribers asynchronously
+func (ps *InMemoryPubSub) Publish(channel string, payload []byte) error {
+	go ps.subscribers.dispatch(channel, payload)
+	return nil
+}
+
+// Subscribe registers a handler for the channel
+func (ps *InMemoryPubSub) Subscribe(channel string, handler func(payload []byte)) error {
+	ps.subscribers.add(channel, handler)
+	return nil
+}
+
+// Close is a no-op for the in-memory backend
+func (ps *InMemoryPubSub) Close() error {
+	return nil
+}
+
+// PostgresPubSub fans payloads out across instances using Postgres LISTEN/NOTIFY
+type PostgresPubSub struct {
+	instanceID  string
+	db          *gorm.DB
+	listener    *pq.Listener
+	subscribers *subscriberRegistry
+	stopChan    chan bool
+}
+
+// NewPostgresPubSub creates a Postgres-backed pub/sub using a dedicated listener connection
+func NewPostgresPubSub(dsn string, db *gorm.DB) (*PostgresPubSub, error) {
+	if db == nil {
+		return nil, errors.New("database connection is not initialized")
+	}
+
+	listener := pq.NewListener(dsn, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventConnectionAttemptFailed:
+			logrus.Errorf("Realtime pub/sub listener connection attempt failed: %v", err)
+		case pq.ListenerEventDisconnected:
+			logrus.Warnf("Realtime pub/sub listener disconnected: %v", err)
+		case pq.ListenerEventReconnected:
+			logrus.Info("Realtime pub/sub listener reconnected")
+		}
+	})
+
+	if err := listener.Ping(); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to connect listener: %v", err)
+	}
+
+	ps := &PostgresPubSub{
+		instanceID:  uuid.New().String(),
+		db:          db,
+		listener:    listener,
+		subscribers: newSubscriberRegistry(),
+		stopChan:    make(chan bool),
+	}
+
+	go ps.run()
+
+	return ps, nil
+}
+
+// InstanceID returns the identifier of this process
+func (ps *PostgresPubSub) InstanceID() string {
+	return ps.instanceID
+}
+
+// Publish sends the payload with NOTIFY, spilling oversized payloads to realtime_events
+func (ps *PostgresPubSub) Publish(channel string, payload []byte) error {
+	message := string(payload)
+
+	if len(payload) > postgresNotifyMaxPayload {
+		var eventID uint
+		err := ps.db.Raw("INSERT INTO realtime_events (channel, payload) VALUES (?, ?) RETURNING id", channel, message).
+			Scan(&eventID).Error
+		if err != nil {
+			return fmt.Errorf("failed to store oversized realtime event: %v", err)
+		}
+		message = postgresNotifyRefPrefix + strconv.FormatUint(uint64(eventID), 10)
+	}
+
+	if err := ps.db.Exec("SELECT pg_notify(?, ?)", channel, message).Error; err != nil {
+		return fmt.Errorf("failed to notify channel %s: %v", channel, err)
+	}
+	return nil
+}
+
+// Subscribe registers a handler and starts listening on the channel
+func (ps *PostgresPubSub) Subscribe(channel string, handler func(payload []byte)) error {
+	if ps.subscribers.add(channel, handler) {
+		if err := ps.listener.Listen(channel); err != nil && !errors.Is(err, pq.ErrChannelAlreadyOpen) {
+			return fmt.Errorf("failed to listen on channel %s: %v", channel, err)
+		}
+	}
+	return nil
+}
+
+// Close stops the listener
+func (ps *PostgresPubSub) Close() error {
+	close(ps.stopChan)
+	return ps.listener.Close()
+}
+
+// run dispatches notifications and periodically purges spilled payloads
+func (ps *PostgresPubSub) run() {
+	cleanupTicker := time.NewTicker(realtimeEventCleanupPeriod)
+	defer cleanupTicker.Stop()
+
+	for {
+		select {
+		case notification, ok := <-ps.listener.Notify:
+			if !ok {
+				return
+			}
+			// A nil notification is sent after a reconnect; anything published
+			// while disconnected is lost, which is acceptable for live updates
+			if notification == nil {
+				continue
+			}
+			payload, err := ps.resolvePayload(notification.Extra)
+			if err != nil {
+				logrus.Errorf("Failed to resolve realtime event on channel %s: %v", notification.Channel, err)
+				continue
+			}
+			ps.subscribers.dispatch(notification.Channel, payload)
+
+		case <-cleanupTicker.C:
+			cutoff := time.Now().Add(-realtimeEventRetention)
+			if err := ps.db.Exec("DELETE FROM realtime_events WHERE created_at < ?", cutoff).Error; err != nil {
+				logrus.Errorf("Failed to purge old realtime events: %v", err)
+			}
+
+		case <-ps.stopChan:
+			return
+		}
+	}
+}
+
+// resolvePayload loads spilled payloads referenced by a notification
+func (ps *PostgresPubSub) resolvePayload(extra string) ([]byte, error) {
+	if !strings.HasPrefix(extra, postgresNotifyRefPrefix) {
+		return []byte(extra), nil
+	}
+
+	eventID, err := strconv.ParseUint(strings.TrimPrefix(extra, postgresNotifyRefPrefix), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid realtime event reference %q", extra)
+	}
+
+	var payload sql.NullString
+	err = ps.db.Raw("SELECT payload FROM realtime_events WHERE id = ?", eventID).Scan(&payload).Error
+	if err != nil {
+		return nil, err
+	}
+	if !payload.Valid {
+		return nil, fmt.Errorf("realtime event %d not found", eventID)
+	}
+	return []byte(payload.String), nil
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+	"treesindia/database"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Characters used in referral codes (no 0/O or 1/I to avoid typos)
+const referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// ReferralService handles referral codes and rewards
+type ReferralService struct {
+	db                 *gorm.DB
+	referralRepo       *repositories.ReferralRepository
+	userRepo           *repositories.UserRepository
+	bookingRepo        *repositories.BookingRepository
+	walletService      *UnifiedWalletService
+	adminConfigService *AdminConfigService
+}
+
+// NewReferralService creates a new referral service
+func NewReferralService() *ReferralService {
+	return &ReferralService{
+		db:                 database.GetDB(),
+		referralRepo:       repositories.NewReferralRepository(),
+		userRepo:           repositories.NewUserRepository(),
+		bookingRepo:        repositories.NewBookingRepository(),
+		walletService:      NewUnifiedWalletService(),
+		adminConfigService: NewAdminConfigService(),
+	}
+}
+
+// GetReferralSummary gets the user's referral code, creating it on first use, and their earnings
+func (rs *ReferralService) GetReferralSummary(userID uint) (*models.ReferralSummary, error) {
+	code, err := rs.getOrCreateReferralCode(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := rs.referralRepo.GetReferrerSummary(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get referral summary: %v", err)
+	}
+	summary.Code = code
+
+	return summary, nil
+}
+
+// ApplyReferralCode links a new customer to the user who referred them
+func (rs *ReferralService) ApplyReferralCode(userID uint, code string) (*models.Referral, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	referrer, err := rs.referralRepo.GetUserByReferralCode(code)
+	if err != nil {
+		return nil, errors.New("invalid referral code")
+	}
+	if referrer.ID == userID {
+		return nil, errors.New("you cannot use your own referral code")
+	}
+
+	if _, err := rs.referralRepo.GetByRefereeID(userID); err == nil {
+		return nil, errors.New("a referral code has already been applied")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing referral: %v", err)
+	}
+
+	// Referrals are for new customers only
+	paid, err := rs.bookingRepo.CountPaidBookingsByUser(userID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check previous bookings: %v", err)
+	}
+	if paid > 0 {
+		return nil, errors.New("referral codes can only be applied before your first booking")
+	}
+
+	referral := &models.Referral{
+		ReferrerID: referrer.ID,
+		RefereeID:  userID,
+		Code:       code,
+		Status:     models.ReferralStatusPending,
+	}
+	if err := rs.referralRepo.Create(referral); err != nil {
+		return nil, fmt.Errorf("failed to apply referral code: %v", err)
+	}
+
+	logrus.Infof("User %d applied referral code %s of user %d", userID, code, referrer.ID)
+	return referral, nil
+}
+
+// RewardFirstCompletedBooking credits both wallets when a referred user completes their first
+// booking. The referral is claimed and both wallets credited in one transaction; if a credit fails
+// the referral stays pending and is rewarded on the referee's next completed booking instead.
+func (rs *ReferralService) RewardFirstCompletedBooking(booking *models.Booking) {
+	referral, err := rs.referralRepo.GetByRefereeID(booking.UserID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logrus.Errorf("Failed to get referral for user %d: %v", booking.UserID, err)
+		}
+		return
+	}
+	if referral.Status != models.ReferralStatusPending {
+		return
+	}
+
+	now := time.Now()
+	referral.RewardBookingID = &booking.ID
+	referral.ReferrerReward = rs.adminConfigService.GetReferralReferrerReward()
+	referral.RefereeReward = rs.adminConfigService.GetReferralRefereeReward()
+	referral.RewardedAt = &now
+
+	claimed := false
+	err = rs.db.Transaction(func(tx *gorm.DB) error {
+		// Claim the referral first so concurrent completions can't pay twice
+		var err error
+		claimed, err = rs.referralRepo.MarkRewarded(tx, referral)
+		if err != nil {
+			return fmt.Errorf("failed to mark referral rewarded: %v", err)
+		}
+		if !claimed {
+			return nil
+		}
+
+		if referral.ReferrerReward > 0 {
+			_, err := rs.walletService.CreditReferralRewardWithTx(tx, referral.ReferrerID, referral.ReferrerReward, referral.ID,
+				"Referral reward: your friend completed their first booking")
+			if err != nil {
+				return fmt.Errorf("failed to credit referrer reward: %v", err)
+			}
+		}
+		if referral.RefereeReward > 0 {
+			_, err := rs.walletService.CreditReferralRewardWithTx(tx, referral.RefereeID, referral.RefereeReward, referral.ID,
+				"Referral reward: first booking completed")
+			if err != nil {
+				return fmt.Errorf("failed to credit referee reward: %v", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("Failed to reward referral %d on booking %d: %v", referral.ID, booking.ID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	logrus.Infof("Referral %d rewarded on booking %d (referrer ₹%.2f, referee ₹%.2f)",
+		referral.ID, booking.ID, referral.ReferrerReward, referral.RefereeReward)
+}
+
+// GetReferrals gets referrals for admins
+func (rs *ReferralService) GetReferrals(status models.ReferralStatus, page, limit int) ([]models.Referral, *repositories.Pagination, error) {
+	return rs.referralRepo.GetReferrals(status, page, limit)
+}
+
+// getOrCreateReferralCode gets the user's referral code, generating a unique one if needed
+func (rs *ReferralService) getOrCreateReferralCode(userID uint) (string, error) {
+	var user models.User
+	if err := rs.userRepo.FindByID(&user, userID); err != nil {
+		return "", errors.New("user not found")
+	}
+	if user.ReferralCode != nil {
+		return *user.ReferralCode, nil
+	}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		code, err := generateReferralCode()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate referral code: %v", err)
+		}
+
+		// A clash with another user's code fails the unique index; try again
+		if err := rs.referralRepo.SetUserReferralCode(userID, code); err != nil {
+			logrus.Warnf("Referral code %s could not be assigned to user %d: %v", code, userID, err)
+			continue
+		}
+
+		// Re-read in case a concurrent request assigned a code first
+		if err := rs.userRepo.FindByID(&user, userID); err != nil {
+			return "", errors.New("user not found")
+		}
+		if user.ReferralCode != nil {
+			return *user.ReferralCode, nil
+		}
+	}
+
+	return "", errors.New("failed to generate referral code")
+}
+
+// generateReferralCode generates a random 8 character referral code
+func generateReferralCode() (string, error) {
+	code := make([]byte, 8)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(referralCodeAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		code[i] = referralCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
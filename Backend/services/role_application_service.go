@@ -2,6 +2,8 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 	"treesindia/database"
 	"treesindia/models"
@@ -15,6 +17,7 @@ type RoleApplicationService struct {
 	applicationRepo     *repositories.RoleApplicationRepository
 	userRepo            *repositories.UserRepository
 	subscriptionRepo    *repositories.UserSubscriptionRepository
+	documentService     *UserDocumentService
 	
 	db                  *gorm.DB
 }
@@ -28,6 +31,7 @@ func NewRoleApplicationService(
 		applicationRepo:  applicationRepo,
 		userRepo:         userRepo,
 		subscriptionRepo: repositories.NewUserSubscriptionRepository(),
+		documentService:  NewUserDocumentService(repositories.NewUserDocumentRepository(), nil),
 		db:               database.GetDB(),
 	}
 }
@@ -245,6 +249,11 @@ func (s *RoleApplicationService) SubmitWorkerApplication(userID uint, workerData
 		return nil, err
 	}
 
+	// Record each document for review
+	if err := s.documentService.SyncApplicationDocuments(userID, workerData.Documents); err != nil {
+		logrus.Errorf("Failed to record application documents for user %d: %v", userID, err)
+	}
+
 	// Send notification to admins about new worker application
 	var user models.User
 	if err := s.db.First(&user, userID).Error; err == nil {
@@ -447,6 +456,11 @@ func (s *RoleApplicationService) SubmitBrokerApplication(userID uint, brokerData
 		return nil, err
 	}
 
+	// Record each document for review
+	if err := s.documentService.SyncApplicationDocuments(userID, brokerData.Documents); err != nil {
+		logrus.Errorf("Failed to record application documents for user %d: %v", userID, err)
+	}
+
 	// Send notification to admins about new broker application
 	var user models.User
 	if err := s.db.First(&user, userID).Error; err == nil {
@@ -501,6 +515,24 @@ func (s *RoleApplicationService) UpdateApplication(id uint, adminID uint, status
 		return nil, err
 	}
 
+	// Every mandatory document must be verified before the application can be approved
+	if status == models.ApplicationStatusApproved {
+		if _, err := s.documentService.GetApplicationDocuments(application); err != nil {
+			return nil, err
+		}
+		unverified, err := s.documentService.GetUnverifiedMandatoryDocuments(application.UserID, application.RequestedRole)
+		if err != nil {
+			return nil, err
+		}
+		if len(unverified) > 0 {
+			names := make([]string, len(unverified))
+			for i, documentType := range unverified {
+				names[i] = string(documentType)
+			}
+			return nil, fmt.Errorf("documents not verified: %s", strings.Join(names, ", "))
+		}
+	}
+
 	application.Status = status
 
 	now := time.Now()
@@ -590,6 +622,44 @@ func (s *RoleApplicationService) UpdateApplication(id uint, adminID uint, status
 	return application, nil
 }
 
+// GetApplicationDocuments gets the documents of an application with their review state
+func (s *RoleApplicationService) GetApplicationDocuments(id uint) ([]models.UserDocument, error) {
+	application, err := s.applicationRepo.GetApplicationByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.documentService.GetApplicationDocuments(application)
+}
+
+// GetUserApplicationDocuments gets the documents of a user's own application with their review state
+func (s *RoleApplicationService) GetUserApplicationDocuments(userID uint) ([]models.UserDocument, error) {
+	application, err := s.applicationRepo.GetApplicationByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetApplicationDocuments(application.ID)
+}
+
+// ReviewApplicationDocument verifies or rejects one document of an application (admin only)
+func (s *RoleApplicationService) ReviewApplicationDocument(applicationID, documentID, adminID uint, req *models.ReviewUserDocumentRequest) (*models.UserDocument, error) {
+	application, err := s.applicationRepo.GetApplicationByID(applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := s.documentService.GetDocument(documentID)
+	if err != nil || document.UserID != application.UserID {
+		return nil, errors.New("document not found")
+	}
+
+	return s.documentService.ReviewDocument(documentID, adminID, req)
+}
+
+// ReuploadApplicationDocument replaces a rejected or expired document of a user's application
+func (s *RoleApplicationService) ReuploadApplicationDocument(userID uint, documentType models.DocumentType, fileURL, fileName string, fileSize int64) (*models.UserDocument, error) {
+	return s.documentService.ReuploadDocument(userID, documentType, fileURL, fileName, fileSize)
+}
+
 // GetPendingApplications gets all pending applications
 func (s *RoleApplicationService) GetPendingApplications() ([]models.RoleApplication, error) {
 	return s.applicationRepo.GetPendingApplications()
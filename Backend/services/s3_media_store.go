@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+	"treesindia/config"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/sirupsen/logrus"
+)
+
+// S3MediaStore stores media in an S3-compatible bucket such as AWS S3 or MinIO
+type S3MediaStore struct {
+	keyedUploads
+	client  *minio.Client
+	bucket  string
+	baseURL string
+}
+
+// NewS3MediaStore creates a media store backed by the configured S3 bucket, creating the bucket if needed
+func NewS3MediaStore(appConfig *config.AppConfig) (*S3MediaStore, error) {
+	if appConfig.S3AccessKey == "" || appConfig.S3SecretKey == "" {
+		return nil, fmt.Errorf("S3_ACCESS_KEY and S3_SECRET_KEY must be set for S3 media storage")
+	}
+
+	client, err := minio.New(appConfig.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(appConfig.S3AccessKey, appConfig.S3SecretKey, ""),
+		Secure: appConfig.S3UseSSL,
+		Region: appConfig.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize S3 client: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	exists, err := client.BucketExists(ctx, appConfig.S3Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check S3 bucket %s: %v", appConfig.S3Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, appConfig.S3Bucket, minio.MakeBucketOptions{Region: appConfig.S3Region}); err != nil {
+			return nil, fmt.Errorf("failed to create S3 bucket %s: %v", appConfig.S3Bucket, err)
+		}
+		logrus.Infof("Created S3 bucket %s", appConfig.S3Bucket)
+	}
+
+	baseURL := appConfig.S3PublicBaseURL
+	if baseURL == "" {
+		scheme := "http"
+		if appConfig.S3UseSSL {
+			scheme = "https"
+		}
+		baseURL = fmt.Sprintf("%s://%s/%s", scheme, appConfig.S3Endpoint, appConfig.S3Bucket)
+	}
+
+	store := &S3MediaStore{
+		client:  client,
+		bucket:  appConfig.S3Bucket,
+		baseURL: baseURL,
+	}
+	store.keyedUploads = keyedUploads{put: store.put, publicURL: store.PublicURL}
+
+	logrus.Infof("S3 media store initialized for bucket %s at %s", appConfig.S3Bucket, appConfig.S3Endpoint)
+	return store, nil
+}
+
+// Backend returns the name of the storage backend
+func (ss *S3MediaStore) Backend() string {
+	return MediaBackendS3
+}
+
+// put uploads an object to the bucket
+func (ss *S3MediaStore) put(ctx context.Context, r io.Reader, size int64, key, contentType string) error {
+	_, err := ss.client.PutObject(ctx, ss.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+// Delete deletes media by its URL
+func (ss *S3MediaStore) Delete(mediaURL string) error {
+	key := ss.KeyFromURL(mediaURL)
+	if key == "" {
+		return fmt.Errorf("media URL is not from the S3 store: %s", mediaURL)
+	}
+
+	if err := ss.client.RemoveObject(context.Background(), ss.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete media: %v", err)
+	}
+
+	logrus.Infof("Media deleted successfully: %s", key)
+	return nil
+}
+
+// KeyFromURL returns the object key of a media URL
+func (ss *S3MediaStore) KeyFromURL(mediaURL string) string {
+	return keyFromBaseURL(ss.baseURL, mediaURL)
+}
+
+// PublicURL returns the public URL of an object key
+func (ss *S3MediaStore) PublicURL(key string) string {
+	return ss.baseURL + "/" + key
+}
+
+// SignedURL returns a presigned GET URL for media
+func (ss *S3MediaStore) SignedURL(mediaURL string, expiry time.Duration) (string, error) {
+	key := ss.KeyFromURL(mediaURL)
+	if key == "" {
+		return "", fmt.Errorf("media URL is not from the S3 store: %s", mediaURL)
+	}
+
+	signed, err := ss.client.PresignedGetObject(context.Background(), ss.bucket, key, expiry, url.Values{})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign media URL: %v", err)
+	}
+	return signed.String(), nil
+}
+
+// ListMedia calls fn for every object in the bucket
+func (ss *S3MediaStore) ListMedia(fn func(MediaObject) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for object := range ss.client.ListObjects(ctx, ss.bucket, minio.ListObjectsOptions{Recursive: true}) {
+		if object.Err != nil {
+			return fmt.Errorf("failed to list media: %v", object.Err)
+		}
+
+		err := fn(MediaObject{
+			Key:          object.Key,
+			URL:          ss.PublicURL(object.Key),
+			Size:         object.Size,
+			LastModified: object.LastModified,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
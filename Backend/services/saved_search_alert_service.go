@@ -0,0 +1,242 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SavedSearchAlertService alerts users when a newly approved property matches one of their saved
+// searches: instant searches straight away, daily searches in one digest a day
+type SavedSearchAlertService struct {
+	savedSearchRepo             *repositories.SavedSearchRepository
+	propertyRepo                *repositories.PropertyRepository
+	userRepo                    *repositories.UserRepository
+	enhancedNotificationService *EnhancedNotificationService
+	emailService                *EmailService
+	stopChan                    chan bool
+}
+
+func NewSavedSearchAlertService(enhancedNotificationService *EnhancedNotificationService) *SavedSearchAlertService {
+	return &SavedSearchAlertService{
+		savedSearchRepo:             repositories.NewSavedSearchRepository(),
+		propertyRepo:                repositories.NewPropertyRepository(),
+		userRepo:                    repositories.NewUserRepository(),
+		enhancedNotificationService: enhancedNotificationService,
+		emailService:                NewEmailService(),
+		stopChan:                    make(chan bool),
+	}
+}
+
+// Start begins the periodic sending of daily digests
+func (ssas *SavedSearchAlertService) Start() {
+	logrus.Info("SavedSearchAlertService starting...")
+
+	// Run immediately on start
+	go ssas.sendDailyDigests()
+
+	// Then run every hour so each search gets its digest about a day after the last one
+	ticker := time.NewTicker(1 * time.Hour)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				ssas.sendDailyDigests()
+			case <-ssas.stopChan:
+				ticker.Stop()
+				logrus.Info("SavedSearchAlertService stopped")
+				return
+			}
+		}
+	}()
+
+	logrus.Info("SavedSearchAlertService started successfully")
+}
+
+// Stop stops the saved search alert service
+func (ssas *SavedSearchAlertService) Stop() {
+	logrus.Info("Stopping SavedSearchAlertService...")
+	ssas.stopChan <- true
+}
+
+// NotifyNewListing records the saved searches a newly approved property matches and alerts the
+// owners of instant searches. Searches of the listing's own owner are skipped. The saved searches are
+// loaded once and matched in memory.
+func (ssas *SavedSearchAlertService) NotifyNewListing(property *models.Property) {
+	if !property.IsApproved || property.Status != models.PropertyStatusAvailable {
+		return
+	}
+
+	ownerID := propertyOwnerID(property)
+	searches, err := ssas.savedSearchRepo.GetAlertingSearches(ownerID)
+	if err != nil {
+		logrus.Errorf("SavedSearchAlertService: failed to get saved searches: %v", err)
+		return
+	}
+
+	matched := 0
+	for i := range searches {
+		search := &searches[i]
+		if search.UserID == property.UserID {
+			continue
+		}
+
+		if !search.Filters.Matches(property) {
+			continue
+		}
+
+		match := &models.SavedSearchMatch{
+			SavedSearchID: search.ID,
+			PropertyID:    property.ID,
+		}
+		created, err := ssas.savedSearchRepo.CreateMatch(match)
+		if err != nil {
+			logrus.Errorf("SavedSearchAlertService: failed to record match of saved search %d: %v", search.ID, err)
+			continue
+		}
+		if !created {
+			continue
+		}
+		matched++
+
+		if search.AlertFrequency != models.AlertFrequencyInstant {
+			continue
+		}
+		ssas.sendAlert(search, []models.Property{*property})
+		if err := ssas.savedSearchRepo.MarkMatchesNotified([]uint{match.ID}, time.Now()); err != nil {
+			logrus.Errorf("SavedSearchAlertService: failed to mark match %d notified: %v", match.ID, err)
+		}
+	}
+
+	if matched > 0 {
+		logrus.Infof("SavedSearchAlertService: property %d matched %d saved searches", property.ID, matched)
+	}
+}
+
+// sendDailyDigests alerts the owner of each daily search about the listings it matched since its
+// last digest
+func (ssas *SavedSearchAlertService) sendDailyDigests() {
+	now := time.Now()
+	searches, err := ssas.savedSearchRepo.GetSearchesDueDigest(now.Add(-24 * time.Hour))
+	if err != nil {
+		logrus.Errorf("SavedSearchAlertService: failed to get saved searches due a digest: %v", err)
+		return
+	}
+
+	sent := 0
+	for i := range searches {
+		search := &searches[i]
+		matches, err := ssas.savedSearchRepo.GetPendingMatches(search.ID)
+		if err != nil {
+			logrus.Errorf("SavedSearchAlertService: failed to get matches of saved search %d: %v", search.ID, err)
+			continue
+		}
+
+		var properties []models.Property
+		matchIDs := make([]uint, 0, len(matches))
+		for _, match := range matches {
+			matchIDs = append(matchIDs, match.ID)
+			// Listings deleted, sold or rented since they matched are left out of the digest
+			if match.Property != nil && match.Property.Status == models.PropertyStatusAvailable {
+				properties = append(properties, *match.Property)
+			}
+		}
+
+		if len(properties) > 0 {
+			ssas.sendAlert(search, properties)
+			sent++
+		}
+		if err := ssas.savedSearchRepo.MarkMatchesNotified(matchIDs, now); err != nil {
+			logrus.Errorf("SavedSearchAlertService: failed to mark matches of saved search %d notified: %v", search.ID, err)
+			continue
+		}
+		if err := ssas.savedSearchRepo.UpdateLastDigestAt(search.ID, now); err != nil {
+			logrus.Errorf("SavedSearchAlertService: failed to update digest time of saved search %d: %v", search.ID, err)
+		}
+	}
+
+	if sent > 0 {
+		logrus.Infof("SavedSearchAlertService: sent %d daily digests", sent)
+	}
+}
+
+// sendAlert alerts the owner of a saved search about matching listings on the channels they chose
+func (ssas *SavedSearchAlertService) sendAlert(search *models.SavedSearch, properties []models.Property) {
+	title := fmt.Sprintf("New listing for \"%s\"", search.Name)
+	body := fmt.Sprintf("%s in %s matches your saved search.", properties[0].Title, properties[0].City)
+	if len(properties) > 1 {
+		title = fmt.Sprintf("%d new listings for \"%s\"", len(properties), search.Name)
+		body = fmt.Sprintf("%s and %d more listings match your saved search.", properties[0].Title, len(properties)-1)
+	}
+
+	if search.NotifyPush && ssas.enhancedNotificationService != nil {
+		go func() {
+			_, err := ssas.enhancedNotificationService.SendNotification(&NotificationRequest{
+				UserID: search.UserID,
+				Type:   models.NotificationTypeSystem,
+				Title:  title,
+				Body:   body,
+				Data: map[string]string{
+					"type":          "saved_search_alert",
+					"savedSearchId": fmt.Sprintf("%d", search.ID),
+					"propertyId":    fmt.Sprintf("%d", properties[0].ID),
+					"count":         fmt.Sprintf("%d", len(properties)),
+				},
+				Priority: "normal",
+			})
+			if err != nil {
+				logrus.Errorf("SavedSearchAlertService: failed to notify user %d: %v", search.UserID, err)
+			}
+		}()
+	}
+
+	if search.NotifyEmail {
+		var user models.User
+		if err := ssas.userRepo.FindByID(&user, search.UserID); err != nil {
+			logrus.Errorf("SavedSearchAlertService: failed to get user %d: %v", search.UserID, err)
+			return
+		}
+		if user.Email == nil || *user.Email == "" {
+			return
+		}
+
+		email := *user.Email
+		emailBody := savedSearchAlertEmail(search, properties)
+		go func() {
+			if err := ssas.emailService.SendEmail(email, title+" - TREESINDIA", emailBody); err != nil {
+				logrus.Errorf("SavedSearchAlertService: failed to email user %d: %v", search.UserID, err)
+			}
+		}()
+	}
+}
+
+// savedSearchAlertEmail builds the HTML body of a saved search alert email
+func savedSearchAlertEmail(search *models.SavedSearch, properties []models.Property) string {
+	var rows strings.Builder
+	for _, property := range properties {
+		price := "Price on request"
+		if property.SalePrice != nil {
+			price = fmt.Sprintf("₹%.0f", *property.SalePrice)
+		} else if property.MonthlyRent != nil {
+			price = fmt.Sprintf("₹%.0f / month", *property.MonthlyRent)
+		}
+		rows.WriteString(fmt.Sprintf("<li><strong>%s</strong><br>%s, %s<br>%s</li>",
+			html.EscapeString(property.Title), html.EscapeString(property.City), html.EscapeString(property.State), price))
+	}
+
+	return fmt.Sprintf(`
+		<html>
+		<body>
+			<h2>New listings for your saved search "%s"</h2>
+			<ul>%s</ul>
+			<p>Open the TREESINDIA app to view these listings or change your alert settings.</p>
+			<p>Best regards,<br>TREESINDIA Team</p>
+		</body>
+		</html>
+	`, html.EscapeString(search.Name), rows.String())
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"treesindia/models"
+	"treesindia/repositories"
+	"treesindia/utils"
+)
+
+// maxSavedSearchesPerUser limits how many searches a user can save
+const maxSavedSearchesPerUser = 20
+
+type SavedSearchService struct {
+	savedSearchRepo *repositories.SavedSearchRepository
+	propertyRepo    *repositories.PropertyRepository
+}
+
+func NewSavedSearchService() *SavedSearchService {
+	return &SavedSearchService{
+		savedSearchRepo: repositories.NewSavedSearchRepository(),
+		propertyRepo:    repositories.NewPropertyRepository(),
+	}
+}
+
+// CreateSavedSearch saves a property search for a user
+func (sss *SavedSearchService) CreateSavedSearch(userID uint, req *models.CreateSavedSearchRequest) (*models.SavedSearch, error) {
+	count, err := sss.savedSearchRepo.CountByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count saved searches: %v", err)
+	}
+	if count >= maxSavedSearchesPerUser {
+		return nil, fmt.Errorf("you can save at most %d searches", maxSavedSearchesPerUser)
+	}
+	if err := validateSavedSearchFilters(&req.Filters); err != nil {
+		return nil, err
+	}
+
+	search := &models.SavedSearch{
+		UserID:         userID,
+		Name:           req.Name,
+		Filters:        req.Filters,
+		AlertFrequency: models.AlertFrequencyInstant,
+		NotifyPush:     true,
+	}
+	if req.AlertFrequency != "" {
+		search.AlertFrequency = req.AlertFrequency
+	}
+	if req.NotifyPush != nil {
+		search.NotifyPush = *req.NotifyPush
+	}
+	if req.NotifyEmail != nil {
+		search.NotifyEmail = *req.NotifyEmail
+	}
+
+	if err := sss.savedSearchRepo.Create(search); err != nil {
+		return nil, fmt.Errorf("failed to save search: %v", err)
+	}
+	return search, nil
+}
+
+// GetSavedSearches gets a user's saved searches
+func (sss *SavedSearchService) GetSavedSearches(userID uint) ([]models.SavedSearch, error) {
+	return sss.savedSearchRepo.GetByUser(userID)
+}
+
+// GetSavedSearch gets a saved search of a user
+func (sss *SavedSearchService) GetSavedSearch(id, userID uint) (*models.SavedSearch, error) {
+	search, err := sss.savedSearchRepo.GetByID(id)
+	if err != nil || search.UserID != userID {
+		return nil, errors.New("saved search not found")
+	}
+	return search, nil
+}
+
+// UpdateSavedSearch updates the name, filters or alert settings of a saved search
+func (sss *SavedSearchService) UpdateSavedSearch(id, userID uint, req *models.UpdateSavedSearchRequest) (*models.SavedSearch, error) {
+	search, err := sss.GetSavedSearch(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		search.Name = *req.Name
+	}
+	if req.Filters != nil {
+		if err := validateSavedSearchFilters(req.Filters); err != nil {
+			return nil, err
+		}
+		search.Filters = *req.Filters
+	}
+	if req.AlertFrequency != nil {
+		search.AlertFrequency = *req.AlertFrequency
+	}
+	if req.NotifyPush != nil {
+		search.NotifyPush = *req.NotifyPush
+	}
+	if req.NotifyEmail != nil {
+		search.NotifyEmail = *req.NotifyEmail
+	}
+
+	if err := sss.savedSearchRepo.Update(search); err != nil {
+		return nil, fmt.Errorf("failed to update saved search: %v", err)
+	}
+	return search, nil
+}
+
+// DeleteSavedSearch deletes a saved search of a user
+func (sss *SavedSearchService) DeleteSavedSearch(id, userID uint) error {
+	if _, err := sss.GetSavedSearch(id, userID); err != nil {
+		return err
+	}
+	if err := sss.savedSearchRepo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete saved search: %v", err)
+	}
+	return nil
+}
+
+// GetSavedSearchResults runs a saved search against the current listings
+func (sss *SavedSearchService) GetSavedSearchResults(id, userID uint, params utils.PaginationParams) ([]models.Property, utils.PaginationResponse, error) {
+	search, err := sss.GetSavedSearch(id, userID)
+	if err != nil {
+		return nil, utils.PaginationResponse{}, err
+	}
+	return sss.propertyRepo.GetAll(params, search.Filters.ToFilterMap(), false)
+}
+
+// validateSavedSearchFilters checks that the ranges of a saved search are consistent
+func validateSavedSearchFilters(filters *models.SavedSearchFilters) error {
+	if filters.MinPrice != nil && filters.MaxPrice != nil && *filters.MinPrice > *filters.MaxPrice {
+		return errors.New("min_price cannot be greater than max_price")
+	}
+	if filters.MinArea != nil && filters.MaxArea != nil && *filters.MinArea > *filters.MaxArea {
+		return errors.New("min_area cannot be greater than max_area")
+	}
+	if filters.Latitude != nil || filters.Longitude != nil || filters.RadiusKm != nil {
+		if filters.Latitude == nil || filters.Longitude == nil || filters.RadiusKm == nil {
+			return errors.New("latitude, longitude and radius_km must be provided together")
+		}
+		if err := filters.ToFilterMap()["near"].(models.GeoRadius).Validate(); err != nil {
+			return err
+		}
+	}
+	if len(filters.ToFilterMap()) == 0 {
+		return errors.New("at least one filter is required")
+	}
+	return nil
+}
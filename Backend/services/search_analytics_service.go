@@ -0,0 +1,195 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// SearchAnalyticsService logs searches and attributes clicks and bookings to them
+type SearchAnalyticsService struct {
+	searchAnalyticsRepo *repositories.SearchAnalyticsRepository
+	adminConfigService  *AdminConfigService
+}
+
+// NewSearchAnalyticsService creates a new search analytics service
+func NewSearchAnalyticsService() *SearchAnalyticsService {
+	return &SearchAnalyticsService{
+		searchAnalyticsRepo: repositories.NewSearchAnalyticsRepository(),
+		adminConfigService:  NewAdminConfigService(),
+	}
+}
+
+// LogSearch records a search and returns its ID and the click token the searcher must send back to
+// record clicks on it. Failures are logged and return nil so that analytics never break search.
+func (sas *SearchAnalyticsService) LogSearch(userID *uint, query string, source models.SearchSource, resultCount int64) (*uint, string) {
+	normalized := normalizeSearchQuery(query)
+	if normalized == "" {
+		return nil, ""
+	}
+
+	token, err := newSearchClickToken()
+	if err != nil {
+		logrus.Warnf("SearchAnalyticsService.LogSearch failed to generate click token: %v", err)
+		return nil, ""
+	}
+
+	log := &models.SearchQueryLog{
+		UserID:          userID,
+		Query:           truncateSearchQuery(strings.TrimSpace(query)),
+		NormalizedQuery: truncateSearchQuery(normalized),
+		Source:          source,
+		ResultCount:     resultCount,
+		ClickToken:      token,
+	}
+
+	if err := sas.searchAnalyticsRepo.Create(log); err != nil {
+		logrus.Warnf("SearchAnalyticsService.LogSearch failed to log search '%s': %v", normalized, err)
+		return nil, ""
+	}
+
+	return &log.ID, token
+}
+
+// RecordClick records the result a user opened from a search. The request must carry the search's
+// click token, which only the searcher was given. A signed-in user who searched as a guest takes
+// ownership of the search so that a later booking can be attributed to it.
+func (sas *SearchAnalyticsService) RecordClick(searchID uint, userID *uint, req *models.RecordSearchClickRequest) error {
+	log, err := sas.searchAnalyticsRepo.GetByID(searchID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("search not found")
+		}
+		return err
+	}
+
+	if log.ClickToken == "" || subtle.ConstantTimeCompare([]byte(log.ClickToken), []byte(req.SearchToken)) != 1 {
+		return errors.New("invalid search token")
+	}
+
+	if log.UserID != nil && (userID == nil || *log.UserID != *userID) {
+		return errors.New("search belongs to another user")
+	}
+
+	if err := sas.searchAnalyticsRepo.RecordClick(searchID, req.EntityType, req.EntityID); err != nil {
+		return fmt.Errorf("failed to record click: %v", err)
+	}
+
+	if log.UserID == nil && userID != nil {
+		if err := sas.searchAnalyticsRepo.ClaimGuestSearch(searchID, *userID); err != nil {
+			logrus.Warnf("SearchAnalyticsService.RecordClick failed to attach search %d to user %d: %v", searchID, *userID, err)
+		}
+	}
+
+	return nil
+}
+
+// AttributeBooking credits a booking to the search the user opened its service from, if any. It is
+// called when a payment for the booking is confirmed, so only paid bookings count as conversions;
+// a booking is credited to at most one search however many payments it has.
+func (sas *SearchAnalyticsService) AttributeBooking(booking *models.Booking) {
+	if booking == nil || booking.ID == 0 || booking.UserID == 0 || booking.ServiceID == 0 {
+		return
+	}
+
+	// The window runs back from when the booking was made, not from the payment, which for
+	// quoted work can come days later
+	bookedAt := booking.CreatedAt
+	if bookedAt.IsZero() {
+		bookedAt = time.Now()
+	}
+	window := time.Duration(sas.adminConfigService.GetSearchAttributionWindowHours()) * time.Hour
+	attributed, err := sas.searchAnalyticsRepo.AttributeBooking(booking.UserID, booking.ServiceID, booking.ID, bookedAt.Add(-window), bookedAt)
+	if err != nil {
+		logrus.Warnf("SearchAnalyticsService.AttributeBooking failed for booking %d: %v", booking.ID, err)
+		return
+	}
+	if attributed > 0 {
+		logrus.Infof("Booking %d attributed to a search by user %d", booking.ID, booking.UserID)
+	}
+}
+
+// GetReport gets the search analytics report for the last given days
+func (sas *SearchAnalyticsService) GetReport(days, limit int) (*models.SearchAnalyticsReport, error) {
+	if days <= 0 {
+		days = 30
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -days)
+
+	summary, err := sas.searchAnalyticsRepo.GetSummary(from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search summary: %v", err)
+	}
+
+	topQueries, err := sas.searchAnalyticsRepo.GetTopQueries(from, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top queries: %v", err)
+	}
+
+	zeroResultQueries, err := sas.searchAnalyticsRepo.GetZeroResultQueries(from, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get zero result queries: %v", err)
+	}
+
+	return &models.SearchAnalyticsReport{
+		From:              from,
+		To:                to,
+		Summary:           *summary,
+		TopQueries:        topQueries,
+		ZeroResultQueries: zeroResultQueries,
+	}, nil
+}
+
+// GetPopularKeywords gets the most searched queries of the last 30 days that returned results and
+// were searched by enough different signed-in users to be shown publicly. One-off queries, which
+// can hold personal details or abuse, never qualify.
+func (sas *SearchAnalyticsService) GetPopularKeywords(limit int) ([]models.SearchSuggestion, error) {
+	minUsers := sas.adminConfigService.GetSearchSuggestionMinUsers()
+	stats, err := sas.searchAnalyticsRepo.GetSuggestableQueries(time.Now().AddDate(0, 0, -30), minUsers, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]models.SearchSuggestion, len(stats))
+	for i, stat := range stats {
+		suggestions[i] = models.SearchSuggestion{Keyword: stat.Query, SearchCount: int(stat.Searches)}
+	}
+	return suggestions, nil
+}
+
+// newSearchClickToken generates the random token that authorises clicks on a logged search
+func newSearchClickToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// normalizeSearchQuery lower-cases a query and collapses its whitespace
+func normalizeSearchQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// truncateSearchQuery keeps a query within the column size
+func truncateSearchQuery(query string) string {
+	runes := []rune(query)
+	if len(runes) > 255 {
+		return string(runes[:255])
+	}
+	return query
+}
@@ -1,12 +1,14 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -17,19 +19,39 @@ import (
 )
 
 type SearchService struct {
-	db           *gorm.DB
-	serviceRepo  *repositories.ServiceRepository
-	categoryRepo *repositories.CategoryRepository
+	db                     *gorm.DB
+	serviceRepo            *repositories.ServiceRepository
+	categoryRepo           *repositories.CategoryRepository
+	synonymRepo            *repositories.SearchSynonymRepository
+	adminConfigService     *AdminConfigService
+	searchAnalyticsService *SearchAnalyticsService
 }
 
 func NewSearchService() *SearchService {
 	return &SearchService{
-		db:           database.GetDB(),
-		serviceRepo:  repositories.NewServiceRepository(),
-		categoryRepo: repositories.NewCategoryRepository(),
+		db:                     database.GetDB(),
+		serviceRepo:            repositories.NewServiceRepository(),
+		categoryRepo:           repositories.NewCategoryRepository(),
+		synonymRepo:            repositories.NewSearchSynonymRepository(),
+		adminConfigService:     NewAdminConfigService(),
+		searchAnalyticsService: NewSearchAnalyticsService(),
 	}
 }
 
+// serviceScoreSQL ranks a service by its own text, its category and parent category, plus title
+// similarity so that misspelt queries still order sensibly. Expects the query bound as tsq.
+const serviceScoreSQL = `ts_rank_cd(services.search_vector, tsq)
+	+ 0.5 * ts_rank_cd(COALESCE(categories.search_vector, ''::tsvector), tsq)
+	+ 0.3 * ts_rank_cd(COALESCE(parent_cat.search_vector, ''::tsvector), tsq)
+	+ 0.5 * word_similarity(?, services.name)
+	+ 0.25 * COALESCE(word_similarity(?, categories.name), 0)`
+
+// rankedService is a service ID with its relevance score
+type rankedService struct {
+	ID    uint
+	Score float64
+}
+
 // GetSearchSuggestions returns 5 popular keywords and 5 popular services
 func (ss *SearchService) GetSearchSuggestions() (*models.SearchSuggestionsResponse, error) {
 	defer func() {
@@ -40,18 +62,24 @@ func (ss *SearchService) GetSearchSuggestions() (*models.SearchSuggestionsRespon
 
 	logrus.Info("SearchService.GetSearchSuggestions called")
 
-	// Get popular keywords (mock data for now - in real implementation, this would come from search analytics)
-	popularKeywords := []models.SearchSuggestion{
-		{Keyword: "home cleaning", SearchCount: 1250, Category: "Cleaning"},
-		{Keyword: "plumbing", SearchCount: 980, Category: "Plumbing"},
-		{Keyword: "electrician", SearchCount: 750, Category: "Electrical"},
-		{Keyword: "pest control", SearchCount: 620, Category: "Pest Control"},
-		{Keyword: "carpenter", SearchCount: 580, Category: "Carpentry"},
+	// Get popular keywords from search analytics, falling back to defaults until there is enough history
+	popularKeywords, err := ss.searchAnalyticsService.GetPopularKeywords(5)
+	if err != nil {
+		logrus.Warnf("SearchService.GetSearchSuggestions error getting popular keywords: %v", err)
+	}
+	if len(popularKeywords) == 0 {
+		popularKeywords = []models.SearchSuggestion{
+			{Keyword: "home cleaning", SearchCount: 1250, Category: "Cleaning"},
+			{Keyword: "plumbing", SearchCount: 980, Category: "Plumbing"},
+			{Keyword: "electrician", SearchCount: 750, Category: "Electrical"},
+			{Keyword: "pest control", SearchCount: 620, Category: "Pest Control"},
+			{Keyword: "carpenter", SearchCount: 580, Category: "Carpentry"},
+		}
 	}
 
 	// Get popular services (most booked + highest rated)
 	var services []models.Service
-	err := ss.db.Preload("Category").Preload("Category.Parent").Preload("Category.Parent.Parent").
+	err = ss.db.Preload("Category").Preload("Category.Parent").Preload("Category.Parent.Parent").
 		Where("services.is_active = ?", true).
 		Order("services.created_at DESC").
 		Limit(5).
@@ -73,8 +101,9 @@ func (ss *SearchService) GetSearchSuggestions() (*models.SearchSuggestionsRespon
 	return response, nil
 }
 
-// SearchServices performs intelligent search based on query
-func (ss *SearchService) SearchServices(query string, page, limit int) (*models.SearchResponse, error) {
+// SearchServices performs intelligent search based on query. The first page of each search is logged
+// for analytics, and its ID is returned in the metadata so clicks and bookings can be attributed to it.
+func (ss *SearchService) SearchServices(query string, page, limit int, userID *uint) (*models.SearchResponse, error) {
 	defer func() {
 		if r := recover(); r != nil {
 			logrus.Errorf("SearchService.SearchServices panic: %v", r)
@@ -164,6 +193,10 @@ func (ss *SearchService) SearchServices(query string, page, limit int) (*models.
 		metadata.FilterApplied = strings.Join(filterParts, ", ")
 	}
 
+	if page == 1 {
+		metadata.SearchID, metadata.SearchToken = ss.searchAnalyticsService.LogSearch(userID, query, models.SearchSourceServices, total)
+	}
+
 	response := &models.SearchResponse{
 		QueryAnalysis:  queryAnalysis,
 		Results:        results,
@@ -517,129 +550,200 @@ func (ss *SearchService) searchByServiceType(filters models.SearchFilters, page,
 	return results, total, nil
 }
 
-// searchByKeywords searches services by keywords
+// searchByKeywords searches services by keywords, ranked in SQL by full-text rank and trigram similarity.
+// Any price and service type filters are applied too, so it also serves combined queries.
 func (ss *SearchService) searchByKeywords(filters models.SearchFilters, page, limit int) ([]models.SearchResult, int64, error) {
 	offset := (page - 1) * limit
 
-	query := ss.db.Model(&models.Service{}).
-		Preload("Category").Preload("Category.Parent").Preload("Category.Parent.Parent").
-		Preload("ServiceAreas").
-		Where("services.is_active = ?", true)
+	expandedTerms := ss.expandSynonyms(filters.Keywords)
+	tsQuery := buildTSQuery(filters.Keywords, expandedTerms)
+	fuzzyText := strings.Join(filters.Keywords, " ")
 
-	// Apply keyword filters
-	if len(filters.Keywords) > 0 {
-		// Add joins once outside the loop to avoid duplicate table references
-		query = query.Joins("LEFT JOIN categories ON services.category_id = categories.id").
-			Joins("LEFT JOIN categories parent_cat ON categories.parent_id = parent_cat.id")
-
-		// Build OR conditions for all keywords
-		var keywordConditions []string
-		var keywordArgs []interface{}
-
-		for _, keyword := range filters.Keywords {
-			keyword = strings.TrimSpace(keyword)
-			if keyword != "" {
-				keywordConditions = append(keywordConditions,
-					"(services.name ILIKE ? OR services.description ILIKE ? OR categories.name ILIKE ? OR parent_cat.name ILIKE ?)")
-				keywordArgs = append(keywordArgs, "%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%")
-			}
+	var ranked []rankedService
+	var total int64
+
+	err := ss.withFuzzyThreshold(func(tx *gorm.DB) error {
+		query := tx.Model(&models.Service{}).
+			Joins("LEFT JOIN categories ON services.category_id = categories.id").
+			Joins("LEFT JOIN categories parent_cat ON categories.parent_id = parent_cat.id").
+			Where("services.is_active = ?", true)
+
+		// Apply price filters
+		if filters.PriceMax != nil {
+			query = query.Where("services.price <= ?", *filters.PriceMax)
+		}
+		if filters.PriceMin != nil {
+			query = query.Where("services.price >= ?", *filters.PriceMin)
 		}
 
-		if len(keywordConditions) > 0 {
-			query = query.Where(strings.Join(keywordConditions, " OR "), keywordArgs...)
+		// Apply service type filter
+		if filters.PriceType != nil {
+			query = query.Where("services.price_type = ?", *filters.PriceType)
 		}
-	}
 
-	// Apply location filter
-	query = ss.applyLocationFilter(query, filters)
+		// Apply keyword match: full-text on the service and its categories, or a similar word in the names
+		scoreSQL := "1.0"
+		var scoreArgs []interface{}
+		if fuzzyText != "" {
+			query = query.Joins("CROSS JOIN to_tsquery('english', ?) AS tsq", tsQuery).
+				Where("(services.search_vector @@ tsq OR categories.search_vector @@ tsq OR parent_cat.search_vector @@ tsq OR ? <% services.name OR ? <% categories.name)",
+					fuzzyText, fuzzyText)
+			scoreSQL = serviceScoreSQL
+			scoreArgs = []interface{}{fuzzyText, fuzzyText}
+		}
 
-	// Get total count
-	var total int64
-	query.Count(&total)
+		// Apply location filter
+		query = ss.applyLocationFilter(query, filters)
 
-	// Get results
-	var services []models.Service
-	err := query.Order("services.name ASC").
-		Offset(offset).
-		Limit(limit).
-		Find(&services).Error
+		if err := query.Count(&total).Error; err != nil {
+			return err
+		}
 
+		return query.Select("services.id, ("+scoreSQL+") AS score", scoreArgs...).
+			Order("score DESC, services.name ASC").
+			Offset(offset).
+			Limit(limit).
+			Scan(&ranked).Error
+	})
 	if err != nil {
 		return nil, 0, err
 	}
 
+	if len(ranked) == 0 {
+		return []models.SearchResult{}, total, nil
+	}
+
+	// Load the page of services with their relations, keeping the SQL ranking order
+	ids := make([]uint, len(ranked))
+	scores := make(map[uint]float64, len(ranked))
+	for i, r := range ranked {
+		ids[i] = r.ID
+		scores[r.ID] = r.Score
+	}
+
+	var loaded []models.Service
+	err = ss.db.Preload("Category").Preload("Category.Parent").Preload("Category.Parent.Parent").
+		Preload("ServiceAreas").
+		Where("id IN ?", ids).
+		Find(&loaded).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	byID := make(map[uint]models.Service, len(loaded))
+	for _, service := range loaded {
+		byID[service.ID] = service
+	}
+	services := make([]models.Service, 0, len(ranked))
+	for _, id := range ids {
+		if service, ok := byID[id]; ok {
+			services = append(services, service)
+		}
+	}
+
 	// Convert to search results with relevance scoring
-	results := ss.convertToSearchResultsWithRelevance(services, filters.Keywords)
+	results := ss.convertToSearchResultsWithRelevance(services, scores, slices.Concat(filters.Keywords, expandedTerms))
 	return results, total, nil
 }
 
 // searchCombined searches services with combined filters
 func (ss *SearchService) searchCombined(filters models.SearchFilters, page, limit int) ([]models.SearchResult, int64, error) {
-	offset := (page - 1) * limit
+	return ss.searchByKeywords(filters, page, limit)
+}
 
-	query := ss.db.Model(&models.Service{}).
-		Preload("Category").Preload("Category.Parent").Preload("Category.Parent.Parent").
-		Preload("ServiceAreas").
-		Where("services.is_active = ?", true)
+// withFuzzyThreshold runs fn in a transaction whose trigram word similarity threshold comes from admin config
+func (ss *SearchService) withFuzzyThreshold(fn func(tx *gorm.DB) error) error {
+	threshold := strconv.FormatFloat(ss.adminConfigService.GetSearchFuzzyThreshold(), 'f', 2, 64)
 
-	// Apply price filters
-	if filters.PriceMax != nil {
-		query = query.Where("services.price <= ?", *filters.PriceMax)
-	}
-	if filters.PriceMin != nil {
-		query = query.Where("services.price >= ?", *filters.PriceMin)
+	return ss.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)", threshold).Error; err != nil {
+			return fmt.Errorf("failed to set fuzzy search threshold: %v", err)
+		}
+		return fn(tx)
+	})
+}
+
+// expandSynonyms returns the synonym phrases for terms found in the keywords. Synonyms work both
+// ways: "ac" adds "air conditioner" and "air conditioner" adds "ac".
+func (ss *SearchService) expandSynonyms(keywords []string) []string {
+	if len(keywords) == 0 {
+		return nil
 	}
 
-	// Apply service type filter
-	if filters.PriceType != nil {
-		query = query.Where("services.price_type = ?", *filters.PriceType)
+	synonyms, err := ss.synonymRepo.GetActive()
+	if err != nil {
+		logrus.Warnf("SearchService.expandSynonyms failed to load synonyms: %v", err)
+		return nil
 	}
 
-	// Apply keyword filters
-	if len(filters.Keywords) > 0 {
-		// Add joins once outside the loop to avoid duplicate table references
-		query = query.Joins("LEFT JOIN categories ON services.category_id = categories.id").
-			Joins("LEFT JOIN categories parent_cat ON categories.parent_id = parent_cat.id")
+	phrase := " " + strings.ToLower(strings.Join(keywords, " ")) + " "
+	seen := make(map[string]bool)
+	for _, keyword := range keywords {
+		seen[strings.ToLower(keyword)] = true
+	}
 
-		// Build OR conditions for all keywords
-		var keywordConditions []string
-		var keywordArgs []interface{}
+	var expanded []string
+	add := func(term string) {
+		if !seen[term] {
+			seen[term] = true
+			expanded = append(expanded, term)
+		}
+	}
 
-		for _, keyword := range filters.Keywords {
-			keyword = strings.TrimSpace(keyword)
-			if keyword != "" {
-				keywordConditions = append(keywordConditions,
-					"(services.name ILIKE ? OR services.description ILIKE ? OR categories.name ILIKE ? OR parent_cat.name ILIKE ?)")
-				keywordArgs = append(keywordArgs, "%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%")
+	for _, synonym := range synonyms {
+		if strings.Contains(phrase, " "+synonym.Term+" ") {
+			for _, alternative := range synonym.Synonyms {
+				add(alternative)
 			}
+			continue
 		}
-
-		if len(keywordConditions) > 0 {
-			query = query.Where(strings.Join(keywordConditions, " OR "), keywordArgs...)
+		for _, alternative := range synonym.Synonyms {
+			if strings.Contains(phrase, " "+alternative+" ") {
+				add(synonym.Term)
+				for _, other := range synonym.Synonyms {
+					add(other)
+				}
+				break
+			}
 		}
 	}
 
-	// Apply location filter
-	query = ss.applyLocationFilter(query, filters)
+	return expanded
+}
 
-	// Get total count
-	var total int64
-	query.Count(&total)
+// buildTSQuery builds a to_tsquery expression that matches any keyword by prefix, or any synonym phrase
+func buildTSQuery(keywords, phrases []string) string {
+	var parts []string
 
-	// Get results
-	var services []models.Service
-	err := query.Order("services.name ASC").
-		Offset(offset).
-		Limit(limit).
-		Find(&services).Error
+	for _, keyword := range keywords {
+		if word := sanitizeSearchWord(keyword); word != "" {
+			parts = append(parts, word+":*")
+		}
+	}
 
-	if err != nil {
-		return nil, 0, err
+	for _, phrase := range phrases {
+		var words []string
+		for _, word := range strings.Fields(phrase) {
+			if word = sanitizeSearchWord(word); word != "" {
+				words = append(words, word)
+			}
+		}
+		if len(words) > 0 {
+			parts = append(parts, "("+strings.Join(words, " & ")+")")
+		}
 	}
 
-	// Convert to search results with relevance scoring
-	results := ss.convertToSearchResultsWithRelevance(services, filters.Keywords)
-	return results, total, nil
+	return strings.Join(parts, " | ")
+}
+
+// sanitizeSearchWord keeps only letters and digits so user input cannot break tsquery syntax
+func sanitizeSearchWord(word string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, word)
 }
 
 // convertToSearchResults converts services to search results
@@ -685,13 +789,12 @@ func (ss *SearchService) convertToSearchResults(services []models.Service, match
 	return results
 }
 
-// convertToSearchResultsWithRelevance converts services to search results with relevance scoring
-func (ss *SearchService) convertToSearchResultsWithRelevance(services []models.Service, keywords []string) []models.SearchResult {
+// convertToSearchResultsWithRelevance converts ranked services to search results, keeping their order
+func (ss *SearchService) convertToSearchResultsWithRelevance(services []models.Service, scores map[uint]float64, keywords []string) []models.SearchResult {
 	results := make([]models.SearchResult, len(services))
 
 	for i, service := range services {
-		// Calculate relevance score
-		score := ss.calculateRelevanceScore(service, keywords)
+		score := scores[service.ID]
 
 		// Extract service area city names
 		serviceAreas := make([]string, 0, len(service.ServiceAreas))
@@ -727,55 +830,9 @@ func (ss *SearchService) convertToSearchResultsWithRelevance(services []models.S
 		results[i].HighlightedDesc = ss.highlightKeywords(service.Description, keywords)
 	}
 
-	// Sort by relevance score
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].MatchScore > results[j].MatchScore
-	})
-
 	return results
 }
 
-// calculateRelevanceScore calculates relevance score for a service
-func (ss *SearchService) calculateRelevanceScore(service models.Service, keywords []string) float64 {
-	score := 0.0
-	
-	for _, keyword := range keywords {
-		keyword = strings.ToLower(keyword)
-		
-		// Exact match in name (highest score)
-		if strings.Contains(strings.ToLower(service.Name), keyword) {
-			score += 100.0
-		}
-		
-		// Partial match in name
-		if strings.Contains(strings.ToLower(service.Name), keyword) {
-			score += 80.0
-		}
-		
-		// Category match
-		if strings.Contains(strings.ToLower(service.Category.Name), keyword) {
-			score += 60.0
-		}
-		
-		// Parent category match (if category has a parent)
-		if service.Category.Parent != nil && strings.Contains(strings.ToLower(service.Category.Parent.Name), keyword) {
-			score += 60.0
-		}
-		
-		// Description match
-		if strings.Contains(strings.ToLower(service.Description), keyword) {
-			score += 40.0
-		}
-	}
-	
-	// Normalize score
-	if len(keywords) > 0 {
-		score = score / float64(len(keywords))
-	}
-	
-	return score
-}
-
 // highlightKeywords highlights matching keywords in text
 func (ss *SearchService) highlightKeywords(text string, keywords []string) string {
 	highlighted := text
@@ -791,3 +848,260 @@ func (ss *SearchService) highlightKeywords(text string, keywords []string) strin
 	
 	return highlighted
 }
+
+// federatedHitsSQL ranks active services, live properties, projects and active vendors against one query.
+// Each branch matches on its full-text vector or a similar word in its title.
+const federatedHitsSQL = `WITH tsq AS (SELECT to_tsquery('english', @tsquery) AS q),
+hits AS (
+	SELECT 'service' AS type, s.id, s.name AS title, COALESCE(c.name, '') AS subtitle, s.slug,
+		COALESCE(s.images[1], '') AS image, s.price,
+		ts_rank_cd(s.search_vector, tsq.q) + 0.5 * ts_rank_cd(COALESCE(c.search_vector, ''::tsvector), tsq.q) + 0.5 * word_similarity(@fuzzy, s.name) AS score,
+		s.created_at
+	FROM services s
+	CROSS JOIN tsq
+	LEFT JOIN categories c ON c.id = s.category_id
+	WHERE s.deleted_at IS NULL AND s.is_active = TRUE
+		AND (s.search_vector @@ tsq.q OR c.search_vector @@ tsq.q OR @fuzzy <% s.name)
+		AND (@city = '' OR EXISTS (
+			SELECT 1 FROM service_service_areas ssa
+			JOIN service_areas sa ON sa.id = ssa.service_area_id
+			WHERE ssa.service_id = s.id AND sa.is_active = TRUE AND LOWER(sa.city) = LOWER(@city)
+		))
+
+	UNION ALL
+
+	SELECT 'property', p.id, p.title, p.city, p.slug,
+		COALESCE(p.images->>0, ''), COALESCE(p.sale_price, p.monthly_rent),
+		ts_rank_cd(p.search_vector, tsq.q) + 0.5 * word_similarity(@fuzzy, p.title),
+		p.created_at
+	FROM properties p
+	CROSS JOIN tsq
+	WHERE p.deleted_at IS NULL AND p.is_approved = TRUE AND p.status = 'available'
+		AND (p.search_vector @@ tsq.q OR @fuzzy <% p.title)
+		AND (@city = '' OR LOWER(p.city) = LOWER(@city))
+
+	UNION ALL
+
+	SELECT 'project', pr.id, pr.title, pr.city, pr.slug,
+		COALESCE(pr.images->>0, ''), NULL,
+		ts_rank_cd(pr.search_vector, tsq.q) + 0.5 * word_similarity(@fuzzy, pr.title),
+		pr.created_at
+	FROM projects pr
+	CROSS JOIN tsq
+	WHERE pr.deleted_at IS NULL
+		AND (pr.search_vector @@ tsq.q OR @fuzzy <% pr.title)
+		AND (@city = '' OR LOWER(pr.city) = LOWER(@city))
+
+	UNION ALL
+
+	SELECT 'vendor', v.id, v.vendor_name, COALESCE(v.business_address->>'city', ''), '',
+		COALESCE(v.profile_picture, ''), NULL,
+		ts_rank_cd(v.search_vector, tsq.q) + 0.5 * word_similarity(@fuzzy, v.vendor_name),
+		v.created_at
+	FROM vendors v
+	CROSS JOIN tsq
+	WHERE v.deleted_at IS NULL AND v.is_active = TRUE
+		AND (v.search_vector @@ tsq.q OR @fuzzy <% v.vendor_name)
+		AND (@city = '' OR LOWER(v.business_address->>'city') = LOWER(@city))
+)
+`
+
+// ErrInvalidSearch is wrapped by the errors returned for search requests that fail validation
+var ErrInvalidSearch = errors.New("invalid search")
+
+// FederatedSearch searches services, properties, projects and vendors at once, ranked in SQL,
+// with a per-type count of matches for faceting
+func (ss *SearchService) FederatedSearch(filters *models.FederatedSearchFilters, userID *uint) (*models.FederatedSearchResponse, error) {
+	startTime := time.Now()
+
+	if filters.Page <= 0 {
+		filters.Page = 1
+	}
+	if filters.Limit <= 0 {
+		filters.Limit = 20
+	}
+	if filters.Limit > 100 {
+		filters.Limit = 100
+	}
+
+	keywords := strings.Fields(strings.ToLower(strings.TrimSpace(filters.Query)))
+	if len(keywords) == 0 {
+		return nil, fmt.Errorf("%w: search query is required", ErrInvalidSearch)
+	}
+
+	types := make([]string, 0, len(models.SearchEntityTypes))
+	for _, entityType := range models.SearchEntityTypes {
+		if len(filters.Types) == 0 || slices.Contains(filters.Types, entityType) {
+			types = append(types, string(entityType))
+		}
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("%w: no valid search types", ErrInvalidSearch)
+	}
+
+	expandedTerms := ss.expandSynonyms(keywords)
+	args := map[string]interface{}{
+		"tsquery": buildTSQuery(keywords, expandedTerms),
+		"fuzzy":   strings.Join(keywords, " "),
+		"city":    strings.TrimSpace(filters.City),
+		"types":   types,
+		"limit":   filters.Limit,
+		"offset":  (filters.Page - 1) * filters.Limit,
+	}
+
+	var facetRows []models.SearchFacet
+	var hits []models.FederatedSearchHit
+
+	err := ss.withFuzzyThreshold(func(tx *gorm.DB) error {
+		err := tx.Raw(federatedHitsSQL+"SELECT type, COUNT(*) AS count FROM hits GROUP BY type", args).
+			Scan(&facetRows).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Raw(federatedHitsSQL+"SELECT * FROM hits WHERE type IN @types ORDER BY score DESC, created_at DESC LIMIT @limit OFFSET @offset", args).
+			Scan(&hits).Error
+	})
+	if err != nil {
+		logrus.Errorf("SearchService.FederatedSearch error: %v", err)
+		return nil, err
+	}
+
+	// Report every type in the facets, and total only the requested ones
+	counts := make(map[models.SearchEntityType]int64, len(facetRows))
+	for _, facet := range facetRows {
+		counts[facet.Type] = facet.Count
+	}
+	facets := make([]models.SearchFacet, 0, len(models.SearchEntityTypes))
+	var total int64
+	for _, entityType := range models.SearchEntityTypes {
+		facets = append(facets, models.SearchFacet{Type: entityType, Count: counts[entityType]})
+		if slices.Contains(types, string(entityType)) {
+			total += counts[entityType]
+		}
+	}
+
+	if hits == nil {
+		hits = []models.FederatedSearchHit{}
+	}
+
+	totalPages := int((total + int64(filters.Limit) - 1) / int64(filters.Limit))
+
+	metadata := models.SearchMetadata{
+		Query:        filters.Query,
+		SearchTimeMs: time.Since(startTime).Milliseconds(),
+		TotalResults: total,
+	}
+	if filters.Page == 1 {
+		metadata.SearchID, metadata.SearchToken = ss.searchAnalyticsService.LogSearch(userID, filters.Query, models.SearchSourceFederated, total)
+	}
+
+	return &models.FederatedSearchResponse{
+		Query:         filters.Query,
+		ExpandedTerms: expandedTerms,
+		Results:       hits,
+		Facets:        facets,
+		Pagination: models.PaginationInfo{
+			Page:       filters.Page,
+			Limit:      filters.Limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    int64(filters.Page*filters.Limit) < total,
+			HasPrev:    filters.Page > 1,
+		},
+		SearchMetadata: metadata,
+	}, nil
+}
+
+// GetSynonyms gets search synonyms for the admin panel
+func (ss *SearchService) GetSynonyms(search string, page, limit int) ([]models.SearchSynonym, *repositories.Pagination, error) {
+	return ss.synonymRepo.GetSynonyms(search, page, limit)
+}
+
+// CreateSynonym creates a search synonym
+func (ss *SearchService) CreateSynonym(req *models.CreateSearchSynonymRequest) (*models.SearchSynonym, error) {
+	term := normalizeSearchQuery(req.Term)
+	if term == "" {
+		return nil, errors.New("term is required")
+	}
+
+	synonyms := normalizeSynonymList(term, req.Synonyms)
+	if len(synonyms) == 0 {
+		return nil, errors.New("at least one synonym different from the term is required")
+	}
+
+	if _, err := ss.synonymRepo.GetByTerm(term); err == nil {
+		return nil, errors.New("synonyms for this term already exist")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing synonyms: %v", err)
+	}
+
+	synonym := &models.SearchSynonym{
+		Term:     term,
+		Synonyms: synonyms,
+		IsActive: true,
+	}
+	if req.IsActive != nil {
+		synonym.IsActive = *req.IsActive
+	}
+
+	if err := ss.synonymRepo.Create(synonym); err != nil {
+		return nil, fmt.Errorf("failed to create synonym: %v", err)
+	}
+
+	return synonym, nil
+}
+
+// UpdateSynonym updates a search synonym
+func (ss *SearchService) UpdateSynonym(id uint, req *models.UpdateSearchSynonymRequest) (*models.SearchSynonym, error) {
+	synonym, err := ss.synonymRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("synonym not found")
+		}
+		return nil, err
+	}
+
+	if req.Synonyms != nil {
+		synonyms := normalizeSynonymList(synonym.Term, req.Synonyms)
+		if len(synonyms) == 0 {
+			return nil, errors.New("at least one synonym different from the term is required")
+		}
+		synonym.Synonyms = synonyms
+	}
+	if req.IsActive != nil {
+		synonym.IsActive = *req.IsActive
+	}
+
+	if err := ss.synonymRepo.Update(synonym); err != nil {
+		return nil, fmt.Errorf("failed to update synonym: %v", err)
+	}
+
+	return synonym, nil
+}
+
+// DeleteSynonym deletes a search synonym
+func (ss *SearchService) DeleteSynonym(id uint) error {
+	if _, err := ss.synonymRepo.GetByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("synonym not found")
+		}
+		return err
+	}
+	return ss.synonymRepo.Delete(id)
+}
+
+// normalizeSynonymList normalizes synonyms, dropping blanks, duplicates and the term itself
+func normalizeSynonymList(term string, synonyms []string) []string {
+	seen := map[string]bool{term: true}
+	result := make([]string, 0, len(synonyms))
+	for _, synonym := range synonyms {
+		synonym = normalizeSearchQuery(synonym)
+		if synonym == "" || seen[synonym] {
+			continue
+		}
+		seen[synonym] = true
+		result = append(result, synonym)
+	}
+	return result
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ServiceOptionService manages the variants and add-ons of services
+type ServiceOptionService struct {
+	serviceRepo       *repositories.ServiceRepository
+	serviceOptionRepo *repositories.ServiceOptionRepository
+	pricingService    *PricingService
+}
+
+// NewServiceOptionService creates a new service option service
+func NewServiceOptionService() *ServiceOptionService {
+	return &ServiceOptionService{
+		serviceRepo:       repositories.NewServiceRepository(),
+		serviceOptionRepo: repositories.NewServiceOptionRepository(),
+		pricingService:    NewPricingService(),
+	}
+}
+
+// GetVariants gets the variants of a service
+func (sos *ServiceOptionService) GetVariants(serviceID uint, activeOnly bool) ([]models.ServiceVariant, error) {
+	if _, err := sos.getFixedPriceService(serviceID); err != nil {
+		return nil, err
+	}
+	return sos.serviceOptionRepo.GetVariants(serviceID, activeOnly)
+}
+
+// CreateVariant adds a variant to a fixed price service
+func (sos *ServiceOptionService) CreateVariant(serviceID uint, req *models.CreateServiceVariantRequest) (*models.ServiceVariant, error) {
+	if _, err := sos.getFixedPriceService(serviceID); err != nil {
+		return nil, err
+	}
+
+	variant := &models.ServiceVariant{
+		ServiceID:   serviceID,
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       roundAmount(req.Price),
+		Duration:    req.Duration,
+		MaxQuantity: 1,
+		SortOrder:   req.SortOrder,
+		IsActive:    true,
+	}
+	if req.MaxQuantity > 0 {
+		variant.MaxQuantity = req.MaxQuantity
+	}
+	if req.IsActive != nil {
+		variant.IsActive = *req.IsActive
+	}
+
+	if err := sos.serviceOptionRepo.CreateVariant(variant); err != nil {
+		return nil, fmt.Errorf("failed to create service variant: %v", err)
+	}
+	return variant, nil
+}
+
+// UpdateVariant updates a variant of a service
+func (sos *ServiceOptionService) UpdateVariant(serviceID, variantID uint, req *models.UpdateServiceVariantRequest) (*models.ServiceVariant, error) {
+	variant, err := sos.serviceOptionRepo.GetVariantByID(serviceID, variantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("service variant not found")
+		}
+		return nil, err
+	}
+
+	if req.Name != "" {
+		variant.Name = req.Name
+	}
+	if req.Description != nil {
+		variant.Description = *req.Description
+	}
+	if req.Price != nil {
+		variant.Price = roundAmount(*req.Price)
+	}
+	if req.Duration != nil {
+		variant.Duration = req.Duration
+		if *req.Duration == "" {
+			variant.Duration = nil
+		}
+	}
+	if req.MaxQuantity != nil {
+		variant.MaxQuantity = *req.MaxQuantity
+	}
+	if req.SortOrder != nil {
+		variant.SortOrder = *req.SortOrder
+	}
+	if req.IsActive != nil {
+		variant.IsActive = *req.IsActive
+	}
+
+	if err := sos.serviceOptionRepo.UpdateVariant(variant); err != nil {
+		return nil, fmt.Errorf("failed to update service variant: %v", err)
+	}
+	return variant, nil
+}
+
+// DeleteVariant deletes a variant of a service. Bookings keep their snapshot of it.
+func (sos *ServiceOptionService) DeleteVariant(serviceID, variantID uint) error {
+	if err := sos.serviceOptionRepo.DeleteVariant(serviceID, variantID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("service variant not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// GetAddOns gets the add-ons of a service
+func (sos *ServiceOptionService) GetAddOns(serviceID uint, activeOnly bool) ([]models.ServiceAddOn, error) {
+	if _, err := sos.getFixedPriceService(serviceID); err != nil {
+		return nil, err
+	}
+	return sos.serviceOptionRepo.GetAddOns(serviceID, activeOnly)
+}
+
+// CreateAddOn adds an add-on to a fixed price service
+func (sos *ServiceOptionService) CreateAddOn(serviceID uint, req *models.CreateServiceAddOnRequest) (*models.ServiceAddOn, error) {
+	if _, err := sos.getFixedPriceService(serviceID); err != nil {
+		return nil, err
+	}
+
+	addOn := &models.ServiceAddOn{
+		ServiceID:   serviceID,
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       roundAmount(req.Price),
+		Duration:    req.Duration,
+		MaxQuantity: 1,
+		SortOrder:   req.SortOrder,
+		IsActive:    true,
+	}
+	if req.MaxQuantity > 0 {
+		addOn.MaxQuantity = req.MaxQuantity
+	}
+	if req.IsActive != nil {
+		addOn.IsActive = *req.IsActive
+	}
+
+	if err := sos.serviceOptionRepo.CreateAddOn(addOn); err != nil {
+		return nil, fmt.Errorf("failed to create service add-on: %v", err)
+	}
+	return addOn, nil
+}
+
+// UpdateAddOn updates an add-on of a service
+func (sos *ServiceOptionService) UpdateAddOn(serviceID, addOnID uint, req *models.UpdateServiceAddOnRequest) (*models.ServiceAddOn, error) {
+	addOn, err := sos.serviceOptionRepo.GetAddOnByID(serviceID, addOnID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("service add-on not found")
+		}
+		return nil, err
+	}
+
+	if req.Name != "" {
+		addOn.Name = req.Name
+	}
+	if req.Description != nil {
+		addOn.Description = *req.Description
+	}
+	if req.Price != nil {
+		addOn.Price = roundAmount(*req.Price)
+	}
+	if req.Duration != nil {
+		addOn.Duration = req.Duration
+		if *req.Duration == "" {
+			addOn.Duration = nil
+		}
+	}
+	if req.MaxQuantity != nil {
+		addOn.MaxQuantity = *req.MaxQuantity
+	}
+	if req.SortOrder != nil {
+		addOn.SortOrder = *req.SortOrder
+	}
+	if req.IsActive != nil {
+		addOn.IsActive = *req.IsActive
+	}
+
+	if err := sos.serviceOptionRepo.UpdateAddOn(addOn); err != nil {
+		return nil, fmt.Errorf("failed to update service add-on: %v", err)
+	}
+	return addOn, nil
+}
+
+// DeleteAddOn deletes an add-on of a service. Bookings keep their snapshot of it.
+func (sos *ServiceOptionService) DeleteAddOn(serviceID, addOnID uint) error {
+	if err := sos.serviceOptionRepo.DeleteAddOn(serviceID, addOnID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("service add-on not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// CalculatePrice prices a variant, quantity and add-ons of a service before booking
+func (sos *ServiceOptionService) CalculatePrice(serviceID uint, selection *models.ServiceSelection) (*models.ServicePriceBreakdown, error) {
+	service, err := sos.serviceRepo.GetByID(serviceID)
+	if err != nil {
+		return nil, errors.New("service not found")
+	}
+	if !service.IsActive {
+		return nil, errors.New("service is not active")
+	}
+	return sos.pricingService.Calculate(service, selection)
+}
+
+// getFixedPriceService gets a service that can have variants and add-ons
+func (sos *ServiceOptionService) getFixedPriceService(serviceID uint) (*models.Service, error) {
+	service, err := sos.serviceRepo.GetByID(serviceID)
+	if err != nil {
+		return nil, errors.New("service not found")
+	}
+	if service.PriceType != "fixed" {
+		return nil, errors.New("only fixed price services have variants and add-ons")
+	}
+	return service, nil
+}
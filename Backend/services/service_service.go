@@ -16,14 +16,16 @@ import (
 type ServiceService struct {
 	serviceRepo     *repositories.ServiceRepository
 	serviceAreaRepo *repositories.ServiceAreaRepository
-	cloudinary      *CloudinaryService
+	serviceOptionRepo *repositories.ServiceOptionRepository
+	mediaStore      MediaStore
 }
 
-func NewServiceService(serviceRepo *repositories.ServiceRepository, cloudinary *CloudinaryService) *ServiceService {
+func NewServiceService(serviceRepo *repositories.ServiceRepository, mediaStore MediaStore) *ServiceService {
 	return &ServiceService{
 		serviceRepo:     serviceRepo,
 		serviceAreaRepo: repositories.NewServiceAreaRepository(),
-		cloudinary:      cloudinary,
+		serviceOptionRepo: repositories.NewServiceOptionRepository(),
+		mediaStore:      mediaStore,
 	}
 }
 
@@ -88,13 +90,13 @@ func (ss *ServiceService) CreateService(req *models.CreateServiceRequest, imageF
 	slug := utils.GenerateSlug(req.Name)
 	logrus.Infof("ServiceService.CreateService generated slug: %s", slug)
 
-	// Upload images to Cloudinary
+	// Upload images to media storage
 	var imageURLs []string
-	if ss.cloudinary != nil {
+	if ss.mediaStore != nil {
 		for _, file := range imageFiles {
 			if file != nil {
 				logrus.Infof("ServiceService.CreateService uploading image: %s", file.Filename)
-				url, err := ss.cloudinary.UploadImage(file, "services")
+				url, err := ss.mediaStore.UploadImage(file, "services")
 				if err != nil {
 					logrus.Errorf("ServiceService.CreateService image upload error: %v", err)
 					return nil, err
@@ -104,7 +106,7 @@ func (ss *ServiceService) CreateService(req *models.CreateServiceRequest, imageF
 			}
 		}
 	} else {
-		logrus.Warn("ServiceService.CreateService cloudinary service is nil, skipping image upload")
+		logrus.Warn("ServiceService.CreateService media store is nil, skipping image upload")
 	}
 
 	// Set default is_active
@@ -122,6 +124,11 @@ func (ss *ServiceService) CreateService(req *models.CreateServiceRequest, imageF
 		logrus.Infof("ServiceService.CreateService rounded price from %v to %v", *req.Price, *roundedPrice)
 	}
 
+	maxQuantity := 1
+	if req.MaxQuantity > 0 {
+		maxQuantity = req.MaxQuantity
+	}
+
 	service := &models.Service{
 		Name:        req.Name,
 		Slug:        slug,
@@ -130,6 +137,7 @@ func (ss *ServiceService) CreateService(req *models.CreateServiceRequest, imageF
 		PriceType:   req.PriceType,
 		Price:       roundedPrice,
 		Duration:    req.Duration,
+		MaxQuantity: maxQuantity,
 		CategoryID:  req.CategoryID,
 		IsActive:    isActive,
 	}
@@ -261,6 +269,16 @@ func (ss *ServiceService) GetServiceByID(id uint) (*models.Service, error) {
 		return nil, err
 	}
 	
+	// Include the variants and add-ons customers can choose from
+	if service.PriceType == "fixed" {
+		if service.Variants, err = ss.serviceOptionRepo.GetVariants(service.ID, true); err != nil {
+			logrus.Errorf("ServiceService.GetServiceByID failed to get variants: %v", err)
+		}
+		if service.AddOns, err = ss.serviceOptionRepo.GetAddOns(service.ID, true); err != nil {
+			logrus.Errorf("ServiceService.GetServiceByID failed to get add-ons: %v", err)
+		}
+	}
+	
 	logrus.Infof("ServiceService.GetServiceByID found service: %s", service.Name)
 	return service, nil
 }
@@ -431,6 +449,9 @@ func (ss *ServiceService) UpdateService(id uint, req *models.UpdateServiceReques
 	if req.Duration != nil {
 		service.Duration = req.Duration
 	}
+	if req.MaxQuantity != nil {
+		service.MaxQuantity = *req.MaxQuantity
+	}
 	// SubcategoryID removed - services now use single CategoryID
 	if req.IsActive != nil {
 		service.IsActive = *req.IsActive
@@ -442,7 +463,7 @@ func (ss *ServiceService) UpdateService(id uint, req *models.UpdateServiceReques
 		var newImageURLs []string
 		for _, file := range imageFiles {
 			if file != nil {
-				url, err := ss.cloudinary.UploadImage(file, "services")
+				url, err := ss.mediaStore.UploadImage(file, "services")
 				if err != nil {
 					return nil, err
 				}
@@ -638,15 +659,15 @@ func (ss *ServiceService) DeleteServiceImage(serviceID uint, imageURL string) er
 		return err
 	}
 	
-	// Delete the image from Cloudinary
-	if ss.cloudinary != nil {
-		publicID := ss.cloudinary.GetPublicIDFromURL(imageURL)
+	// Delete the image from media storage
+	if ss.mediaStore != nil {
+		publicID := ss.mediaStore.KeyFromURL(imageURL)
 		if publicID != "" {
-			if deleteErr := ss.cloudinary.DeleteImage(publicID); deleteErr != nil {
-				logrus.Warnf("ServiceService.DeleteServiceImage failed to delete image from Cloudinary: %v", deleteErr)
+			if deleteErr := ss.mediaStore.Delete(imageURL); deleteErr != nil {
+				logrus.Warnf("ServiceService.DeleteServiceImage failed to delete image from media storage: %v", deleteErr)
 				// Don't return error as the database update was successful
 			} else {
-				logrus.Infof("ServiceService.DeleteServiceImage successfully deleted image from Cloudinary: %s", publicID)
+				logrus.Infof("ServiceService.DeleteServiceImage successfully deleted image from media storage: %s", publicID)
 			}
 		}
 	}
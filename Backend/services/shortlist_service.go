@@ -0,0 +1,112 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"treesindia/models"
+	"treesindia/repositories"
+	"treesindia/utils"
+)
+
+type ShortlistService struct {
+	shortlistRepo *repositories.ShortlistRepository
+	propertyRepo  *repositories.PropertyRepository
+	projectRepo   *repositories.ProjectRepository
+}
+
+func NewShortlistService() *ShortlistService {
+	return &ShortlistService{
+		shortlistRepo: repositories.NewShortlistRepository(),
+		propertyRepo:  repositories.NewPropertyRepository(),
+		projectRepo:   repositories.NewProjectRepository(),
+	}
+}
+
+// AddToShortlist shortlists a property or project for a user
+func (ss *ShortlistService) AddToShortlist(userID uint, req *models.AddToShortlistRequest) (*models.Shortlist, error) {
+	item := &models.Shortlist{
+		UserID:   userID,
+		ItemType: req.ItemType,
+		ItemID:   req.ItemID,
+	}
+
+	switch req.ItemType {
+	case models.ShortlistItemTypeProperty:
+		property, err := ss.propertyRepo.GetByID(req.ItemID)
+		if err != nil || !property.IsPublic() {
+			return nil, errors.New("property not found")
+		}
+		item.Property = property
+	case models.ShortlistItemTypeProject:
+		project, err := ss.projectRepo.GetByID(req.ItemID)
+		if err != nil {
+			return nil, errors.New("project not found")
+		}
+		item.Project = project
+	default:
+		return nil, fmt.Errorf("invalid item type: %s", req.ItemType)
+	}
+
+	if err := ss.shortlistRepo.Add(item); err != nil {
+		return nil, fmt.Errorf("failed to add to shortlist: %v", err)
+	}
+	return item, nil
+}
+
+// RemoveFromShortlist removes a property or project from a user's shortlist
+func (ss *ShortlistService) RemoveFromShortlist(userID uint, itemType models.ShortlistItemType, itemID uint) error {
+	removed, err := ss.shortlistRepo.Remove(userID, itemType, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to remove from shortlist: %v", err)
+	}
+	if !removed {
+		return errors.New("shortlist item not found")
+	}
+	return nil
+}
+
+// GetShortlist gets a user's shortlisted properties and projects. Items whose listing has since
+// been deleted, or is no longer shown publicly, are returned without it.
+func (ss *ShortlistService) GetShortlist(userID uint, itemType string, params utils.PaginationParams) ([]models.Shortlist, utils.PaginationResponse, error) {
+	items, pagination, err := ss.shortlistRepo.GetByUser(userID, itemType, params)
+	if err != nil {
+		return nil, utils.PaginationResponse{}, err
+	}
+
+	var propertyIDs, projectIDs []uint
+	for _, item := range items {
+		if item.ItemType == models.ShortlistItemTypeProperty {
+			propertyIDs = append(propertyIDs, item.ItemID)
+		} else {
+			projectIDs = append(projectIDs, item.ItemID)
+		}
+	}
+
+	properties, err := ss.propertyRepo.GetPublicByIDs(propertyIDs)
+	if err != nil {
+		return nil, utils.PaginationResponse{}, err
+	}
+	projects, err := ss.projectRepo.GetByIDs(projectIDs)
+	if err != nil {
+		return nil, utils.PaginationResponse{}, err
+	}
+
+	propertiesByID := make(map[uint]*models.Property, len(properties))
+	for i := range properties {
+		propertiesByID[properties[i].ID] = &properties[i]
+	}
+	projectsByID := make(map[uint]*models.Project, len(projects))
+	for i := range projects {
+		projectsByID[projects[i].ID] = &projects[i]
+	}
+
+	for i := range items {
+		if items[i].ItemType == models.ShortlistItemTypeProperty {
+			items[i].Property = propertiesByID[items[i].ItemID]
+		} else {
+			items[i].Project = projectsByID[items[i].ItemID]
+		}
+	}
+
+	return items, pagination, nil
+}
@@ -16,7 +16,7 @@ type SimpleConversationService struct {
 	messageRepo      *repositories.SimpleConversationMessageRepository
 	userRepo         *repositories.UserRepository
 	wsService        *SimpleConversationWebSocketService
-	cloudinaryService *CloudinaryService
+	mediaStore MediaStore
 }
 
 func NewSimpleConversationService(
@@ -24,14 +24,14 @@ func NewSimpleConversationService(
 	messageRepo *repositories.SimpleConversationMessageRepository,
 	userRepo *repositories.UserRepository,
 	wsService *SimpleConversationWebSocketService,
-	cloudinaryService *CloudinaryService,
+	mediaStore MediaStore,
 ) *SimpleConversationService {
 	return &SimpleConversationService{
 		conversationRepo: conversationRepo,
 		messageRepo:      messageRepo,
 		userRepo:         userRepo,
 		wsService:        wsService,
-		cloudinaryService: cloudinaryService,
+		mediaStore: mediaStore,
 	}
 }
 
@@ -263,8 +263,8 @@ func (s *SimpleConversationService) SendMessage(senderID uint, conversationID ui
 	var cloudinaryPublicID *string
 	
 	if req.AttachmentFile != nil {
-		if s.cloudinaryService == nil {
-			return nil, errors.New("cloudinary service is not available")
+		if s.mediaStore == nil {
+			return nil, errors.New("media store is not available")
 		}
 		
 		// Determine media type from file's Content-Type header (more reliable than file extension)
@@ -274,22 +274,24 @@ func (s *SimpleConversationService) SendMessage(senderID uint, conversationID ui
 		if mediaType == "image" {
 			atType := "image"
 			attachmentType = &atType
-			url, publicID, uploadErr := s.cloudinaryService.UploadChatImage(req.AttachmentFile, "chat/images")
+			url, uploadErr := s.mediaStore.UploadImage(req.AttachmentFile, "chat/images")
 			if uploadErr != nil {
 				logrus.Errorf("SimpleConversationService.SendMessage failed to upload image: %v", uploadErr)
 				return nil, fmt.Errorf("failed to upload image: %v", uploadErr)
 			}
 			imageURL = &url
+			publicID := s.mediaStore.KeyFromURL(url)
 			cloudinaryPublicID = &publicID
 		} else if mediaType == "video" {
 			atType := "video"
 			attachmentType = &atType
-			url, publicID, uploadErr := s.cloudinaryService.UploadChatVideo(req.AttachmentFile, "chat/videos")
+			url, uploadErr := s.mediaStore.UploadVideo(req.AttachmentFile, "chat/videos")
 			if uploadErr != nil {
 				logrus.Errorf("SimpleConversationService.SendMessage failed to upload video: %v", uploadErr)
 				return nil, fmt.Errorf("failed to upload video: %v", uploadErr)
 			}
 			videoURL = &url
+			publicID := s.mediaStore.KeyFromURL(url)
 			cloudinaryPublicID = &publicID
 		} else {
 			return nil, fmt.Errorf("invalid file type: %s. Only JPEG, PNG, WebP images and MP4, WebM, AVI videos are allowed", fileContentType)
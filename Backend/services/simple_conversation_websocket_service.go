@@ -5,6 +5,7 @@ import (
 	"log"
 	"sync"
 	"time"
+	"treesindia/metrics"
 
 	"github.com/gorilla/websocket"
 )
@@ -27,6 +28,8 @@ type SimpleConversationWebSocketService struct {
 	register chan SimpleConversationClient
 	// Channel for unregistering clients
 	unregister chan SimpleConversationClient
+	// Fans broadcasts out to services running on other instances
+	pubSub RealtimePubSub
 }
 
 // SimpleConversationClient represents a WebSocket client
@@ -43,16 +46,120 @@ type SimpleConversationMessage struct {
 	Event          string                 `json:"event"`
 }
 
+// Kinds of simple conversation broadcasts fanned out through pub/sub
+const (
+	simpleConversationEventConversation                   = "conversation"
+	simpleConversationEventTotalUnreadCount               = "total_unread_count"
+	simpleConversationEventConversationUnreadCount        = "conversation_unread_count"
+	simpleConversationEventUserMonitors                   = "user_monitors"
+	simpleConversationEventUserMonitorsTotalUnread        = "user_monitors_total_unread_count"
+	simpleConversationEventUserMonitorsConversationUnread = "user_monitors_conversation_unread_count"
+	simpleConversationEventAdmins                         = "admins"
+)
+
+// simpleConversationEnvelope wraps a broadcast published to other instances
+type simpleConversationEnvelope struct {
+	Origin         string                 `json:"origin"`
+	Kind           string                 `json:"kind"`
+	ConversationID uint                   `json:"conversation_id,omitempty"`
+	Event          string                 `json:"event,omitempty"`
+	Count          int                    `json:"count,omitempty"`
+	Data           map[string]interface{} `json:"data,omitempty"`
+}
+
 // NewSimpleConversationWebSocketService creates a new WebSocket service
-func NewSimpleConversationWebSocketService() *SimpleConversationWebSocketService {
-	return &SimpleConversationWebSocketService{
+func NewSimpleConversationWebSocketService(pubSub RealtimePubSub) *SimpleConversationWebSocketService {
+	service := &SimpleConversationWebSocketService{
 		conversationClients:    make(map[uint]map[*websocket.Conn]bool),
 		userConnections:        make(map[uint][]*websocket.Conn),
 		adminConnections:       make(map[uint]*websocket.Conn),
 		userMonitorConnections: make(map[uint]*websocket.Conn),
-		broadcast:              make(chan SimpleConversationMessage),
-		register:            make(chan SimpleConversationClient),
-		unregister:          make(chan SimpleConversationClient),
+		broadcast:              make(chan SimpleConversationMessage, realtimeBroadcastBuffer),
+		register:               make(chan SimpleConversationClient),
+		unregister:             make(chan SimpleConversationClient),
+		pubSub:                 pubSub,
+	}
+
+	if pubSub != nil {
+		if err := pubSub.Subscribe(RealtimeChannelSimpleConversation, service.handleRemoteEvent); err != nil {
+			log.Printf("Error subscribing simple conversation service to realtime pub/sub: %v", err)
+		}
+	}
+
+	metrics.RegisterWebSocketHub("simple_conversation", service.ConnectionCount)
+
+	return service
+}
+
+// ConnectionCount returns the number of websocket connections open on this instance
+func (s *SimpleConversationWebSocketService) ConnectionCount() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	count := len(s.adminConnections) + len(s.userMonitorConnections)
+	for _, clients := range s.conversationClients {
+		count += len(clients)
+	}
+	return count
+}
+
+// publish sends a locally originated broadcast to services on other instances
+func (s *SimpleConversationWebSocketService) publish(envelope simpleConversationEnvelope) {
+	if s.pubSub == nil {
+		return
+	}
+
+	envelope.Origin = s.pubSub.InstanceID()
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		log.Printf("Error marshaling simple conversation event for pub/sub: %v", err)
+		return
+	}
+
+	if err := s.pubSub.Publish(RealtimeChannelSimpleConversation, data); err != nil {
+		log.Printf("Error publishing simple conversation event %s: %v", envelope.Kind, err)
+	}
+}
+
+// handleRemoteEvent delivers a broadcast published by another instance to local clients
+func (s *SimpleConversationWebSocketService) handleRemoteEvent(payload []byte) {
+	var envelope simpleConversationEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		log.Printf("Error unmarshaling simple conversation event from pub/sub: %v", err)
+		return
+	}
+
+	// Broadcasts from this instance were already delivered locally
+	if envelope.Origin == s.pubSub.InstanceID() {
+		return
+	}
+
+	switch envelope.Kind {
+	case simpleConversationEventConversation:
+		message := SimpleConversationMessage{
+			ConversationID: envelope.ConversationID,
+			Message:        envelope.Data,
+			Event:          envelope.Event,
+		}
+		select {
+		case s.broadcast <- message:
+		default:
+			log.Printf("Simple conversation broadcast queue is full, dropping event for conversation %d from another instance", envelope.ConversationID)
+		}
+	case simpleConversationEventTotalUnreadCount:
+		s.sendTotalUnreadCount(envelope.Count)
+	case simpleConversationEventConversationUnreadCount:
+		s.sendConversationUnreadCount(envelope.ConversationID, envelope.Count)
+	case simpleConversationEventUserMonitors:
+		s.sendToUserMonitors(envelope.Event, envelope.Data)
+	case simpleConversationEventUserMonitorsTotalUnread:
+		s.sendTotalUnreadCountToUserMonitors(envelope.Count)
+	case simpleConversationEventUserMonitorsConversationUnread:
+		s.sendConversationUnreadCountToUserMonitors(envelope.ConversationID, envelope.Count)
+	case simpleConversationEventAdmins:
+		s.sendToAdmins(envelope.Event, envelope.Data)
+	default:
+		log.Printf("Unknown simple conversation event kind from pub/sub: %s", envelope.Kind)
 	}
 }
 
@@ -98,6 +205,12 @@ func (s *SimpleConversationWebSocketService) BroadcastSimpleConversationMessage(
 		Event:          "conversation_message",
 	}
 	s.broadcast <- message
+	s.publish(simpleConversationEnvelope{
+		Kind:           simpleConversationEventConversation,
+		ConversationID: conversationID,
+		Event:          message.Event,
+		Data:           messageData,
+	})
 }
 
 // BroadcastConversationStatus broadcasts conversation status updates
@@ -108,10 +221,25 @@ func (s *SimpleConversationWebSocketService) BroadcastConversationStatus(convers
 		Event:          "conversation_status",
 	}
 	s.broadcast <- message
+	s.publish(simpleConversationEnvelope{
+		Kind:           simpleConversationEventConversation,
+		ConversationID: conversationID,
+		Event:          message.Event,
+		Data:           statusData,
+	})
 }
 
 // BroadcastTotalUnreadCount broadcasts total unread count to all admin clients
 func (s *SimpleConversationWebSocketService) BroadcastTotalUnreadCount(totalUnreadCount int) {
+	s.sendTotalUnreadCount(totalUnreadCount)
+	s.publish(simpleConversationEnvelope{
+		Kind:  simpleConversationEventTotalUnreadCount,
+		Count: totalUnreadCount,
+	})
+}
+
+// sendTotalUnreadCount sends total unread count to admin clients on this instance
+func (s *SimpleConversationWebSocketService) sendTotalUnreadCount(totalUnreadCount int) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
@@ -139,7 +267,16 @@ func (s *SimpleConversationWebSocketService) BroadcastTotalUnreadCount(totalUnre
 
 // BroadcastConversationUnreadCount broadcasts individual conversation unread count to all admin clients
 func (s *SimpleConversationWebSocketService) BroadcastConversationUnreadCount(conversationID uint, unreadCount int) {
-	
+	s.sendConversationUnreadCount(conversationID, unreadCount)
+	s.publish(simpleConversationEnvelope{
+		Kind:           simpleConversationEventConversationUnreadCount,
+		ConversationID: conversationID,
+		Count:          unreadCount,
+	})
+}
+
+// sendConversationUnreadCount sends conversation unread count to admin clients on this instance
+func (s *SimpleConversationWebSocketService) sendConversationUnreadCount(conversationID uint, unreadCount int) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
@@ -376,6 +513,16 @@ func (s *SimpleConversationWebSocketService) UnregisterUserMonitorClient(userID
 
 // BroadcastToUserMonitors broadcasts a message to all connected user monitor clients
 func (s *SimpleConversationWebSocketService) BroadcastToUserMonitors(event string, data map[string]interface{}) {
+	s.sendToUserMonitors(event, data)
+	s.publish(simpleConversationEnvelope{
+		Kind:  simpleConversationEventUserMonitors,
+		Event: event,
+		Data:  data,
+	})
+}
+
+// sendToUserMonitors sends a message to user monitor clients on this instance
+func (s *SimpleConversationWebSocketService) sendToUserMonitors(event string, data map[string]interface{}) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
@@ -395,6 +542,15 @@ func (s *SimpleConversationWebSocketService) BroadcastToUserMonitors(event strin
 
 // BroadcastTotalUnreadCountToUserMonitors broadcasts total unread count to all connected user monitor clients
 func (s *SimpleConversationWebSocketService) BroadcastTotalUnreadCountToUserMonitors(totalUnreadCount int) {
+	s.sendTotalUnreadCountToUserMonitors(totalUnreadCount)
+	s.publish(simpleConversationEnvelope{
+		Kind:  simpleConversationEventUserMonitorsTotalUnread,
+		Count: totalUnreadCount,
+	})
+}
+
+// sendTotalUnreadCountToUserMonitors sends total unread count to user monitor clients on this instance
+func (s *SimpleConversationWebSocketService) sendTotalUnreadCountToUserMonitors(totalUnreadCount int) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
@@ -423,6 +579,16 @@ func (s *SimpleConversationWebSocketService) BroadcastTotalUnreadCountToUserMoni
 
 // BroadcastConversationUnreadCountToUserMonitors broadcasts conversation unread count to all connected user monitor clients
 func (s *SimpleConversationWebSocketService) BroadcastConversationUnreadCountToUserMonitors(conversationID uint, unreadCount int) {
+	s.sendConversationUnreadCountToUserMonitors(conversationID, unreadCount)
+	s.publish(simpleConversationEnvelope{
+		Kind:           simpleConversationEventUserMonitorsConversationUnread,
+		ConversationID: conversationID,
+		Count:          unreadCount,
+	})
+}
+
+// sendConversationUnreadCountToUserMonitors sends conversation unread count to user monitor clients on this instance
+func (s *SimpleConversationWebSocketService) sendConversationUnreadCountToUserMonitors(conversationID uint, unreadCount int) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
@@ -452,12 +618,22 @@ func (s *SimpleConversationWebSocketService) BroadcastConversationUnreadCountToU
 
 // BroadcastToAdmins broadcasts a message to all connected admin clients
 func (s *SimpleConversationWebSocketService) BroadcastToAdmins(event string, data map[string]interface{}) {
+	s.sendToAdmins(event, data)
+	s.publish(simpleConversationEnvelope{
+		Kind:  simpleConversationEventAdmins,
+		Event: event,
+		Data:  data,
+	})
+}
+
+// sendToAdmins sends a message to admin clients on this instance
+func (s *SimpleConversationWebSocketService) sendToAdmins(event string, data map[string]interface{}) {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
 	message := map[string]interface{}{
-		"event": event,
-		"data":  data,
+		"event":     event,
+		"data":      data,
 		"timestamp": time.Now().Unix(),
 	}
 
@@ -485,9 +661,9 @@ func (s *SimpleConversationWebSocketService) GetAllConversationsData() map[strin
 	// This would typically fetch data from the database
 	// For now, return connection statistics
 	return map[string]interface{}{
-		"total_conversations": len(s.conversationClients),
-		"total_users":         len(s.userConnections),
-		"total_admins":        len(s.adminConnections),
+		"total_conversations":  len(s.conversationClients),
+		"total_users":          len(s.userConnections),
+		"total_admins":         len(s.adminConnections),
 		"active_conversations": s.getActiveConversations(),
 	}
 }
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SiteVisitReminderService reminds buyers and listing owners about upcoming property site visits
+type SiteVisitReminderService struct {
+	enquiryRepo                 *repositories.PropertyEnquiryRepository
+	adminConfigService          *AdminConfigService
+	enhancedNotificationService *EnhancedNotificationService
+	stopChan                    chan bool
+}
+
+func NewSiteVisitReminderService(enhancedNotificationService *EnhancedNotificationService) *SiteVisitReminderService {
+	return &SiteVisitReminderService{
+		enquiryRepo:                 repositories.NewPropertyEnquiryRepository(),
+		adminConfigService:          NewAdminConfigService(),
+		enhancedNotificationService: enhancedNotificationService,
+		stopChan:                    make(chan bool),
+	}
+}
+
+// Start begins the periodic check for visits due a reminder
+func (svrs *SiteVisitReminderService) Start() {
+	logrus.Info("SiteVisitReminderService starting...")
+
+	// Run immediately on start
+	go svrs.sendReminders()
+
+	// Then run every 15 minutes
+	ticker := time.NewTicker(15 * time.Minute)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				svrs.sendReminders()
+			case <-svrs.stopChan:
+				ticker.Stop()
+				logrus.Info("SiteVisitReminderService stopped")
+				return
+			}
+		}
+	}()
+
+	logrus.Info("SiteVisitReminderService started successfully")
+}
+
+// Stop stops the site visit reminder service
+func (svrs *SiteVisitReminderService) Stop() {
+	logrus.Info("Stopping SiteVisitReminderService...")
+	svrs.stopChan <- true
+}
+
+// sendReminders reminds both parties of each visit starting within the reminder window
+func (svrs *SiteVisitReminderService) sendReminders() {
+	now := time.Now()
+	reminderHours := svrs.adminConfigService.GetSiteVisitReminderHours()
+
+	visits, err := svrs.enquiryRepo.GetVisitsDueForReminder(now, now.Add(time.Duration(reminderHours)*time.Hour))
+	if err != nil {
+		logrus.Errorf("SiteVisitReminderService: failed to get visits due a reminder: %v", err)
+		return
+	}
+
+	sent := 0
+	for i := range visits {
+		visit := &visits[i]
+		if err := svrs.enquiryRepo.MarkReminderSent(visit.ID, now); err != nil {
+			logrus.Errorf("SiteVisitReminderService: failed to mark reminder sent for visit %d: %v", visit.ID, err)
+			continue
+		}
+		sent++
+		svrs.sendReminder(visit, visit.BuyerID)
+		svrs.sendReminder(visit, visit.OwnerID)
+	}
+
+	if sent > 0 {
+		logrus.Infof("SiteVisitReminderService: reminded %d site visits", sent)
+	}
+}
+
+// sendReminder reminds one party of an upcoming site visit
+func (svrs *SiteVisitReminderService) sendReminder(visit *models.PropertySiteVisit, userID uint) {
+	if svrs.enhancedNotificationService == nil {
+		return
+	}
+
+	go func() {
+		_, err := svrs.enhancedNotificationService.SendNotification(&NotificationRequest{
+			UserID:   userID,
+			Type:     models.NotificationTypeSystem,
+			Title:    "Upcoming Site Visit",
+			Body:     fmt.Sprintf("Reminder: your visit to %s is on %s.", propertyTitle(visit.Property), visit.ScheduledAt.Format("02 Jan 2006, 3:04 PM")),
+			Data:     siteVisitNotificationData(visit),
+			Priority: "high",
+		})
+		if err != nil {
+			logrus.Errorf("SiteVisitReminderService: failed to notify user %d: %v", userID, err)
+		}
+	}()
+}
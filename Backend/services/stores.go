@@ -0,0 +1,93 @@
+package services
+
+import (
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"gorm.io/gorm"
+)
+
+// The interfaces below are the parts of the repositories that the payment, wallet, booking and quote
+// services use. The services take them in their ...With constructors so tests can swap in fakes; the
+// repositories package provides the production implementations.
+
+// PaymentStore stores payments
+type PaymentStore interface {
+	Create(payment *models.Payment) error
+	Update(payment *models.Payment) error
+	GetByID(id uint) (*models.Payment, error)
+	GetByReference(reference string) (*models.Payment, error)
+	GetByRazorpayOrderID(orderID string) (*models.Payment, error)
+	GetPayments(filters *models.PaymentFilters) ([]models.Payment, *repositories.Pagination, error)
+	GetUserPayments(userID uint, filters *models.PaymentFilters) ([]models.Payment, *repositories.Pagination, error)
+	GetHeldBookingPayments(bookingID uint) ([]models.Payment, error)
+	GetPaymentStats(userID *uint) (map[string]interface{}, error)
+	GetAbandonedWalletPayments(cutoffTime time.Time) ([]*models.Payment, error)
+	GetByUserIDAndTypes(userID uint, paymentTypes []models.PaymentType, limit, offset int) ([]models.Payment, error)
+	GetCountByUserIDAndTypes(userID uint, paymentTypes []models.PaymentType) (int64, error)
+	GetRecentByUserIDAndTypes(userID uint, paymentTypes []models.PaymentType, limit int) ([]models.Payment, error)
+	GetTotalAmountByUserIDAndType(userID uint, paymentType models.PaymentType) (float64, error)
+	GetTotalAmountByUserIDTypeAndStatus(userID uint, paymentType models.PaymentType, status models.PaymentStatus) (float64, error)
+	GetByUserIDAndTypesAndStatus(userID uint, paymentTypes []models.PaymentType, status models.PaymentStatus, limit, offset int) ([]models.Payment, error)
+	GetCountByUserIDAndTypesAndStatus(userID uint, paymentTypes []models.PaymentType, status models.PaymentStatus) (int64, error)
+	GetAdminPayments(filters *models.AdminPaymentFilters) ([]models.Payment, *repositories.Pagination, error)
+	GetAdminTransactionStats() (*models.AdminTransactionStats, error)
+	GetPaymentsForExport(filters *models.AdminPaymentFilters) ([]models.Payment, error)
+}
+
+// UserStore loads and saves users. The model arguments are pointers to models.User.
+type UserStore interface {
+	FindByID(model interface{}, id uint) error
+	Update(model interface{}) error
+}
+
+// WalletLedger moves wallet balances. Post adds delta to the balance of payment's user and stores
+// payment as a completed transaction, as one locked unit; a debit larger than the balance fails
+// with *repositories.InsufficientWalletBalanceError. When tx is set the post joins that transaction.
+type WalletLedger interface {
+	Post(tx *gorm.DB, payment *models.Payment, delta float64) error
+}
+
+// BookingStore stores bookings
+type BookingStore interface {
+	Create(booking *models.Booking) (*models.Booking, error)
+	Update(booking *models.Booking) error
+	GetByID(id uint) (*models.Booking, error)
+	GetUserBookings(userID uint, filters *repositories.UserBookingFilters) ([]models.Booking, *repositories.Pagination, error)
+	GetBookingsWithFilters(filters *repositories.AdminBookingFilters) ([]models.Booking, *repositories.Pagination, error)
+	GetInquiryBookings(filters *repositories.InquiryBookingFilters) ([]models.Booking, *repositories.Pagination, error)
+	GetExpiredTemporaryHolds() ([]models.Booking, error)
+	GetBookingStats() (map[string]interface{}, error)
+	GetRecentBookings(limit int) ([]models.OptimizedBookingResponse, error)
+	GetUrgentAlerts() ([]models.OptimizedBookingResponse, error)
+	GetQuotesExpiringBefore(before time.Time) ([]models.Booking, error)
+	MarkQuoteExpiryWarningSent(bookingID uint, sentAt time.Time) error
+	GetExpiredQuotes() ([]models.Booking, error)
+}
+
+// PaymentSegmentStore stores the payment segments of quoted bookings
+type PaymentSegmentStore interface {
+	CreateMultiple(segments []models.PaymentSegment) error
+	Update(segment *models.PaymentSegment) error
+	GetByBookingID(bookingID uint) ([]models.PaymentSegment, error)
+	GetByBookingIDAndSegmentNumber(bookingID uint, segmentNumber int) (*models.PaymentSegment, error)
+	GetPendingSegments(bookingID uint) ([]models.PaymentSegment, error)
+	GetPaidSegments(bookingID uint) ([]models.PaymentSegment, error)
+	MarkAsPaid(segmentID uint, paymentID uint) error
+	DeleteByBookingID(bookingID uint) error
+	GetPaymentProgress(bookingID uint) (*models.PaymentProgress, error)
+	IsAllSegmentsPaid(bookingID uint) (bool, error)
+}
+
+// QuoteRevisionStore stores the revisions of a booking's quote
+type QuoteRevisionStore interface {
+	Create(revision *models.QuoteRevision) error
+	GetByID(id uint) (*models.QuoteRevision, error)
+	GetByBookingID(bookingID uint) ([]models.QuoteRevision, error)
+	GetActiveQuote(bookingID uint) (*models.QuoteRevision, error)
+	GetLatestVersion(bookingID uint) (int, error)
+	CountByBookingID(bookingID uint) (int64, error)
+	UpdateStatusByBooking(bookingID uint, from []models.QuoteRevisionStatus, to models.QuoteRevisionStatus) error
+	Lock(revisionID uint, lockedAt time.Time) error
+}
@@ -46,6 +46,19 @@ func (sps *SubscriptionPlanService) CreatePlanWithPricing(planData map[string]in
 		}
 	}
 	
+	// Free listing boosts granted each month
+	if freeBoosts, ok := planData[models.PlanFeatureFreeBoostsPerMonth].(float64); ok {
+		if freeBoosts < 0 {
+			return nil, errors.New("free_boosts_per_month cannot be negative")
+		}
+		if freeBoosts > 0 {
+			if features == nil {
+				features = models.JSONB{}
+			}
+			features[models.PlanFeatureFreeBoostsPerMonth] = int(freeBoosts)
+		}
+	}
+	
 	// Validate pricing array
 	pricingArray, ok := planData["pricing"].([]interface{})
 	if !ok || len(pricingArray) == 0 {
@@ -140,6 +153,15 @@ func (sps *SubscriptionPlanService) UpdatePlan(id uint, planData map[string]inte
 		plan.IsActive = isActive
 	}
 	
+	// Keep the free boosts when the features are rewritten
+	freeBoosts := plan.FreeBoostsPerMonth()
+	if value, ok := planData[models.PlanFeatureFreeBoostsPerMonth].(float64); ok {
+		if value < 0 {
+			return nil, errors.New("free_boosts_per_month cannot be negative")
+		}
+		freeBoosts = int(value)
+	}
+	
 	// Handle features - convert array to JSONB
 	if featuresArray, ok := planData["features"].([]interface{}); ok {
 		if len(featuresArray) > 0 {
@@ -163,6 +185,15 @@ func (sps *SubscriptionPlanService) UpdatePlan(id uint, planData map[string]inte
 		}
 	}
 	
+	if freeBoosts > 0 {
+		if plan.Features == nil {
+			plan.Features = models.JSONB{}
+		}
+		plan.Features[models.PlanFeatureFreeBoostsPerMonth] = freeBoosts
+	} else if plan.Features != nil {
+		delete(plan.Features, models.PlanFeatureFreeBoostsPerMonth)
+	}
+	
 	// Handle pricing - update the entire pricing array
 	if pricingArray, ok := planData["pricing"].([]interface{}); ok && len(pricingArray) > 0 {
 		var pricingOptions models.PricingOptionsJSONB
@@ -4,25 +4,42 @@ import (
 	"errors"
 	"fmt"
 	"time"
+	"treesindia/metrics"
 	"treesindia/models"
 	"treesindia/repositories"
 
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
+// WalletLimits are the admin configured recharge and balance limits. AdminConfigService provides them.
+type WalletLimits interface {
+	GetMinRechargeAmount() float64
+	GetMaxRechargeAmount() float64
+	GetMaxWalletBalance() float64
+}
+
 // UnifiedWalletService handles all wallet operations using the unified payment system
 type UnifiedWalletService struct {
 	paymentService   *PaymentService
-	userRepo         *repositories.UserRepository
-	adminConfigService *AdminConfigService
+	userRepo         UserStore
+	ledger           WalletLedger
+	adminConfigService WalletLimits
 }
 
 // NewUnifiedWalletService creates a new unified wallet service
 func NewUnifiedWalletService() *UnifiedWalletService {
+	return NewUnifiedWalletServiceWith(NewPaymentService(), repositories.NewUserRepository(), repositories.NewWalletLedgerRepository(), NewAdminConfigService())
+}
+
+// NewUnifiedWalletServiceWith creates a wallet service that records wallet transactions through
+// paymentService, reads the users in userRepo and moves their balances through ledger
+func NewUnifiedWalletServiceWith(paymentService *PaymentService, userRepo UserStore, ledger WalletLedger, limits WalletLimits) *UnifiedWalletService {
 	return &UnifiedWalletService{
-		paymentService:   NewPaymentService(),
-		userRepo:         repositories.NewUserRepository(),
-		adminConfigService: NewAdminConfigService(),
+		paymentService:   paymentService,
+		userRepo:         userRepo,
+		ledger:           ledger,
+		adminConfigService: limits,
 	}
 }
 
@@ -154,111 +171,62 @@ func (s *UnifiedWalletService) RefreshWalletRechargeOrder(paymentID uint, userID
 
 // DeductFromWallet deducts amount from user's wallet for service payments
 func (s *UnifiedWalletService) DeductFromWallet(userID uint, amount float64, serviceID uint, description string) (*models.Payment, error) {
-	// Get user
-	var user models.User
-	if err := s.userRepo.FindByID(&user, userID); err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
-	}
-
-	// Check if user has sufficient balance
-	if user.WalletBalance < amount {
-		return nil, fmt.Errorf("insufficient wallet balance. Required: ₹%.2f, Available: ₹%.2f", amount, user.WalletBalance)
-	}
-
-	// Calculate new balance
-	newBalance := user.WalletBalance - amount
-
-	// Create payment record for wallet debit
-	paymentReq := &models.CreatePaymentRequest{
+	payment, err := s.debitWallet(nil, &models.CreatePaymentRequest{
 		UserID:            userID,
 		Amount:            amount,
-		Currency:          "INR",
-		Type:              models.PaymentTypeWalletDebit,
-		Method:            "wallet",
 		RelatedEntityType: "service",
 		RelatedEntityID:   serviceID,
 		Description:       description,
 		Notes:             "Service payment from wallet",
-	}
-
-	// Create payment record
-	payment, err := s.paymentService.CreatePayment(paymentReq)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create payment record: %w", err)
+		return nil, err
 	}
 
-	// Update user wallet balance
-	user.WalletBalance = newBalance
-	if err := s.userRepo.Update(&user); err != nil {
-		return nil, fmt.Errorf("failed to update user wallet: %w", err)
-	}
+	metrics.RecordWalletDebit("service", amount)
 
-	// Update payment status to completed
-	now := time.Now()
-	payment.Status = models.PaymentStatusCompleted
-	payment.CompletedAt = &now
-	payment.BalanceAfter = &newBalance
-
-	if err := s.paymentService.UpdatePayment(payment); err != nil {
-		return nil, fmt.Errorf("failed to update payment: %w", err)
-	}
-
-	logrus.Infof("Wallet debit for user %d: ₹%.2f, new balance: ₹%.2f", userID, amount, newBalance)
+	logrus.Infof("Wallet debit for user %d: ₹%.2f, new balance: ₹%.2f", userID, amount, *payment.BalanceAfter)
 	return payment, nil
 }
 
 // DeductFromWalletForBooking deducts amount from user's wallet for booking payments
 func (s *UnifiedWalletService) DeductFromWalletForBooking(userID uint, amount float64, bookingID uint, description string) (*models.Payment, error) {
-	// Get user
-	var user models.User
-	if err := s.userRepo.FindByID(&user, userID); err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
-	}
-
-	// Check if user has sufficient balance
-	if user.WalletBalance < amount {
-		return nil, fmt.Errorf("insufficient wallet balance. Required: ₹%.2f, Available: ₹%.2f", amount, user.WalletBalance)
-	}
-
-	// Calculate new balance
-	newBalance := user.WalletBalance - amount
-
-	// Create payment record for wallet debit
-	paymentReq := &models.CreatePaymentRequest{
+	payment, err := s.debitWallet(nil, &models.CreatePaymentRequest{
 		UserID:            userID,
 		Amount:            amount,
-		Currency:          "INR",
-		Type:              models.PaymentTypeWalletDebit,
-		Method:            "wallet",
 		RelatedEntityType: "booking",
 		RelatedEntityID:   bookingID,
 		Description:       description,
 		Notes:             "Booking payment from wallet",
-	}
-
-	// Create payment record
-	payment, err := s.paymentService.CreatePayment(paymentReq)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create payment record: %w", err)
+		return nil, err
 	}
 
-	// Update user wallet balance
-	user.WalletBalance = newBalance
-	if err := s.userRepo.Update(&user); err != nil {
-		return nil, fmt.Errorf("failed to update user wallet: %w", err)
-	}
+	metrics.RecordWalletDebit("booking", amount)
 
-	// Update payment status to completed
-	now := time.Now()
-	payment.Status = models.PaymentStatusCompleted
-	payment.CompletedAt = &now
-	payment.BalanceAfter = &newBalance
+	logrus.Infof("Wallet debit for booking %d, user %d: ₹%.2f, new balance: ₹%.2f", bookingID, userID, amount, *payment.BalanceAfter)
+	return payment, nil
+}
 
-	if err := s.paymentService.UpdatePayment(payment); err != nil {
-		return nil, fmt.Errorf("failed to update payment: %w", err)
+// DeductFromWalletForPropertyPromotionWithTx deducts amount from user's wallet for a listing boost
+// or featured slot within tx, so the debit is rolled back if the promotion cannot be activated
+func (s *UnifiedWalletService) DeductFromWalletForPropertyPromotionWithTx(tx *gorm.DB, userID uint, amount float64, promotionID uint, description string) (*models.Payment, error) {
+	payment, err := s.debitWallet(tx, &models.CreatePaymentRequest{
+		UserID:            userID,
+		Amount:            amount,
+		RelatedEntityType: "property_promotion",
+		RelatedEntityID:   promotionID,
+		Description:       description,
+		Notes:             "Listing promotion payment from wallet",
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	logrus.Infof("Wallet debit for booking %d, user %d: ₹%.2f, new balance: ₹%.2f", bookingID, userID, amount, newBalance)
+	metrics.RecordWalletDebit("property_promotion", amount)
+
+	logrus.Infof("Wallet debit for property promotion %d, user %d: ₹%.2f, new balance: ₹%.2f", promotionID, userID, amount, *payment.BalanceAfter)
 	return payment, nil
 }
 
@@ -320,6 +288,84 @@ func (s *UnifiedWalletService) CreditWorkerEarnings(workerUserID uint, amount fl
 	return payment, nil
 }
 
+// CreditRefundToWallet credits a refund for a booking to the user's wallet
+func (s *UnifiedWalletService) CreditRefundToWallet(userID uint, amount float64, bookingID uint, description string) (*models.Payment, error) {
+	return s.CreditRefundToWalletWithTx(nil, userID, amount, bookingID, description)
+}
+
+// CreditRefundToWalletWithTx credits a refund for a booking to the user's wallet within tx, so the
+// credit is rolled back if the caller's own updates fail
+func (s *UnifiedWalletService) CreditRefundToWalletWithTx(tx *gorm.DB, userID uint, amount float64, bookingID uint, description string) (*models.Payment, error) {
+	// Refunds are always credited, even above the wallet limit
+	payment, err := s.postWalletTransaction(tx, &models.CreatePaymentRequest{
+		UserID:            userID,
+		Amount:            amount,
+		Currency:          "INR",
+		Type:              models.PaymentTypeRefund,
+		Method:            "wallet",
+		RelatedEntityType: "booking",
+		RelatedEntityID:   bookingID,
+		Description:       description,
+		Notes:             "Refund credited to wallet",
+	}, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Refund credited to wallet for booking %d, user %d: ₹%.2f, new balance: ₹%.2f", bookingID, userID, amount, *payment.BalanceAfter)
+	return payment, nil
+}
+
+// postWalletTransaction records req as a completed wallet transaction that moves the user's
+// balance by delta
+func (s *UnifiedWalletService) postWalletTransaction(tx *gorm.DB, req *models.CreatePaymentRequest, delta float64) (*models.Payment, error) {
+	payment := s.paymentService.newPayment(req)
+	if err := s.ledger.Post(tx, payment, delta); err != nil {
+		var insufficient *repositories.InsufficientWalletBalanceError
+		switch {
+		case errors.As(err, &insufficient):
+			return nil, err
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, fmt.Errorf("user not found: %w", err)
+		default:
+			return nil, fmt.Errorf("failed to record wallet transaction: %w", err)
+		}
+	}
+	return payment, nil
+}
+
+// debitWallet records req as a completed wallet debit of req.Amount. It fails with
+// *repositories.InsufficientWalletBalanceError when the balance is too low.
+func (s *UnifiedWalletService) debitWallet(tx *gorm.DB, req *models.CreatePaymentRequest) (*models.Payment, error) {
+	req.Currency = "INR"
+	req.Type = models.PaymentTypeWalletDebit
+	req.Method = "wallet"
+	return s.postWalletTransaction(tx, req, -req.Amount)
+}
+
+// CreditReferralRewardWithTx credits a referral reward to the user's wallet within tx, so the
+// credit is rolled back with the caller's claim of the reward
+func (s *UnifiedWalletService) CreditReferralRewardWithTx(tx *gorm.DB, userID uint, amount float64, referralID uint, description string) (*models.Payment, error) {
+	// Rewards are always credited, even above the wallet limit
+	payment, err := s.postWalletTransaction(tx, &models.CreatePaymentRequest{
+		UserID:            userID,
+		Amount:            amount,
+		Currency:          "INR",
+		Type:              models.PaymentTypeReferralReward,
+		Method:            "wallet",
+		RelatedEntityType: "referral",
+		RelatedEntityID:   referralID,
+		Description:       description,
+		Notes:             "Referral reward credited to wallet",
+	}, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Referral reward credited to wallet for referral %d, user %d: ₹%.2f, new balance: ₹%.2f", referralID, userID, amount, *payment.BalanceAfter)
+	return payment, nil
+}
+
 // GetUserWalletTransactions gets wallet transactions for a user
 func (s *UnifiedWalletService) GetUserWalletTransactions(userID uint, page, limit int) ([]models.Payment, int64, error) {
 	offset := (page - 1) * limit
@@ -329,6 +375,8 @@ func (s *UnifiedWalletService) GetUserWalletTransactions(userID uint, page, limi
 		models.PaymentTypeWalletRecharge,
 		models.PaymentTypeWalletDebit,
 		models.PaymentTypeWorkerEarnings,
+		models.PaymentTypeReferralReward,
+		models.PaymentTypeRefund,
 	}, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get wallet transactions: %w", err)
@@ -339,6 +387,8 @@ func (s *UnifiedWalletService) GetUserWalletTransactions(userID uint, page, limi
 		models.PaymentTypeWalletRecharge,
 		models.PaymentTypeWalletDebit,
 		models.PaymentTypeWorkerEarnings,
+		models.PaymentTypeReferralReward,
+		models.PaymentTypeRefund,
 	})
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get transaction count: %w", err)
@@ -359,6 +409,8 @@ func (s *UnifiedWalletService) GetUserWalletSummary(userID uint) (map[string]int
 		models.PaymentTypeWalletRecharge,
 		models.PaymentTypeWalletDebit,
 		models.PaymentTypeWorkerEarnings,
+		models.PaymentTypeReferralReward,
+		models.PaymentTypeRefund,
 	}, 5)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent transactions: %w", err)
@@ -387,6 +439,8 @@ func (s *UnifiedWalletService) GetUserWalletSummary(userID uint) (map[string]int
 		models.PaymentTypeWalletRecharge,
 		models.PaymentTypeWalletDebit,
 		models.PaymentTypeWorkerEarnings,
+		models.PaymentTypeReferralReward,
+		models.PaymentTypeRefund,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transaction count: %w", err)
@@ -531,6 +585,8 @@ func (s *UnifiedWalletService) GetUserCompletedWalletTransactions(userID uint, p
 		models.PaymentTypeWalletRecharge,
 		models.PaymentTypeWalletDebit,
 		models.PaymentTypeWorkerEarnings,
+		models.PaymentTypeReferralReward,
+		models.PaymentTypeRefund,
 	}, models.PaymentStatusCompleted, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get completed wallet transactions: %w", err)
@@ -541,6 +597,8 @@ func (s *UnifiedWalletService) GetUserCompletedWalletTransactions(userID uint, p
 		models.PaymentTypeWalletRecharge,
 		models.PaymentTypeWalletDebit,
 		models.PaymentTypeWorkerEarnings,
+		models.PaymentTypeReferralReward,
+		models.PaymentTypeRefund,
 	}, models.PaymentStatusCompleted)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get completed transaction count: %w", err)
@@ -0,0 +1,224 @@
+package services_test
+
+import (
+	"testing"
+	"treesindia/models"
+	"treesindia/repositories"
+	"treesindia/services"
+	"treesindia/testutil"
+)
+
+// walletFixture is a wallet service running on in-memory stores and the fake payment gateway
+type walletFixture struct {
+	wallet   *services.UnifiedWalletService
+	users    *testutil.FakeUserStore
+	payments *testutil.FakePaymentStore
+	gateway  *testutil.FakePaymentGateway
+}
+
+func newWalletFixture(limits testutil.StaticWalletLimits) *walletFixture {
+	f := &walletFixture{
+		users:    testutil.NewFakeUserStore(),
+		payments: testutil.NewFakePaymentStore(),
+		gateway:  testutil.NewFakePaymentGateway(),
+	}
+	paymentService := services.NewPaymentServiceWith(f.payments, f.gateway)
+	f.wallet = services.NewUnifiedWalletServiceWith(paymentService, f.users, testutil.NewFakeWalletLedger(f.users, f.payments), limits)
+	return f
+}
+
+func TestDeductFromWalletForBooking(t *testing.T) {
+	f := newWalletFixture(testutil.StaticWalletLimits{})
+	userID := f.users.Add(models.User{Phone: "+919000000001", WalletBalance: 500})
+
+	payment, err := f.wallet.DeductFromWalletForBooking(userID, 200, 7, "Segment 1 payment for booking")
+	if err != nil {
+		t.Fatalf("DeductFromWalletForBooking returned error: %v", err)
+	}
+
+	if got := f.users.WalletBalance(userID); got != 300 {
+		t.Errorf("wallet balance = %.2f, want 300", got)
+	}
+	if payment.Type != models.PaymentTypeWalletDebit || payment.Status != models.PaymentStatusCompleted {
+		t.Errorf("payment type/status = %s/%s, want wallet_debit/completed", payment.Type, payment.Status)
+	}
+	if payment.RelatedEntityType != "booking" || payment.RelatedEntityID != 7 {
+		t.Errorf("payment is related to %s %d, want booking 7", payment.RelatedEntityType, payment.RelatedEntityID)
+	}
+	if payment.BalanceAfter == nil || *payment.BalanceAfter != 300 {
+		t.Errorf("payment balance after = %v, want 300", payment.BalanceAfter)
+	}
+
+	stored, err := f.payments.GetByID(payment.ID)
+	if err != nil {
+		t.Fatalf("payment %d was not stored: %v", payment.ID, err)
+	}
+	if stored.Status != models.PaymentStatusCompleted {
+		t.Errorf("stored payment status = %s, want completed", stored.Status)
+	}
+}
+
+func TestDeductFromWalletInsufficientBalance(t *testing.T) {
+	f := newWalletFixture(testutil.StaticWalletLimits{})
+	userID := f.users.Add(models.User{Phone: "+919000000001", WalletBalance: 50})
+
+	if _, err := f.wallet.DeductFromWalletForBooking(userID, 200, 7, "Segment 1 payment for booking"); err == nil {
+		t.Fatal("DeductFromWalletForBooking returned no error")
+	}
+
+	if got := f.users.WalletBalance(userID); got != 50 {
+		t.Errorf("wallet balance = %.2f, want 50", got)
+	}
+	if payments := f.payments.All(); len(payments) != 0 {
+		t.Errorf("%d payments were recorded, want none", len(payments))
+	}
+}
+
+func TestCreditRefundToWalletIgnoresBalanceLimit(t *testing.T) {
+	f := newWalletFixture(testutil.StaticWalletLimits{MaxBalance: 1000})
+	userID := f.users.Add(models.User{Phone: "+919000000001", WalletBalance: 900})
+
+	payment, err := f.wallet.CreditRefundToWallet(userID, 300, 7, "Refund for booking")
+	if err != nil {
+		t.Fatalf("CreditRefundToWallet returned error: %v", err)
+	}
+
+	if got := f.users.WalletBalance(userID); got != 1200 {
+		t.Errorf("wallet balance = %.2f, want 1200", got)
+	}
+	if payment.Type != models.PaymentTypeRefund || payment.Status != models.PaymentStatusCompleted {
+		t.Errorf("payment type/status = %s/%s, want refund/completed", payment.Type, payment.Status)
+	}
+
+	transactions, total, err := f.wallet.GetUserWalletTransactions(userID, 1, 10)
+	if err != nil {
+		t.Fatalf("GetUserWalletTransactions returned error: %v", err)
+	}
+	if total != 1 || len(transactions) != 1 || transactions[0].ID != payment.ID {
+		t.Errorf("wallet transactions = %v (total %d), want the refund", transactions, total)
+	}
+}
+
+func TestCreditReferralReward(t *testing.T) {
+	f := newWalletFixture(testutil.StaticWalletLimits{MaxBalance: 1000})
+	userID := f.users.Add(models.User{Phone: "+919000000001", WalletBalance: 950})
+
+	payment, err := f.wallet.CreditReferralRewardWithTx(nil, userID, 100, 3, "Referral reward")
+	if err != nil {
+		t.Fatalf("CreditReferralRewardWithTx returned error: %v", err)
+	}
+
+	if got := f.users.WalletBalance(userID); got != 1050 {
+		t.Errorf("wallet balance = %.2f, want 1050", got)
+	}
+	if payment.Type != models.PaymentTypeReferralReward || payment.Status != models.PaymentStatusCompleted {
+		t.Errorf("payment type/status = %s/%s, want referral_reward/completed", payment.Type, payment.Status)
+	}
+	if payment.RelatedEntityType != "referral" || payment.RelatedEntityID != 3 {
+		t.Errorf("payment is related to %s %d, want referral 3", payment.RelatedEntityType, payment.RelatedEntityID)
+	}
+	if payment.BalanceAfter == nil || *payment.BalanceAfter != 1050 {
+		t.Errorf("payment balance after = %v, want 1050", payment.BalanceAfter)
+	}
+}
+
+func TestRechargeWalletCreatesGatewayOrder(t *testing.T) {
+	f := newWalletFixture(testutil.StaticWalletLimits{MinRecharge: 100, MaxRecharge: 50000, MaxBalance: 100000})
+	userID := f.users.Add(models.User{Phone: "+919000000001"})
+
+	payment, order, err := f.wallet.RechargeWallet(userID, 1000, "razorpay")
+	if err != nil {
+		t.Fatalf("RechargeWallet returned error: %v", err)
+	}
+
+	if payment.Status != models.PaymentStatusPending || payment.Type != models.PaymentTypeWalletRecharge {
+		t.Errorf("payment type/status = %s/%s, want wallet_recharge/pending", payment.Type, payment.Status)
+	}
+	if payment.RazorpayOrderID == nil || *payment.RazorpayOrderID != order["id"] {
+		t.Errorf("payment order ID = %v, want %v", payment.RazorpayOrderID, order["id"])
+	}
+	if order["amount"] != int64(100000) {
+		t.Errorf("order amount = %v paise, want 100000", order["amount"])
+	}
+	if got := f.users.WalletBalance(userID); got != 0 {
+		t.Errorf("wallet balance = %.2f before the payment is verified, want 0", got)
+	}
+}
+
+func TestRechargeWalletLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance float64
+		amount  float64
+	}{
+		{"below minimum recharge", 0, 50},
+		{"above maximum recharge", 0, 60000},
+		{"above maximum balance", 99500, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newWalletFixture(testutil.StaticWalletLimits{MinRecharge: 100, MaxRecharge: 50000, MaxBalance: 100000})
+			userID := f.users.Add(models.User{Phone: "+919000000001", WalletBalance: tt.balance})
+
+			if _, _, err := f.wallet.RechargeWallet(userID, tt.amount, "razorpay"); err == nil {
+				t.Fatal("RechargeWallet returned no error")
+			}
+			if orders := f.gateway.Orders(); len(orders) != 0 {
+				t.Errorf("%d gateway orders were created, want none", len(orders))
+			}
+		})
+	}
+}
+
+func TestCompleteWalletRecharge(t *testing.T) {
+	testutil.RequireDB(t)
+	gateway := testutil.NewFakePaymentGateway()
+	paymentService := services.NewPaymentServiceWith(repositories.NewPaymentRepository(), gateway)
+	wallet := services.NewUnifiedWalletServiceWith(paymentService, repositories.NewUserRepository(), repositories.NewWalletLedgerRepository(), testutil.StaticWalletLimits{MinRecharge: 100})
+	user := testutil.CreateUser(t, 250)
+
+	payment, order, err := wallet.RechargeWallet(user.ID, 1000, "razorpay")
+	if err != nil {
+		t.Fatalf("RechargeWallet returned error: %v", err)
+	}
+
+	if err := wallet.CompleteWalletRecharge(payment.ID, "pay_test_1", "bad signature"); err == nil {
+		t.Fatal("CompleteWalletRecharge accepted a bad signature")
+	}
+	failed, err := paymentService.GetPaymentByID(payment.ID)
+	if err != nil {
+		t.Fatalf("failed to reload payment: %v", err)
+	}
+	if failed.Status != models.PaymentStatusFailed {
+		t.Errorf("payment status after a bad signature = %s, want failed", failed.Status)
+	}
+
+	// A failed payment cannot be retried, so pay with a new recharge
+	payment, order, err = wallet.RechargeWallet(user.ID, 1000, "razorpay")
+	if err != nil {
+		t.Fatalf("RechargeWallet returned error: %v", err)
+	}
+	orderID := order["id"].(string)
+	if err := wallet.CompleteWalletRecharge(payment.ID, "pay_test_2", gateway.Sign(orderID, "pay_test_2")); err != nil {
+		t.Fatalf("CompleteWalletRecharge returned error: %v", err)
+	}
+
+	var reloaded models.User
+	if err := repositories.NewUserRepository().FindByID(&reloaded, user.ID); err != nil {
+		t.Fatalf("failed to reload user: %v", err)
+	}
+	if reloaded.WalletBalance != 1250 {
+		t.Errorf("wallet balance = %.2f, want 1250", reloaded.WalletBalance)
+	}
+
+	completed, err := paymentService.GetPaymentByID(payment.ID)
+	if err != nil {
+		t.Fatalf("failed to reload payment: %v", err)
+	}
+	if completed.Status != models.PaymentStatusCompleted {
+		t.Errorf("payment status = %s, want completed", completed.Status)
+	}
+	if completed.BalanceAfter == nil || *completed.BalanceAfter != 1250 {
+		t.Errorf("payment balance after = %v, want 1250", completed.BalanceAfter)
+	}
+}
@@ -1,22 +1,33 @@
 package services
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 	"treesindia/models"
 	"treesindia/repositories"
 
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 type UserDocumentService struct {
 	documentRepo *repositories.UserDocumentRepository
-	cloudinaryService *CloudinaryService
+	mediaStore MediaStore
+	workerRepo *repositories.WorkerRepository
+	brokerRepo *repositories.BrokerRepository
+	adminConfigService *AdminConfigService
 }
 
-func NewUserDocumentService(documentRepo *repositories.UserDocumentRepository, cloudinaryService *CloudinaryService) *UserDocumentService {
+func NewUserDocumentService(documentRepo *repositories.UserDocumentRepository, mediaStore MediaStore) *UserDocumentService {
 	return &UserDocumentService{
 		documentRepo: documentRepo,
-		cloudinaryService: cloudinaryService,
+		mediaStore: mediaStore,
+		workerRepo: repositories.NewWorkerRepository(),
+		brokerRepo: repositories.NewBrokerRepository(),
+		adminConfigService: NewAdminConfigService(),
 	}
 }
 
@@ -89,3 +100,251 @@ func (s *UserDocumentService) CheckRequiredDocuments(userID uint) (bool, error)
 func (s *UserDocumentService) DeleteUserDocuments(userID uint) error {
 	return s.documentRepo.DeleteDocumentsByUserID(userID)
 }
+
+// SyncApplicationDocuments records each document of a worker or broker application, from the
+// documents JSON of the worker or broker profile, for review. Documents whose file changed are
+// reviewed again; unchanged ones keep their review.
+func (s *UserDocumentService) SyncApplicationDocuments(userID uint, documentsJSON string) error {
+	if documentsJSON == "" {
+		return nil
+	}
+
+	var files map[string]string
+	if err := json.Unmarshal([]byte(documentsJSON), &files); err != nil {
+		return fmt.Errorf("invalid documents: %v", err)
+	}
+
+	for key, fileURL := range files {
+		documentType, ok := models.ApplicationDocumentTypes[key]
+		if !ok || fileURL == "" {
+			continue
+		}
+
+		document, err := s.documentRepo.GetDocumentByUserAndType(userID, documentType)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			document = &models.UserDocument{
+				UserID:             userID,
+				DocumentType:       documentType,
+				FileURL:            fileURL,
+				VerificationStatus: models.DocumentVerificationStatusPending,
+			}
+			if err := s.documentRepo.CreateDocument(document); err != nil {
+				return fmt.Errorf("failed to record %s: %v", documentType, err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get %s: %v", documentType, err)
+		}
+
+		if document.FileURL == fileURL {
+			continue
+		}
+		document.FileURL = fileURL
+		resetDocumentReview(document)
+		if err := s.documentRepo.UpdateDocument(document); err != nil {
+			return fmt.Errorf("failed to update %s: %v", documentType, err)
+		}
+	}
+
+	return nil
+}
+
+// GetApplicationDocuments gets the documents of a role application with their review state
+func (s *UserDocumentService) GetApplicationDocuments(application *models.RoleApplication) ([]models.UserDocument, error) {
+	// Applications submitted before per-document review only have the profile documents JSON
+	documentsJSON := ""
+	if application.Worker != nil {
+		documentsJSON = application.Worker.Documents
+	} else if application.Broker != nil {
+		documentsJSON = application.Broker.Documents
+	}
+	if err := s.SyncApplicationDocuments(application.UserID, documentsJSON); err != nil {
+		return nil, err
+	}
+
+	return s.documentRepo.GetDocumentsByUserAndTypes(application.UserID, applicationDocumentTypes())
+}
+
+// GetUnverifiedMandatoryDocuments returns the mandatory documents of a role that are missing or not verified
+func (s *UserDocumentService) GetUnverifiedMandatoryDocuments(userID uint, role string) ([]models.DocumentType, error) {
+	mandatory := models.MandatoryDocumentTypes(role)
+	documents, err := s.documentRepo.GetDocumentsByUserAndTypes(userID, mandatory)
+	if err != nil {
+		return nil, err
+	}
+
+	verified := make(map[models.DocumentType]bool)
+	for _, document := range documents {
+		if document.VerificationStatus == models.DocumentVerificationStatusVerified && !document.IsExpired() {
+			verified[document.DocumentType] = true
+		}
+	}
+
+	var unverified []models.DocumentType
+	for _, documentType := range mandatory {
+		if !verified[documentType] {
+			unverified = append(unverified, documentType)
+		}
+	}
+	return unverified, nil
+}
+
+// HasExpiredMandatoryDocuments checks if any of a worker's mandatory documents have expired. Such
+// workers cannot take new assignments until a replacement is verified.
+func (s *UserDocumentService) HasExpiredMandatoryDocuments(workerID uint) (bool, error) {
+	return s.documentRepo.HasExpiredDocuments(workerID, models.MandatoryDocumentTypes(string(models.RoleTypeWorker)), time.Now())
+}
+
+// GetWorkersWithExpiredMandatoryDocuments returns which of the given workers have a mandatory document past its expiry date
+func (s *UserDocumentService) GetWorkersWithExpiredMandatoryDocuments(workerIDs []uint) (map[uint]bool, error) {
+	userIDs, err := s.documentRepo.GetUserIDsWithExpiredDocuments(workerIDs, models.MandatoryDocumentTypes(string(models.RoleTypeWorker)), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	expired := make(map[uint]bool, len(userIDs))
+	for _, userID := range userIDs {
+		expired[userID] = true
+	}
+	return expired, nil
+}
+
+// ReviewDocument verifies or rejects a document (admin only)
+func (s *UserDocumentService) ReviewDocument(documentID uint, adminID uint, req *models.ReviewUserDocumentRequest) (*models.UserDocument, error) {
+	document, err := s.documentRepo.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, errors.New("document not found")
+	}
+
+	now := time.Now()
+	switch req.Status {
+	case models.DocumentVerificationStatusVerified:
+		expiresAt := req.ExpiresAt
+		if expiresAt == nil && document.DocumentType == models.DocumentTypePoliceVerification {
+			validUntil := now.AddDate(0, s.adminConfigService.GetPoliceVerificationValidityMonths(), 0)
+			expiresAt = &validUntil
+		}
+		if expiresAt != nil && !expiresAt.After(now) {
+			return nil, errors.New("expiry date must be in the future")
+		}
+		document.ExpiresAt = expiresAt
+		document.RejectionReason = ""
+	case models.DocumentVerificationStatusRejected:
+		if strings.TrimSpace(req.RejectionReason) == "" {
+			return nil, errors.New("rejection reason is required")
+		}
+		document.RejectionReason = strings.TrimSpace(req.RejectionReason)
+	default:
+		return nil, errors.New("status must be verified or rejected")
+	}
+
+	document.VerificationStatus = req.Status
+	document.ReviewedBy = &adminID
+	document.ReviewedAt = &now
+
+	if err := s.documentRepo.UpdateDocument(document); err != nil {
+		logrus.Errorf("Failed to review document: %v", err)
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// ReuploadDocument replaces a rejected or expired document and sends it for review again
+func (s *UserDocumentService) ReuploadDocument(userID uint, documentType models.DocumentType, fileURL, fileName string, fileSize int64) (*models.UserDocument, error) {
+	document, err := s.documentRepo.GetDocumentByUserAndType(userID, documentType)
+	if err != nil {
+		return nil, errors.New("document not found")
+	}
+
+	if document.VerificationStatus != models.DocumentVerificationStatusRejected &&
+		document.VerificationStatus != models.DocumentVerificationStatusExpired &&
+		!document.IsExpired() {
+		return nil, errors.New("only rejected or expired documents can be re-uploaded")
+	}
+
+	document.FileURL = fileURL
+	document.FileName = fileName
+	document.FileSize = fileSize
+	resetDocumentReview(document)
+
+	if err := s.documentRepo.UpdateDocument(document); err != nil {
+		logrus.Errorf("Failed to update document: %v", err)
+		return nil, err
+	}
+
+	if err := s.updateProfileDocument(userID, documentType, fileURL); err != nil {
+		logrus.Errorf("Failed to update profile documents for user %d: %v", userID, err)
+	}
+
+	return document, nil
+}
+
+// updateProfileDocument keeps the documents JSON of the worker or broker profile in step with a re-uploaded document
+func (s *UserDocumentService) updateProfileDocument(userID uint, documentType models.DocumentType, fileURL string) error {
+	key := ""
+	for jsonKey, applicationType := range models.ApplicationDocumentTypes {
+		if applicationType == documentType {
+			key = jsonKey
+		}
+	}
+	if key == "" {
+		return nil
+	}
+
+	if worker, err := s.workerRepo.GetByUserID(userID); err == nil {
+		documentsJSON, err := setDocumentURL(worker.Documents, key, fileURL)
+		if err != nil {
+			return err
+		}
+		worker.Documents = documentsJSON
+		return s.workerRepo.Update(worker)
+	}
+
+	if broker, err := s.brokerRepo.GetByUserID(userID); err == nil {
+		documentsJSON, err := setDocumentURL(broker.Documents, key, fileURL)
+		if err != nil {
+			return err
+		}
+		broker.Documents = documentsJSON
+		return s.brokerRepo.Update(broker)
+	}
+
+	return nil
+}
+
+// resetDocumentReview sends a document back for review. The expiry date is kept, so a worker
+// whose document expired stays blocked until the replacement is verified.
+func resetDocumentReview(document *models.UserDocument) {
+	document.VerificationStatus = models.DocumentVerificationStatusPending
+	document.RejectionReason = ""
+	document.ReviewedBy = nil
+	document.ReviewedAt = nil
+}
+
+// setDocumentURL sets one document in a documents JSON object
+func setDocumentURL(documentsJSON, key, fileURL string) (string, error) {
+	files := make(map[string]string)
+	if documentsJSON != "" {
+		if err := json.Unmarshal([]byte(documentsJSON), &files); err != nil {
+			return "", fmt.Errorf("invalid documents: %v", err)
+		}
+	}
+	files[key] = fileURL
+
+	updated, err := json.Marshal(files)
+	if err != nil {
+		return "", err
+	}
+	return string(updated), nil
+}
+
+// applicationDocumentTypes lists the document types collected by role applications
+func applicationDocumentTypes() []models.DocumentType {
+	documentTypes := make([]models.DocumentType, 0, len(models.ApplicationDocumentTypes))
+	for _, documentType := range models.ApplicationDocumentTypes {
+		documentTypes = append(documentTypes, documentType)
+	}
+	return documentTypes
+}
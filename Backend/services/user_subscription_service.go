@@ -18,6 +18,7 @@ type UserSubscriptionService struct {
 	userRepo           *repositories.UserRepository
 	subscriptionCache  *utils.SubscriptionCache
 	notificationService *NotificationService
+	couponService      *CouponService
 }
 
 // NewUserSubscriptionService creates a new user subscription service
@@ -27,6 +28,7 @@ func NewUserSubscriptionService() *UserSubscriptionService {
 		userRepo:           repositories.NewUserRepository(),
 		subscriptionCache:  utils.NewSubscriptionCache(),
 		notificationService: NewNotificationService(),
+		couponService:      NewCouponService(),
 	}
 }
 
@@ -84,7 +86,7 @@ func (uss *UserSubscriptionService) CheckAndUpdateSubscriptionStatus(userID uint
 }
 
 // CreateSubscriptionPaymentOrder creates a payment order for subscription purchase
-func (uss *UserSubscriptionService) CreateSubscriptionPaymentOrder(userID uint, planID uint, durationType string) (*models.Payment, map[string]interface{}, error) {
+func (uss *UserSubscriptionService) CreateSubscriptionPaymentOrder(userID uint, planID uint, durationType string, couponCode string) (*models.Payment, map[string]interface{}, error) {
 	// Check current subscription status before purchasing
 	user, err := uss.CheckAndUpdateSubscriptionStatus(userID)
 	if err != nil {
@@ -137,12 +139,37 @@ func (uss *UserSubscriptionService) CreateSubscriptionPaymentOrder(userID uint,
 			"duration_days": selectedPricing.DurationDays,
 		},
 	}
+
+	// Apply promo code; a new order replaces any earlier unpaid one for this plan
+	uss.couponService.Release("subscription", planID, userID)
+	var redemption *models.CouponRedemption
+	if couponCode != "" {
+		checkout := &CouponCheckout{
+			UserID: userID,
+			Scope:  models.CouponScopeSubscription,
+			Amount: selectedPricing.Price,
+		}
+		discount, err := uss.couponService.CalculateDiscount(couponCode, checkout)
+		if err != nil {
+			return nil, nil, err
+		}
+		redemption, err = uss.couponService.Reserve(discount, checkout, "subscription", planID)
+		if err != nil {
+			return nil, nil, err
+		}
+		paymentReq.Amount = discount.FinalAmount
+		paymentReq.Metadata = discount.PaymentMetadata(paymentReq.Metadata)
+	}
 	
 	// Create Razorpay order
 	payment, razorpayOrder, err := paymentService.CreateRazorpayOrder(paymentReq)
 	if err != nil {
+		uss.couponService.Release("subscription", planID, userID)
 		return nil, nil, fmt.Errorf("failed to create payment order: %v", err)
 	}
+	if redemption != nil {
+		uss.couponService.AttachPayment(redemption, payment.ID)
+	}
 	
 	return payment, razorpayOrder, nil
 }
@@ -226,7 +253,7 @@ func (uss *UserSubscriptionService) CompleteSubscriptionPurchase(userID uint, pa
 		Status:        models.SubscriptionStatusActive,
 		PaymentMethod: models.PaymentMethodRazorpay,
 		PaymentID:     razorpayPaymentID,
-		Amount:        selectedPricing.Price,
+		Amount:        payment.Amount, // Amount charged, after any coupon discount
 	}
 	
 	// Save subscription and update user in transaction
@@ -260,7 +287,7 @@ func (uss *UserSubscriptionService) CompleteSubscriptionPurchase(userID uint, pa
 }
 
 // PurchaseSubscription purchases a subscription for a user (wallet only)
-func (uss *UserSubscriptionService) PurchaseSubscription(userID uint, planID uint, paymentMethod string, durationType string) (*models.UserSubscription, error) {
+func (uss *UserSubscriptionService) PurchaseSubscription(userID uint, planID uint, paymentMethod string, durationType string, couponCode string) (*models.UserSubscription, error) {
 	// Check current subscription status before purchasing
 	user, err := uss.CheckAndUpdateSubscriptionStatus(userID)
 	if err != nil {
@@ -302,17 +329,45 @@ func (uss *UserSubscriptionService) PurchaseSubscription(userID uint, planID uin
 	// Calculate end date based on duration_days
 	endDate := startDate.AddDate(0, 0, selectedPricing.DurationDays)
 	
+	// Apply promo code
+	price := selectedPricing.Price
+	var discount *CouponDiscount
+	checkout := &CouponCheckout{
+		UserID: userID,
+		Scope:  models.CouponScopeSubscription,
+		Amount: price,
+	}
+	if couponCode != "" {
+		discount, err = uss.couponService.CalculateDiscount(couponCode, checkout)
+		if err != nil {
+			return nil, err
+		}
+		price = discount.FinalAmount
+	}
+
 	// Process payment based on method
 	var paymentID string
+	var redemption *models.CouponRedemption
 	if paymentMethod == models.PaymentMethodWallet {
 		// Check wallet balance
-		if user.WalletBalance < selectedPricing.Price {
+		if user.WalletBalance < price {
 			return nil, errors.New("insufficient wallet balance")
 		}
+
+		// Reserve the coupon before charging the wallet
+		if discount != nil {
+			redemption, err = uss.couponService.Reserve(discount, checkout, "subscription", planID)
+			if err != nil {
+				return nil, err
+			}
+		}
 		
 		// Deduct from wallet
-		user.WalletBalance -= selectedPricing.Price
+		user.WalletBalance -= price
 		if err := uss.userRepo.Update(user); err != nil {
+			if redemption != nil {
+				uss.couponService.Release("subscription", planID, userID)
+			}
 			return nil, err
 		}
 	} else if paymentMethod == models.PaymentMethodRazorpay {
@@ -332,7 +387,7 @@ func (uss *UserSubscriptionService) PurchaseSubscription(userID uint, planID uin
 		Status:        models.SubscriptionStatusActive,
 		PaymentMethod: paymentMethod,
 		PaymentID:     paymentID,
-		Amount:        selectedPricing.Price,
+		Amount:        price,
 	}
 	
 	// Save subscription and update user in transaction
@@ -354,8 +409,16 @@ func (uss *UserSubscriptionService) PurchaseSubscription(userID uint, planID uin
 	})
 	
 	if err != nil {
+		if redemption != nil {
+			uss.couponService.Release("subscription", planID, userID)
+		}
 		return nil, err
 	}
+
+	// The wallet has been charged, so the coupon is used
+	if redemption != nil {
+		uss.couponService.ApplyRedemption(redemption)
+	}
 	
 	// Invalidate cache
 	uss.subscriptionCache.Invalidate(userID)
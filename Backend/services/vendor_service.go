@@ -18,22 +18,22 @@ type VendorService struct {
 	vendorRepo       *repositories.VendorRepository
 	userRepo         *repositories.UserRepository
 	validationHelper *utils.ValidationHelper
-	cloudinary       *CloudinaryService
+	mediaStore       MediaStore
 }
 
 // NewVendorService creates a new vendor service
 func NewVendorService() *VendorService {
-	cloudinaryService, err := NewCloudinaryService()
+	mediaStore, err := NewMediaStore()
 	if err != nil {
-		logrus.Warnf("Failed to initialize Cloudinary service: %v", err)
-		cloudinaryService = nil
+		logrus.Warnf("Failed to initialize media store: %v", err)
+		mediaStore = nil
 	}
 
 	return &VendorService{
 		vendorRepo:       repositories.NewVendorRepository(),
 		userRepo:         repositories.NewUserRepository(),
 		validationHelper: utils.NewValidationHelper(),
-		cloudinary:       cloudinaryService,
+		mediaStore:       mediaStore,
 	}
 }
 
@@ -351,9 +351,9 @@ func (vs *VendorService) UploadProfilePicture(vendorID, userID uint, file *multi
 		return nil, errors.New("unauthorized: you can only update your own vendor profiles")
 	}
 
-	// Upload to Cloudinary if available
-	if vs.cloudinary != nil {
-		url, err := vs.cloudinary.UploadImage(file, "vendors/profiles")
+	// Upload to media storage if available
+	if vs.mediaStore != nil {
+		url, err := vs.mediaStore.UploadImage(file, "vendors/profiles")
 		if err != nil {
 			return nil, fmt.Errorf("failed to upload image: %w", err)
 		}
@@ -388,9 +388,9 @@ func (vs *VendorService) UploadGalleryImages(vendorID, userID uint, file *multip
 		return nil, errors.New("unauthorized: you can only update your own vendor profiles")
 	}
 
-	// Upload to Cloudinary if available
-	if vs.cloudinary != nil {
-		url, err := vs.cloudinary.UploadImage(file, "vendors/gallery")
+	// Upload to media storage if available
+	if vs.mediaStore != nil {
+		url, err := vs.mediaStore.UploadImage(file, "vendors/gallery")
 		if err != nil {
 			return nil, fmt.Errorf("failed to upload image: %w", err)
 		}
@@ -444,7 +444,7 @@ func (vs *VendorService) GetVendorStatsForUser(userID uint) (map[string]interfac
 	return stats, nil
 }
 
-// GetCloudinaryService returns the cloudinary service instance
-func (vs *VendorService) GetCloudinaryService() *CloudinaryService {
-	return vs.cloudinary
+// GetMediaStore returns the media store instance
+func (vs *VendorService) GetMediaStore() MediaStore {
+	return vs.mediaStore
 }
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"treesindia/metrics"
 
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
@@ -14,11 +15,11 @@ import (
 
 // WebSocket message types
 const (
-	MessageTypeJoin     = "join"
-	MessageTypeLeave    = "leave"
-	MessageTypeMessage  = "message"
-	MessageTypePing     = "ping"
-	MessageTypePong     = "pong"
+	MessageTypeJoin    = "join"
+	MessageTypeLeave   = "leave"
+	MessageTypeMessage = "message"
+	MessageTypePing    = "ping"
+	MessageTypePong    = "pong"
 )
 
 // WebSocket message structure
@@ -33,13 +34,13 @@ type WSMessage struct {
 
 // Client represents a WebSocket client
 type Client struct {
-	ID       string
-	UserID   uint
-	RoomID   uint
-	Conn     *websocket.Conn
-	Send     chan []byte
-	Hub      *Hub
-	mu       sync.Mutex
+	ID     string
+	UserID uint
+	RoomID uint
+	Conn   *websocket.Conn
+	Send   chan []byte
+	Hub    *Hub
+	mu     sync.Mutex
 }
 
 // Hub manages all WebSocket connections
@@ -56,17 +57,35 @@ type Hub struct {
 	// Broadcast messages to specific room
 	broadcast chan *WSMessage
 
+	// Fans room messages out to hubs running on other instances
+	pubSub RealtimePubSub
+
 	mu sync.RWMutex
 }
 
+// hubEnvelope wraps a room message published to other instances
+type hubEnvelope struct {
+	Origin  string     `json:"origin"`
+	Message *WSMessage `json:"message"`
+}
+
 // NewHub creates a new WebSocket hub
-func NewHub() *Hub {
-	return &Hub{
-		rooms:        make(map[uint]map[*Client]bool),
-		register:     make(chan *Client),
-		unregister:   make(chan *Client),
-		broadcast:    make(chan *WSMessage),
+func NewHub(pubSub RealtimePubSub) *Hub {
+	hub := &Hub{
+		rooms:      make(map[uint]map[*Client]bool),
+		register:   make(chan *Client),
+		unregister: make(chan *Client),
+		broadcast:  make(chan *WSMessage, realtimeBroadcastBuffer),
+		pubSub:     pubSub,
 	}
+
+	if pubSub != nil {
+		if err := pubSub.Subscribe(RealtimeChannelChatHub, hub.handleRemoteMessage); err != nil {
+			logrus.Errorf("Failed to subscribe chat hub to realtime pub/sub: %v", err)
+		}
+	}
+
+	return hub
 }
 
 // Run starts the hub
@@ -89,6 +108,7 @@ func (h *Hub) Run() {
 				Timestamp: time.Now(),
 			}
 			h.broadcastToRoom(joinMsg)
+			h.publish(joinMsg)
 
 			logrus.Infof("Client %s joined room %d", client.ID, client.RoomID)
 
@@ -113,6 +133,7 @@ func (h *Hub) Run() {
 				Timestamp: time.Now(),
 			}
 			h.broadcastToRoom(leaveMsg)
+			h.publish(leaveMsg)
 
 			logrus.Infof("Client %s left room %d", client.ID, client.RoomID)
 
@@ -122,6 +143,18 @@ func (h *Hub) Run() {
 	}
 }
 
+// ConnectionCount returns the number of clients connected to this instance
+func (h *Hub) ConnectionCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	count := 0
+	for _, clients := range h.rooms {
+		count += len(clients)
+	}
+	return count
+}
+
 // broadcastToRoom sends a message to all clients in a specific room
 func (h *Hub) broadcastToRoom(message *WSMessage) {
 	h.mu.RLock()
@@ -129,7 +162,8 @@ func (h *Hub) broadcastToRoom(message *WSMessage) {
 	h.mu.RUnlock()
 
 	if !exists {
-		logrus.Warnf("Room %d does not exist for broadcasting", message.RoomID)
+		// Clients of this room may be connected to another instance
+		logrus.Debugf("Room %d has no local clients for broadcasting", message.RoomID)
 		return
 	}
 
@@ -153,7 +187,7 @@ func (h *Hub) broadcastToRoom(message *WSMessage) {
 			delete(room, client)
 		}
 	}
-	
+
 	logrus.Infof("Successfully broadcasted message to %d/%d clients in room %d", successCount, clientCount, message.RoomID)
 }
 
@@ -166,6 +200,47 @@ func (h *Hub) BroadcastMessage(roomID uint, messageType string, data map[string]
 		Timestamp: time.Now(),
 	}
 	h.broadcast <- msg
+	h.publish(msg)
+}
+
+// publish sends a locally originated message to hubs on other instances
+func (h *Hub) publish(message *WSMessage) {
+	if h.pubSub == nil {
+		return
+	}
+
+	data, err := json.Marshal(hubEnvelope{
+		Origin:  h.pubSub.InstanceID(),
+		Message: message,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to marshal hub message for pub/sub: %v", err)
+		return
+	}
+
+	if err := h.pubSub.Publish(RealtimeChannelChatHub, data); err != nil {
+		logrus.Errorf("Failed to publish hub message for room %d: %v", message.RoomID, err)
+	}
+}
+
+// handleRemoteMessage delivers a message published by another instance to local clients
+func (h *Hub) handleRemoteMessage(payload []byte) {
+	var envelope hubEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		logrus.Errorf("Failed to unmarshal hub message from pub/sub: %v", err)
+		return
+	}
+
+	// Messages from this instance were already delivered locally
+	if envelope.Origin == h.pubSub.InstanceID() || envelope.Message == nil {
+		return
+	}
+
+	select {
+	case h.broadcast <- envelope.Message:
+	default:
+		logrus.Warnf("Chat hub broadcast queue is full, dropping message for room %d from another instance", envelope.Message.RoomID)
+	}
 }
 
 // readPump reads messages from the WebSocket connection
@@ -249,6 +324,7 @@ func (c *Client) readPump() {
 			wsMsg.UserID = c.UserID
 			wsMsg.RoomID = c.RoomID
 			c.Hub.broadcast <- &wsMsg
+			c.Hub.publish(&wsMsg)
 
 		default:
 			logrus.Infof("Received WebSocket message of type: %s", wsMsg.Type)
@@ -308,10 +384,12 @@ type WebSocketService struct {
 }
 
 // NewWebSocketService creates a new WebSocket service
-func NewWebSocketService() *WebSocketService {
-	hub := NewHub()
+func NewWebSocketService(pubSub RealtimePubSub) *WebSocketService {
+	hub := NewHub(pubSub)
 	go hub.Run()
 
+	metrics.RegisterWebSocketHub("chat", hub.ConnectionCount)
+
 	return &WebSocketService{
 		hub: hub,
 	}
@@ -377,4 +455,7 @@ func (ws *WebSocketService) BroadcastChatMessage(roomID uint, message map[string
 	ws.hub.BroadcastMessage(roomID, MessageTypeMessage, message)
 }
 
-
+// BroadcastRoomMessage broadcasts a message of any type to all clients in the room
+func (ws *WebSocketService) BroadcastRoomMessage(roomID uint, messageType string, data map[string]interface{}) {
+	ws.hub.BroadcastMessage(roomID, messageType, data)
+}
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"crypto/subtle"
 	"errors"
+	"fmt"
 	"time"
 	"treesindia/models"
 	"treesindia/repositories"
@@ -19,9 +21,14 @@ type WorkerAssignmentService struct {
 	chatService          *ChatService
 	callMaskingService   *CallMaskingService
 	walletService        *UnifiedWalletService
+	milestoneService     *PaymentSegmentMilestoneService
+	referralService      *ReferralService
+	trackingService      *LocationTrackingService
+	adminConfigService   *AdminConfigService
+	assignmentOfferRepo  *repositories.AssignmentOfferRepository
 }
 
-func NewWorkerAssignmentService(chatService *ChatService) *WorkerAssignmentService {
+func NewWorkerAssignmentService(chatService *ChatService, trackingService *LocationTrackingService) *WorkerAssignmentService {
 	return &WorkerAssignmentService{
 		workerAssignmentRepo: repositories.NewWorkerAssignmentRepository(),
 		bookingRepo:          repositories.NewBookingRepository(),
@@ -32,6 +39,11 @@ func NewWorkerAssignmentService(chatService *ChatService) *WorkerAssignmentServi
 		chatService:          chatService,
 		callMaskingService:   NewCallMaskingService(),
 		walletService:        NewUnifiedWalletService(),
+		milestoneService:     NewPaymentSegmentMilestoneService(),
+		referralService:      NewReferralService(),
+		trackingService:      trackingService,
+		adminConfigService:   NewAdminConfigService(),
+		assignmentOfferRepo:  repositories.NewAssignmentOfferRepository(),
 	}
 }
 
@@ -184,6 +196,10 @@ func (was *WorkerAssignmentService) AcceptAssignment(assignmentID uint, workerID
 		return nil, errors.New("failed to accept assignment")
 	}
 
+	if err := was.assignmentOfferRepo.RespondToOffer(assignment.ID, workerID, models.AssignmentOfferStatusAccepted, notes); err != nil {
+		logrus.Errorf("Failed to record acceptance of assignment %d: %v", assignment.ID, err)
+	}
+
 	// Update booking status
 	booking, err := was.bookingRepo.GetByID(assignment.BookingID)
 	if err != nil {
@@ -257,6 +273,11 @@ func (was *WorkerAssignmentService) RejectAssignment(assignmentID uint, workerID
 		return nil, errors.New("failed to reject assignment")
 	}
 
+	// The booking is offered to the next worker by the WorkerReassignmentService
+	if err := was.assignmentOfferRepo.RespondToOffer(assignment.ID, workerID, models.AssignmentOfferStatusRejected, reason); err != nil {
+		logrus.Errorf("Failed to record rejection of assignment %d: %v", assignment.ID, err)
+	}
+
 	// Update booking status back to confirmed
 	booking, err := was.bookingRepo.GetByID(assignment.BookingID)
 	if err != nil {
@@ -292,8 +313,8 @@ func (was *WorkerAssignmentService) RejectAssignment(assignmentID uint, workerID
 	return assignment, nil
 }
 
-// StartAssignment starts an assignment
-func (was *WorkerAssignmentService) StartAssignment(assignmentID uint, workerID uint, notes string) (*models.WorkerAssignment, error) {
+// StartAssignment starts an assignment once the worker enters the customer's start PIN
+func (was *WorkerAssignmentService) StartAssignment(assignmentID uint, workerID uint, pin string, notes string) (*models.WorkerAssignment, error) {
 	// Get the assignment
 	assignment, err := was.workerAssignmentRepo.GetByID(assignmentID)
 	if err != nil {
@@ -310,6 +331,16 @@ func (was *WorkerAssignmentService) StartAssignment(assignmentID uint, workerID
 		return nil, errors.New("assignment cannot be started in current status")
 	}
 
+	// Check that no unpaid segment holds the start of work
+	if err := was.milestoneService.CheckGate(assignment.BookingID, models.PaymentSegmentGateWorkStart); err != nil {
+		return nil, err
+	}
+
+	// The customer gives the worker the start PIN once they are on site
+	if err := was.verifyServicePIN(&assignment.Booking, assignment.Booking.StartPIN, pin); err != nil {
+		return nil, err
+	}
+
 	// Update assignment
 	now := time.Now()
 	assignment.Status = models.AssignmentStatusInProgress
@@ -336,6 +367,9 @@ func (was *WorkerAssignmentService) StartAssignment(assignmentID uint, workerID
 		return nil, errors.New("failed to update booking status")
 	}
 
+	// Segments due on work start fall due now
+	was.milestoneService.ActivateTrigger(assignment.BookingID, models.PaymentSegmentDueOnWorkStart)
+
 	// Send in-app notification to user about work started
 	go func() {
 		// Get worker and service details for notification
@@ -352,8 +386,8 @@ func (was *WorkerAssignmentService) StartAssignment(assignmentID uint, workerID
 	return assignment, nil
 }
 
-// CompleteAssignment completes an assignment
-func (was *WorkerAssignmentService) CompleteAssignment(assignmentID uint, workerID uint, notes string, materialsUsed []string, photos []string) (*models.WorkerAssignment, error) {
+// CompleteAssignment completes an assignment once the worker enters the customer's completion PIN
+func (was *WorkerAssignmentService) CompleteAssignment(assignmentID uint, workerID uint, pin string, notes string, materialsUsed []string, photos []string) (*models.WorkerAssignment, error) {
 	// Get the assignment
 	assignment, err := was.workerAssignmentRepo.GetByID(assignmentID)
 	if err != nil {
@@ -370,16 +404,122 @@ func (was *WorkerAssignmentService) CompleteAssignment(assignmentID uint, worker
 		return nil, errors.New("assignment cannot be completed in current status")
 	}
 
+	// Check that no unpaid segment holds the completion of work
+	if err := was.milestoneService.CheckGate(assignment.BookingID, models.PaymentSegmentGateCompletion); err != nil {
+		return nil, err
+	}
+
+	// The customer gives the worker the completion PIN once they are satisfied with the work
+	if err := was.verifyServicePIN(&assignment.Booking, assignment.Booking.CompletionPIN, pin); err != nil {
+		return nil, err
+	}
+
+	return was.finishAssignment(assignment, models.CompletionTypeManual, nil, notes, materialsUsed, photos)
+}
+
+// ForceCompleteBooking completes the service of a booking without the completion PIN, for when the
+// customer cannot confirm it. Payment gates are not checked; segments due on completion still fall
+// due.
+func (was *WorkerAssignmentService) ForceCompleteBooking(bookingID uint, adminID uint, reason string) (*models.WorkerAssignment, error) {
+	assignment, err := was.workerAssignmentRepo.GetByBookingID(bookingID)
+	if err != nil {
+		return nil, errors.New("no worker is assigned to this booking")
+	}
+
+	if assignment.Status != models.AssignmentStatusAccepted && assignment.Status != models.AssignmentStatusInProgress {
+		return nil, errors.New("assignment cannot be completed in current status")
+	}
+
+	logrus.Infof("Admin %d force-completing assignment %d for booking %d: %s", adminID, assignment.ID, bookingID, reason)
+	return was.finishAssignment(assignment, models.CompletionTypeAdminForced, &adminID, reason, nil, nil)
+}
+
+// CompleteExpiredAssignments completes services that were started but never completed within the
+// configured number of hours. Services held by an unpaid payment segment are left for an admin.
+func (was *WorkerAssignmentService) CompleteExpiredAssignments() ([]models.WorkerAssignment, error) {
+	hours := was.adminConfigService.GetServiceAutoCompleteHours()
+	assignments, err := was.workerAssignmentRepo.GetInProgressStartedBefore(time.Now().Add(-time.Duration(hours) * time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired assignments: %v", err)
+	}
+
+	var completed []models.WorkerAssignment
+	for i := range assignments {
+		assignment := &assignments[i]
+		if err := was.milestoneService.CheckGate(assignment.BookingID, models.PaymentSegmentGateCompletion); err != nil {
+			logrus.Warnf("Not auto-completing assignment %d: %v", assignment.ID, err)
+			continue
+		}
+
+		reason := fmt.Sprintf("Completed automatically %d hours after the service started", hours)
+		if _, err := was.finishAssignment(assignment, models.CompletionTypeTimeExpired, nil, reason, nil, nil); err != nil {
+			logrus.Errorf("Failed to auto-complete assignment %d: %v", assignment.ID, err)
+			continue
+		}
+		completed = append(completed, *assignment)
+	}
+	return completed, nil
+}
+
+// verifyServicePIN checks a start or completion PIN entered by the worker. Every attempt is counted
+// before the PIN is compared, and too many incorrect PINs lock PIN entry for the booking for a while.
+func (was *WorkerAssignmentService) verifyServicePIN(booking *models.Booking, expected *string, pin string) error {
+	if expected == nil {
+		return errors.New("this booking has no service PIN, please contact support")
+	}
+
+	attempts, err := was.bookingRepo.CountServicePINAttempt(booking.ID)
+	if err != nil {
+		logrus.Errorf("Failed to count service PIN attempt for booking %d: %v", booking.ID, err)
+		return errors.New("failed to verify PIN")
+	}
+	if attempts == 0 {
+		if now := time.Now(); booking.PINLockedUntil != nil && now.Before(*booking.PINLockedUntil) {
+			minutes := int(booking.PINLockedUntil.Sub(now).Minutes()) + 1
+			return fmt.Errorf("too many incorrect PINs, try again in %d minutes", minutes)
+		}
+		return errors.New("too many incorrect PINs, try again later")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(*expected), []byte(pin)) != 1 {
+		if attempts >= was.adminConfigService.GetServicePINMaxAttempts() {
+			lockUntil := time.Now().Add(time.Duration(was.adminConfigService.GetServicePINLockMinutes()) * time.Minute)
+			if err := was.bookingRepo.LockServicePINEntry(booking.ID, lockUntil); err != nil {
+				logrus.Errorf("Failed to lock service PIN entry for booking %d: %v", booking.ID, err)
+			}
+			return errors.New("incorrect PIN, too many attempts so PIN entry is locked for a while")
+		}
+		return errors.New("incorrect PIN")
+	}
+
+	if err := was.bookingRepo.ResetServicePINAttempts(booking.ID); err != nil {
+		logrus.Errorf("Failed to reset service PIN attempts for booking %d: %v", booking.ID, err)
+	}
+	return nil
+}
+
+// finishAssignment completes an assignment and its booking, credits the worker and closes the
+// booking's chat and tracking
+func (was *WorkerAssignmentService) finishAssignment(assignment *models.WorkerAssignment, completionType models.CompletionType, forcedBy *uint, reason string, materialsUsed []string, photos []string) (*models.WorkerAssignment, error) {
+	assignmentID := assignment.ID
+
 	// Update assignment
 	now := time.Now()
+	if assignment.StartedAt == nil {
+		assignment.StartedAt = &now
+	}
 	assignment.Status = models.AssignmentStatusCompleted
 	assignment.CompletedAt = &now
 
-	err = was.workerAssignmentRepo.Update(assignment)
+	// Only the caller that completes the assignment goes on to credit the worker
+	completed, err := was.workerAssignmentRepo.Complete(assignment, now)
 	if err != nil {
 		logrus.Errorf("Failed to complete assignment: %v", err)
 		return nil, errors.New("failed to complete assignment")
 	}
+	if !completed {
+		return nil, errors.New("assignment cannot be completed in current status")
+	}
 
 	// Update booking status
 	booking, err := was.bookingRepo.GetByID(assignment.BookingID)
@@ -389,6 +529,11 @@ func (was *WorkerAssignmentService) CompleteAssignment(assignmentID uint, worker
 	}
 
 	booking.Status = models.BookingStatusCompleted
+	booking.CompletionType = &completionType
+	booking.CompletionForcedBy = forcedBy
+	if completionType != models.CompletionTypeManual {
+		booking.CompletionReason = reason
+	}
 	booking.ActualEndTime = &now
 	
 	// Calculate actual duration if start time is available
@@ -403,9 +548,20 @@ func (was *WorkerAssignmentService) CompleteAssignment(assignmentID uint, worker
 		return nil, errors.New("failed to update booking status")
 	}
 
+	// Segments due on completion fall due now
+	was.milestoneService.ActivateTrigger(assignment.BookingID, models.PaymentSegmentDueOnCompletion)
+
+	// Reward the referral if this was the customer's first completed booking
+	go was.referralService.RewardFirstCompletedBooking(booking)
+
 	// Disable call masking when assignment is completed
 	go was.callMaskingService.DisableCallMasking(assignment.BookingID)
 
+	// Stop sharing the worker's location with the customer
+	if was.trackingService != nil {
+		go was.trackingService.CompleteTracking(assignment)
+	}
+
 	// Update worker statistics
 	worker, err := was.workerRepo.GetByUserID(assignment.WorkerID)
 	if err != nil {
@@ -415,10 +571,13 @@ func (was *WorkerAssignmentService) CompleteAssignment(assignmentID uint, worker
 		// Calculate earnings from booking
 		earnings := 0.0
 
-		// Get earnings from quote amount (for inquiry bookings) or service price (for regular bookings)
+		// Get earnings from quote amount (for inquiry bookings) or booked price (for regular bookings)
 		if booking.QuoteAmount != nil {
 			earnings = *booking.QuoteAmount
 			logrus.Infof("Assignment %d earnings from quote_amount: ₹%.2f", assignmentID, earnings)
+		} else if booking.PriceBreakdown != nil {
+			earnings = booking.PriceBreakdown.Total
+			logrus.Infof("Assignment %d earnings from booked price: ₹%.2f", assignmentID, earnings)
 		} else if booking.Service.ID != 0 && booking.Service.Price != nil {
 			earnings = *booking.Service.Price
 			logrus.Infof("Assignment %d earnings from service price: ₹%.2f", assignmentID, earnings)
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+
+	"github.com/sirupsen/logrus"
+)
+
+// acceptanceStatsWindow is how far back offers count towards a worker's acceptance rate
+const acceptanceStatsWindow = 90 * 24 * time.Hour
+
+// WorkerReassignmentService expires assignments that are not accepted within the acceptance SLA and
+// offers rejected or expired bookings to the next eligible worker, escalating to admins when no
+// worker takes the booking
+type WorkerReassignmentService struct {
+	bookingService              *BookingService
+	bookingRepo                 *repositories.BookingRepository
+	workerAssignmentRepo        *repositories.WorkerAssignmentRepository
+	assignmentOfferRepo         *repositories.AssignmentOfferRepository
+	userRepo                    *repositories.UserRepository
+	adminConfigService          *AdminConfigService
+	enhancedNotificationService *EnhancedNotificationService
+	stopChan                    chan bool
+}
+
+func NewWorkerReassignmentService(enhancedNotificationService *EnhancedNotificationService) *WorkerReassignmentService {
+	return &WorkerReassignmentService{
+		bookingService:              NewBookingService(enhancedNotificationService),
+		bookingRepo:                 repositories.NewBookingRepository(),
+		workerAssignmentRepo:        repositories.NewWorkerAssignmentRepository(),
+		assignmentOfferRepo:         repositories.NewAssignmentOfferRepository(),
+		userRepo:                    repositories.NewUserRepository(),
+		adminConfigService:          NewAdminConfigService(),
+		enhancedNotificationService: enhancedNotificationService,
+		stopChan:                    make(chan bool),
+	}
+}
+
+// Start begins the periodic expiry and reassignment of assignments
+func (wrs *WorkerReassignmentService) Start() {
+	logrus.Info("WorkerReassignmentService starting...")
+
+	// Run immediately on start
+	go wrs.processAssignments()
+
+	// Then run every minute, as acceptance SLAs are measured in minutes
+	ticker := time.NewTicker(1 * time.Minute)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				wrs.processAssignments()
+			case <-wrs.stopChan:
+				ticker.Stop()
+				logrus.Info("WorkerReassignmentService stopped")
+				return
+			}
+		}
+	}()
+
+	logrus.Info("WorkerReassignmentService started successfully")
+}
+
+// Stop stops the worker reassignment service
+func (wrs *WorkerReassignmentService) Stop() {
+	logrus.Info("Stopping WorkerReassignmentService...")
+	wrs.stopChan <- true
+}
+
+// GetEscalatedBookings gets bookings waiting for an admin to assign a worker
+func (wrs *WorkerReassignmentService) GetEscalatedBookings() ([]models.Booking, error) {
+	return wrs.bookingRepo.GetEscalatedAssignmentBookings()
+}
+
+// GetBookingOffers gets the workers a booking was offered to and how they responded
+func (wrs *WorkerReassignmentService) GetBookingOffers(bookingID uint) ([]models.AssignmentOffer, error) {
+	return wrs.assignmentOfferRepo.GetBookingOffers(bookingID)
+}
+
+// GetWorkerAcceptanceStats gets how a worker responded to the bookings offered to them recently
+func (wrs *WorkerReassignmentService) GetWorkerAcceptanceStats(workerID uint) (*models.WorkerAcceptanceStats, error) {
+	stats, err := wrs.assignmentOfferRepo.GetAcceptanceStats([]uint{workerID}, time.Now().Add(-acceptanceStatsWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get acceptance stats: %v", err)
+	}
+	workerStats := stats[workerID]
+	return &workerStats, nil
+}
+
+// processAssignments expires unaccepted assignments, then reassigns rejected ones
+func (wrs *WorkerReassignmentService) processAssignments() {
+	expired, err := wrs.workerAssignmentRepo.GetUnacceptedPastDeadline(time.Now())
+	if err != nil {
+		logrus.Errorf("WorkerReassignmentService: failed to get unaccepted assignments: %v", err)
+	}
+	for i := range expired {
+		wrs.expireAssignment(&expired[i])
+	}
+
+	rejected, err := wrs.workerAssignmentRepo.GetRejectedAwaitingReassignment()
+	if err != nil {
+		logrus.Errorf("WorkerReassignmentService: failed to get rejected assignments: %v", err)
+		return
+	}
+	for i := range rejected {
+		wrs.reassign(&rejected[i])
+	}
+
+	if len(expired) > 0 || len(rejected) > 0 {
+		logrus.Infof("WorkerReassignmentService: %d assignments expired, %d bookings reassigned or escalated", len(expired), len(rejected))
+	}
+}
+
+// expireAssignment treats an assignment the worker did not accept in time as rejected. Nothing
+// changes if the worker accepted or the booking was cancelled since the assignment was read.
+func (wrs *WorkerReassignmentService) expireAssignment(assignment *models.WorkerAssignment) {
+	reason := "Not accepted in time"
+	expired, err := wrs.workerAssignmentRepo.ExpireUnaccepted(assignment, reason, time.Now())
+	if err != nil {
+		logrus.Errorf("WorkerReassignmentService: failed to expire assignment %d: %v", assignment.ID, err)
+		return
+	}
+	if !expired {
+		return
+	}
+
+	if err := wrs.assignmentOfferRepo.RespondToOffer(assignment.ID, assignment.WorkerID, models.AssignmentOfferStatusExpired, reason); err != nil {
+		logrus.Errorf("WorkerReassignmentService: failed to expire offer for assignment %d: %v", assignment.ID, err)
+	}
+
+	booking := &assignment.Booking
+
+	wrs.notify(assignment.WorkerID, "Assignment Expired",
+		fmt.Sprintf("Booking %s was offered to another worker because it was not accepted in time", booking.BookingReference),
+		map[string]string{
+			"type":          "worker_assignment",
+			"assignment_id": fmt.Sprintf("%d", assignment.ID),
+			"booking_id":    fmt.Sprintf("%d", booking.ID),
+			"status":        "expired",
+		})
+}
+
+// reassign offers a booking whose worker rejected or did not accept it to the next eligible worker
+func (wrs *WorkerReassignmentService) reassign(assignment *models.WorkerAssignment) {
+	booking := &assignment.Booking
+
+	offers, err := wrs.assignmentOfferRepo.GetBookingOffers(booking.ID)
+	if err != nil {
+		logrus.Errorf("WorkerReassignmentService: failed to get offers for booking %d: %v", booking.ID, err)
+		return
+	}
+
+	// Workers who already declined or let the offer expire are not asked again
+	declined := map[uint]bool{assignment.WorkerID: true}
+	failed := 0
+	for _, offer := range offers {
+		if offer.Status == models.AssignmentOfferStatusRejected || offer.Status == models.AssignmentOfferStatusExpired {
+			declined[offer.WorkerID] = true
+			failed++
+		}
+	}
+
+	maxOffers := wrs.adminConfigService.GetAssignmentMaxAutoOffers()
+	if failed >= maxOffers {
+		wrs.escalate(booking, fmt.Sprintf("%d workers declined or did not respond", failed))
+		return
+	}
+	if booking.ScheduledTime != nil && booking.ScheduledTime.Before(time.Now()) {
+		wrs.escalate(booking, "the scheduled time has passed")
+		return
+	}
+
+	candidates, err := wrs.rankCandidates(booking, declined)
+	if err != nil {
+		logrus.Errorf("WorkerReassignmentService: failed to find workers for booking %d: %v", booking.ID, err)
+		return
+	}
+
+	for _, workerID := range candidates {
+		_, err := wrs.bookingService.assignWorker(booking, workerID, assignment.AssignedBy, "Worker reassigned automatically")
+		if err == nil {
+			logrus.Infof("WorkerReassignmentService: booking %d offered to worker %d", booking.ID, workerID)
+			return
+		}
+		logrus.Warnf("WorkerReassignmentService: could not offer booking %d to worker %d: %v", booking.ID, workerID, err)
+	}
+
+	wrs.escalate(booking, "no other eligible worker is available")
+}
+
+// rankCandidates returns the workers a booking can be offered to, best first, leaving out workers
+// whose mandatory documents have expired. Workers are ranked by acceptance rate, then rating, then
+// fewest completed jobs to spread the work.
+func (wrs *WorkerReassignmentService) rankCandidates(booking *models.Booking, excluded map[uint]bool) ([]uint, error) {
+	var workers []models.User
+	err := wrs.userRepo.GetDB().Joins("JOIN workers ON users.id = workers.user_id").
+		Where("users.user_type = ? AND users.is_active = ? AND workers.worker_type = ? AND workers.deleted_at IS NULL",
+			models.UserTypeWorker, true, models.WorkerTypeTreesIndia).
+		Preload("Worker").
+		Find(&workers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workers: %v", err)
+	}
+
+	// Workers whose mandatory documents have expired cannot take new bookings
+	allWorkerIDs := make([]uint, len(workers))
+	for i, worker := range workers {
+		allWorkerIDs[i] = worker.ID
+	}
+	expiredDocuments, err := wrs.bookingService.userDocumentService.GetWorkersWithExpiredMandatoryDocuments(allWorkerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check worker documents: %v", err)
+	}
+
+	var startTime, endTime time.Time
+	if booking.ScheduledTime != nil {
+		startTime = *booking.ScheduledTime
+		endTime = startTime.Add(time.Duration(bookingDurationMinutes(booking)) * time.Minute)
+	}
+
+	var eligible []models.User
+	var workerIDs []uint
+	for _, worker := range workers {
+		if excluded[worker.ID] || expiredDocuments[worker.ID] || worker.Worker == nil {
+			continue
+		}
+		if booking.ScheduledTime != nil {
+			hasConflict, err := wrs.bookingService.checkWorkerBookingConflict(worker.ID, startTime, endTime)
+			if err != nil || hasConflict {
+				continue
+			}
+		}
+		eligible = append(eligible, worker)
+		workerIDs = append(workerIDs, worker.ID)
+	}
+	if len(eligible) == 0 {
+		return nil, nil
+	}
+
+	stats, err := wrs.assignmentOfferRepo.GetAcceptanceStats(workerIDs, time.Now().Add(-acceptanceStatsWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get acceptance stats: %v", err)
+	}
+
+	sort.SliceStable(eligible, func(i, j int) bool {
+		a, b := eligible[i], eligible[j]
+		scoreA, scoreB := stats[a.ID].RankingScore(), stats[b.ID].RankingScore()
+		if scoreA != scoreB {
+			return scoreA > scoreB
+		}
+		if a.Worker.Rating != b.Worker.Rating {
+			return a.Worker.Rating > b.Worker.Rating
+		}
+		return a.Worker.TotalJobs < b.Worker.TotalJobs
+	})
+
+	ranked := make([]uint, len(eligible))
+	for i, worker := range eligible {
+		ranked[i] = worker.ID
+	}
+	return ranked, nil
+}
+
+// escalate hands a booking to admins to assign a worker manually
+func (wrs *WorkerReassignmentService) escalate(booking *models.Booking, reason string) {
+	if err := wrs.bookingRepo.MarkAssignmentEscalated(booking.ID, time.Now()); err != nil {
+		logrus.Errorf("WorkerReassignmentService: failed to escalate booking %d: %v", booking.ID, err)
+		return
+	}
+	logrus.Warnf("WorkerReassignmentService: booking %d escalated to admins: %s", booking.ID, reason)
+
+	var admins []models.User
+	if err := wrs.userRepo.FindByUserType(&admins, models.UserTypeAdmin); err != nil {
+		logrus.Errorf("WorkerReassignmentService: failed to get admins: %v", err)
+		return
+	}
+
+	data := map[string]string{
+		"type":       "assignment_escalated",
+		"booking_id": fmt.Sprintf("%d", booking.ID),
+		"reason":     reason,
+	}
+	body := fmt.Sprintf("Booking %s needs a worker: %s", booking.BookingReference, reason)
+	for _, admin := range admins {
+		if admin.IsActive {
+			wrs.notify(admin.ID, "Worker Assignment Needed", body, data)
+		}
+	}
+}
+
+// notify sends a push notification without blocking the reassignment run
+func (wrs *WorkerReassignmentService) notify(userID uint, title, body string, data map[string]string) {
+	if wrs.enhancedNotificationService == nil {
+		return
+	}
+
+	go func() {
+		_, err := wrs.enhancedNotificationService.SendNotification(&NotificationRequest{
+			UserID:   userID,
+			Type:     models.NotificationTypeWorkerAssignment,
+			Title:    title,
+			Body:     body,
+			Data:     data,
+			Priority: "high",
+		})
+		if err != nil {
+			logrus.Errorf("WorkerReassignmentService: failed to notify user %d: %v", userID, err)
+		}
+	}()
+}
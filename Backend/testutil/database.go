@@ -0,0 +1,154 @@
+// Package testutil holds the test harness: a throwaway Postgres schema built from the goose
+// migrations, fakes for the external clients and stores the services depend on, and fixtures.
+package testutil
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"treesindia/database"
+
+	_ "github.com/lib/pq"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// DatabaseURLEnv names the environment variable holding the Postgres database tests may use
+const DatabaseURLEnv = "TEST_DATABASE_URL"
+
+var testDB *gorm.DB
+
+// Main runs the tests of a package and returns the exit code. When TEST_DATABASE_URL is set, the
+// tests run against a new schema in that database, migrated with the goose migrations and installed
+// as the database the repositories use. The schema is dropped once the tests finish. Without the
+// variable, tests that need a database are skipped.
+func Main(m *testing.M) int {
+	databaseURL := os.Getenv(DatabaseURLEnv)
+	if databaseURL == "" {
+		return m.Run()
+	}
+
+	cleanup, err := setupDatabase(databaseURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "testutil: failed to set up test database: %v\n", err)
+		return 1
+	}
+	defer cleanup()
+
+	return m.Run()
+}
+
+// RequireDB returns the test database, skipping the test when there is none
+func RequireDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	if testDB == nil {
+		t.Skipf("%s is not set, skipping database test", DatabaseURLEnv)
+	}
+	return testDB
+}
+
+// setupDatabase creates and migrates a schema for this test run and returns the function that drops it
+func setupDatabase(databaseURL string) (func(), error) {
+	admin, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := admin.Ping(); err != nil {
+		admin.Close()
+		return nil, err
+	}
+
+	if err := createExtensions(admin); err != nil {
+		admin.Close()
+		return nil, err
+	}
+
+	schema := fmt.Sprintf("test_%d_%d", os.Getpid(), time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		admin.Close()
+		return nil, fmt.Errorf("failed to create schema: %v", err)
+	}
+	dropSchema := func() {
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			fmt.Fprintf(os.Stderr, "testutil: failed to drop schema %s: %v\n", schema, err)
+		}
+		admin.Close()
+	}
+
+	schemaURL, err := withSearchPath(databaseURL, schema)
+	if err != nil {
+		dropSchema()
+		return nil, err
+	}
+
+	if err := migrate(schemaURL); err != nil {
+		dropSchema()
+		return nil, err
+	}
+
+	db, err := gorm.Open(postgres.Open(schemaURL), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+		SkipDefaultTransaction:                   true,
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		dropSchema()
+		return nil, err
+	}
+	database.SetDB(db)
+	testDB = db
+
+	return func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		dropSchema()
+	}, nil
+}
+
+// extensions are the Postgres extensions the migrations create
+var extensions = []string{"pg_trgm", "cube", "earthdistance"}
+
+// createExtensions installs the extensions in public before any test schema is migrated.
+// Extensions belong to the whole database, so if a migration created them they would land in the
+// schema of whichever test package got there first and be dropped with it. Test packages run in
+// parallel, so the extensions are created under a lock.
+func createExtensions(admin *sql.DB) error {
+	tx, err := admin.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('testutil_extensions'))"); err != nil {
+		return fmt.Errorf("failed to lock extensions: %v", err)
+	}
+	for _, extension := range extensions {
+		if _, err := tx.Exec("CREATE EXTENSION IF NOT EXISTS " + extension + " WITH SCHEMA public"); err != nil {
+			return fmt.Errorf("failed to create extension %s: %v", extension, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// withSearchPath points a connection string, in URL or keyword/value form, at schema. Extensions
+// already installed in public stay visible.
+func withSearchPath(databaseURL, schema string) (string, error) {
+	searchPath := schema + ",public"
+	if strings.HasPrefix(databaseURL, "postgres://") || strings.HasPrefix(databaseURL, "postgresql://") {
+		u, err := url.Parse(databaseURL)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s: %v", DatabaseURLEnv, err)
+		}
+		query := u.Query()
+		query.Set("search_path", searchPath)
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+	return databaseURL + " search_path=" + searchPath, nil
+}
@@ -0,0 +1,674 @@
+package testutil
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"treesindia/models"
+	"treesindia/repositories"
+	"treesindia/services"
+
+	"gorm.io/gorm"
+)
+
+// fakeGatewaySecret signs the payments of FakePaymentGateway
+const fakeGatewaySecret = "fake_gateway_secret"
+
+// FakeRefund is a refund made through FakePaymentGateway
+type FakeRefund struct {
+	PaymentID string
+	Amount    float64
+	Notes     string
+}
+
+// FakePaymentGateway is an in-memory PaymentGateway. Payments are verified the way Razorpay does
+// it, against an HMAC of the order and payment IDs; Sign returns the valid signature.
+type FakePaymentGateway struct {
+	mu      sync.Mutex
+	orders  []map[string]interface{}
+	refunds []FakeRefund
+
+	// CreateOrderErr, when set, is returned by CreateOrder
+	CreateOrderErr error
+}
+
+// NewFakePaymentGateway creates a fake payment gateway
+func NewFakePaymentGateway() *FakePaymentGateway {
+	return &FakePaymentGateway{}
+}
+
+// CreateOrder creates an order with the ID order_test_<n>
+func (g *FakePaymentGateway) CreateOrder(amount float64, receipt string, notes string) (map[string]interface{}, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.CreateOrderErr != nil {
+		return nil, g.CreateOrderErr
+	}
+
+	order := map[string]interface{}{
+		"id":       fmt.Sprintf("order_test_%d", len(g.orders)+1),
+		"amount":   int64(amount * 100),
+		"currency": "INR",
+		"receipt":  receipt,
+		"status":   "created",
+		"notes":    map[string]string{"description": notes},
+	}
+	g.orders = append(g.orders, order)
+	return order, nil
+}
+
+// VerifyPayment reports whether signature is the one Sign returns for the order and payment
+func (g *FakePaymentGateway) VerifyPayment(paymentID, orderID, signature string) (bool, error) {
+	return hmac.Equal([]byte(signature), []byte(g.Sign(orderID, paymentID))), nil
+}
+
+// GetPaymentDetails returns a captured payment with the amount refunded so far, in paise
+func (g *FakePaymentGateway) GetPaymentDetails(paymentID string) (map[string]interface{}, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var refunded float64
+	for _, refund := range g.refunds {
+		if refund.PaymentID == paymentID {
+			refunded += refund.Amount * 100
+		}
+	}
+	return map[string]interface{}{
+		"id":              paymentID,
+		"status":          "captured",
+		"amount_refunded": refunded,
+	}, nil
+}
+
+// RefundPayment records the refund
+func (g *FakePaymentGateway) RefundPayment(paymentID string, amount float64, notes string) (map[string]interface{}, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.refunds = append(g.refunds, FakeRefund{PaymentID: paymentID, Amount: amount, Notes: notes})
+	return map[string]interface{}{
+		"id":         fmt.Sprintf("rfnd_test_%d", len(g.refunds)),
+		"payment_id": paymentID,
+		"amount":     int64(amount * 100),
+		"status":     "processed",
+	}, nil
+}
+
+// Sign returns the signature checkout would return for a payment of an order
+func (g *FakePaymentGateway) Sign(orderID, paymentID string) string {
+	mac := hmac.New(sha256.New, []byte(fakeGatewaySecret))
+	mac.Write([]byte(orderID + "|" + paymentID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Orders returns the orders created so far
+func (g *FakePaymentGateway) Orders() []map[string]interface{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]map[string]interface{}(nil), g.orders...)
+}
+
+// Refunds returns the refunds made so far
+func (g *FakePaymentGateway) Refunds() []FakeRefund {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]FakeRefund(nil), g.refunds...)
+}
+
+// FakePush is a notification sent through FakePushSender to device tokens or a topic
+type FakePush struct {
+	Tokens       []string
+	Topic        string
+	Notification services.FCMNotification
+}
+
+// FakePushSender is a PushSender that records what it sends
+type FakePushSender struct {
+	mu   sync.Mutex
+	sent []FakePush
+
+	// InvalidTokens are the tokens ValidateToken rejects
+	InvalidTokens map[string]bool
+}
+
+// NewFakePushSender creates a fake push sender
+func NewFakePushSender() *FakePushSender {
+	return &FakePushSender{InvalidTokens: make(map[string]bool)}
+}
+
+// SendToDevice records a notification to one device
+func (p *FakePushSender) SendToDevice(token string, notification *services.FCMNotification) (*services.FCMResponse, error) {
+	return p.SendToMultipleDevices([]string{token}, notification)
+}
+
+// SendToMultipleDevices records a notification to several devices
+func (p *FakePushSender) SendToMultipleDevices(tokens []string, notification *services.FCMNotification) (*services.FCMResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sent = append(p.sent, FakePush{Tokens: append([]string(nil), tokens...), Notification: *notification})
+	response := &services.FCMResponse{}
+	for _, token := range tokens {
+		if p.InvalidTokens[token] {
+			response.FailureCount++
+			response.Errors = append(response.Errors, "invalid registration token")
+		} else {
+			response.SuccessCount++
+			response.Responses = append(response.Responses, "projects/test/messages/"+token)
+		}
+	}
+	return response, nil
+}
+
+// SendToTopic records a notification to a topic
+func (p *FakePushSender) SendToTopic(topic string, notification *services.FCMNotification) (*services.FCMResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sent = append(p.sent, FakePush{Topic: topic, Notification: *notification})
+	return &services.FCMResponse{SuccessCount: 1, Responses: []string{"projects/test/messages/" + topic}}, nil
+}
+
+// ValidateToken rejects the tokens in InvalidTokens
+func (p *FakePushSender) ValidateToken(token string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return !p.InvalidTokens[token], nil
+}
+
+// Sent returns the notifications sent so far
+func (p *FakePushSender) Sent() []FakePush {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]FakePush(nil), p.sent...)
+}
+
+// FakeCall is a call placed through FakeCallProvider
+type FakeCall struct {
+	ID   string
+	From string
+	To   string
+}
+
+// FakeCallProvider is a CallProvider that records the calls it places
+type FakeCallProvider struct {
+	mu    sync.Mutex
+	calls []FakeCall
+}
+
+// NewFakeCallProvider creates a fake call provider
+func NewFakeCallProvider() *FakeCallProvider {
+	return &FakeCallProvider{}
+}
+
+// InitiateCall records a call and returns the call ID call_test_<n>
+func (c *FakeCallProvider) InitiateCall(fromPhone, toPhone string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call := FakeCall{ID: fmt.Sprintf("call_test_%d", len(c.calls)+1), From: fromPhone, To: toPhone}
+	c.calls = append(c.calls, call)
+	return call.ID, nil
+}
+
+// ParseCallDuration parses a duration in seconds, returning 0 when it is not a number
+func (c *FakeCallProvider) ParseCallDuration(duration string) int {
+	seconds, err := strconv.Atoi(duration)
+	if err != nil {
+		return 0
+	}
+	return seconds
+}
+
+// IsServiceAvailable always reports the provider as configured
+func (c *FakeCallProvider) IsServiceAvailable() bool {
+	return true
+}
+
+// TestCall records a call to testPhoneNumber
+func (c *FakeCallProvider) TestCall(testPhoneNumber string) (string, error) {
+	return c.InitiateCall("", testPhoneNumber)
+}
+
+// Calls returns the calls placed so far
+func (c *FakeCallProvider) Calls() []FakeCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]FakeCall(nil), c.calls...)
+}
+
+// FakeChatCompleter is a ChatCompleter that answers every chat with Reply
+type FakeChatCompleter struct {
+	mu    sync.Mutex
+	chats [][]services.OpenAIMessage
+
+	Reply string
+	Err   error
+}
+
+// NewFakeChatCompleter creates a fake chat completer that answers with reply
+func NewFakeChatCompleter(reply string) *FakeChatCompleter {
+	return &FakeChatCompleter{Reply: reply}
+}
+
+// Complete records the chat and returns Reply, or Err when it is set
+func (c *FakeChatCompleter) Complete(messages []services.OpenAIMessage) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.chats = append(c.chats, append([]services.OpenAIMessage(nil), messages...))
+	if c.Err != nil {
+		return "", c.Err
+	}
+	return c.Reply, nil
+}
+
+// Chats returns the chats completed so far
+func (c *FakeChatCompleter) Chats() [][]services.OpenAIMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]services.OpenAIMessage(nil), c.chats...)
+}
+
+// fakeMediaBaseURL is the URL FakeMediaStore serves media from
+const fakeMediaBaseURL = "https://media.test/"
+
+// FakeMediaStore is an in-memory MediaStore
+type FakeMediaStore struct {
+	mu      sync.Mutex
+	objects map[string]fakeMediaObject
+}
+
+type fakeMediaObject struct {
+	data     []byte
+	modified time.Time
+}
+
+// NewFakeMediaStore creates an empty in-memory media store
+func NewFakeMediaStore() *FakeMediaStore {
+	return &FakeMediaStore{objects: make(map[string]fakeMediaObject)}
+}
+
+// Backend returns "fake"
+func (s *FakeMediaStore) Backend() string {
+	return "fake"
+}
+
+// UploadImage stores an uploaded image
+func (s *FakeMediaStore) UploadImage(file *multipart.FileHeader, folder string) (string, error) {
+	return s.UploadMedia(file, folder, "image")
+}
+
+// UploadVideo stores an uploaded video
+func (s *FakeMediaStore) UploadVideo(file *multipart.FileHeader, folder string) (string, error) {
+	return s.UploadMedia(file, folder, "video")
+}
+
+// UploadMedia stores an uploaded file
+func (s *FakeMediaStore) UploadMedia(file *multipart.FileHeader, folder string, mediaType string) (string, error) {
+	f, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return s.UploadReader(f, folder, file.Filename, mediaType)
+}
+
+// UploadImageFromPath stores an image read from a local file
+func (s *FakeMediaStore) UploadImageFromPath(filePath string, folder string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return s.UploadReader(f, folder, filepath.Base(filePath), "image")
+}
+
+// UploadReader stores media under folder/filename
+func (s *FakeMediaStore) UploadReader(r io.Reader, folder, filename, mediaType string) (string, error) {
+	return s.Put(r, path.Join(folder, filename), "")
+}
+
+// Put stores media under key
+func (s *FakeMediaStore) Put(r io.Reader, key, contentType string) (string, error) {
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = fakeMediaObject{data: buf.Bytes(), modified: time.Now()}
+	return s.PublicURL(key), nil
+}
+
+// Delete removes media by its URL
+func (s *FakeMediaStore) Delete(url string) error {
+	key := s.KeyFromURL(url)
+	if key == "" {
+		return fmt.Errorf("media %s is not from this store", url)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+// KeyFromURL returns the key of a URL from this store
+func (s *FakeMediaStore) KeyFromURL(url string) string {
+	if !strings.HasPrefix(url, fakeMediaBaseURL) {
+		return ""
+	}
+	return strings.TrimPrefix(url, fakeMediaBaseURL)
+}
+
+// PublicURL returns the URL of key
+func (s *FakeMediaStore) PublicURL(key string) string {
+	return fakeMediaBaseURL + key
+}
+
+// SignedURL returns the URL with an expiry parameter
+func (s *FakeMediaStore) SignedURL(url string, expiry time.Duration) (string, error) {
+	return fmt.Sprintf("%s?expires=%d", url, time.Now().Add(expiry).Unix()), nil
+}
+
+// ListMedia calls fn for the stored media in key order
+func (s *FakeMediaStore) ListMedia(fn func(services.MediaObject) error) error {
+	s.mu.Lock()
+	objects := make([]services.MediaObject, 0, len(s.objects))
+	for key, object := range s.objects {
+		objects = append(objects, services.MediaObject{
+			Key:          key,
+			URL:          s.PublicURL(key),
+			Size:         int64(len(object.data)),
+			LastModified: object.modified,
+		})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	for _, object := range objects {
+		if err := fn(object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns the media stored under key
+func (s *FakeMediaStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	object, ok := s.objects[key]
+	return object.data, ok
+}
+
+// FakeUserStore is an in-memory UserStore
+type FakeUserStore struct {
+	mu     sync.Mutex
+	users  map[uint]models.User
+	nextID uint
+}
+
+// NewFakeUserStore creates a user store holding users
+func NewFakeUserStore(users ...models.User) *FakeUserStore {
+	store := &FakeUserStore{users: make(map[uint]models.User)}
+	for _, user := range users {
+		store.Add(user)
+	}
+	return store
+}
+
+// Add stores a user, giving it an ID when it has none, and returns the ID
+func (s *FakeUserStore) Add(user models.User) uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user.ID == 0 {
+		s.nextID++
+		user.ID = s.nextID
+	} else if user.ID > s.nextID {
+		s.nextID = user.ID
+	}
+	s.users[user.ID] = user
+	return user.ID
+}
+
+// FindByID loads a user into model, which must be a *models.User
+func (s *FakeUserStore) FindByID(model interface{}, id uint) error {
+	user, ok := model.(*models.User)
+	if !ok {
+		return fmt.Errorf("FakeUserStore cannot load %T", model)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.users[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*user = stored
+	return nil
+}
+
+// Update saves model, which must be a *models.User already in the store
+func (s *FakeUserStore) Update(model interface{}) error {
+	user, ok := model.(*models.User)
+	if !ok {
+		return fmt.Errorf("FakeUserStore cannot save %T", model)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[user.ID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	s.users[user.ID] = *user
+	return nil
+}
+
+// WalletBalance returns the wallet balance of a stored user
+func (s *FakeUserStore) WalletBalance(id uint) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.users[id].WalletBalance
+}
+
+// FakePaymentStore is an in-memory PaymentStore covering what the payment, wallet and inquiry fee
+// flows use. The listing, statistics and admin queries are not implemented; the embedded
+// PaymentStore is nil, so calling one of them panics.
+type FakePaymentStore struct {
+	services.PaymentStore
+
+	mu       sync.Mutex
+	payments map[uint]models.Payment
+	nextID   uint
+}
+
+// NewFakePaymentStore creates an empty payment store
+func NewFakePaymentStore() *FakePaymentStore {
+	return &FakePaymentStore{payments: make(map[uint]models.Payment)}
+}
+
+// Create stores a new payment and gives it an ID
+func (s *FakePaymentStore) Create(payment *models.Payment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	payment.ID = s.nextID
+	payment.CreatedAt = time.Now()
+	payment.UpdatedAt = payment.CreatedAt
+	s.payments[payment.ID] = *payment
+	return nil
+}
+
+// Update saves a stored payment
+func (s *FakePaymentStore) Update(payment *models.Payment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.payments[payment.ID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	payment.UpdatedAt = time.Now()
+	s.payments[payment.ID] = *payment
+	return nil
+}
+
+// GetByID gets a payment by ID
+func (s *FakePaymentStore) GetByID(id uint) (*models.Payment, error) {
+	return s.find(func(p *models.Payment) bool { return p.ID == id })
+}
+
+// GetByReference gets a payment by its reference
+func (s *FakePaymentStore) GetByReference(reference string) (*models.Payment, error) {
+	return s.find(func(p *models.Payment) bool { return p.PaymentReference == reference })
+}
+
+// GetByRazorpayOrderID gets a payment by its Razorpay order ID
+func (s *FakePaymentStore) GetByRazorpayOrderID(orderID string) (*models.Payment, error) {
+	return s.find(func(p *models.Payment) bool { return p.RazorpayOrderID != nil && *p.RazorpayOrderID == orderID })
+}
+
+// GetHeldBookingPayments gets the held payments of a booking
+func (s *FakePaymentStore) GetHeldBookingPayments(bookingID uint) ([]models.Payment, error) {
+	return s.filter(func(p *models.Payment) bool {
+		return p.RelatedEntityType == "booking" && p.RelatedEntityID == bookingID && p.Status == models.PaymentStatusHold
+	}), nil
+}
+
+// GetByUserIDAndTypes gets a page of a user's payments of the given types, newest first
+func (s *FakePaymentStore) GetByUserIDAndTypes(userID uint, paymentTypes []models.PaymentType, limit, offset int) ([]models.Payment, error) {
+	payments := s.filter(func(p *models.Payment) bool { return p.UserID == userID && hasPaymentType(paymentTypes, p.Type) })
+	sort.Slice(payments, func(i, j int) bool { return payments[i].ID > payments[j].ID })
+	if offset >= len(payments) {
+		return []models.Payment{}, nil
+	}
+	payments = payments[offset:]
+	if limit > 0 && limit < len(payments) {
+		payments = payments[:limit]
+	}
+	return payments, nil
+}
+
+// GetCountByUserIDAndTypes counts a user's payments of the given types
+func (s *FakePaymentStore) GetCountByUserIDAndTypes(userID uint, paymentTypes []models.PaymentType) (int64, error) {
+	payments := s.filter(func(p *models.Payment) bool { return p.UserID == userID && hasPaymentType(paymentTypes, p.Type) })
+	return int64(len(payments)), nil
+}
+
+// All returns every stored payment in ID order
+func (s *FakePaymentStore) All() []models.Payment {
+	return s.filter(func(*models.Payment) bool { return true })
+}
+
+func (s *FakePaymentStore) find(match func(*models.Payment) bool) (*models.Payment, error) {
+	payments := s.filter(match)
+	if len(payments) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &payments[0], nil
+}
+
+func (s *FakePaymentStore) filter(match func(*models.Payment) bool) []models.Payment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var payments []models.Payment
+	for _, payment := range s.payments {
+		payment := payment
+		if match(&payment) {
+			payments = append(payments, payment)
+		}
+	}
+	sort.Slice(payments, func(i, j int) bool { return payments[i].ID < payments[j].ID })
+	return payments
+}
+
+func hasPaymentType(paymentTypes []models.PaymentType, paymentType models.PaymentType) bool {
+	for _, t := range paymentTypes {
+		if t == paymentType {
+			return true
+		}
+	}
+	return false
+}
+
+// FakeWalletLedger is a WalletLedger that moves the balances of the users in a FakeUserStore and
+// records the transactions in a FakePaymentStore. It has no transactions to join, so tx is ignored.
+type FakeWalletLedger struct {
+	mu       sync.Mutex
+	users    *FakeUserStore
+	payments *FakePaymentStore
+}
+
+// NewFakeWalletLedger creates a wallet ledger over users and payments
+func NewFakeWalletLedger(users *FakeUserStore, payments *FakePaymentStore) *FakeWalletLedger {
+	return &FakeWalletLedger{users: users, payments: payments}
+}
+
+// Post adds delta to the user's balance and stores payment as completed
+func (l *FakeWalletLedger) Post(tx *gorm.DB, payment *models.Payment, delta float64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var user models.User
+	if err := l.users.FindByID(&user, payment.UserID); err != nil {
+		return err
+	}
+
+	newBalance := user.WalletBalance + delta
+	if delta < 0 && newBalance < 0 {
+		return &repositories.InsufficientWalletBalanceError{Required: -delta, Available: user.WalletBalance}
+	}
+
+	now := time.Now()
+	payment.Status = models.PaymentStatusCompleted
+	payment.CompletedAt = &now
+	payment.BalanceAfter = &newBalance
+	if err := l.payments.Create(payment); err != nil {
+		return err
+	}
+
+	user.WalletBalance = newBalance
+	return l.users.Update(&user)
+}
+
+// StaticWalletLimits are fixed WalletLimits; a zero maximum means no limit
+type StaticWalletLimits struct {
+	MinRecharge float64
+	MaxRecharge float64
+	MaxBalance  float64
+}
+
+// GetMinRechargeAmount returns MinRecharge
+func (l StaticWalletLimits) GetMinRechargeAmount() float64 {
+	return l.MinRecharge
+}
+
+// GetMaxRechargeAmount returns MaxRecharge
+func (l StaticWalletLimits) GetMaxRechargeAmount() float64 {
+	return l.MaxRecharge
+}
+
+// GetMaxWalletBalance returns MaxBalance
+func (l StaticWalletLimits) GetMaxWalletBalance() float64 {
+	return l.MaxBalance
+}
@@ -0,0 +1,143 @@
+package testutil
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"treesindia/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// fixtureSeq makes the unique columns of fixtures unique within a test run
+var fixtureSeq int64
+
+func nextFixtureSeq() int64 {
+	return atomic.AddInt64(&fixtureSeq, 1)
+}
+
+// CreateUser creates a normal user with the given wallet balance in the test database
+func CreateUser(t testing.TB, walletBalance float64) *models.User {
+	t.Helper()
+	db := RequireDB(t)
+
+	seq := nextFixtureSeq()
+	user := &models.User{
+		Name:          fmt.Sprintf("Test User %d", seq),
+		Phone:         fmt.Sprintf("+9190%08d", seq),
+		UserType:      models.UserTypeNormal,
+		IsActive:      true,
+		WalletBalance: walletBalance,
+	}
+	if err := db.Omit(clause.Associations).Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return user
+}
+
+// CreateAdmin creates an admin user in the test database
+func CreateAdmin(t testing.TB) *models.User {
+	t.Helper()
+	user := CreateUser(t, 0)
+	user.UserType = models.UserTypeAdmin
+	if err := RequireDB(t).Model(user).Update("user_type", models.UserTypeAdmin).Error; err != nil {
+		t.Fatalf("failed to make user %d an admin: %v", user.ID, err)
+	}
+	return user
+}
+
+// CreateInquiryService creates an active inquiry-priced service, in a new category, that is
+// available in city and state
+func CreateInquiryService(t testing.TB, city, state string) *models.Service {
+	t.Helper()
+	return createService(t, &models.Service{PriceType: "inquiry"}, city, state)
+}
+
+// CreateFixedPriceService creates an active service with a fixed price that takes an hour, in a
+// new category, that is available in city and state
+func CreateFixedPriceService(t testing.TB, price float64, city, state string) *models.Service {
+	t.Helper()
+	duration := "1 hour"
+	return createService(t, &models.Service{PriceType: "fixed", Price: &price, Duration: &duration}, city, state)
+}
+
+// createService saves service in a new category and makes it available in city and state
+func createService(t testing.TB, service *models.Service, city, state string) *models.Service {
+	t.Helper()
+	db := RequireDB(t)
+
+	seq := nextFixtureSeq()
+	category := &models.Category{
+		Name:     fmt.Sprintf("Test Category %d", seq),
+		Slug:     fmt.Sprintf("test-category-%d", seq),
+		IsActive: true,
+	}
+	if err := db.Omit(clause.Associations).Create(category).Error; err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+
+	service.Name = fmt.Sprintf("Test Service %d", seq)
+	service.Slug = fmt.Sprintf("test-service-%d", seq)
+	service.CategoryID = category.ID
+	service.IsActive = true
+	if err := db.Omit(clause.Associations).Create(service).Error; err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+
+	area := &models.ServiceArea{
+		City:     city,
+		State:    state,
+		Country:  "India",
+		IsActive: true,
+	}
+	if err := db.Omit(clause.Associations).Create(area).Error; err != nil {
+		t.Fatalf("failed to create service area: %v", err)
+	}
+	if err := db.Exec("INSERT INTO service_service_areas (service_id, service_area_id) VALUES (?, ?)", service.ID, area.ID).Error; err != nil {
+		t.Fatalf("failed to link service %d to service area %d: %v", service.ID, area.ID, err)
+	}
+
+	return service
+}
+
+// CreateTreesIndiaWorker creates an active Trees India worker, who can take fixed price bookings,
+// in the test database
+func CreateTreesIndiaWorker(t testing.TB) *models.User {
+	t.Helper()
+	db := RequireDB(t)
+
+	user := CreateUser(t, 0)
+	user.UserType = models.UserTypeWorker
+	if err := db.Model(user).Update("user_type", models.UserTypeWorker).Error; err != nil {
+		t.Fatalf("failed to make user %d a worker: %v", user.ID, err)
+	}
+
+	worker := &models.Worker{
+		UserID:      user.ID,
+		WorkerType:  models.WorkerTypeTreesIndia,
+		ContactInfo: "{}",
+		Address:     "{}",
+		Skills:      "[]",
+		IsAvailable: true,
+		IsActive:    true,
+	}
+	if err := db.Omit(clause.Associations).Create(worker).Error; err != nil {
+		t.Fatalf("failed to create worker for user %d: %v", user.ID, err)
+	}
+	return user
+}
+
+// SetAdminConfig sets an admin config value, creating the config when it does not exist
+func SetAdminConfig(t testing.TB, key, value string) {
+	t.Helper()
+	db := RequireDB(t)
+
+	var config models.AdminConfig
+	err := db.Where(models.AdminConfig{Key: key}).
+		Attrs(models.AdminConfig{Type: "string", Category: "system", IsActive: true}).
+		Assign(models.AdminConfig{Value: value}).
+		FirstOrCreate(&config).Error
+	if err != nil {
+		t.Fatalf("failed to set admin config %s: %v", key, err)
+	}
+}
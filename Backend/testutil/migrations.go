@@ -0,0 +1,32 @@
+package testutil
+
+import (
+	"database/sql"
+	"path/filepath"
+	"runtime"
+
+	"github.com/pressly/goose/v3"
+)
+
+// MigrationsDir returns the directory holding the goose migrations
+func MigrationsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "migrations")
+}
+
+// migrate runs the goose migrations against the database at databaseURL with the goose library,
+// so annotations such as NO TRANSACTION and StatementBegin are honoured as the goose CLI does in
+// production
+func migrate(databaseURL string) error {
+	conn, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	goose.SetLogger(goose.NopLogger())
+	if err := goose.SetDialect("postgres"); err != nil {
+		return err
+	}
+	return goose.Up(conn, MigrationsDir())
+}
@@ -0,0 +1,46 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+)
+
+func TestCollectMigrations(t *testing.T) {
+	migrations, err := goose.CollectMigrations(MigrationsDir(), 0, goose.MaxVersion)
+	if err != nil {
+		t.Fatalf("CollectMigrations returned error: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations found")
+	}
+}
+
+// Every extension a migration creates must be created in public before the test schemas are migrated
+func TestExtensionsCoverMigrations(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(MigrationsDir(), "*.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := make(map[string]bool)
+	for _, extension := range extensions {
+		created[extension] = true
+	}
+
+	createExtension := regexp.MustCompile(`(?i)CREATE EXTENSION (?:IF NOT EXISTS )?"?(\w+)`)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range createExtension.FindAllStringSubmatch(string(content), -1) {
+			if !created[match[1]] {
+				t.Errorf("%s creates extension %s, which testutil does not create in public", filepath.Base(file), match[1])
+			}
+		}
+	}
+}
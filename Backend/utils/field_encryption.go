@@ -0,0 +1,242 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// encryptedFieldPrefix marks a value written by EncryptField. The full format is
+// enc:v1:<key id>:<wrapped data key>:<ciphertext>, with both binary parts base64 encoded.
+const encryptedFieldPrefix = "enc:v1:"
+
+// fieldKeySize is the length of both key encryption keys and per-value data keys (AES-256)
+const fieldKeySize = 32
+
+// FieldKeyring holds the key encryption keys used for field-level encryption. New values are
+// encrypted with the primary key; the other keys are kept so values written before a rotation
+// can still be decrypted.
+type FieldKeyring struct {
+	primaryID string
+	keys      map[string][]byte
+}
+
+var (
+	fieldKeyring   *FieldKeyring
+	fieldKeyringMu sync.RWMutex
+)
+
+// ParseFieldEncryptionKeys parses a comma separated list of id:base64key pairs, e.g.
+// "2025-06:q8V...=,2024-01:Zm9...=". The first key is the primary key; each key must decode to 32 bytes.
+func ParseFieldEncryptionKeys(spec string) (*FieldKeyring, error) {
+	keyring := &FieldKeyring{keys: make(map[string][]byte)}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.New("field encryption keys must be in the form id:base64key")
+		}
+		id := parts[0]
+
+		key, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid field encryption key %s: %v", id, err)
+		}
+		if len(key) != fieldKeySize {
+			return nil, fmt.Errorf("field encryption key %s must be %d bytes, got %d", id, fieldKeySize, len(key))
+		}
+		if _, exists := keyring.keys[id]; exists {
+			return nil, fmt.Errorf("duplicate field encryption key id %s", id)
+		}
+
+		if keyring.primaryID == "" {
+			keyring.primaryID = id
+		}
+		keyring.keys[id] = key
+	}
+
+	if keyring.primaryID == "" {
+		return nil, errors.New("no field encryption keys configured")
+	}
+	return keyring, nil
+}
+
+// InitFieldEncryption configures the keys used by EncryptField and DecryptField. An empty spec
+// disables encryption: values are then stored as they are and encrypted values cannot be read.
+func InitFieldEncryption(spec string) error {
+	var keyring *FieldKeyring
+	if strings.TrimSpace(spec) != "" {
+		var err error
+		keyring, err = ParseFieldEncryptionKeys(spec)
+		if err != nil {
+			return err
+		}
+	}
+
+	fieldKeyringMu.Lock()
+	fieldKeyring = keyring
+	fieldKeyringMu.Unlock()
+	return nil
+}
+
+// FieldEncryptionEnabled reports whether field encryption keys are configured
+func FieldEncryptionEnabled() bool {
+	return currentFieldKeyring() != nil
+}
+
+// PrimaryFieldKeyID returns the id of the key new values are encrypted with
+func PrimaryFieldKeyID() string {
+	keyring := currentFieldKeyring()
+	if keyring == nil {
+		return ""
+	}
+	return keyring.primaryID
+}
+
+// IsEncryptedField reports whether a stored value was written by EncryptField
+func IsEncryptedField(value string) bool {
+	return strings.HasPrefix(value, encryptedFieldPrefix)
+}
+
+// FieldNeedsEncryption reports whether a stored value is plaintext or encrypted with a key other
+// than the primary key, and so should be rewritten
+func FieldNeedsEncryption(value string) bool {
+	if value == "" {
+		return false
+	}
+	if !IsEncryptedField(value) {
+		return true
+	}
+	keyID, _, _, err := splitEncryptedField(value)
+	return err != nil || keyID != PrimaryFieldKeyID()
+}
+
+// EncryptField encrypts a value with a fresh data key, wrapped by the primary key. Empty values
+// are returned as they are, as is everything when encryption is not configured.
+func EncryptField(plaintext string) (string, error) {
+	keyring := currentFieldKeyring()
+	if plaintext == "" || keyring == nil {
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, fieldKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %v", err)
+	}
+
+	// The key id is authenticated with the wrapped data key so it cannot be swapped
+	wrappedKey, err := sealAESGCM(keyring.keys[keyring.primaryID], dataKey, []byte(keyring.primaryID))
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap data key: %v", err)
+	}
+	ciphertext, err := sealAESGCM(dataKey, []byte(plaintext), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt value: %v", err)
+	}
+
+	return encryptedFieldPrefix + keyring.primaryID + ":" +
+		base64.StdEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptField decrypts a value written by EncryptField. Values that are not encrypted, such as
+// rows written before encryption was enabled, are returned as they are.
+func DecryptField(value string) (string, error) {
+	if !IsEncryptedField(value) {
+		return value, nil
+	}
+
+	keyring := currentFieldKeyring()
+	if keyring == nil {
+		return "", errors.New("value is encrypted but no field encryption keys are configured")
+	}
+
+	keyID, wrappedKey, ciphertext, err := splitEncryptedField(value)
+	if err != nil {
+		return "", err
+	}
+	kek, ok := keyring.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("unknown field encryption key %s", keyID)
+	}
+
+	dataKey, err := openAESGCM(kek, wrappedKey, []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data key: %v", err)
+	}
+	plaintext, err := openAESGCM(dataKey, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %v", err)
+	}
+	return string(plaintext), nil
+}
+
+// splitEncryptedField returns the key id, wrapped data key and ciphertext of an encrypted value
+func splitEncryptedField(value string) (string, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(value, encryptedFieldPrefix), ":")
+	if len(parts) != 3 {
+		return "", nil, nil, errors.New("malformed encrypted value")
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("malformed encrypted value: %v", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("malformed encrypted value: %v", err)
+	}
+	return parts[0], wrappedKey, ciphertext, nil
+}
+
+// sealAESGCM encrypts data with AES-GCM, prefixing the random nonce
+func sealAESGCM(key, data, additionalData []byte) ([]byte, error) {
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, additionalData), nil
+}
+
+// openAESGCM decrypts data sealed by sealAESGCM
+func openAESGCM(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, data := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, data, additionalData)
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func currentFieldKeyring() *FieldKeyring {
+	fieldKeyringMu.RLock()
+	defer fieldKeyringMu.RUnlock()
+	return fieldKeyring
+}
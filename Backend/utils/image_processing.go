@@ -0,0 +1,340 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math/bits"
+	"path"
+	"strings"
+
+	_ "image/gif"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Image sizes produced for every uploaded image
+const (
+	WebImageMaxDimension = 1600 // Longest edge of the web-optimised image
+	ImageJPEGQuality     = 82
+	MaxImagePixels       = 50_000_000 // Largest image decoded; a 50 MP RGBA bitmap is about 200 MB
+)
+
+// ErrImageTooLarge is returned for images whose declared size is over MaxImagePixels
+var ErrImageTooLarge = errors.New("image dimensions are too large")
+
+// ImageVariant is a fixed size generated next to the web-optimised image
+type ImageVariant struct {
+	Name   string
+	Width  int
+	Height int
+	Crop   bool // Fill the exact size, cropping the centre, instead of fitting inside it
+}
+
+// ImageVariants are the thumbnails generated for every uploaded image
+var ImageVariants = []ImageVariant{
+	{Name: "thumb", Width: 200, Height: 200, Crop: true},
+	{Name: "small", Width: 480, Height: 480},
+}
+
+// EncodedImage is an encoded image and its size
+type EncodedImage struct {
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// ProcessedImage is an upload after it went through the image pipeline
+type ProcessedImage struct {
+	Web            EncodedImage
+	Variants       map[string]EncodedImage
+	ContentType    string
+	Extension      string
+	PerceptualHash uint64
+}
+
+// ProcessImage decodes an image, applies its EXIF orientation and re-encodes it at the web size and
+// every variant size. Re-encoding drops all metadata, including EXIF GPS coordinates. The header is
+// checked before decoding, so a small file declaring a huge canvas is rejected without allocating it.
+func ProcessImage(data []byte) (*ProcessedImage, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %v", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxImagePixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrImageTooLarge, config.Width, config.Height)
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %v", err)
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = JPEGOrientation(data)
+	}
+
+	// Scaling to the longest edge doesn't depend on orientation, so scale first and rotate the smaller image
+	web := orientImage(resizeToFit(src, WebImageMaxDimension, WebImageMaxDimension), orientation)
+
+	// Keep PNG for PNG uploads and anything with transparency; everything else becomes JPEG
+	usePNG := format == "png" || !isOpaque(web)
+
+	processed := &ProcessedImage{
+		Variants:       make(map[string]EncodedImage, len(ImageVariants)),
+		ContentType:    "image/jpeg",
+		Extension:      ".jpg",
+		PerceptualHash: PerceptualHash(web),
+	}
+	if usePNG {
+		processed.ContentType = "image/png"
+		processed.Extension = ".png"
+	}
+
+	if processed.Web, err = encodeImage(web, usePNG); err != nil {
+		return nil, err
+	}
+
+	for _, variant := range ImageVariants {
+		var resized *image.NRGBA
+		if variant.Crop {
+			resized = resizeToFill(web, variant.Width, variant.Height)
+		} else {
+			resized = resizeToFit(web, variant.Width, variant.Height)
+		}
+
+		encoded, err := encodeImage(resized, usePNG)
+		if err != nil {
+			return nil, err
+		}
+		processed.Variants[variant.Name] = encoded
+	}
+
+	return processed, nil
+}
+
+// ImageVariantURL returns the URL (or storage key) of a variant of a processed image,
+// e.g. ".../kitchen_123.jpg" -> ".../kitchen_123_thumb.jpg"
+func ImageVariantURL(url, variant string) string {
+	query := ""
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url, query = url[:i], url[i:]
+	}
+
+	ext := path.Ext(url)
+	if strings.Contains(ext, "/") {
+		ext = ""
+	}
+	return strings.TrimSuffix(url, ext) + "_" + variant + ext + query
+}
+
+// PerceptualHash computes a 64-bit difference hash of an image. Near-identical images (resized,
+// recompressed or with small edits) have hashes a small Hamming distance apart.
+func PerceptualHash(img image.Image) uint64 {
+	small := image.NewNRGBA(image.Rect(0, 0, 9, 8))
+	draw.ApproxBiLinear.Scale(small, small.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if luminance(small, x, y) < luminance(small, x+1, y) {
+				hash |= 1 << uint(y*8+x)
+			}
+		}
+	}
+	return hash
+}
+
+// HammingDistance counts the bits that differ between two perceptual hashes
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// JPEGOrientation reads the EXIF orientation (1-8) of a JPEG, defaulting to 1
+func JPEGOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for offset := 2; offset+4 <= len(data); {
+		if data[offset] != 0xFF {
+			return 1
+		}
+		marker := data[offset+1]
+		if marker == 0xDA || marker == 0xD9 {
+			// Start of scan or end of image: no more metadata
+			return 1
+		}
+
+		length := int(binary.BigEndian.Uint16(data[offset+2:]))
+		segmentEnd := offset + 2 + length
+		if length < 2 || segmentEnd > len(data) {
+			return 1
+		}
+
+		if marker == 0xE1 {
+			if orientation, err := exifOrientation(data[offset+4 : segmentEnd]); err == nil {
+				return orientation
+			}
+		}
+		offset = segmentEnd
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag from the IFD0 of an APP1 EXIF segment
+func exifOrientation(segment []byte) (int, error) {
+	if len(segment) < 14 || string(segment[:6]) != "Exif\x00\x00" {
+		return 0, errors.New("not an EXIF segment")
+	}
+	tiff := segment[6:]
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, errors.New("invalid TIFF byte order")
+	}
+
+	ifdOffset := int(order.Uint32(tiff[4:]))
+	if ifdOffset+2 > len(tiff) {
+		return 0, errors.New("invalid IFD offset")
+	}
+
+	entries := int(order.Uint16(tiff[ifdOffset:]))
+	for i := 0; i < entries; i++ {
+		entry := ifdOffset + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 0, errors.New("invalid orientation")
+			}
+			return orientation, nil
+		}
+	}
+	return 0, errors.New("no orientation tag")
+}
+
+// orientImage rotates and flips an image so that EXIF orientation 1 displays it correctly
+func orientImage(img *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // Mirrored horizontally
+				dx, dy = w-1-x, y
+			case 3: // Rotated 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // Mirrored vertically
+				dx, dy = x, h-1-y
+			case 5: // Mirrored horizontally and rotated 270° clockwise
+				dx, dy = y, x
+			case 6: // Rotated 90° clockwise
+				dx, dy = h-1-y, x
+			case 7: // Mirrored horizontally and rotated 90° clockwise
+				dx, dy = h-1-y, w-1-x
+			case 8: // Rotated 270° clockwise
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):dst.PixOffset(dx, dy)+4], img.Pix[img.PixOffset(x, y):img.PixOffset(x, y)+4])
+		}
+	}
+	return dst
+}
+
+// resizeToFit scales an image down to fit inside maxWidth x maxHeight, keeping its aspect ratio
+func resizeToFit(img image.Image, maxWidth, maxHeight int) *image.NRGBA {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	scale := 1.0
+	if w > maxWidth {
+		scale = float64(maxWidth) / float64(w)
+	}
+	if h > maxHeight && float64(maxHeight)/float64(h) < scale {
+		scale = float64(maxHeight) / float64(h)
+	}
+
+	dstW, dstH := max(1, int(float64(w)*scale+0.5)), max(1, int(float64(h)*scale+0.5))
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+	if scale == 1.0 {
+		draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	} else {
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	}
+	return dst
+}
+
+// resizeToFill scales and centre-crops an image to exactly width x height
+func resizeToFill(img image.Image, width, height int) *image.NRGBA {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	// Largest centred crop with the target aspect ratio
+	cropW, cropH := w, w*height/width
+	if cropH > h {
+		cropW, cropH = h*width/height, h
+	}
+	x0 := bounds.Min.X + (w-cropW)/2
+	y0 := bounds.Min.Y + (h-cropH)/2
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, image.Rect(x0, y0, x0+cropW, y0+cropH), draw.Src, nil)
+	return dst
+}
+
+// encodeImage encodes an image as PNG or JPEG
+func encodeImage(img *image.NRGBA, usePNG bool) (EncodedImage, error) {
+	var buf bytes.Buffer
+	var err error
+	if usePNG {
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		err = encoder.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: ImageJPEGQuality})
+	}
+	if err != nil {
+		return EncodedImage{}, fmt.Errorf("failed to encode image: %v", err)
+	}
+
+	return EncodedImage{Data: buf.Bytes(), Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}, nil
+}
+
+// isOpaque reports whether every pixel of an image is fully opaque
+func isOpaque(img *image.NRGBA) bool {
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] != 0xFF {
+			return false
+		}
+	}
+	return true
+}
+
+// luminance returns the brightness of a pixel
+func luminance(img *image.NRGBA, x, y int) uint32 {
+	i := img.PixOffset(x, y)
+	return 299*uint32(img.Pix[i]) + 587*uint32(img.Pix[i+1]) + 114*uint32(img.Pix[i+2])
+}